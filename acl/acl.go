@@ -15,6 +15,13 @@ import (
 // Redefine this value from structs to avoid circular dependency.
 const AllNamespacesSentinel = "*"
 
+// Redefine the suffixes of the IDs of dispatched and periodic child jobs from
+// structs to avoid circular dependency.
+const (
+	dispatchLaunchSuffix = "/dispatch-"
+	periodicLaunchSuffix = "/periodic-"
+)
+
 // ManagementACL is a singleton used for management tokens
 var ManagementACL *ACL
 
@@ -44,6 +51,16 @@ func (c capabilitySet) Clear() {
 	}
 }
 
+// List returns the sorted capabilities of the set.
+func (c capabilitySet) List() []string {
+	caps := make([]string, 0, len(c))
+	for cap := range c {
+		caps = append(caps, cap)
+	}
+	sort.Strings(caps)
+	return caps
+}
+
 // ACL object is used to convert a set of policies into a structure that
 // can be efficiently evaluated to determine if an action is allowed.
 type ACL struct {
@@ -68,6 +85,12 @@ type ACL struct {
 	variables         *iradix.Tree[capabilitySet]
 	wildcardVariables *iradix.Tree[capabilitySet]
 
+	// jobs and wildcardJobs map the job rules of namespace policies to a
+	// capabilitySet, keyed by the namespace and job ID pattern separated by
+	// a null character.
+	jobs         *iradix.Tree[capabilitySet]
+	wildcardJobs *iradix.Tree[capabilitySet]
+
 	// The attributes below store the policy value for policies that don't have
 	// fine-grained capabilities.
 	agent    string
@@ -123,6 +146,9 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	svTxn := iradix.New[capabilitySet]().Txn()
 	wsvTxn := iradix.New[capabilitySet]().Txn()
 
+	jobTxn := iradix.New[capabilitySet]().Txn()
+	wjobTxn := iradix.New[capabilitySet]().Txn()
+
 	for _, policy := range policies {
 	NAMESPACES:
 		for _, ns := range policy.Namespaces {
@@ -177,6 +203,36 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 				}
 			}
 
+		JOBS:
+			for _, jobPolicy := range ns.Jobs {
+				key := []byte(ns.Name + "\x00" + jobPolicy.Name)
+				txn := jobTxn
+				if globDefinition || strings.Contains(jobPolicy.Name, "*") {
+					txn = wjobTxn
+				}
+
+				jobCapabilities, ok := txn.Get(key)
+				if !ok {
+					jobCapabilities = make(capabilitySet)
+					txn.Insert(key, jobCapabilities)
+				}
+
+				// Deny always takes precedence
+				if jobCapabilities.Check(NamespaceCapabilityDeny) {
+					continue
+				}
+
+				for _, cap := range jobPolicy.Capabilities {
+					if cap == NamespaceCapabilityDeny {
+						// Overwrite any existing capabilities
+						jobCapabilities.Clear()
+						jobCapabilities.Set(NamespaceCapabilityDeny)
+						continue JOBS
+					}
+					jobCapabilities.Set(cap)
+				}
+			}
+
 			// Deny always takes precedence
 			if capabilities.Check(NamespaceCapabilityDeny) {
 				continue NAMESPACES
@@ -304,6 +360,9 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.variables = svTxn.Commit()
 	acl.wildcardVariables = wsvTxn.Commit()
 
+	acl.jobs = jobTxn.Commit()
+	acl.wildcardJobs = wjobTxn.Commit()
+
 	acl.client = PolicyDeny
 	acl.server = PolicyDeny
 	acl.isLeader = false
@@ -352,6 +411,126 @@ func (a *ACL) AllowNamespaceOperation(ns string, op string) bool {
 	return capabilities.Check(op)
 }
 
+// AllowJobOp is shorthand for AllowJobOperation
+func (a *ACL) AllowJobOp(ns, jobID, op string) bool {
+	return a.AllowJobOperation(ns, jobID, op)
+}
+
+// AllowJobOpFunc is a helper that returns a function that can be used to check
+// whether any of the operations is allowed for a job.
+func (a *ACL) AllowJobOpFunc(ops ...string) func(ns, jobID string) bool {
+	return func(ns, jobID string) bool {
+		for _, op := range ops {
+			if a.AllowJobOperation(ns, jobID, op) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllowJobOperation checks if a given operation is allowed for a job. The job
+// rules of a namespace policy grant capabilities to the jobs matching their
+// pattern in addition to the capabilities of the namespace, unless they deny
+// access to the job. Dispatched and periodic child jobs are also subject to
+// the rules matching their parent job.
+func (a *ACL) AllowJobOperation(ns, jobID, op string) bool {
+	if a == nil {
+		return false
+	}
+
+	// Hot path management tokens or when ACLs are disabled
+	if a.aclsDisabled || a.management {
+		return true
+	}
+
+	nsCapabilities, nsOk := a.matchingNamespaceCapabilitySet(ns)
+	if nsOk && nsCapabilities.Check(NamespaceCapabilityDeny) {
+		return false
+	}
+
+	jobIDs := []string{jobID}
+	if parentID, ok := parentJobID(jobID); ok {
+		jobIDs = append(jobIDs, parentID)
+	}
+
+	// Deny always takes precedence, so check the rules of the parent job even
+	// if the rules of the child allow the operation
+	allow := false
+	for _, id := range jobIDs {
+		if jobCapabilities, ok := a.matchingJobCapabilitySet(ns, id); ok {
+			if jobCapabilities.Check(NamespaceCapabilityDeny) {
+				return false
+			}
+			allow = allow || jobCapabilities.Check(op)
+		}
+	}
+
+	return allow || (nsOk && nsCapabilities.Check(op))
+}
+
+// parentJobID returns the ID of the parent of a dispatched or periodic child
+// job, which is the prefix of the child ID before the launch suffix.
+func parentJobID(jobID string) (string, bool) {
+	for _, suffix := range []string{dispatchLaunchSuffix, periodicLaunchSuffix} {
+		if i := strings.LastIndex(jobID, suffix); i > 0 {
+			return jobID[:i], true
+		}
+	}
+	return "", false
+}
+
+// AllowNsOpAnyJob is a loose check that the operation is allowed for the
+// namespace or for at least one job in it, with an expectation that the actual
+// results will be filtered with AllowJobOp.
+func (a *ACL) AllowNsOpAnyJob(ns, op string) bool {
+	if a == nil {
+		return false
+	}
+
+	if a.AllowNamespaceOperation(ns, op) {
+		return true
+	}
+
+	if ns != AllNamespacesSentinel {
+		capabilities, ok := a.matchingNamespaceCapabilitySet(ns)
+		if ok && capabilities.Check(NamespaceCapabilityDeny) {
+			return false
+		}
+	}
+
+	allow := false
+	checkFn := func(k []byte, v capabilitySet) bool {
+		pattern, _, _ := strings.Cut(string(k), "\x00")
+		if ns != AllNamespacesSentinel && pattern != ns && !glob.Glob(pattern, ns) {
+			return false
+		}
+		allow = v.Check(op)
+		return allow
+	}
+
+	a.jobs.Root().Walk(checkFn)
+	if allow {
+		return true
+	}
+
+	a.wildcardJobs.Root().Walk(checkFn)
+	return allow
+}
+
+// AllowNsOpAnyJobFunc is a helper that returns a function that can be used to
+// check namespace permissions with AllowNsOpAnyJob.
+func (a *ACL) AllowNsOpAnyJobFunc(ops ...string) func(string) bool {
+	return func(ns string) bool {
+		for _, op := range ops {
+			if a.AllowNsOpAnyJob(ns, op) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // AllowNamespace checks if any operations are allowed for a namespace
 func (a *ACL) AllowNamespace(ns string) bool {
 	if a == nil {
@@ -615,6 +794,18 @@ func (a *ACL) anyNamespaceAllows(cb func(capabilitySet) bool) bool {
 	return allow
 }
 
+// matchingJobCapabilitySet looks for a capabilitySet that matches the job in
+// the namespace, if no concrete definitions are found, then we return the
+// closest matching glob.
+func (a *ACL) matchingJobCapabilitySet(ns, jobID string) (capabilitySet, bool) {
+	capSet, ok := a.jobs.Get([]byte(ns + "\x00" + jobID))
+	if ok {
+		return capSet, true
+	}
+
+	return a.findClosestMatchingGlob(a.wildcardJobs, ns+"\x00"+jobID)
+}
+
 // matchingNodePoolCapabilitySet returns the capabilitySet that closest match
 // the node pool.
 func (a *ACL) matchingNodePoolCapabilitySet(pool string) (capabilitySet, bool) {
@@ -679,17 +870,27 @@ type matchingGlob struct {
 }
 
 func (a *ACL) findClosestMatchingGlob(radix *iradix.Tree[capabilitySet], ns string) (capabilitySet, bool) {
+	match, ok := findClosestMatchingWildcard(radix, ns)
+	if !ok {
+		return capabilitySet{}, false
+	}
+	return match.capabilitySet, true
+}
+
+// findClosestMatchingWildcard returns the glob of the radix tree that closest
+// matches the name.
+func findClosestMatchingWildcard(radix *iradix.Tree[capabilitySet], name string) (matchingGlob, bool) {
 	// First, find all globs that match.
-	matchingGlobs := findAllMatchingWildcards(radix, ns)
+	matchingGlobs := findAllMatchingWildcards(radix, name)
 
 	// If none match, let's return.
 	if len(matchingGlobs) == 0 {
-		return capabilitySet{}, false
+		return matchingGlob{}, false
 	}
 
 	// If a single matches, lets be efficient and return early.
 	if len(matchingGlobs) == 1 {
-		return matchingGlobs[0].capabilitySet, true
+		return matchingGlobs[0], true
 	}
 
 	// Stable sort the matched globs, based on the character difference between
@@ -699,7 +900,7 @@ func (a *ACL) findClosestMatchingGlob(radix *iradix.Tree[capabilitySet], ns stri
 		return matchingGlobs[i].difference <= matchingGlobs[j].difference
 	})
 
-	return matchingGlobs[0].capabilitySet, true
+	return matchingGlobs[0], true
 }
 
 func findAllMatchingWildcards(radix *iradix.Tree[capabilitySet], name string) []matchingGlob {
@@ -913,7 +1114,24 @@ func (a *ACL) AllowServiceRegistrationReadList(ns string, isWorkload bool) bool
 	case a.aclsDisabled, a.management:
 		return true
 	}
-	return isWorkload || a.AllowNsOp(ns, NamespaceCapabilityReadJob)
+	return isWorkload || a.AllowNsOpAnyJob(ns, NamespaceCapabilityReadJob)
+}
+
+// AllowServiceRegistrationRead checks if the service registrations of a job
+// can be read. Unlike AllowServiceRegistrationReadList, the job rules of the
+// namespace are applied to callers that are not workloads or clients.
+func (a *ACL) AllowServiceRegistrationRead(ns, jobID string, isWorkload bool) bool {
+	switch {
+	case a == nil:
+		return false
+	case a.client == PolicyRead,
+		a.client == PolicyWrite:
+		// COMPAT: older clients won't send WI tokens for these requests
+		return true
+	case a.aclsDisabled, a.management:
+		return true
+	}
+	return isWorkload || a.AllowJobOp(ns, jobID, NamespaceCapabilityReadJob)
 }
 
 // AllowServerOp checks if server-only operations are allowed
@@ -930,6 +930,181 @@ func TestVariablesMatching(t *testing.T) {
 
 }
 
+func TestJobMatching(t *testing.T) {
+	ci.Parallel(t)
+
+	tests := []struct {
+		name   string
+		policy string
+		ns     string
+		job    string
+		op     string
+		allow  bool
+	}{
+		{
+			name: "concrete job grants capability",
+			policy: `namespace "prod" {
+					job "payments" { capabilities = ["alloc-exec"] }}`,
+			ns:    "prod",
+			job:   "payments",
+			op:    NamespaceCapabilityAllocExec,
+			allow: true,
+		},
+		{
+			name: "wildcard job grants capability",
+			policy: `namespace "prod" {
+					job "payments-*" { capabilities = ["alloc-exec"] }}`,
+			ns:    "prod",
+			job:   "payments-api",
+			op:    NamespaceCapabilityAllocExec,
+			allow: true,
+		},
+		{
+			name: "job rule does not match other jobs",
+			policy: `namespace "prod" {
+					job "payments-*" { capabilities = ["alloc-exec"] }}`,
+			ns:    "prod",
+			job:   "billing",
+			op:    NamespaceCapabilityAllocExec,
+			allow: false,
+		},
+		{
+			name: "job rule does not match other namespaces",
+			policy: `namespace "prod" {
+					job "payments-*" { capabilities = ["alloc-exec"] }}`,
+			ns:    "dev",
+			job:   "payments-api",
+			op:    NamespaceCapabilityAllocExec,
+			allow: false,
+		},
+		{
+			name: "wildcard namespace job rule matches",
+			policy: `namespace "*" {
+					job "payments" { policy = "write" }}`,
+			ns:    "prod",
+			job:   "payments",
+			op:    NamespaceCapabilitySubmitJob,
+			allow: true,
+		},
+		{
+			name: "namespace capabilities apply to jobs",
+			policy: `namespace "prod" {
+					policy = "read"
+					job "payments" { capabilities = ["alloc-exec"] }}`,
+			ns:    "prod",
+			job:   "payments",
+			op:    NamespaceCapabilityReadJob,
+			allow: true,
+		},
+		{
+			name: "job deny takes precedence over namespace",
+			policy: `namespace "prod" {
+					policy = "write"
+					job "secret-*" { capabilities = ["deny"] }}`,
+			ns:    "prod",
+			job:   "secret-db",
+			op:    NamespaceCapabilityReadJob,
+			allow: false,
+		},
+		{
+			name: "namespace deny takes precedence over job",
+			policy: `namespace "prod" {
+					capabilities = ["deny"]
+					job "payments" { capabilities = ["read-job"] }}`,
+			ns:    "prod",
+			job:   "payments",
+			op:    NamespaceCapabilityReadJob,
+			allow: false,
+		},
+		{
+			name: "concrete job takes precedence over wildcard",
+			policy: `namespace "prod" {
+					job "payments-*" { capabilities = ["deny"] }
+					job "payments-api" { capabilities = ["read-job"] }}`,
+			ns:    "prod",
+			job:   "payments-api",
+			op:    NamespaceCapabilityReadJob,
+			allow: true,
+		},
+		{
+			name: "dispatched child matches parent rule",
+			policy: `namespace "prod" {
+					job "payments" { capabilities = ["alloc-exec"] }}`,
+			ns:    "prod",
+			job:   "payments/dispatch-1700000000-4b9e10a2",
+			op:    NamespaceCapabilityAllocExec,
+			allow: true,
+		},
+		{
+			name: "periodic child matches parent rule",
+			policy: `namespace "prod" {
+					job "backup" { capabilities = ["alloc-exec"] }}`,
+			ns:    "prod",
+			job:   "backup/periodic-1700000000",
+			op:    NamespaceCapabilityAllocExec,
+			allow: true,
+		},
+		{
+			name: "parent deny takes precedence over child wildcard",
+			policy: `namespace "prod" {
+					policy = "write"
+					job "*" { capabilities = ["alloc-exec"] }
+					job "secret" { capabilities = ["deny"] }}`,
+			ns:    "prod",
+			job:   "secret/dispatch-1700000000-4b9e10a2",
+			op:    NamespaceCapabilityReadJob,
+			allow: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := Parse(tc.policy)
+			must.NoError(t, err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			must.NoError(t, err)
+			must.Eq(t, tc.allow, acl.AllowJobOp(tc.ns, tc.job, tc.op))
+		})
+	}
+
+	t.Run("any job in namespace", func(t *testing.T) {
+		policy, err := Parse(`
+namespace "prod" {
+  job "payments-*" { capabilities = ["list-jobs", "read-job"] }
+}
+namespace "secret" {
+  capabilities = ["deny"]
+  job "payments" { capabilities = ["list-jobs"] }
+}`)
+		must.NoError(t, err)
+
+		acl, err := NewACL(false, []*Policy{policy})
+		must.NoError(t, err)
+		must.False(t, acl.AllowNsOp("prod", NamespaceCapabilityListJobs))
+		must.True(t, acl.AllowNsOpAnyJob("prod", NamespaceCapabilityListJobs))
+		must.True(t, acl.AllowNsOpAnyJob(AllNamespacesSentinel, NamespaceCapabilityListJobs))
+		must.False(t, acl.AllowNsOpAnyJob("prod", NamespaceCapabilitySubmitJob))
+		must.False(t, acl.AllowNsOpAnyJob("dev", NamespaceCapabilityListJobs))
+		must.False(t, acl.AllowNsOpAnyJob("secret", NamespaceCapabilityListJobs))
+		must.True(t, acl.AllowNsOpAnyJobFunc(NamespaceCapabilitySubmitJob, NamespaceCapabilityReadJob)("prod"))
+
+		allow := acl.AllowJobOpFunc(NamespaceCapabilityReadJob)
+		must.True(t, allow("prod", "payments-api"))
+		must.False(t, allow("prod", "billing"))
+	})
+
+	t.Run("management and disabled", func(t *testing.T) {
+		must.True(t, ManagementACL.AllowJobOp("prod", "payments", NamespaceCapabilitySubmitJob))
+		must.True(t, ACLsDisabledACL.AllowJobOp("prod", "payments", NamespaceCapabilitySubmitJob))
+		must.True(t, ACLsDisabledACL.AllowNsOpAnyJob("prod", NamespaceCapabilitySubmitJob))
+
+		var nilACL *ACL
+		must.False(t, nilACL.AllowJobOp("prod", "payments", NamespaceCapabilityReadJob))
+		must.False(t, nilACL.AllowNsOpAnyJob("prod", NamespaceCapabilityReadJob))
+	})
+}
+
 func TestACL_matchingCapabilitySet_returnsAllMatches(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package acl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	iradix "github.com/hashicorp/go-immutable-radix/v2"
+)
+
+const (
+	// The following are the types of operation whose ACL decision can be
+	// explained.
+	OperationTypeNamespace  = "namespace"
+	OperationTypeVariable   = "variable"
+	OperationTypeNodePool   = "node_pool"
+	OperationTypeHostVolume = "host_volume"
+	OperationTypeAgent      = "agent"
+	OperationTypeNode       = "node"
+	OperationTypeOperator   = "operator"
+	OperationTypeQuota      = "quota"
+	OperationTypePlugin     = "plugin"
+)
+
+// Operation describes an operation whose ACL decision can be explained.
+type Operation struct {
+	// Type is the type of resource of the operation.
+	Type string
+
+	// Namespace is the namespace of namespace and variable operations.
+	Namespace string
+
+	// JobID optionally narrows namespace operations to a single job.
+	JobID string
+
+	// Path is the path of variable operations.
+	Path string
+
+	// Name is the name of the node pool or host volume.
+	Name string
+
+	// Capability is the capability checked by the operation. For the agent,
+	// node, operator, quota and plugin types it is the policy disposition,
+	// such as "read" or "write".
+	Capability string
+}
+
+// Validate returns an error if the operation can't be explained.
+func (o *Operation) Validate() error {
+	if o == nil {
+		return errors.New("missing operation")
+	}
+	if o.Capability == "" {
+		return errors.New("missing capability")
+	}
+	if o.Capability == PolicyDeny {
+		return errors.New("the deny capability can't be explained")
+	}
+
+	switch o.Type {
+	case OperationTypeNamespace:
+		if o.Namespace == "" {
+			return errors.New("missing namespace")
+		}
+		if o.JobID != "" {
+			if !isJobCapabilityValid(o.Capability) {
+				return fmt.Errorf("invalid job capability %q", o.Capability)
+			}
+		} else if !isNamespaceCapabilityValid(o.Capability) {
+			return fmt.Errorf("invalid namespace capability %q", o.Capability)
+		}
+	case OperationTypeVariable:
+		if o.Namespace == "" {
+			return errors.New("missing namespace")
+		}
+		if o.Path == "" {
+			return errors.New("missing variable path")
+		}
+		if !isPathCapabilityValid(o.Capability) {
+			return fmt.Errorf("invalid variable capability %q", o.Capability)
+		}
+	case OperationTypeNodePool:
+		if o.Name == "" {
+			return errors.New("missing node pool name")
+		}
+		if !isNodePoolCapabilityValid(o.Capability) {
+			return fmt.Errorf("invalid node pool capability %q", o.Capability)
+		}
+	case OperationTypeHostVolume:
+		if o.Name == "" {
+			return errors.New("missing host volume name")
+		}
+		if !isHostVolumeCapabilityValid(o.Capability) {
+			return fmt.Errorf("invalid host volume capability %q", o.Capability)
+		}
+	case OperationTypeAgent, OperationTypeNode, OperationTypeOperator, OperationTypeQuota:
+		if o.Capability != PolicyRead && o.Capability != PolicyWrite {
+			return fmt.Errorf("invalid %s capability %q, must be %q or %q",
+				o.Type, o.Capability, PolicyRead, PolicyWrite)
+		}
+	case OperationTypePlugin:
+		if o.Capability != PolicyRead && o.Capability != PolicyList {
+			return fmt.Errorf("invalid plugin capability %q, must be %q or %q",
+				o.Capability, PolicyRead, PolicyList)
+		}
+	default:
+		return fmt.Errorf("invalid operation type %q", o.Type)
+	}
+	return nil
+}
+
+// AllowOperation checks if the operation is allowed.
+func (a *ACL) AllowOperation(op *Operation) bool {
+	if a == nil || op == nil {
+		return false
+	}
+
+	switch op.Type {
+	case OperationTypeNamespace:
+		if op.JobID != "" {
+			return a.AllowJobOp(op.Namespace, op.JobID, op.Capability)
+		}
+		return a.AllowNsOp(op.Namespace, op.Capability)
+	case OperationTypeVariable:
+		return a.AllowVariableOperation(op.Namespace, op.Path, op.Capability, nil)
+	case OperationTypeNodePool:
+		return a.AllowNodePoolOperation(op.Name, op.Capability)
+	case OperationTypeHostVolume:
+		return a.AllowHostVolumeOperation(op.Name, op.Capability)
+	case OperationTypeAgent:
+		if op.Capability == PolicyWrite {
+			return a.AllowAgentWrite()
+		}
+		return a.AllowAgentRead()
+	case OperationTypeNode:
+		if op.Capability == PolicyWrite {
+			return a.AllowNodeWrite()
+		}
+		return a.AllowNodeRead()
+	case OperationTypeOperator:
+		if op.Capability == PolicyWrite {
+			return a.AllowOperatorWrite()
+		}
+		return a.AllowOperatorRead()
+	case OperationTypeQuota:
+		if op.Capability == PolicyWrite {
+			return a.AllowQuotaWrite()
+		}
+		return a.AllowQuotaRead()
+	case OperationTypePlugin:
+		if op.Capability == PolicyList {
+			return a.AllowPluginList()
+		}
+		return a.AllowPluginRead()
+	default:
+		return false
+	}
+}
+
+// Explanation is the ACL decision for an operation and the policy rules
+// that apply to it.
+type Explanation struct {
+	// Allowed is the decision of the combined policies.
+	Allowed bool
+
+	// Rules are the rules of each policy that apply to the operation. Only
+	// the rules that closest match the operation are included, which are the
+	// namespace rule and, for job operations, the job rule of the namespace.
+	Rules []*ExplainedRule
+}
+
+// ExplainedRule is a policy rule that applies to an operation.
+type ExplainedRule struct {
+	// Policy is the name of the policy the rule belongs to.
+	Policy string
+
+	// Rule identifies the rule within the policy, such as
+	// `namespace "prod" job "payments-*"`.
+	Rule string
+
+	// Capabilities are the capabilities granted by the rule, or its policy
+	// disposition for rules without fine-grained capabilities.
+	Capabilities []string
+
+	// Allowed is true if the rule grants the capability of the operation.
+	Allowed bool
+
+	// Denied is true if the rule denies access, which takes precedence over
+	// the capabilities granted by any other rule.
+	Denied bool
+}
+
+// Explain returns the decision of the policies for the operation, along with
+// the rules of each policy that apply to it. The policies are keyed by name.
+func Explain(op *Operation, policies map[string]*Policy) (*Explanation, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	explanation := &Explanation{}
+	all := make([]*Policy, 0, len(policies))
+	for _, name := range names {
+		policy := policies[name]
+		all = append(all, policy)
+
+		aclObj, err := NewACL(false, []*Policy{policy})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile policy %q: %w", name, err)
+		}
+		for _, rule := range aclObj.explainRules(op) {
+			rule.Policy = name
+			explanation.Rules = append(explanation.Rules, rule)
+		}
+	}
+
+	aclObj, err := NewACL(false, all)
+	if err != nil {
+		return nil, err
+	}
+	explanation.Allowed = aclObj.AllowOperation(op)
+	return explanation, nil
+}
+
+// explainRules returns the rules of the ACL object that apply to the
+// operation.
+func (a *ACL) explainRules(op *Operation) []*ExplainedRule {
+	var rules []*ExplainedRule
+
+	capabilityRule := func(rule string, capabilities capabilitySet) {
+		rules = append(rules, &ExplainedRule{
+			Rule:         rule,
+			Capabilities: capabilities.List(),
+			Allowed:      capabilities.Check(op.Capability) && !capabilities.Check(PolicyDeny),
+			Denied:       capabilities.Check(PolicyDeny),
+		})
+	}
+	policyRule := func(rule, policy string) {
+		if policy == "" {
+			return
+		}
+		rules = append(rules, &ExplainedRule{
+			Rule:         rule,
+			Capabilities: []string{policy},
+			Allowed:      a.AllowOperation(op),
+			Denied:       policy == PolicyDeny,
+		})
+	}
+
+	switch op.Type {
+	case OperationTypeNamespace:
+		if op.JobID != "" {
+			if key, caps, ok := closestMatchingRule(a.jobs, a.wildcardJobs, op.Namespace+"\x00"+op.JobID); ok {
+				ns, job, _ := strings.Cut(key, "\x00")
+				capabilityRule(fmt.Sprintf("namespace %q job %q", ns, job), caps)
+			}
+		}
+		if key, caps, ok := closestMatchingRule(a.namespaces, a.wildcardNamespaces, op.Namespace); ok {
+			capabilityRule(fmt.Sprintf("namespace %q", key), caps)
+		}
+	case OperationTypeVariable:
+		if key, caps, ok := closestMatchingRule(a.variables, a.wildcardVariables, op.Namespace+"\x00"+op.Path); ok {
+			ns, path, _ := strings.Cut(key, "\x00")
+			capabilityRule(fmt.Sprintf("namespace %q variables path %q", ns, path), caps)
+		}
+	case OperationTypeNodePool:
+		if key, caps, ok := closestMatchingRule(a.nodePools, a.wildcardNodePools, op.Name); ok {
+			capabilityRule(fmt.Sprintf("node_pool %q", key), caps)
+		}
+	case OperationTypeHostVolume:
+		if key, caps, ok := closestMatchingRule(a.hostVolumes, a.wildcardHostVolumes, op.Name); ok {
+			capabilityRule(fmt.Sprintf("host_volume %q", key), caps)
+		}
+	case OperationTypeAgent:
+		policyRule("agent", a.agent)
+	case OperationTypeNode:
+		policyRule("node", a.node)
+	case OperationTypeOperator:
+		policyRule("operator", a.operator)
+	case OperationTypeQuota:
+		policyRule("quota", a.quota)
+	case OperationTypePlugin:
+		policyRule("plugin", a.plugin)
+	}
+	return rules
+}
+
+// closestMatchingRule returns the key and capabilities of the exact match of
+// the name, or of the closest matching glob.
+func closestMatchingRule(exact, wildcard *iradix.Tree[capabilitySet], name string) (string, capabilitySet, bool) {
+	if caps, ok := exact.Get([]byte(name)); ok {
+		return name, caps, true
+	}
+	match, ok := findClosestMatchingWildcard(wildcard, name)
+	if !ok {
+		return "", nil, false
+	}
+	return match.name, match.capabilitySet, true
+}
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package acl
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperation_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		op   *Operation
+		err  string
+	}{
+		{
+			name: "namespace",
+			op:   &Operation{Type: OperationTypeNamespace, Namespace: "prod", Capability: NamespaceCapabilityReadJob},
+		},
+		{
+			name: "missing namespace",
+			op:   &Operation{Type: OperationTypeNamespace, Capability: NamespaceCapabilityReadJob},
+			err:  "missing namespace",
+		},
+		{
+			name: "invalid job capability",
+			op: &Operation{Type: OperationTypeNamespace, Namespace: "prod", JobID: "example",
+				Capability: NamespaceCapabilityCSIMountVolume},
+			err: `invalid job capability "csi-mount-volume"`,
+		},
+		{
+			name: "missing variable path",
+			op:   &Operation{Type: OperationTypeVariable, Namespace: "prod", Capability: VariablesCapabilityRead},
+			err:  "missing variable path",
+		},
+		{
+			name: "node pool",
+			op:   &Operation{Type: OperationTypeNodePool, Name: "prod", Capability: NodePoolCapabilityWrite},
+		},
+		{
+			name: "invalid agent capability",
+			op:   &Operation{Type: OperationTypeAgent, Capability: PolicyList},
+			err:  `invalid agent capability "list"`,
+		},
+		{
+			name: "deny",
+			op:   &Operation{Type: OperationTypeOperator, Capability: PolicyDeny},
+			err:  "deny capability can't be explained",
+		},
+		{
+			name: "invalid type",
+			op:   &Operation{Type: "job", Capability: PolicyRead},
+			err:  `invalid operation type "job"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.op.Validate()
+			if tc.err == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	ci.Parallel(t)
+
+	parse := func(raw string) *Policy {
+		t.Helper()
+		policy, err := Parse(raw)
+		must.NoError(t, err)
+		return policy
+	}
+
+	policies := map[string]*Policy{
+		"readers": parse(`
+namespace "*" {
+  policy = "read"
+}
+agent {
+  policy = "read"
+}`),
+		"payments": parse(`
+namespace "prod" {
+  capabilities = ["submit-job"]
+  job "payments-*" {
+    capabilities = ["alloc-exec"]
+  }
+  job "secret" {
+    capabilities = ["deny"]
+  }
+  variables {
+    path "payments/*" {
+      capabilities = ["read"]
+    }
+  }
+}`),
+	}
+
+	readCapabilities := expandNamespacePolicy(PolicyRead)
+	sort.Strings(readCapabilities)
+
+	t.Run("namespace", func(t *testing.T) {
+		exp, err := Explain(&Operation{
+			Type:       OperationTypeNamespace,
+			Namespace:  "prod",
+			Capability: NamespaceCapabilitySubmitJob,
+		}, policies)
+		must.NoError(t, err)
+		must.True(t, exp.Allowed)
+		must.Eq(t, []*ExplainedRule{
+			{
+				Policy:       "payments",
+				Rule:         `namespace "prod"`,
+				Capabilities: []string{NamespaceCapabilitySubmitJob},
+				Allowed:      true,
+			},
+			{
+				Policy:       "readers",
+				Rule:         `namespace "*"`,
+				Capabilities: readCapabilities,
+			},
+		}, exp.Rules)
+	})
+
+	t.Run("job", func(t *testing.T) {
+		exp, err := Explain(&Operation{
+			Type:       OperationTypeNamespace,
+			Namespace:  "prod",
+			JobID:      "payments-api",
+			Capability: NamespaceCapabilityAllocExec,
+		}, policies)
+		must.NoError(t, err)
+		must.True(t, exp.Allowed)
+		must.Len(t, 3, exp.Rules)
+		must.Eq(t, `namespace "prod" job "payments-*"`, exp.Rules[0].Rule)
+		must.True(t, exp.Rules[0].Allowed)
+		must.False(t, exp.Rules[1].Allowed)
+	})
+
+	t.Run("job deny", func(t *testing.T) {
+		exp, err := Explain(&Operation{
+			Type:       OperationTypeNamespace,
+			Namespace:  "prod",
+			JobID:      "secret",
+			Capability: NamespaceCapabilityReadJob,
+		}, policies)
+		must.NoError(t, err)
+		must.False(t, exp.Allowed)
+		must.Eq(t, `namespace "prod" job "secret"`, exp.Rules[0].Rule)
+		must.True(t, exp.Rules[0].Denied)
+
+		// The readers policy allows reading all jobs on its own.
+		must.Eq(t, "readers", exp.Rules[2].Policy)
+		must.True(t, exp.Rules[2].Allowed)
+	})
+
+	t.Run("variable", func(t *testing.T) {
+		exp, err := Explain(&Operation{
+			Type:       OperationTypeVariable,
+			Namespace:  "prod",
+			Path:       "payments/db",
+			Capability: VariablesCapabilityWrite,
+		}, policies)
+		must.NoError(t, err)
+		must.False(t, exp.Allowed)
+		must.Len(t, 1, exp.Rules)
+		must.Eq(t, `namespace "prod" variables path "payments/*"`, exp.Rules[0].Rule)
+		must.False(t, exp.Rules[0].Allowed)
+	})
+
+	t.Run("agent", func(t *testing.T) {
+		exp, err := Explain(&Operation{
+			Type:       OperationTypeAgent,
+			Capability: PolicyRead,
+		}, policies)
+		must.NoError(t, err)
+		must.True(t, exp.Allowed)
+		must.Eq(t, []*ExplainedRule{{
+			Policy:       "readers",
+			Rule:         "agent",
+			Capabilities: []string{PolicyRead},
+			Allowed:      true,
+		}}, exp.Rules)
+	})
+
+	t.Run("no matching rules", func(t *testing.T) {
+		exp, err := Explain(&Operation{
+			Type:       OperationTypeNodePool,
+			Name:       "prod",
+			Capability: NodePoolCapabilityRead,
+		}, policies)
+		must.NoError(t, err)
+		must.False(t, exp.Allowed)
+		must.SliceEmpty(t, exp.Rules)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		_, err := Explain(&Operation{Type: OperationTypeNodePool}, policies)
+		must.ErrorContains(t, err, "missing capability")
+	})
+}
@@ -55,6 +55,10 @@ const (
 
 var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
+
+	// validJobPattern is the rule used to validate the job ID glob pattern of
+	// a job rule. Job IDs can't contain spaces or null characters.
+	validJobPattern = regexp.MustCompile(`^[^\s\x00]{1,128}$`)
 )
 
 const (
@@ -134,6 +138,15 @@ type NamespacePolicy struct {
 	Policy       string
 	Capabilities []string
 	Variables    *VariablesPolicy `hcl:"variables"`
+	Jobs         []*JobPolicy     `hcl:"job,expand"`
+}
+
+// JobPolicy is the policy for the jobs of a namespace whose ID matches the
+// glob pattern in Name.
+type JobPolicy struct {
+	Name         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
 }
 
 // NodePoolPolicy is the policfy for a specific node pool.
@@ -216,6 +229,23 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isJobCapabilityValid ensures the given capability is valid for a job
+// policy. Only the namespace capabilities that apply to a single job can be
+// granted to a job.
+func isJobCapabilityValid(cap string) bool {
+	switch cap {
+	case NamespaceCapabilityDeny, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
+		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
+		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
+		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
+		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy,
+		NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
+		return true
+	default:
+		return false
+	}
+}
+
 // isPathCapabilityValid ensures the given capability is valid for a
 // variables path policy
 func isPathCapabilityValid(cap string) bool {
@@ -277,6 +307,18 @@ func expandNamespacePolicy(policy string) []string {
 	}
 }
 
+// expandJobPolicy provides the equivalent set of capabilities for a job
+// policy, which are the namespace capabilities that apply to a single job.
+func expandJobPolicy(policy string) []string {
+	var caps []string
+	for _, cap := range expandNamespacePolicy(policy) {
+		if isJobCapabilityValid(cap) {
+			caps = append(caps, cap)
+		}
+	}
+	return caps
+}
+
 func isNodePoolCapabilityValid(cap string) bool {
 	switch cap {
 	case NodePoolCapabilityDelete, NodePoolCapabilityRead, NodePoolCapabilityWrite,
@@ -407,6 +449,26 @@ func Parse(rules string) (*Policy, error) {
 
 		}
 
+		for _, job := range ns.Jobs {
+			if !validJobPattern.MatchString(job.Name) {
+				return nil, fmt.Errorf("Invalid job pattern '%s' in namespace %s", job.Name, ns.Name)
+			}
+			if job.Policy != "" && !isPolicyValid(job.Policy) {
+				return nil, fmt.Errorf("Invalid job policy '%s' for '%s' in namespace %s", job.Policy, job.Name, ns.Name)
+			}
+			for _, cap := range job.Capabilities {
+				if !isJobCapabilityValid(cap) {
+					return nil, fmt.Errorf("Invalid job capability '%s' for '%s' in namespace %s", cap, job.Name, ns.Name)
+				}
+			}
+
+			// Expand the short hand policy to the capabilities and
+			// add to any existing capabilities
+			if job.Policy != "" {
+				extraCap := expandJobPolicy(job.Policy)
+				job.Capabilities = append(job.Capabilities, extraCap...)
+			}
+		}
 	}
 
 	for _, np := range p.NodePools {
@@ -840,6 +840,102 @@ func TestParse(t *testing.T) {
 			"Invalid host volume name",
 			nil,
 		},
+		{
+			`
+			namespace "prod" {
+				policy = "read"
+				job "payments-*" {
+					capabilities = ["alloc-exec"]
+				}
+				job "billing" {
+					policy = "write"
+				}
+				job "secret" {
+					capabilities = ["deny"]
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name:   "prod",
+						Policy: PolicyRead,
+						Capabilities: []string{
+							NamespaceCapabilityListJobs,
+							NamespaceCapabilityParseJob,
+							NamespaceCapabilityReadJob,
+							NamespaceCapabilityCSIListVolume,
+							NamespaceCapabilityCSIReadVolume,
+							NamespaceCapabilityReadJobScaling,
+							NamespaceCapabilityListScalingPolicies,
+							NamespaceCapabilityReadScalingPolicy,
+						},
+						Jobs: []*JobPolicy{
+							{
+								Name:         "payments-*",
+								Capabilities: []string{NamespaceCapabilityAllocExec},
+							},
+							{
+								Name:   "billing",
+								Policy: PolicyWrite,
+								Capabilities: []string{
+									NamespaceCapabilityListJobs,
+									NamespaceCapabilityReadJob,
+									NamespaceCapabilityReadJobScaling,
+									NamespaceCapabilityListScalingPolicies,
+									NamespaceCapabilityReadScalingPolicy,
+									NamespaceCapabilityScaleJob,
+									NamespaceCapabilitySubmitJob,
+									NamespaceCapabilityDispatchJob,
+									NamespaceCapabilityReadLogs,
+									NamespaceCapabilityReadFS,
+									NamespaceCapabilityAllocExec,
+									NamespaceCapabilityAllocLifecycle,
+								},
+							},
+							{
+								Name:         "secret",
+								Capabilities: []string{NamespaceCapabilityDeny},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "prod" {
+				job "payments prod" {
+					capabilities = ["read-job"]
+				}
+			}
+			`,
+			"Invalid job pattern",
+			nil,
+		},
+		{
+			`
+			namespace "prod" {
+				job "payments" {
+					capabilities = ["csi-write-volume"]
+				}
+			}
+			`,
+			"Invalid job capability 'csi-write-volume'",
+			nil,
+		},
+		{
+			`
+			namespace "prod" {
+				job "payments" {
+					policy = "list"
+				}
+			}
+			`,
+			"Invalid job policy 'list'",
+			nil,
+		},
 		{
 			`
 			plugin {
@@ -178,6 +178,21 @@ func (a *ACLTokens) Self(q *QueryOptions) (*ACLToken, *QueryMeta, error) {
 	return &resp, wm, nil
 }
 
+// Renew is used to renew a renewable token, extending its expiration time.
+// If the accessor ID is empty, the token used to make the request is renewed.
+func (a *ACLTokens) Renew(accessorID string, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	path := "/v1/acl/token/self/renew"
+	if accessorID != "" {
+		path = "/v1/acl/token/" + accessorID + "/renew"
+	}
+	var resp ACLToken
+	wm, err := a.client.put(path, nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // UpsertOneTimeToken is used to create a one-time token
 func (a *ACLTokens) UpsertOneTimeToken(q *WriteOptions) (*OneTimeToken, *WriteMeta, error) {
 	var resp *OneTimeTokenUpsertResponse
@@ -208,6 +223,43 @@ func (a *ACLTokens) ExchangeOneTimeToken(secret string, q *WriteOptions) (*ACLTo
 	return resp.Token, wm, nil
 }
 
+// Explain is used to explain the ACL decision of a token for an operation.
+// If the accessor ID is empty, the token used to make the request is explained.
+func (a *ACLTokens) Explain(op *ACLOperation, accessorID string, q *QueryOptions) (*ACLExplanation, *QueryMeta, error) {
+	if op == nil {
+		return nil, nil, errors.New("missing operation")
+	}
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	if op.Namespace != "" {
+		q.Namespace = op.Namespace
+	}
+	params := map[string]string{
+		"type":        op.Type,
+		"job":         op.JobID,
+		"path":        op.Path,
+		"name":        op.Name,
+		"capability":  op.Capability,
+		"accessor_id": accessorID,
+	}
+	for k, v := range params {
+		if v != "" {
+			q.Params[k] = v
+		}
+	}
+
+	var resp ACLExplanation
+	qm, err := a.client.query("/v1/acl/explain", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 var (
 	// errMissingACLRoleID is the generic errors to use when a call is missing
 	// the required ACL Role ID parameter.
@@ -494,6 +546,19 @@ func (a *ACLAuth) CompleteAuth(req *ACLOIDCCompleteAuthRequest, q *WriteOptions)
 	return &resp, wm, nil
 }
 
+// DeviceAuth starts the OIDC device authorization flow. The user should visit
+// the verification URI of the response and enter the user code, while
+// CompleteAuth is polled with the device code until the authorization has been
+// completed.
+func (a *ACLAuth) DeviceAuth(req *ACLOIDCDeviceAuthRequest, q *WriteOptions) (*ACLOIDCDeviceAuthResponse, *WriteMeta, error) {
+	var resp ACLOIDCDeviceAuthResponse
+	wm, err := a.client.put("/v1/acl/oidc/device-auth", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Login exchanges the third party token for a Nomad token with the appropriate
 // claims attached.
 func (a *ACLAuth) Login(req *ACLLoginRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
@@ -558,10 +623,126 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration `json:",omitempty"`
 
+	// Renewable indicates the token can be renewed, which extends its
+	// ExpirationTime to the time of the renewal plus its ExpirationTTL.
+	Renewable bool `json:",omitempty"`
+
+	// MaxTTL is the maximum lifetime of a renewable token since its creation,
+	// after which renewals will no longer extend its ExpirationTime. If zero,
+	// the region's maximum token expiration TTL is used.
+	MaxTTL time.Duration `json:",omitempty"`
+
+	// AllocID is the ID of the allocation the token was derived for by the
+	// nomad block of one of its tasks. These tokens are revoked when the
+	// allocation stops.
+	AllocID string `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+const (
+	// The following are the types of operation whose ACL decision can be
+	// explained.
+	ACLOperationTypeNamespace  = "namespace"
+	ACLOperationTypeVariable   = "variable"
+	ACLOperationTypeNodePool   = "node_pool"
+	ACLOperationTypeHostVolume = "host_volume"
+	ACLOperationTypeAgent      = "agent"
+	ACLOperationTypeNode       = "node"
+	ACLOperationTypeOperator   = "operator"
+	ACLOperationTypeQuota      = "quota"
+	ACLOperationTypePlugin     = "plugin"
+)
+
+// ACLOperation describes an operation whose ACL decision can be explained.
+type ACLOperation struct {
+	// Type is the type of resource of the operation, such as "namespace" or
+	// "variable".
+	Type string
+
+	// Namespace is the namespace of namespace and variable operations.
+	Namespace string
+
+	// JobID optionally narrows namespace operations to a single job.
+	JobID string
+
+	// Path is the path of variable operations.
+	Path string
+
+	// Name is the name of the node pool or host volume.
+	Name string
+
+	// Capability is the capability checked by the operation. For the agent,
+	// node, operator, quota and plugin types it is the policy disposition,
+	// such as "read" or "write".
+	Capability string
+}
+
+// ACLExplanation is the ACL decision of a token for an operation, along with
+// the policy rules that produced it.
+type ACLExplanation struct {
+	AccessorID string
+	Name       string
+	Type       string
+
+	// Allowed is the decision of the token for the operation.
+	Allowed bool
+
+	// Management is true if the token is a management token, in which case
+	// every operation is allowed and no rules are reported.
+	Management bool
+
+	// Expired is true if the token has expired, in which case every
+	// operation is denied regardless of the rules reported.
+	Expired bool
+
+	// Rules are the rules of the token policies that apply to the operation.
+	Rules []*ACLExplainedRule
+
+	// Policies describes how each policy came to be attached to the token.
+	Policies []*ACLExplanationPolicy
+}
+
+// ACLExplainedRule is a policy rule that applies to an operation.
+type ACLExplainedRule struct {
+	// Policy is the name of the policy the rule belongs to.
+	Policy string
+
+	// Rule identifies the rule within the policy, such as
+	// `namespace "prod" job "payments-*"`.
+	Rule string
+
+	// Capabilities are the capabilities granted by the rule.
+	Capabilities []string
+
+	// Allowed is true if the rule grants the capability of the operation.
+	Allowed bool
+
+	// Denied is true if the rule denies access, which takes precedence over
+	// the capabilities granted by any other rule.
+	Denied bool
+}
+
+// ACLExplanationPolicy describes how a policy came to be attached to a token.
+type ACLExplanationPolicy struct {
+	// Name is the name of the policy.
+	Name string
+
+	// Token is true if the policy is linked directly to the token.
+	Token bool
+
+	// Roles are the names of the token roles that link the policy.
+	Roles []string
+
+	// BindingRules are the IDs of the binding rules that bind either the
+	// policy or one of the roles linking it.
+	BindingRules []string
+
+	// Missing is true if the policy no longer exists.
+	Missing bool
+}
+
 // ACLTokenRoleLink is used to link an ACL token to an ACL role. The ACL token
 // can therefore inherit all the ACL policy permissions that the ACL role
 // contains.
@@ -582,14 +763,19 @@ func (a *ACLToken) MarshalJSON() ([]byte, error) {
 	type Alias ACLToken
 	exported := &struct {
 		ExpirationTTL string
+		MaxTTL        string
 		*Alias
 	}{
 		ExpirationTTL: a.ExpirationTTL.String(),
+		MaxTTL:        a.MaxTTL.String(),
 		Alias:         (*Alias)(a),
 	}
 	if a.ExpirationTTL == 0 {
 		exported.ExpirationTTL = ""
 	}
+	if a.MaxTTL == 0 {
+		exported.MaxTTL = ""
+	}
 	return json.Marshal(exported)
 }
 
@@ -599,6 +785,7 @@ func (a *ACLToken) UnmarshalJSON(data []byte) (err error) {
 	type Alias ACLToken
 	aux := &struct {
 		ExpirationTTL any
+		MaxTTL        any
 		*Alias
 	}{
 		Alias: (*Alias)(a),
@@ -620,9 +807,26 @@ func (a *ACLToken) UnmarshalJSON(data []byte) (err error) {
 		}
 
 	}
+	if a.MaxTTL, err = unmarshalJSONDuration(aux.MaxTTL); err != nil {
+		return err
+	}
 	return nil
 }
 
+// unmarshalJSONDuration converts a duration decoded from JSON, which can be
+// either a duration string like "2m" or a number of nanoseconds.
+func unmarshalJSONDuration(v any) (time.Duration, error) {
+	switch v := v.(type) {
+	case string:
+		if v != "" {
+			return time.ParseDuration(v)
+		}
+	case float64:
+		return time.Duration(v), nil
+	}
+	return 0, nil
+}
+
 type ACLTokenListStub struct {
 	AccessorID string
 	Name       string
@@ -637,6 +841,12 @@ type ACLTokenListStub struct {
 	// indicates no expiration has been set on the token.
 	ExpirationTime *time.Time `json:",omitempty"`
 
+	// Renewable indicates the token can be renewed.
+	Renewable bool `json:",omitempty"`
+
+	// AllocID is the ID of the allocation the token was derived for, if any.
+	AllocID string `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -692,10 +902,52 @@ type ACLRole struct {
 	// both. At least one entry is required.
 	Policies []*ACLRolePolicyLink
 
+	// TokenRenewable makes the ACL tokens created with a link to this role
+	// renewable, as long as they have an expiration TTL.
+	TokenRenewable bool `json:",omitempty"`
+
+	// TokenMaxTTL limits the MaxTTL of the renewable ACL tokens created with a
+	// link to this role. If zero, the role doesn't limit the MaxTTL.
+	TokenMaxTTL time.Duration `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// MarshalJSON implements the json.Marshaler interface and allows
+// ACLRole.TokenMaxTTL to be marshaled correctly.
+func (r *ACLRole) MarshalJSON() ([]byte, error) {
+	type Alias ACLRole
+	exported := &struct {
+		TokenMaxTTL string `json:",omitempty"`
+		*Alias
+	}{
+		TokenMaxTTL: r.TokenMaxTTL.String(),
+		Alias:       (*Alias)(r),
+	}
+	if r.TokenMaxTTL == 0 {
+		exported.TokenMaxTTL = ""
+	}
+	return json.Marshal(exported)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and allows
+// ACLRole.TokenMaxTTL to be unmarshalled correctly.
+func (r *ACLRole) UnmarshalJSON(data []byte) (err error) {
+	type Alias ACLRole
+	aux := &struct {
+		TokenMaxTTL any
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err = json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.TokenMaxTTL, err = unmarshalJSONDuration(aux.TokenMaxTTL)
+	return err
+}
+
 // ACLRolePolicyLink is used to link a policy to an ACL role. We use a struct
 // rather than a list of strings as in the future we will want to add IDs to
 // policies and then link via these.
@@ -760,6 +1012,14 @@ type ACLAuthMethod struct {
 	// MaxTokenTTL is the maximum life of a token created by this method.
 	MaxTokenTTL time.Duration
 
+	// TokenTTL is the expiration TTL of the tokens created by this method.
+	// If zero, MaxTokenTTL is used.
+	TokenTTL time.Duration
+
+	// TokenRenewable makes the tokens created by this method renewable, up to
+	// MaxTokenTTL since their creation.
+	TokenRenewable bool
+
 	// Default identifies whether this is the default auth-method to use when
 	// attempting to login without specifying an auth-method name to use.
 	Default bool
@@ -780,14 +1040,19 @@ func (m *ACLAuthMethod) MarshalJSON() ([]byte, error) {
 	type Alias ACLAuthMethod
 	exported := &struct {
 		MaxTokenTTL string
+		TokenTTL    string
 		*Alias
 	}{
 		MaxTokenTTL: m.MaxTokenTTL.String(),
+		TokenTTL:    m.TokenTTL.String(),
 		Alias:       (*Alias)(m),
 	}
 	if m.MaxTokenTTL == 0 {
 		exported.MaxTokenTTL = ""
 	}
+	if m.TokenTTL == 0 {
+		exported.TokenTTL = ""
+	}
 	return json.Marshal(exported)
 }
 
@@ -797,6 +1062,7 @@ func (m *ACLAuthMethod) UnmarshalJSON(data []byte) error {
 	type Alias ACLAuthMethod
 	aux := &struct {
 		MaxTokenTTL string
+		TokenTTL    string
 		*Alias
 	}{
 		Alias: (*Alias)(m),
@@ -810,6 +1076,11 @@ func (m *ACLAuthMethod) UnmarshalJSON(data []byte) error {
 			return err
 		}
 	}
+	if aux.TokenTTL != "" {
+		if m.TokenTTL, err = time.ParseDuration(aux.TokenTTL); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -828,6 +1099,8 @@ type ACLAuthMethodConfig struct {
 	OIDCClientSecret string
 	// Disable claims from the OIDC UserInfo endpoint
 	OIDCDisableUserInfo bool
+	// Require the PKCE extension for the OIDC authorization code flow
+	OIDCEnablePKCE bool
 	// List of OIDC scopes
 	OIDCScopes []string
 	// List of auth claims that are valid for login
@@ -857,6 +1130,35 @@ type ACLAuthMethodConfig struct {
 	// (value).
 	ClaimMappings     map[string]string
 	ListClaimMappings map[string]string
+	// The URLs of the LDAP servers, such as "ldaps://ldap.example.com".
+	LDAPURLs []string
+	// Upgrade "ldap://" connections to TLS using the StartTLS operation.
+	LDAPStartTLS bool
+	// Skip the verification of the LDAP server certificate.
+	LDAPInsecureSkipVerify bool
+	// PEM encoded CA cert for use by the TLS client used to talk with the
+	// LDAP servers.
+	LDAPCACert string
+	// The credentials used to search for users and groups. If the bind DN is
+	// empty, searches are performed as the user logging in.
+	LDAPBindDN       string
+	LDAPBindPassword string
+	// The base DN under which to search for users.
+	LDAPUserDN string
+	// The attribute of user entries matching the username.
+	LDAPUserAttr string
+	// The Go template of the filter used to search for the user logging in.
+	LDAPUserFilter string
+	// The base DN under which to search for groups.
+	LDAPGroupDN string
+	// The attribute of group entries holding the group name.
+	LDAPGroupAttr string
+	// The Go template of the filter used to search for the groups of the user.
+	LDAPGroupFilter string
+
+	// PEM encoded CA certs trusted to issue the client certificates
+	// authenticated by the cert auth method.
+	CertCACerts []string
 }
 
 // MarshalJSON implements the json.Marshaler interface and allows
@@ -976,6 +1278,14 @@ const (
 	// ACLAuthMethodTypeJWT the ACLAuthMethod.Type and represents an auth-method
 	// which uses the JWT type.
 	ACLAuthMethodTypeJWT = "JWT"
+
+	// ACLAuthMethodTypeLDAP the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates users against an LDAP directory.
+	ACLAuthMethodTypeLDAP = "LDAP"
+
+	// ACLAuthMethodTypeCert the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates TLS client certificates.
+	ACLAuthMethodTypeCert = "CERT"
 )
 
 // ACLBindingRule contains a direct relation to an ACLAuthMethod and represents
@@ -1075,6 +1385,11 @@ type ACLOIDCAuthURLRequest struct {
 	// is up to the client to generate this and Go integrations should use the
 	// oidc.NewID function within the hashicorp/cap library.
 	ClientNonce string
+
+	// CodeChallenge is the S256 PKCE code challenge of the code verifier
+	// generated by the client. It is required if the auth method enables
+	// PKCE.
+	CodeChallenge string `json:",omitempty"`
 }
 
 // ACLOIDCAuthURLResponse is the response when starting the OIDC authentication
@@ -1104,6 +1419,49 @@ type ACLOIDCCompleteAuthRequest struct {
 	// RedirectURI is the URL that authorization should redirect to. This is a
 	// required parameter.
 	RedirectURI string
+
+	// CodeVerifier is the PKCE code verifier of the challenge passed to
+	// ACLOIDCAuthURLRequest. It is required if the auth method enables PKCE.
+	CodeVerifier string `json:",omitempty"`
+
+	// DeviceCode is the device code returned by ACLOIDCDeviceAuthResponse.
+	// When set, the request polls for the completion of the device
+	// authorization and the other parameters are not required.
+	DeviceCode string `json:",omitempty"`
+}
+
+// ACLOIDCDeviceAuthRequest is the request to make when starting the OIDC
+// device authorization flow.
+type ACLOIDCDeviceAuthRequest struct {
+
+	// AuthMethodName is the OIDC auth-method to use. This is a required
+	// parameter.
+	AuthMethodName string
+}
+
+// ACLOIDCDeviceAuthResponse is the response when starting the OIDC device
+// authorization flow.
+type ACLOIDCDeviceAuthResponse struct {
+
+	// DeviceCode is passed to ACLOIDCCompleteAuthRequest to poll for the
+	// completion of the authorization.
+	DeviceCode string
+
+	// UserCode is the code the user logging in should enter at the
+	// verification URI.
+	UserCode string
+
+	// VerificationURI is where the user logging in should go, and
+	// VerificationURIComplete optionally includes the user code.
+	VerificationURI         string
+	VerificationURIComplete string
+
+	// Expiry is the time at which the device code expires.
+	Expiry time.Time
+
+	// Interval is the minimum duration between polls for the completion of
+	// the authorization.
+	Interval time.Duration
 }
 
 // ACLLoginRequest is the request object to begin auth with an external bearer
@@ -1112,6 +1470,11 @@ type ACLLoginRequest struct {
 	// AuthMethodName is the name of the auth method being used to login. This
 	// is a required parameter.
 	AuthMethodName string
-	// LoginToken is the token used to login. This is a required parameter.
+	// LoginToken is the token used to login. This is a required parameter,
+	// unless logging in with a username and password.
 	LoginToken string
+	// Username and Password are the credentials used to login with auth
+	// methods which don't use tokens, such as LDAP.
+	Username string `json:",omitempty"`
+	Password string `json:",omitempty"`
 }
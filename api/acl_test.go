@@ -421,6 +421,39 @@ func TestACLTokens_Self(t *testing.T) {
 	must.Eq(t, out, out2)
 }
 
+func TestACLTokens_Explain(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	at := c.ACLTokens()
+
+	// Explain the operation using the management token.
+	op := &ACLOperation{
+		Type:       ACLOperationTypeNamespace,
+		Namespace:  "default",
+		Capability: "submit-job",
+	}
+	out, qm, err := at.Explain(op, "", nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.True(t, out.Allowed)
+	must.True(t, out.Management)
+
+	// Explain the operation for a token without policies.
+	token, _, err := at.Create(&ACLToken{
+		Name:     "foo",
+		Type:     "client",
+		Policies: []string{"foo1"},
+	}, nil)
+	must.NoError(t, err)
+
+	out, _, err = at.Explain(op, token.AccessorID, nil)
+	must.NoError(t, err)
+	must.False(t, out.Allowed)
+	must.Eq(t, []*ACLExplanationPolicy{{Name: "foo1", Token: true, Missing: true}}, out.Policies)
+}
+
 func TestACLTokens_Delete(t *testing.T) {
 	testutil.Parallel(t)
 
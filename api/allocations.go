@@ -436,6 +436,16 @@ type AllocNetworkStatus struct {
 	InterfaceName string
 	Address       string
 	DNS           *DNSConfig
+	Interfaces    []*AllocNetworkInterfaceStatus
+}
+
+// AllocNetworkInterfaceStatus is the status of one of the networks of an
+// allocation with multiple network blocks.
+type AllocNetworkInterfaceStatus struct {
+	Mode          string
+	InterfaceName string
+	Address       string
+	PortLabels    []string
 }
 
 type AllocatedResources struct {
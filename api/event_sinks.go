@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	// EventSinkWebhook sinks send batches of events as a JSON array in the
+	// body of an HTTP POST request.
+	EventSinkWebhook = "webhook"
+
+	// EventSinkFile sinks append batches of events as newline delimited JSON
+	// to a file on the leader.
+	EventSinkFile = "file"
+
+	// EventSinkExec sinks run a command on the leader for every batch of
+	// events and write the batch as newline delimited JSON to its stdin.
+	EventSinkExec = "exec"
+)
+
+// EventSinks is used to access event sink endpoints.
+type EventSinks struct {
+	client *Client
+}
+
+// EventSinks returns a handle on the event sink endpoints.
+func (c *Client) EventSinks() *EventSinks {
+	return &EventSinks{client: c}
+}
+
+// List is used to list the event sinks.
+func (e *EventSinks) List(q *QueryOptions) ([]*EventSinkListStub, *QueryMeta, error) {
+	var resp []*EventSinkListStub
+	qm, err := e.client.query("/v1/event/sinks", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Info is used to fetch details of a specific event sink.
+func (e *EventSinks) Info(id string, q *QueryOptions) (*EventSink, *QueryMeta, error) {
+	if id == "" {
+		return nil, nil, errors.New("missing event sink ID")
+	}
+
+	var resp EventSink
+	qm, err := e.client.query("/v1/event/sink/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create or update an event sink.
+func (e *EventSinks) Register(sink *EventSink, w *WriteOptions) (*WriteMeta, error) {
+	if sink == nil {
+		return nil, errors.New("missing event sink")
+	}
+	if sink.ID == "" {
+		return nil, errors.New("missing event sink ID")
+	}
+
+	wm, err := e.client.put("/v1/event/sink/"+url.PathEscape(sink.ID), sink, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete an event sink.
+func (e *EventSinks) Delete(id string, w *WriteOptions) (*WriteMeta, error) {
+	if id == "" {
+		return nil, errors.New("missing event sink ID")
+	}
+
+	wm, err := e.client.delete("/v1/event/sink/"+url.PathEscape(id), nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// EventSink is used to serialize an event sink.
+type EventSink struct {
+	ID            string
+	Type          string
+	Topics        map[Topic][]string
+	Namespace     string
+	Address       string
+	Headers       map[string]string
+	Path          string
+	Command       string
+	Args          []string
+	BatchSize     int
+	BatchInterval time.Duration
+	LatestIndex   uint64
+	Gaps          uint64
+	LastGap       *EventSinkGap
+	CreateIndex   uint64
+	ModifyIndex   uint64
+}
+
+// EventSinkGap describes a range of indexes whose events may have been dropped
+// from the event buffer before they could be delivered to a sink.
+type EventSinkGap struct {
+	FromIndex uint64
+	ToIndex   uint64
+	Time      time.Time
+}
+
+// EventSinkListStub is used to serialize the summary of an event sink
+// returned when listing sinks.
+type EventSinkListStub struct {
+	ID          string
+	Type        string
+	Topics      map[Topic][]string
+	Namespace   string
+	LatestIndex uint64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
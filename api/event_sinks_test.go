@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestEventSinks_CRUD(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	sinks := c.EventSinks()
+
+	// Create an event sink.
+	sink := &EventSink{
+		ID:      "jobs",
+		Type:    EventSinkWebhook,
+		Topics:  map[Topic][]string{TopicJob: {"*"}},
+		Address: "http://127.0.0.1:8080/events",
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}
+	wm, err := sinks.Register(sink, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// Read it back, including the defaults set by the server.
+	resp, qm, err := sinks.Info(sink.ID, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.Eq(t, sink.Address, resp.Address)
+	must.Eq(t, sink.Headers, resp.Headers)
+	must.Eq(t, "*", resp.Namespace)
+	must.Positive(t, resp.BatchSize)
+
+	// List the sinks.
+	list, _, err := sinks.List(nil)
+	must.NoError(t, err)
+	must.Len(t, 1, list)
+	must.Eq(t, sink.ID, list[0].ID)
+	must.Eq(t, sink.Topics, list[0].Topics)
+
+	// Delete the sink.
+	wm, err = sinks.Delete(sink.ID, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = sinks.Info(sink.ID, nil)
+	must.ErrorContains(t, err, "not found")
+}
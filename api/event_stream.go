@@ -14,14 +14,21 @@ import (
 )
 
 const (
-	TopicDeployment Topic = "Deployment"
-	TopicEvaluation Topic = "Evaluation"
-	TopicAllocation Topic = "Allocation"
-	TopicJob        Topic = "Job"
-	TopicNode       Topic = "Node"
-	TopicNodePool   Topic = "NodePool"
-	TopicService    Topic = "Service"
-	TopicAll        Topic = "*"
+	TopicDeployment    Topic = "Deployment"
+	TopicEvaluation    Topic = "Evaluation"
+	TopicAllocation    Topic = "Allocation"
+	TopicJob           Topic = "Job"
+	TopicNode          Topic = "Node"
+	TopicNodePool      Topic = "NodePool"
+	TopicService       Topic = "Service"
+	TopicVariable      Topic = "Variable"
+	TopicNamespace     Topic = "Namespace"
+	TopicCSIVolume     Topic = "CSIVolume"
+	TopicCSIPlugin     Topic = "CSIPlugin"
+	TopicScalingPolicy Topic = "ScalingPolicy"
+	TopicScalingEvent  Topic = "ScalingEvent"
+	TopicRootKey       Topic = "RootKey"
+	TopicAll           Topic = "*"
 )
 
 // Events is a set of events for a corresponding index. Events returned for the
@@ -120,14 +127,93 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// Variable returns the VariableMetadata from a given event payload. If the
+// Event Topic is Variable this will return valid VariableMetadata. The
+// variable items are never included in the event stream.
+func (e *Event) Variable() (*VariableMetadata, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Variable, nil
+}
+
+// Namespace returns a Namespace struct from a given event payload. If the
+// Event Topic is Namespace this will return a valid Namespace.
+func (e *Event) Namespace() (*Namespace, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Namespace, nil
+}
+
+// CSIVolume returns a CSIVolumeListStub from a given event payload. If the
+// Event Topic is CSIVolume this will return a valid CSIVolumeListStub.
+func (e *Event) CSIVolume() (*CSIVolumeListStub, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Volume, nil
+}
+
+// CSIPlugin returns a CSIPluginListStub from a given event payload. If the
+// Event Topic is CSIPlugin this will return a valid CSIPluginListStub.
+func (e *Event) CSIPlugin() (*CSIPluginListStub, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Plugin, nil
+}
+
+// ScalingPolicy returns a ScalingPolicy struct from a given event payload. If
+// the Event Topic is ScalingPolicy this will return a valid ScalingPolicy.
+func (e *Event) ScalingPolicy() (*ScalingPolicy, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.ScalingPolicy, nil
+}
+
+// ScalingEvents returns a JobScalingEvents struct from a given event payload.
+// If the Event Topic is ScalingEvent this will return a valid
+// JobScalingEvents.
+func (e *Event) ScalingEvents() (*JobScalingEvents, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.ScalingEvents, nil
+}
+
+// RootKeyMeta returns a RootKeyMeta struct from a given event payload. If the
+// Event Topic is RootKey this will return a valid RootKeyMeta.
+func (e *Event) RootKeyMeta() (*RootKeyMeta, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.RootKeyMeta, nil
+}
+
 type eventPayload struct {
-	Allocation *Allocation          `mapstructure:"Allocation"`
-	Deployment *Deployment          `mapstructure:"Deployment"`
-	Evaluation *Evaluation          `mapstructure:"Evaluation"`
-	Job        *Job                 `mapstructure:"Job"`
-	Node       *Node                `mapstructure:"Node"`
-	NodePool   *NodePool            `mapstructure:"NodePool"`
-	Service    *ServiceRegistration `mapstructure:"Service"`
+	Allocation    *Allocation          `mapstructure:"Allocation"`
+	Deployment    *Deployment          `mapstructure:"Deployment"`
+	Evaluation    *Evaluation          `mapstructure:"Evaluation"`
+	Job           *Job                 `mapstructure:"Job"`
+	Node          *Node                `mapstructure:"Node"`
+	NodePool      *NodePool            `mapstructure:"NodePool"`
+	Service       *ServiceRegistration `mapstructure:"Service"`
+	Variable      *VariableMetadata    `mapstructure:"Variable"`
+	Namespace     *Namespace           `mapstructure:"Namespace"`
+	Volume        *CSIVolumeListStub   `mapstructure:"Volume"`
+	Plugin        *CSIPluginListStub   `mapstructure:"Plugin"`
+	ScalingPolicy *ScalingPolicy       `mapstructure:"ScalingPolicy"`
+	ScalingEvents *JobScalingEvents    `mapstructure:"ScalingEvents"`
+	RootKeyMeta   *RootKeyMeta         `mapstructure:"RootKeyMeta"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
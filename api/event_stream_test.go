@@ -49,6 +49,34 @@ func TestTopic_String(t *testing.T) {
 			inputTopic:     TopicService,
 			expectedOutput: "Service",
 		},
+		{
+			inputTopic:     TopicVariable,
+			expectedOutput: "Variable",
+		},
+		{
+			inputTopic:     TopicNamespace,
+			expectedOutput: "Namespace",
+		},
+		{
+			inputTopic:     TopicCSIVolume,
+			expectedOutput: "CSIVolume",
+		},
+		{
+			inputTopic:     TopicCSIPlugin,
+			expectedOutput: "CSIPlugin",
+		},
+		{
+			inputTopic:     TopicScalingPolicy,
+			expectedOutput: "ScalingPolicy",
+		},
+		{
+			inputTopic:     TopicScalingEvent,
+			expectedOutput: "ScalingEvent",
+		},
+		{
+			inputTopic:     TopicRootKey,
+			expectedOutput: "RootKey",
+		},
 		{
 			inputTopic:     TopicAll,
 			expectedOutput: "*",
@@ -319,6 +347,98 @@ func TestEventStream_PayloadValueHelpers(t *testing.T) {
 				}, n)
 			},
 		},
+		{
+			desc:  "variable",
+			input: []byte(`{"Topic":"Variable","Payload":{"Variable":{"Namespace":"default","Path":"nomad/jobs/example","ModifyIndex":10}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicVariable, event.Topic)
+				v, err := event.Variable()
+				must.NoError(t, err)
+				must.Eq(t, &VariableMetadata{
+					Namespace:   "default",
+					Path:        "nomad/jobs/example",
+					ModifyIndex: 10,
+				}, v)
+			},
+		},
+		{
+			desc:  "namespace",
+			input: []byte(`{"Topic":"Namespace","Payload":{"Namespace":{"Name":"prod","Description":"production"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicNamespace, event.Topic)
+				ns, err := event.Namespace()
+				must.NoError(t, err)
+				must.Eq(t, &Namespace{
+					Name:        "prod",
+					Description: "production",
+				}, ns)
+			},
+		},
+		{
+			desc:  "csi_volume",
+			input: []byte(`{"Topic":"CSIVolume","Payload":{"Volume":{"ID":"vol","Namespace":"default","PluginID":"ebs"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicCSIVolume, event.Topic)
+				vol, err := event.CSIVolume()
+				must.NoError(t, err)
+				must.Eq(t, &CSIVolumeListStub{
+					ID:        "vol",
+					Namespace: "default",
+					PluginID:  "ebs",
+				}, vol)
+			},
+		},
+		{
+			desc:  "csi_plugin",
+			input: []byte(`{"Topic":"CSIPlugin","Payload":{"Plugin":{"ID":"ebs","Provider":"aws.ebs"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicCSIPlugin, event.Topic)
+				plug, err := event.CSIPlugin()
+				must.NoError(t, err)
+				must.Eq(t, &CSIPluginListStub{
+					ID:       "ebs",
+					Provider: "aws.ebs",
+				}, plug)
+			},
+		},
+		{
+			desc:  "scaling_policy",
+			input: []byte(`{"Topic":"ScalingPolicy","Payload":{"ScalingPolicy":{"ID":"some-id","Type":"horizontal"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicScalingPolicy, event.Topic)
+				p, err := event.ScalingPolicy()
+				must.NoError(t, err)
+				must.Eq(t, &ScalingPolicy{
+					ID:   "some-id",
+					Type: "horizontal",
+				}, p)
+			},
+		},
+		{
+			desc:  "scaling_event",
+			input: []byte(`{"Topic":"ScalingEvent","Payload":{"ScalingEvents":{"Namespace":"default","JobID":"example","ScalingEvents":{"web":[{"Message":"scaled"}]}}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicScalingEvent, event.Topic)
+				e, err := event.ScalingEvents()
+				must.NoError(t, err)
+				must.Eq(t, "example", e.JobID)
+				must.Len(t, 1, e.ScalingEvents["web"])
+				must.Eq(t, "scaled", e.ScalingEvents["web"][0].Message)
+			},
+		},
+		{
+			desc:  "root_key",
+			input: []byte(`{"Topic":"RootKey","Payload":{"RootKeyMeta":{"KeyID":"some-id","State":"active"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				must.Eq(t, TopicRootKey, event.Topic)
+				k, err := event.RootKeyMeta()
+				must.NoError(t, err)
+				must.Eq(t, &RootKeyMeta{
+					KeyID: "some-id",
+					State: RootKeyStateActive,
+				}, k)
+			},
+		},
 		{
 			desc:  "service",
 			input: []byte(`{"Topic": "Service", "Payload": {"Service":{"ID":"some-service-id","Namespace":"some-service-namespace-id","Datacenter":"us-east-1a"}}}`),
@@ -253,6 +253,28 @@ func (j *Jobs) ScaleStatus(jobID string, q *QueryOptions) (*JobScaleStatusRespon
 	return &resp, qm, nil
 }
 
+// Prefetch is used to pull the container images and download the artifacts of
+// a job onto the nodes it may be placed on, ahead of its placement.
+func (j *Jobs) Prefetch(jobID string, q *WriteOptions) (*JobPrefetchResponse, *WriteMeta, error) {
+	var resp JobPrefetchResponse
+	wm, err := j.client.put(fmt.Sprintf("/v1/job/%s/prefetch", url.PathEscape(jobID)), nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// PrefetchStatus is used to retrieve the progress of prefetching a job on each
+// node it may be placed on.
+func (j *Jobs) PrefetchStatus(jobID string, q *QueryOptions) (*JobPrefetchStatusResponse, *QueryMeta, error) {
+	var resp JobPrefetchStatusResponse
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/prefetch", url.PathEscape(jobID)), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Versions is used to retrieve all versions of a particular job given its
 // unique ID.
 func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*JobDiff, *QueryMeta, error) {
@@ -1526,6 +1548,66 @@ type JobDispatchResponse struct {
 	WriteMeta
 }
 
+// JobPrefetchResponse is used to respond to a job prefetch request.
+type JobPrefetchResponse struct {
+	// NodeIDs are the nodes prefetching the job
+	NodeIDs []string
+
+	// Errors maps the IDs of the nodes on which prefetching could not be
+	// started to the error
+	Errors map[string]string
+
+	WriteMeta
+}
+
+// JobPrefetchStatusResponse is used to respond to a job prefetch status
+// request.
+type JobPrefetchStatusResponse struct {
+	// Nodes is the progress of prefetching the job on each node
+	Nodes []*NodePrefetch
+
+	// Errors maps the IDs of the nodes whose progress could not be read to
+	// the error
+	Errors map[string]string
+
+	QueryMeta
+}
+
+const (
+	NodePrefetchStatusPending  = "pending"
+	NodePrefetchStatusRunning  = "running"
+	NodePrefetchStatusComplete = "complete"
+	NodePrefetchStatusFailed   = "failed"
+	NodePrefetchStatusSkipped  = "skipped"
+)
+
+// NodePrefetch is the progress of prefetching the container images and
+// artifacts of a job on a node.
+type NodePrefetch struct {
+	NodeID     string
+	Namespace  string
+	JobID      string
+	JobVersion uint64
+	Status     string
+	Images     []*NodePrefetchItem
+	Artifacts  []*NodePrefetchItem
+	StartTime  int64
+	EndTime    int64
+}
+
+// Terminal returns whether prefetching has finished.
+func (p *NodePrefetch) Terminal() bool {
+	return p.Status == NodePrefetchStatusComplete || p.Status == NodePrefetchStatusFailed
+}
+
+// NodePrefetchItem is the progress of prefetching a container image or an
+// artifact.
+type NodePrefetchItem struct {
+	Source string
+	Status string
+	Error  string
+}
+
 // JobVersionsResponse is used for a job get versions request
 type JobVersionsResponse struct {
 	Versions []*Job
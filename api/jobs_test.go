@@ -2530,6 +2530,37 @@ func TestJobs_ScaleStatus(t *testing.T) {
 	must.Eq(t, groupCount, result.TaskGroups[groupName].Desired)
 }
 
+func TestJobs_Prefetch(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Prefetching an unknown job returns an error
+	_, _, err := jobs.Prefetch("unknown", nil)
+	must.ErrorContains(t, err, "not found")
+
+	// Register a job with an artifact
+	job := testJob()
+	job.TaskGroups[0].Tasks[0].Artifacts = []*TaskArtifact{{
+		GetterSource: pointerOf("https://example.com/file.txt"),
+	}}
+	_, wm, err := jobs.Register(job, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	resp, wm, err := jobs.Prefetch(*job.ID, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+	must.MapEmpty(t, resp.Errors)
+
+	status, qm, err := jobs.PrefetchStatus(*job.ID, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.NotNil(t, status.Nodes)
+}
+
 func TestJobs_Services(t *testing.T) {
 	// TODO(jrasell) add tests once registration process is in place.
 }
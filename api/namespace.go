@@ -70,16 +70,17 @@ func (n *Namespaces) Delete(namespace string, q *WriteOptions) (*WriteMeta, erro
 
 // Namespace is used to serialize a namespace.
 type Namespace struct {
-	Name                  string
-	Description           string
-	Quota                 string
-	Capabilities          *NamespaceCapabilities          `hcl:"capabilities,block"`
-	NodePoolConfiguration *NamespaceNodePoolConfiguration `hcl:"node_pool_config,block"`
-	VaultConfiguration    *NamespaceVaultConfiguration    `hcl:"vault,block"`
-	ConsulConfiguration   *NamespaceConsulConfiguration   `hcl:"consul,block"`
-	Meta                  map[string]string
-	CreateIndex           uint64
-	ModifyIndex           uint64
+	Name                   string
+	Description            string
+	Quota                  string
+	Capabilities           *NamespaceCapabilities           `hcl:"capabilities,block"`
+	NodePoolConfiguration  *NamespaceNodePoolConfiguration  `hcl:"node_pool_config,block"`
+	VaultConfiguration     *NamespaceVaultConfiguration     `hcl:"vault,block"`
+	ConsulConfiguration    *NamespaceConsulConfiguration    `hcl:"consul,block"`
+	VariablesConfiguration *NamespaceVariablesConfiguration `hcl:"variables,block"`
+	Meta                   map[string]string
+	CreateIndex            uint64
+	ModifyIndex            uint64
 }
 
 // NamespaceCapabilities represents a set of capabilities allowed for this
@@ -141,6 +142,15 @@ type NamespaceConsulConfiguration struct {
 	Denied []string
 }
 
+// NamespaceVariablesConfiguration stores configuration about variables for a
+// namespace.
+type NamespaceVariablesConfiguration struct {
+	// MaxVersions is the number of previous versions of each variable kept
+	// in the variable's history. Zero disables the history. If unset, the
+	// server's default is used.
+	MaxVersions *int `hcl:"max_versions"`
+}
+
 // NamespaceIndexSort is a wrapper to sort Namespaces by CreateIndex. We
 // reverse the test so that we get the highest index first.
 type NamespaceIndexSort []*Namespace
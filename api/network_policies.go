@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/url"
+)
+
+// NetworkPolicies is used to access network policies endpoints.
+type NetworkPolicies struct {
+	client *Client
+}
+
+// NetworkPolicies returns a handle on the network policies endpoints.
+func (c *Client) NetworkPolicies() *NetworkPolicies {
+	return &NetworkPolicies{client: c}
+}
+
+// List is used to list the network policies of a namespace.
+func (n *NetworkPolicies) List(q *QueryOptions) ([]*NetworkPolicy, *QueryMeta, error) {
+	var resp []*NetworkPolicy
+	qm, err := n.client.query("/v1/network-policies", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list network policies that match a given prefix.
+func (n *NetworkPolicies) PrefixList(prefix string, q *QueryOptions) ([]*NetworkPolicy, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return n.List(q)
+}
+
+// Info is used to fetch details of a specific network policy.
+func (n *NetworkPolicies) Info(name string, q *QueryOptions) (*NetworkPolicy, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, errors.New("missing network policy name")
+	}
+
+	var resp NetworkPolicy
+	qm, err := n.client.query("/v1/network-policy/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create or update a network policy.
+func (n *NetworkPolicies) Register(policy *NetworkPolicy, w *WriteOptions) (*WriteMeta, error) {
+	if policy == nil {
+		return nil, errors.New("missing network policy")
+	}
+	if policy.Name == "" {
+		return nil, errors.New("missing network policy name")
+	}
+
+	wm, err := n.client.put("/v1/network-policy/"+url.PathEscape(policy.Name), policy, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a network policy.
+func (n *NetworkPolicies) Delete(name string, w *WriteOptions) (*WriteMeta, error) {
+	if name == "" {
+		return nil, errors.New("missing network policy name")
+	}
+
+	wm, err := n.client.delete("/v1/network-policy/"+url.PathEscape(name), nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// NetworkPolicy is used to serialize a network policy.
+type NetworkPolicy struct {
+	Name        string
+	Namespace   string
+	Description string
+	Selector    *NetworkPolicySelector
+	Ingress     []*NetworkPolicyIngressRule
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NetworkPolicySelector is used to serialize the selector of allocations of
+// a network policy or of an ingress rule.
+type NetworkPolicySelector struct {
+	Namespace string
+	Job       string
+	Group     string
+	Meta      map[string]string
+}
+
+// NetworkPolicyIngressRule is used to serialize a rule allowing connections
+// to the allocations selected by a network policy.
+type NetworkPolicyIngressRule struct {
+	From     []*NetworkPolicySelector
+	Ports    []int
+	Protocol string
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestNetworkPolicies_CRUD(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	policies := c.NetworkPolicies()
+
+	// Create a network policy.
+	policy := &NetworkPolicy{
+		Name:        "db",
+		Description: "only api may connect to db",
+		Selector:    &NetworkPolicySelector{Job: "db"},
+		Ingress: []*NetworkPolicyIngressRule{{
+			From:     []*NetworkPolicySelector{{Job: "api"}},
+			Ports:    []int{5432},
+			Protocol: "tcp",
+		}},
+	}
+	wm, err := policies.Register(policy, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// Read it back.
+	resp, qm, err := policies.Info(policy.Name, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+	must.Eq(t, policy.Name, resp.Name)
+	must.Eq(t, "default", resp.Namespace)
+	must.Eq(t, policy.Ingress, resp.Ingress)
+
+	// List and prefix list.
+	list, _, err := policies.List(nil)
+	must.NoError(t, err)
+	must.Len(t, 1, list)
+
+	list, _, err = policies.PrefixList("x", nil)
+	must.NoError(t, err)
+	must.Len(t, 0, list)
+
+	// Delete the policy.
+	wm, err = policies.Delete(policy.Name, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = policies.Info(policy.Name, nil)
+	must.ErrorContains(t, err, "not found")
+}
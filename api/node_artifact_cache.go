@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+// ArtifactCacheEntry describes an artifact in the artifact cache of a Node.
+type ArtifactCacheEntry struct {
+	// Key identifies the content of the artifact
+	Key string
+
+	// Source is the source the artifact was downloaded from, without its
+	// query string
+	Source string
+
+	// Name is the file name of the artifact
+	Name string
+
+	// Size is the size of the artifact in bytes
+	Size int64
+
+	// References is the number of tasks currently using the artifact
+	References int
+
+	CreateTime  int64
+	LastUseTime int64
+}
+
+// ArtifactCacheResponse contains the artifacts in the artifact cache of a
+// Node.
+type ArtifactCacheResponse struct {
+	// Entries are the cached artifacts, most recently used first
+	Entries []*ArtifactCacheEntry
+
+	// Size is the total size of the cached artifacts in bytes
+	Size int64
+
+	// MaxSize is the size in bytes beyond which artifacts are evicted
+	MaxSize int64
+}
+
+// ArtifactCachePurgeRequest contains the artifacts to remove from the
+// artifact cache of a Node.
+type ArtifactCachePurgeRequest struct {
+	NodeID string
+
+	// Keys are the keys of the artifacts to remove. Every artifact is
+	// removed if empty.
+	Keys []string
+}
+
+// ArtifactCachePurgeResponse lists the artifacts affected by a purge.
+type ArtifactCachePurgeResponse struct {
+	// Purged are the keys of the removed artifacts
+	Purged []string
+
+	// InUse are the keys of the artifacts that were not removed because
+	// tasks are using them
+	InUse []string
+
+	// NotFound are the requested keys that are not in the cache
+	NotFound []string
+}
+
+// NodeArtifactCache is a client for the artifact cache of Nodes.
+type NodeArtifactCache struct {
+	client *Client
+}
+
+// ArtifactCache returns a NodeArtifactCache client.
+func (n *Nodes) ArtifactCache() *NodeArtifactCache {
+	return &NodeArtifactCache{client: n.client}
+}
+
+// List the artifacts in the artifact cache of a Node. If nodeID is empty
+// then the artifact cache of the Node receiving the request is returned.
+func (n *NodeArtifactCache) List(nodeID string, qo *QueryOptions) (*ArtifactCacheResponse, error) {
+	if qo == nil {
+		qo = &QueryOptions{}
+	}
+
+	if qo.Params == nil {
+		qo.Params = make(map[string]string)
+	}
+
+	if nodeID != "" {
+		qo.Params["node_id"] = nodeID
+	}
+
+	var out ArtifactCacheResponse
+	_, err := n.client.query("/v1/client/artifact-cache", &out, qo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Purge removes artifacts from the artifact cache of a Node. Artifacts in use
+// by tasks are not removed. If NodeID is unset then the Node receiving the
+// request is purged.
+func (n *NodeArtifactCache) Purge(req *ArtifactCachePurgeRequest, qo *QueryOptions) (*ArtifactCachePurgeResponse, error) {
+	var out ArtifactCachePurgeResponse
+	_, err := n.client.putQuery("/v1/client/artifact-cache/purge", req, &out, qo)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -568,6 +568,7 @@ type Node struct {
 	CSIControllerPlugins  map[string]*CSIInfo
 	CSINodePlugins        map[string]*CSIInfo
 	LastDrain             *DrainMetadata
+	PrefetchedImages      []string
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
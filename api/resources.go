@@ -144,17 +144,24 @@ type DNSConfig struct {
 	Options  []string `mapstructure:"options" hcl:"options,optional"`
 }
 
+// NetworkBandwidth is used to limit the bandwidth of a group network.
+type NetworkBandwidth struct {
+	IngressMBits int `mapstructure:"ingress_mbits" hcl:"ingress_mbits,optional"`
+	EgressMBits  int `mapstructure:"egress_mbits" hcl:"egress_mbits,optional"`
+}
+
 // NetworkResource is used to describe required network
 // resources of a given task.
 type NetworkResource struct {
-	Mode          string     `hcl:"mode,optional"`
-	Device        string     `hcl:"device,optional"`
-	CIDR          string     `hcl:"cidr,optional"`
-	IP            string     `hcl:"ip,optional"`
-	DNS           *DNSConfig `hcl:"dns,block"`
-	ReservedPorts []Port     `hcl:"reserved_ports,block"`
-	DynamicPorts  []Port     `hcl:"port,block"`
-	Hostname      string     `hcl:"hostname,optional"`
+	Mode          string            `hcl:"mode,optional"`
+	Device        string            `hcl:"device,optional"`
+	CIDR          string            `hcl:"cidr,optional"`
+	IP            string            `hcl:"ip,optional"`
+	DNS           *DNSConfig        `hcl:"dns,block"`
+	Bandwidth     *NetworkBandwidth `hcl:"bandwidth,block"`
+	ReservedPorts []Port            `hcl:"reserved_ports,block"`
+	DynamicPorts  []Port            `hcl:"port,block"`
+	Hostname      string            `hcl:"hostname,optional"`
 
 	// COMPAT(0.13)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
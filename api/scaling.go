@@ -120,3 +120,12 @@ type ScalingEvent struct {
 	Time          uint64
 	CreateIndex   uint64
 }
+
+// JobScalingEvents is the set of recent scaling events for a job, indexed by
+// task group.
+type JobScalingEvents struct {
+	Namespace     string
+	JobID         string
+	ScalingEvents map[string][]*ScalingEvent
+	ModifyIndex   uint64
+}
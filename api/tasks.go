@@ -449,6 +449,48 @@ func (m *MigrateStrategy) Copy() *MigrateStrategy {
 	return nm
 }
 
+// TemplateRestartPolicy describes how the restarts triggered by templates with
+// change_mode "restart" are rolled across the allocations of a task group.
+type TemplateRestartPolicy struct {
+	MaxParallel     *int           `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
+	Stagger         *time.Duration `mapstructure:"stagger" hcl:"stagger,optional"`
+	HealthCheck     *string        `mapstructure:"health_check" hcl:"health_check,optional"`
+	MinHealthyTime  *time.Duration `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
+	HealthyDeadline *time.Duration `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
+}
+
+func DefaultTemplateRestartPolicy() *TemplateRestartPolicy {
+	return &TemplateRestartPolicy{
+		MaxParallel:     pointerOf(1),
+		Stagger:         pointerOf(30 * time.Second),
+		HealthCheck:     pointerOf("checks"),
+		MinHealthyTime:  pointerOf(10 * time.Second),
+		HealthyDeadline: pointerOf(5 * time.Minute),
+	}
+}
+
+func (p *TemplateRestartPolicy) Canonicalize() {
+	if p == nil {
+		return
+	}
+	defaults := DefaultTemplateRestartPolicy()
+	if p.MaxParallel == nil {
+		p.MaxParallel = defaults.MaxParallel
+	}
+	if p.Stagger == nil {
+		p.Stagger = defaults.Stagger
+	}
+	if p.HealthCheck == nil {
+		p.HealthCheck = defaults.HealthCheck
+	}
+	if p.MinHealthyTime == nil {
+		p.MinHealthyTime = defaults.MinHealthyTime
+	}
+	if p.HealthyDeadline == nil {
+		p.HealthyDeadline = defaults.HealthyDeadline
+	}
+}
+
 // VolumeRequest is a representation of a storage volume that a TaskGroup wishes to use.
 type VolumeRequest struct {
 	Name           string           `hcl:"name,label"`
@@ -507,6 +549,7 @@ type TaskGroup struct {
 	EphemeralDisk    *EphemeralDisk            `hcl:"ephemeral_disk,block"`
 	Update           *UpdateStrategy           `hcl:"update,block"`
 	Migrate          *MigrateStrategy          `hcl:"migrate,block"`
+	TemplateRestart  *TemplateRestartPolicy    `hcl:"template_restart,block"`
 	Networks         []*NetworkResource        `hcl:"network,block"`
 	Meta             map[string]string         `hcl:"meta,block"`
 	Services         []*Service                `hcl:"service,block"`
@@ -609,6 +652,10 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 		g.Migrate.Canonicalize()
 	}
 
+	if g.TemplateRestart != nil {
+		g.TemplateRestart.Canonicalize()
+	}
+
 	var defaultRestartPolicy *RestartPolicy
 	switch *job.Type {
 	case "service", "system":
@@ -783,7 +830,9 @@ type Task struct {
 	Artifacts       []*TaskArtifact        `hcl:"artifact,block"`
 	Vault           *Vault                 `hcl:"vault,block"`
 	Consul          *Consul                `hcl:"consul,block"`
+	Nomad           *Nomad                 `hcl:"nomad,block"`
 	Templates       []*Template            `hcl:"template,block"`
+	Secrets         []*Secret              `hcl:"secret,block"`
 	DispatchPayload *DispatchPayloadConfig `hcl:"dispatch_payload,block"`
 	VolumeMounts    []*VolumeMount         `hcl:"volume_mount,block"`
 	CSIPluginConfig *TaskCSIPluginConfig   `mapstructure:"csi_plugin" json:",omitempty" hcl:"csi_plugin,block"`
@@ -828,9 +877,15 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	if t.Consul != nil {
 		t.Consul.Canonicalize()
 	}
+	if t.Nomad != nil {
+		t.Nomad.Canonicalize()
+	}
 	for _, tmpl := range t.Templates {
 		tmpl.Canonicalize()
 	}
+	for _, secret := range t.Secrets {
+		secret.Canonicalize()
+	}
 	for _, s := range t.Services {
 		s.Canonicalize(t, tg, job)
 	}
@@ -858,12 +913,30 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 
 // TaskArtifact is used to download artifacts before running a task.
 type TaskArtifact struct {
-	GetterSource   *string           `mapstructure:"source" hcl:"source,optional"`
-	GetterOptions  map[string]string `mapstructure:"options" hcl:"options,block"`
-	GetterHeaders  map[string]string `mapstructure:"headers" hcl:"headers,block"`
-	GetterMode     *string           `mapstructure:"mode" hcl:"mode,optional"`
-	GetterInsecure *bool             `mapstructure:"insecure" hcl:"insecure,optional"`
-	RelativeDest   *string           `mapstructure:"destination" hcl:"destination,optional"`
+	GetterSource   *string                `mapstructure:"source" hcl:"source,optional"`
+	GetterOptions  map[string]string      `mapstructure:"options" hcl:"options,block"`
+	GetterHeaders  map[string]string      `mapstructure:"headers" hcl:"headers,block"`
+	GetterMode     *string                `mapstructure:"mode" hcl:"mode,optional"`
+	GetterInsecure *bool                  `mapstructure:"insecure" hcl:"insecure,optional"`
+	RelativeDest   *string                `mapstructure:"destination" hcl:"destination,optional"`
+	Signature      *TaskArtifactSignature `mapstructure:"signature" hcl:"signature,block"`
+}
+
+// TaskArtifactSignature is the detached signature of an artifact, verified
+// by the client against its trusted public keys.
+type TaskArtifactSignature struct {
+	Source *string  `hcl:"source,optional"`
+	Format *string  `hcl:"format,optional"`
+	Keys   []string `hcl:"keys,optional"`
+}
+
+func (s *TaskArtifactSignature) Canonicalize() {
+	if s.Source == nil {
+		s.Source = pointerOf("")
+	}
+	if s.Format == nil {
+		s.Format = pointerOf("minisign")
+	}
 }
 
 func (a *TaskArtifact) Canonicalize() {
@@ -883,6 +956,9 @@ func (a *TaskArtifact) Canonicalize() {
 	if len(a.GetterHeaders) == 0 {
 		a.GetterHeaders = nil
 	}
+	if a.Signature != nil {
+		a.Signature.Canonicalize()
+	}
 	if a.RelativeDest == nil {
 		switch *a.GetterMode {
 		case "file":
@@ -1007,6 +1083,47 @@ func (tmpl *Template) Canonicalize() {
 	}
 }
 
+// Secret delivers the items of a Nomad Variable or Vault secret to a task as
+// files in its secrets directory or as environment variables.
+type Secret struct {
+	Name         string  `hcl:"name,label"`
+	Provider     *string `mapstructure:"provider" hcl:"provider,optional"`
+	Path         *string `mapstructure:"path" hcl:"path"`
+	Env          *bool   `mapstructure:"env" hcl:"env,optional"`
+	ChangeMode   *string `mapstructure:"change_mode" hcl:"change_mode,optional"`
+	ChangeSignal *string `mapstructure:"change_signal" hcl:"change_signal,optional"`
+	Perms        *string `mapstructure:"perms" hcl:"perms,optional"`
+	Uid          *int    `mapstructure:"uid" hcl:"uid,optional"`
+	Gid          *int    `mapstructure:"gid" hcl:"gid,optional"`
+}
+
+func (s *Secret) Canonicalize() {
+	if s.Provider == nil {
+		s.Provider = pointerOf("nomad")
+	}
+	if s.Path == nil {
+		s.Path = pointerOf("")
+	}
+	if s.Env == nil {
+		s.Env = pointerOf(false)
+	}
+	if s.ChangeMode == nil {
+		s.ChangeMode = pointerOf("restart")
+	}
+	if s.ChangeSignal == nil {
+		if *s.ChangeMode == "signal" {
+			s.ChangeSignal = pointerOf("SIGHUP")
+		} else {
+			s.ChangeSignal = pointerOf("")
+		}
+	} else {
+		s.ChangeSignal = pointerOf(strings.ToUpper(*s.ChangeSignal))
+	}
+	if s.Perms == nil {
+		s.Perms = pointerOf("0600")
+	}
+}
+
 type Vault struct {
 	Policies             []string `hcl:"policies,optional"`
 	Role                 string   `hcl:"role,optional"`
@@ -1043,6 +1160,35 @@ func (v *Vault) Canonicalize() {
 	}
 }
 
+// Nomad requests a short-lived Nomad ACL token for a task, linked to the given
+// ACL roles.
+type Nomad struct {
+	Roles        []string       `hcl:"roles,optional"`
+	TTL          *time.Duration `mapstructure:"ttl" hcl:"ttl,optional"`
+	Env          *bool          `hcl:"env,optional"`
+	DisableFile  *bool          `mapstructure:"disable_file" hcl:"disable_file,optional"`
+	ChangeMode   *string        `mapstructure:"change_mode" hcl:"change_mode,optional"`
+	ChangeSignal *string        `mapstructure:"change_signal" hcl:"change_signal,optional"`
+}
+
+func (n *Nomad) Canonicalize() {
+	if n.TTL == nil {
+		n.TTL = pointerOf(time.Hour)
+	}
+	if n.Env == nil {
+		n.Env = pointerOf(true)
+	}
+	if n.DisableFile == nil {
+		n.DisableFile = pointerOf(false)
+	}
+	if n.ChangeMode == nil {
+		n.ChangeMode = pointerOf("restart")
+	}
+	if n.ChangeSignal == nil {
+		n.ChangeSignal = pointerOf("SIGHUP")
+	}
+}
+
 // NewTask creates and initializes a new Task.
 func NewTask(name, driver string) *Task {
 	return &Task{
@@ -1124,26 +1270,27 @@ type TaskHandle struct {
 }
 
 const (
-	TaskSetup                  = "Task Setup"
-	TaskSetupFailure           = "Setup Failure"
-	TaskDriverFailure          = "Driver Failure"
-	TaskDriverMessage          = "Driver"
-	TaskReceived               = "Received"
-	TaskFailedValidation       = "Failed Validation"
-	TaskStarted                = "Started"
-	TaskTerminated             = "Terminated"
-	TaskKilling                = "Killing"
-	TaskKilled                 = "Killed"
-	TaskRestarting             = "Restarting"
-	TaskNotRestarting          = "Not Restarting"
-	TaskDownloadingArtifacts   = "Downloading Artifacts"
-	TaskArtifactDownloadFailed = "Failed Artifact Download"
-	TaskSiblingFailed          = "Sibling Task Failed"
-	TaskSignaling              = "Signaling"
-	TaskRestartSignal          = "Restart Signaled"
-	TaskLeaderDead             = "Leader Task Dead"
-	TaskBuildingTaskDir        = "Building Task Directory"
-	TaskClientReconnected      = "Reconnected"
+	TaskSetup                      = "Task Setup"
+	TaskSetupFailure               = "Setup Failure"
+	TaskDriverFailure              = "Driver Failure"
+	TaskDriverMessage              = "Driver"
+	TaskReceived                   = "Received"
+	TaskFailedValidation           = "Failed Validation"
+	TaskStarted                    = "Started"
+	TaskTerminated                 = "Terminated"
+	TaskKilling                    = "Killing"
+	TaskKilled                     = "Killed"
+	TaskRestarting                 = "Restarting"
+	TaskNotRestarting              = "Not Restarting"
+	TaskDownloadingArtifacts       = "Downloading Artifacts"
+	TaskArtifactDownloadFailed     = "Failed Artifact Download"
+	TaskArtifactVerificationFailed = "Failed Artifact Verification"
+	TaskSiblingFailed              = "Sibling Task Failed"
+	TaskSignaling                  = "Signaling"
+	TaskRestartSignal              = "Restart Signaled"
+	TaskLeaderDead                 = "Leader Task Dead"
+	TaskBuildingTaskDir            = "Building Task Directory"
+	TaskClientReconnected          = "Reconnected"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -1248,6 +1395,7 @@ type WorkloadIdentity struct {
 	File         bool          `hcl:"file,optional"`
 	ServiceName  string        `hcl:"service_name,optional"`
 	TTL          time.Duration `mapstructure:"ttl" hcl:"ttl,optional"`
+	X509         bool          `mapstructure:"x509" hcl:"x509,optional"`
 }
 
 type Action struct {
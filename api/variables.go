@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -144,6 +146,34 @@ func (vars *Variables) List(qo *QueryOptions) ([]*VariableMetadata, *QueryMeta,
 	return resp, qm, nil
 }
 
+// ListVersions is used to list the metadata of the current and previous
+// versions of a variable, with the highest version first.
+func (vars *Variables) ListVersions(path string, qo *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	path = cleanPathString(path)
+	var resp []*VariableMetadata
+	qm, err := vars.client.query("/v1/var/"+path+"?versions", &resp, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// ReadVersion is used to query a specific version of a variable, which may be
+// the current version or a previous one. This will error if the version is
+// not found.
+func (vars *Variables) ReadVersion(path string, version uint64, qo *QueryOptions) (*Variable, *QueryMeta, error) {
+	path = cleanPathString(path)
+	var v = new(Variable)
+	qm, err := vars.readInternal("/v1/var/"+path+"?version="+strconv.FormatUint(version, 10), &v, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v == nil {
+		return nil, qm, ErrVariablePathNotFound
+	}
+	return v, qm, nil
+}
+
 // PrefixList is used to do a prefix List search over variables.
 func (vars *Variables) PrefixList(prefix string, qo *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
 	if qo == nil {
@@ -383,6 +413,17 @@ type Variable struct {
 	// Path is the path to the variable
 	Path string `hcl:"path"`
 
+	// Version is incremented each time the contents of the variable change
+	Version uint64 `hcl:"version"`
+
+	// TTL is the optional duration after which the variable expires and is
+	// deleted, measured from the last time the variable was written
+	TTL time.Duration `hcl:"ttl,optional"`
+
+	// ExpireTime is the unix nano of the time the variable expires, or zero if
+	// the variable doesn't expire
+	ExpireTime int64 `hcl:"expire_time"`
+
 	// CreateIndex tracks the index of creation time
 	CreateIndex uint64 `hcl:"create_index"`
 
@@ -411,6 +452,17 @@ type VariableMetadata struct {
 	// Path is the path to the variable
 	Path string `hcl:"path"`
 
+	// Version is incremented each time the contents of the variable change
+	Version uint64 `hcl:"version"`
+
+	// TTL is the optional duration after which the variable expires and is
+	// deleted, measured from the last time the variable was written
+	TTL time.Duration `hcl:"ttl,optional"`
+
+	// ExpireTime is the unix nano of the time the variable expires, or zero if
+	// the variable doesn't expire
+	ExpireTime int64 `hcl:"expire_time"`
+
 	// CreateIndex tracks the index of creation time
 	CreateIndex uint64 `hcl:"create_index"`
 
@@ -471,6 +523,9 @@ func (v *Variable) Metadata() *VariableMetadata {
 	return &VariableMetadata{
 		Namespace:   v.Namespace,
 		Path:        v.Path,
+		Version:     v.Version,
+		TTL:         v.TTL,
+		ExpireTime:  v.ExpireTime,
 		CreateIndex: v.CreateIndex,
 		ModifyIndex: v.ModifyIndex,
 		CreateTime:  v.CreateTime,
@@ -64,6 +64,15 @@ func (c *Client) ResolveToken(bearerToken string) (*acl.ACL, error) {
 	return a, err
 }
 
+// ResolveIdentity is used to translate an ACL Token Secret ID or workload
+// identity into the identity it authenticates, or an error.
+func (c *Client) ResolveIdentity(bearerToken string) (*structs.AuthenticatedIdentity, error) {
+	if !c.GetConfig().ACLEnabled {
+		return &structs.AuthenticatedIdentity{ACLToken: structs.ACLsDisabledToken}, nil
+	}
+	return c.resolveTokenValue(bearerToken)
+}
+
 func (c *Client) resolveTokenAndACL(bearerToken string) (*acl.ACL, *structs.AuthenticatedIdentity, error) {
 	// Fast-path if ACLs are disabled
 	if !c.GetConfig().ACLEnabled {
@@ -188,6 +188,10 @@ type allocRunner struct {
 	shutdownDelayCtx      context.Context
 	shutdownDelayCancelFn context.CancelFunc
 
+	// templateRestartHook coordinates the restarts triggered by the templates
+	// of the tasks with the other allocations of the task group.
+	templateRestartHook *templateRestartHook
+
 	// rpcClient is the RPC Client that should be used by the allocrunner and its
 	// hooks to communicate with Nomad Servers.
 	rpcClient config.RPCer
@@ -338,6 +342,8 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			AllocHookResources:  ar.hookResources,
 			WIDMgr:              ar.widmgr,
 			Users:               ar.users,
+			RPCClient:           ar.rpcClient,
+			TemplateRestarter:   ar.templateRestartHook,
 		}
 
 		// Create, but do not Run, the task runner
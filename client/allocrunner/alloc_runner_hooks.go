@@ -122,6 +122,20 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 	// directory path exists for other hooks.
 	alloc := ar.Alloc()
 
+	// Create the template restart hook, which is given to the task runners
+	// to coordinate the restarts triggered by their templates.
+	ar.templateRestartHook = newTemplateRestartHook(templateRestartHookConfig{
+		alloc:                 alloc,
+		rpcClient:             ar.rpcClient,
+		region:                config.Region,
+		nodeSecret:            config.Node.SecretID,
+		listenerFn:            ar.Listener,
+		taskEnvBuilderFactory: newEnvBuilder,
+		consul:                ar.consulServicesHandler,
+		checkStore:            ar.checkStore,
+		logger:                hookLogger,
+	})
+
 	ar.runnerHooks = []interfaces.RunnerHook{
 		newIdentityHook(hookLogger, ar.widmgr),
 		newAllocDirHook(hookLogger, ar.allocDir),
@@ -158,6 +172,7 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		newCSIHook(alloc, hookLogger, ar.csiManager, ar.rpcClient, ar, ar.hookResources, ar.clientConfig.Node.SecretID),
 		newChecksHook(hookLogger, alloc, ar.checkStore, ar, builtTaskEnv),
 	}
+	ar.runnerHooks = append(ar.runnerHooks, ar.templateRestartHook)
 	if config.ExtraAllocHooks != nil {
 		ar.runnerHooks = append(ar.runnerHooks, config.ExtraAllocHooks...)
 	}
@@ -188,18 +188,28 @@ func newNetworkConfigurator(log hclog.Logger, alloc *structs.Allocation, config
 		ignorePortMappingHostIP = false
 	}
 
+	// Networks following the first one in groups with multiple networks are
+	// attached by the configurator of the first network.
+	additionalNetworks := tg.Networks[1:]
+
 	switch {
 	case netMode == "bridge":
 		c, err := newBridgeNetworkConfigurator(log, alloc, config.BridgeNetworkName, config.BridgeNetworkAllocSubnet, config.BridgeNetworkHairpinMode, config.CNIPath, ignorePortMappingHostIP, config.Node)
 		if err != nil {
 			return nil, err
 		}
+		if err := c.cni.addNetworks(config.CNIConfigDir, additionalNetworks, config.Node); err != nil {
+			return nil, err
+		}
 		return &synchronizedNetworkConfigurator{c}, nil
 	case strings.HasPrefix(netMode, "cni/"):
 		c, err := newCNINetworkConfigurator(log, config.CNIPath, config.CNIInterfacePrefix, config.CNIConfigDir, netMode[4:], ignorePortMappingHostIP, config.Node)
 		if err != nil {
 			return nil, err
 		}
+		if err := c.addNetworks(config.CNIConfigDir, additionalNetworks, config.Node); err != nil {
+			return nil, err
+		}
 		return &synchronizedNetworkConfigurator{c}, nil
 	default:
 		return &hostNetworkConfigurator{}, nil
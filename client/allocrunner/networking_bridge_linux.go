@@ -59,18 +59,19 @@ func newBridgeNetworkConfigurator(log hclog.Logger, alloc *structs.Allocation, b
 		b.allocSubnet = defaultNomadAllocSubnet
 	}
 
-	var netCfg []byte
+	var withConsulCNI, withBandwidth bool
 
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	for _, svc := range tg.Services {
 		if svc.Connect.HasTransparentProxy() {
-			netCfg = buildNomadBridgeNetConfig(*b, true)
+			withConsulCNI = true
 			break
 		}
 	}
-	if netCfg == nil {
-		netCfg = buildNomadBridgeNetConfig(*b, false)
+	if len(tg.Networks) > 0 && !tg.Networks[0].Bandwidth.IsZero() {
+		withBandwidth = true
 	}
+	netCfg := buildNomadBridgeNetConfig(*b, withConsulCNI, withBandwidth)
 
 	c, err := newCNINetworkConfiguratorWithConf(log, cniPath, bridgeNetworkAllocIfPrefix, ignorePortMappingHostIP, netCfg, node)
 	if err != nil {
@@ -152,17 +153,23 @@ func (b *bridgeNetworkConfigurator) Teardown(ctx context.Context, alloc *structs
 	return b.cni.Teardown(ctx, alloc, spec)
 }
 
-func buildNomadBridgeNetConfig(b bridgeNetworkConfigurator, withConsulCNI bool) []byte {
+func buildNomadBridgeNetConfig(b bridgeNetworkConfigurator, withConsulCNI, withBandwidth bool) []byte {
 	var consulCNI string
 	if withConsulCNI {
 		consulCNI = consulCNIBlock
 	}
 
+	var bandwidth string
+	if withBandwidth {
+		bandwidth = bandwidthCNIBlock
+	}
+
 	return []byte(fmt.Sprintf(nomadCNIConfigTemplate,
 		b.bridgeName,
 		b.hairpinMode,
 		b.allocSubnet,
 		cniAdminChainName,
+		bandwidth,
 		consulCNI,
 	))
 }
@@ -207,11 +214,17 @@ const nomadCNIConfigTemplate = `{
 			"type": "portmap",
 			"capabilities": {"portMappings": true},
 			"snat": true
-		}%s
+		}%s%s
 	]
 }
 `
 
+const bandwidthCNIBlock = `,
+		{
+			"type": "bandwidth",
+			"capabilities": {"bandwidth": true}
+		}`
+
 const consulCNIBlock = `,
 		{
 			"type": "consul-cni",
@@ -52,9 +52,16 @@ const (
 type cniNetworkConfigurator struct {
 	cni                     cni.CNI
 	cniConf                 []byte
+	ifPrefix                string
 	ignorePortMappingHostIP bool
-	nodeAttrs               map[string]string
-	nodeMeta                map[string]string
+
+	// additionalConfs are the CNI configurations of the networks following
+	// the first one in groups with multiple networks. They are attached in
+	// order after the first network.
+	additionalConfs [][]byte
+
+	nodeAttrs map[string]string
+	nodeMeta  map[string]string
 
 	rand   *rand.Rand
 	logger log.Logger
@@ -87,6 +94,7 @@ func newCNINetworkConfiguratorWithConf(logger log.Logger, cniPath, cniInterfaceP
 	if cniInterfacePrefix == "" {
 		cniInterfacePrefix = defaultCNIInterfacePrefix
 	}
+	conf.ifPrefix = cniInterfacePrefix
 
 	c, err := cni.New(cni.WithPluginDir(filepath.SplitList(cniPath)),
 		cni.WithInterfacePrefix(cniInterfacePrefix))
@@ -98,6 +106,74 @@ func newCNINetworkConfiguratorWithConf(logger log.Logger, cniPath, cniInterfaceP
 	return conf, nil
 }
 
+// addNetworks loads the CNI configuration of the given networks so they are
+// attached to the allocation after the first network. Networks using a
+// cni/<name> mode load the configuration of the CNI network, and networks
+// using a host/<name> mode attach an interface on the device of the node's
+// host network.
+func (c *cniNetworkConfigurator) addNetworks(cniConfDir string, networks structs.Networks, node *structs.Node) error {
+	for _, network := range networks {
+		if hostNetwork, ok := network.AttachedHostNetwork(); ok {
+			conf, err := buildHostNetworkConfig(node, hostNetwork)
+			if err != nil {
+				return err
+			}
+			c.additionalConfs = append(c.additionalConfs, conf)
+			continue
+		}
+
+		name, ok := strings.CutPrefix(strings.ToLower(network.Mode), "cni/")
+		if !ok {
+			return fmt.Errorf("unsupported mode %q for additional network", network.Mode)
+		}
+		conf, err := loadCNIConf(cniConfDir, name)
+		if err != nil {
+			return fmt.Errorf("failed to load CNI config for network %q: %v", name, err)
+		}
+		c.additionalConfs = append(c.additionalConfs, conf)
+	}
+	return nil
+}
+
+// buildHostNetworkConfig returns the CNI configuration attaching a macvlan
+// interface on the device of the host network to the allocation. The address
+// of the interface is requested from the DHCP server of the host network.
+func buildHostNetworkConfig(node *structs.Node, hostNetwork string) ([]byte, error) {
+	var device string
+	if node != nil && node.NodeResources != nil {
+		for _, net := range node.NodeResources.NodeNetworks {
+			if net.HasAlias(hostNetwork) {
+				device = net.Device
+				break
+			}
+		}
+	}
+	if device == "" {
+		return nil, fmt.Errorf("failed to find interface of host network %q", hostNetwork)
+	}
+
+	return []byte(fmt.Sprintf(hostNetworkCNIConfigTemplate, "nomad-host-"+hostNetwork, device)), nil
+}
+
+// Update website/content/docs/job-specification/network.mdx when the host
+// network configuration is modified. If CNI plugins are added, add a new
+// constraint to nomad/job_endpoint_hooks.go
+const hostNetworkCNIConfigTemplate = `{
+	"cniVersion": "0.4.0",
+	"name": %q,
+	"plugins": [
+		{
+			"type": "macvlan",
+			"master": %q,
+			"mode": "bridge",
+			"ipam": {
+				"type": "dhcp"
+			}
+		}
+	]
+}
+`
+
 const (
 	ConsulIPTablesConfigEnvVar = "CONSUL_IPTABLES_CONFIG"
 )
@@ -128,6 +204,14 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 		cniArgs[ConsulIPTablesConfigEnvVar] = string(iptablesCfg)
 	}
 
+	opts := []cni.NamespaceOpts{
+		cni.WithCapabilityPortMap(portMaps.ports),
+		cni.WithLabels(cniArgs), // "labels" turn into CNI_ARGS
+	}
+	if bw := getBandwidth(alloc); bw != nil {
+		opts = append(opts, cni.WithCapabilityBandWidth(*bw))
+	}
+
 	// Depending on the version of bridge cni plugin used, a known race could occure
 	// where two alloc attempt to create the nomad bridge at the same time, resulting
 	// in one of them to fail. This rety attempts to overcome those erroneous failures.
@@ -136,10 +220,13 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 	var res *cni.Result
 	for attempt := 1; ; attempt++ {
 		var err error
-		if res, err = c.cni.Setup(ctx, alloc.ID, spec.Path,
-			cni.WithCapabilityPortMap(portMaps.ports),
-			cni.WithLabels(cniArgs), // "labels" turn into CNI_ARGS
-		); err != nil {
+		setup := c.cni.Setup
+		if len(c.additionalConfs) > 0 {
+			// Attach multiple networks in the order of the group network
+			// blocks so interface names match their index.
+			setup = c.cni.SetupSerially
+		}
+		if res, err = setup(ctx, alloc.ID, spec.Path, opts...); err != nil {
 			c.logger.Warn("failed to configure network", "error", err, "attempt", attempt)
 			switch attempt {
 			case 1:
@@ -178,9 +265,39 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 		allocNet.DNS.Servers = []string{tproxyArgs.ConsulDNSIP}
 	}
 
+	if len(c.additionalConfs) > 0 {
+		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+		allocNet.Interfaces = c.cniToInterfaceStatuses(res, tg.Networks)
+	}
+
 	return allocNet, nil
 }
 
+// cniToInterfaceStatuses returns the status of each network of a group with
+// multiple networks. Networks are attached in order, so the interface of each
+// network is named after its index.
+func (c *cniNetworkConfigurator) cniToInterfaceStatuses(res *cni.Result, networks structs.Networks) []*structs.AllocNetworkInterfaceStatus {
+	statuses := make([]*structs.AllocNetworkInterfaceStatus, 0, len(networks))
+	for i, network := range networks {
+		status := &structs.AllocNetworkInterfaceStatus{
+			Mode:          network.Mode,
+			InterfaceName: c.ifPrefix + strconv.Itoa(i),
+		}
+		for label := range network.PortLabels() {
+			status.PortLabels = append(status.PortLabels, label)
+		}
+		sort.Strings(status.PortLabels)
+
+		if iface := res.Interfaces[status.InterfaceName]; iface != nil && len(iface.IPConfigs) > 0 {
+			status.Address = iface.IPConfigs[0].IP.String()
+		} else {
+			c.logger.Warn("no address found for network interface", "interface", status.InterfaceName, "mode", network.Mode)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 // setupTransparentProxyArgs returns a Consul SDK iptables configuration if the
 // allocation has a transparent_proxy block
 func (c *cniNetworkConfigurator) setupTransparentProxyArgs(alloc *structs.Allocation, spec *drivers.NetworkIsolationSpec, portMaps *portMappings) (*consulIPTables.Config, error) {
@@ -470,7 +587,12 @@ func (c *cniNetworkConfigurator) Teardown(ctx context.Context, alloc *structs.Al
 
 	portMap := getPortMapping(alloc, c.ignorePortMappingHostIP)
 
-	if err := c.cni.Remove(ctx, alloc.ID, spec.Path, cni.WithCapabilityPortMap(portMap.ports)); err != nil {
+	opts := []cni.NamespaceOpts{cni.WithCapabilityPortMap(portMap.ports)}
+	if bw := getBandwidth(alloc); bw != nil {
+		opts = append(opts, cni.WithCapabilityBandWidth(*bw))
+	}
+
+	if err := c.cni.Remove(ctx, alloc.ID, spec.Path, opts...); err != nil {
 		// create a real handle to iptables
 		ipt, iptErr := iptables.New()
 		if iptErr != nil {
@@ -569,7 +691,11 @@ func (c *cniNetworkConfigurator) forceCleanup(ipt IPTables, allocID string) erro
 
 func (c *cniNetworkConfigurator) ensureCNIInitialized() error {
 	if err := c.cni.Status(); cni.IsCNINotInitialized(err) {
-		return c.cni.Load(cni.WithConfListBytes(c.cniConf))
+		opts := []cni.Opt{cni.WithConfListBytes(c.cniConf)}
+		for _, conf := range c.additionalConfs {
+			opts = append(opts, cni.WithConfListBytes(conf))
+		}
+		return c.cni.Load(opts...)
 	} else {
 		return err
 	}
@@ -641,3 +767,27 @@ func getPortMapping(alloc *structs.Allocation, ignoreHostIP bool) *portMappings
 	}
 	return mappings
 }
+
+// getBandwidth builds the bandwidth capability arguments for the bandwidth CNI
+// plugin from the group network, or returns nil if the group network has no
+// bandwidth limits
+func getBandwidth(alloc *structs.Allocation) *cni.BandWidth {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || len(tg.Networks) == 0 || tg.Networks[0].Bandwidth.IsZero() {
+		return nil
+	}
+
+	// The plugin expects rates and bursts in bits per second. The burst
+	// allows for 100ms of traffic at the full rate.
+	bw := tg.Networks[0].Bandwidth
+	out := &cni.BandWidth{}
+	if bw.IngressMBits > 0 {
+		out.IngressRate = uint64(bw.IngressMBits) * 1_000_000
+		out.IngressBurst = out.IngressRate / 10
+	}
+	if bw.EgressMBits > 0 {
+		out.EgressRate = uint64(bw.EgressMBits) * 1_000_000
+		out.EgressBurst = out.EgressRate / 10
+	}
+	return out
+}
@@ -6,11 +6,13 @@
 package allocrunner
 
 import (
+	"encoding/json"
 	"errors"
 	"net"
 	"testing"
 
 	"github.com/containerd/go-cni"
+	cnilibrary "github.com/containernetworking/cni/libcni"
 	"github.com/containernetworking/cni/pkg/types"
 	"github.com/hashicorp/consul/sdk/iptables"
 	"github.com/hashicorp/nomad/ci"
@@ -181,6 +183,102 @@ func TestCNI_cniToAllocNet_Fallback(t *testing.T) {
 	test.Nil(t, allocNet.DNS)
 }
 
+// TestCNI_cniToInterfaceStatuses asserts the status of each network of a
+// group with multiple networks is reported from the interface at its index.
+func TestCNI_cniToInterfaceStatuses(t *testing.T) {
+	ci.Parallel(t)
+
+	cniResult := &cni.Result{
+		Interfaces: map[string]*cni.Config{
+			"nomad": {},
+			"eth0": {
+				IPConfigs: []*cni.IPConfig{{IP: net.IPv4(172, 26, 64, 2)}},
+				Sandbox:   "/var/run/netns/alloc",
+			},
+			"eth1": {
+				IPConfigs: []*cni.IPConfig{{IP: net.IPv4(10, 0, 5, 2)}},
+				Sandbox:   "/var/run/netns/alloc",
+			},
+		},
+	}
+	networks := structs.Networks{
+		{Mode: "bridge", DynamicPorts: []structs.Port{{Label: "http"}}},
+		{Mode: "cni/storage", ReservedPorts: []structs.Port{{Label: "nfs", Value: 2049}}},
+		{Mode: "cni/missing"},
+	}
+
+	c := &cniNetworkConfigurator{
+		ifPrefix: "eth",
+		logger:   testlog.HCLogger(t),
+	}
+	must.Eq(t, []*structs.AllocNetworkInterfaceStatus{
+		{Mode: "bridge", InterfaceName: "eth0", Address: "172.26.64.2", PortLabels: []string{"http"}},
+		{Mode: "cni/storage", InterfaceName: "eth1", Address: "10.0.5.2", PortLabels: []string{"nfs"}},
+		{Mode: "cni/missing", InterfaceName: "eth2"},
+	}, c.cniToInterfaceStatuses(cniResult, networks))
+}
+
+// TestCNI_addNetworks_hostNetwork asserts host networks are attached with a
+// macvlan interface on the device of the node's host network.
+func TestCNI_addNetworks_hostNetwork(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	node.NodeResources.NodeNetworks = []*structs.NodeNetworkResource{
+		{
+			Device:    "eth1",
+			Addresses: []structs.NodeNetworkAddress{{Alias: "storage"}},
+		},
+	}
+
+	c := &cniNetworkConfigurator{logger: testlog.HCLogger(t)}
+	must.NoError(t, c.addNetworks(t.TempDir(), structs.Networks{{Mode: "host/storage"}}, node))
+	must.Len(t, 1, c.additionalConfs)
+
+	confList, err := cnilibrary.ConfListFromBytes(c.additionalConfs[0])
+	must.NoError(t, err)
+	must.Eq(t, "nomad-host-storage", confList.Name)
+	must.Len(t, 1, confList.Plugins)
+	must.Eq(t, "macvlan", confList.Plugins[0].Network.Type)
+
+	var plugin struct {
+		Master string `json:"master"`
+		IPAM   struct {
+			Type string `json:"type"`
+		} `json:"ipam"`
+	}
+	must.NoError(t, json.Unmarshal(confList.Plugins[0].Bytes, &plugin))
+	must.Eq(t, "eth1", plugin.Master)
+	must.Eq(t, "dhcp", plugin.IPAM.Type)
+
+	err = c.addNetworks(t.TempDir(), structs.Networks{{Mode: "host/missing"}}, node)
+	must.ErrorContains(t, err, `failed to find interface of host network "missing"`)
+}
+
+func TestCNI_getBandwidth(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Networks = structs.Networks{{Mode: "bridge"}}
+	must.Nil(t, getBandwidth(alloc))
+
+	alloc.Job.TaskGroups[0].Networks[0].Bandwidth = &structs.NetworkBandwidth{
+		IngressMBits: 100,
+	}
+	must.Eq(t, &cni.BandWidth{
+		IngressRate:  100_000_000,
+		IngressBurst: 10_000_000,
+	}, getBandwidth(alloc))
+
+	alloc.Job.TaskGroups[0].Networks[0].Bandwidth.EgressMBits = 10
+	must.Eq(t, &cni.BandWidth{
+		IngressRate:  100_000_000,
+		IngressBurst: 10_000_000,
+		EgressRate:   10_000_000,
+		EgressBurst:  1_000_000,
+	}, getBandwidth(alloc))
+}
+
 // TestCNI_cniToAllocNet_Invalid asserts an error is returned if a CNI plugin
 // result lacks any IP addresses. This has not been observed, but Nomad still
 // must guard against invalid results from external plugins.
@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	metrics "github.com/armon/go-metrics"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// networkPolicyRule allows connections from a source address to a destination
+// address on the bridge network. An empty protocol allows every protocol and
+// a zero port allows every port of the protocol.
+type networkPolicyRule struct {
+	Source      string
+	Destination string
+	Protocol    string
+	Port        int
+}
+
+// networkPolicyRuleset is the compiled form of the network policies that
+// apply to the allocations of a client.
+type networkPolicyRuleset struct {
+	// Allowed is the set of connections allowed to protected destinations.
+	Allowed []networkPolicyRule
+
+	// Protected is the set of destination addresses selected by at least one
+	// network policy. Connections from the bridge subnet to these addresses
+	// that are not allowed are dropped.
+	Protected []string
+}
+
+// networkPolicyEnforcer applies a compiled ruleset to the host firewall.
+type networkPolicyEnforcer interface {
+	// Enforce replaces the rules currently enforced with the given ruleset.
+	Enforce(*networkPolicyRuleset) error
+
+	// Denied returns the number of connections dropped for each protected
+	// destination address since the ruleset was last enforced.
+	Denied() (map[string]uint64, error)
+}
+
+// networkPolicyAlloc is an allocation connected to the bridge network.
+type networkPolicyAlloc struct {
+	address  string
+	endpoint *structs.NetworkPolicyEndpoint
+}
+
+// NetworkPolicyManager compiles the network policies received from the
+// servers into firewall rules restricting traffic between the allocations of
+// the client on the bridge network, and keeps them up to date as allocations
+// come and go.
+type NetworkPolicyManager struct {
+	logger   hclog.Logger
+	enforcer networkPolicyEnforcer
+
+	mu       sync.Mutex
+	policies []*structs.NetworkPolicy
+	allocs   map[string]*networkPolicyAlloc
+	ruleset  *networkPolicyRuleset
+
+	// denied is the number of denied connections already emitted as metrics
+	// for each protected address since the ruleset was last enforced.
+	denied map[string]uint64
+
+	// labels are the client labels added to the metrics.
+	labels []metrics.Label
+}
+
+// NewNetworkPolicyManager returns a NetworkPolicyManager for the bridge
+// network with the given allocation subnet.
+func NewNetworkPolicyManager(logger hclog.Logger, allocSubnet string) *NetworkPolicyManager {
+	logger = logger.Named("network_policy")
+	return newNetworkPolicyManager(logger, newNetworkPolicyEnforcer(logger, allocSubnet))
+}
+
+func newNetworkPolicyManager(logger hclog.Logger, enforcer networkPolicyEnforcer) *NetworkPolicyManager {
+	return &NetworkPolicyManager{
+		logger:   logger,
+		enforcer: enforcer,
+		allocs:   make(map[string]*networkPolicyAlloc),
+		ruleset:  &networkPolicyRuleset{},
+		denied:   make(map[string]uint64),
+	}
+}
+
+// SetPolicies replaces the set of network policies to enforce.
+func (m *NetworkPolicyManager) SetPolicies(policies []*structs.NetworkPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.policies = policies
+	m.reconcileLocked()
+}
+
+// UpsertAlloc adds or updates an allocation connected to the bridge network
+// with the given address.
+func (m *NetworkPolicyManager) UpsertAlloc(address string, endpoint *structs.NetworkPolicyEndpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.allocs[endpoint.AllocID]; ok && existing.address == address {
+		return
+	}
+	m.allocs[endpoint.AllocID] = &networkPolicyAlloc{
+		address:  address,
+		endpoint: endpoint,
+	}
+	m.reconcileLocked()
+}
+
+// RemoveAlloc removes an allocation that is no longer connected to the
+// bridge network.
+func (m *NetworkPolicyManager) RemoveAlloc(allocID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.allocs[allocID]; !ok {
+		return
+	}
+	delete(m.allocs, allocID)
+	m.reconcileLocked()
+}
+
+// EmitStats emits the number of connections denied to each protected
+// allocation since the last time stats were emitted.
+func (m *NetworkPolicyManager) EmitStats(baseLabels []metrics.Label) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.labels = baseLabels
+	m.emitDeniedLocked()
+}
+
+// reconcileLocked compiles the current policies and allocations and enforces
+// the result if it changed. Must be called with the lock held.
+func (m *NetworkPolicyManager) reconcileLocked() {
+	ruleset := compileNetworkPolicies(m.policies, m.allocs)
+	if slices.Equal(ruleset.Allowed, m.ruleset.Allowed) &&
+		slices.Equal(ruleset.Protected, m.ruleset.Protected) {
+		return
+	}
+
+	// Enforcing a new ruleset resets the denied counters, so emit what has
+	// been counted so far first.
+	m.emitDeniedLocked()
+
+	if err := m.enforcer.Enforce(ruleset); err != nil {
+		m.logger.Error("failed to enforce network policies", "error", err)
+		return
+	}
+	m.ruleset = ruleset
+	m.denied = make(map[string]uint64)
+}
+
+// emitDeniedLocked emits the denied connection counters. Must be called with
+// the lock held.
+func (m *NetworkPolicyManager) emitDeniedLocked() {
+	if len(m.ruleset.Protected) == 0 {
+		return
+	}
+
+	counts, err := m.enforcer.Denied()
+	if err != nil {
+		m.logger.Warn("failed to read network policy counters", "error", err)
+		return
+	}
+
+	byAddress := make(map[string]*structs.NetworkPolicyEndpoint, len(m.allocs))
+	for _, alloc := range m.allocs {
+		byAddress[alloc.address] = alloc.endpoint
+	}
+
+	for address, count := range counts {
+		delta := count - m.denied[address]
+		if count < m.denied[address] {
+			delta = count
+		}
+		m.denied[address] = count
+
+		endpoint, ok := byAddress[address]
+		if !ok || delta == 0 {
+			continue
+		}
+
+		labels := append(slices.Clone(m.labels),
+			metrics.Label{Name: "alloc_id", Value: endpoint.AllocID},
+			metrics.Label{Name: "job", Value: endpoint.JobID},
+			metrics.Label{Name: "namespace", Value: endpoint.Namespace},
+			metrics.Label{Name: "task_group", Value: endpoint.TaskGroup},
+		)
+		metrics.IncrCounterWithLabels(
+			[]string{"client", "allocs", "network_policy", "denied"}, float32(delta), labels)
+	}
+}
+
+// compileNetworkPolicies computes the ruleset enforcing the policies for the
+// given allocations. The result is sorted so that it can be compared with a
+// previous ruleset.
+func compileNetworkPolicies(policies []*structs.NetworkPolicy, allocs map[string]*networkPolicyAlloc) *networkPolicyRuleset {
+	ruleset := &networkPolicyRuleset{}
+
+	for _, dst := range allocs {
+		protected := false
+
+		for _, policy := range policies {
+			if !policy.Selects(dst.endpoint) {
+				continue
+			}
+			protected = true
+
+			for _, rule := range policy.Ingress {
+				for _, src := range allocs {
+					if src == dst || !rule.Allows(policy.Namespace, src.endpoint) {
+						continue
+					}
+					ruleset.Allowed = append(ruleset.Allowed,
+						expandNetworkPolicyRule(src.address, dst.address, rule)...)
+				}
+			}
+		}
+
+		if protected {
+			ruleset.Protected = append(ruleset.Protected, dst.address)
+		}
+	}
+
+	sort.Slice(ruleset.Allowed, func(i, j int) bool {
+		a, b := ruleset.Allowed[i], ruleset.Allowed[j]
+		switch {
+		case a.Destination != b.Destination:
+			return a.Destination < b.Destination
+		case a.Source != b.Source:
+			return a.Source < b.Source
+		case a.Protocol != b.Protocol:
+			return a.Protocol < b.Protocol
+		default:
+			return a.Port < b.Port
+		}
+	})
+	ruleset.Allowed = slices.Compact(ruleset.Allowed)
+	sort.Strings(ruleset.Protected)
+
+	return ruleset
+}
+
+// expandNetworkPolicyRule returns one rule per protocol and port allowed by
+// the ingress rule. Ports without a protocol are allowed for both TCP and
+// UDP.
+func expandNetworkPolicyRule(src, dst string, rule *structs.NetworkPolicyIngressRule) []networkPolicyRule {
+	if len(rule.Ports) == 0 {
+		return []networkPolicyRule{{Source: src, Destination: dst, Protocol: rule.Protocol}}
+	}
+
+	protocols := []string{rule.Protocol}
+	if rule.Protocol == "" {
+		protocols = []string{structs.NetworkPolicyProtocolTCP, structs.NetworkPolicyProtocolUDP}
+	}
+
+	rules := make([]networkPolicyRule, 0, len(protocols)*len(rule.Ports))
+	for _, protocol := range protocols {
+		for _, port := range rule.Ports {
+			rules = append(rules, networkPolicyRule{
+				Source:      src,
+				Destination: dst,
+				Protocol:    protocol,
+				Port:        port,
+			})
+		}
+	}
+	return rules
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/coreos/go-iptables/iptables"
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// networkPolicyChainName is the name of the iptables chain holding the
+	// rules compiled from network policies. It is jumped to from the CNI
+	// admin chain so that it is evaluated before traffic to the bridge is
+	// accepted.
+	networkPolicyChainName = "NOMAD-POLICY"
+)
+
+// iptablesNetworkPolicyEnforcer is a networkPolicyEnforcer that enforces
+// network policies with iptables.
+type iptablesNetworkPolicyEnforcer struct {
+	allocSubnet string
+	logger      hclog.Logger
+}
+
+func newNetworkPolicyEnforcer(logger hclog.Logger, allocSubnet string) networkPolicyEnforcer {
+	if allocSubnet == "" {
+		allocSubnet = defaultNomadAllocSubnet
+	}
+	return &iptablesNetworkPolicyEnforcer{
+		allocSubnet: allocSubnet,
+		logger:      logger,
+	}
+}
+
+// Enforce rebuilds the network policy chain from the ruleset.
+func (e *iptablesNetworkPolicyEnforcer) Enforce(ruleset *networkPolicyRuleset) error {
+	networkingGlobalMutex.Lock()
+	defer networkingGlobalMutex.Unlock()
+
+	ipt, err := iptables.New()
+	if err != nil {
+		return err
+	}
+
+	if err := e.ensurePolicyChain(ipt); err != nil {
+		return err
+	}
+
+	if err := ipt.ClearChain("filter", networkPolicyChainName); err != nil {
+		return fmt.Errorf("failed to clear iptables chain %s: %v", networkPolicyChainName, err)
+	}
+	for _, rule := range e.generateChainRules(ruleset) {
+		if err := ipt.Append("filter", networkPolicyChainName, rule...); err != nil {
+			return fmt.Errorf("failed to append network policy rule: %v", err)
+		}
+	}
+
+	e.logger.Debug("enforced network policies",
+		"allowed", len(ruleset.Allowed), "protected", len(ruleset.Protected))
+	return nil
+}
+
+// Denied returns the packet counters of the drop rules of the network policy
+// chain. Only the first packet of a connection reaches the drop rules, so
+// the counters match the number of denied connections.
+func (e *iptablesNetworkPolicyEnforcer) Denied() (map[string]uint64, error) {
+	networkingGlobalMutex.Lock()
+	defer networkingGlobalMutex.Unlock()
+
+	ipt, err := iptables.New()
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := ipt.StructuredStats("filter", networkPolicyChainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read iptables chain %s: %v", networkPolicyChainName, err)
+	}
+
+	denied := make(map[string]uint64)
+	for _, stat := range stats {
+		if stat.Target != "DROP" || stat.Destination == nil {
+			continue
+		}
+		denied[stat.Destination.IP.String()] += stat.Packets
+	}
+	return denied, nil
+}
+
+// ensurePolicyChain ensures that the network policy chain exists and that it
+// is jumped to at the top of the CNI admin chain.
+func (e *iptablesNetworkPolicyEnforcer) ensurePolicyChain(ipt *iptables.IPTables) error {
+	if err := ensureChain(ipt, "filter", networkPolicyChainName); err != nil {
+		return err
+	}
+	if err := ensureChain(ipt, "filter", cniAdminChainName); err != nil {
+		return err
+	}
+
+	jump := []string{"-j", networkPolicyChainName}
+	exists, err := ipt.Exists("filter", cniAdminChainName, jump...)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ipt.Insert("filter", cniAdminChainName, 1, jump...)
+	}
+	return nil
+}
+
+// generateChainRules builds the rules of the network policy chain. Replies to
+// established connections and allowed connections return to the admin
+// chain, while any other connection from the bridge subnet to a protected
+// allocation is dropped.
+func (e *iptablesNetworkPolicyEnforcer) generateChainRules(ruleset *networkPolicyRuleset) [][]string {
+	rules := [][]string{
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "RETURN"},
+	}
+
+	for _, allowed := range ruleset.Allowed {
+		rule := []string{"-s", allowed.Source + "/32", "-d", allowed.Destination + "/32"}
+		if allowed.Protocol != "" {
+			rule = append(rule, "-p", allowed.Protocol)
+		}
+		if allowed.Port != 0 {
+			rule = append(rule, "--dport", strconv.Itoa(allowed.Port))
+		}
+		rules = append(rules, append(rule, "-j", "RETURN"))
+	}
+
+	for _, protected := range ruleset.Protected {
+		rules = append(rules, []string{"-s", e.allocSubnet, "-d", protected + "/32", "-j", "DROP"})
+	}
+
+	return rules
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+func Test_iptablesNetworkPolicyEnforcer_generateChainRules(t *testing.T) {
+	ci.Parallel(t)
+
+	e := newNetworkPolicyEnforcer(testlog.HCLogger(t), "").(*iptablesNetworkPolicyEnforcer)
+	rules := e.generateChainRules(&networkPolicyRuleset{
+		Allowed: []networkPolicyRule{
+			{Source: "172.26.64.3", Destination: "172.26.64.2", Protocol: "tcp", Port: 5432},
+			{Source: "172.26.64.4", Destination: "172.26.64.2"},
+		},
+		Protected: []string{"172.26.64.2"},
+	})
+
+	must.Eq(t, [][]string{
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "RETURN"},
+		{"-s", "172.26.64.3/32", "-d", "172.26.64.2/32", "-p", "tcp", "--dport", "5432", "-j", "RETURN"},
+		{"-s", "172.26.64.4/32", "-d", "172.26.64.2/32", "-j", "RETURN"},
+		{"-s", defaultNomadAllocSubnet, "-d", "172.26.64.2/32", "-j", "DROP"},
+	}, rules)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !linux
+// +build !linux
+
+package allocrunner
+
+import (
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// noopNetworkPolicyEnforcer is a networkPolicyEnforcer for systems that don't
+// support bridge networking.
+type noopNetworkPolicyEnforcer struct{}
+
+func newNetworkPolicyEnforcer(_ hclog.Logger, _ string) networkPolicyEnforcer {
+	return noopNetworkPolicyEnforcer{}
+}
+
+func (noopNetworkPolicyEnforcer) Enforce(*networkPolicyRuleset) error {
+	return nil
+}
+
+func (noopNetworkPolicyEnforcer) Denied() (map[string]uint64, error) {
+	return nil, nil
+}
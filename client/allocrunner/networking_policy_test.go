@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+type mockNetworkPolicyEnforcer struct {
+	enforced []*networkPolicyRuleset
+	denied   map[string]uint64
+}
+
+func (m *mockNetworkPolicyEnforcer) Enforce(ruleset *networkPolicyRuleset) error {
+	m.enforced = append(m.enforced, ruleset)
+	return nil
+}
+
+func (m *mockNetworkPolicyEnforcer) Denied() (map[string]uint64, error) {
+	return m.denied, nil
+}
+
+func (m *mockNetworkPolicyEnforcer) last() *networkPolicyRuleset {
+	return m.enforced[len(m.enforced)-1]
+}
+
+func TestNetworkPolicyManager(t *testing.T) {
+	ci.Parallel(t)
+
+	enforcer := &mockNetworkPolicyEnforcer{}
+	m := newNetworkPolicyManager(testlog.HCLogger(t), enforcer)
+
+	endpoint := func(allocID, job string) *structs.NetworkPolicyEndpoint {
+		return &structs.NetworkPolicyEndpoint{
+			AllocID:   allocID,
+			Namespace: structs.DefaultNamespace,
+			JobID:     job,
+			TaskGroup: "group",
+		}
+	}
+	m.UpsertAlloc("172.26.64.2", endpoint("db-alloc", "db"))
+	m.UpsertAlloc("172.26.64.3", endpoint("api-alloc", "api"))
+	m.UpsertAlloc("172.26.64.4", endpoint("web-alloc", "web"))
+
+	// Without policies nothing is protected and there is nothing to enforce.
+	must.Len(t, 0, enforcer.enforced)
+
+	// Only the api job may connect to the db job.
+	m.SetPolicies([]*structs.NetworkPolicy{{
+		Name:      "db",
+		Namespace: structs.DefaultNamespace,
+		Selector:  &structs.NetworkPolicySelector{Job: "db"},
+		Ingress: []*structs.NetworkPolicyIngressRule{{
+			From:  []*structs.NetworkPolicySelector{{Job: "api"}},
+			Ports: []int{5432},
+		}},
+	}})
+	must.Len(t, 1, enforcer.enforced)
+	must.Eq(t, &networkPolicyRuleset{
+		Allowed: []networkPolicyRule{
+			{Source: "172.26.64.3", Destination: "172.26.64.2", Protocol: "tcp", Port: 5432},
+			{Source: "172.26.64.3", Destination: "172.26.64.2", Protocol: "udp", Port: 5432},
+		},
+		Protected: []string{"172.26.64.2"},
+	}, enforcer.last())
+
+	// Re-adding an alloc with the same address doesn't enforce the rules
+	// again.
+	m.UpsertAlloc("172.26.64.4", endpoint("web-alloc", "web"))
+	must.Len(t, 1, enforcer.enforced)
+
+	// Denied counters are read before the rules are rebuilt.
+	enforcer.denied = map[string]uint64{"172.26.64.2": 3}
+	m.EmitStats(nil)
+	must.Eq(t, map[string]uint64{"172.26.64.2": 3}, m.denied)
+
+	// Removing the api alloc removes the allowed connections but the db alloc
+	// remains protected.
+	m.RemoveAlloc("api-alloc")
+	must.Len(t, 2, enforcer.enforced)
+	must.Eq(t, &networkPolicyRuleset{
+		Protected: []string{"172.26.64.2"},
+	}, enforcer.last())
+	must.MapEmpty(t, m.denied)
+
+	// Removing the policies removes all the rules.
+	m.SetPolicies(nil)
+	must.Len(t, 3, enforcer.enforced)
+	must.Eq(t, &networkPolicyRuleset{}, enforcer.last())
+}
+
+func TestCompileNetworkPolicies(t *testing.T) {
+	ci.Parallel(t)
+
+	allocs := map[string]*networkPolicyAlloc{
+		"a": {address: "10.0.0.1", endpoint: &structs.NetworkPolicyEndpoint{
+			AllocID: "a", Namespace: "prod", JobID: "db", Meta: map[string]string{"tier": "data"}}},
+		"b": {address: "10.0.0.2", endpoint: &structs.NetworkPolicyEndpoint{
+			AllocID: "b", Namespace: "prod", JobID: "api"}},
+		"c": {address: "10.0.0.3", endpoint: &structs.NetworkPolicyEndpoint{
+			AllocID: "c", Namespace: "dev", JobID: "api"}},
+	}
+
+	testCases := []struct {
+		name     string
+		policies []*structs.NetworkPolicy
+		expected *networkPolicyRuleset
+	}{
+		{
+			name: "deny all",
+			policies: []*structs.NetworkPolicy{{
+				Name:      "deny",
+				Namespace: "prod",
+				Selector:  &structs.NetworkPolicySelector{Meta: map[string]string{"tier": "data"}},
+			}},
+			expected: &networkPolicyRuleset{
+				Protected: []string{"10.0.0.1"},
+			},
+		},
+		{
+			name: "allow same namespace",
+			policies: []*structs.NetworkPolicy{{
+				Name:      "same-ns",
+				Namespace: "prod",
+				Selector:  &structs.NetworkPolicySelector{Job: "db"},
+				Ingress: []*structs.NetworkPolicyIngressRule{{
+					Protocol: structs.NetworkPolicyProtocolTCP,
+				}},
+			}},
+			expected: &networkPolicyRuleset{
+				Allowed: []networkPolicyRule{
+					{Source: "10.0.0.2", Destination: "10.0.0.1", Protocol: "tcp"},
+					{Source: "10.0.0.3", Destination: "10.0.0.1", Protocol: "tcp"},
+				},
+				Protected: []string{"10.0.0.1"},
+			},
+		},
+		{
+			name: "allow other namespace",
+			policies: []*structs.NetworkPolicy{{
+				Name:      "other-ns",
+				Namespace: "prod",
+				Selector:  &structs.NetworkPolicySelector{Job: "db"},
+				Ingress: []*structs.NetworkPolicyIngressRule{{
+					From: []*structs.NetworkPolicySelector{{Namespace: "dev", Job: "api"}},
+				}},
+			}},
+			expected: &networkPolicyRuleset{
+				Allowed: []networkPolicyRule{
+					{Source: "10.0.0.3", Destination: "10.0.0.1"},
+				},
+				Protected: []string{"10.0.0.1"},
+			},
+		},
+		{
+			name: "policy in other namespace",
+			policies: []*structs.NetworkPolicy{{
+				Name:      "dev",
+				Namespace: "dev",
+				Selector:  &structs.NetworkPolicySelector{Job: "db"},
+			}},
+			expected: &networkPolicyRuleset{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compileNetworkPolicies(tc.policies, allocs)
+			must.Eq(t, tc.expected, got)
+		})
+	}
+}
@@ -5,11 +5,13 @@ package taskrunner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	ci "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -46,6 +48,17 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource, "aid", aid)
 
 		if err := h.getter.Get(req.TaskEnv, artifact); err != nil {
+			// Artifacts that fail verification are rejected rather than
+			// downloaded again
+			if errors.Is(err, getter.ErrVerification) {
+				verr := fmt.Errorf("failed to verify artifact %q: %v", artifact.GetterSource, err)
+				event := structs.NewTaskEvent(structs.TaskArtifactVerificationFailed).
+					SetVerificationError(verr).
+					SetFailsTask()
+				errorChannel <- NewHookError(verr, event)
+				continue
+			}
+
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
 				true,
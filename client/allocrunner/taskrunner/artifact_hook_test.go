@@ -19,10 +19,12 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,6 +64,42 @@ func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 	require.Equal(t, structs.TaskDownloadingArtifacts, me.Events()[0].Type)
 }
 
+// TestTaskRunner_ArtifactHook_Verification asserts that artifacts failing
+// signature verification are rejected with a distinct task event.
+func TestTaskRunner_ArtifactHook_Verification(t *testing.T) {
+	ci.Parallel(t)
+
+	me := &trtesting.MockEmitter{}
+	sbox := getter.New(&config.ArtifactConfig{RequireSignature: true}, nil, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskEnv: taskenv.NewEmptyTaskEnv(),
+		TaskDir: &allocdir.TaskDir{Dir: os.TempDir()},
+		Task: &structs.Task{
+			Artifacts: []*structs.TaskArtifact{
+				{
+					GetterSource: "http://127.0.0.1:0/app.tar.gz",
+					GetterMode:   structs.GetterModeAny,
+				},
+			},
+		},
+	}
+
+	resp := interfaces.TaskPrestartResponse{}
+
+	err := artifactHook.Prestart(context.Background(), req, &resp)
+	must.False(t, resp.Done)
+	must.ErrorContains(t, err, "the client requires signatures")
+	must.False(t, structs.IsRecoverable(err))
+
+	herr, ok := err.(*hookError)
+	must.True(t, ok)
+	must.Eq(t, structs.TaskArtifactVerificationFailed, herr.taskEvent.Type)
+	must.True(t, herr.taskEvent.FailsTask)
+	must.StrContains(t, herr.taskEvent.Details["verification_error"], "app.tar.gz")
+}
+
 // TestTaskRunnerArtifactHook_PartialDone asserts that the artifact hook skips
 // already downloaded artifacts when subsequent artifacts fail and cause a
 // restart.
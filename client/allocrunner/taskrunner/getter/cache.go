@@ -0,0 +1,477 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// cacheMetaFile is the name of the file holding the metadata of an entry
+	// in the artifact cache. Its modification time is the last time the entry
+	// was used.
+	cacheMetaFile = "meta.json"
+
+	// cacheDataDir is the directory of an entry holding the downloaded
+	// artifact.
+	cacheDataDir = "data"
+
+	// cacheStagingPrefix is the prefix of the directories into which
+	// artifacts are downloaded before being added to the cache.
+	cacheStagingPrefix = ".staging-"
+)
+
+// cacheEntry is an artifact in the artifact cache.
+type cacheEntry struct {
+	Key        string    `json:"key"`
+	Source     string    `json:"source"`
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	CreateTime time.Time `json:"create_time"`
+
+	// Digest is the SHA-256 digest of the downloaded artifact, which is
+	// verified before the entry is used
+	Digest string `json:"digest"`
+
+	// lastUsed is the last time the entry was used by a task
+	lastUsed time.Time
+
+	// refs is the number of tasks currently using the entry, which cannot be
+	// evicted while in use
+	refs int
+}
+
+// path returns the path of the downloaded artifact of the entry.
+func (e *cacheEntry) path(dir string) string {
+	return filepath.Join(dir, e.Key, cacheDataDir, e.Name)
+}
+
+func (e *cacheEntry) stub() *structs.ArtifactCacheEntry {
+	return &structs.ArtifactCacheEntry{
+		Key:         e.Key,
+		Source:      e.Source,
+		Name:        e.Name,
+		Size:        e.Size,
+		References:  e.refs,
+		CreateTime:  e.CreateTime.UnixNano(),
+		LastUseTime: e.lastUsed.UnixNano(),
+	}
+}
+
+// A Cache holds downloaded artifacts shared by every task on the client,
+// keyed by their content. Artifacts are evicted in least recently used order
+// once the size of the cache exceeds its maximum.
+type Cache struct {
+	logger   hclog.Logger
+	dir      string
+	maxBytes int64
+
+	lock    sync.Mutex
+	entries map[string]*cacheEntry
+	fills   map[string]chan struct{}
+	size    int64
+}
+
+// NewCache creates a Cache in dir, restoring the artifacts previously added
+// to it.
+func NewCache(dir string, maxBytes int64, logger hclog.Logger) (*Cache, error) {
+	c := &Cache{
+		logger:   logger.Named("artifact_cache"),
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*cacheEntry),
+		fills:    make(map[string]chan struct{}),
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache directory: %w", err)
+	}
+	if err := c.restore(); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.evictLocked()
+	c.emitSizeLocked()
+	return c, nil
+}
+
+// restore loads the entries of the cache from its directory, removing
+// leftover staging directories and incomplete entries.
+func (c *Cache) restore() error {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact cache directory: %w", err)
+	}
+
+	for _, f := range files {
+		path := filepath.Join(c.dir, f.Name())
+		if !f.IsDir() || strings.HasPrefix(f.Name(), cacheStagingPrefix) {
+			_ = os.RemoveAll(path)
+			continue
+		}
+
+		entry, err := readCacheEntry(path)
+		if err != nil || entry.Key != f.Name() {
+			c.logger.Warn("removing invalid artifact cache entry", "key", f.Name(), "error", err)
+			_ = os.RemoveAll(path)
+			continue
+		}
+		c.entries[entry.Key] = entry
+		c.size += entry.Size
+	}
+	return nil
+}
+
+func readCacheEntry(dir string) (*cacheEntry, error) {
+	metaPath := filepath.Join(dir, cacheMetaFile)
+	b, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(metaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(b, entry); err != nil {
+		return nil, err
+	}
+	if entry.Digest == "" {
+		return nil, errors.New("missing digest")
+	}
+	if _, err := os.Stat(entry.path(filepath.Dir(dir))); err != nil {
+		return nil, err
+	}
+	entry.lastUsed = info.ModTime()
+	return entry, nil
+}
+
+// cacheKey returns the key of an artifact in the cache. The source must not
+// include the options which only affect how the artifact is unpacked, and
+// version identifies its content, such as its checksum or ETag.
+func cacheKey(source, version string) string {
+	h := sha256.New()
+	h.Write([]byte(source))
+	h.Write([]byte{0})
+	h.Write([]byte(version))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheFillFn downloads an artifact into the data directory of a new cache
+// entry and returns its file name.
+type cacheFillFn func(dataDir string) (string, error)
+
+// acquire returns the entry of the cache for key, calling fill to download
+// the artifact if it is not cached yet. Concurrent callers for the same key
+// wait for a single download. The entry cannot be evicted until it is
+// released.
+func (c *Cache) acquire(key, source string, fill cacheFillFn) (*cacheEntry, error) {
+	for {
+		c.lock.Lock()
+		if entry, ok := c.entries[key]; ok {
+			entry.refs++
+			c.touchLocked(entry)
+			c.lock.Unlock()
+
+			// never hand out an artifact whose content changed since it was
+			// downloaded, and download it again instead
+			if err := c.verify(entry); err != nil {
+				c.logger.Warn("removing modified artifact cache entry",
+					"key", entry.Key, "source", entry.Source, "error", err)
+				metrics.IncrCounter([]string{"client", "artifact_cache", "invalid"}, 1)
+				c.lock.Lock()
+				entry.refs--
+				if c.entries[key] == entry {
+					if err := c.removeLocked(entry); err != nil {
+						c.lock.Unlock()
+						return nil, fmt.Errorf("failed to remove modified artifact: %w", err)
+					}
+					c.emitSizeLocked()
+				}
+				c.lock.Unlock()
+				continue
+			}
+
+			metrics.IncrCounter([]string{"client", "artifact_cache", "hit"}, 1)
+			return entry, nil
+		}
+
+		if done, ok := c.fills[key]; ok {
+			// another task is downloading the same artifact
+			c.lock.Unlock()
+			<-done
+			continue
+		}
+
+		done := make(chan struct{})
+		c.fills[key] = done
+		c.lock.Unlock()
+
+		metrics.IncrCounter([]string{"client", "artifact_cache", "miss"}, 1)
+		entry, err := c.fill(key, source, fill)
+
+		c.lock.Lock()
+		delete(c.fills, key)
+		close(done)
+		if err != nil {
+			c.lock.Unlock()
+			return nil, err
+		}
+		entry.refs++
+		c.entries[key] = entry
+		c.size += entry.Size
+		c.evictLocked()
+		c.emitSizeLocked()
+		c.lock.Unlock()
+		return entry, nil
+	}
+}
+
+// release marks the entry as no longer used by a task.
+func (c *Cache) release(entry *cacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry.refs--
+	c.evictLocked()
+	c.emitSizeLocked()
+}
+
+// fill downloads the artifact into a staging directory and moves it into the
+// cache once complete.
+func (c *Cache) fill(key, source string, fill cacheFillFn) (*cacheEntry, error) {
+	staging, err := os.MkdirTemp(c.dir, cacheStagingPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	dataDir := filepath.Join(staging, cacheDataDir)
+	name, err := fill(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// make the cached artifact read-only, as it is never modified once cached
+	var size int64
+	err = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+			return os.Chmod(path, info.Mode().Perm()&^0o222)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add artifact to cache: %w", err)
+	}
+	digest, err := digestDir(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add artifact to cache: %w", err)
+	}
+
+	entry := &cacheEntry{
+		Key:        key,
+		Source:     source,
+		Name:       name,
+		Size:       size,
+		CreateTime: time.Now(),
+		Digest:     digest,
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(staging, cacheMetaFile), b, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to add artifact to cache: %w", err)
+	}
+
+	entryDir := filepath.Join(c.dir, key)
+	_ = os.RemoveAll(entryDir)
+	if err := os.Rename(staging, entryDir); err != nil {
+		return nil, fmt.Errorf("failed to add artifact to cache: %w", err)
+	}
+	entry.lastUsed = entry.CreateTime
+	return entry, nil
+}
+
+// verify returns an error if the content of the entry no longer matches the
+// digest recorded when it was added to the cache.
+func (c *Cache) verify(entry *cacheEntry) error {
+	digest, err := digestDir(filepath.Join(c.dir, entry.Key, cacheDataDir))
+	if err != nil {
+		return err
+	}
+	if digest != entry.Digest {
+		return errors.New("artifact digest mismatch")
+	}
+	return nil
+}
+
+// digestDir returns the SHA-256 digest of the names and content of the
+// files in dir.
+func digestDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		h.Write([]byte(filepath.ToSlash(rel)))
+		h.Write([]byte{0})
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(h, f)
+		h.Write([]byte{0})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// touchLocked records the use of the entry. Must be called with the lock
+// held.
+func (c *Cache) touchLocked(entry *cacheEntry) {
+	entry.lastUsed = time.Now()
+	metaPath := filepath.Join(c.dir, entry.Key, cacheMetaFile)
+	if err := os.Chtimes(metaPath, entry.lastUsed, entry.lastUsed); err != nil {
+		c.logger.Debug("failed to update artifact cache entry", "key", entry.Key, "error", err)
+	}
+}
+
+// evictLocked removes the least recently used entries not in use until the
+// size of the cache is below its maximum. Must be called with the lock held.
+func (c *Cache) evictLocked() {
+	if c.size <= c.maxBytes {
+		return
+	}
+
+	entries := make([]*cacheEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if entry.refs == 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+
+	for _, entry := range entries {
+		if c.size <= c.maxBytes {
+			return
+		}
+		if err := c.removeLocked(entry); err != nil {
+			c.logger.Warn("failed to evict artifact", "key", entry.Key, "source", entry.Source, "error", err)
+			continue
+		}
+		metrics.IncrCounter([]string{"client", "artifact_cache", "evict"}, 1)
+		c.logger.Debug("evicted artifact", "key", entry.Key, "source", entry.Source)
+	}
+}
+
+func (c *Cache) removeLocked(entry *cacheEntry) error {
+	if err := os.RemoveAll(filepath.Join(c.dir, entry.Key)); err != nil {
+		return err
+	}
+	delete(c.entries, entry.Key)
+	c.size -= entry.Size
+	return nil
+}
+
+func (c *Cache) emitSizeLocked() {
+	metrics.SetGauge([]string{"client", "artifact_cache", "size"}, float32(c.size))
+	metrics.SetGauge([]string{"client", "artifact_cache", "entries"}, float32(len(c.entries)))
+}
+
+// List returns the artifacts in the cache.
+func (c *Cache) List() *structs.ArtifactCacheResponse {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	resp := &structs.ArtifactCacheResponse{
+		Entries: make([]*structs.ArtifactCacheEntry, 0, len(c.entries)),
+		Size:    c.size,
+		MaxSize: c.maxBytes,
+	}
+	for _, entry := range c.entries {
+		resp.Entries = append(resp.Entries, entry.stub())
+	}
+	sort.Slice(resp.Entries, func(i, j int) bool {
+		return resp.Entries[i].LastUseTime > resp.Entries[j].LastUseTime
+	})
+	return resp
+}
+
+// Purge removes the artifacts with the given keys from the cache, or every
+// artifact if no keys are given. Artifacts in use by a task are not removed.
+func (c *Cache) Purge(keys []string) (*structs.ArtifactCachePurgeResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys = slices.Clone(keys)
+	if len(keys) == 0 {
+		for key := range c.entries {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	resp := new(structs.ArtifactCachePurgeResponse)
+	var mErr []error
+	for _, key := range keys {
+		entry, ok := c.entries[key]
+		switch {
+		case !ok:
+			resp.NotFound = append(resp.NotFound, key)
+		case entry.refs > 0:
+			resp.InUse = append(resp.InUse, key)
+		default:
+			if err := c.removeLocked(entry); err != nil {
+				mErr = append(mErr, fmt.Errorf("failed to purge artifact %s: %w", key, err))
+				continue
+			}
+			resp.Purged = append(resp.Purged, key)
+		}
+	}
+	c.emitSizeLocked()
+	return resp, errors.Join(mErr...)
+}
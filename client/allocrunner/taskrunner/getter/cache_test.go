@@ -0,0 +1,247 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+// testFill returns a cacheFillFn writing data to a file with the given name,
+// and a counter of its calls.
+func testFill(name string, data []byte) (cacheFillFn, *atomic.Int32) {
+	calls := new(atomic.Int32)
+	return func(dataDir string) (string, error) {
+		calls.Add(1)
+		if err := os.MkdirAll(dataDir, 0o755); err != nil {
+			return "", err
+		}
+		return name, os.WriteFile(filepath.Join(dataDir, name), data, 0o644)
+	}, calls
+}
+
+func TestCache_acquire(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 1024, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	fill, calls := testFill("app.tar.gz", []byte("hello"))
+	key := cacheKey("https://example.com/app.tar.gz", "checksum:md5:abc")
+
+	// first use downloads the artifact
+	entry, err := cache.acquire(key, "https://example.com/app.tar.gz", fill)
+	must.NoError(t, err)
+	must.Eq(t, 1, calls.Load())
+	must.Eq(t, 5, entry.Size)
+	must.Eq(t, "app.tar.gz", entry.Name)
+
+	b, err := os.ReadFile(entry.path(dir))
+	must.NoError(t, err)
+	must.Eq(t, "hello", string(b))
+
+	// cached artifacts are read-only
+	info, err := os.Stat(entry.path(dir))
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(0o444), info.Mode().Perm())
+
+	// second use is a hit
+	entry2, err := cache.acquire(key, "https://example.com/app.tar.gz", fill)
+	must.NoError(t, err)
+	must.Eq(t, 1, calls.Load())
+	must.Eq(t, 2, entry2.refs)
+
+	cache.release(entry)
+	cache.release(entry2)
+
+	list := cache.List()
+	must.Len(t, 1, list.Entries)
+	must.Eq(t, key, list.Entries[0].Key)
+	must.Eq(t, 0, list.Entries[0].References)
+	must.Eq(t, 5, list.Size)
+
+	// entries are restored with the cache
+	cache, err = NewCache(dir, 1024, testlog.HCLogger(t))
+	must.NoError(t, err)
+	list = cache.List()
+	must.Len(t, 1, list.Entries)
+	must.Eq(t, "https://example.com/app.tar.gz", list.Entries[0].Source)
+}
+
+func TestCache_acquire_modified(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 1024, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	fill, calls := testFill("file.txt", []byte("hello"))
+	key := cacheKey("https://example.com/file.txt", "checksum:md5:abc")
+	get := func() string {
+		entry, err := cache.acquire(key, "https://example.com/file.txt", fill)
+		must.NoError(t, err)
+		defer cache.release(entry)
+
+		dst := filepath.Join(t.TempDir(), "local", "file.txt")
+		must.NoError(t, newLinkGetter().GetFile(dst, &url.URL{Path: entry.path(dir)}))
+		return dst
+	}
+
+	// a task modifying its artifact doesn't modify the cached one
+	dst := get()
+	must.NoError(t, os.WriteFile(dst, []byte("poisoned"), 0o644))
+
+	b, err := os.ReadFile(get())
+	must.NoError(t, err)
+	must.Eq(t, "hello", string(b))
+	must.Eq(t, 1, calls.Load())
+
+	// cached artifacts modified in place are downloaded again
+	path := filepath.Join(dir, key, cacheDataDir, "file.txt")
+	must.NoError(t, os.Chmod(path, 0o644))
+	must.NoError(t, os.WriteFile(path, []byte("poisoned"), 0o644))
+
+	b, err = os.ReadFile(get())
+	must.NoError(t, err)
+	must.Eq(t, "hello", string(b))
+	must.Eq(t, 2, calls.Load())
+	must.Len(t, 1, cache.List().Entries)
+}
+
+func TestCache_acquire_concurrent(t *testing.T) {
+	ci.Parallel(t)
+
+	cache, err := NewCache(t.TempDir(), 1024, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	fill, calls := testFill("file.txt", []byte("hello"))
+	key := cacheKey("https://example.com/file.txt", "etag:\"abc\"")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry, err := cache.acquire(key, "https://example.com/file.txt", fill)
+			if err == nil {
+				cache.release(entry)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		must.NoError(t, err)
+	}
+
+	// the artifact is downloaded once
+	must.Eq(t, 1, calls.Load())
+}
+
+func TestCache_acquire_failure(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 1024, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	key := cacheKey("https://example.com/file.txt", "checksum:md5:abc")
+	_, err = cache.acquire(key, "https://example.com/file.txt", func(string) (string, error) {
+		return "", errors.New("download failed")
+	})
+	must.EqError(t, err, "download failed")
+
+	// nothing is left in the cache
+	must.Len(t, 0, cache.List().Entries)
+	files, err := os.ReadDir(dir)
+	must.NoError(t, err)
+	must.Len(t, 0, files)
+}
+
+func TestCache_evict(t *testing.T) {
+	ci.Parallel(t)
+
+	cache, err := NewCache(t.TempDir(), 10, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	acquire := func(name string) *cacheEntry {
+		fill, _ := testFill(name, []byte("12345"))
+		entry, err := cache.acquire(cacheKey(name, ""), name, fill)
+		must.NoError(t, err)
+		return entry
+	}
+
+	a := acquire("a")
+	cache.release(a)
+	b := acquire("b")
+	cache.release(b)
+
+	// use a again so that b is the least recently used artifact
+	a = acquire("a")
+	cache.release(a)
+
+	// c exceeds the maximum size, so b is evicted
+	c := acquire("c")
+	list := cache.List()
+	must.Len(t, 2, list.Entries)
+	must.Eq(t, 10, list.Size)
+	must.Eq(t, c.Key, list.Entries[0].Key)
+	must.Eq(t, a.Key, list.Entries[1].Key)
+
+	// artifacts in use are not evicted
+	d := acquire("d")
+	list = cache.List()
+	must.Len(t, 2, list.Entries)
+	must.Eq(t, d.Key, list.Entries[0].Key)
+	must.Eq(t, c.Key, list.Entries[1].Key)
+
+	cache.release(c)
+	cache.release(d)
+}
+
+func TestCache_Purge(t *testing.T) {
+	ci.Parallel(t)
+
+	cache, err := NewCache(t.TempDir(), 1024, testlog.HCLogger(t))
+	must.NoError(t, err)
+
+	acquire := func(name string) *cacheEntry {
+		fill, _ := testFill(name, []byte("12345"))
+		entry, err := cache.acquire(cacheKey(name, ""), name, fill)
+		must.NoError(t, err)
+		return entry
+	}
+
+	a := acquire("a")
+	cache.release(a)
+	b := acquire("b")
+	c := acquire("c")
+	cache.release(c)
+
+	resp, err := cache.Purge([]string{a.Key, b.Key, "unknown"})
+	must.NoError(t, err)
+	must.Eq(t, []string{a.Key}, resp.Purged)
+	must.Eq(t, []string{b.Key}, resp.InUse)
+	must.Eq(t, []string{"unknown"}, resp.NotFound)
+
+	cache.release(b)
+	resp, err = cache.Purge(nil)
+	must.NoError(t, err)
+	must.Len(t, 2, resp.Purged)
+	must.Len(t, 0, cache.List().Entries)
+	must.Eq(t, 0, cache.List().Size)
+}
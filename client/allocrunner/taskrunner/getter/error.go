@@ -3,6 +3,16 @@
 
 package getter
 
+import "errors"
+
+// ErrVerification is wrapped by the errors of artifacts whose signature could
+// not be verified.
+var ErrVerification = errors.New("artifact signature verification failed")
+
+// ErrNotCacheable is wrapped by the errors of artifacts prefetched into the
+// artifact cache which cannot be cached.
+var ErrNotCacheable = errors.New("artifact cannot be cached")
+
 // Error is a RecoverableError used to include the URL along with the underlying
 // fetching error.
 type Error struct {
@@ -22,6 +32,10 @@ func (e *Error) IsRecoverable() bool {
 	return e.Recoverable
 }
 
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 func (e *Error) Equal(o *Error) bool {
 	if e == nil || o == nil {
 		return e == o
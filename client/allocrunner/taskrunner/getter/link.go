@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-getter"
+)
+
+// linkGetter is a go-getter file getter which places files from the artifact
+// cache into the task directory by cloning them where the filesystem supports
+// it, and copying them otherwise. Files are never hard linked, as a task able
+// to write to the shared inode would change the artifact for every other task
+// using the cache.
+type linkGetter struct {
+	getter.FileGetter
+}
+
+func newLinkGetter() *linkGetter {
+	return &linkGetter{
+		FileGetter: getter.FileGetter{Copy: true},
+	}
+}
+
+func (g *linkGetter) GetFile(dst string, u *url.URL) error {
+	path := u.Path
+	if u.RawPath != "" {
+		path = u.RawPath
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("source path error: %s", err)
+	} else if fi.IsDir() {
+		return fmt.Errorf("source path must be a file")
+	}
+
+	if _, err := os.Lstat(dst); err == nil {
+		if err := os.Remove(dst); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// cached files are read-only, so restore the owner write permission of
+	// the clone or copy
+	writable := fi.Mode().Perm() | 0o200
+
+	if err := reflink(path, dst, writable); err == nil {
+		return nil
+	}
+
+	if err := g.FileGetter.GetFile(dst, u); err != nil {
+		return err
+	}
+	return os.Chmod(dst, writable)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestLinkGetter_GetFile(t *testing.T) {
+	ci.Parallel(t)
+
+	src := filepath.Join(t.TempDir(), "file.txt")
+	must.NoError(t, os.WriteFile(src, []byte("hello"), 0o444))
+
+	dst := filepath.Join(t.TempDir(), "local", "file.txt")
+	g := newLinkGetter()
+	must.NoError(t, g.GetFile(dst, &url.URL{Path: src}))
+
+	b, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, "hello", string(b))
+
+	// files are independent copies writable by their owner, which never
+	// share an inode with the cached file
+	srcInfo, err := os.Stat(src)
+	must.NoError(t, err)
+	dstInfo, err := os.Stat(dst)
+	must.NoError(t, err)
+	must.False(t, os.SameFile(srcInfo, dstInfo))
+	must.Eq(t, os.FileMode(0o644), dstInfo.Mode().Perm())
+
+	// modifying the file doesn't modify the cached file
+	must.NoError(t, os.WriteFile(dst, []byte("poisoned"), 0o644))
+	b, err = os.ReadFile(src)
+	must.NoError(t, err)
+	must.Eq(t, "hello", string(b))
+
+	// existing files are replaced
+	must.NoError(t, g.GetFile(dst, &url.URL{Path: src}))
+
+	// directories cannot be linked
+	err = g.GetFile(dst, &url.URL{Path: filepath.Dir(src)})
+	must.ErrorContains(t, err, "source path must be a file")
+}
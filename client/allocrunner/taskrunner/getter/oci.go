@@ -0,0 +1,680 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/distribution/reference"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-getter"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// ociMaxManifestSize is the maximum size of the manifests read from a
+	// registry.
+	ociMaxManifestSize = 4 << 20
+
+	// ociAnnotationUnpack is set by ORAS on layers holding a directory packed
+	// as a gzipped tarball.
+	ociAnnotationUnpack = "io.deis.oras.content.unpack"
+
+	ociMediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	ociMediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ociMediaTypeDockerLayer        = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+)
+
+// ociManifestMediaTypes are the media types of the manifests accepted when
+// resolving an artifact.
+var ociManifestMediaTypes = []string{
+	ocispec.MediaTypeImageManifest,
+	ocispec.MediaTypeImageIndex,
+	ociMediaTypeDockerManifest,
+	ociMediaTypeDockerManifestList,
+}
+
+// ociLayerArchives maps the media types of layers which are tarballs to the
+// decompressor used to unpack them.
+var ociLayerArchives = map[string]string{
+	ocispec.MediaTypeImageLayer:     "tar",
+	ocispec.MediaTypeImageLayerGzip: "tar.gz",
+	ocispec.MediaTypeImageLayerZstd: "tar.zst",
+	ociMediaTypeDockerLayer:         "tar.gz",
+}
+
+// ociCredentials are the credentials used to pull artifacts from an OCI
+// registry. They are resolved by the Nomad client, as the getter sub-process
+// cannot read the credential files or run credential helpers.
+type ociCredentials struct {
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identity_token"`
+	RegistryToken string `json:"registry_token"`
+}
+
+// Equal returns whether c and o are the same.
+func (c *ociCredentials) Equal(o *ociCredentials) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return *c == *o
+}
+
+// loadOCIAuthFile returns the credentials for the registry from a
+// Docker-style credentials file, or nil if the file has none. Credential
+// helpers configured in the file are run to get the credentials.
+func loadOCIAuthFile(path, registry string) (*ociCredentials, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfile := configfile.New(path)
+	if err := cfile.LoadFromReader(f); err != nil {
+		return nil, err
+	}
+
+	auth, err := cfile.GetAuthConfig(registry)
+	if err != nil {
+		return nil, err
+	}
+	if auth.Username == "" && auth.Password == "" && auth.IdentityToken == "" && auth.RegistryToken == "" {
+		return nil, nil
+	}
+	return &ociCredentials{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		IdentityToken: auth.IdentityToken,
+		RegistryToken: auth.RegistryToken,
+	}, nil
+}
+
+// ociReference is an artifact in an OCI registry, with a source of the form
+// oci://registry/repository:tag@digest.
+type ociReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     digest.Digest
+}
+
+// parseOCIReference parses the reference of an OCI artifact source. The tag
+// defaults to latest if neither a tag nor a digest is set.
+func parseOCIReference(u *url.URL) (*ociReference, error) {
+	ref, err := reference.Parse(u.Host + u.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI reference %q: %w", u.Host+u.Path, err)
+	}
+	named, ok := ref.(reference.Named)
+	if !ok || reference.Domain(named) != u.Host {
+		return nil, fmt.Errorf("invalid OCI reference %q: must include registry and repository", u.Host+u.Path)
+	}
+
+	r := &ociReference{
+		Registry:   u.Host,
+		Repository: reference.Path(named),
+	}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		r.Tag = tagged.Tag()
+	}
+	if digested, ok := ref.(reference.Digested); ok {
+		r.Digest = digested.Digest()
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = "latest"
+	}
+	return r, nil
+}
+
+// manifest returns the tag or digest used to resolve the manifest of the
+// artifact, preferring the digest which pins its content.
+func (r *ociReference) manifest() string {
+	if r.Digest != "" {
+		return r.Digest.String()
+	}
+	return r.Tag
+}
+
+func (r *ociReference) url(kind, ref string) string {
+	return fmt.Sprintf("https://%s/v2/%s/%s/%s", r.Registry, r.Repository, kind, ref)
+}
+
+// ociGetter is a go-getter Getter for artifacts stored in OCI registries. It
+// resolves the manifest of the artifact, selecting the manifest of the client
+// platform from an index, and downloads the selected layers after verifying
+// their digests. Tarball layers are unpacked into the destination and other
+// layers are written to files named after their title annotation.
+//
+// The layers are selected with the following options:
+//
+//   - media_type: only layers with this media type are downloaded
+//   - annotation: only layers with this annotation are downloaded, as either
+//     a key or a key=value pair
+//   - platform: the os/arch[/variant] of the manifest selected from an index,
+//     which defaults to the platform of the client
+type ociGetter struct {
+	getter *getter.Client
+
+	client      *http.Client
+	credentials *ociCredentials
+	readTimeout time.Duration
+	maxBytes    int64
+
+	// token is the bearer token of the registry, and basic is set if the
+	// registry uses basic authentication instead
+	token string
+	basic bool
+}
+
+func newOCIGetter(p *parameters) *ociGetter {
+	transport := cleanhttp.DefaultPooledTransport()
+	if p.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &ociGetter{
+		client:      &http.Client{Transport: transport},
+		credentials: p.OCICredentials,
+		readTimeout: p.HTTPReadTimeout,
+		maxBytes:    p.HTTPMaxBytes,
+	}
+}
+
+func (g *ociGetter) SetClient(c *getter.Client) {
+	g.getter = c
+}
+
+func (g *ociGetter) ClientMode(*url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeDir, nil
+}
+
+func (g *ociGetter) context() (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if g.getter != nil && g.getter.Ctx != nil {
+		ctx = g.getter.Ctx
+	}
+	if g.readTimeout > 0 {
+		return context.WithTimeout(ctx, g.readTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Get downloads the selected layers of the artifact into the dst directory.
+func (g *ociGetter) Get(dst string, u *url.URL) error {
+	ctx, cancel := g.context()
+	defer cancel()
+
+	ref, layers, err := g.resolve(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		if err := g.getLayer(ctx, ref, layer, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFile downloads the selected layer of the artifact into the dst file,
+// without unpacking it. Exactly one layer must be selected.
+func (g *ociGetter) GetFile(dst string, u *url.URL) error {
+	ctx, cancel := g.context()
+	defer cancel()
+
+	ref, layers, err := g.resolve(ctx, u)
+	if err != nil {
+		return err
+	}
+	if len(layers) != 1 {
+		return fmt.Errorf("OCI artifact has %d matching layers, but file mode requires exactly one", len(layers))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return g.writeBlob(ctx, ref, layers[0], dst)
+}
+
+// getLayer downloads a layer into the dst directory, unpacking it if it is a
+// tarball.
+func (g *ociGetter) getLayer(ctx context.Context, ref *ociReference, layer ocispec.Descriptor, dst string) error {
+	archive, ok := ociLayerArchives[layer.MediaType]
+	target := dst
+	if layer.Annotations[ociAnnotationUnpack] == "true" {
+		name, err := ociLayerName(layer)
+		if err != nil {
+			return err
+		}
+		archive, ok = "tar.gz", true
+		target = filepath.Join(dst, name)
+	}
+
+	if !ok {
+		name, err := ociLayerName(layer)
+		if err != nil {
+			return err
+		}
+		return g.writeBlob(ctx, ref, layer, filepath.Join(dst, name))
+	}
+
+	decompressor := g.decompressor(archive)
+	if decompressor == nil {
+		return fmt.Errorf("no decompressor for OCI layer %s of type %q", layer.Digest, layer.MediaType)
+	}
+
+	tmp, err := os.CreateTemp("", "oci-layer-")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := g.writeBlob(ctx, ref, layer, tmp.Name()); err != nil {
+		return err
+	}
+
+	var umask os.FileMode
+	if g.getter != nil {
+		umask = g.getter.Umask
+	}
+	if err := decompressor.Decompress(target, tmp.Name(), true, umask); err != nil {
+		return fmt.Errorf("failed to unpack OCI layer %s: %w", layer.Digest, err)
+	}
+	return nil
+}
+
+func (g *ociGetter) decompressor(archive string) getter.Decompressor {
+	if g.getter != nil && g.getter.Decompressors != nil {
+		return g.getter.Decompressors[archive]
+	}
+	return getter.Decompressors[archive]
+}
+
+// ociLayerName returns the name of the file of a layer, from its title
+// annotation or else its digest.
+func ociLayerName(layer ocispec.Descriptor) (string, error) {
+	name, ok := layer.Annotations[ocispec.AnnotationTitle]
+	if !ok {
+		return layer.Digest.Encoded(), nil
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("OCI layer %s has invalid title %q", layer.Digest, name)
+	}
+	return name, nil
+}
+
+// resolve returns the layers of the artifact selected by the options of the
+// source.
+func (g *ociGetter) resolve(ctx context.Context, u *url.URL) (*ociReference, []ocispec.Descriptor, error) {
+	ref, err := parseOCIReference(u)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+
+	body, mediaType, err := g.getManifest(ctx, ref, ref.manifest(), ref.Digest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mediaType == ocispec.MediaTypeImageIndex || mediaType == ociMediaTypeDockerManifestList {
+		var index ocispec.Index
+		if err := json.Unmarshal(body, &index); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode OCI index: %w", err)
+		}
+
+		platform := q.Get("platform")
+		if platform == "" {
+			platform = runtime.GOOS + "/" + runtime.GOARCH
+		}
+		desc, err := ociSelectPlatform(index.Manifests, platform)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		body, _, err = g.getManifest(ctx, ref, desc.Digest.String(), desc.Digest)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode OCI manifest: %w", err)
+	}
+
+	layers := ociSelectLayers(manifest.Layers, q.Get("media_type"), q.Get("annotation"))
+	if len(layers) == 0 {
+		return nil, nil, fmt.Errorf("OCI artifact %s has no matching layers", ref.manifest())
+	}
+	return ref, layers, nil
+}
+
+// ociSelectPlatform returns the manifest of an index for the given
+// os/arch[/variant] platform. An index with a single manifest without
+// platform selects that manifest.
+func ociSelectPlatform(manifests []ocispec.Descriptor, platform string) (ocispec.Descriptor, error) {
+	goos, arch, _ := strings.Cut(platform, "/")
+	arch, variant, _ := strings.Cut(arch, "/")
+
+	for _, desc := range manifests {
+		p := desc.Platform
+		switch {
+		case p == nil:
+			continue
+		case p.OS != goos || p.Architecture != arch:
+			continue
+		case variant != "" && p.Variant != variant:
+			continue
+		}
+		return desc, nil
+	}
+
+	if len(manifests) == 1 && manifests[0].Platform == nil {
+		return manifests[0], nil
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("OCI index has no manifest for platform %q", platform)
+}
+
+// ociSelectLayers returns the layers matching the media type and annotation,
+// if set.
+func ociSelectLayers(layers []ocispec.Descriptor, mediaType, annotation string) []ocispec.Descriptor {
+	key, value, hasValue := strings.Cut(annotation, "=")
+
+	var selected []ocispec.Descriptor
+	for _, layer := range layers {
+		if mediaType != "" && layer.MediaType != mediaType {
+			continue
+		}
+		if annotation != "" {
+			v, ok := layer.Annotations[key]
+			if !ok || (hasValue && v != value) {
+				continue
+			}
+		}
+		selected = append(selected, layer)
+	}
+	return selected
+}
+
+// getManifest returns the manifest with the given tag or digest and its media
+// type. The manifest is verified against the expected digest, if set.
+func (g *ociGetter) getManifest(ctx context.Context, ref *ociReference, tagOrDigest string, expected digest.Digest) ([]byte, string, error) {
+	resp, err := g.get(ctx, ref, ref.url("manifests", tagOrDigest), ociManifestMediaTypes)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, ociMaxManifestSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read OCI manifest: %w", err)
+	}
+	if len(body) > ociMaxManifestSize {
+		return nil, "", fmt.Errorf("OCI manifest exceeds maximum size of %d bytes", ociMaxManifestSize)
+	}
+
+	if expected != "" {
+		if err := expected.Validate(); err != nil {
+			return nil, "", fmt.Errorf("invalid OCI manifest digest: %w", err)
+		}
+		if actual := expected.Algorithm().FromBytes(body); actual != expected {
+			return nil, "", fmt.Errorf("OCI manifest digest mismatch: expected %s, got %s", expected, actual)
+		}
+	}
+
+	// registries may not set the content type, in which case the media type
+	// of the manifest is read from its content
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" || mediaType == "application/json" || mediaType == "application/octet-stream" {
+		var content struct {
+			MediaType string `json:"mediaType"`
+		}
+		_ = json.Unmarshal(body, &content)
+		mediaType = content.MediaType
+	}
+	return body, mediaType, nil
+}
+
+// writeBlob downloads a blob into the dst file, verifying its size and
+// digest. The file is removed if the blob cannot be verified.
+func (g *ociGetter) writeBlob(ctx context.Context, ref *ociReference, desc ocispec.Descriptor, dst string) (err error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return fmt.Errorf("invalid OCI layer digest: %w", err)
+	}
+	if desc.Size < 0 {
+		return fmt.Errorf("invalid size of OCI layer %s: %d", desc.Digest, desc.Size)
+	}
+	if g.maxBytes > 0 && desc.Size > g.maxBytes {
+		return fmt.Errorf("OCI layer %s size %d exceeds maximum of %d bytes", desc.Digest, desc.Size, g.maxBytes)
+	}
+
+	resp, err := g.get(ctx, ref, ref.url("blobs", desc.Digest.String()), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+
+	verifier := desc.Digest.Verifier()
+	n, err := io.Copy(io.MultiWriter(f, verifier), io.LimitReader(resp.Body, desc.Size+1))
+	switch {
+	case err != nil:
+		return fmt.Errorf("failed to download OCI layer %s: %w", desc.Digest, err)
+	case n != desc.Size:
+		return fmt.Errorf("OCI layer %s size mismatch: expected %d bytes", desc.Digest, desc.Size)
+	case !verifier.Verified():
+		return fmt.Errorf("OCI layer %s digest mismatch", desc.Digest)
+	}
+	return nil
+}
+
+// get sends a GET request to the registry, authenticating if the registry
+// responds with a challenge.
+func (g *ociGetter) get(ctx context.Context, ref *ociReference, u string, accept []string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		switch {
+		case g.token != "":
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		case g.basic:
+			req.SetBasicAuth(g.credentials.Username, g.credentials.Password)
+		}
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return resp, nil
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			if err := g.authenticate(ctx, ref, challenge); err != nil {
+				return nil, err
+			}
+		default:
+			resp.Body.Close()
+			return nil, fmt.Errorf("bad response code from OCI registry: %d", resp.StatusCode)
+		}
+	}
+}
+
+// authenticate handles the authentication challenge of the registry, getting
+// a bearer token from its token service if necessary.
+func (g *ociGetter) authenticate(ctx context.Context, ref *ociReference, challenge string) error {
+	scheme, params := parseOCIChallenge(challenge)
+	creds := g.credentials
+
+	switch scheme {
+	case "basic":
+		if creds == nil || creds.Username == "" {
+			return errors.New("OCI registry requires credentials")
+		}
+		g.basic = true
+		return nil
+
+	case "bearer":
+		if creds != nil && creds.RegistryToken != "" {
+			g.token = creds.RegistryToken
+			return nil
+		}
+
+		realm, err := url.Parse(params["realm"])
+		if err != nil || (realm.Scheme != "https" && realm.Scheme != "http") {
+			return fmt.Errorf("invalid OCI registry token realm %q", params["realm"])
+		}
+		scope := params["scope"]
+		if scope == "" {
+			scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+		}
+
+		token, err := g.getToken(ctx, realm, params["service"], scope)
+		if err != nil {
+			return fmt.Errorf("failed to get OCI registry token: %w", err)
+		}
+		g.token = token
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported OCI registry authentication scheme %q", scheme)
+	}
+}
+
+// getToken gets a bearer token from the token service of a registry. Identity
+// tokens are exchanged with an OAuth2 request, and other credentials use
+// basic authentication.
+func (g *ociGetter) getToken(ctx context.Context, realm *url.URL, service, scope string) (string, error) {
+	var req *http.Request
+	var err error
+
+	creds := g.credentials
+	if creds != nil && creds.IdentityToken != "" {
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {creds.IdentityToken},
+			"service":       {service},
+			"scope":         {scope},
+			"client_id":     {"nomad"},
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, realm.String(), strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		q := realm.Query()
+		if service != "" {
+			q.Set("service", service)
+		}
+		q.Set("scope", scope)
+		realm.RawQuery = q.Encode()
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if creds != nil && creds.Username != "" {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad response code from token service: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, ociMaxManifestSize)).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", errors.New("token service returned no token")
+}
+
+// parseOCIChallenge parses a WWW-Authenticate header into its lowercase
+// scheme and parameters.
+func parseOCIChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			v, r, _ := strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(v)
+			rest = "," + r
+		}
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ","))
+	}
+	return strings.ToLower(scheme), params
+}
@@ -0,0 +1,374 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/shoenig/test/must"
+)
+
+// testRegistry is an OCI registry serving the manifests and blobs of a single
+// repository, which requires a bearer token from its token service.
+type testRegistry struct {
+	*httptest.Server
+
+	username  string
+	password  string
+	manifests map[string][]byte
+	types     map[string]string
+	blobs     map[digest.Digest][]byte
+}
+
+func newTestRegistry(t *testing.T, username, password string) *testRegistry {
+	r := &testRegistry{
+		username:  username,
+		password:  password,
+		manifests: make(map[string][]byte),
+		types:     make(map[string]string),
+		blobs:     make(map[digest.Digest][]byte),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		if u, p, _ := req.BasicAuth(); u != r.username || p != r.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Query().Get("scope") != "repository:org/app:pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "secret"})
+	})
+	mux.HandleFunc("/v2/org/app/", func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="test",scope="repository:org/app:pull"`, r.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		kind, ref, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/org/app/"), "/")
+		switch kind {
+		case "manifests":
+			b, ok := r.manifests[ref]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", r.types[ref])
+			_, _ = w.Write(b)
+		case "blobs":
+			b, ok := r.blobs[digest.Digest(ref)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(b)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	r.Server = httptest.NewTLSServer(mux)
+	t.Cleanup(r.Close)
+	return r
+}
+
+// blob adds a blob and returns its descriptor.
+func (r *testRegistry) blob(mediaType string, b []byte, annotations map[string]string) ocispec.Descriptor {
+	d := digest.FromBytes(b)
+	r.blobs[d] = b
+	return ocispec.Descriptor{
+		MediaType:   mediaType,
+		Digest:      d,
+		Size:        int64(len(b)),
+		Annotations: annotations,
+	}
+}
+
+// manifest adds a manifest by digest and the given tag, and returns its
+// descriptor.
+func (r *testRegistry) manifest(t *testing.T, tag, mediaType string, v any) ocispec.Descriptor {
+	b, err := json.Marshal(v)
+	must.NoError(t, err)
+
+	d := digest.FromBytes(b)
+	for _, ref := range []string{d.String(), tag} {
+		if ref != "" {
+			r.manifests[ref] = b
+			r.types[ref] = mediaType
+		}
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(b))}
+}
+
+// host returns the host of the registry, used in the source of artifacts.
+func (r *testRegistry) host() string {
+	return strings.TrimPrefix(r.URL, "https://")
+}
+
+// testOCIArtifact pushes an artifact with a tarball layer and a config file
+// layer to the registry and returns the descriptor of its manifest.
+func testOCIArtifact(t *testing.T, r *testRegistry) ocispec.Descriptor {
+	config := r.blob(ocispec.MediaTypeEmptyJSON, []byte("{}"), nil)
+	bundle := r.blob(ocispec.MediaTypeImageLayerGzip, testTarGz(t, "bin/app", []byte("binary")), nil)
+	file := r.blob("application/vnd.example.config", []byte("port = 8080"), map[string]string{
+		ocispec.AnnotationTitle: "app.hcl",
+		"example.com/role":      "config",
+	})
+
+	return r.manifest(t, "v1", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{bundle, file},
+	})
+}
+
+func testOCIGetter(r *testRegistry, creds *ociCredentials) *ociGetter {
+	g := newOCIGetter(&parameters{Insecure: true, OCICredentials: creds})
+	g.SetClient(&getter.Client{
+		Decompressors: getter.LimitedDecompressors(10, 1<<20),
+	})
+	return g
+}
+
+func testOCIURL(t *testing.T, source string) *url.URL {
+	u, err := url.Parse(source)
+	must.NoError(t, err)
+	return u
+}
+
+func TestOCIGetter_Get(t *testing.T) {
+	ci.Parallel(t)
+
+	r := newTestRegistry(t, "user", "pass")
+	manifest := testOCIArtifact(t, r)
+	creds := &ociCredentials{Username: "user", Password: "pass"}
+
+	cases := []struct {
+		name   string
+		source string
+		files  map[string]string
+	}{
+		{
+			name:   "tag",
+			source: "oci://" + r.host() + "/org/app:v1",
+			files:  map[string]string{"bin/app": "binary", "app.hcl": "port = 8080"},
+		},
+		{
+			name:   "digest",
+			source: "oci://" + r.host() + "/org/app:v1@" + manifest.Digest.String(),
+			files:  map[string]string{"bin/app": "binary", "app.hcl": "port = 8080"},
+		},
+		{
+			name:   "media type",
+			source: "oci://" + r.host() + "/org/app:v1?media_type=" + url.QueryEscape(ocispec.MediaTypeImageLayerGzip),
+			files:  map[string]string{"bin/app": "binary"},
+		},
+		{
+			name:   "annotation",
+			source: "oci://" + r.host() + "/org/app:v1?annotation=" + url.QueryEscape("example.com/role=config"),
+			files:  map[string]string{"app.hcl": "port = 8080"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "out")
+			err := testOCIGetter(r, creds).Get(dst, testOCIURL(t, tc.source))
+			must.NoError(t, err)
+
+			for name, content := range tc.files {
+				b, err := os.ReadFile(filepath.Join(dst, name))
+				must.NoError(t, err)
+				must.Eq(t, content, string(b))
+			}
+			entries, err := os.ReadDir(dst)
+			must.NoError(t, err)
+			must.Len(t, len(tc.files), entries)
+		})
+	}
+}
+
+func TestOCIGetter_GetFile(t *testing.T) {
+	ci.Parallel(t)
+
+	r := newTestRegistry(t, "user", "pass")
+	testOCIArtifact(t, r)
+	creds := &ociCredentials{Username: "user", Password: "pass"}
+	dst := filepath.Join(t.TempDir(), "app.hcl")
+
+	// file mode requires a single layer
+	source := "oci://" + r.host() + "/org/app:v1"
+	err := testOCIGetter(r, creds).GetFile(dst, testOCIURL(t, source))
+	must.ErrorContains(t, err, "has 2 matching layers")
+
+	source += "?media_type=application/vnd.example.config"
+	err = testOCIGetter(r, creds).GetFile(dst, testOCIURL(t, source))
+	must.NoError(t, err)
+
+	b, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, "port = 8080", string(b))
+}
+
+func TestOCIGetter_Get_index(t *testing.T) {
+	ci.Parallel(t)
+
+	r := newTestRegistry(t, "", "")
+	var manifests []ocispec.Descriptor
+	for _, arch := range []string{"amd64", "arm64"} {
+		layer := r.blob("application/octet-stream", []byte(arch), map[string]string{
+			ocispec.AnnotationTitle: "arch",
+		})
+		desc := r.manifest(t, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    r.blob(ocispec.MediaTypeEmptyJSON, []byte("{}"), nil),
+			Layers:    []ocispec.Descriptor{layer},
+		})
+		desc.Platform = &ocispec.Platform{OS: "linux", Architecture: arch}
+		manifests = append(manifests, desc)
+	}
+	r.manifest(t, "v1", ocispec.MediaTypeImageIndex, ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+
+	// anonymous pulls still get a token
+	dst := t.TempDir()
+	source := "oci://" + r.host() + "/org/app:v1?platform=linux/arm64"
+	err := testOCIGetter(r, nil).Get(dst, testOCIURL(t, source))
+	must.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(dst, "arch"))
+	must.NoError(t, err)
+	must.Eq(t, "arm64", string(b))
+
+	source = "oci://" + r.host() + "/org/app:v1?platform=windows/amd64"
+	err = testOCIGetter(r, nil).Get(t.TempDir(), testOCIURL(t, source))
+	must.ErrorContains(t, err, `no manifest for platform "windows/amd64"`)
+}
+
+func TestOCIGetter_Get_verify(t *testing.T) {
+	ci.Parallel(t)
+
+	r := newTestRegistry(t, "user", "pass")
+	manifest := testOCIArtifact(t, r)
+	creds := &ociCredentials{Username: "user", Password: "pass"}
+
+	// manifest does not match the digest of the reference
+	other := digest.FromString("other")
+	r.manifests[other.String()] = r.manifests[manifest.Digest.String()]
+	source := "oci://" + r.host() + "/org/app@" + other.String()
+	err := testOCIGetter(r, creds).Get(t.TempDir(), testOCIURL(t, source))
+	must.ErrorContains(t, err, "OCI manifest digest mismatch")
+
+	// blob does not match its digest
+	for d, b := range r.blobs {
+		r.blobs[d] = bytes.ToUpper(b)
+	}
+	dst := t.TempDir()
+	source = "oci://" + r.host() + "/org/app:v1?media_type=application/vnd.example.config"
+	err = testOCIGetter(r, creds).Get(dst, testOCIURL(t, source))
+	must.ErrorContains(t, err, "digest mismatch")
+	must.FileNotExists(t, filepath.Join(dst, "app.hcl"))
+
+	// wrong credentials
+	err = testOCIGetter(r, &ociCredentials{Username: "user", Password: "wrong"}).
+		Get(t.TempDir(), testOCIURL(t, source))
+	must.ErrorContains(t, err, "failed to get OCI registry token")
+}
+
+func TestOCIGetter_invalidTitle(t *testing.T) {
+	ci.Parallel(t)
+
+	r := newTestRegistry(t, "", "")
+	layer := r.blob("application/octet-stream", []byte("escape"), map[string]string{
+		ocispec.AnnotationTitle: "../escape",
+	})
+	r.manifest(t, "v1", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.blob(ocispec.MediaTypeEmptyJSON, []byte("{}"), nil),
+		Layers:    []ocispec.Descriptor{layer},
+	})
+
+	source := "oci://" + r.host() + "/org/app:v1"
+	err := testOCIGetter(r, nil).Get(t.TempDir(), testOCIURL(t, source))
+	must.ErrorContains(t, err, `has invalid title "../escape"`)
+}
+
+func TestParseOCIReference(t *testing.T) {
+	ci.Parallel(t)
+
+	d := digest.FromString("manifest")
+	cases := []struct {
+		source string
+		exp    *ociReference
+		err    string
+	}{
+		{
+			source: "oci://registry.example.com/org/app:v1",
+			exp:    &ociReference{Registry: "registry.example.com", Repository: "org/app", Tag: "v1"},
+		},
+		{
+			source: "oci://localhost:5000/app",
+			exp:    &ociReference{Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+		},
+		{
+			source: "oci://registry.example.com/app:v1@" + d.String(),
+			exp:    &ociReference{Registry: "registry.example.com", Repository: "app", Tag: "v1", Digest: d},
+		},
+		{
+			source: "oci://registry.example.com/App",
+			err:    "invalid OCI reference",
+		},
+		{
+			source: "oci://registry.example.com",
+			err:    "invalid OCI reference",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.source, func(t *testing.T) {
+			ref, err := parseOCIReference(testOCIURL(t, tc.source))
+			if tc.err != "" {
+				must.ErrorContains(t, err, tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, ref)
+		})
+	}
+}
+
+func TestParseOCIChallenge(t *testing.T) {
+	ci.Parallel(t)
+
+	scheme, params := parseOCIChallenge(`Bearer realm="https://auth.example.com/token",service="registry",scope="repository:app:pull,push"`)
+	must.Eq(t, "bearer", scheme)
+	must.Eq(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry",
+		"scope":   "repository:app:pull,push",
+	}, params)
+
+	scheme, params = parseOCIChallenge(`Basic realm=registry`)
+	must.Eq(t, "basic", scheme)
+	must.Eq(t, map[string]string{"realm": "registry"}, params)
+}
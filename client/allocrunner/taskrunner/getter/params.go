@@ -5,14 +5,17 @@ package getter
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"io"
 	"io/fs"
 	"maps"
+	"net/http"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-getter"
 	"github.com/hashicorp/nomad/helper"
 )
@@ -42,6 +45,20 @@ type parameters struct {
 	Destination string              `json:"artifact_destination"`
 	Headers     map[string][]string `json:"artifact_headers"`
 
+	// Signature is set when the signature of the artifact must be verified
+	Signature *signatureParameters `json:"artifact_signature,omitempty"`
+
+	// Cached is set when the source is a file in the artifact cache, which is
+	// linked into the task directory instead of copied where possible
+	Cached bool `json:"artifact_cached"`
+
+	// OCICredentials are the credentials of the registry of an OCI artifact
+	OCICredentials *ociCredentials `json:"artifact_oci_credentials,omitempty"`
+
+	// ETag is set when the sub-process only requests the ETag of the source,
+	// which it writes to standard output
+	ETag bool `json:"artifact_etag"`
+
 	// Task Filesystem
 	AllocDir string `json:"alloc_dir"`
 	TaskDir  string `json:"task_dir"`
@@ -116,6 +133,14 @@ func (p *parameters) Equal(o *parameters) bool {
 		return false
 	case !maps.EqualFunc(p.Headers, o.Headers, headersCompareFn):
 		return false
+	case !p.Signature.Equal(o.Signature):
+		return false
+	case p.Cached != o.Cached:
+		return false
+	case p.ETag != o.ETag:
+		return false
+	case !p.OCICredentials.Equal(o.OCICredentials):
+		return false
 	}
 
 	return true
@@ -133,6 +158,46 @@ const (
 	umask = fs.ModeSetuid | fs.ModeSetgid
 )
 
+// getETag returns the strong ETag of the source, or an empty string if the
+// server does not provide one. The request is made with the same transport
+// settings as go-getter uses for the download.
+func (p *parameters) getETag(ctx context.Context) (string, error) {
+	timeout := cacheETagTimeout
+	if p.HTTPReadTimeout > 0 {
+		timeout = min(timeout, p.HTTPReadTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, p.Source, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = http.Header(p.Headers).Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	client := cleanhttp.DefaultClient()
+	if p.Insecure {
+		transport := cleanhttp.DefaultTransport()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		client.Transport = transport
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || strings.HasPrefix(etag, "W/") {
+		return "", nil
+	}
+	return etag, nil
+}
+
 func (p *parameters) client(ctx context.Context) *getter.Client {
 	httpGetter := &getter.HttpGetter{
 		Netrc:  true,
@@ -162,6 +227,29 @@ func (p *parameters) client(ctx context.Context) *getter.Client {
 		p.DecompressionLimitSize,
 	)
 
+	getters := map[string]getter.Getter{
+		"git": &getter.GitGetter{
+			Timeout: p.GitTimeout,
+		},
+		"hg": &getter.HgGetter{
+			Timeout: p.HgTimeout,
+		},
+		"gcs": &getter.GCSGetter{
+			Timeout: p.GCSTimeout,
+		},
+		"s3": &getter.S3Getter{
+			Timeout: p.S3Timeout,
+		},
+		"http":  httpGetter,
+		"https": httpGetter,
+		"oci":   newOCIGetter(p),
+	}
+
+	// artifacts from the cache are the only local files that can be fetched
+	if p.Cached {
+		getters["file"] = newLinkGetter()
+	}
+
 	return &getter.Client{
 		Ctx:             ctx,
 		Src:             p.Source,
@@ -171,21 +259,6 @@ func (p *parameters) client(ctx context.Context) *getter.Client {
 		Umask:           umask,
 		DisableSymlinks: true,
 		Decompressors:   decompressors,
-		Getters: map[string]getter.Getter{
-			"git": &getter.GitGetter{
-				Timeout: p.GitTimeout,
-			},
-			"hg": &getter.HgGetter{
-				Timeout: p.HgTimeout,
-			},
-			"gcs": &getter.GCSGetter{
-				Timeout: p.GCSTimeout,
-			},
-			"s3": &getter.S3Getter{
-				Timeout: p.S3Timeout,
-			},
-			"http":  httpGetter,
-			"https": httpGetter,
-		},
+		Getters:         getters,
 	}
 }
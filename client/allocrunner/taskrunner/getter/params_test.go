@@ -38,6 +38,8 @@ const paramsAsJSON = `
   "artifact_headers": {
     "X-Nomad-Artifact": ["hi"]
   },
+  "artifact_cached": false,
+  "artifact_etag": false,
   "alloc_dir": "/path/to/alloc",
   "task_dir": "/path/to/alloc/task"
 }`
@@ -4,17 +4,36 @@
 package getter
 
 import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-getter"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
-// New creates a Sandbox with the given ArtifactConfig.
-func New(ac *config.ArtifactConfig, logger hclog.Logger) *Sandbox {
+const (
+	// cacheETagTimeout is the maximum duration of the request for the ETag of
+	// an artifact that may be cached.
+	cacheETagTimeout = 30 * time.Second
+)
+
+// New creates a Sandbox with the given ArtifactConfig. Artifacts are stored
+// in the cache, if not nil, and shared between tasks.
+func New(ac *config.ArtifactConfig, cache *Cache, logger hclog.Logger) *Sandbox {
 	return &Sandbox{
 		logger: logger.Named("artifact"),
 		ac:     ac,
+		cache:  cache,
 	}
 }
 
@@ -22,27 +41,107 @@ func New(ac *config.ArtifactConfig, logger hclog.Logger) *Sandbox {
 type Sandbox struct {
 	logger hclog.Logger
 	ac     *config.ArtifactConfig
+	cache  *Cache
 }
 
 func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact) error {
 	s.logger.Debug("get", "source", artifact.GetterSource, "destination", artifact.RelativeDest)
 
-	source, err := getURL(env, artifact)
+	params, err := s.getParameters(env, artifact)
 	if err != nil {
 		return err
 	}
 
-	destination, err := getDestination(env, artifact)
+	if key, etag := s.getCacheKey(params); key != "" {
+		return s.getCached(params, key, etag)
+	}
+
+	if err = s.runCmd(params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Prefetch downloads the artifact into the artifact cache before any task
+// using it is placed on the client. The source of the artifact is not
+// interpolated. Artifacts which cannot be cached return an error wrapping
+// ErrNotCacheable.
+func (s *Sandbox) Prefetch(artifact *structs.TaskArtifact) error {
+	s.logger.Debug("prefetch", "source", artifact.GetterSource)
+
+	if s.cache == nil {
+		return fmt.Errorf("%w: the artifact cache is disabled", ErrNotCacheable)
+	}
+
+	// the getter needs a task directory, so use a scratch directory within
+	// the artifact cache which is removed on restore if left behind
+	scratch, err := os.MkdirTemp(s.cache.dir, cacheStagingPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create prefetch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	env := &prefetchEnv{taskDir: filepath.Join(scratch, "local")}
+	if err := os.MkdirAll(filepath.Join(env.taskDir, "tmp"), 0o755); err != nil {
+		return fmt.Errorf("failed to create prefetch directory: %w", err)
+	}
+
+	params, err := s.getParameters(env, artifact)
 	if err != nil {
 		return err
 	}
 
+	key, etag := s.getCacheKey(params)
+	if key == "" {
+		return fmt.Errorf("%w: only unsigned files downloaded over HTTP with a checksum or ETag are cached", ErrNotCacheable)
+	}
+
+	raw, _, _, name, err := splitArchiveSource(params.Source)
+	if err != nil {
+		return &Error{
+			URL:         params.Source,
+			Err:         err,
+			Recoverable: false,
+		}
+	}
+
+	entry, err := s.fillCache(params, raw, name, key, etag)
+	if err != nil {
+		return err
+	}
+	s.cache.release(entry)
+	return nil
+}
+
+// getParameters returns the parameters of the getter subprocess downloading
+// the artifact into the task directory.
+func (s *Sandbox) getParameters(env interfaces.EnvReplacer, artifact *structs.TaskArtifact) (*parameters, error) {
+	signature, err := s.getSignature(env, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := getURL(env, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	ociCredentials, err := s.getOCICredentials(source)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := getDestination(env, artifact)
+	if err != nil {
+		return nil, err
+	}
+
 	mode := getMode(artifact)
 	insecure := isInsecure(artifact)
 	headers := getHeaders(env, artifact)
 	allocDir, taskDir := getWritableDirs(env)
 
-	params := &parameters{
+	return &parameters{
 		// downloader configuration
 		HTTPReadTimeout:               s.ac.HTTPReadTimeout,
 		HTTPMaxBytes:                  s.ac.HTTPMaxBytes,
@@ -62,14 +161,224 @@ func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact
 		Source:      source,
 		Destination: destination,
 		Headers:     headers,
+		Signature:   signature,
+
+		OCICredentials: ociCredentials,
 
 		// task filesystem
 		AllocDir: allocDir,
 		TaskDir:  taskDir,
+	}, nil
+}
+
+// getSignature returns the parameters used to verify the signature of the
+// artifact, or nil if the artifact is not signed. Unsigned artifacts are
+// rejected if the client requires signatures.
+func (s *Sandbox) getSignature(env interfaces.EnvReplacer, artifact *structs.TaskArtifact) (*signatureParameters, error) {
+	sig := artifact.Signature
+	if sig == nil {
+		if s.ac.RequireSignature {
+			return nil, &Error{
+				URL:         artifact.GetterSource,
+				Err:         fmt.Errorf("%w: artifact is not signed and the client requires signatures", ErrVerification),
+				Recoverable: false,
+			}
+		}
+		return nil, nil
 	}
 
-	if err = s.runCmd(params); err != nil {
+	keys := make(map[string]string, len(s.ac.TrustedKeys))
+	for name, key := range s.ac.TrustedKeys {
+		if len(sig.Keys) == 0 || slices.Contains(sig.Keys, name) {
+			keys[name] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, &Error{
+			URL:         artifact.GetterSource,
+			Err:         fmt.Errorf("%w: no trusted keys configured on the client can verify the signature", ErrVerification),
+			Recoverable: false,
+		}
+	}
+
+	return &signatureParameters{
+		Source: getSignatureURL(env, artifact),
+		Format: sig.Format,
+		Keys:   keys,
+	}, nil
+}
+
+// getOCICredentials returns the credentials for the registry of an OCI
+// artifact, or nil if the artifact is not from an OCI registry or the client
+// has no credentials for it. Credentials set in the client configuration take
+// precedence over those of the Docker-style credentials file.
+func (s *Sandbox) getOCICredentials(source string) (*ociCredentials, error) {
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme != "oci" {
+		return nil, nil
+	}
+
+	if auth, ok := s.ac.OCIAuth[u.Host]; ok {
+		username, password, _ := strings.Cut(auth, ":")
+		return &ociCredentials{Username: username, Password: password}, nil
+	}
+
+	if s.ac.OCIAuthFile == "" {
+		return nil, nil
+	}
+
+	auth, err := loadOCIAuthFile(s.ac.OCIAuthFile, u.Host)
+	if err != nil {
+		return nil, &Error{
+			URL:         redactSource(source),
+			Err:         fmt.Errorf("failed to read OCI registry credentials: %w", err),
+			Recoverable: false,
+		}
+	}
+	return auth, nil
+}
+
+// getCacheKey returns the key of the artifact in the artifact cache, or an
+// empty key if the artifact cannot be cached. Only files downloaded over HTTP
+// are cached, identified by their checksum or else by the ETag of the source.
+func (s *Sandbox) getCacheKey(params *parameters) (key, etag string) {
+	if s.cache == nil || params.Signature != nil || params.Mode == getter.ClientModeDir {
+		return "", ""
+	}
+
+	u, err := url.Parse(params.Source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", ""
+	}
+
+	// the archive and filename options only affect how the artifact is
+	// unpacked into the task directory
+	q := u.Query()
+	checksum := q.Get("checksum")
+	q.Del("checksum")
+	q.Del("archive")
+	q.Del("filename")
+	u.RawQuery = q.Encode()
+	source := u.String()
+
+	// checksums read from a file do not pin the content of the artifact
+	if checksum != "" && !strings.HasPrefix(checksum, "file:") {
+		return cacheKey(source, "checksum:"+checksum), ""
+	}
+
+	etag = s.getETag(source, params)
+	if etag == "" {
+		return "", ""
+	}
+	return cacheKey(source, "etag:"+etag), etag
+}
+
+// getETag returns the strong ETag of the source, or an empty string if the
+// server does not provide one. The ETag is requested by the getter
+// sub-process, so that the request is made with the same environment, such as
+// proxy settings, and filesystem isolation as the download.
+func (s *Sandbox) getETag(source string, params *parameters) string {
+	probe := *params
+	probe.Source = source
+	probe.ETag = true
+
+	stdout := new(bytes.Buffer)
+	if err := s.runCmdOutput(&probe, stdout); err != nil {
+		s.logger.Debug("failed to get ETag of artifact", "error", err)
+		return ""
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+// getCached downloads the artifact into the cache unless it is already
+// cached, and then places it into the task directory.
+func (s *Sandbox) getCached(params *parameters, key, etag string) error {
+	raw, archive, filename, name, err := splitArchiveSource(params.Source)
+	if err != nil {
+		return &Error{
+			URL:         params.Source,
+			Err:         err,
+			Recoverable: false,
+		}
+	}
+
+	entry, err := s.fillCache(params, raw, name, key, etag)
+	if err != nil {
 		return err
 	}
-	return nil
+	defer s.cache.release(entry)
+
+	q := url.Values{}
+	if archive != "" {
+		q.Set("archive", archive)
+	}
+	if filename != "" {
+		q.Set("filename", filename)
+	}
+	source := entry.path(s.cache.dir)
+	if len(q) > 0 {
+		source += "?" + q.Encode()
+	}
+
+	params.Source = source
+	params.Headers = nil
+	params.Cached = true
+	params.FilesystemIsolationExtraPaths = append(
+		slices.Clone(params.FilesystemIsolationExtraPaths),
+		"d:r:"+filepath.Join(s.cache.dir, entry.Key),
+	)
+	return s.runCmd(params)
+}
+
+// fillCache acquires the cache entry of the artifact, downloading the raw
+// source into the cache as name unless it is already cached. The caller must
+// release the entry.
+func (s *Sandbox) fillCache(params *parameters, raw, name, key, etag string) (*cacheEntry, error) {
+	return s.cache.acquire(key, redactSource(raw), func(dataDir string) (string, error) {
+		fill := *params
+		fill.Source = raw
+		fill.Mode = getter.ClientModeFile
+		fill.Destination = filepath.Join(dataDir, name)
+		fill.FilesystemIsolationExtraPaths = append(
+			slices.Clone(params.FilesystemIsolationExtraPaths),
+			"d:rwc:"+filepath.Dir(dataDir),
+		)
+
+		// fail the download if the artifact changed since its ETag was read,
+		// so it is not cached under the wrong key
+		if etag != "" {
+			fill.Headers = maps.Clone(params.Headers)
+			if fill.Headers == nil {
+				fill.Headers = make(map[string][]string, 1)
+			}
+			fill.Headers["If-Match"] = []string{etag}
+		}
+		return name, s.runCmd(&fill)
+	})
+}
+
+// redactSource removes the credentials and query of the source, which may
+// contain secrets, before it is recorded in the artifact cache.
+func redactSource(source string) string {
+	u, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
+
+// prefetchEnv is the environment of artifacts prefetched outside of a task,
+// which is not interpolated.
+type prefetchEnv struct {
+	taskDir string
+}
+
+func (e *prefetchEnv) ReplaceEnv(s string) string {
+	return s
+}
+
+func (e *prefetchEnv) ClientPath(path string, _ bool) (string, bool) {
+	return filepath.Join(e.taskDir, path), false
 }
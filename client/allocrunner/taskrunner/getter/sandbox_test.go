@@ -8,9 +8,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -36,7 +39,7 @@ func TestSandbox_Get_http(t *testing.T) {
 	logger := testlog.HCLogger(t)
 
 	ac := artifactConfig(10 * time.Second)
-	sbox := New(ac, logger)
+	sbox := New(ac, nil, logger)
 
 	_, taskDir := SetupDir(t)
 	env := noopTaskEnv(taskDir)
@@ -59,7 +62,7 @@ func TestSandbox_Get_insecure_http(t *testing.T) {
 	logger := testlog.HCLogger(t)
 
 	ac := artifactConfig(10 * time.Second)
-	sbox := New(ac, logger)
+	sbox := New(ac, nil, logger)
 
 	_, taskDir := SetupDir(t)
 	env := noopTaskEnv(taskDir)
@@ -82,3 +85,330 @@ func TestSandbox_Get_insecure_http(t *testing.T) {
 	err = sbox.Get(env, artifact)
 	must.NoError(t, err)
 }
+
+func TestSandbox_getSignature(t *testing.T) {
+	ci.Parallel(t)
+	logger := testlog.HCLogger(t)
+	env := noopTaskEnv(t.TempDir())
+
+	ac := artifactConfig(10 * time.Second)
+	ac.TrustedKeys = map[string]string{
+		"release": "release-key",
+		"nightly": "nightly-key",
+	}
+	sbox := New(ac, nil, logger)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: "https://example.com/app.tar.gz",
+	}
+
+	// Unsigned artifacts are allowed unless the client requires signatures
+	sig, err := sbox.getSignature(env, artifact)
+	must.NoError(t, err)
+	must.Nil(t, sig)
+
+	ac.RequireSignature = true
+	_, err = sbox.getSignature(env, artifact)
+	must.ErrorIs(t, err, ErrVerification)
+	must.False(t, err.(*Error).IsRecoverable())
+
+	// Signed artifacts can only be verified with the keys they allow
+	artifact.Signature = &structs.TaskArtifactSignature{
+		Format: structs.ArtifactSignatureFormatMinisign,
+		Keys:   []string{"release"},
+	}
+	sig, err = sbox.getSignature(env, artifact)
+	must.NoError(t, err)
+	must.Eq(t, &signatureParameters{
+		Source: "https://example.com/app.tar.gz.minisig",
+		Format: structs.ArtifactSignatureFormatMinisign,
+		Keys:   map[string]string{"release": "release-key"},
+	}, sig)
+
+	artifact.Signature.Keys = []string{"unknown"}
+	_, err = sbox.getSignature(env, artifact)
+	must.ErrorIs(t, err, ErrVerification)
+	must.ErrorContains(t, err, "no trusted keys")
+}
+
+func TestSandbox_getOCICredentials(t *testing.T) {
+	ci.Parallel(t)
+	logger := testlog.HCLogger(t)
+
+	authFile := filepath.Join(t.TempDir(), "config.json")
+	must.NoError(t, os.WriteFile(authFile, []byte(`{
+  "auths": {
+    "registry.example.com": {"auth": "ZmlsZTpzZWNyZXQ="},
+    "tokens.example.com": {"identitytoken": "refresh"}
+  }
+}`), 0o600))
+
+	ac := artifactConfig(10 * time.Second)
+	ac.OCIAuth = map[string]string{"registry.example.com": "config:secret:with:colons"}
+	ac.OCIAuthFile = authFile
+	sbox := New(ac, nil, logger)
+
+	// client configuration takes precedence over the credentials file
+	creds, err := sbox.getOCICredentials("oci://registry.example.com/app:v1")
+	must.NoError(t, err)
+	must.Eq(t, &ociCredentials{Username: "config", Password: "secret:with:colons"}, creds)
+
+	ac.OCIAuth = nil
+	creds, err = sbox.getOCICredentials("oci://registry.example.com/app:v1")
+	must.NoError(t, err)
+	must.Eq(t, &ociCredentials{Username: "file", Password: "secret"}, creds)
+
+	creds, err = sbox.getOCICredentials("oci://tokens.example.com/app:v1")
+	must.NoError(t, err)
+	must.Eq(t, &ociCredentials{IdentityToken: "refresh"}, creds)
+
+	creds, err = sbox.getOCICredentials("oci://unknown.example.com/app:v1")
+	must.NoError(t, err)
+	must.Nil(t, creds)
+
+	// other artifacts have no registry credentials
+	creds, err = sbox.getOCICredentials("https://registry.example.com/app.tar.gz")
+	must.NoError(t, err)
+	must.Nil(t, creds)
+
+	ac.OCIAuthFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = sbox.getOCICredentials("oci://registry.example.com/app:v1")
+	must.ErrorContains(t, err, "failed to read OCI registry credentials")
+}
+
+func TestSandbox_Get_oci(t *testing.T) {
+	testutil.RequireRoot(t)
+	logger := testlog.HCLogger(t)
+
+	r := newTestRegistry(t, "user", "pass")
+	testOCIArtifact(t, r)
+
+	ac := artifactConfig(10 * time.Second)
+	ac.DecompressionLimitFileCount = 10
+	ac.DecompressionLimitSize = 1 << 20
+	ac.OCIAuth = map[string]string{r.host(): "user:pass"}
+	sbox := New(ac, nil, logger)
+
+	_, taskDir := SetupDir(t)
+	must.NoError(t, os.Mkdir(filepath.Join(taskDir, "tmp"), 0o755))
+	env := noopTaskEnv(taskDir)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource:   "oci://" + r.host() + "/org/app:v1",
+		GetterInsecure: true,
+		RelativeDest:   "local/app",
+	}
+
+	err := sbox.Get(env, artifact)
+	must.NoError(t, err)
+
+	b, err := os.ReadFile(filepath.Join(taskDir, "local", "app", "bin", "app"))
+	must.NoError(t, err)
+	must.Eq(t, "binary", string(b))
+
+	b, err = os.ReadFile(filepath.Join(taskDir, "local", "app", "app.hcl"))
+	must.NoError(t, err)
+	must.Eq(t, "port = 8080", string(b))
+}
+
+func TestSandbox_getCacheKey(t *testing.T) {
+	testutil.RequireRoot(t)
+	logger := testlog.HCLogger(t)
+
+	etag := `"v1"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/strong":
+			w.Header().Set("ETag", etag)
+		case "/auth":
+			if r.Header.Get("Authorization") == "secret" {
+				w.Header().Set("ETag", etag)
+			}
+		case "/weak":
+			w.Header().Set("ETag", "W/"+etag)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cache, err := NewCache(t.TempDir(), 1024, logger)
+	must.NoError(t, err)
+	sbox := New(artifactConfig(10*time.Second), cache, logger)
+
+	// the ETag is requested by the getter sub-process
+	allocDir, taskDir := SetupDir(t)
+	params := func(source string) *parameters {
+		return &parameters{
+			Source:          source,
+			Mode:            getter.ClientModeAny,
+			HTTPReadTimeout: sbox.ac.HTTPReadTimeout,
+			AllocDir:        allocDir,
+			TaskDir:         taskDir,
+		}
+	}
+
+	// checksums identify the content, ignoring the unpacking options
+	key, tag := sbox.getCacheKey(params("https://example.com/app.tar.gz?checksum=md5:abc"))
+	must.NotEq(t, "", key)
+	must.Eq(t, "", tag)
+	key2, _ := sbox.getCacheKey(params("https://example.com/app.tar.gz?archive=false&checksum=md5:abc"))
+	must.Eq(t, key, key2)
+	key3, _ := sbox.getCacheKey(params("https://example.com/app.tar.gz?checksum=md5:def"))
+	must.NotEq(t, key, key3)
+
+	// strong ETags identify the content
+	key, tag = sbox.getCacheKey(params(srv.URL + "/strong"))
+	must.NotEq(t, "", key)
+	must.Eq(t, etag, tag)
+
+	etag = `"v2"`
+	key2, _ = sbox.getCacheKey(params(srv.URL + "/strong"))
+	must.NotEq(t, key, key2)
+
+	// the ETag is requested with the headers of the artifact
+	key, _ = sbox.getCacheKey(params(srv.URL + "/auth"))
+	must.Eq(t, "", key)
+	p := params(srv.URL + "/auth")
+	p.Headers = map[string][]string{"Authorization": {"secret"}}
+	key, tag = sbox.getCacheKey(p)
+	must.NotEq(t, "", key)
+	must.Eq(t, etag, tag)
+
+	// artifacts without a known version are not cached
+	key, _ = sbox.getCacheKey(params(srv.URL + "/weak"))
+	must.Eq(t, "", key)
+	key, _ = sbox.getCacheKey(params(srv.URL + "/none"))
+	must.Eq(t, "", key)
+	key, _ = sbox.getCacheKey(params(srv.URL + "/none?checksum=file:" + srv.URL + "/SHA256SUMS"))
+	must.Eq(t, "", key)
+
+	// only files downloaded over HTTP are cached
+	key, _ = sbox.getCacheKey(params("git::https://example.com/repo.git?checksum=md5:abc"))
+	must.Eq(t, "", key)
+
+	p = params("https://example.com/app.tar.gz?checksum=md5:abc")
+	p.Mode = getter.ClientModeDir
+	key, _ = sbox.getCacheKey(p)
+	must.Eq(t, "", key)
+
+	p = params("https://example.com/app.tar.gz?checksum=md5:abc")
+	p.Signature = &signatureParameters{}
+	key, _ = sbox.getCacheKey(p)
+	must.Eq(t, "", key)
+
+	// nothing is cached without a cache
+	sbox = New(artifactConfig(10*time.Second), nil, logger)
+	key, _ = sbox.getCacheKey(params("https://example.com/app.tar.gz?checksum=md5:abc"))
+	must.Eq(t, "", key)
+}
+
+func TestSandbox_Get_cached(t *testing.T) {
+	testutil.RequireRoot(t)
+	logger := testlog.HCLogger(t)
+
+	data := []byte("hello from the cache")
+	archive := testTarGz(t, "hello.txt", data)
+
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Method == http.MethodGet {
+			if match := r.Header.Get("If-Match"); match != "" && match != `"v1"` {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			downloads.Add(1)
+		}
+		switch r.URL.Path {
+		case "/file.txt":
+			_, _ = w.Write(data)
+		case "/app.tar.gz":
+			_, _ = w.Write(archive)
+		}
+	}))
+	defer srv.Close()
+
+	cache, err := NewCache(t.TempDir(), 1<<20, logger)
+	must.NoError(t, err)
+	sbox := New(artifactConfig(10*time.Second), cache, logger)
+
+	for i := 0; i < 2; i++ {
+		_, taskDir := SetupDir(t)
+		must.NoError(t, os.Mkdir(filepath.Join(taskDir, "tmp"), 0o755))
+		env := noopTaskEnv(taskDir)
+
+		must.NoError(t, sbox.Get(env, &structs.TaskArtifact{
+			GetterSource: srv.URL + "/file.txt",
+			RelativeDest: "local/downloads",
+		}))
+		b, err := os.ReadFile(filepath.Join(taskDir, "local", "downloads", "file.txt"))
+		must.NoError(t, err)
+		must.Eq(t, data, b)
+
+		must.NoError(t, sbox.Get(env, &structs.TaskArtifact{
+			GetterSource: srv.URL + "/app.tar.gz",
+			RelativeDest: "local/app",
+		}))
+		b, err = os.ReadFile(filepath.Join(taskDir, "local", "app", "hello.txt"))
+		must.NoError(t, err)
+		must.Eq(t, data, b)
+	}
+
+	// each artifact was downloaded once
+	must.Eq(t, 2, downloads.Load())
+	must.Len(t, 2, cache.List().Entries)
+}
+
+func TestSandbox_Prefetch(t *testing.T) {
+	testutil.RequireRoot(t)
+	logger := testlog.HCLogger(t)
+
+	data := []byte("hello from the prefetch")
+
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Method == http.MethodGet {
+			downloads.Add(1)
+		}
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	// artifacts are not prefetched without a cache
+	sbox := New(artifactConfig(10*time.Second), nil, logger)
+	err := sbox.Prefetch(&structs.TaskArtifact{GetterSource: srv.URL + "/file.txt"})
+	must.ErrorIs(t, err, ErrNotCacheable)
+
+	cache, err := NewCache(t.TempDir(), 1<<20, logger)
+	must.NoError(t, err)
+	sbox = New(artifactConfig(10*time.Second), cache, logger)
+
+	// directories are not cached
+	err = sbox.Prefetch(&structs.TaskArtifact{
+		GetterSource: srv.URL + "/file.txt",
+		GetterMode:   structs.GetterModeDir,
+	})
+	must.ErrorIs(t, err, ErrNotCacheable)
+
+	must.NoError(t, sbox.Prefetch(&structs.TaskArtifact{GetterSource: srv.URL + "/file.txt"}))
+	must.Eq(t, 1, downloads.Load())
+	must.Len(t, 1, cache.List().Entries)
+
+	// the prefetched artifact is used by tasks
+	_, taskDir := SetupDir(t)
+	must.NoError(t, os.Mkdir(filepath.Join(taskDir, "tmp"), 0o755))
+	must.NoError(t, sbox.Get(noopTaskEnv(taskDir), &structs.TaskArtifact{
+		GetterSource: srv.URL + "/file.txt",
+		RelativeDest: "local/downloads",
+	}))
+	b, err := os.ReadFile(filepath.Join(taskDir, "local", "downloads", "file.txt"))
+	must.NoError(t, err)
+	must.Eq(t, data, b)
+	must.Eq(t, 1, downloads.Load())
+
+	// no scratch directories are left behind
+	files, err := os.ReadDir(cache.dir)
+	must.NoError(t, err)
+	must.Len(t, 1, files)
+}
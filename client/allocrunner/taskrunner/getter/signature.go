@@ -0,0 +1,363 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/crypto/blake2b"
+)
+
+// signatureParameters is the part of the parameters of the getter sub-process
+// used to verify the signature of an artifact.
+type signatureParameters struct {
+	Source string `json:"source"`
+	Format string `json:"format"`
+
+	// Keys maps the names of the trusted keys allowed to verify the signature
+	// to the contents of their public key files.
+	Keys map[string]string `json:"keys"`
+}
+
+func (s *signatureParameters) Equal(o *signatureParameters) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	switch {
+	case s.Source != o.Source:
+		return false
+	case s.Format != o.Format:
+		return false
+	case !maps.Equal(s.Keys, o.Keys):
+		return false
+	}
+	return true
+}
+
+const (
+	// minisignAlgPrehashed is the signature algorithm of minisign signatures
+	// of the BLAKE2b-512 hash of the file, the default since minisign 0.8
+	minisignAlgPrehashed = "ED"
+
+	// minisignAlgLegacy is the signature algorithm of minisign public keys
+	// and of legacy signatures of the whole file
+	minisignAlgLegacy = "Ed"
+
+	minisignKeyIDLen = 8
+)
+
+// minisignKey is a minisign public key.
+type minisignKey struct {
+	id  [minisignKeyIDLen]byte
+	key ed25519.PublicKey
+}
+
+// minisignSignature is a minisign signature file.
+type minisignSignature struct {
+	alg             string
+	keyID           [minisignKeyIDLen]byte
+	signature       []byte
+	trustedComment  string
+	globalSignature []byte
+}
+
+// minisignLines returns the lines of a minisign file which are not untrusted
+// comments.
+func minisignLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "untrusted comment:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseMinisignKey parses a minisign public key file, or the base64 encoded
+// public key printed by "minisign -P".
+func parseMinisignKey(data []byte) (*minisignKey, error) {
+	lines := minisignLines(data)
+	if len(lines) != 1 {
+		return nil, errors.New("not a minisign public key")
+	}
+	b, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(b) != 2+minisignKeyIDLen+ed25519.PublicKeySize {
+		return nil, errors.New("not a minisign public key")
+	}
+	if string(b[:2]) != minisignAlgLegacy {
+		return nil, fmt.Errorf("unsupported minisign key algorithm %q", b[:2])
+	}
+
+	k := &minisignKey{key: ed25519.PublicKey(b[2+minisignKeyIDLen:])}
+	copy(k.id[:], b[2:2+minisignKeyIDLen])
+	return k, nil
+}
+
+// parseMinisignSignature parses a minisign signature file.
+func parseMinisignSignature(data []byte) (*minisignSignature, error) {
+	lines := minisignLines(data)
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "trusted comment: ") {
+		return nil, errors.New("malformed minisign signature")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(b) != 2+minisignKeyIDLen+ed25519.SignatureSize {
+		return nil, errors.New("malformed minisign signature")
+	}
+	global, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return nil, errors.New("malformed minisign global signature")
+	}
+
+	s := &minisignSignature{
+		alg:             string(b[:2]),
+		signature:       b[2+minisignKeyIDLen:],
+		trustedComment:  strings.TrimPrefix(lines[1], "trusted comment: "),
+		globalSignature: global,
+	}
+	copy(s.keyID[:], b[2:2+minisignKeyIDLen])
+	return s, nil
+}
+
+// parseCosignKey parses a PEM encoded ECDSA public key as created by
+// "cosign generate-key-pair".
+func parseCosignKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("not a PEM encoded public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return key, nil
+}
+
+// artifactDigests holds the digests of an artifact used by the supported
+// signature formats.
+type artifactDigests struct {
+	blake2b512 []byte
+	sha256     []byte
+}
+
+func digestArtifact(path string) (*artifactDigests, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b2, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, err
+	}
+	s256 := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(b2, s256), f); err != nil {
+		return nil, err
+	}
+	return &artifactDigests{
+		blake2b512: b2.Sum(nil),
+		sha256:     s256.Sum(nil),
+	}, nil
+}
+
+// sortedKeyNames returns the names of keys in lexical order so the keys are
+// always tried in the same order.
+func sortedKeyNames(keys map[string]string) []string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// verifySignature verifies the signature of the artifact at path with one of
+// the given trusted keys, and returns the name of the key. The errors of
+// signatures that don't verify wrap ErrVerification.
+func verifySignature(format string, keys map[string]string, path string, signature []byte) (string, error) {
+	digests, err := digestArtifact(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	switch format {
+	case structs.ArtifactSignatureFormatMinisign:
+		return verifyMinisign(keys, digests, signature)
+	case structs.ArtifactSignatureFormatCosign:
+		return verifyCosign(keys, digests, signature)
+	default:
+		return "", fmt.Errorf("%w: unsupported signature format %q", ErrVerification, format)
+	}
+}
+
+func verifyMinisign(keys map[string]string, digests *artifactDigests, signature []byte) (string, error) {
+	sig, err := parseMinisignSignature(signature)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	if sig.alg != minisignAlgPrehashed {
+		return "", fmt.Errorf("%w: legacy minisign signatures are not supported, sign with prehashing", ErrVerification)
+	}
+
+	for _, name := range sortedKeyNames(keys) {
+		key, err := parseMinisignKey([]byte(keys[name]))
+		if err != nil || key.id != sig.keyID {
+			continue
+		}
+		if !ed25519.Verify(key.key, digests.blake2b512, sig.signature) {
+			return "", fmt.Errorf("%w: invalid signature for key %q", ErrVerification, name)
+		}
+		global := append(append([]byte{}, sig.signature...), sig.trustedComment...)
+		if !ed25519.Verify(key.key, global, sig.globalSignature) {
+			return "", fmt.Errorf("%w: invalid trusted comment signature for key %q", ErrVerification, name)
+		}
+		return name, nil
+	}
+	return "", fmt.Errorf("%w: signature key ID %X is not trusted", ErrVerification, sig.keyID)
+}
+
+func verifyCosign(keys map[string]string, digests *artifactDigests, signature []byte) (string, error) {
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed cosign signature", ErrVerification)
+	}
+
+	found := false
+	for _, name := range sortedKeyNames(keys) {
+		key, err := parseCosignKey([]byte(keys[name]))
+		if err != nil {
+			continue
+		}
+		found = true
+		if ecdsa.VerifyASN1(key, digests.sha256, sig) {
+			return name, nil
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%w: no trusted cosign keys", ErrVerification)
+	}
+	return "", fmt.Errorf("%w: signature does not match any trusted key", ErrVerification)
+}
+
+// splitArchiveSource returns the source of the artifact with archive
+// extraction disabled, along with the archive and filename query parameters
+// of the source and the file name of the artifact.
+func splitArchiveSource(source string) (raw, archive, filename, name string, err error) {
+	forced := ""
+	if i := strings.Index(source, "::"); i > 0 {
+		forced, source = source[:i+2], source[i+2:]
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", "", "", "", fmt.Errorf("failed to parse source URL: %w", err)
+	}
+
+	q := u.Query()
+	archive = q.Get("archive")
+	filename = q.Get("filename")
+	q.Del("filename")
+	q.Set("archive", "false")
+	u.RawQuery = q.Encode()
+
+	name = path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "artifact"
+	}
+	return forced + u.String(), archive, filename, name, nil
+}
+
+// getVerified downloads the artifact and its signature into a staging
+// directory inside the task directory, verifies the signature and then
+// extracts the artifact to its destination the same way go-getter would.
+func (p *parameters) getVerified(ctx context.Context) error {
+	staging, err := os.MkdirTemp(p.TaskDir, ".nomad-artifact-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	raw, archive, filename, name, err := splitArchiveSource(p.Source)
+	if err != nil {
+		return err
+	}
+
+	artifactPath := filepath.Join(staging, "artifact", name)
+	c := p.client(ctx)
+	c.Src = raw
+	c.Dst = artifactPath
+	c.Mode = getter.ClientModeFile
+	if err := c.Get(); err != nil {
+		return fmt.Errorf("failed to download artifact: %w", err)
+	}
+
+	signaturePath := filepath.Join(staging, "signature")
+	c = p.client(ctx)
+	c.Src = p.Signature.Source
+	c.Dst = signaturePath
+	c.Mode = getter.ClientModeFile
+	if err := c.Get(); err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+	if _, err := verifySignature(p.Signature.Format, p.Signature.Keys, artifactPath, signature); err != nil {
+		return err
+	}
+
+	// Extract the verified artifact from the staging directory, keeping the
+	// archive and filename options of the source
+	q := url.Values{}
+	if archive != "" {
+		q.Set("archive", archive)
+	}
+	if filename != "" {
+		q.Set("filename", filename)
+	}
+	src := artifactPath
+	if len(q) > 0 {
+		src += "?" + q.Encode()
+	}
+
+	c = p.client(ctx)
+	c.Src = src
+	c.Getters = map[string]getter.Getter{
+		"file": &getter.FileGetter{Copy: true},
+	}
+	if err := c.Get(); err != nil {
+		return fmt.Errorf("failed to extract artifact: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"golang.org/x/crypto/blake2b"
+)
+
+// testMinisignKey is a minisign key pair used to sign test artifacts.
+type testMinisignKey struct {
+	id   [minisignKeyIDLen]byte
+	pub  ed25519.PublicKey
+	priv ed25519.PrivateKey
+}
+
+func newTestMinisignKey(t *testing.T) *testMinisignKey {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	must.NoError(t, err)
+	k := &testMinisignKey{pub: pub, priv: priv}
+	_, err = rand.Read(k.id[:])
+	must.NoError(t, err)
+	return k
+}
+
+// publicKey returns the public key file of the key.
+func (k *testMinisignKey) publicKey() string {
+	b := append([]byte(minisignAlgLegacy), k.id[:]...)
+	b = append(b, k.pub...)
+	return fmt.Sprintf("untrusted comment: minisign public key %X\n%s\n",
+		k.id, base64.StdEncoding.EncodeToString(b))
+}
+
+// sign returns the prehashed signature file of data.
+func (k *testMinisignKey) sign(data []byte) []byte {
+	digest := blake2b.Sum512(data)
+	sig := ed25519.Sign(k.priv, digest[:])
+	comment := "timestamp:1700000000\tfile:artifact"
+	global := ed25519.Sign(k.priv, append(append([]byte{}, sig...), comment...))
+
+	b := append([]byte(minisignAlgPrehashed), k.id[:]...)
+	b = append(b, sig...)
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(b), comment, base64.StdEncoding.EncodeToString(global)))
+}
+
+// testCosignKey returns a PEM encoded ECDSA public key and a function to sign
+// data the way "cosign sign-blob" does.
+func testCosignKey(t *testing.T) (string, func([]byte) []byte) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	must.NoError(t, err)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	return string(pub), func(data []byte) []byte {
+		digest := sha256.Sum256(data)
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+		must.NoError(t, err)
+		return []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+	}
+}
+
+// testTarGz returns a gzipped tar archive holding a single file.
+func testTarGz(t *testing.T, name string, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	must.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}))
+	_, err := tw.Write(data)
+	must.NoError(t, err)
+	must.NoError(t, tw.Close())
+	must.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func writeArtifact(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "artifact")
+	must.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func TestSignature_verifyMinisign(t *testing.T) {
+	ci.Parallel(t)
+
+	data := []byte("hello world")
+	path := writeArtifact(t, data)
+
+	trusted := newTestMinisignKey(t)
+	other := newTestMinisignKey(t)
+	keys := map[string]string{"release": trusted.publicKey()}
+
+	t.Run("valid", func(t *testing.T) {
+		name, err := verifySignature(structs.ArtifactSignatureFormatMinisign, keys, path, trusted.sign(data))
+		must.NoError(t, err)
+		must.Eq(t, "release", name)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		_, err := verifySignature(structs.ArtifactSignatureFormatMinisign, keys, path, trusted.sign([]byte("hello mars")))
+		must.ErrorIs(t, err, ErrVerification)
+		must.ErrorContains(t, err, `invalid signature for key "release"`)
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		_, err := verifySignature(structs.ArtifactSignatureFormatMinisign, keys, path, other.sign(data))
+		must.ErrorIs(t, err, ErrVerification)
+		must.ErrorContains(t, err, "is not trusted")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := verifySignature(structs.ArtifactSignatureFormatMinisign, keys, path, []byte("garbage"))
+		must.ErrorIs(t, err, ErrVerification)
+	})
+}
+
+func TestSignature_verifyCosign(t *testing.T) {
+	ci.Parallel(t)
+
+	data := []byte("hello world")
+	path := writeArtifact(t, data)
+
+	pub, sign := testCosignKey(t)
+	otherPub, _ := testCosignKey(t)
+
+	t.Run("valid", func(t *testing.T) {
+		keys := map[string]string{"a": otherPub, "b": pub}
+		name, err := verifySignature(structs.ArtifactSignatureFormatCosign, keys, path, sign(data))
+		must.NoError(t, err)
+		must.Eq(t, "b", name)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		keys := map[string]string{"release": pub}
+		_, err := verifySignature(structs.ArtifactSignatureFormatCosign, keys, path, sign([]byte("hello mars")))
+		must.ErrorIs(t, err, ErrVerification)
+		must.ErrorContains(t, err, "does not match any trusted key")
+	})
+
+	t.Run("no cosign keys", func(t *testing.T) {
+		keys := map[string]string{"release": newTestMinisignKey(t).publicKey()}
+		_, err := verifySignature(structs.ArtifactSignatureFormatCosign, keys, path, sign(data))
+		must.ErrorIs(t, err, ErrVerification)
+		must.ErrorContains(t, err, "no trusted cosign keys")
+	})
+}
+
+func TestSignature_splitArchiveSource(t *testing.T) {
+	ci.Parallel(t)
+
+	raw, archive, filename, name, err := splitArchiveSource(
+		"https://example.com/app.tar.gz?archive=tgz&filename=app&checksum=sha256:abc")
+	must.NoError(t, err)
+	must.Eq(t, "https://example.com/app.tar.gz?archive=false&checksum=sha256%3Aabc", raw)
+	must.Eq(t, "tgz", archive)
+	must.Eq(t, "app", filename)
+	must.Eq(t, "app.tar.gz", name)
+
+	raw, archive, _, name, err = splitArchiveSource("s3::https://bucket.s3.amazonaws.com/app.zip")
+	must.NoError(t, err)
+	must.Eq(t, "s3::https://bucket.s3.amazonaws.com/app.zip?archive=false", raw)
+	must.Eq(t, "", archive)
+	must.Eq(t, "app.zip", name)
+}
+
+func TestParameters_getVerified(t *testing.T) {
+	ci.Parallel(t)
+
+	data := []byte("hello world")
+	key := newTestMinisignKey(t)
+
+	srcDir := t.TempDir()
+	must.NoError(t, os.WriteFile(filepath.Join(srcDir, "hello.txt"), data, 0o644))
+	must.NoError(t, os.WriteFile(filepath.Join(srcDir, "hello.txt.minisig"), key.sign(data), 0o644))
+	must.NoError(t, os.WriteFile(filepath.Join(srcDir, "bad.minisig"), key.sign([]byte("tampered")), 0o644))
+
+	archive := testTarGz(t, "inner.txt", data)
+	must.NoError(t, os.WriteFile(filepath.Join(srcDir, "hello.tar.gz"), archive, 0o644))
+	must.NoError(t, os.WriteFile(filepath.Join(srcDir, "hello.tar.gz.minisig"), key.sign(archive), 0o644))
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(srcDir)))
+	t.Cleanup(srv.Close)
+
+	get := func(t *testing.T, source, signature string) (string, error) {
+		taskDir := t.TempDir()
+		p := &parameters{
+			HTTPReadTimeout:             10 * time.Second,
+			HTTPMaxBytes:                1e6,
+			DecompressionLimitFileCount: 10,
+			DecompressionLimitSize:      1e6,
+			Mode:                        getter.ClientModeAny,
+			Source:                      srv.URL + "/" + source,
+			Destination:                 filepath.Join(taskDir, "local"),
+			TaskDir:                     taskDir,
+			Signature: &signatureParameters{
+				Source: srv.URL + "/" + signature,
+				Format: structs.ArtifactSignatureFormatMinisign,
+				Keys:   map[string]string{"release": key.publicKey()},
+			},
+		}
+		return taskDir, p.getVerified(context.Background())
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		taskDir, err := get(t, "hello.txt", "hello.txt.minisig")
+		must.NoError(t, err)
+
+		b, err := os.ReadFile(filepath.Join(taskDir, "local", "hello.txt"))
+		must.NoError(t, err)
+		must.Eq(t, data, b)
+
+		// The staging directory is removed
+		entries, err := os.ReadDir(taskDir)
+		must.NoError(t, err)
+		must.Len(t, 1, entries)
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		taskDir, err := get(t, "hello.tar.gz", "hello.tar.gz.minisig")
+		must.NoError(t, err)
+
+		b, err := os.ReadFile(filepath.Join(taskDir, "local", "inner.txt"))
+		must.NoError(t, err)
+		must.Eq(t, data, b)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		taskDir, err := get(t, "hello.txt", "bad.minisig")
+		must.ErrorIs(t, err, ErrVerification)
+		must.FileNotExists(t, filepath.Join(taskDir, "local", "hello.txt"))
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		_, err := get(t, "hello.txt", "missing.minisig")
+		must.ErrorContains(t, err, "failed to download signature")
+		must.False(t, errors.Is(err, ErrVerification))
+	})
+}
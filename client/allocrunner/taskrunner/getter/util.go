@@ -5,7 +5,9 @@ package getter
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -61,6 +63,26 @@ func getURL(taskEnv interfaces.EnvReplacer, artifact *structs.TaskArtifact) (str
 	return sourceURL, nil
 }
 
+// getSignatureURL returns the source of the signature of the artifact. If the
+// signature block has no source, the extension of the signature format is
+// appended to the source of the artifact, without its query string.
+func getSignatureURL(taskEnv interfaces.EnvReplacer, artifact *structs.TaskArtifact) string {
+	if source := artifact.Signature.Source; source != "" {
+		return taskEnv.ReplaceEnv(source)
+	}
+
+	source := taskEnv.ReplaceEnv(artifact.GetterSource)
+	if i := strings.Index(source, "?"); i >= 0 {
+		source = source[:i]
+	}
+	switch artifact.Signature.Format {
+	case structs.ArtifactSignatureFormatCosign:
+		return source + ".sig"
+	default:
+		return source + ".minisig"
+	}
+}
+
 func getDestination(env interfaces.EnvReplacer, artifact *structs.TaskArtifact) (string, error) {
 	destination, escapes := env.ClientPath(artifact.RelativeDest, true)
 	if escapes {
@@ -130,6 +152,12 @@ func environment(taskDir string, inherit string) []string {
 }
 
 func (s *Sandbox) runCmd(env *parameters) error {
+	return s.runCmdOutput(env, nil)
+}
+
+// runCmdOutput runs the getter subprocess, writing its standard output to
+// stdout instead of the logs if stdout is not nil.
+func (s *Sandbox) runCmdOutput(env *parameters, stdout io.Writer) error {
 	// find the nomad process
 	bin := subproc.Self()
 
@@ -144,11 +172,23 @@ func (s *Sandbox) runCmd(env *parameters) error {
 	cmd.Stdin = env.reader()
 	cmd.Stdout = output
 	cmd.Stderr = output
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
 
 	// start & wait for the subprocess to terminate
 	if err := cmd.Run(); err != nil {
 		msg := subproc.Log(output, s.logger.Error)
 
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == exitVerificationFailure {
+			return &Error{
+				URL:         env.Source,
+				Err:         fmt.Errorf("%w: %v", ErrVerification, msg),
+				Recoverable: false,
+			}
+		}
+
 		return &Error{
 			URL:         env.Source,
 			Err:         fmt.Errorf("getter subprocess failed: %v: %v", err, msg),
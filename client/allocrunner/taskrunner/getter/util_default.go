@@ -6,6 +6,8 @@
 package getter
 
 import (
+	"errors"
+	"io/fs"
 	"path/filepath"
 )
 
@@ -23,3 +25,8 @@ func defaultEnvironment(taskDir string) map[string]string {
 		"TMPDIR": tmpDir,
 	}
 }
+
+// reflink is not supported
+func reflink(string, string, fs.FileMode) error {
+	return errors.ErrUnsupported
+}
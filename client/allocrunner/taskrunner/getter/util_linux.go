@@ -6,6 +6,8 @@
 package getter
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -145,3 +147,26 @@ func filesForVCS(
 	}
 	return result
 }
+
+// reflink creates dst as a copy-on-write clone of src, where supported by the
+// filesystem.
+func reflink(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	err = unix.IoctlFileClone(int(out.Fd()), int(in.Fd()))
+	err = errors.Join(err, out.Close())
+	if err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return nil
+}
@@ -68,6 +68,47 @@ func TestUtil_getURL(t *testing.T) {
 	}
 }
 
+func TestUtil_getSignatureURL(t *testing.T) {
+	ci.Parallel(t)
+
+	env := noopTaskEnv("/path/to/task")
+
+	cases := []struct {
+		name      string
+		source    string
+		signature *structs.TaskArtifactSignature
+		expURL    string
+	}{{
+		name:      "minisign default",
+		source:    "https://example.com/app.tar.gz?archive=false",
+		signature: &structs.TaskArtifactSignature{Format: structs.ArtifactSignatureFormatMinisign},
+		expURL:    "https://example.com/app.tar.gz.minisig",
+	}, {
+		name:      "cosign default",
+		source:    "https://example.com/app.tar.gz",
+		signature: &structs.TaskArtifactSignature{Format: structs.ArtifactSignatureFormatCosign},
+		expURL:    "https://example.com/app.tar.gz.sig",
+	}, {
+		name:   "explicit",
+		source: "https://example.com/app.tar.gz",
+		signature: &structs.TaskArtifactSignature{
+			Source: "https://example.com/sigs/app.sig",
+			Format: structs.ArtifactSignatureFormatCosign,
+		},
+		expURL: "https://example.com/sigs/app.sig",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := getSignatureURL(env, &structs.TaskArtifact{
+				GetterSource: tc.source,
+				Signature:    tc.signature,
+			})
+			must.Eq(t, tc.expURL, result)
+		})
+	}
+}
+
 func TestUtil_getDestination(t *testing.T) {
 	ci.Parallel(t)
 
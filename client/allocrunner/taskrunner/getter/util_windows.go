@@ -6,6 +6,8 @@
 package getter
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -27,3 +29,8 @@ func defaultEnvironment(taskDir string) map[string]string {
 		"TEMP":        tmpDir,
 	}
 }
+
+// reflink is not supported
+func reflink(string, string, fs.FileMode) error {
+	return errors.ErrUnsupported
+}
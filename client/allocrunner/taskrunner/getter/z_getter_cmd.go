@@ -4,6 +4,8 @@
 package getter
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/hashicorp/nomad/helper/subproc"
@@ -13,6 +15,10 @@ const (
 	// SubCommand is the first argument to the clone of the nomad
 	// agent process for downloading artifacts.
 	SubCommand = "artifact-isolation"
+
+	// exitVerificationFailure indicates the signature of the artifact could
+	// not be verified.
+	exitVerificationFailure = 3
 )
 
 func init() {
@@ -40,11 +46,35 @@ func init() {
 			}
 		}
 
+		// only request the ETag of an artifact which may be cached
+		if env.ETag {
+			etag, err := env.getETag(ctx)
+			if err != nil {
+				subproc.Print("failed to get ETag of artifact: %v", err)
+				return subproc.ExitFailure
+			}
+			fmt.Fprintln(os.Stdout, etag)
+			return subproc.ExitSuccess
+		}
+
 		// create the go-getter client
 		// options were already transformed into url query parameters
 		// headers were already replaced and are usable now
 		c := env.client(ctx)
 
+		// download and verify signed artifacts before extracting them
+		if env.Signature != nil {
+			if err := env.getVerified(ctx); err != nil {
+				subproc.Print("%v", err)
+				if errors.Is(err, ErrVerification) {
+					return exitVerificationFailure
+				}
+				return subproc.ExitFailure
+			}
+			subproc.Print("artifact download and verification was a success")
+			return subproc.ExitSuccess
+		}
+
 		// run the go-getter client
 		if err := c.Get(); err != nil {
 			subproc.Print("failed to download artifact: %v", err)
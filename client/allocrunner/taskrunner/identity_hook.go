@@ -141,6 +141,10 @@ func (h *identityHook) watchIdentity(wid *structs.WorkloadIdentity, runCh chan s
 				h.logger.Error(err.Error())
 			}
 
+			if err := h.setX509SVID(wid, signedWID); err != nil {
+				h.logger.Error(err.Error())
+			}
+
 			// Skip ChangeMode on firstRun and notify caller it can proceed
 			if firstRun {
 				select {
@@ -242,6 +246,33 @@ func (h *identityHook) setAltToken(widspec *structs.WorkloadIdentity, rawJWT str
 	return nil
 }
 
+// setX509SVID writes the X.509 SVID of the workload identity, its private key
+// and the trust bundle to the task's secrets directory if the identity
+// requested one. The private key is written first so that consumers watching
+// the SVID never load it with a stale key.
+func (h *identityHook) setX509SVID(widspec *structs.WorkloadIdentity, signedWID *structs.SignedWorkloadIdentity) error {
+	if !widspec.X509 || len(signedWID.X509SVID) == 0 {
+		return nil
+	}
+
+	files := []struct {
+		name     string
+		contents []byte
+	}{
+		{fmt.Sprintf("nomad_%s_svid_key.pem", widspec.Name), signedWID.X509Key},
+		{fmt.Sprintf("nomad_%s_svid.pem", widspec.Name), signedWID.X509SVID},
+		{fmt.Sprintf("nomad_%s_bundle.pem", widspec.Name), signedWID.X509Bundle},
+	}
+	for _, f := range files {
+		path := filepath.Join(h.tokenDir, f.name)
+		if err := users.WriteFileFor(path, f.contents, h.task.User); err != nil {
+			return fmt.Errorf("failed to write x509 svid for identity %q: %w", widspec.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // Stop implements interfaces.TaskStopHook
 func (h *identityHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
 	h.stop()
@@ -5,6 +5,8 @@ package taskrunner
 
 import (
 	"context"
+	"crypto/tls"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -249,6 +251,72 @@ func TestIdentityHook_RenewOne(t *testing.T) {
 
 // TestIdentityHook_ErrorWriting assert Prestart returns an error if the
 // default token could not be written when requested.
+// TestIdentityHook_X509 asserts identities with x509 enabled have their SVID,
+// private key, and trust bundle written to the secrets dir.
+func TestIdentityHook_X509(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.LookupTask("web")
+	task.Identities = []*structs.WorkloadIdentity{
+		{
+			Name:     "mesh",
+			Audience: []string{"mesh"},
+			TTL:      time.Hour,
+			X509:     true,
+		},
+	}
+
+	secretsDir := t.TempDir()
+	stopCtx, stop := context.WithCancel(context.Background())
+	t.Cleanup(stop)
+
+	logger := testlog.HCLogger(t)
+	db := cstate.NewMemDB(logger)
+	mockSigner := widmgr.NewMockWIDSigner(task.Identities)
+	envBuilder := taskenv.NewBuilder(mock.Node(), alloc, nil, "global")
+	mockWIDMgr := widmgr.NewWIDMgr(mockSigner, alloc, db, logger, envBuilder)
+
+	h := &identityHook{
+		alloc:      alloc,
+		task:       task,
+		tokenDir:   secretsDir,
+		envBuilder: taskenv.NewBuilder(node, alloc, task, alloc.Job.Region),
+		ts:         &MockTokenSetter{},
+		lifecycle:  trtesting.NewMockTaskHooks(),
+		widmgr:     mockWIDMgr,
+		logger:     logger,
+		stopCtx:    stopCtx,
+		stop:       stop,
+	}
+
+	must.NoError(t, h.widmgr.Run())
+	must.NoError(t, h.Prestart(context.Background(), nil, nil))
+	t.Cleanup(func() { must.NoError(t, h.Stop(context.Background(), nil, nil)) })
+
+	// The JWT isn't written because file=false
+	must.FileNotExists(t, filepath.Join(secretsDir, "nomad_mesh.jwt"))
+
+	testutil.WaitForResult(func() (bool, error) {
+		for _, f := range []string{"nomad_mesh_svid.pem", "nomad_mesh_svid_key.pem", "nomad_mesh_bundle.pem"} {
+			if _, err := os.Stat(filepath.Join(secretsDir, f)); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("svid files not written: %v", err)
+	})
+	certPEM := testutil.MustReadFile(t, secretsDir, "nomad_mesh_svid.pem")
+	keyPEM := testutil.MustReadFile(t, secretsDir, "nomad_mesh_svid_key.pem")
+	_, err := tls.X509KeyPair(certPEM, keyPEM)
+	must.NoError(t, err)
+	must.Eq(t, mockSigner.X509Bundle(),
+		testutil.MustReadFile(t, secretsDir, "nomad_mesh_bundle.pem"))
+}
+
 func TestIdentityHook_ErrorWriting(t *testing.T) {
 	ci.Parallel(t)
 
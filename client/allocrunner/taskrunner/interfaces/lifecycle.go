@@ -28,3 +28,11 @@ type TaskLifecycle interface {
 	// to handle restored tasks; use this as an escape hatch.
 	IsRunning() bool
 }
+
+// TemplateRestarter coordinates the restarts of a task triggered by its
+// templates with the other allocations of its task group.
+type TemplateRestarter interface {
+	// Restart calls restart once the task is allowed to restart and returns
+	// its error. Events are emitted to the task while the restart waits.
+	Restart(ctx context.Context, task string, events EventEmitter, restart func() error) error
+}
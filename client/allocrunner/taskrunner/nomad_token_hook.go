@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/signals"
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	nomadTokenHookName = "nomad_token"
+
+	// nomadTokenFile is the name of the file holding the Nomad ACL token of
+	// the task inside the task's secrets and private directories
+	nomadTokenFile = "nomad_acl_token"
+
+	// nomadTokenEnv is the environment variable the token is exposed as
+	nomadTokenEnv = "NOMAD_TOKEN"
+
+	// nomadTokenBackoffBaseline and nomadTokenBackoffLimit bound the backoff
+	// between failed attempts to replace the token
+	nomadTokenBackoffBaseline = 5 * time.Second
+	nomadTokenBackoffLimit    = 3 * time.Minute
+
+	// nomadTokenMinRenewInterval is the shortest time to wait before renewing
+	// the token
+	nomadTokenMinRenewInterval = 5 * time.Second
+)
+
+type nomadTokenHookConfig struct {
+	alloc  *structs.Allocation
+	task   *structs.Task
+	region string
+
+	// aclEnabled is false when the client has ACLs disabled, in which case
+	// tasks don't need a token to call the Nomad API
+	aclEnabled bool
+
+	// rpcClient and nodeSecret are used to derive and renew the token
+	rpcClient  config.RPCer
+	nodeSecret string
+
+	lifecycle ti.TaskLifecycle
+	logger    log.Logger
+}
+
+// nomadTokenHook derives the Nomad ACL token requested by the nomad block of a
+// task, writes it to the task's secrets directory or exposes it as the
+// NOMAD_TOKEN environment variable, and renews it while the task runs. If the
+// token can't be renewed, a new token is derived and the change mode of the
+// block is applied. The servers revoke the token when the allocation stops.
+type nomadTokenHook struct {
+	config *nomadTokenHookConfig
+	block  *structs.Nomad
+	logger log.Logger
+
+	// mu protects the fields below
+	mu sync.Mutex
+
+	// token is the current token, and expiration its expiration time
+	token      *structs.ACLToken
+	expiration time.Time
+
+	// privateDirTokenPath and secretsDirTokenPath are where the token secret
+	// is written. The private copy is used to recover the token when the
+	// client restarts.
+	privateDirTokenPath string
+	secretsDirTokenPath string
+
+	// renewing is true once the renewal loop has been started
+	renewing bool
+
+	// ctx and cancel stop the renewal loop
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newNomadTokenHook(config *nomadTokenHookConfig) *nomadTokenHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &nomadTokenHook{
+		config: config,
+		block:  config.task.Nomad,
+		logger: config.logger.Named(nomadTokenHookName),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (*nomadTokenHook) Name() string {
+	return nomadTokenHookName
+}
+
+func (h *nomadTokenHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	if !h.config.aclEnabled {
+		h.logger.Debug("ACLs are disabled, skipping token derivation")
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.privateDirTokenPath = filepath.Join(req.TaskDir.PrivateDir, nomadTokenFile)
+	h.secretsDirTokenPath = filepath.Join(req.TaskDir.SecretsDir, nomadTokenFile)
+
+	// The token is kept across task restarts. When the client restarts, try
+	// to recover the token from the private directory.
+	if h.token == nil {
+		if err := h.recoverToken(); err != nil {
+			h.logger.Debug("failed to recover token", "error", err)
+		}
+	}
+	if h.token == nil {
+		token, err := h.deriveToken()
+		if err != nil {
+			return structs.NewRecoverableError(err, true)
+		}
+		h.setToken(token)
+	}
+
+	if err := h.writeToken(); err != nil {
+		return err
+	}
+	if h.block.Env {
+		resp.Env = map[string]string{nomadTokenEnv: h.token.SecretID}
+	}
+
+	if !h.renewing {
+		h.renewing = true
+		go h.renew()
+	}
+	return nil
+}
+
+func (h *nomadTokenHook) Stop(_ context.Context, _ *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
+	h.cancel()
+	return nil
+}
+
+func (h *nomadTokenHook) Shutdown() {
+	h.cancel()
+}
+
+// recoverToken reads the token secret written by a previous run of the hook
+// and renews it to check it is still valid. It must be called with the lock
+// held.
+func (h *nomadTokenHook) recoverToken() error {
+	secret, err := os.ReadFile(h.privateDirTokenPath)
+	if err != nil {
+		return err
+	}
+
+	token, err := h.renewToken(string(secret))
+	if err != nil {
+		return err
+	}
+	h.setToken(token)
+	return nil
+}
+
+// deriveToken asks the servers for a new token.
+func (h *nomadTokenHook) deriveToken() (*structs.ACLToken, error) {
+	args := structs.ACLDeriveTaskTokenRequest{
+		AllocID: h.config.alloc.ID,
+		Task:    h.config.task.Name,
+		WriteRequest: structs.WriteRequest{
+			Region:    h.config.region,
+			AuthToken: h.config.nodeSecret,
+		},
+	}
+	var reply structs.ACLDeriveTaskTokenResponse
+	if err := h.config.rpcClient.RPC(structs.ACLDeriveTaskTokenRPCMethod, &args, &reply); err != nil {
+		return nil, fmt.Errorf("failed to derive Nomad token: %w", err)
+	}
+	if reply.Token == nil {
+		return nil, errors.New("failed to derive Nomad token: server returned no token")
+	}
+	return reply.Token, nil
+}
+
+// renewToken renews the token with the given secret, using the token itself
+// to authenticate.
+func (h *nomadTokenHook) renewToken(secret string) (*structs.ACLToken, error) {
+	args := structs.ACLTokenRenewRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    h.config.region,
+			AuthToken: secret,
+		},
+	}
+	var reply structs.ACLTokenRenewResponse
+	if err := h.config.rpcClient.RPC(structs.ACLRenewTokenRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Token == nil || reply.Token.ExpirationTime == nil {
+		return nil, errors.New("server returned no token")
+	}
+	return reply.Token, nil
+}
+
+// setToken must be called with the lock held.
+func (h *nomadTokenHook) setToken(token *structs.ACLToken) {
+	h.token = token
+	if token.ExpirationTime != nil {
+		h.expiration = *token.ExpirationTime
+	}
+}
+
+// writeToken must be called with the lock held.
+func (h *nomadTokenHook) writeToken() error {
+	if err := os.WriteFile(h.privateDirTokenPath, []byte(h.token.SecretID), 0600); err != nil {
+		return fmt.Errorf("failed to write Nomad token: %w", err)
+	}
+	if !h.block.DisableFile {
+		if err := os.WriteFile(h.secretsDirTokenPath, []byte(h.token.SecretID), 0666); err != nil {
+			return fmt.Errorf("failed to write Nomad token to secrets dir: %w", err)
+		}
+	}
+	return nil
+}
+
+// renew renews the token halfway through its remaining lifetime until the hook
+// is stopped. Once the token can't be renewed anymore, a new token is derived
+// and the change mode is applied. Failures are retried with a backoff until the
+// current token expires, at which point the task is killed.
+func (h *nomadTokenHook) renew() {
+	var attempts uint64
+	for {
+		h.mu.Lock()
+		secret := h.token.SecretID
+		expiration := h.expiration
+		h.mu.Unlock()
+
+		wait := time.Until(expiration) / 2
+		if attempts > 0 {
+			wait = helper.Backoff(nomadTokenBackoffBaseline, nomadTokenBackoffLimit, attempts)
+		}
+		wait = max(wait, nomadTokenMinRenewInterval)
+
+		select {
+		case <-time.After(wait):
+		case <-h.ctx.Done():
+			return
+		}
+
+		token, err := h.renewToken(secret)
+		if err == nil {
+			attempts = 0
+			h.mu.Lock()
+			h.setToken(token)
+			h.mu.Unlock()
+			continue
+		}
+		h.logger.Debug("failed to renew token, deriving a new token", "error", err)
+
+		token, err = h.deriveToken()
+		if err != nil {
+			if time.Now().After(expiration) {
+				h.logger.Error("failed to replace expired token", "error", err)
+				_ = h.config.lifecycle.Kill(h.ctx,
+					structs.NewTaskEvent(structs.TaskKilling).
+						SetFailsTask().
+						SetDisplayMessage(fmt.Sprintf("Nomad: %v", err)))
+				return
+			}
+			attempts++
+			h.logger.Warn("failed to replace token, retrying", "error", err)
+			continue
+		}
+		attempts = 0
+
+		h.mu.Lock()
+		h.setToken(token)
+		err = h.writeToken()
+		h.mu.Unlock()
+		if err != nil {
+			h.logger.Error("failed to write new token", "error", err)
+		}
+
+		h.changeToken()
+	}
+}
+
+// changeToken applies the change mode of the nomad block once the token has
+// been replaced.
+func (h *nomadTokenHook) changeToken() {
+	switch h.block.ChangeMode {
+	case structs.NomadChangeModeRestart:
+		_ = h.config.lifecycle.Restart(h.ctx,
+			structs.NewTaskEvent(structs.TaskRestartSignal).
+				SetDisplayMessage("Nomad: new Nomad token acquired"), false)
+
+	case structs.NomadChangeModeSignal:
+		sig, err := signals.Parse(h.block.ChangeSignal)
+		if err != nil {
+			h.logger.Error("failed to parse signal", "error", err)
+			return
+		}
+		event := structs.NewTaskEvent(structs.TaskSignaling).
+			SetTaskSignal(sig).
+			SetDisplayMessage("Nomad: new Nomad token acquired")
+		if err := h.config.lifecycle.Signal(event, h.block.ChangeSignal); err != nil {
+			_ = h.config.lifecycle.Kill(h.ctx,
+				structs.NewTaskEvent(structs.TaskKilling).
+					SetFailsTask().
+					SetDisplayMessage(fmt.Sprintf("Nomad: failed to send signal: %v", err)))
+		}
+
+	case structs.NomadChangeModeNoop:
+		// True to its name, this is a noop!
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/fsisolation"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+// Statically assert the nomad token hook implements the expected interfaces
+var (
+	_ interfaces.TaskPrestartHook = (*nomadTokenHook)(nil)
+	_ interfaces.TaskStopHook     = (*nomadTokenHook)(nil)
+	_ interfaces.ShutdownHook     = (*nomadTokenHook)(nil)
+)
+
+// mockNomadTokenRPCer serves ACL.DeriveTaskToken and ACL.RenewToken requests
+// from an in-memory set of tokens.
+type mockNomadTokenRPCer struct {
+	lock     sync.Mutex
+	ttl      time.Duration
+	tokens   map[string]*structs.ACLToken
+	derived  int
+	noRenew  bool
+	noDerive bool
+}
+
+func newMockNomadTokenRPCer(ttl time.Duration) *mockNomadTokenRPCer {
+	return &mockNomadTokenRPCer{ttl: ttl, tokens: map[string]*structs.ACLToken{}}
+}
+
+func (m *mockNomadTokenRPCer) RPC(method string, args any, reply any) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch method {
+	case structs.ACLDeriveTaskTokenRPCMethod:
+		if m.noDerive {
+			return errors.New("derive failed")
+		}
+		req := args.(*structs.ACLDeriveTaskTokenRequest)
+		m.derived++
+		token := &structs.ACLToken{
+			AccessorID:     uuid.Generate(),
+			SecretID:       uuid.Generate(),
+			Type:           structs.ACLClientToken,
+			Renewable:      true,
+			AllocID:        req.AllocID,
+			ExpirationTTL:  m.ttl,
+			ExpirationTime: pointer.Of(time.Now().Add(m.ttl)),
+		}
+		m.tokens[token.SecretID] = token
+		reply.(*structs.ACLDeriveTaskTokenResponse).Token = token.Copy()
+		return nil
+
+	case structs.ACLRenewTokenRPCMethod:
+		req := args.(*structs.ACLTokenRenewRequest)
+		token, ok := m.tokens[req.AuthToken]
+		if !ok || m.noRenew {
+			return structs.ErrPermissionDenied
+		}
+		token.ExpirationTime = pointer.Of(time.Now().Add(m.ttl))
+		reply.(*structs.ACLTokenRenewResponse).Token = token.Copy()
+		return nil
+	}
+	return fmt.Errorf("unexpected RPC method %q", method)
+}
+
+func (m *mockNomadTokenRPCer) setFailures(noRenew, noDerive bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.noRenew = noRenew
+	m.noDerive = noDerive
+}
+
+func (m *mockNomadTokenRPCer) derivedTokens() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.derived
+}
+
+func testNomadTokenHook(t *testing.T, block *structs.Nomad, rpc *mockNomadTokenRPCer, taskDir *allocdir.TaskDir) (
+	*nomadTokenHook, *trtesting.MockTaskHooks, *allocdir.TaskDir) {
+	logger := testlog.HCLogger(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Nomad = block
+
+	if taskDir == nil {
+		allocDir := allocdir.NewAllocDir(logger, t.TempDir(), t.TempDir(), alloc.ID)
+		t.Cleanup(func() { _ = allocDir.Destroy() })
+		taskDir = allocDir.NewTaskDir(task.Name)
+		must.NoError(t, taskDir.Build(fsisolation.None, nil, task.User))
+	}
+
+	mockHooks := trtesting.NewMockTaskHooks()
+	h := newNomadTokenHook(&nomadTokenHookConfig{
+		alloc:      alloc,
+		task:       task,
+		region:     "global",
+		aclEnabled: true,
+		rpcClient:  rpc,
+		nodeSecret: uuid.Generate(),
+		lifecycle:  mockHooks,
+		logger:     logger,
+	})
+	t.Cleanup(func() {
+		_ = h.Stop(context.Background(), nil, nil)
+	})
+
+	return h, mockHooks, taskDir
+}
+
+func TestNomadTokenHook_Prestart(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := newMockNomadTokenRPCer(time.Hour)
+	h, _, taskDir := testNomadTokenHook(t, &structs.Nomad{
+		Roles:      []string{"ops"},
+		TTL:        time.Hour,
+		Env:        true,
+		ChangeMode: structs.NomadChangeModeRestart,
+	}, rpc, nil)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	resp := &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(context.Background(), req, resp))
+	must.Eq(t, 1, rpc.derivedTokens())
+
+	secret := h.token.SecretID
+	must.Eq(t, map[string]string{nomadTokenEnv: secret}, resp.Env)
+
+	buf, err := os.ReadFile(filepath.Join(taskDir.SecretsDir, nomadTokenFile))
+	must.NoError(t, err)
+	must.Eq(t, secret, string(buf))
+
+	// The token is kept when the task restarts
+	resp = &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(context.Background(), req, resp))
+	must.Eq(t, 1, rpc.derivedTokens())
+	must.Eq(t, secret, resp.Env[nomadTokenEnv])
+}
+
+func TestNomadTokenHook_Prestart_DisableFile(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := newMockNomadTokenRPCer(time.Hour)
+	h, _, taskDir := testNomadTokenHook(t, &structs.Nomad{
+		Roles:       []string{"ops"},
+		TTL:         time.Hour,
+		DisableFile: true,
+		ChangeMode:  structs.NomadChangeModeRestart,
+	}, rpc, nil)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	resp := &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(context.Background(), req, resp))
+	must.MapEmpty(t, resp.Env)
+	must.FileNotExists(t, filepath.Join(taskDir.SecretsDir, nomadTokenFile))
+	must.FileExists(t, filepath.Join(taskDir.PrivateDir, nomadTokenFile))
+}
+
+func TestNomadTokenHook_Prestart_ACLDisabled(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := newMockNomadTokenRPCer(time.Hour)
+	h, _, taskDir := testNomadTokenHook(t, &structs.Nomad{
+		Roles:      []string{"ops"},
+		Env:        true,
+		ChangeMode: structs.NomadChangeModeRestart,
+	}, rpc, nil)
+	h.config.aclEnabled = false
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	resp := &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(context.Background(), req, resp))
+	must.Zero(t, rpc.derivedTokens())
+	must.MapEmpty(t, resp.Env)
+}
+
+func TestNomadTokenHook_Prestart_Recover(t *testing.T) {
+	ci.Parallel(t)
+
+	block := &structs.Nomad{
+		Roles:      []string{"ops"},
+		TTL:        time.Hour,
+		ChangeMode: structs.NomadChangeModeRestart,
+	}
+	rpc := newMockNomadTokenRPCer(time.Hour)
+	h, _, taskDir := testNomadTokenHook(t, block, rpc, nil)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	must.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+	secret := h.token.SecretID
+
+	// A new hook, as created when the client restarts, recovers the token
+	// from the private directory
+	h2, _, _ := testNomadTokenHook(t, block, rpc, taskDir)
+	must.NoError(t, h2.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+	must.Eq(t, 1, rpc.derivedTokens())
+	must.Eq(t, secret, h2.token.SecretID)
+}
+
+func TestNomadTokenHook_Prestart_DeriveFailure(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := newMockNomadTokenRPCer(time.Hour)
+	rpc.setFailures(false, true)
+	h, _, taskDir := testNomadTokenHook(t, &structs.Nomad{
+		Roles:      []string{"ops"},
+		ChangeMode: structs.NomadChangeModeRestart,
+	}, rpc, nil)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	err := h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{})
+	must.ErrorContains(t, err, "failed to derive Nomad token")
+	must.True(t, structs.IsRecoverable(err))
+}
+
+func TestNomadTokenHook_Replace(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := newMockNomadTokenRPCer(time.Second)
+	h, mockHooks, taskDir := testNomadTokenHook(t, &structs.Nomad{
+		Roles:        []string{"ops"},
+		TTL:          time.Second,
+		ChangeMode:   structs.NomadChangeModeSignal,
+		ChangeSignal: "SIGHUP",
+	}, rpc, nil)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	must.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+	secret := h.token.SecretID
+
+	// Once the token can't be renewed a new token is derived and the task is
+	// signaled
+	rpc.setFailures(true, false)
+
+	select {
+	case <-mockHooks.SignalCh:
+		must.Eq(t, []string{"SIGHUP"}, mockHooks.Signals())
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected task to be signaled")
+	}
+	must.Eq(t, 2, rpc.derivedTokens())
+
+	path := filepath.Join(taskDir.SecretsDir, nomadTokenFile)
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			buf, err := os.ReadFile(path)
+			return err == nil && string(buf) != secret
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+}
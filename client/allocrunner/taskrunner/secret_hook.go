@@ -0,0 +1,404 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/signals"
+	log "github.com/hashicorp/go-hclog"
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	secretHookName = "secrets"
+
+	// secretVaultPollInterval is how often a Vault secret without a lease is
+	// read again to detect changes.
+	secretVaultPollInterval = 5 * time.Minute
+
+	// secretRetryBaseline and secretRetryMax bound the backoff between
+	// failed attempts to read a secret that is being watched.
+	secretRetryBaseline = 5 * time.Second
+	secretRetryMax      = 1 * time.Minute
+)
+
+type secretHookConfig struct {
+	alloc  *structs.Allocation
+	task   *structs.Task
+	region string
+
+	// rpcClient is used to read Nomad Variables
+	rpcClient config.RPCer
+
+	// vaultConfigsFunc returns the Vault configurations of the client
+	vaultConfigsFunc func(log.Logger) map[string]*sconfig.VaultConfig
+
+	lifecycle ti.TaskLifecycle
+	events    ti.EventEmitter
+	logger    log.Logger
+}
+
+// secretHook reads the Nomad Variables and Vault secrets of a task's secret
+// blocks, writes their items to the task's secrets directory or exposes them
+// as environment variables, and watches them for changes.
+type secretHook struct {
+	config *secretHookConfig
+	logger log.Logger
+
+	// mu protects the fields below
+	mu sync.Mutex
+
+	// nomadToken and vaultToken are the current tokens used to read secrets
+	nomadToken string
+	vaultToken string
+
+	// secretsDir is the task's secrets directory on the host
+	secretsDir string
+
+	// written holds the keys of the files written for each secret
+	written map[string][]string
+
+	// cancel stops the watchers started by the last prestart
+	cancel context.CancelFunc
+}
+
+func newSecretHook(config *secretHookConfig) *secretHook {
+	return &secretHook{
+		config: config,
+		logger: config.logger.Named(secretHookName),
+	}
+}
+
+func (*secretHook) Name() string {
+	return secretHookName
+}
+
+func (h *secretHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.mu.Lock()
+	h.stopWatchers()
+	h.nomadToken = req.NomadToken
+	h.vaultToken = req.VaultToken
+	h.secretsDir = req.TaskDir.SecretsDir
+	h.mu.Unlock()
+
+	env := map[string]string{}
+	indexes := make([]uint64, len(h.config.task.Secrets))
+	values := make([]map[string]string, len(h.config.task.Secrets))
+
+	for i, secret := range h.config.task.Secrets {
+		items, index, err := h.read(ctx, secret, 0)
+		if err != nil {
+			return structs.NewRecoverableError(
+				fmt.Errorf("failed to read secret %q: %w", secret.Name, err), true)
+		}
+
+		if secret.Env {
+			maps.Copy(env, items)
+		} else if err := h.write(secret, items); err != nil {
+			return fmt.Errorf("failed to write secret %q: %w", secret.Name, err)
+		}
+
+		indexes[i] = index
+		values[i] = items
+	}
+	resp.Env = env
+
+	// Watch the secrets until the task is restarted or stopped
+	watchCtx, cancel := context.WithCancel(context.Background())
+	h.mu.Lock()
+	h.cancel = cancel
+	h.mu.Unlock()
+
+	for i, secret := range h.config.task.Secrets {
+		go h.watch(watchCtx, secret, values[i], indexes[i])
+	}
+
+	return nil
+}
+
+// Update is used to pick up renewed Vault and Nomad tokens.
+func (h *secretHook) Update(_ context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nomadToken = req.NomadToken
+	h.vaultToken = req.VaultToken
+	return nil
+}
+
+func (h *secretHook) Stop(_ context.Context, _ *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopWatchers()
+	return nil
+}
+
+// stopWatchers must be called with the lock held.
+func (h *secretHook) stopWatchers() {
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
+
+// watch waits for the items of a secret to change and applies its change
+// mode when they do. It runs until the context is canceled.
+func (h *secretHook) watch(ctx context.Context, secret *structs.Secret, items map[string]string, index uint64) {
+	logger := h.logger.With("secret", secret.Name)
+	retry := 0
+
+	for {
+		// Vault has no blocking queries, so the secret is read again after
+		// its lease or the poll interval passes.
+		if secret.Provider == structs.SecretProviderVault {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(index) * time.Second):
+			}
+		}
+
+		newItems, newIndex, err := h.read(ctx, secret, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Warn("failed to read secret", "error", err)
+			backoff := helper.Backoff(secretRetryBaseline, secretRetryMax, uint64(retry))
+			retry++
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		retry = 0
+		index = newIndex
+
+		if maps.Equal(items, newItems) {
+			continue
+		}
+		items = newItems
+
+		if !secret.Env {
+			if err := h.write(secret, items); err != nil {
+				logger.Error("failed to write secret", "error", err)
+				h.config.events.EmitEvent(structs.NewTaskEvent(structs.TaskHookFailed).
+					SetDisplayMessage(fmt.Sprintf("Secret %q failed to render: %v", secret.Name, err)))
+				continue
+			}
+		}
+
+		h.handleChange(ctx, secret)
+	}
+}
+
+// handleChange applies the change mode of a secret whose items changed.
+func (h *secretHook) handleChange(ctx context.Context, secret *structs.Secret) {
+	switch secret.ChangeMode {
+	case structs.SecretChangeModeRestart:
+		_ = h.config.lifecycle.Restart(ctx,
+			structs.NewTaskEvent(structs.TaskRestartSignal).
+				SetDisplayMessage(fmt.Sprintf("Secret %q with change_mode restart changed", secret.Name)), false)
+
+	case structs.SecretChangeModeSignal:
+		sig, err := signals.Parse(secret.ChangeSignal)
+		if err != nil {
+			h.logger.Error("failed to parse signal", "secret", secret.Name, "error", err)
+			return
+		}
+
+		event := structs.NewTaskEvent(structs.TaskSignaling).
+			SetTaskSignal(sig).
+			SetDisplayMessage(fmt.Sprintf("Secret %q changed", secret.Name))
+		if err := h.config.lifecycle.Signal(event, secret.ChangeSignal); err != nil {
+			_ = h.config.lifecycle.Kill(context.Background(),
+				structs.NewTaskEvent(structs.TaskKilling).
+					SetFailsTask().
+					SetDisplayMessage(fmt.Sprintf("Secret %q failed to send signal %v: %v", secret.Name, sig, err)))
+		}
+	}
+}
+
+// read returns the items of a secret and the index to pass to the next read.
+// For Nomad Variables the index is the modify index used to block until the
+// variable changes, and for Vault secrets it is the number of seconds to wait
+// before reading the secret again.
+func (h *secretHook) read(ctx context.Context, secret *structs.Secret, index uint64) (map[string]string, uint64, error) {
+	switch secret.Provider {
+	case structs.SecretProviderVault:
+		return h.readVault(ctx, secret.Path)
+	default:
+		return h.readVariable(secret.Path, index)
+	}
+}
+
+func (h *secretHook) readVariable(path string, index uint64) (map[string]string, uint64, error) {
+	h.mu.Lock()
+	token := h.nomadToken
+	h.mu.Unlock()
+
+	args := structs.VariablesReadRequest{
+		Path: path,
+		QueryOptions: structs.QueryOptions{
+			Region:        h.config.region,
+			Namespace:     h.config.alloc.Namespace,
+			AuthToken:     token,
+			AllowStale:    true,
+			MinQueryIndex: index,
+		},
+	}
+
+	var reply structs.VariablesReadResponse
+	if err := h.config.rpcClient.RPC(structs.VariablesReadRPCMethod, &args, &reply); err != nil {
+		return nil, 0, err
+	}
+	if reply.Data == nil {
+		return nil, 0, fmt.Errorf("variable %q not found", path)
+	}
+
+	return reply.Data.Items, reply.Index, nil
+}
+
+func (h *secretHook) readVault(ctx context.Context, path string) (map[string]string, uint64, error) {
+	h.mu.Lock()
+	token := h.vaultToken
+	h.mu.Unlock()
+
+	vaultBlock := h.config.task.Vault
+	if vaultBlock == nil {
+		return nil, 0, errors.New("task has no vault block")
+	}
+
+	vaultConfig := h.config.vaultConfigsFunc(h.logger)[h.config.task.GetVaultClusterName()]
+	if vaultConfig == nil {
+		return nil, 0, fmt.Errorf("Vault cluster %q is disabled or not configured",
+			h.config.task.GetVaultClusterName())
+	}
+
+	apiConfig, err := vaultConfig.ApiConfig()
+	if err != nil {
+		return nil, 0, err
+	}
+	client, err := vaultapi.NewClient(apiConfig)
+	if err != nil {
+		return nil, 0, err
+	}
+	client.SetToken(token)
+	if vaultBlock.Namespace != "" {
+		client.SetNamespace(vaultBlock.Namespace)
+	}
+
+	vaultSecret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if vaultSecret == nil {
+		return nil, 0, fmt.Errorf("Vault secret %q not found", path)
+	}
+
+	data := vaultSecret.Data
+
+	// Secrets of the KV version 2 engine nest their data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	items := make(map[string]string, len(data))
+	for k, v := range data {
+		switch value := v.(type) {
+		case string:
+			items[k] = value
+		default:
+			buf, err := json.Marshal(value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to encode item %q: %w", k, err)
+			}
+			items[k] = string(buf)
+		}
+	}
+
+	wait := secretVaultPollInterval
+	if vaultSecret.LeaseDuration > 0 {
+		wait = time.Duration(vaultSecret.LeaseDuration) * time.Second / 2
+	}
+	return items, uint64(wait / time.Second), nil
+}
+
+// write writes each item of a secret to a file in a directory named after the
+// secret in the task's secrets directory. Files written for items that no
+// longer exist are removed.
+func (h *secretHook) write(secret *structs.Secret, items map[string]string) error {
+	perms := os.FileMode(0o600)
+	if secret.Perms != "" {
+		mode, err := strconv.ParseUint(secret.Perms, 8, 12)
+		if err != nil {
+			return fmt.Errorf("failed to parse %q as octal: %w", secret.Perms, err)
+		}
+		perms = os.FileMode(mode)
+	}
+
+	uid, gid := -1, -1
+	if secret.Uid != nil {
+		uid = *secret.Uid
+	}
+	if secret.Gid != nil {
+		gid = *secret.Gid
+	}
+
+	for key := range items {
+		if key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+			return fmt.Errorf("item %q is not a valid file name", key)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Only remove the files written by the hook, as the task may write its
+	// own files to the directory.
+	var remove []string
+	for _, key := range h.written[secret.Name] {
+		if _, ok := items[key]; !ok {
+			remove = append(remove, key)
+		}
+	}
+
+	if err := writeSecretFiles(h.secretsDir, secret.Name, items, remove, perms, uid, gid); err != nil {
+		return err
+	}
+
+	if h.written == nil {
+		h.written = make(map[string][]string)
+	}
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	h.written[secret.Name] = keys
+	return nil
+}
@@ -0,0 +1,354 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/fsisolation"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+// Statically assert the secret hook implements the expected interfaces
+var (
+	_ interfaces.TaskPrestartHook = (*secretHook)(nil)
+	_ interfaces.TaskUpdateHook   = (*secretHook)(nil)
+	_ interfaces.TaskStopHook     = (*secretHook)(nil)
+)
+
+// mockVariableRPCer serves Variables.Read requests from an in-memory set of
+// variables, blocking briefly when the requested index is current.
+type mockVariableRPCer struct {
+	lock      sync.Mutex
+	index     uint64
+	variables map[string]map[string]string
+}
+
+func (m *mockVariableRPCer) setVariable(path string, items map[string]string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.index++
+	m.variables[path] = items
+}
+
+func (m *mockVariableRPCer) RPC(method string, args any, reply any) error {
+	if method != structs.VariablesReadRPCMethod {
+		return fmt.Errorf("unexpected RPC method %q", method)
+	}
+	req := args.(*structs.VariablesReadRequest)
+	resp := reply.(*structs.VariablesReadResponse)
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for {
+		m.lock.Lock()
+		if m.index > req.MinQueryIndex || time.Now().After(deadline) {
+			resp.Index = m.index
+			if items, ok := m.variables[req.Path]; ok {
+				resp.Data = &structs.VariableDecrypted{
+					VariableMetadata: structs.VariableMetadata{Path: req.Path},
+					Items:            maps.Clone(items),
+				}
+			}
+			m.lock.Unlock()
+			return nil
+		}
+		m.lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func testSecretHook(t *testing.T, secrets []*structs.Secret, rpc *mockVariableRPCer) (
+	*secretHook, *trtesting.MockTaskHooks, *allocdir.TaskDir) {
+	logger := testlog.HCLogger(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Secrets = secrets
+
+	allocDir := allocdir.NewAllocDir(logger, t.TempDir(), t.TempDir(), alloc.ID)
+	t.Cleanup(func() { _ = allocDir.Destroy() })
+	taskDir := allocDir.NewTaskDir(task.Name)
+	must.NoError(t, taskDir.Build(fsisolation.None, nil, task.User))
+
+	mockHooks := trtesting.NewMockTaskHooks()
+	h := newSecretHook(&secretHookConfig{
+		alloc:     alloc,
+		task:      task,
+		region:    "global",
+		rpcClient: rpc,
+		lifecycle: mockHooks,
+		events:    mockHooks,
+		logger:    logger,
+	})
+	t.Cleanup(func() {
+		_ = h.Stop(context.Background(), nil, nil)
+	})
+
+	return h, mockHooks, taskDir
+}
+
+func TestSecretHook_Prestart(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := &mockVariableRPCer{variables: map[string]map[string]string{
+		"db":  {"username": "admin", "password": "hunter2"},
+		"api": {"API_KEY": "abcd1234"},
+	}}
+	h, _, taskDir := testSecretHook(t, []*structs.Secret{
+		{
+			Name:       "db",
+			Provider:   structs.SecretProviderNomad,
+			Path:       "db",
+			ChangeMode: structs.SecretChangeModeRestart,
+		},
+		{
+			Name:       "api",
+			Provider:   structs.SecretProviderNomad,
+			Path:       "api",
+			Env:        true,
+			ChangeMode: structs.SecretChangeModeRestart,
+		},
+	}, rpc)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir, NomadToken: "token"}
+	resp := &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(context.Background(), req, resp))
+
+	// File secrets are written to the secrets directory
+	buf, err := os.ReadFile(filepath.Join(taskDir.SecretsDir, "db", "password"))
+	must.NoError(t, err)
+	must.Eq(t, "hunter2", string(buf))
+
+	// Files are only readable by their owner by default
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(taskDir.SecretsDir, "db", "username"))
+		must.NoError(t, err)
+		must.Eq(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// Env secrets are returned as environment variables only
+	must.Eq(t, map[string]string{"API_KEY": "abcd1234"}, resp.Env)
+	must.DirNotExists(t, filepath.Join(taskDir.SecretsDir, "api"))
+}
+
+func TestSecretHook_Prestart_Missing(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := &mockVariableRPCer{variables: map[string]map[string]string{}}
+	h, _, taskDir := testSecretHook(t, []*structs.Secret{{
+		Name:       "db",
+		Provider:   structs.SecretProviderNomad,
+		Path:       "db",
+		ChangeMode: structs.SecretChangeModeRestart,
+	}}, rpc)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	resp := &interfaces.TaskPrestartResponse{}
+	err := h.Prestart(context.Background(), req, resp)
+	must.ErrorContains(t, err, `variable "db" not found`)
+	must.True(t, structs.IsRecoverable(err))
+}
+
+func TestSecretHook_InvalidItemKey(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := &mockVariableRPCer{variables: map[string]map[string]string{
+		"db": {"../escape": "value"},
+	}}
+	h, _, taskDir := testSecretHook(t, []*structs.Secret{{
+		Name:       "db",
+		Provider:   structs.SecretProviderNomad,
+		Path:       "db",
+		ChangeMode: structs.SecretChangeModeRestart,
+	}}, rpc)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	resp := &interfaces.TaskPrestartResponse{}
+	err := h.Prestart(context.Background(), req, resp)
+	must.ErrorContains(t, err, "is not a valid file name")
+}
+
+func TestSecretHook_Symlink(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on windows")
+	}
+
+	// hostDir stands in for a directory of the host outside the task dir
+	hostDir := t.TempDir()
+	hostFile := filepath.Join(hostDir, "passwd")
+	must.NoError(t, os.WriteFile(hostFile, []byte("root"), 0o644))
+
+	secret := &structs.Secret{
+		Name:       "db",
+		Provider:   structs.SecretProviderNomad,
+		Path:       "db",
+		ChangeMode: structs.SecretChangeModeRestart,
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		rpc := &mockVariableRPCer{variables: map[string]map[string]string{
+			"db": {"password": "hunter2"},
+		}}
+		h, _, taskDir := testSecretHook(t, []*structs.Secret{secret}, rpc)
+		must.NoError(t, os.Symlink(hostDir, filepath.Join(taskDir.SecretsDir, "db")))
+
+		req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+		resp := &interfaces.TaskPrestartResponse{}
+		err := h.Prestart(context.Background(), req, resp)
+		must.ErrorContains(t, err, "is not a directory")
+
+		// Nothing was written to or removed from the symlinked directory
+		entries, err := os.ReadDir(hostDir)
+		must.NoError(t, err)
+		must.Len(t, 1, entries)
+		must.FileNotExists(t, filepath.Join(hostDir, "password"))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		rpc := &mockVariableRPCer{variables: map[string]map[string]string{
+			"db": {"passwd": "hunter2"},
+		}}
+		h, _, taskDir := testSecretHook(t, []*structs.Secret{secret}, rpc)
+		dir := filepath.Join(taskDir.SecretsDir, "db")
+		must.NoError(t, os.MkdirAll(dir, 0o755))
+		must.NoError(t, os.Symlink(hostFile, filepath.Join(dir, ".passwd.tmp")))
+		must.NoError(t, os.Symlink(hostFile, filepath.Join(dir, "passwd")))
+
+		req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+		resp := &interfaces.TaskPrestartResponse{}
+		must.NoError(t, h.Prestart(context.Background(), req, resp))
+
+		// The symlinks are replaced rather than written through
+		buf, err := os.ReadFile(hostFile)
+		must.NoError(t, err)
+		must.Eq(t, "root", string(buf))
+
+		info, err := os.Lstat(filepath.Join(dir, "passwd"))
+		must.NoError(t, err)
+		must.True(t, info.Mode().IsRegular())
+		buf, err = os.ReadFile(filepath.Join(dir, "passwd"))
+		must.NoError(t, err)
+		must.Eq(t, "hunter2", string(buf))
+	})
+}
+
+func TestSecretHook_RemoveItems(t *testing.T) {
+	ci.Parallel(t)
+
+	rpc := &mockVariableRPCer{variables: map[string]map[string]string{
+		"db": {"username": "admin", "password": "hunter2"},
+	}}
+	h, _, taskDir := testSecretHook(t, []*structs.Secret{{
+		Name:       "db",
+		Provider:   structs.SecretProviderNomad,
+		Path:       "db",
+		ChangeMode: structs.SecretChangeModeNoop,
+	}}, rpc)
+
+	req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+	resp := &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(context.Background(), req, resp))
+
+	// Files written by the task are left alone
+	dir := filepath.Join(taskDir.SecretsDir, "db")
+	must.NoError(t, os.WriteFile(filepath.Join(dir, "task.txt"), []byte("task"), 0o644))
+
+	rpc.setVariable("db", map[string]string{"password": "hunter3"})
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			_, err := os.Stat(filepath.Join(dir, "username"))
+			return os.IsNotExist(err)
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+	must.FileExists(t, filepath.Join(dir, "task.txt"))
+	must.FileExists(t, filepath.Join(dir, "password"))
+}
+
+func TestSecretHook_ChangeMode(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name       string
+		changeMode string
+	}{
+		{name: "restart", changeMode: structs.SecretChangeModeRestart},
+		{name: "signal", changeMode: structs.SecretChangeModeSignal},
+		{name: "noop", changeMode: structs.SecretChangeModeNoop},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rpc := &mockVariableRPCer{variables: map[string]map[string]string{
+				"db": {"password": "hunter2"},
+			}}
+			secret := &structs.Secret{
+				Name:       "db",
+				Provider:   structs.SecretProviderNomad,
+				Path:       "db",
+				ChangeMode: tc.changeMode,
+			}
+			if tc.changeMode == structs.SecretChangeModeSignal {
+				secret.ChangeSignal = "SIGHUP"
+			}
+			h, mockHooks, taskDir := testSecretHook(t, []*structs.Secret{secret}, rpc)
+
+			req := &interfaces.TaskPrestartRequest{TaskDir: taskDir}
+			resp := &interfaces.TaskPrestartResponse{}
+			must.NoError(t, h.Prestart(context.Background(), req, resp))
+
+			rpc.setVariable("db", map[string]string{"password": "hunter3"})
+
+			switch tc.changeMode {
+			case structs.SecretChangeModeRestart:
+				select {
+				case <-mockHooks.RestartCh:
+				case <-time.After(5 * time.Second):
+					t.Fatal("expected task to be restarted")
+				}
+			case structs.SecretChangeModeSignal:
+				select {
+				case <-mockHooks.SignalCh:
+					must.Eq(t, []string{"SIGHUP"}, mockHooks.Signals())
+				case <-time.After(5 * time.Second):
+					t.Fatal("expected task to be signaled")
+				}
+			}
+
+			// The file is updated regardless of the change mode
+			path := filepath.Join(taskDir.SecretsDir, "db", "password")
+			must.Wait(t, wait.InitialSuccess(
+				wait.BoolFunc(func() bool {
+					buf, err := os.ReadFile(path)
+					return err == nil && string(buf) == "hunter3"
+				}),
+				wait.Timeout(5*time.Second),
+				wait.Gap(10*time.Millisecond),
+			))
+
+			if tc.changeMode == structs.SecretChangeModeNoop {
+				must.Zero(t, mockHooks.Restarts())
+				must.SliceEmpty(t, mockHooks.Signals())
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !windows
+
+package taskrunner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// writeSecretFiles writes the items of a secret to files in the directory
+// named after the secret in the secrets directory, and removes the files of
+// the keys in remove.
+//
+// The task can write to its secrets directory, so every file is created and
+// replaced relative to descriptors of the directories, without following
+// symlinks. Otherwise a task could point the directory or one of its files
+// at the host, and have the client write or remove files there.
+func writeSecretFiles(secretsDir, name string, items map[string]string, remove []string, perms os.FileMode, uid, gid int) error {
+	rootFd, err := unix.Open(secretsDir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open secrets directory: %w", err)
+	}
+	defer unix.Close(rootFd)
+
+	if err := unix.Mkdirat(rootFd, name, 0o755); err != nil && !errors.Is(err, unix.EEXIST) {
+		return fmt.Errorf("failed to create directory %q: %w", name, err)
+	}
+	dirFd, err := unix.Openat(rootFd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ELOOP) || errors.Is(err, unix.ENOTDIR) {
+		return fmt.Errorf("%q in the secrets directory is not a directory", name)
+	} else if err != nil {
+		return fmt.Errorf("failed to open directory %q: %w", name, err)
+	}
+	defer unix.Close(dirFd)
+
+	if uid != -1 || gid != -1 {
+		if err := unix.Fchown(dirFd, uid, gid); err != nil {
+			return fmt.Errorf("failed to change owner of directory %q: %w", name, err)
+		}
+	}
+
+	for key, value := range items {
+		// Write the file atomically so the task never reads a partial file.
+		// The temporary file is always created anew so an existing file or
+		// symlink in its place is never written through.
+		tmp := "." + key + ".tmp"
+		if err := unix.Unlinkat(dirFd, tmp, 0); err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("failed to remove %q: %w", tmp, err)
+		}
+		fd, err := unix.Openat(dirFd, tmp,
+			unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, uint32(perms.Perm()))
+		if err != nil {
+			return fmt.Errorf("failed to create %q: %w", tmp, err)
+		}
+		if err := writeSecretFile(os.NewFile(uintptr(fd), tmp), value, perms, uid, gid); err != nil {
+			_ = unix.Unlinkat(dirFd, tmp, 0)
+			return err
+		}
+		if err := unix.Renameat(dirFd, tmp, dirFd, key); err != nil {
+			_ = unix.Unlinkat(dirFd, tmp, 0)
+			return fmt.Errorf("failed to rename %q: %w", tmp, err)
+		}
+	}
+
+	for _, key := range remove {
+		if err := unix.Unlinkat(dirFd, key, 0); err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("failed to remove %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// writeSecretFile writes the value of an item to the open file and sets its
+// mode and owner. The file is always closed.
+func writeSecretFile(f *os.File, value string, perms os.FileMode, uid, gid int) error {
+	defer f.Close()
+
+	if _, err := f.WriteString(value); err != nil {
+		return err
+	}
+	if err := f.Chmod(perms); err != nil {
+		return err
+	}
+	if uid != -1 || gid != -1 {
+		if err := f.Chown(uid, gid); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
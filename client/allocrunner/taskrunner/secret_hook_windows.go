@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build windows
+
+package taskrunner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// writeSecretFiles writes the items of a secret to files in the directory
+// named after the secret in the secrets directory, and removes the files of
+// the keys in remove. Setting the owner of the files is not supported on
+// windows.
+func writeSecretFiles(secretsDir, name string, items map[string]string, remove []string, perms os.FileMode, _, _ int) error {
+	dir := filepath.Join(secretsDir, name)
+	if err := os.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("failed to create directory %q: %w", name, err)
+	}
+	if fi, err := os.Lstat(dir); err != nil {
+		return err
+	} else if !fi.IsDir() {
+		return fmt.Errorf("%q in the secrets directory is not a directory", name)
+	}
+
+	for key, value := range items {
+		// Write the file atomically so the task never reads a partial file.
+		// The temporary file is always created anew so an existing file or
+		// symlink in its place is never written through.
+		tmp := filepath.Join(dir, "."+key+".tmp")
+		if err := os.Remove(tmp); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perms)
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteString(value)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(tmp, filepath.Join(dir, key))
+		}
+		if err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+	}
+
+	for _, key := range remove {
+		if err := os.Remove(filepath.Join(dir, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/restarts"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	"github.com/hashicorp/nomad/client/config"
@@ -281,6 +282,13 @@ type TaskRunner struct {
 	// users manages the pool of dynamic workload users
 	users dynamic.Pool
 
+	// rpcClient is used by hooks to make RPC calls to the servers
+	rpcClient config.RPCer
+
+	// templateRestarter coordinates the restarts triggered by templates with
+	// the other allocations of the task group
+	templateRestarter ti.TemplateRestarter
+
 	// pauser controls whether the task should be run or stopped based on a
 	// schedule. (Enterprise)
 	pauser *pauseGate
@@ -362,6 +370,13 @@ type Config struct {
 
 	// Users manages a pool of dynamic workload users
 	Users dynamic.Pool
+
+	// RPCClient is used by hooks to make RPC calls to the servers
+	RPCClient config.RPCer
+
+	// TemplateRestarter coordinates the restarts triggered by templates with
+	// the other allocations of the task group
+	TemplateRestarter ti.TemplateRestarter
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -425,6 +440,8 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		wranglers:               config.Wranglers,
 		widmgr:                  config.WIDMgr,
 		users:                   config.Users,
+		rpcClient:               config.RPCClient,
+		templateRestarter:       config.TemplateRestarter,
 	}
 
 	// Create the logger based on the allocation ID
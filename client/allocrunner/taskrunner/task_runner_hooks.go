@@ -105,6 +105,20 @@ func (tr *TaskRunner) initHooks() {
 		}))
 	}
 
+	// If the task requests a Nomad ACL token, add the hook
+	if task.Nomad != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newNomadTokenHook(&nomadTokenHookConfig{
+			alloc:      tr.Alloc(),
+			task:       tr.Task(),
+			region:     tr.clientConfig.Region,
+			aclEnabled: tr.clientConfig.ACLEnabled,
+			rpcClient:  tr.rpcClient,
+			nodeSecret: tr.clientConfig.Node.SecretID,
+			lifecycle:  tr,
+			logger:     hookLogger,
+		}))
+	}
+
 	// Get the consul namespace for the TG of the allocation.
 	consulNamespace := tr.alloc.ConsulNamespaceForTask(tr.taskName)
 
@@ -112,6 +126,21 @@ func (tr *TaskRunner) initHooks() {
 	// consul tokens are present for the task).
 	tr.runnerHooks = append(tr.runnerHooks, newConsulHook(hookLogger, tr))
 
+	// If there are secrets, add the hook. It runs before the template hook
+	// so templates can read the rendered secrets.
+	if len(task.Secrets) != 0 {
+		tr.runnerHooks = append(tr.runnerHooks, newSecretHook(&secretHookConfig{
+			alloc:            tr.Alloc(),
+			task:             tr.Task(),
+			region:           tr.clientConfig.Region,
+			rpcClient:        tr.rpcClient,
+			vaultConfigsFunc: tr.clientConfig.GetVaultConfigs,
+			lifecycle:        tr,
+			events:           tr,
+			logger:           hookLogger,
+		}))
+	}
+
 	// If there are templates is enabled, add the hook
 	if len(task.Templates) != 0 {
 		tr.runnerHooks = append(tr.runnerHooks, newTemplateHook(&templateHookConfig{
@@ -126,6 +155,7 @@ func (tr *TaskRunner) initHooks() {
 			consulNamespace:     consulNamespace,
 			nomadNamespace:      tr.alloc.Job.Namespace,
 			renderOnTaskRestart: task.RestartPolicy.RenderTemplates,
+			restarter:           tr.templateRestarter,
 		}))
 	}
 
@@ -139,6 +139,14 @@ type TaskTemplateManagerConfig struct {
 	// in downstream platform-specific template runner consumers
 	TaskID string
 
+	// TaskName is the name of the task the templates belong to
+	TaskName string
+
+	// Restarter coordinates the restarts triggered by templates with the
+	// other allocations of the task group. The task is restarted directly if
+	// it is nil.
+	Restarter interfaces.TemplateRestarter
+
 	Logger hclog.Logger
 }
 
@@ -534,9 +542,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 	}
 
 	if restart {
-		tm.config.Lifecycle.Restart(context.Background(),
-			structs.NewTaskEvent(structs.TaskRestartSignal).
-				SetDisplayMessage("Template with change_mode restart re-rendered"), false)
+		tm.handleChangeModeRestart()
 	} else {
 		// Handle signals and scripts since the task may have multiple
 		// templates with mixed change_mode values.
@@ -545,6 +551,33 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 	}
 }
 
+// handleChangeModeRestart restarts the task, once the restarter allows it if
+// the restarts of the task group are coordinated.
+func (tm *TaskTemplateManager) handleChangeModeRestart() {
+	restart := func() error {
+		return tm.config.Lifecycle.Restart(context.Background(),
+			structs.NewTaskEvent(structs.TaskRestartSignal).
+				SetDisplayMessage("Template with change_mode restart re-rendered"), false)
+	}
+	if tm.config.Restarter == nil {
+		restart()
+		return
+	}
+
+	// Stop waiting for the restart when the manager shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-tm.shutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	tm.config.Restarter.Restart(ctx, tm.config.TaskName, tm.config.Events, restart)
+}
+
 func (tm *TaskTemplateManager) handleChangeModeSignal(signals map[string]struct{}) {
 	var mErr multierror.Error
 	for signal := range signals {
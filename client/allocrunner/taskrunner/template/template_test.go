@@ -5,6 +5,7 @@ package template
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -23,6 +24,7 @@ import (
 	ctestutil "github.com/hashicorp/consul/sdk/testutil"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/taskenv"
@@ -2561,3 +2563,58 @@ func TestTaskTemplateManager_writeToFile(t *testing.T) {
 	must.NoError(t, err)
 	must.Eq(t, "hello", string(r))
 }
+
+// mockTemplateRestarter records the restarts it is asked to coordinate. If
+// block is set, it waits for the context to be canceled instead of
+// restarting the task.
+type mockTemplateRestarter struct {
+	block bool
+	tasks []string
+}
+
+func (m *mockTemplateRestarter) Restart(ctx context.Context, task string, _ interfaces.EventEmitter, restart func() error) error {
+	m.tasks = append(m.tasks, task)
+	if m.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return restart()
+}
+
+func TestTaskTemplateManager_ChangeModeRestart_Restarter(t *testing.T) {
+	ci.Parallel(t)
+
+	template := &structs.Template{
+		EmbeddedTmpl: "hello, world!",
+		DestPath:     "my.tmpl",
+		ChangeMode:   structs.TemplateChangeModeRestart,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.start(t)
+	defer harness.stop()
+
+	// Restarts are coordinated by the restarter when set
+	restarter := &mockTemplateRestarter{}
+	harness.manager.config.TaskName = TestTaskName
+	harness.manager.config.Restarter = restarter
+	harness.manager.handleChangeModeRestart()
+	must.Eq(t, []string{TestTaskName}, restarter.tasks)
+	must.Eq(t, 1, harness.mockHooks.Restarts())
+
+	// Waiting for the restart stops when the manager stops
+	restarter.block = true
+	done := make(chan struct{})
+	go func() {
+		harness.manager.handleChangeModeRestart()
+		close(done)
+	}()
+	harness.manager.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatal("expected restart to stop waiting")
+	}
+	must.Eq(t, 1, harness.mockHooks.Restarts())
+}
@@ -55,6 +55,10 @@ type templateHookConfig struct {
 
 	// hookResources are used to fetch Consul tokens
 	hookResources *cstructs.AllocHookResources
+
+	// restarter coordinates the restarts triggered by templates with the
+	// other allocations of the task group. It may be nil.
+	restarter ti.TemplateRestarter
 }
 
 type templateHook struct {
@@ -246,6 +250,8 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		NomadNamespace:       h.config.nomadNamespace,
 		NomadToken:           h.nomadToken,
 		TaskID:               h.taskID,
+		TaskName:             h.task.Name,
+		Restarter:            h.config.restarter,
 		Logger:               h.logger,
 	})
 	if err != nil {
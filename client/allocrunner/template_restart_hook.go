@@ -0,0 +1,312 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allochealth"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// templateRestartRetryInterval is how long to wait before asking the
+	// servers again for a restart slot after an RPC error.
+	templateRestartRetryInterval = 5 * time.Second
+
+	// templateRestartReleaseAttempts is the number of times releasing a
+	// restart slot is attempted. The servers expire slots which are never
+	// released.
+	templateRestartReleaseAttempts = 3
+)
+
+type templateRestartHookConfig struct {
+	alloc                 *structs.Allocation
+	rpcClient             config.RPCer
+	region                string
+	nodeSecret            string
+	listenerFn            func() *cstructs.AllocListener
+	taskEnvBuilderFactory func() *taskenv.Builder
+	consul                serviceregistration.Handler
+	checkStore            checkstore.Shim
+	logger                hclog.Logger
+}
+
+// templateRestartHook coordinates the restarts triggered by the templates of
+// the tasks of an allocation with the other allocations of its task group,
+// according to the template restart policy of the group. The servers grant a
+// restart slot to the allocation before it restarts, and the hook releases
+// the slot once the allocation is healthy again or its healthy deadline is
+// reached.
+type templateRestartHook struct {
+	rpcClient             config.RPCer
+	region                string
+	nodeSecret            string
+	listenerFn            func() *cstructs.AllocListener
+	taskEnvBuilderFactory func() *taskenv.Builder
+	consul                serviceregistration.Handler
+	checkStore            checkstore.Shim
+	logger                hclog.Logger
+
+	// alloc is updated by Update. Must hold allocLock to access.
+	alloc     *structs.Allocation
+	allocLock sync.Mutex
+
+	// sem serializes the restarts of the tasks of the allocation, which
+	// hold it until the slot of their restart is released.
+	sem chan struct{}
+
+	shutdownCtx      context.Context
+	shutdownCancelFn context.CancelFunc
+}
+
+var (
+	_ interfaces.RunnerUpdateHook  = (*templateRestartHook)(nil)
+	_ interfaces.RunnerPostrunHook = (*templateRestartHook)(nil)
+	_ interfaces.RunnerDestroyHook = (*templateRestartHook)(nil)
+	_ interfaces.ShutdownHook      = (*templateRestartHook)(nil)
+	_ ti.TemplateRestarter         = (*templateRestartHook)(nil)
+)
+
+func newTemplateRestartHook(cfg templateRestartHookConfig) *templateRestartHook {
+	shutdownCtx, shutdownCancelFn := context.WithCancel(context.Background())
+
+	return &templateRestartHook{
+		alloc:                 cfg.alloc,
+		rpcClient:             cfg.rpcClient,
+		region:                cfg.region,
+		nodeSecret:            cfg.nodeSecret,
+		listenerFn:            cfg.listenerFn,
+		taskEnvBuilderFactory: cfg.taskEnvBuilderFactory,
+		consul:                cfg.consul,
+		checkStore:            cfg.checkStore,
+		logger:                cfg.logger.Named("template_restart"),
+		sem:                   make(chan struct{}, 1),
+		shutdownCtx:           shutdownCtx,
+		shutdownCancelFn:      shutdownCancelFn,
+	}
+}
+
+func (h *templateRestartHook) Name() string {
+	return "template_restart"
+}
+
+func (h *templateRestartHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	h.allocLock.Lock()
+	defer h.allocLock.Unlock()
+	h.alloc = req.Alloc
+	return nil
+}
+
+func (h *templateRestartHook) Postrun() error {
+	h.shutdownCancelFn()
+	return nil
+}
+
+func (h *templateRestartHook) Destroy() error {
+	h.shutdownCancelFn()
+	return nil
+}
+
+func (h *templateRestartHook) Shutdown() {
+	h.shutdownCancelFn()
+}
+
+// Restart calls restart once the servers grant a restart slot to the
+// allocation. The task is restarted directly if its group has no template
+// restart policy, or if the servers don't support coordinating restarts.
+func (h *templateRestartHook) Restart(ctx context.Context, task string, events ti.EventEmitter, restart func() error) error {
+	h.allocLock.Lock()
+	alloc := h.alloc
+	h.allocLock.Unlock()
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || tg.TemplateRestart == nil {
+		return restart()
+	}
+	policy := tg.TemplateRestart
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(h.shutdownCtx, cancel)
+	defer stop()
+
+	select {
+	case h.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	coordinated, err := h.acquire(ctx, alloc.ID, events)
+	if err != nil {
+		<-h.sem
+		return err
+	}
+	if !coordinated {
+		defer func() { <-h.sem }()
+		return restart()
+	}
+
+	start := time.Now()
+	if err := restart(); err != nil {
+		// The task didn't restart, so the health of the allocation is
+		// unchanged
+		h.release(alloc.ID, true)
+		<-h.sem
+		return err
+	}
+
+	go h.watchHealth(alloc.ID, task, policy, start)
+	return nil
+}
+
+// acquire asks the servers for a restart slot until one is granted. It
+// returns false if the servers don't support coordinating restarts.
+func (h *templateRestartHook) acquire(ctx context.Context, allocID string, events ti.EventEmitter) (bool, error) {
+	var reason string
+	for {
+		req := &structs.TemplateRestartAcquireRequest{
+			AllocID: allocID,
+			WriteRequest: structs.WriteRequest{
+				Region:    h.region,
+				AuthToken: h.nodeSecret,
+			},
+		}
+		var resp structs.TemplateRestartAcquireResponse
+		err := h.rpcClient.RPC(structs.TemplateRestartAcquireRPCMethod, req, &resp)
+
+		wait := resp.RetryAfter
+		switch {
+		case structs.IsErrUnknownMethod(err):
+			h.logger.Warn("servers do not support template restart policies, restarting task without waiting")
+			return false, nil
+		case err != nil:
+			h.logger.Warn("failed to acquire template restart slot", "error", err)
+			wait = templateRestartRetryInterval
+		case resp.Granted:
+			return true, nil
+		case resp.Reason != reason:
+			reason = resp.Reason
+			events.EmitEvent(structs.NewTaskEvent(structs.TaskHookMessage).
+				SetDisplayMessage(fmt.Sprintf("Template restart waiting: %s", reason)))
+		}
+		if wait <= 0 {
+			wait = templateRestartRetryInterval
+		}
+
+		// Add jitter so clients waiting on the same group don't all ask at
+		// once
+		timer, stop := helper.NewSafeTimer(wait + helper.RandomStagger(wait/4))
+		select {
+		case <-timer.C:
+			stop()
+		case <-ctx.Done():
+			stop()
+			return false, ctx.Err()
+		}
+	}
+}
+
+// release releases the restart slot of the allocation, with whether it was
+// healthy after its restart.
+func (h *templateRestartHook) release(allocID string, healthy bool) {
+	req := &structs.TemplateRestartReleaseRequest{
+		AllocID: allocID,
+		Healthy: healthy,
+		WriteRequest: structs.WriteRequest{
+			Region:    h.region,
+			AuthToken: h.nodeSecret,
+		},
+	}
+
+	var err error
+	for attempt := 0; attempt < templateRestartReleaseAttempts; attempt++ {
+		var resp structs.GenericResponse
+		if err = h.rpcClient.RPC(structs.TemplateRestartReleaseRPCMethod, req, &resp); err == nil {
+			return
+		}
+
+		select {
+		case <-time.After(templateRestartRetryInterval):
+		case <-h.shutdownCtx.Done():
+			return
+		}
+	}
+	h.logger.Warn("failed to release template restart slot", "error", err)
+}
+
+// watchHealth waits until the allocation is healthy after the restart of the
+// task, or its healthy deadline is reached, and releases its restart slot.
+func (h *templateRestartHook) watchHealth(allocID, task string, policy *structs.TemplateRestartPolicy, start time.Time) {
+	defer func() { <-h.sem }()
+
+	ctx, cancel := context.WithTimeout(h.shutdownCtx, policy.HealthyDeadline)
+	defer cancel()
+
+	healthy := h.waitHealthy(ctx, task, policy, start)
+
+	// Stopped allocations don't need to release their slot, as the servers
+	// forget the allocations which stopped
+	if h.shutdownCtx.Err() != nil {
+		return
+	}
+
+	h.logger.Debug("template restart finished", "task", task, "healthy", healthy)
+	h.release(allocID, healthy)
+}
+
+// waitHealthy returns whether the allocation is healthy after the restart of
+// the task.
+func (h *templateRestartHook) waitHealthy(ctx context.Context, task string, policy *structs.TemplateRestartPolicy, start time.Time) bool {
+	// Wait for the task to run again, so the health of the allocation isn't
+	// tracked from its task states before the restart
+	listener := h.listenerFn()
+	defer listener.Close()
+
+	var alloc *structs.Allocation
+	for alloc == nil {
+		select {
+		case <-ctx.Done():
+			return false
+		case update, ok := <-listener.Ch():
+			if !ok {
+				return false
+			}
+			state := update.TaskStates[task]
+			if state != nil && state.State == structs.TaskStateRunning && !state.StartedAt.Before(start) {
+				alloc = update
+			}
+		}
+	}
+
+	trackerListener := h.listenerFn()
+	defer trackerListener.Close()
+
+	tracker := allochealth.NewTracker(
+		ctx, h.logger, alloc, trackerListener, h.taskEnvBuilderFactory().UpdateTask(alloc, nil),
+		h.consul, h.checkStore, policy.MinHealthyTime, policy.UseChecks(),
+	)
+	tracker.Start()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-tracker.AllocStoppedCh():
+		return false
+	case healthy := <-tracker.HealthyCh():
+		return healthy
+	}
+}
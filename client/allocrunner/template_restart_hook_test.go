@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	regMock "github.com/hashicorp/nomad/client/serviceregistration/mock"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// mockTemplateRestartRPCer denies the first restart slot requests it receives
+// and records the released slots.
+type mockTemplateRestartRPCer struct {
+	lock     sync.Mutex
+	denials  int
+	acquires int
+	released []bool
+	err      error
+}
+
+func (r *mockTemplateRestartRPCer) RPC(method string, args any, reply any) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	switch method {
+	case structs.TemplateRestartAcquireRPCMethod:
+		r.acquires++
+		resp := reply.(*structs.TemplateRestartAcquireResponse)
+		if r.acquires <= r.denials {
+			resp.Reason = "1 of 1 allocations restarting"
+			resp.RetryAfter = 10 * time.Millisecond
+			return nil
+		}
+		resp.Granted = true
+	case structs.TemplateRestartReleaseRPCMethod:
+		r.released = append(r.released, args.(*structs.TemplateRestartReleaseRequest).Healthy)
+	default:
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	return nil
+}
+
+func (r *mockTemplateRestartRPCer) getReleased() []bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.released
+}
+
+// mockTaskEvents records the events emitted to a task.
+type mockTaskEvents struct {
+	lock   sync.Mutex
+	events []*structs.TaskEvent
+}
+
+func (m *mockTaskEvents) EmitEvent(event *structs.TaskEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.events = append(m.events, event)
+}
+
+func newTestTemplateRestartHook(t *testing.T, alloc *structs.Allocation, rpc *mockTemplateRestartRPCer) (*templateRestartHook, *cstructs.AllocBroadcaster) {
+	logger := testlog.HCLogger(t)
+	b := cstructs.NewAllocBroadcaster(logger)
+	t.Cleanup(b.Close)
+
+	h := newTemplateRestartHook(templateRestartHookConfig{
+		alloc:                 alloc,
+		rpcClient:             rpc,
+		region:                "global",
+		nodeSecret:            "secret",
+		listenerFn:            b.Listen,
+		taskEnvBuilderFactory: taskEnvBuilderFactory(alloc),
+		consul:                regMock.NewServiceRegistrationHandler(logger),
+		checkStore:            new(mock.CheckShim),
+		logger:                logger,
+	})
+	t.Cleanup(h.Shutdown)
+	return h, b
+}
+
+func TestTemplateRestartHook_Restart(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].TemplateRestart = &structs.TemplateRestartPolicy{
+		MaxParallel:     1,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:  1,
+		HealthyDeadline: 10 * time.Second,
+	}
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	rpc := &mockTemplateRestartRPCer{denials: 2}
+	h, b := newTestTemplateRestartHook(t, alloc, rpc)
+
+	// The task restarts once the slot is granted
+	restarts := 0
+	restart := func() error {
+		restarts++
+		running := alloc.Copy()
+		running.ClientStatus = structs.AllocClientStatusRunning
+		running.TaskStates = map[string]*structs.TaskState{
+			task.Name: {State: structs.TaskStateRunning, StartedAt: time.Now()},
+		}
+		return b.Send(running)
+	}
+
+	events := &mockTaskEvents{}
+	must.NoError(t, h.Restart(context.Background(), task.Name, events, restart))
+	must.Eq(t, 1, restarts)
+	must.Eq(t, 3, rpc.acquires)
+
+	// An event is emitted while waiting for the slot
+	must.Len(t, 1, events.events)
+	must.Eq(t, "Template restart waiting: 1 of 1 allocations restarting", events.events[0].DisplayMessage)
+
+	// The slot is released once the allocation is healthy
+	testutil.WaitForResult(func() (bool, error) {
+		released := rpc.getReleased()
+		if len(released) != 1 {
+			return false, fmt.Errorf("expected slot to be released")
+		}
+		return released[0], fmt.Errorf("expected allocation to be healthy")
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+}
+
+func TestTemplateRestartHook_Uncoordinated(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	rpc := &mockTemplateRestartRPCer{err: fmt.Errorf("rpc error")}
+	h, _ := newTestTemplateRestartHook(t, alloc, rpc)
+
+	restarts := 0
+	restart := func() error {
+		restarts++
+		return nil
+	}
+
+	// Groups without a policy restart without asking the servers
+	must.NoError(t, h.Restart(context.Background(), task.Name, &mockTaskEvents{}, restart))
+	must.Eq(t, 1, restarts)
+
+	// Servers which don't support coordinating restarts are not waited on
+	alloc = alloc.Copy()
+	alloc.Job.TaskGroups[0].TemplateRestart = structs.DefaultTemplateRestartPolicy()
+	must.NoError(t, h.Update(&interfaces.RunnerUpdateRequest{Alloc: alloc}))
+	rpc.err = structs.ErrUnknownMethod
+	must.NoError(t, h.Restart(context.Background(), task.Name, &mockTaskEvents{}, restart))
+	must.Eq(t, 2, restarts)
+	must.Eq(t, 0, rpc.acquires)
+
+	// Waiting for a slot stops when the context is canceled
+	rpc.err = fmt.Errorf("rpc error")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	must.ErrorIs(t, h.Restart(ctx, task.Name, &mockTaskEvents{}, restart), context.Canceled)
+	must.Eq(t, 2, restarts)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// errArtifactCacheDisabled is returned when the artifact cache is accessed on
+// a client where it is not enabled.
+const errArtifactCacheDisabled = "artifact cache is not enabled on this client"
+
+// ArtifactCache endpoint is used for inspecting and purging the artifacts
+// shared between tasks on the client.
+type ArtifactCache struct {
+	c *Client
+}
+
+func newArtifactCacheEndpoint(c *Client) *ArtifactCache {
+	return &ArtifactCache{c: c}
+}
+
+// List returns the artifacts in the artifact cache.
+func (a *ArtifactCache) List(args *structs.NodeSpecificRequest, reply *structs.ArtifactCacheResponse) error {
+	defer metrics.MeasureSince([]string{"client", "artifact_cache", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	if a.c.artifactCache == nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, errArtifactCacheDisabled)
+	}
+
+	*reply = *a.c.artifactCache.List()
+	return nil
+}
+
+// Purge removes artifacts from the artifact cache.
+func (a *ArtifactCache) Purge(args *structs.ArtifactCachePurgeRequest, reply *structs.ArtifactCachePurgeResponse) error {
+	defer metrics.MeasureSince([]string{"client", "artifact_cache", "purge"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if a.c.artifactCache == nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, errArtifactCacheDisabled)
+	}
+
+	resp, err := a.c.artifactCache.Purge(args.Keys)
+	*reply = *resp
+	return err
+}
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestArtifactCache_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, _, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+		c.Artifact = &config.ArtifactConfig{
+			CacheEnabled:  true,
+			CacheMaxBytes: 1024,
+		}
+	})
+	defer cleanup()
+
+	// Artifact cache endpoints should fail without auth
+	listReq := &structs.NodeSpecificRequest{
+		NodeID: c1.NodeID(),
+	}
+	var listResp structs.ArtifactCacheResponse
+	err := c1.ClientRPC("ArtifactCache.List", listReq, &listResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	purgeReq := &structs.ArtifactCachePurgeRequest{
+		NodeID: c1.NodeID(),
+	}
+	var purgeResp structs.ArtifactCachePurgeResponse
+	err = c1.ClientRPC("ArtifactCache.Purge", purgeReq, &purgeResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	// Reading requires node read
+	policyRead := mock.NodePolicy(acl.PolicyRead)
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1009, "read", policyRead)
+
+	listReq.AuthToken = tokenRead.SecretID
+	err = c1.ClientRPC("ArtifactCache.List", listReq, &listResp)
+	must.NoError(t, err)
+	must.Len(t, 0, listResp.Entries)
+	must.Eq(t, 1024, listResp.MaxSize)
+
+	purgeReq.AuthToken = tokenRead.SecretID
+	err = c1.ClientRPC("ArtifactCache.Purge", purgeReq, &purgeResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	// Purging requires node write
+	policyWrite := mock.NodePolicy(acl.PolicyWrite)
+	tokenWrite := mock.CreatePolicyAndToken(t, s.State(), 1011, "write", policyWrite)
+
+	purgeReq.AuthToken = tokenWrite.SecretID
+	err = c1.ClientRPC("ArtifactCache.Purge", purgeReq, &purgeResp)
+	must.NoError(t, err)
+}
+
+func TestArtifactCache_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	c1, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	var resp structs.ArtifactCacheResponse
+	err := c1.ClientRPC("ArtifactCache.List", &structs.NodeSpecificRequest{}, &resp)
+	must.ErrorContains(t, err, errArtifactCacheDisabled)
+}
@@ -265,6 +265,10 @@ type Client struct {
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
 
+	// networkPolicies enforces the network policies between allocations on
+	// the bridge network
+	networkPolicies *allocrunner.NetworkPolicyManager
+
 	// clientACLResolver holds the ACL resolution state
 	clientACLResolver
 
@@ -318,6 +322,14 @@ type Client struct {
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
 
+	// artifactCache holds the artifacts shared between tasks, or is nil if
+	// the artifact cache is disabled.
+	artifactCache *getter.Cache
+
+	// prefetcher fetches the images and artifacts of jobs before they are
+	// placed on the client.
+	prefetcher *prefetcher
+
 	// wranglers is used to keep track of processes and manage their interaction
 	// with drivers and stuff
 	wranglers *proclib.Wranglers
@@ -393,7 +405,6 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		serversContactedOnce: sync.Once{},
 		registeredCh:         make(chan struct{}),
 		registeredOnce:       sync.Once{},
-		getter:               getter.New(cfg.Artifact, logger),
 		EnterpriseClient:     newEnterpriseClient(logger),
 		allocrunnerFactory:   cfg.AllocRunnerFactory,
 	}
@@ -420,6 +431,19 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("failed to initialize client: %v", err)
 	}
 
+	// initialize the artifact downloader (needs to happen after init so the
+	// artifact cache can be placed in the state dir)
+	if cfg.Artifact != nil && cfg.Artifact.CacheEnabled {
+		cacheDir := filepath.Join(c.GetConfig().StateDir, "artifact_cache")
+		cache, err := getter.NewCache(cacheDir, cfg.Artifact.CacheMaxBytes, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize artifact cache: %w", err)
+		}
+		c.artifactCache = cache
+	}
+	c.getter = getter.New(cfg.Artifact, c.artifactCache, logger)
+	c.prefetcher = newPrefetcher(c)
+
 	// initialize the dynamic registry (needs to happen after init)
 	c.dynamicRegistry =
 		dynamicplugins.NewRegistry(c.stateDB, map[string]dynamicplugins.PluginDispenser{
@@ -565,6 +589,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
 	go c.garbageCollector.Run()
 
+	// Setup the network policy manager
+	c.networkPolicies = allocrunner.NewNetworkPolicyManager(c.logger, cfg.BridgeNetworkAllocSubnet)
+
 	// Set the preconfigured list of static servers
 	if len(cfg.Servers) > 0 {
 		if _, err := c.setServersImpl(cfg.Servers, true); err != nil {
@@ -1315,6 +1342,14 @@ func (c *Client) restoreState() error {
 		c.allocs[alloc.ID] = ar
 		c.allocLock.Unlock()
 
+		// The network status of restored allocations is not updated again
+		// unless they restart, so register them with the network policies
+		c.updateNetworkPolicyAlloc(&structs.Allocation{
+			ID:            alloc.ID,
+			ClientStatus:  alloc.ClientStatus,
+			NetworkStatus: ar.AllocState().NetworkStatus,
+		})
+
 		c.heartbeatStop.allocHook(alloc)
 	}
 
@@ -1760,6 +1795,12 @@ func (c *Client) registerAndHeartbeat() {
 	// Start watching for emitting node events
 	go c.watchNodeEvents()
 
+	// Start watching network policies
+	go c.watchNetworkPolicies()
+
+	// Start watching the images prefetched onto the node
+	go c.prefetcher.watchImages()
+
 	// Setup the heartbeat timer, for the initial registration
 	// we want to do this quickly. We want to do it extra quickly
 	// in development mode.
@@ -1925,6 +1966,74 @@ func (c *Client) submitNodeEvents(events []*structs.NodeEvent) error {
 	return nil
 }
 
+// watchNetworkPolicies is a long lived function that watches the network
+// policies of all namespaces and enforces them on the bridge network.
+func (c *Client) watchNetworkPolicies() {
+	// Network policies are only enforced on the bridge network, so nodes
+	// without one have nothing to enforce
+	if !c.bridgeNetworkAvailable() {
+		c.logger.Debug("bridge network not available, network policies not enforced")
+		return
+	}
+
+	req := structs.NetworkPolicyListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			Namespace:  structs.AllNamespacesSentinel,
+			AllowStale: true,
+			AuthToken:  c.secretNodeID(),
+		},
+	}
+
+	for {
+		var resp structs.NetworkPolicyListResponse
+		err := c.RPC("NetworkPolicy.List", &req, &resp)
+		if err != nil {
+			// Shutdown often causes EOF errors, so check for shutdown first
+			select {
+			case <-c.shutdownCh:
+				return
+			default:
+			}
+
+			// Servers older than the client may not support network policies
+			if structs.IsErrUnknownMethod(err) {
+				c.logger.Debug("servers do not support network policies", "error", err)
+			} else if err != noServersErr {
+				c.logger.Error("error querying network policies", "error", err)
+			}
+			retry := c.retryIntv(getAllocRetryIntv)
+			select {
+			case <-c.rpcRetryWatcher():
+				continue
+			case <-time.After(retry):
+				continue
+			case <-c.shutdownCh:
+				return
+			}
+		}
+
+		// Check for shutdown
+		select {
+		case <-c.shutdownCh:
+			return
+		default:
+		}
+
+		// Reset the index if it goes backwards to avoid blocking forever
+		if resp.Index < req.MinQueryIndex {
+			req.MinQueryIndex = 0
+			continue
+		}
+		if resp.Index == req.MinQueryIndex {
+			continue
+		}
+		req.MinQueryIndex = resp.Index
+
+		c.networkPolicies.SetPolicies(resp.NetworkPolicies)
+	}
+}
+
 // watchNodeEvents is a handler which receives node events and on a interval
 // and submits them in batch format to the server
 func (c *Client) watchNodeEvents() {
@@ -2204,6 +2313,83 @@ func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
 	stripped.NetworkStatus = alloc.NetworkStatus
 
 	c.pendingUpdates.add(stripped)
+
+	c.updateNetworkPolicyAlloc(alloc)
+}
+
+// updateNetworkPolicyAlloc updates the allocations known to the network
+// policy manager when the network status of an allocation in bridge mode
+// changes or when it stops.
+func (c *Client) updateNetworkPolicyAlloc(alloc *structs.Allocation) {
+	if alloc.ClientTerminalStatus() {
+		c.networkPolicies.RemoveAlloc(alloc.ID)
+		return
+	}
+	if alloc.NetworkStatus == nil {
+		return
+	}
+
+	// The updated allocation only holds the fields updatable by the client,
+	// so lookup the job from the alloc runner.
+	ar, err := c.getAllocRunner(alloc.ID)
+	if err != nil {
+		return
+	}
+	full := ar.Alloc()
+	tg := full.Job.LookupTaskGroup(full.TaskGroup)
+	if tg == nil {
+		return
+	}
+	address := bridgeNetworkAddress(tg, alloc.NetworkStatus)
+	if address == "" {
+		return
+	}
+
+	meta := maps.Clone(full.Job.Meta)
+	if meta == nil {
+		meta = make(map[string]string, len(tg.Meta))
+	}
+	maps.Copy(meta, tg.Meta)
+
+	c.networkPolicies.UpsertAlloc(address, &structs.NetworkPolicyEndpoint{
+		AllocID:   full.ID,
+		Namespace: full.Namespace,
+		JobID:     full.JobID,
+		TaskGroup: full.TaskGroup,
+		Meta:      meta,
+	})
+}
+
+// bridgeNetworkAddress returns the address of the allocation on the bridge
+// network, or an empty string if no network of the group is in bridge mode.
+func bridgeNetworkAddress(tg *structs.TaskGroup, status *structs.AllocNetworkStatus) string {
+	for _, iface := range status.Interfaces {
+		if iface != nil && iface.Mode == "bridge" {
+			return iface.Address
+		}
+	}
+	if len(tg.Networks) > 0 && tg.Networks[0].Mode == "bridge" {
+		return status.Address
+	}
+	return ""
+}
+
+// bridgeNetworkAvailable returns whether the node fingerprinted the bridge
+// network and the CNI bridge plugin, which network policies are enforced on.
+func (c *Client) bridgeNetworkAvailable() bool {
+	node := c.Node()
+	if _, ok := node.Attributes["plugins.cni.version.bridge"]; !ok {
+		return false
+	}
+	if node.NodeResources == nil {
+		return false
+	}
+	for _, nw := range node.NodeResources.Networks {
+		if nw.Mode == "bridge" {
+			return true
+		}
+	}
+	return false
 }
 
 // PutAllocation stores an allocation or returns an error if it could not be stored.
@@ -2680,6 +2866,7 @@ func (c *Client) removeAlloc(allocID string) {
 
 	// Stop tracking alloc runner as it's been GC'd by the server
 	delete(c.allocs, allocID)
+	c.networkPolicies.RemoveAlloc(allocID)
 
 	// Ensure the GC has a reference and then collect. Collecting through the GC
 	// applies rate limiting
@@ -3321,6 +3508,7 @@ func (c *Client) emitClientMetrics() {
 	labels := c.labels()
 
 	c.setGaugeForAllocationStats(nodeID, labels)
+	c.networkPolicies.EmitStats(labels)
 
 	// Emit allocation metrics
 	blocked, migrating, pending, running, terminal := 0, 0, 0, 0, 0
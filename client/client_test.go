@@ -2184,3 +2184,61 @@ func TestClient_AllocPrerunErrorDuringRestore(t *testing.T) {
 	must.Eq(t, expectEvents, actual)
 	test.StrContains(t, ts.Events[3].DisplayMessage, allocrunner.ErrFailHookError.Error())
 }
+
+func TestClient_bridgeNetworkAddress(t *testing.T) {
+	ci.Parallel(t)
+
+	bridge := &structs.NetworkResource{Mode: "bridge"}
+	cni := &structs.NetworkResource{Mode: "cni/storage"}
+
+	testCases := []struct {
+		name     string
+		networks structs.Networks
+		status   *structs.AllocNetworkStatus
+		exp      string
+	}{
+		{
+			name:     "bridge",
+			networks: structs.Networks{bridge},
+			status:   &structs.AllocNetworkStatus{Address: "172.26.64.2"},
+			exp:      "172.26.64.2",
+		},
+		{
+			name:     "host",
+			networks: structs.Networks{{Mode: "host"}},
+			status:   &structs.AllocNetworkStatus{Address: "10.0.0.1"},
+			exp:      "",
+		},
+		{
+			name:     "bridge not first",
+			networks: structs.Networks{cni, bridge},
+			status: &structs.AllocNetworkStatus{
+				Address: "10.0.5.2",
+				Interfaces: []*structs.AllocNetworkInterfaceStatus{
+					{Mode: "cni/storage", InterfaceName: "eth0", Address: "10.0.5.2"},
+					{Mode: "bridge", InterfaceName: "eth1", Address: "172.26.64.2"},
+				},
+			},
+			exp: "172.26.64.2",
+		},
+		{
+			name:     "no bridge",
+			networks: structs.Networks{cni, {Mode: "cni/other"}},
+			status: &structs.AllocNetworkStatus{
+				Address: "10.0.5.2",
+				Interfaces: []*structs.AllocNetworkInterfaceStatus{
+					{Mode: "cni/storage", InterfaceName: "eth0", Address: "10.0.5.2"},
+					{Mode: "cni/other", InterfaceName: "eth1", Address: "10.0.6.2"},
+				},
+			},
+			exp: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &structs.TaskGroup{Networks: tc.networks}
+			must.Eq(t, tc.exp, bridgeNetworkAddress(tg, tc.status))
+		})
+	}
+}
@@ -5,6 +5,8 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"os"
 	"slices"
 	"time"
 
@@ -29,6 +31,20 @@ type ArtifactConfig struct {
 	DisableFilesystemIsolation    bool
 	FilesystemIsolationExtraPaths []string
 	SetEnvironmentVariables       string
+
+	RequireSignature bool
+
+	// TrustedKeys maps the names of the keys trusted to sign artifacts to the
+	// contents of their public key files.
+	TrustedKeys map[string]string
+
+	CacheEnabled  bool
+	CacheMaxBytes int64
+
+	// OCIAuth maps the hosts of OCI registries to their credentials, in the
+	// form username:password.
+	OCIAuth     map[string]string
+	OCIAuthFile string
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
@@ -69,6 +85,23 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 		return nil, fmt.Errorf("error parsing DecompressionLimitSize: %w", err)
 	}
 
+	cacheMaxSize, err := humanize.ParseBytes(*c.CacheMaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CacheMaxSize: %w", err)
+	}
+
+	var trustedKeys map[string]string
+	if len(c.TrustedKeys) > 0 {
+		trustedKeys = make(map[string]string, len(c.TrustedKeys))
+		for name, path := range c.TrustedKeys {
+			key, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading trusted key %q: %w", name, err)
+			}
+			trustedKeys[name] = string(key)
+		}
+	}
+
 	return &ArtifactConfig{
 		HTTPReadTimeout:               httpReadTimeout,
 		HTTPMaxBytes:                  int64(httpMaxSize),
@@ -81,6 +114,12 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 		DisableFilesystemIsolation:    *c.DisableFilesystemIsolation,
 		FilesystemIsolationExtraPaths: slices.Clone(c.FilesystemIsolationExtraPaths),
 		SetEnvironmentVariables:       *c.SetEnvironmentVariables,
+		RequireSignature:              *c.RequireSignature,
+		TrustedKeys:                   trustedKeys,
+		CacheEnabled:                  *c.CacheEnabled,
+		CacheMaxBytes:                 int64(cacheMaxSize),
+		OCIAuth:                       maps.Clone(c.OCIAuth),
+		OCIAuthFile:                   *c.OCIAuthFile,
 	}, nil
 
 }
@@ -91,5 +130,7 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	}
 
 	newCopy := *a
+	newCopy.TrustedKeys = maps.Clone(a.TrustedKeys)
+	newCopy.OCIAuth = maps.Clone(a.OCIAuth)
 	return &newCopy
 }
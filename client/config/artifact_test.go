@@ -34,6 +34,7 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 				S3Timeout:                   30 * time.Minute,
 				DecompressionLimitFileCount: 4096,
 				DecompressionLimitSize:      100_000_000_000,
+				CacheMaxBytes:               10_000_000_000,
 			},
 		},
 		{
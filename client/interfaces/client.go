@@ -42,6 +42,9 @@ type EnvReplacer interface {
 type ArtifactGetter interface {
 	// Get artifact and put it in the task directory.
 	Get(EnvReplacer, *structs.TaskArtifact) error
+
+	// Prefetch artifact into the artifact cache before it is used by a task.
+	Prefetch(*structs.TaskArtifact) error
 }
 
 // ProcessWranglers is an interface satisfied by the proclib package.
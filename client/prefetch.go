@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// prefetchImageDriver is the driver used to pull the container images of
+	// prefetched jobs.
+	prefetchImageDriver = "docker"
+
+	// prefetchRetention is how long the progress of finished prefetches is
+	// kept.
+	prefetchRetention = 24 * time.Hour
+
+	// prefetchImageCheckInterval is how often the client checks whether the
+	// prefetched images of the node are still present.
+	prefetchImageCheckInterval = 5 * time.Minute
+)
+
+// prefetcher pulls the container images and downloads the artifacts of jobs
+// before their allocations are placed on the client, so that their tasks
+// start faster.
+type prefetcher struct {
+	c      *Client
+	logger hclog.Logger
+
+	// jobs is the progress of the latest prefetch of each job
+	jobs map[structs.NamespacedID]*structs.NodePrefetch
+	lock sync.Mutex
+}
+
+func newPrefetcher(c *Client) *prefetcher {
+	return &prefetcher{
+		c:      c,
+		logger: c.logger.Named("prefetch"),
+		jobs:   make(map[structs.NamespacedID]*structs.NodePrefetch),
+	}
+}
+
+// start starts prefetching the images and artifacts of the job, and returns
+// its progress. A job which is being or was prefetched at the same version is
+// not prefetched again, unless prefetching it failed.
+func (p *prefetcher) start(req *structs.NodePrefetchRequest) *structs.NodePrefetch {
+	id := structs.NewNamespacedID(req.JobID, req.RequestNamespace())
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.pruneLocked()
+
+	if prev, ok := p.jobs[id]; ok && prev.JobVersion == req.JobVersion &&
+		prev.Status != structs.NodePrefetchStatusFailed {
+		return prev.Copy()
+	}
+
+	pf := &structs.NodePrefetch{
+		NodeID:     p.c.NodeID(),
+		Namespace:  id.Namespace,
+		JobID:      id.ID,
+		JobVersion: req.JobVersion,
+		Status:     structs.NodePrefetchStatusPending,
+		StartTime:  time.Now().UnixNano(),
+	}
+	for _, image := range req.Images {
+		pf.Images = append(pf.Images, &structs.NodePrefetchItem{
+			Source: image,
+			Status: structs.NodePrefetchStatusPending,
+		})
+	}
+	for _, artifact := range req.Artifacts {
+		pf.Artifacts = append(pf.Artifacts, &structs.NodePrefetchItem{
+			Source: redactPrefetchSource(artifact.GetterSource),
+			Status: structs.NodePrefetchStatusPending,
+		})
+	}
+	p.jobs[id] = pf
+
+	go p.run(pf, req.ImageAuths, req.Artifacts)
+
+	return pf.Copy()
+}
+
+// status returns the progress of prefetching the job, or nil if the job was
+// not prefetched.
+func (p *prefetcher) status(namespace, jobID string) *structs.NodePrefetch {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.pruneLocked()
+	return p.jobs[structs.NewNamespacedID(jobID, namespace)].Copy()
+}
+
+// pruneLocked removes the progress of prefetches which finished before the
+// retention period. The lock must be held.
+func (p *prefetcher) pruneLocked() {
+	cutoff := time.Now().Add(-prefetchRetention).UnixNano()
+	for id, pf := range p.jobs {
+		if pf.Terminal() && pf.EndTime < cutoff {
+			delete(p.jobs, id)
+		}
+	}
+}
+
+// run fetches the images and artifacts of the prefetch one at a time.
+func (p *prefetcher) run(pf *structs.NodePrefetch, imageAuths map[string]*structs.PrefetchImageAuth, artifacts []*structs.TaskArtifact) {
+	logger := p.logger.With("namespace", pf.Namespace, "job_id", pf.JobID)
+	p.update(func() { pf.Status = structs.NodePrefetchStatusRunning })
+
+	var imageDriver drivers.ImagePrefetchDriver
+	if len(pf.Images) > 0 {
+		if driver, err := p.c.drivermanager.Dispense(prefetchImageDriver); err == nil {
+			imageDriver, _ = driver.(drivers.ImagePrefetchDriver)
+		}
+	}
+
+	pulled := false
+	for _, item := range pf.Images {
+		if p.shuttingDown() {
+			break
+		}
+
+		if imageDriver == nil {
+			p.finishItem(item, structs.NodePrefetchStatusSkipped,
+				"the docker driver is not available to pull images")
+			continue
+		}
+
+		p.update(func() { item.Status = structs.NodePrefetchStatusRunning })
+		if err := imageDriver.PrefetchImage(item.Source, imageAuths[item.Source]); err != nil {
+			logger.Warn("failed to prefetch image", "image", item.Source, "error", err)
+			p.finishItem(item, structs.NodePrefetchStatusFailed, err.Error())
+			continue
+		}
+
+		p.c.UpdateNode(func(node *structs.Node) {
+			if !slices.Contains(node.PrefetchedImages, item.Source) {
+				node.PrefetchedImages = append(node.PrefetchedImages, item.Source)
+			}
+		})
+		pulled = true
+		p.finishItem(item, structs.NodePrefetchStatusComplete, "")
+	}
+
+	// let the scheduler prefer this node for the images it now holds
+	if pulled {
+		p.c.updateNode()
+	}
+
+	for i, item := range pf.Artifacts {
+		if p.shuttingDown() {
+			break
+		}
+
+		p.update(func() { item.Status = structs.NodePrefetchStatusRunning })
+		err := p.c.getter.Prefetch(artifacts[i])
+		switch {
+		case errors.Is(err, getter.ErrNotCacheable):
+			p.finishItem(item, structs.NodePrefetchStatusSkipped, err.Error())
+		case err != nil:
+			logger.Warn("failed to prefetch artifact", "source", item.Source, "error", err)
+			p.finishItem(item, structs.NodePrefetchStatusFailed, err.Error())
+		default:
+			p.finishItem(item, structs.NodePrefetchStatusComplete, "")
+		}
+	}
+
+	p.update(func() {
+		pf.Status = structs.NodePrefetchStatusComplete
+		for _, item := range append(slices.Clone(pf.Images), pf.Artifacts...) {
+			if item.Status != structs.NodePrefetchStatusComplete &&
+				item.Status != structs.NodePrefetchStatusSkipped {
+				pf.Status = structs.NodePrefetchStatusFailed
+			}
+		}
+		pf.EndTime = time.Now().UnixNano()
+	})
+	logger.Debug("prefetch finished", "status", pf.Status)
+}
+
+// watchImages periodically removes the images which are no longer present
+// from the prefetched images of the node, so that the scheduler doesn't
+// prefer the node for images it would have to pull again.
+func (p *prefetcher) watchImages() {
+	ticker := time.NewTicker(prefetchImageCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.c.shutdownCh:
+			return
+		case <-ticker.C:
+			p.pruneImages()
+		}
+	}
+}
+
+// pruneImages removes the images which are no longer present from the
+// prefetched images of the node.
+func (p *prefetcher) pruneImages() {
+	images := p.c.Node().PrefetchedImages
+	if len(images) == 0 {
+		return
+	}
+
+	driver, err := p.c.drivermanager.Dispense(prefetchImageDriver)
+	if err != nil {
+		return
+	}
+	imageDriver, ok := driver.(drivers.ImagePrefetchDriver)
+	if !ok {
+		return
+	}
+
+	var removed []string
+	for _, image := range images {
+		present, err := imageDriver.ImagePresent(image)
+		if err != nil {
+			p.logger.Debug("failed to check prefetched image", "image", image, "error", err)
+			continue
+		}
+		if !present {
+			removed = append(removed, image)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	p.c.UpdateNode(func(node *structs.Node) {
+		node.PrefetchedImages = slices.DeleteFunc(slices.Clone(node.PrefetchedImages), func(image string) bool {
+			return slices.Contains(removed, image)
+		})
+	})
+	p.c.updateNode()
+}
+
+// update applies fn to the progress of a prefetch while holding the lock.
+func (p *prefetcher) update(fn func()) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	fn()
+}
+
+// finishItem sets the final status of a prefetched image or artifact.
+func (p *prefetcher) finishItem(item *structs.NodePrefetchItem, status, err string) {
+	p.update(func() {
+		item.Status = status
+		item.Error = err
+	})
+}
+
+// shuttingDown returns whether the client is shutting down, in which case
+// remaining items are left pending and the prefetch fails.
+func (p *prefetcher) shuttingDown() bool {
+	select {
+	case <-p.c.shutdownCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// redactPrefetchSource removes the credentials and query of the source of an
+// artifact, which may contain secrets, before it is reported.
+func redactPrefetchSource(source string) string {
+	u, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
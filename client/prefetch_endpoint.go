@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodePrefetch endpoint is used for fetching the container images and
+// artifacts of jobs before they are placed on the client.
+type NodePrefetch struct {
+	c *Client
+}
+
+func newNodePrefetchEndpoint(c *Client) *NodePrefetch {
+	return &NodePrefetch{c: c}
+}
+
+// Start starts prefetching the images and artifacts of a job.
+func (n *NodePrefetch) Start(args *structs.NodePrefetchRequest, reply *structs.NodePrefetchResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_prefetch", "start"}, time.Now())
+
+	// Check submit job permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Prefetch = n.c.prefetcher.start(args)
+	return nil
+}
+
+// Status returns the progress of prefetching a job, which is nil if the job
+// was not prefetched.
+func (n *NodePrefetch) Status(args *structs.NodePrefetchStatusRequest, reply *structs.NodePrefetchResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_prefetch", "status"}, time.Now())
+
+	// Check read job permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Prefetch = n.c.prefetcher.status(args.RequestNamespace(), args.JobID)
+	return nil
+}
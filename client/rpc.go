@@ -22,12 +22,14 @@ import (
 
 // rpcEndpoints holds the RPC endpoints
 type rpcEndpoints struct {
-	ClientStats *ClientStats
-	CSI         *CSI
-	FileSystem  *FileSystem
-	Allocations *Allocations
-	Agent       *Agent
-	NodeMeta    *NodeMeta
+	ClientStats   *ClientStats
+	CSI           *CSI
+	FileSystem    *FileSystem
+	Allocations   *Allocations
+	Agent         *Agent
+	NodeMeta      *NodeMeta
+	ArtifactCache *ArtifactCache
+	NodePrefetch  *NodePrefetch
 }
 
 // ClientRPC is used to make a local, client only RPC call
@@ -293,6 +295,8 @@ func (c *Client) setupClientRpc(rpcs map[string]interface{}) {
 		c.endpoints.Allocations = NewAllocationsEndpoint(c)
 		c.endpoints.Agent = NewAgentEndpoint(c)
 		c.endpoints.NodeMeta = newNodeMetaEndpoint(c)
+		c.endpoints.ArtifactCache = newArtifactCacheEndpoint(c)
+		c.endpoints.NodePrefetch = newNodePrefetchEndpoint(c)
 		c.setupClientRpcServer(c.rpcServer)
 	}
 
@@ -308,6 +312,8 @@ func (c *Client) setupClientRpcServer(server *rpc.Server) {
 	server.Register(c.endpoints.Allocations)
 	server.Register(c.endpoints.Agent)
 	server.Register(c.endpoints.NodeMeta)
+	server.Register(c.endpoints.ArtifactCache)
+	server.Register(c.endpoints.NodePrefetch)
 }
 
 // rpcConnListener is a long lived function that listens for new connections
@@ -150,13 +150,15 @@ func GetAddress(
 			return netStatus.Address, 0, nil
 		}
 
-		// If port is a label and is found then return it
+		// If port is a label and is found then return it, using the address
+		// of the network that defines the port
 		if port, ok := ports.Get(portLabel); ok {
+			_, addr := netStatus.AddressForPort(portLabel)
 			// Use port.To value unless not set
 			if port.To > 0 {
-				return netStatus.Address, port.To, nil
+				return addr, port.To, nil
 			}
-			return netStatus.Address, port.Value, nil
+			return addr, port.Value, nil
 		}
 
 		// Check if port is a literal number
@@ -316,6 +316,29 @@ func Test_GetAddress(t *testing.T) {
 			expIP:   "172.26.0.1",
 			expPort: 12345,
 		},
+		{
+			name:      "Alloc multiple networks",
+			mode:      structs.AddressModeAlloc,
+			portLabel: "nfs",
+			ports: []structs.AllocatedPortMapping{
+				{
+					Label:  "nfs",
+					Value:  12345,
+					To:     2049,
+					HostIP: HostIP,
+				},
+			},
+			status: &structs.AllocNetworkStatus{
+				InterfaceName: "eth0",
+				Address:       "172.26.0.1",
+				Interfaces: []*structs.AllocNetworkInterfaceStatus{
+					{Mode: "bridge", InterfaceName: "eth0", Address: "172.26.0.1"},
+					{Mode: "cni/storage", InterfaceName: "eth1", Address: "10.0.5.2", PortLabels: []string{"nfs"}},
+				},
+			},
+			expIP:   "10.0.5.2",
+			expPort: 2049,
+		},
 		{
 			name:      "AllocCustomPort",
 			mode:      structs.AddressModeAlloc,
@@ -1002,13 +1002,15 @@ func buildUpstreamsEnv(envMap map[string]string, upstreams []structs.ConsulUpstr
 
 // addNomadAllocNetwork builds NOMAD_ALLOC_{IP,INTERFACE,ADDR}_{port_label}
 // vars. NOMAD_ALLOC_PORT_* is handled within addPorts and therefore omitted
-// from this function.
+// from this function. Ports of groups with multiple networks use the
+// interface of the network that defines them.
 func addNomadAllocNetwork(envMap map[string]string, p structs.AllocatedPorts, netStatus *structs.AllocNetworkStatus) {
 	for _, allocatedPort := range p {
 		portStr := strconv.Itoa(allocatedPort.To)
-		envMap[AllocPrefix+"INTERFACE_"+allocatedPort.Label] = netStatus.InterfaceName
-		envMap[AllocPrefix+"IP_"+allocatedPort.Label] = netStatus.Address
-		envMap[AllocPrefix+"ADDR_"+allocatedPort.Label] = net.JoinHostPort(netStatus.Address, portStr)
+		ifName, addr := netStatus.AddressForPort(allocatedPort.Label)
+		envMap[AllocPrefix+"INTERFACE_"+allocatedPort.Label] = ifName
+		envMap[AllocPrefix+"IP_"+allocatedPort.Label] = addr
+		envMap[AllocPrefix+"ADDR_"+allocatedPort.Label] = net.JoinHostPort(addr, portStr)
 	}
 }
 
@@ -865,6 +865,29 @@ func Test_addNetNamespacePort(t *testing.T) {
 			},
 			name: "multiple input ports",
 		},
+		{
+			inputPorts: structs.AllocatedPorts{
+				{Label: "http", To: 80},
+				{Label: "nfs", To: 2049},
+			},
+			inputNetwork: &structs.AllocNetworkStatus{
+				InterfaceName: "eth0",
+				Address:       "172.26.64.11",
+				Interfaces: []*structs.AllocNetworkInterfaceStatus{
+					{Mode: "bridge", InterfaceName: "eth0", Address: "172.26.64.11", PortLabels: []string{"http"}},
+					{Mode: "cni/storage", InterfaceName: "eth1", Address: "10.0.5.2", PortLabels: []string{"nfs"}},
+				},
+			},
+			expectedOutput: map[string]string{
+				"NOMAD_ALLOC_INTERFACE_http": "eth0",
+				"NOMAD_ALLOC_IP_http":        "172.26.64.11",
+				"NOMAD_ALLOC_ADDR_http":      "172.26.64.11:80",
+				"NOMAD_ALLOC_INTERFACE_nfs":  "eth1",
+				"NOMAD_ALLOC_IP_nfs":         "10.0.5.2",
+				"NOMAD_ALLOC_ADDR_nfs":       "10.0.5.2:2049",
+			},
+			name: "multiple networks",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -4,9 +4,16 @@
 package widmgr
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net/url"
 	"slices"
 	"time"
 
@@ -26,6 +33,10 @@ type MockWIDSigner struct {
 	key     *rsa.PrivateKey
 	keyID   string
 	mockNow time.Time // allows moving the clock
+
+	// caKey and caCert sign the X.509 SVIDs of requests with a CSR
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
 }
 
 func NewMockWIDSigner(wids []*structs.WorkloadIdentity) *MockWIDSigner {
@@ -33,9 +44,15 @@ func NewMockWIDSigner(wids []*structs.WorkloadIdentity) *MockWIDSigner {
 	if err != nil {
 		panic(err)
 	}
+	caKey, caCert, err := newMockCA()
+	if err != nil {
+		panic(err)
+	}
 	m := &MockWIDSigner{
-		key:   privKey,
-		keyID: uuid.Generate(),
+		key:    privKey,
+		keyID:  uuid.Generate(),
+		caKey:  caKey,
+		caCert: caCert,
 	}
 	if wids != nil {
 		m.setWIDs(wids)
@@ -43,6 +60,61 @@ func NewMockWIDSigner(wids []*structs.WorkloadIdentity) *MockWIDSigner {
 	return m
 }
 
+// newMockCA returns a self-signed CA for signing X.509 SVIDs.
+func newMockCA() (*ecdsa.PrivateKey, *x509.Certificate, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Mock Workload Identity CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return caKey, caCert, nil
+}
+
+// X509Bundle returns the PEM encoded CA certificate which signs X.509 SVIDs.
+func (m *MockWIDSigner) X509Bundle() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: m.caCert.Raw})
+}
+
+// signX509SVID signs a PEM encoded X.509 SVID for the CSR.
+func (m *MockWIDSigner) signX509SVID(claims *structs.IdentityClaims, csr []byte) ([]byte, error) {
+	req, err := x509.ParseCertificateRequest(csr)
+	if err != nil {
+		return nil, err
+	}
+	spiffeID, err := url.Parse(claims.SPIFFEID("global"))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(m.now().UnixNano()),
+		NotBefore:    m.now().Add(-time.Minute),
+		NotAfter:     claims.Expiry.Time(),
+		URIs:         []*url.URL{spiffeID},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, m.caCert, req.PublicKey, m.caKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
 // setWIDs is a test helper to use Task.Identities in the MockWIDSigner for
 // sharing TTLs and validating names.
 func (m *MockWIDSigner) setWIDs(wids []*structs.WorkloadIdentity) {
@@ -109,6 +181,14 @@ func (m *MockWIDSigner) SignIdentities(minIndex uint64, req []*structs.WorkloadI
 			JWT:                     token,
 			Expiration:              claims.Expiry.Time(),
 		}
+		if len(idReq.CSR) > 0 && claims.Expiry != nil {
+			swid.X509SVID, err = m.signX509SVID(claims, idReq.CSR)
+			if err != nil {
+				return nil, fmt.Errorf("error signing x509 svid: %w", err)
+			}
+			swid.X509Bundle = m.X509Bundle()
+			swid.CSR = nil
+		}
 		swids = append(swids, swid)
 	}
 	return swids, nil
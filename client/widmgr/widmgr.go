@@ -294,11 +294,17 @@ func (m *WIDMgr) getInitialIdentities() error {
 	// Get signed workload identities
 	signedWIDs := []*structs.SignedWorkloadIdentity{}
 	if len(m.widSpecs) != 0 {
-		var err error
+		x509Keys, err := m.setCSRs(reqs)
+		if err != nil {
+			return err
+		}
 		signedWIDs, err = m.signer.SignIdentities(m.minIndex, reqs)
 		if err != nil {
 			return err
 		}
+		if err := setX509Keys(x509Keys, signedWIDs); err != nil {
+			return err
+		}
 	}
 
 	// Store default identity tokens
@@ -389,8 +395,20 @@ func (m *WIDMgr) renew() {
 			return
 		}
 
-		// Renew all tokens together since its cheap
+		// Renew all tokens together since its cheap. X.509 SVIDs get a new
+		// private key on each renewal.
+		x509Keys, err := m.setCSRs(reqs)
+		if err != nil {
+			retry++
+			wait = helper.Backoff(m.minWait, time.Hour, retry) + helper.RandomStagger(m.minWait)
+			m.logger.Error("error renewing workload identities", "error", err, "next", wait)
+			continue
+		}
+
 		tokens, err := m.signer.SignIdentities(m.minIndex, reqs)
+		if err == nil {
+			err = setX509Keys(x509Keys, tokens)
+		}
 		if err != nil {
 			retry++
 			wait = helper.Backoff(m.minWait, time.Hour, retry) + helper.RandomStagger(m.minWait)
@@ -4,6 +4,7 @@
 package widmgr
 
 import (
+	"crypto/tls"
 	"testing"
 	"time"
 
@@ -65,3 +66,48 @@ func TestWIDMgr_Restore(t *testing.T) {
 	must.NoError(t, err)
 	must.True(t, hasExpired)
 }
+
+func TestWIDMgr_X509(t *testing.T) {
+
+	logger := testlog.HCLogger(t)
+
+	db := cstate.NewMemDB(logger)
+
+	alloc := mock.Alloc()
+	widSpecs := []*structs.WorkloadIdentity{
+		{Name: "default"},
+		{Name: "svid", TTL: time.Hour, X509: true},
+	}
+	alloc.Job.TaskGroups[0].Tasks[0].Identities = widSpecs
+	envBuilder := taskenv.NewBuilder(mock.Node(), alloc, nil, "global")
+
+	signer := NewMockWIDSigner(widSpecs)
+	mgr := NewWIDMgr(signer, alloc, db, logger, envBuilder)
+	must.NoError(t, mgr.getInitialIdentities())
+
+	// identities without x509 are only signed as JWTs
+	swid := mgr.get(structs.WIHandle{
+		WorkloadIdentifier: "web",
+		WorkloadType:       structs.WorkloadTypeTask,
+		IdentityName:       "default",
+	})
+	must.NotNil(t, swid)
+	must.Nil(t, swid.X509SVID)
+	must.Nil(t, swid.X509Key)
+
+	swid = mgr.get(structs.WIHandle{
+		WorkloadIdentifier: "web",
+		WorkloadType:       structs.WorkloadTypeTask,
+		IdentityName:       "svid",
+	})
+	must.NotNil(t, swid)
+	must.Nil(t, swid.CSR)
+	must.Eq(t, signer.X509Bundle(), swid.X509Bundle)
+
+	// the svid must be issued for the private key kept by the client
+	cert, err := tls.X509KeyPair(swid.X509SVID, swid.X509Key)
+	must.NoError(t, err)
+	must.SliceLen(t, 1, cert.Leaf.URIs)
+	must.Eq(t, "spiffe://global.nomad/ns/default/job/test/task/web",
+		cert.Leaf.URIs[0].String())
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package widmgr
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// setCSRs generates a private key and a certificate signing request for the
+// requests of identities with X509 set. The private keys never leave the
+// client; they are returned PEM encoded by workload handle so they can be set
+// on the signed identities.
+func (m *WIDMgr) setCSRs(reqs []*structs.WorkloadIdentityRequest) (map[structs.WIHandle][]byte, error) {
+	keys := map[structs.WIHandle][]byte{}
+	for _, req := range reqs {
+		if widspec := m.widSpecs[req.WIHandle]; widspec == nil || !widspec.X509 {
+			continue
+		}
+
+		key, csr, err := newX509CSR()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create x509 svid request for identity %q: %w",
+				req.IdentityName, err)
+		}
+		req.CSR = csr
+		keys[req.WIHandle] = key
+	}
+	return keys, nil
+}
+
+// setX509Keys sets the private keys generated by setCSRs on the signed
+// identities, and returns an error if the server did not sign an X.509 SVID
+// for a request which had a CSR.
+func setX509Keys(keys map[structs.WIHandle][]byte, swids []*structs.SignedWorkloadIdentity) error {
+	for _, swid := range swids {
+		key, ok := keys[swid.WIHandle]
+		if !ok {
+			continue
+		}
+		if len(swid.X509SVID) == 0 {
+			return fmt.Errorf("no x509 svid was signed for identity %q; all servers must be upgraded",
+				swid.IdentityName)
+		}
+		swid.X509Key = key
+	}
+	return nil
+}
+
+// newX509CSR generates an ECDSA P-256 private key and returns it PEM encoded,
+// along with an ASN.1 DER encoded certificate signing request for it. The
+// server sets the SPIFFE ID of the SVID, so the request has no subject.
+func newX509CSR() ([]byte, []byte, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return key, csr, nil
+}
@@ -78,6 +78,8 @@ func formatAuthMethod(authMethod *api.ACLAuthMethod) string {
 		fmt.Sprintf("Type|%s", authMethod.Type),
 		fmt.Sprintf("Locality|%s", authMethod.TokenLocality),
 		fmt.Sprintf("Max Token TTL|%s", authMethod.MaxTokenTTL.String()),
+		fmt.Sprintf("Token TTL|%s", authMethod.TokenTTL.String()),
+		fmt.Sprintf("Token Renewable|%t", authMethod.TokenRenewable),
 		fmt.Sprintf("Token Name Format|%s", authMethod.TokenNameFormat),
 		fmt.Sprintf("Default|%t", authMethod.Default),
 		fmt.Sprintf("Create Index|%d", authMethod.CreateIndex),
@@ -94,6 +96,7 @@ func formatAuthMethodConfig(config *api.ACLAuthMethodConfig) string {
 		fmt.Sprintf("OIDC Client ID|%s", config.OIDCClientID),
 		fmt.Sprintf("OIDC Client Secret|%s", config.OIDCClientSecret),
 		fmt.Sprintf("OIDC Disable UserInfo|%t", config.OIDCDisableUserInfo),
+		fmt.Sprintf("OIDC Enable PKCE|%t", config.OIDCEnablePKCE),
 		fmt.Sprintf("OIDC Scopes|%s", strings.Join(config.OIDCScopes, ",")),
 		fmt.Sprintf("Bound audiences|%s", strings.Join(config.BoundAudiences, ",")),
 		fmt.Sprintf("Bound issuer|%s", strings.Join(config.BoundIssuer, ",")),
@@ -106,6 +109,19 @@ func formatAuthMethodConfig(config *api.ACLAuthMethodConfig) string {
 		fmt.Sprintf("ClockSkew Leeway|%s", config.ClockSkewLeeway.String()),
 		fmt.Sprintf("Claim mappings|%s", strings.Join(formatMap(config.ClaimMappings), "; ")),
 		fmt.Sprintf("List claim mappings|%s", strings.Join(formatMap(config.ListClaimMappings), "; ")),
+		fmt.Sprintf("LDAP URLs|%s", strings.Join(config.LDAPURLs, ",")),
+		fmt.Sprintf("LDAP StartTLS|%t", config.LDAPStartTLS),
+		fmt.Sprintf("LDAP Insecure Skip Verify|%t", config.LDAPInsecureSkipVerify),
+		fmt.Sprintf("LDAP CA cert|%s", config.LDAPCACert),
+		fmt.Sprintf("LDAP Bind DN|%s", config.LDAPBindDN),
+		fmt.Sprintf("LDAP Bind Password|%s", config.LDAPBindPassword),
+		fmt.Sprintf("LDAP User DN|%s", config.LDAPUserDN),
+		fmt.Sprintf("LDAP User Attr|%s", config.LDAPUserAttr),
+		fmt.Sprintf("LDAP User Filter|%s", config.LDAPUserFilter),
+		fmt.Sprintf("LDAP Group DN|%s", config.LDAPGroupDN),
+		fmt.Sprintf("LDAP Group Attr|%s", config.LDAPGroupAttr),
+		fmt.Sprintf("LDAP Group Filter|%s", config.LDAPGroupFilter),
+		fmt.Sprintf("Cert CA certs|%s", strings.Join(config.CertCACerts, ",")),
 	}
 	return formatKV(out)
 }
@@ -28,6 +28,8 @@ type ACLAuthMethodCreateCommand struct {
 	tokenLocality   string
 	tokenNameFormat string
 	maxTokenTTL     time.Duration
+	tokenTTL        time.Duration
+	tokenRenewable  bool
 	isDefault       bool
 	config          string
 	json            bool
@@ -54,12 +56,21 @@ ACL Auth Method Create Options:
     between 1-128 characters and is a required parameter.
 
   -type
-    Sets the type of the auth method. Supported types are 'OIDC' and 'JWT'.
+    Sets the type of the auth method. Supported types are 'OIDC', 'JWT',
+    'LDAP' and 'CERT'.
 
   -max-token-ttl
     Sets the duration of time all tokens created by this auth method should be
     valid for.
 
+  -token-ttl
+    Sets the duration of time tokens created by this auth method are valid
+    for before they expire or must be renewed. Defaults to the max token TTL.
+
+  -token-renewable
+    Specifies whether tokens created by this auth method can be renewed, up to
+    the max token TTL.
+
   -token-locality
     Defines the kind of token that this auth method should produce. This can be
     either 'local' or 'global'.
@@ -90,8 +101,10 @@ func (a *ACLAuthMethodCreateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-name":              complete.PredictAnything,
-			"-type":              complete.PredictSet("OIDC", "JWT"),
+			"-type":              complete.PredictSet("OIDC", "JWT", "LDAP", "CERT"),
 			"-max-token-ttl":     complete.PredictAnything,
+			"-token-ttl":         complete.PredictAnything,
+			"-token-renewable":   complete.PredictSet("true", "false"),
 			"-token-locality":    complete.PredictSet("local", "global"),
 			"-token-name-format": complete.PredictNothing,
 			"-default":           complete.PredictSet("true", "false"),
@@ -121,6 +134,8 @@ func (a *ACLAuthMethodCreateCommand) Run(args []string) int {
 	flags.StringVar(&a.tokenLocality, "token-locality", "", "")
 	flags.StringVar(&a.tokenNameFormat, "token-name-format", "", "")
 	flags.DurationVar(&a.maxTokenTTL, "max-token-ttl", 0, "")
+	flags.DurationVar(&a.tokenTTL, "token-ttl", 0, "")
+	flags.BoolVar(&a.tokenRenewable, "token-renewable", false, "")
 	flags.BoolVar(&a.isDefault, "default", false, "")
 	flags.StringVar(&a.config, "config", "", "")
 	flags.BoolVar(&a.json, "json", false, "")
@@ -149,8 +164,12 @@ func (a *ACLAuthMethodCreateCommand) Run(args []string) int {
 		a.Ui.Error("Max token TTL must be set to a value between min and max TTL configured for the server.")
 		return 1
 	}
-	if !slices.Contains([]string{"OIDC", "JWT"}, strings.ToUpper(a.methodType)) {
-		a.Ui.Error("ACL auth method type must be set to 'OIDC' or 'JWT'")
+	if a.tokenTTL < 0 || a.tokenTTL > a.maxTokenTTL {
+		a.Ui.Error("Token TTL must not be negative or more than the max token TTL")
+		return 1
+	}
+	if !slices.Contains([]string{"OIDC", "JWT", "LDAP", "CERT"}, strings.ToUpper(a.methodType)) {
+		a.Ui.Error("ACL auth method type must be set to 'OIDC', 'JWT', 'LDAP' or 'CERT'")
 		return 1
 	}
 	if len(a.config) == 0 {
@@ -178,6 +197,8 @@ func (a *ACLAuthMethodCreateCommand) Run(args []string) int {
 		TokenLocality:   a.tokenLocality,
 		TokenNameFormat: a.tokenNameFormat,
 		MaxTokenTTL:     a.maxTokenTTL,
+		TokenTTL:        a.tokenTTL,
+		TokenRenewable:  a.tokenRenewable,
 		Default:         a.isDefault,
 		Config:          &configJSON,
 	}
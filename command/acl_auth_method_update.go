@@ -28,6 +28,8 @@ type ACLAuthMethodUpdateCommand struct {
 	tokenLocality   string
 	tokenNameFormat string
 	maxTokenTTL     time.Duration
+	tokenTTL        time.Duration
+	tokenRenewable  bool
 	isDefault       bool
 	config          string
 	json            bool
@@ -50,12 +52,21 @@ General Options:
 ACL Auth Method Update Options:
 
   -type
-    Updates the type of the auth method. Supported types are 'OIDC' and 'JWT'.
+    Updates the type of the auth method. Supported types are 'OIDC', 'JWT',
+    'LDAP' and 'CERT'.
 
   -max-token-ttl
     Updates the duration of time all tokens created by this auth method should be
     valid for.
 
+  -token-ttl
+    Updates the duration of time tokens created by this auth method are valid
+    for before they expire or must be renewed. Defaults to the max token TTL.
+
+  -token-renewable
+    Updates whether tokens created by this auth method can be renewed, up to
+    the max token TTL.
+
   -token-locality
     Updates the kind of token that this auth method should produce. This can be
     either 'local' or 'global'.
@@ -86,8 +97,10 @@ ACL Auth Method Update Options:
 func (a *ACLAuthMethodUpdateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-type":              complete.PredictSet("OIDC", "JWT"),
+			"-type":              complete.PredictSet("OIDC", "JWT", "LDAP", "CERT"),
 			"-max-token-ttl":     complete.PredictAnything,
+			"-token-ttl":         complete.PredictAnything,
+			"-token-renewable":   complete.PredictSet("true", "false"),
 			"-token-locality":    complete.PredictSet("local", "global"),
 			"-token-name-format": complete.PredictNothing,
 			"-default":           complete.PredictSet("true", "false"),
@@ -116,6 +129,8 @@ func (a *ACLAuthMethodUpdateCommand) Run(args []string) int {
 	flags.StringVar(&a.tokenLocality, "token-locality", "", "")
 	flags.StringVar(&a.tokenNameFormat, "token-name-format", "", "")
 	flags.DurationVar(&a.maxTokenTTL, "max-token-ttl", 0, "")
+	flags.DurationVar(&a.tokenTTL, "token-ttl", 0, "")
+	flags.BoolVar(&a.tokenRenewable, "token-renewable", false, "")
 	flags.StringVar(&a.config, "config", "", "")
 	flags.BoolVar(&a.isDefault, "default", false, "")
 	flags.BoolVar(&a.json, "json", false, "")
@@ -149,7 +164,7 @@ func (a *ACLAuthMethodUpdateCommand) Run(args []string) int {
 
 	// Check if any command-specific flags were set
 	setFlags := []string{}
-	for _, f := range []string{"type", "token-locality", "token-name-format", "max-token-ttl", "config", "default"} {
+	for _, f := range []string{"type", "token-locality", "token-name-format", "max-token-ttl", "token-ttl", "token-renewable", "config", "default"} {
 		if flagPassed(flags, f) {
 			setFlags = append(setFlags, f)
 		}
@@ -174,8 +189,8 @@ func (a *ACLAuthMethodUpdateCommand) Run(args []string) int {
 	}
 
 	if slices.Contains(setFlags, "type") {
-		if !slices.Contains([]string{"OIDC", "JWT"}, strings.ToUpper(a.methodType)) {
-			a.Ui.Error("ACL auth method type must be set to 'OIDC' or 'JWT'")
+		if !slices.Contains([]string{"OIDC", "JWT", "LDAP", "CERT"}, strings.ToUpper(a.methodType)) {
+			a.Ui.Error("ACL auth method type must be set to 'OIDC', 'JWT', 'LDAP' or 'CERT'")
 			return 1
 		}
 		updatedMethod.Type = a.methodType
@@ -189,6 +204,18 @@ func (a *ACLAuthMethodUpdateCommand) Run(args []string) int {
 		updatedMethod.MaxTokenTTL = a.maxTokenTTL
 	}
 
+	if slices.Contains(setFlags, "token-ttl") {
+		if a.tokenTTL < 0 {
+			a.Ui.Error("Token TTL must not be negative")
+			return 1
+		}
+		updatedMethod.TokenTTL = a.tokenTTL
+	}
+
+	if slices.Contains(setFlags, "token-renewable") {
+		updatedMethod.TokenRenewable = a.tokenRenewable
+	}
+
 	if slices.Contains(setFlags, "default") {
 		updatedMethod.Default = a.isDefault
 	}
@@ -192,6 +192,25 @@ func outputACLToken(ui cli.Ui, token *api.ACLToken) {
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	}
 
+	// Only renewable tokens have a max TTL, so only show the renewal details
+	// for these tokens.
+	if token.Renewable {
+		maxTTL := "<region max>"
+		if token.MaxTTL != 0 {
+			maxTTL = token.MaxTTL.String()
+		}
+		kvOutput = append(kvOutput,
+			fmt.Sprintf("Renewable|%v", token.Renewable),
+			fmt.Sprintf("TTL|%s", token.ExpirationTTL),
+			fmt.Sprintf("Max TTL|%s", maxTTL),
+		)
+	}
+
+	// Tokens derived for a task are tied to the lifetime of its allocation.
+	if token.AllocID != "" {
+		kvOutput = append(kvOutput, fmt.Sprintf("Alloc ID|%s", token.AllocID))
+	}
+
 	// If the token is a management type, make it obvious that it is not
 	// possible to have policies or roles assigned to it and just output the
 	// KV data.
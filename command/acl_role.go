@@ -67,14 +67,30 @@ func (a *ACLRoleCommand) Run(_ []string) int { return cli.RunResultHelp }
 // formatACLRole formats and converts the ACL role API object into a string KV
 // representation suitable for console output.
 func formatACLRole(aclRole *api.ACLRole) string {
-	return formatKV([]string{
+	out := []string{
 		fmt.Sprintf("ID|%s", aclRole.ID),
 		fmt.Sprintf("Name|%s", aclRole.Name),
 		fmt.Sprintf("Description|%s", aclRole.Description),
 		fmt.Sprintf("Policies|%s", strings.Join(aclRolePolicyLinkToStringList(aclRole.Policies), ",")),
+	}
+
+	// Only show the token settings for roles that use them.
+	if aclRole.TokenRenewable {
+		maxTTL := "<region max>"
+		if aclRole.TokenMaxTTL != 0 {
+			maxTTL = aclRole.TokenMaxTTL.String()
+		}
+		out = append(out,
+			"Token Renewable|true",
+			fmt.Sprintf("Token Max TTL|%s", maxTTL),
+		)
+	}
+
+	out = append(out,
 		fmt.Sprintf("Create Index|%d", aclRole.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", aclRole.ModifyIndex),
-	})
+	)
+	return formatKV(out)
 }
 
 // aclRolePolicyLinkToStringList converts an array of ACL role policy links to
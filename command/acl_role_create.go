@@ -6,6 +6,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
@@ -22,8 +23,12 @@ type ACLRoleCreateCommand struct {
 	name        string
 	description string
 	policyNames []string
-	json        bool
-	tmpl        string
+
+	tokenRenewable bool
+	tokenMaxTTL    time.Duration
+
+	json bool
+	tmpl string
 }
 
 // Help satisfies the cli.Command Help function.
@@ -51,6 +56,13 @@ ACL Create Options:
     Specifies a policy to associate with the role identified by their name. This
     flag can be specified multiple times and must be specified at least once.
 
+  -token-renewable
+    Specifies whether expiring tokens created with the role can be renewed.
+
+  -token-max-ttl
+    Sets the maximum lifetime of renewable tokens created with the role. When
+    unset, the region's maximum token expiration TTL is used.
+
   -json
     Output the ACL role in a JSON format.
 
@@ -63,11 +75,13 @@ ACL Create Options:
 func (a *ACLRoleCreateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-name":        complete.PredictAnything,
-			"-description": complete.PredictAnything,
-			"-policy":      complete.PredictAnything,
-			"-json":        complete.PredictNothing,
-			"-t":           complete.PredictAnything,
+			"-name":            complete.PredictAnything,
+			"-description":     complete.PredictAnything,
+			"-policy":          complete.PredictAnything,
+			"-token-renewable": complete.PredictSet("true", "false"),
+			"-token-max-ttl":   complete.PredictAnything,
+			"-json":            complete.PredictNothing,
+			"-t":               complete.PredictAnything,
 		})
 }
 
@@ -90,6 +104,8 @@ func (a *ACLRoleCreateCommand) Run(args []string) int {
 		a.policyNames = append(a.policyNames, s)
 		return nil
 	}), "policy", "")
+	flags.BoolVar(&a.tokenRenewable, "token-renewable", false, "")
+	flags.DurationVar(&a.tokenMaxTTL, "token-max-ttl", 0, "")
 	flags.BoolVar(&a.json, "json", false, "")
 	flags.StringVar(&a.tmpl, "t", "", "")
 	if err := flags.Parse(args); err != nil {
@@ -119,6 +135,9 @@ func (a *ACLRoleCreateCommand) Run(args []string) int {
 		Name:        a.name,
 		Description: a.description,
 		Policies:    aclRolePolicyNamesToPolicyLinks(a.policyNames),
+
+		TokenRenewable: a.tokenRenewable,
+		TokenMaxTTL:    a.tokenMaxTTL,
 	}
 
 	// Get the HTTP client.
@@ -6,6 +6,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/mitchellh/cli"
@@ -22,9 +23,13 @@ type ACLRoleUpdateCommand struct {
 	name        string
 	description string
 	policyNames []string
-	noMerge     bool
-	json        bool
-	tmpl        string
+
+	tokenRenewable bool
+	tokenMaxTTL    time.Duration
+
+	noMerge bool
+	json    bool
+	tmpl    string
 }
 
 // Help satisfies the cli.Command Help function.
@@ -57,6 +62,13 @@ Update Options:
     command. Instead overwrite all fields with the exception of the role ID
     which is immutable.
 
+  -token-renewable
+    Specifies whether expiring tokens created with the role can be renewed.
+
+  -token-max-ttl
+    Sets the maximum lifetime of renewable tokens created with the role. When
+    unset, the region's maximum token expiration TTL is used.
+
   -json
     Output the ACL role in a JSON format.
 
@@ -70,12 +82,14 @@ Update Options:
 func (a *ACLRoleUpdateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-name":        complete.PredictAnything,
-			"-description": complete.PredictAnything,
-			"-no-merge":    complete.PredictNothing,
-			"-policy":      complete.PredictAnything,
-			"-json":        complete.PredictNothing,
-			"-t":           complete.PredictAnything,
+			"-name":            complete.PredictAnything,
+			"-description":     complete.PredictAnything,
+			"-no-merge":        complete.PredictNothing,
+			"-policy":          complete.PredictAnything,
+			"-token-renewable": complete.PredictSet("true", "false"),
+			"-token-max-ttl":   complete.PredictAnything,
+			"-json":            complete.PredictNothing,
+			"-t":               complete.PredictAnything,
 		})
 }
 
@@ -98,6 +112,8 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		a.policyNames = append(a.policyNames, s)
 		return nil
 	}), "policy", "")
+	flags.BoolVar(&a.tokenRenewable, "token-renewable", false, "")
+	flags.DurationVar(&a.tokenMaxTTL, "token-max-ttl", 0, "")
 	flags.BoolVar(&a.noMerge, "no-merge", false, "")
 	flags.BoolVar(&a.json, "json", false, "")
 	flags.StringVar(&a.tmpl, "t", "", "")
@@ -152,11 +168,17 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 			Name:        a.name,
 			Description: a.description,
 			Policies:    aclRolePolicyNamesToPolicyLinks(a.policyNames),
+
+			TokenRenewable: a.tokenRenewable,
+			TokenMaxTTL:    a.tokenMaxTTL,
 		}
 	default:
 		// Check that the operator specified at least one flag to update the ACL
 		// role with.
-		if len(a.policyNames) == 0 && a.name == "" && a.description == "" {
+		tokenRenewableSet := flagPassed(flags, "token-renewable")
+		tokenMaxTTLSet := flagPassed(flags, "token-max-ttl")
+		if len(a.policyNames) == 0 && a.name == "" && a.description == "" &&
+			!tokenRenewableSet && !tokenMaxTTLSet {
 			a.Ui.Error("Please provide at least one flag to update the ACL role")
 			a.Ui.Error(commandErrorText(a))
 			return 1
@@ -172,6 +194,12 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		if a.description != "" {
 			updatedRole.Description = a.description
 		}
+		if tokenRenewableSet {
+			updatedRole.TokenRenewable = a.tokenRenewable
+		}
+		if tokenMaxTTLSet {
+			updatedRole.TokenMaxTTL = a.tokenMaxTTL
+		}
 
 		// In order to merge the policy updates, we need to identify if the
 		// specified policy names already exist within the ACL role linking.
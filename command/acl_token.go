@@ -34,6 +34,14 @@ Usage: nomad acl token <subcommand> [options] [args]
 
       $ nomad acl policy delete <token_accessor_id>
 
+  Renew the current token:
+
+      $ nomad acl token renew
+
+  Explain whether the current token can submit a job:
+
+      $ nomad acl token explain -job example namespace submit-job
+
   Please see the individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
@@ -57,6 +57,15 @@ Create Options:
     a time duration such as "5m" and "1h". By default, tokens will be created
     without a TTL and therefore never expire.
 
+  -renewable=false
+    Makes the token renewable. Renewing the token extends its expiration time
+    by its TTL, up to its max TTL since creation. Requires the -ttl flag.
+
+  -max-ttl
+    Specifies the maximum lifetime of a renewable token since its creation.
+    This takes the form of a time duration such as "24h". By default, the
+    maximum token TTL of the region is used.
+
   -json
     Output the ACL token information in JSON format.
 
@@ -76,6 +85,8 @@ func (c *ACLTokenCreateCommand) AutocompleteFlags() complete.Flags {
 			"role-id":   complete.PredictAnything,
 			"role-name": complete.PredictAnything,
 			"ttl":       complete.PredictAnything,
+			"renewable": complete.PredictNothing,
+			"max-ttl":   complete.PredictAnything,
 			"-json":     complete.PredictNothing,
 			"-t":        complete.PredictAnything,
 		})
@@ -92,8 +103,8 @@ func (c *ACLTokenCreateCommand) Synopsis() string {
 func (c *ACLTokenCreateCommand) Name() string { return "acl token create" }
 
 func (c *ACLTokenCreateCommand) Run(args []string) int {
-	var name, tokenType, ttl, tmpl string
-	var global, json bool
+	var name, tokenType, ttl, maxTTL, tmpl string
+	var global, renewable, json bool
 	var policies []string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -101,6 +112,8 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 	flags.StringVar(&tokenType, "type", "client", "")
 	flags.BoolVar(&global, "global", false, "")
 	flags.StringVar(&ttl, "ttl", "", "")
+	flags.BoolVar(&renewable, "renewable", false, "")
+	flags.StringVar(&maxTTL, "max-ttl", "", "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.Var((funcVar)(func(s string) error {
@@ -129,11 +142,12 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 
 	// Set up the token.
 	tk := &api.ACLToken{
-		Name:     name,
-		Type:     tokenType,
-		Policies: policies,
-		Roles:    generateACLTokenRoleLinks(c.roleNames, c.roleIDs),
-		Global:   global,
+		Name:      name,
+		Type:      tokenType,
+		Policies:  policies,
+		Roles:     generateACLTokenRoleLinks(c.roleNames, c.roleIDs),
+		Global:    global,
+		Renewable: renewable,
 	}
 
 	// If the user set a TTL flag value, convert this to a time duration and
@@ -146,6 +160,14 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		}
 		tk.ExpirationTTL = ttlDuration
 	}
+	if maxTTL != "" {
+		maxTTLDuration, err := time.ParseDuration(maxTTL)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse max TTL as time duration: %s", err))
+			return 1
+		}
+		tk.MaxTTL = maxTTLDuration
+	}
 
 	// Get the HTTP client
 	client, err := c.Meta.Client()
@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// Ensure ACLTokenExplainCommand satisfies the cli.Command interface.
+var _ cli.Command = &ACLTokenExplainCommand{}
+
+// ACLTokenExplainCommand implements cli.Command.
+type ACLTokenExplainCommand struct {
+	Meta
+
+	accessorID string
+	jobID      string
+	path       string
+	name       string
+	json       bool
+	tmpl       string
+}
+
+// Help satisfies the cli.Command Help function.
+func (c *ACLTokenExplainCommand) Help() string {
+	helpText := `
+Usage: nomad acl token explain [options] <type> <capability>
+
+  Explain is used to show whether an ACL token is allowed to perform an
+  operation, along with the policy rules that produced the decision and how
+  each policy is attached to the token. The type of the operation is one of
+  "namespace", "variable", "node_pool", "host_volume", "agent", "node",
+  "operator", "quota" or "plugin". The capability is a capability of the
+  operation type, such as "submit-job", or a policy disposition such as "read"
+  for the types without fine-grained capabilities.
+
+  By default the token used to make the request is explained. Explaining any
+  other token requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+ACL Token Explain Options:
+
+  -accessor
+    The accessor ID of the token to explain.
+
+  -job
+    The job of a namespace operation. Only job capabilities can be explained
+    for jobs.
+
+  -path
+    The path of a variable operation.
+
+  -name
+    The name of the node pool or host volume of the operation.
+
+  -json
+    Output the explanation in a JSON format.
+
+  -t
+    Format and display the explanation using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLTokenExplainCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-accessor": complete.PredictAnything,
+			"-job":      complete.PredictAnything,
+			"-path":     complete.PredictAnything,
+			"-name":     complete.PredictAnything,
+			"-json":     complete.PredictNothing,
+			"-t":        complete.PredictAnything,
+		})
+}
+
+func (c *ACLTokenExplainCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictSet(
+		api.ACLOperationTypeNamespace,
+		api.ACLOperationTypeVariable,
+		api.ACLOperationTypeNodePool,
+		api.ACLOperationTypeHostVolume,
+		api.ACLOperationTypeAgent,
+		api.ACLOperationTypeNode,
+		api.ACLOperationTypeOperator,
+		api.ACLOperationTypeQuota,
+		api.ACLOperationTypePlugin,
+	)
+}
+
+// Synopsis satisfies the cli.Command Synopsis function.
+func (c *ACLTokenExplainCommand) Synopsis() string {
+	return "Explain the ACL decision of a token for an operation"
+}
+
+// Name returns the name of this command.
+func (c *ACLTokenExplainCommand) Name() string { return "acl token explain" }
+
+// Run satisfies the cli.Command Run function.
+func (c *ACLTokenExplainCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&c.accessorID, "accessor", "", "")
+	flags.StringVar(&c.jobID, "job", "", "")
+	flags.StringVar(&c.path, "path", "", "")
+	flags.StringVar(&c.name, "name", "", "")
+	flags.BoolVar(&c.json, "json", false, "")
+	flags.StringVar(&c.tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly two arguments.
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <type> <capability>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	op := &api.ACLOperation{
+		Type:       args[0],
+		JobID:      c.jobID,
+		Path:       c.path,
+		Name:       c.name,
+		Capability: args[1],
+	}
+	explanation, _, err := client.ACLTokens().Explain(op, c.accessorID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error explaining ACL token: %s", err))
+		return 1
+	}
+
+	if c.json || len(c.tmpl) > 0 {
+		out, err := Format(c.json, c.tmpl, explanation)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.outputExplanation(explanation)
+	return 0
+}
+
+// outputExplanation outputs the ACL decision of a token along with the rules
+// and policies that produced it.
+func (c *ACLTokenExplainCommand) outputExplanation(explanation *api.ACLExplanation) {
+	decision := "denied"
+	if explanation.Allowed {
+		decision = "allowed"
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Accessor ID|%s", explanation.AccessorID),
+		fmt.Sprintf("Name|%s", explanation.Name),
+		fmt.Sprintf("Type|%s", explanation.Type),
+		fmt.Sprintf("Management|%t", explanation.Management),
+		fmt.Sprintf("Expired|%t", explanation.Expired),
+		fmt.Sprintf("Decision|%s", decision),
+	}))
+
+	if len(explanation.Rules) > 0 {
+		rules := []string{"Policy|Rule|Capabilities|Effect"}
+		for _, rule := range explanation.Rules {
+			effect := "none"
+			switch {
+			case rule.Denied:
+				effect = "deny"
+			case rule.Allowed:
+				effect = "allow"
+			}
+			rules = append(rules, fmt.Sprintf("%s|%s|%s|%s",
+				rule.Policy, rule.Rule, strings.Join(rule.Capabilities, ","), effect))
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]Rules[reset]"))
+		c.Ui.Output(formatList(rules))
+	}
+
+	if len(explanation.Policies) > 0 {
+		policies := []string{"Name|Token|Roles|Binding Rules|Missing"}
+		for _, policy := range explanation.Policies {
+			policies = append(policies, fmt.Sprintf("%s|%t|%s|%s|%t",
+				policy.Name, policy.Token, strings.Join(policy.Roles, ","),
+				strings.Join(policy.BindingRules, ","), policy.Missing))
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]Policies[reset]"))
+		c.Ui.Output(formatList(policies))
+	}
+}
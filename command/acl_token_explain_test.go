@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenExplainCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+
+	// Create a policy and a token linked to it.
+	policy := mock.ACLPolicy()
+	must.NoError(t, state.UpsertACLPolicies(structs.MsgTypeTestSetup, 1000, []*structs.ACLPolicy{policy}))
+
+	token := mock.ACLToken()
+	token.Policies = []string{policy.Name}
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1010, []*structs.ACLToken{token}))
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenExplainCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Test the basic validation on the command.
+	must.One(t, cmd.Run([]string{"-address=" + url, "namespace"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes two arguments")
+	ui.ErrorWriter.Reset()
+
+	// Explain an allowed operation of the token.
+	code := cmd.Run([]string{
+		"-address=" + url, "-token=" + token.SecretID, "-job=example", "namespace", "submit-job"})
+	must.Zero(t, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, token.AccessorID)
+	must.StrContains(t, out, "allowed")
+	must.StrContains(t, out, `namespace "default"`)
+	ui.OutputWriter.Reset()
+
+	// Explain a denied operation of another token using the root token.
+	code = cmd.Run([]string{
+		"-address=" + url, "-token=" + srv.RootToken.SecretID, "-accessor=" + token.AccessorID,
+		"operator", "write"})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), "denied")
+	ui.OutputWriter.Reset()
+
+	// Explaining another token requires a management token.
+	code = cmd.Run([]string{
+		"-address=" + url, "-token=" + token.SecretID, "-accessor=" + srv.RootToken.AccessorID,
+		"operator", "write"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Permission denied")
+	ui.ErrorWriter.Reset()
+
+	// Invalid operations are rejected.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "namespace", "bogus"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "invalid namespace capability")
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ACLTokenRenewCommand struct {
+	Meta
+}
+
+func (c *ACLTokenRenewCommand) Help() string {
+	helpText := `
+Usage: nomad acl token renew [options] [<token_accessor_id>]
+
+  Renew is used to renew a renewable ACL token, extending its expiration time
+  by its TTL, up to its max TTL since creation. If no accessor ID is given, the
+  currently set ACL token is renewed. Renewing another token requires a
+  management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Renew Options:
+
+  -json
+    Output the ACL token information in JSON format.
+
+  -t
+    Format and display the ACL token information using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLTokenRenewCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *ACLTokenRenewCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLTokenRenewCommand) Synopsis() string {
+	return "Renew a renewable ACL token"
+}
+
+func (c *ACLTokenRenewCommand) Name() string { return "acl token renew" }
+
+func (c *ACLTokenRenewCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at most one argument, the accessor ID of the token to
+	// renew.
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes at most one argument: <token_accessor_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var tokenAccessorID string
+	if len(args) == 1 {
+		tokenAccessorID = args[0]
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Renew the token
+	token, _, err := client.ACLTokens().Renew(tokenAccessorID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error renewing token: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Format the output
+	outputACLToken(c.Ui, token)
+	return 0
+}
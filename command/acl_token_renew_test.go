@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenRenewCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, client, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	rootToken := srv.RootToken
+	must.NotNil(t, rootToken)
+	client.SetSecretID(rootToken.SecretID)
+
+	// Create a renewable and a non-renewable token.
+	renewable, _, err := client.ACLTokens().Create(&api.ACLToken{
+		Type:          "management",
+		ExpirationTTL: 10 * time.Minute,
+		Renewable:     true,
+		MaxTTL:        time.Hour,
+	}, nil)
+	must.NoError(t, err)
+
+	nonRenewable, _, err := client.ACLTokens().Create(&api.ACLToken{
+		Type:          "management",
+		ExpirationTTL: 10 * time.Minute,
+	}, nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenRenewCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// A token can renew itself.
+	code := cmd.Run([]string{"-address=" + url, "-token=" + renewable.SecretID})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, renewable.AccessorID)
+	must.StrContains(t, out, "Renewable    = true")
+	must.StrContains(t, out, "Max TTL      = 1h0m0s")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Non-renewable tokens can't be renewed.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + rootToken.SecretID, nonRenewable.AccessorID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "token is not renewable")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// A management token can renew other tokens.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + rootToken.SecretID, "-json", renewable.AccessorID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), `"Renewable":true`)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -174,9 +175,17 @@ func (s *HTTPServer) ACLTokenSpecificRequest(resp http.ResponseWriter, req *http
 		return s.aclTokenUpdate(resp, req, "")
 	case "/v1/acl/token/self":
 		return s.aclTokenSelf(resp, req)
+	case "/v1/acl/token/self/renew":
+		return s.aclTokenRenew(resp, req, "")
 	}
 
 	accessor := strings.TrimPrefix(path, "/v1/acl/token/")
+	if accessor, ok := strings.CutSuffix(accessor, "/renew"); ok {
+		if accessor == "" {
+			return nil, CodedError(400, "Missing Token Accessor")
+		}
+		return s.aclTokenRenew(resp, req, accessor)
+	}
 	return s.aclTokenCrud(resp, req, accessor)
 }
 
@@ -272,6 +281,25 @@ func (s *HTTPServer) aclTokenUpdate(resp http.ResponseWriter, req *http.Request,
 	return nil, nil
 }
 
+func (s *HTTPServer) aclTokenRenew(resp http.ResponseWriter, req *http.Request,
+	tokenAccessor string) (interface{}, error) {
+	if !(req.Method == http.MethodPut || req.Method == http.MethodPost) {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ACLTokenRenewRequest{
+		AccessorID: tokenAccessor,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ACLTokenRenewResponse
+	if err := s.agent.RPC(structs.ACLRenewTokenRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out.Token, nil
+}
+
 func (s *HTTPServer) aclTokenDelete(resp http.ResponseWriter, req *http.Request,
 	tokenAccessor string) (interface{}, error) {
 
@@ -327,6 +355,41 @@ func (s *HTTPServer) ExchangeOneTimeToken(resp http.ResponseWriter, req *http.Re
 	return out, nil
 }
 
+// ACLExplainRequest explains the ACL decision of a token for an operation and
+// is callable via the /v1/acl/explain HTTP API.
+func (s *HTTPServer) ACLExplainRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	query := req.URL.Query()
+	args := structs.ACLExplainRequest{
+		AccessorID: query.Get("accessor_id"),
+		Operation: &acl.Operation{
+			Type:       query.Get("type"),
+			JobID:      query.Get("job"),
+			Path:       query.Get("path"),
+			Name:       query.Get("name"),
+			Capability: query.Get("capability"),
+		},
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+	args.Operation.Namespace = args.RequestNamespace()
+
+	var out structs.ACLExplainResponse
+	if err := s.agent.RPC(structs.ACLExplainRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Explanation == nil {
+		return nil, CodedError(http.StatusNotFound, "ACL token not found")
+	}
+	return out.Explanation, nil
+}
+
 // ACLRoleListRequest performs a listing of ACL roles and is callable via the
 // /v1/acl/roles HTTP API.
 func (s *HTTPServer) ACLRoleListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -855,6 +918,28 @@ func (s *HTTPServer) ACLOIDCAuthURLRequest(_ http.ResponseWriter, req *http.Requ
 	return out, nil
 }
 
+// ACLOIDCDeviceAuthRequest starts the OIDC device authorization workflow.
+func (s *HTTPServer) ACLOIDCDeviceAuthRequest(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports PUT or POST requests.
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var args structs.ACLOIDCDeviceAuthRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	var out structs.ACLOIDCDeviceAuthResponse
+	if err := s.agent.RPC(structs.ACLOIDCDeviceAuthRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ACLOIDCCompleteAuthRequest completes the OIDC login workflow.
 func (s *HTTPServer) ACLOIDCCompleteAuthRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
@@ -889,6 +974,16 @@ func (s *HTTPServer) ACLLoginRequest(resp http.ResponseWriter, req *http.Request
 	if err := decodeBody(req, &args); err != nil {
 		return nil, CodedError(http.StatusBadRequest, err.Error())
 	}
+
+	// Pass along the certificate chain presented over HTTPS, which is used by
+	// cert auth methods. It's only ever taken from the TLS connection.
+	args.ClientCertificates = nil
+	if req.TLS != nil {
+		for _, cert := range req.TLS.PeerCertificates {
+			args.ClientCertificates = append(args.ClientCertificates, cert.Raw)
+		}
+	}
+
 	var out structs.ACLLoginResponse
 	if err := s.agent.RPC(structs.ACLLoginRPCMethod, &args, &out); err != nil {
 		return nil, err
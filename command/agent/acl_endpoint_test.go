@@ -442,6 +442,72 @@ func TestHTTP_ACLTokenSelf(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLExplain(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+		policy := mock.ACLPolicy()
+		must.NoError(t, s.Agent.RPC(structs.ACLUpsertPoliciesRPCMethod, &structs.ACLPolicyUpsertRequest{
+			Policies: []*structs.ACLPolicy{policy},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}, &structs.GenericResponse{}))
+
+		token := mock.ACLToken()
+		token.AccessorID = ""
+		token.Policies = []string{policy.Name}
+		var upsertResp structs.ACLTokenUpsertResponse
+		must.NoError(t, s.Agent.RPC(structs.ACLUpsertTokensRPCMethod, &structs.ACLTokenUpsertRequest{
+			Tokens: []*structs.ACLToken{token},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}, &upsertResp))
+		token = upsertResp.Tokens[0]
+
+		// Explain an operation allowed by the policy.
+		req, err := http.NewRequest(http.MethodGet,
+			"/v1/acl/explain?type=namespace&namespace=default&capability=submit-job", nil)
+		must.NoError(t, err)
+		setToken(req, token)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.ACLExplainRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		explanation := obj.(*structs.ACLExplanation)
+		must.True(t, explanation.Allowed)
+		must.Eq(t, token.AccessorID, explanation.AccessorID)
+		must.Len(t, 1, explanation.Rules)
+		must.Eq(t, policy.Name, explanation.Rules[0].Policy)
+
+		// Explain an operation denied by the policy.
+		req, err = http.NewRequest(http.MethodGet,
+			"/v1/acl/explain?type=operator&capability=read", nil)
+		must.NoError(t, err)
+		setToken(req, token)
+		obj, err = s.Server.ACLExplainRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		must.False(t, obj.(*structs.ACLExplanation).Allowed)
+
+		// Explaining an unknown token returns not found.
+		req, err = http.NewRequest(http.MethodGet,
+			"/v1/acl/explain?type=agent&capability=read&accessor_id="+uuid.Generate(), nil)
+		must.NoError(t, err)
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLExplainRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "ACL token not found")
+
+		// Invalid methods are rejected.
+		req, err = http.NewRequest(http.MethodPut, "/v1/acl/explain", nil)
+		must.NoError(t, err)
+		_, err = s.Server.ACLExplainRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_ACLTokenCreate(t *testing.T) {
 	ci.Parallel(t)
 	httpACLTest(t, nil, func(s *TestAgent) {
@@ -562,6 +628,59 @@ func TestHTTP_ACLTokenDelete(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLTokenRenew(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+		p1 := mock.ACLToken()
+		p1.AccessorID = ""
+		p1.ExpirationTTL = time.Hour
+		p1.Renewable = true
+		args := structs.ACLTokenUpsertRequest{
+			Tokens: []*structs.ACLToken{p1},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var resp structs.ACLTokenUpsertResponse
+		must.NoError(t, s.Agent.RPC(structs.ACLUpsertTokensRPCMethod, &args, &resp))
+		token := resp.Tokens[0]
+
+		// Renew the token using its own secret.
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/token/self/renew", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, token)
+
+		obj, err := s.Server.ACLTokenSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"))
+
+		renewed := obj.(*structs.ACLToken)
+		must.Eq(t, token.AccessorID, renewed.AccessorID)
+		must.False(t, renewed.ExpirationTime.Before(*token.ExpirationTime))
+
+		// Renew the token by its accessor ID using the management token.
+		req, err = http.NewRequest(http.MethodPut, "/v1/acl/token/"+token.AccessorID+"/renew", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLTokenSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, token.AccessorID, obj.(*structs.ACLToken).AccessorID)
+
+		// Only write requests are allowed.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/token/self/renew", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, token)
+
+		_, err = s.Server.ACLTokenSpecificRequest(respW, req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_OneTimeToken(t *testing.T) {
 	ci.Parallel(t)
 	httpACLTest(t, nil, func(s *TestAgent) {
@@ -11,7 +11,9 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -360,6 +362,11 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 
 	conf.OIDCIssuer = agentConfig.Server.OIDCIssuer
 
+	if err := config.ValidateKEKProviders(agentConfig.Server.KEKProviders); err != nil {
+		return nil, fmt.Errorf("invalid server.keyring configuration: %w", err)
+	}
+	conf.KEKProviderConfigs = helper.CopySlice(agentConfig.Server.KEKProviders)
+
 	// Set up the bind addresses
 	rpcAddr, err := net.ResolveTCPAddr("tcp", agentConfig.normalizedAddrs.RPC)
 	if err != nil {
@@ -493,6 +500,13 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.RootKeyRotationThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.VariableVersionGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.VariableVersionGCThreshold = dur
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -602,6 +616,48 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftBoltNoFreelistSync = bolt.NoFreelistSync
 	}
 
+	// Set the raft encryption parameters
+	if raftEncryption := agentConfig.Server.RaftEncryption; raftEncryption != nil {
+		conf.RaftEncryptionEnabled = raftEncryption.Enabled != nil && *raftEncryption.Enabled
+
+		// The aead provider stores the key encryption key in the clear in the
+		// data directory, next to the raft data it would protect.
+		if conf.RaftEncryptionEnabled {
+			active := config.ActiveKEKProvider(conf.KEKProviderConfigs)
+			if active == nil || active.Provider == config.KEKProviderAEAD {
+				return nil, fmt.Errorf("raft_encryption requires an active keyring provider other than %q", config.KEKProviderAEAD)
+			}
+		}
+		if threshold := raftEncryption.RotationThreshold; threshold != "" {
+			dur, err := time.ParseDuration(threshold)
+			if err != nil {
+				return nil, fmt.Errorf("invalid raft_encryption rotation_threshold: %w", err)
+			}
+			if dur < time.Minute {
+				return nil, fmt.Errorf("raft_encryption rotation_threshold must be at least 1m")
+			}
+			conf.RaftEncryptionRotationThreshold = dur
+		}
+	}
+
+	// Set the event sink destinations allowed to run on the server
+	if eventSinks := agentConfig.Server.EventSinks; eventSinks != nil {
+		for _, command := range eventSinks.AllowedCommands {
+			if !filepath.IsAbs(command) {
+				return nil, fmt.Errorf("event_sinks allowed_commands must be absolute paths, got %q", command)
+			}
+		}
+		for _, dir := range eventSinks.AllowedDirectories {
+			if !filepath.IsAbs(dir) {
+				return nil, fmt.Errorf("event_sinks allowed_directories must be absolute paths, got %q", dir)
+			}
+		}
+		conf.EventSinkAllowlist = &structs.EventSinkAllowlist{
+			Commands:    slices.Clone(eventSinks.AllowedCommands),
+			Directories: slices.Clone(eventSinks.AllowedDirectories),
+		}
+	}
+
 	// Interpret job_max_source_size as bytes from string value
 	if agentConfig.Server.JobMaxSourceSize == nil {
 		agentConfig.Server.JobMaxSourceSize = pointer.Of("1M")
@@ -1355,6 +1411,13 @@ func (a *Agent) ShouldReload(newConfig *Config) (agent, http bool) {
 		agent = true
 	}
 
+	// Reload the auditor if its configuration changed, and reopen the audit
+	// log files on every reload so they can be rotated by external tools.
+	if newConfig.Audit != nil && (!reflect.DeepEqual(a.config.Audit, newConfig.Audit) ||
+		(a.auditor != nil && a.auditor.Enabled())) {
+		agent = true
+	}
+
 	return agent, http
 }
 
@@ -1382,6 +1445,7 @@ func (a *Agent) Reload(newConfig *Config) error {
 		if err := a.entReloadEventer(newConfig.Audit); err != nil {
 			return err
 		}
+		current.Audit = newConfig.Audit.Copy()
 	}
 	// Allow auditor to call reopen regardless of config changes
 	// This is primarily for enterprise audit logging to allow the underlying
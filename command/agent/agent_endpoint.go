@@ -104,6 +104,13 @@ func (s *HTTPServer) AgentSelfRequest(resp http.ResponseWriter, req *http.Reques
 		self.Config.Telemetry.CirconusAPIToken = "<redacted>"
 	}
 
+	if self.Config != nil && self.Config.Client != nil && self.Config.Client.Artifact != nil {
+		for registry, auth := range self.Config.Client.Artifact.OCIAuth {
+			username, _, _ := strings.Cut(auth, ":")
+			self.Config.Client.Artifact.OCIAuth[registry] = username + ":<redacted>"
+		}
+	}
+
 	return self, nil
 }
 
@@ -96,6 +96,15 @@ func TestHTTP_AgentSelf(t *testing.T) {
 		require.NoError(err)
 		self = obj.(agentSelf)
 		require.Equal("<redacted>", self.Config.Telemetry.CirconusAPIToken)
+
+		// Assign OCI registry credentials and require the password is redacted.
+		s.Config.Client.Artifact.OCIAuth = map[string]string{"registry.example.com": "user:badc0deb"}
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.AgentSelfRequest(respW, req)
+		require.NoError(err)
+		self = obj.(agentSelf)
+		require.Equal("user:<redacted>", self.Config.Client.Artifact.OCIAuth["registry.example.com"])
+		require.Equal("user:badc0deb", s.Config.Client.Artifact.OCIAuth["registry.example.com"])
 	})
 }
 
@@ -603,6 +603,80 @@ func TestAgent_ServerConfig_RaftTrailingLogs(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_RaftEncryption(t *testing.T) {
+	ci.Parallel(t)
+
+	transitProvider := &config.KEKProviderConfig{
+		Provider: "transit",
+		Config:   map[string]string{"key_name": "nomad-raft"},
+	}
+
+	cases := []struct {
+		name            string
+		value           *RaftEncryptionConfig
+		kekProviders    []*config.KEKProviderConfig
+		expectEnabled   bool
+		expectThreshold time.Duration
+		expectErr       string
+	}{
+		{
+			name:            "empty",
+			expectThreshold: 720 * time.Hour,
+		},
+		{
+			name:            "enabled",
+			value:           &RaftEncryptionConfig{Enabled: pointer.Of(true), RotationThreshold: "24h"},
+			kekProviders:    []*config.KEKProviderConfig{transitProvider},
+			expectEnabled:   true,
+			expectThreshold: 24 * time.Hour,
+		},
+		{
+			name:      "enabled with default keyring provider",
+			value:     &RaftEncryptionConfig{Enabled: pointer.Of(true)},
+			expectErr: `raft_encryption requires an active keyring provider other than "aead"`,
+		},
+		{
+			name:  "enabled with aead keyring provider active",
+			value: &RaftEncryptionConfig{Enabled: pointer.Of(true)},
+			kekProviders: []*config.KEKProviderConfig{
+				{Provider: config.KEKProviderAEAD, Active: true},
+				{Provider: "transit", Config: transitProvider.Config},
+			},
+			expectErr: `raft_encryption requires an active keyring provider other than "aead"`,
+		},
+		{
+			name:            "disabled with default keyring provider",
+			value:           &RaftEncryptionConfig{Enabled: pointer.Of(false)},
+			expectThreshold: 720 * time.Hour,
+		},
+		{
+			name:         "bad threshold",
+			value:        &RaftEncryptionConfig{Enabled: pointer.Of(true), RotationThreshold: "1s"},
+			kekProviders: []*config.KEKProviderConfig{transitProvider},
+			expectErr:    "raft_encryption rotation_threshold must be at least 1m",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ci.Parallel(t)
+			conf := DevConfig(nil)
+			must.NoError(t, conf.normalizeAddrs())
+
+			conf.Server.RaftEncryption = tc.value
+			conf.Server.KEKProviders = tc.kekProviders
+			nc, err := convertServerConfig(conf)
+			if tc.expectErr != "" {
+				must.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expectEnabled, nc.RaftEncryptionEnabled)
+			must.Eq(t, tc.expectThreshold, nc.RaftEncryptionRotationThreshold)
+		})
+	}
+}
+
 func TestAgent_ServerConfig_RaftSnapshotThreshold(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,316 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/ryanuber/go-glob"
+)
+
+const (
+	// auditDeliveryEnforced sinks fail the request if the audit event can't
+	// be written.
+	auditDeliveryEnforced = "enforced"
+
+	// auditDeliveryBestEffort sinks log a warning if the audit event can't be
+	// written.
+	auditDeliveryBestEffort = "best-effort"
+
+	// defaultAuditRotateDuration is the rotation period of audit log files if
+	// the sink doesn't set one.
+	defaultAuditRotateDuration = 24 * time.Hour
+
+	// defaultAuditFileMode is the permission mode of audit log files if the
+	// sink doesn't set one.
+	defaultAuditFileMode = 0o600
+)
+
+// auditor is the event.Auditor that writes audit events as JSON lines to
+// file sinks. Its configuration can be replaced at runtime when the agent
+// configuration is reloaded.
+type auditor struct {
+	logger  hclog.Logger
+	dataDir string
+
+	lock    sync.RWMutex
+	enabled bool
+	sinks   []*auditSink
+	filters []*config.AuditFilter
+}
+
+// auditSink is a file the audit log is written to.
+type auditSink struct {
+	name     string
+	enforced bool
+	file     *logFile
+}
+
+// Ensure auditor is an Auditor
+var _ event.Auditor = &auditor{}
+
+// newAuditor returns an auditor for the given configuration. The default
+// sink writes to the audit directory within dataDir.
+func newAuditor(cfg *config.AuditConfig, dataDir string, logger hclog.Logger) (*auditor, error) {
+	a := &auditor{
+		logger:  logger.Named("audit"),
+		dataDir: dataDir,
+	}
+	if err := a.reload(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload replaces the configuration of the auditor. The previous
+// configuration is kept if the new one is invalid.
+func (a *auditor) reload(cfg *config.AuditConfig) error {
+	if cfg == nil {
+		cfg = &config.AuditConfig{}
+	}
+
+	enabled := cfg.Enabled != nil && *cfg.Enabled
+
+	var sinks []*auditSink
+	if enabled {
+		sinkCfgs := cfg.Sinks
+		if len(sinkCfgs) == 0 {
+			if a.dataDir == "" {
+				return errors.New("audit logging requires a sink or the agent data_dir to be set")
+			}
+			sinkCfgs = []*config.AuditSink{{
+				Name: "audit",
+				Path: filepath.Join(a.dataDir, "audit", "audit.log"),
+			}}
+		}
+
+		var mErr *multierror.Error
+		for _, sinkCfg := range sinkCfgs {
+			sink, err := newAuditSink(sinkCfg)
+			if err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("invalid audit sink %q: %w", sinkCfg.Name, err))
+				continue
+			}
+			sinks = append(sinks, sink)
+		}
+		for _, filter := range cfg.Filters {
+			if err := validateAuditFilter(filter); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("invalid audit filter %q: %w", filter.Name, err))
+			}
+		}
+		if err := mErr.ErrorOrNil(); err != nil {
+			return err
+		}
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, sink := range a.sinks {
+		if err := sink.file.Close(); err != nil {
+			a.logger.Warn("failed to close audit log", "sink", sink.name, "error", err)
+		}
+	}
+
+	a.enabled = enabled
+	a.sinks = sinks
+	a.filters = make([]*config.AuditFilter, 0, len(cfg.Filters))
+	for _, filter := range cfg.Filters {
+		a.filters = append(a.filters, filter.Copy())
+	}
+	return nil
+}
+
+func newAuditSink(cfg *config.AuditSink) (*auditSink, error) {
+	if cfg.Type != "" && cfg.Type != "file" {
+		return nil, fmt.Errorf("unsupported type %q", cfg.Type)
+	}
+	if cfg.Format != "" && cfg.Format != "json" {
+		return nil, fmt.Errorf("unsupported format %q", cfg.Format)
+	}
+	if cfg.Path == "" {
+		return nil, errors.New("path must be set")
+	}
+
+	sink := &auditSink{name: cfg.Name}
+	switch cfg.DeliveryGuarantee {
+	case "", auditDeliveryEnforced:
+		sink.enforced = true
+	case auditDeliveryBestEffort:
+	default:
+		return nil, fmt.Errorf("invalid delivery_guarantee %q, must be %q or %q",
+			cfg.DeliveryGuarantee, auditDeliveryEnforced, auditDeliveryBestEffort)
+	}
+
+	mode := os.FileMode(defaultAuditFileMode)
+	if cfg.Mode != "" {
+		m, err := strconv.ParseUint(cfg.Mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mode %q: %w", cfg.Mode, err)
+		}
+		mode = os.FileMode(m)
+	}
+
+	duration := cfg.RotateDuration
+	if duration == 0 {
+		duration = defaultAuditRotateDuration
+	}
+
+	dir, fileName := filepath.Split(cfg.Path)
+	if fileName == "" {
+		return nil, fmt.Errorf("path %q must be a file", cfg.Path)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	sink.file = &logFile{
+		fileName: fileName,
+		logPath:  dir,
+		duration: duration,
+		MaxBytes: cfg.RotateBytes,
+		MaxFiles: cfg.RotateMaxFiles,
+		mode:     mode,
+	}
+	return sink, nil
+}
+
+func validateAuditFilter(filter *config.AuditFilter) error {
+	if filter.Type != "" && filter.Type != event.HTTPEvent {
+		return fmt.Errorf("unsupported type %q", filter.Type)
+	}
+	for _, stage := range filter.Stages {
+		if !glob.Glob(stage, string(event.OperationReceived)) &&
+			!glob.Glob(stage, string(event.OperationComplete)) {
+			return fmt.Errorf("invalid stage %q", stage)
+		}
+	}
+	return nil
+}
+
+// Event writes the audit event to every sink unless it is excluded by a
+// filter. An error is returned if the event couldn't be written to a sink
+// that enforces delivery.
+func (a *auditor) Event(_ context.Context, eventType string, payload interface{}) error {
+	ev, ok := payload.(*event.Event)
+	if !ok {
+		return fmt.Errorf("unsupported audit event payload %T", payload)
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if !a.enabled || a.filtered(ev) {
+		return nil
+	}
+
+	line, err := json.Marshal(&event.Entry{
+		CreatedAt: time.Now().UTC(),
+		EventType: eventType,
+		Payload:   ev,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	line = append(line, '\n')
+
+	var mErr *multierror.Error
+	for _, sink := range a.sinks {
+		if _, err := sink.file.Write(line); err != nil {
+			if sink.enforced {
+				mErr = multierror.Append(mErr, fmt.Errorf("failed to write audit event to sink %q: %w", sink.name, err))
+				continue
+			}
+			a.logger.Warn("failed to write audit event", "sink", sink.name, "error", err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// filtered returns true if the event matches any of the filters. A filter
+// matches if the event matches one of the glob patterns of each of its
+// fields, and an empty field matches every event. Query parameters are
+// ignored when matching endpoints.
+func (a *auditor) filtered(ev *event.Event) bool {
+	endpoint, _, _ := strings.Cut(ev.Request.Endpoint, "?")
+	for _, filter := range a.filters {
+		if matchAuditFilter(filter.Endpoints, endpoint) &&
+			matchAuditFilter(filter.Stages, string(ev.Stage)) &&
+			matchAuditFilter(filter.Operations, strings.ToUpper(ev.Request.Operation)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAuditFilter(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, value) || strings.EqualFold(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns whether audit events are written.
+func (a *auditor) Enabled() bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.enabled
+}
+
+// Reopen closes the audit log files so they are opened again on the next
+// write, which allows them to be moved by external log rotation.
+func (a *auditor) Reopen() error {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	var mErr *multierror.Error
+	for _, sink := range a.sinks {
+		if err := sink.file.Close(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("failed to close audit log %q: %w", sink.name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SetEnabled enables or disables writing audit events.
+func (a *auditor) SetEnabled(enabled bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.enabled = enabled && len(a.sinks) > 0
+}
+
+// DeliveryEnforced returns true if any sink enforces delivery, in which case
+// requests fail if their audit events can't be written.
+func (a *auditor) DeliveryEnforced() bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	for _, sink := range a.sinks {
+		if sink.enforced {
+			return true
+		}
+	}
+	return false
+}
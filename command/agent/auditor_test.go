@@ -0,0 +1,314 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+// readAuditLog returns the entries of the audit log at path.
+func readAuditLog(t *testing.T, path string) []*event.Entry {
+	t.Helper()
+
+	f, err := os.Open(path)
+	must.NoError(t, err)
+	defer f.Close()
+
+	var entries []*event.Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry event.Entry
+		must.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, &entry)
+	}
+	must.NoError(t, scanner.Err())
+	return entries
+}
+
+func testAuditEvent(stage event.Stage, method, endpoint string) *event.Event {
+	return &event.Event{
+		ID:      "id",
+		Stage:   stage,
+		Type:    event.AuditEventType,
+		Version: event.AuditEventVersion,
+		Auth:    &event.Auth{AccessorID: "accessor"},
+		Request: &event.Request{
+			ID:        "request",
+			Operation: method,
+			Endpoint:  endpoint,
+		},
+	}
+}
+
+func TestAuditor_Event(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	a, err := newAuditor(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name: "file",
+			Path: path,
+		}},
+		Filters: []*config.AuditFilter{
+			{
+				Name:      "metrics",
+				Type:      event.HTTPEvent,
+				Endpoints: []string{"/v1/metrics"},
+			},
+			{
+				Name:       "reads",
+				Endpoints:  []string{"/v1/job/*"},
+				Stages:     []string{string(event.OperationComplete)},
+				Operations: []string{"get"},
+			},
+		},
+	}, "", hclog.NewNullLogger())
+	must.NoError(t, err)
+	must.True(t, a.Enabled())
+	must.True(t, a.DeliveryEnforced())
+
+	ctx := context.Background()
+	events := []*event.Event{
+		testAuditEvent(event.OperationReceived, http.MethodGet, "/v1/metrics?format=prometheus"),
+		testAuditEvent(event.OperationReceived, http.MethodGet, "/v1/job/example"),
+		testAuditEvent(event.OperationComplete, http.MethodGet, "/v1/job/example"),
+		testAuditEvent(event.OperationComplete, http.MethodPut, "/v1/job/example"),
+	}
+	for _, ev := range events {
+		must.NoError(t, a.Event(ctx, event.AuditEventType, ev))
+	}
+
+	entries := readAuditLog(t, path)
+	must.Len(t, 2, entries)
+	must.Eq(t, event.AuditEventType, entries[0].EventType)
+	must.Eq(t, event.OperationReceived, entries[0].Payload.Stage)
+	must.Eq(t, http.MethodGet, entries[0].Payload.Request.Operation)
+	must.Eq(t, event.OperationComplete, entries[1].Payload.Stage)
+	must.Eq(t, http.MethodPut, entries[1].Payload.Request.Operation)
+	must.Eq(t, "accessor", entries[1].Payload.Auth.AccessorID)
+
+	info, err := os.Stat(path)
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(defaultAuditFileMode), info.Mode().Perm())
+
+	// Disabled auditors don't write events.
+	a.SetEnabled(false)
+	must.NoError(t, a.Event(ctx, event.AuditEventType, events[3]))
+	must.Len(t, 2, readAuditLog(t, path))
+}
+
+func TestAuditor_DefaultSink(t *testing.T) {
+	ci.Parallel(t)
+
+	dataDir := t.TempDir()
+	a, err := newAuditor(&config.AuditConfig{Enabled: pointer.Of(true)}, dataDir, hclog.NewNullLogger())
+	must.NoError(t, err)
+	must.True(t, a.DeliveryEnforced())
+
+	ev := testAuditEvent(event.OperationReceived, http.MethodGet, "/v1/jobs")
+	must.NoError(t, a.Event(context.Background(), event.AuditEventType, ev))
+	must.Len(t, 1, readAuditLog(t, filepath.Join(dataDir, "audit", "audit.log")))
+}
+
+func TestAuditor_DeliveryGuarantee(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	newTestAuditor := func(guarantee string) *auditor {
+		a, err := newAuditor(&config.AuditConfig{
+			Enabled: pointer.Of(true),
+			Sinks: []*config.AuditSink{{
+				Name:              guarantee,
+				DeliveryGuarantee: guarantee,
+				Path:              filepath.Join(dir, guarantee, "audit.log"),
+			}},
+		}, "", hclog.NewNullLogger())
+		must.NoError(t, err)
+
+		// Replace the log directory with a file so writes fail.
+		must.NoError(t, os.RemoveAll(filepath.Join(dir, guarantee)))
+		must.NoError(t, os.WriteFile(filepath.Join(dir, guarantee), nil, 0o600))
+		return a
+	}
+
+	ev := testAuditEvent(event.OperationReceived, http.MethodGet, "/v1/jobs")
+
+	enforced := newTestAuditor(auditDeliveryEnforced)
+	must.True(t, enforced.DeliveryEnforced())
+	must.ErrorContains(t, enforced.Event(context.Background(), event.AuditEventType, ev),
+		`failed to write audit event to sink "enforced"`)
+
+	bestEffort := newTestAuditor(auditDeliveryBestEffort)
+	must.False(t, bestEffort.DeliveryEnforced())
+	must.NoError(t, bestEffort.Event(context.Background(), event.AuditEventType, ev))
+}
+
+func TestAuditor_Reload(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	a, err := newAuditor(&config.AuditConfig{}, "", hclog.NewNullLogger())
+	must.NoError(t, err)
+	must.False(t, a.Enabled())
+
+	// Invalid configurations are rejected and the auditor stays disabled.
+	err = a.reload(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name:              "file",
+			Path:              filepath.Join(dir, "audit.log"),
+			DeliveryGuarantee: "sometimes",
+		}},
+	})
+	must.ErrorContains(t, err, "invalid delivery_guarantee")
+	must.False(t, a.Enabled())
+
+	err = a.reload(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Filters: []*config.AuditFilter{{Name: "bad", Stages: []string{"Done"}}},
+		Sinks: []*config.AuditSink{{
+			Name: "file",
+			Path: filepath.Join(dir, "audit.log"),
+		}},
+	})
+	must.ErrorContains(t, err, `invalid stage "Done"`)
+
+	must.NoError(t, a.reload(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name: "file",
+			Path: filepath.Join(dir, "audit.log"),
+			Mode: "0640",
+		}},
+	}))
+	must.True(t, a.Enabled())
+
+	ev := testAuditEvent(event.OperationReceived, http.MethodGet, "/v1/jobs")
+	must.NoError(t, a.Event(context.Background(), event.AuditEventType, ev))
+
+	// Reopening allows the file to be moved away by external tools.
+	must.NoError(t, os.Rename(filepath.Join(dir, "audit.log"), filepath.Join(dir, "audit.log.1")))
+	must.NoError(t, a.Reopen())
+	must.NoError(t, a.Event(context.Background(), event.AuditEventType, ev))
+	must.Len(t, 1, readAuditLog(t, filepath.Join(dir, "audit.log")))
+
+	info, err := os.Stat(filepath.Join(dir, "audit.log"))
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(0o640), info.Mode().Perm())
+
+	must.NoError(t, a.reload(&config.AuditConfig{Enabled: pointer.Of(false)}))
+	must.False(t, a.Enabled())
+}
+
+func TestHTTP_AuditLog(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	httpACLTest(t, func(c *Config) {
+		c.Audit = &config.AuditConfig{
+			Enabled: pointer.Of(true),
+			Sinks: []*config.AuditSink{{
+				Name: "file",
+				Path: path,
+			}},
+		}
+	}, func(s *TestAgent) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/jobs?namespace=prod", nil)
+		must.NoError(t, err)
+		setToken(req, s.RootToken)
+		respW := httptest.NewRecorder()
+		s.Server.wrap(s.Server.JobsRequest)(respW, req)
+		must.Eq(t, http.StatusOK, respW.Code)
+
+		// Failed requests are recorded with their status.
+		req, err = http.NewRequest(http.MethodGet, "/v1/jobs", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		s.Server.wrap(s.Server.JobsRequest)(respW, req)
+		must.Eq(t, http.StatusForbidden, respW.Code)
+
+		raw, err := os.ReadFile(path)
+		must.NoError(t, err)
+		must.StrNotContains(t, string(raw), s.RootToken.SecretID)
+
+		entries := readAuditLog(t, path)
+		must.Len(t, 4, entries)
+
+		received, complete := entries[0].Payload, entries[1].Payload
+		must.Eq(t, event.OperationReceived, received.Stage)
+		must.Eq(t, s.RootToken.AccessorID, received.Auth.AccessorID)
+		must.Eq(t, "/v1/jobs?namespace=prod", received.Request.Endpoint)
+		must.Eq(t, "prod", received.Request.Namespace.ID)
+		must.Nil(t, received.Response)
+
+		must.Eq(t, event.OperationComplete, complete.Stage)
+		must.Eq(t, received.ID, complete.ID)
+		must.Eq(t, received.Request.ID, complete.Request.ID)
+		must.Eq(t, http.StatusOK, complete.Response.StatusCode)
+
+		anonymous := entries[3].Payload
+		must.Eq(t, "anonymous", anonymous.Auth.AccessorID)
+		must.Eq(t, http.StatusForbidden, anonymous.Response.StatusCode)
+		must.True(t, strings.Contains(anonymous.Response.Error, "Permission denied"))
+
+		// Requests fail if their audit events can't be written to an
+		// enforced sink.
+		must.NoError(t, os.Remove(path))
+		must.NoError(t, os.Mkdir(path, 0o700))
+		must.NoError(t, s.Agent.auditor.Reopen())
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/jobs", nil)
+		must.NoError(t, err)
+		setToken(req, s.RootToken)
+		respW = httptest.NewRecorder()
+		s.Server.wrap(s.Server.JobsRequest)(respW, req)
+		must.Eq(t, http.StatusInternalServerError, respW.Code)
+		must.StrContains(t, respW.Body.String(), "failed to write audit event")
+	})
+}
+
+func TestAgent_Reload_Audit(t *testing.T) {
+	ci.Parallel(t)
+
+	agent := NewTestAgent(t, t.Name(), nil)
+	defer agent.Shutdown()
+	must.False(t, agent.auditor.Enabled())
+
+	newConfig := agent.GetConfig().Copy()
+	newConfig.Audit = &config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name: "file",
+			Path: filepath.Join(t.TempDir(), "audit.log"),
+		}},
+	}
+
+	shouldReloadAgent, _ := agent.ShouldReload(newConfig)
+	must.True(t, shouldReloadAgent)
+	must.NoError(t, agent.Reload(newConfig))
+	must.True(t, agent.auditor.Enabled())
+	must.Eq(t, newConfig.Audit, agent.GetConfig().Audit)
+
+	// Invalid configurations fail the reload and keep the auditor running.
+	badConfig := newConfig.Copy()
+	badConfig.Audit.Sinks[0].Type = "syslog"
+	must.ErrorContains(t, agent.Reload(badConfig), `unsupported type "syslog"`)
+	must.True(t, agent.auditor.Enabled())
+}
@@ -568,6 +568,10 @@ type ServerConfig struct {
 	// collection interval.
 	RootKeyRotationThreshold string `hcl:"root_key_rotation_threshold"`
 
+	// VariableVersionGCThreshold is how long a variable must have been
+	// deleted for its previous versions to be eligible for GC.
+	VariableVersionGCThreshold string `hcl:"variable_version_gc_threshold"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -677,6 +681,10 @@ type ServerConfig struct {
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
 
+	// RaftEncryption configures encryption of the raft logs and snapshots at
+	// rest.
+	RaftEncryption *RaftEncryptionConfig `hcl:"raft_encryption"`
+
 	// RaftSnapshotThreshold controls how many outstanding logs there must be
 	// before we perform a snapshot. This is to prevent excessive snapshotting by
 	// replaying a small set of logs instead. The value passed here is the initial
@@ -714,6 +722,14 @@ type ServerConfig struct {
 	// issuer. Third parties such as AWS IAM OIDC Provider expect the issuer to
 	// be a publically accessible HTTPS URL signed by a trusted well-known CA.
 	OIDCIssuer string `hcl:"oidc_issuer"`
+
+	// KEKProviders configure the providers that wrap the key encryption keys
+	// protecting the root keys in the keystore.
+	KEKProviders []*config.KEKProviderConfig `hcl:"keyring"`
+
+	// EventSinks restricts the destinations of the file and exec event sinks
+	// delivered by the server when it is the leader.
+	EventSinks *EventSinksConfig `hcl:"event_sinks"`
 }
 
 func (s *ServerConfig) Copy() *ServerConfig {
@@ -737,12 +753,15 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
 	ns.Search = s.Search.Copy()
 	ns.RaftBoltConfig = s.RaftBoltConfig.Copy()
+	ns.RaftEncryption = s.RaftEncryption.Copy()
 	ns.RaftSnapshotInterval = pointer.Copy(s.RaftSnapshotInterval)
 	ns.RaftSnapshotThreshold = pointer.Copy(s.RaftSnapshotThreshold)
 	ns.RaftTrailingLogs = pointer.Copy(s.RaftTrailingLogs)
 	ns.JobDefaultPriority = pointer.Copy(s.JobDefaultPriority)
 	ns.JobMaxPriority = pointer.Copy(s.JobMaxPriority)
 	ns.JobTrackedVersions = pointer.Copy(s.JobTrackedVersions)
+	ns.KEKProviders = helper.CopySlice(s.KEKProviders)
+	ns.EventSinks = s.EventSinks.Copy()
 	return &ns
 }
 
@@ -766,6 +785,88 @@ func (r *RaftBoltConfig) Copy() *RaftBoltConfig {
 	return &nr
 }
 
+// RaftEncryptionConfig is used in servers to configure encryption of the raft
+// logs and snapshots at rest.
+type RaftEncryptionConfig struct {
+	// Enabled toggles whether new raft data is encrypted. Data encrypted
+	// while it was enabled can still be read after it's disabled.
+	//
+	// Default: false.
+	Enabled *bool `hcl:"enabled"`
+
+	// RotationThreshold is how "old" the key encrypting raft data can be
+	// before it's rotated.
+	//
+	// Default: 720h.
+	RotationThreshold string `hcl:"rotation_threshold"`
+}
+
+func (r *RaftEncryptionConfig) Copy() *RaftEncryptionConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	nr.Enabled = pointer.Copy(r.Enabled)
+	return &nr
+}
+
+func (r *RaftEncryptionConfig) Merge(b *RaftEncryptionConfig) *RaftEncryptionConfig {
+	if r == nil {
+		return b.Copy()
+	}
+
+	result := r.Copy()
+	if b == nil {
+		return result
+	}
+	if b.Enabled != nil {
+		result.Enabled = pointer.Copy(b.Enabled)
+	}
+	if b.RotationThreshold != "" {
+		result.RotationThreshold = b.RotationThreshold
+	}
+	return result
+}
+
+// EventSinksConfig is used in servers to restrict the destinations of the file
+// and exec event sinks. These sinks write files and run commands as the server
+// process, so they are refused unless their destination is allowed here.
+type EventSinksConfig struct {
+	// AllowedCommands are the absolute paths of the commands exec sinks may
+	// run.
+	AllowedCommands []string `hcl:"allowed_commands"`
+
+	// AllowedDirectories are the absolute paths of the directories file
+	// sinks may write to.
+	AllowedDirectories []string `hcl:"allowed_directories"`
+}
+
+func (e *EventSinksConfig) Copy() *EventSinksConfig {
+	if e == nil {
+		return nil
+	}
+
+	return &EventSinksConfig{
+		AllowedCommands:    slices.Clone(e.AllowedCommands),
+		AllowedDirectories: slices.Clone(e.AllowedDirectories),
+	}
+}
+
+func (e *EventSinksConfig) Merge(b *EventSinksConfig) *EventSinksConfig {
+	if e == nil {
+		return b.Copy()
+	}
+
+	result := e.Copy()
+	if b == nil {
+		return result
+	}
+	result.AllowedCommands = append(result.AllowedCommands, b.AllowedCommands...)
+	result.AllowedDirectories = append(result.AllowedDirectories, b.AllowedDirectories...)
+	return result
+}
+
 // PlanRejectionTracker is used in servers to configure the plan rejection
 // tracker.
 type PlanRejectionTracker struct {
@@ -2110,6 +2211,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.RootKeyRotationThreshold != "" {
 		result.RootKeyRotationThreshold = b.RootKeyRotationThreshold
 	}
+	if b.VariableVersionGCThreshold != "" {
+		result.VariableVersionGCThreshold = b.VariableVersionGCThreshold
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -2204,6 +2308,10 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	if b.RaftEncryption != nil {
+		result.RaftEncryption = result.RaftEncryption.Merge(b.RaftEncryption)
+	}
+
 	if b.RaftSnapshotThreshold != nil {
 		result.RaftSnapshotThreshold = pointer.Of(*b.RaftSnapshotThreshold)
 	}
@@ -2224,6 +2332,14 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.OIDCIssuer = b.OIDCIssuer
 	}
 
+	if len(b.KEKProviders) != 0 {
+		result.KEKProviders = config.KEKProvidersMerge(s.KEKProviders, b.KEKProviders)
+	}
+
+	if b.EventSinks != nil {
+		result.EventSinks = result.EventSinks.Merge(b.EventSinks)
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
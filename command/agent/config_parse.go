@@ -325,6 +325,12 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k)
 	}
 
+	for _, p := range c.Server.KEKProviders {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, p.Provider)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "config")
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "keyring")
+	}
+
 	for _, k := range []string{"datadog_tags"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "telemetry")
@@ -1029,6 +1029,36 @@ func TestConfig_MultipleVault(t *testing.T) {
 	}
 }
 
+func TestConfig_Keyring(t *testing.T) {
+
+	for _, suffix := range []string{"hcl", "json"} {
+		t.Run(suffix, func(t *testing.T) {
+			fc, err := LoadConfig("testdata/keyring." + suffix)
+			must.NoError(t, err)
+
+			cfg := DefaultConfig().Merge(fc)
+			must.Eq(t, []*config.KEKProviderConfig{
+				{
+					Provider: "aead",
+				},
+				{
+					Provider: "transit",
+					Active:   true,
+					Config: map[string]string{
+						"address":  "https://vault.example.com:8200",
+						"key_name": "nomad-keyring",
+					},
+				},
+			}, cfg.Server.KEKProviders)
+			must.NoError(t, config.ValidateKEKProviders(cfg.Server.KEKProviders))
+			must.Eq(t, &RaftEncryptionConfig{
+				Enabled:           pointer.Of(true),
+				RotationThreshold: "168h",
+			}, cfg.Server.RaftEncryption)
+		})
+	}
+}
+
 func TestConfig_MultipleConsul(t *testing.T) {
 
 	for _, suffix := range []string{"hcl", "json"} {
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package event
+
+import (
+	"time"
+)
+
+const (
+	// AuditEventType is the event type of audit log entries.
+	AuditEventType = "audit"
+
+	// HTTPEvent is the filter type matching audit events of HTTP requests.
+	HTTPEvent = "HTTPEvent"
+
+	// AuditEventVersion is the version of the audit event format.
+	AuditEventVersion = 1
+)
+
+// Stage is the point in the lifecycle of a request an audit event is
+// emitted at.
+type Stage string
+
+const (
+	// OperationReceived is emitted before the request is handled.
+	OperationReceived Stage = "OperationReceived"
+
+	// OperationComplete is emitted after the request was handled and
+	// includes the response status.
+	OperationComplete Stage = "OperationComplete"
+)
+
+// Entry is a single line of an audit log.
+type Entry struct {
+	CreatedAt time.Time `json:"created_at"`
+	EventType string    `json:"event_type"`
+	Payload   *Event    `json:"payload"`
+}
+
+// Event is the audit record of a single stage of an HTTP request. Both
+// stages of a request share the same ID.
+type Event struct {
+	ID        string    `json:"id"`
+	Stage     Stage     `json:"stage"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   int       `json:"version"`
+	Auth      *Auth     `json:"auth"`
+	Request   *Request  `json:"request"`
+	Response  *Response `json:"response,omitempty"`
+}
+
+// Auth describes the identity that made the request. It never includes the
+// secret used to authenticate.
+type Auth struct {
+	// AccessorID, Name, Type, Policies, Roles and Global describe the ACL
+	// token used for the request.
+	AccessorID string   `json:"accessor_id,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	Policies   []string `json:"policies,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	Global     bool     `json:"global,omitempty"`
+
+	// CreateTime is the time the ACL token was created.
+	CreateTime time.Time `json:"create_time"`
+
+	// Workload is set if the request was authenticated with a workload
+	// identity.
+	Workload *WorkloadIdentity `json:"workload,omitempty"`
+
+	// ClientID is set if the request was authenticated with a node secret.
+	ClientID string `json:"client_id,omitempty"`
+
+	// Error is the reason the identity could not be resolved, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// WorkloadIdentity describes the workload a workload identity was issued to.
+type WorkloadIdentity struct {
+	Namespace    string `json:"namespace"`
+	JobID        string `json:"job_id"`
+	AllocationID string `json:"allocation_id"`
+	TaskName     string `json:"task,omitempty"`
+	ServiceName  string `json:"service,omitempty"`
+}
+
+// Request describes the HTTP request.
+type Request struct {
+	ID          string       `json:"id"`
+	Operation   string       `json:"operation"`
+	Endpoint    string       `json:"endpoint"`
+	Namespace   *Namespace   `json:"namespace"`
+	Region      string       `json:"region,omitempty"`
+	RequestMeta *RequestMeta `json:"request_meta"`
+	NodeMeta    *NodeMeta    `json:"node_meta"`
+}
+
+// Namespace is the namespace targeted by the request.
+type Namespace struct {
+	ID string `json:"id"`
+}
+
+// RequestMeta describes the client that made the request.
+type RequestMeta struct {
+	RemoteAddr string `json:"remote_address"`
+	UserAgent  string `json:"user_agent"`
+}
+
+// NodeMeta describes the agent that handled the request.
+type NodeMeta struct {
+	IP string `json:"ip"`
+}
+
+// Response describes the outcome of the request.
+type Response struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_EventSink_CRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		sink := mock.EventSink()
+		path := fmt.Sprintf("/v1/event/sink/%s", sink.ID)
+
+		// Register the sink.
+		buf, err := json.Marshal(sink)
+		must.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPut, path, bytes.NewReader(buf))
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.EventSinkSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// An ID that doesn't match the path is rejected.
+		req, err = http.NewRequest(http.MethodPut, "/v1/event/sink/other", bytes.NewReader(buf))
+		must.NoError(t, err)
+		_, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "does not match")
+
+		// List the sinks.
+		req, err = http.NewRequest(http.MethodGet, "/v1/event/sinks", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err := s.Server.EventSinksRequest(respW, req)
+		must.NoError(t, err)
+		must.SliceLen(t, 1, obj.([]*structs.EventSinkListStub))
+
+		// Read the sink.
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.EventSinkSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, sink.Address, obj.(*structs.EventSink).Address)
+
+		// Delete the sink.
+		req, err = http.NewRequest(http.MethodDelete, path, nil)
+		must.NoError(t, err)
+		_, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		must.NoError(t, err)
+		_, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "not found")
+	})
+}
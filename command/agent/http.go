@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -58,6 +59,11 @@ const (
 	// MissingRequestID is a placeholder if we cannot retrieve a request
 	// UUID from context
 	MissingRequestID = "<missing request id>"
+
+	// certLoginPath is the only path which accepts client certificates issued
+	// by the CAs of cert auth methods rather than the CA of the agent, when
+	// HTTPS clients are verified.
+	certLoginPath = "/v1/acl/login"
 )
 
 var (
@@ -156,12 +162,32 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 		}
 
 		// If TLS is enabled, wrap the listener with a TLS listener
+		var agentCAs *x509.CertPool
 		if config.TLSConfig.EnableHTTP {
 			tlsConfig, err := tlsConf.IncomingTLSConfig()
 			if err != nil {
 				serverInitializationErrors = multierror.Append(serverInitializationErrors, err)
 				continue
 			}
+
+			// Request client certificates even if they aren't verified, so
+			// they can be exchanged for ACL tokens by cert auth methods. The
+			// auth method verifies the certificate against its own CAs.
+			//
+			// When clients are verified, servers also accept certificates
+			// issued by the CAs of cert auth methods during the handshake,
+			// and only allow them to log in.
+			switch {
+			case tlsConfig.ClientAuth == tls.NoClientCert:
+				tlsConfig.ClientAuth = tls.RequestClientCert
+			case agent.Server() != nil:
+				agentCAs = tlsConfig.ClientCAs
+				server := agent.Server()
+				tlsConfig.ClientAuth = tls.RequireAnyClientCert
+				tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					return verifyHTTPSClientCert(rawCerts, agentCAs, server)
+				}
+			}
 			ln = tls.NewListener(tcpKeepAliveListener{ln.(*net.TCPListener)}, tlsConfig)
 		}
 
@@ -178,10 +204,15 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 		}
 		srv.registerHandlers(config.EnableDebug)
 
+		var handler http.Handler = srv.mux
+		if agentCAs != nil {
+			handler = requireAgentClientCert(agentCAs, handler)
+		}
+
 		// Create HTTP server with timeouts
 		httpServer := http.Server{
 			Addr:      srv.Addr,
-			Handler:   handlers.CompressHandler(srv.mux),
+			Handler:   handlers.CompressHandler(handler),
 			ConnState: makeConnState(config.TLSConfig.EnableHTTP, handshakeTimeout, maxConns, srv.logger),
 			ErrorLog:  newHTTPServerLogger(srv.logger),
 		}
@@ -417,6 +448,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/acl/tokens", s.wrap(s.ACLTokensRequest))
 	s.mux.HandleFunc("/v1/acl/token", s.wrap(s.ACLTokenSpecificRequest))
 	s.mux.HandleFunc("/v1/acl/token/", s.wrap(s.ACLTokenSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/explain", s.wrap(s.ACLExplainRequest))
 
 	// Register our ACL role handlers.
 	s.mux.HandleFunc("/v1/acl/roles", s.wrap(s.ACLRoleListRequest))
@@ -436,6 +468,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	// Register out ACL OIDC SSO and auth handlers.
 	s.mux.HandleFunc("/v1/acl/oidc/auth-url", s.wrap(s.ACLOIDCAuthURLRequest))
 	s.mux.HandleFunc("/v1/acl/oidc/complete-auth", s.wrap(s.ACLOIDCCompleteAuthRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/device-auth", s.wrap(s.ACLOIDCDeviceAuthRequest))
 	s.mux.HandleFunc("/v1/acl/login", s.wrap(s.ACLLoginRequest))
 
 	s.mux.Handle("/v1/client/fs/", wrapCORS(s.wrap(s.FsRequest)))
@@ -443,6 +476,8 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.Handle("/v1/client/stats", wrapCORS(s.wrap(s.ClientStatsRequest)))
 	s.mux.Handle("/v1/client/allocation/", wrapCORS(s.wrap(s.ClientAllocRequest)))
 	s.mux.Handle("/v1/client/metadata", wrapCORS(s.wrap(s.NodeMetaRequest)))
+	s.mux.HandleFunc("/v1/client/artifact-cache", s.wrap(s.ArtifactCacheRequest))
+	s.mux.HandleFunc("/v1/client/artifact-cache/purge", s.wrap(s.ArtifactCachePurgeRequest))
 
 	s.mux.HandleFunc("/v1/agent/self", s.wrap(s.AgentSelfRequest))
 	s.mux.HandleFunc("/v1/agent/join", s.wrap(s.AgentJoinRequest))
@@ -496,6 +531,11 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
 
 	s.mux.HandleFunc("/v1/event/stream", s.wrap(s.EventStream))
+	s.mux.HandleFunc("/v1/event/sinks", s.wrap(s.EventSinksRequest))
+	s.mux.HandleFunc("/v1/event/sink/", s.wrap(s.EventSinkSpecificRequest))
+
+	s.mux.HandleFunc("/v1/network-policies", s.wrap(s.NetworkPoliciesRequest))
+	s.mux.HandleFunc("/v1/network-policy/", s.wrap(s.NetworkPolicySpecificRequest))
 
 	s.mux.HandleFunc("/v1/namespaces", s.wrap(s.NamespacesRequest))
 	s.mux.HandleFunc("/v1/namespace", s.wrap(s.NamespaceCreateRequest))
@@ -506,6 +546,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 
 	// OIDC Handlers
 	s.mux.HandleFunc(structs.JWKSPath, s.wrap(s.JWKSRequest))
+	s.mux.HandleFunc(structs.SPIFFEBundlePath, s.wrap(s.SPIFFEBundleRequest))
 	s.mux.HandleFunc("/.well-known/openid-configuration", s.wrap(s.OIDCDiscoveryRequest))
 
 	agentConfig := s.agent.GetConfig()
@@ -1125,6 +1166,93 @@ func (s *HTTPServer) wrapUntrustedContent(handler handlerFn) handlerFn {
 	}
 }
 
+// verifyHTTPSClientCert verifies the client certificate chain presented
+// during the TLS handshake against the CA of the agent, or else against the CAs
+// of the cert auth methods of the server.
+func verifyHTTPSClientCert(rawCerts [][]byte, agentCAs *x509.CertPool, srv *nomad.Server) error {
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	err := verifyClientCertChain(certs, agentCAs)
+	if err == nil {
+		return nil
+	}
+
+	methodCAs, methodErr := certAuthMethodCAs(srv)
+	if methodErr != nil {
+		return methodErr
+	}
+	if methodCAs == nil || verifyClientCertChain(certs, methodCAs) != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyClientCertChain verifies the client certificate chain, leaf first,
+// against the roots.
+func verifyClientCertChain(certs []*x509.Certificate, roots *x509.CertPool) error {
+	if len(certs) == 0 {
+		return errors.New("no client certificate presented")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// certAuthMethodCAs returns the CA certs of the cert auth methods of the
+// server, or nil if there are none.
+func certAuthMethodCAs(srv *nomad.Server) (*x509.CertPool, error) {
+	iter, err := srv.State().GetACLAuthMethods(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool *x509.CertPool
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		if method.Type != structs.ACLAuthMethodTypeCert || method.Config == nil {
+			continue
+		}
+		for _, caCert := range method.Config.CertCACerts {
+			if pool == nil {
+				pool = x509.NewCertPool()
+			}
+			pool.AppendCertsFromPEM([]byte(caCert))
+		}
+	}
+	return pool, nil
+}
+
+// requireAgentClientCert rejects requests to any path but the login one which
+// present a client certificate not issued by the CA of the agent, as accepted
+// by verifyHTTPSClientCert for cert auth methods.
+func requireAgentClientCert(agentCAs *x509.CertPool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.TLS != nil && req.URL.Path != certLoginPath {
+			if err := verifyClientCertChain(req.TLS.PeerCertificates, agentCAs); err != nil {
+				resp.WriteHeader(http.StatusForbidden)
+				resp.Write([]byte("client certificates issued by the CAs of auth methods may only be used to log in"))
+				return
+			}
+		}
+		next.ServeHTTP(resp, req)
+	})
+}
+
 // wrapCORS wraps a HandlerFunc in allowCORS with read ("HEAD", "GET") methods
 // and returns a http.Handler
 func wrapCORS(f func(http.ResponseWriter, *http.Request)) http.Handler {
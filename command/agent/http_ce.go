@@ -8,6 +8,11 @@ package agent
 
 import (
 	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // registerEnterpriseHandlers is a no-op for the oss release
@@ -32,15 +37,184 @@ func (s *HTTPServer) entOnly(resp http.ResponseWriter, req *http.Request) (inter
 
 // auditHandler wraps the passed handlerFn
 func (s *HTTPServer) auditHandler(h handlerFn) handlerFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		if !s.auditEnabled() {
+			return h(resp, req)
+		}
+
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			return nil, err
+		}
+		obj, rspErr := h(resp, req)
+		if err := s.auditComplete(req, ev, http.StatusOK, rspErr); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
 // auditHTTPHandler wraps  the passed handlerByteFn
 func (s *HTTPServer) auditNonJSONHandler(h handlerByteFn) handlerByteFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+		if !s.auditEnabled() {
+			return h(resp, req)
+		}
+
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			return nil, err
+		}
+		obj, rspErr := h(resp, req)
+		if err := s.auditComplete(req, ev, http.StatusOK, rspErr); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
 // auditHTTPHandler wraps the passed http.Handler
 func (s *HTTPServer) auditHTTPHandler(h http.Handler) http.Handler {
-	return h
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !s.auditEnabled() {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			code, errMsg := errCodeFromHandler(err)
+			resp.WriteHeader(code)
+			resp.Write([]byte(errMsg))
+			return
+		}
+
+		// The status of the response is already sent when the request
+		// completes, so it can't fail anymore.
+		rec := &auditResponseWriter{ResponseWriter: resp, status: http.StatusOK}
+		h.ServeHTTP(rec, req)
+		_ = s.auditComplete(req, ev, rec.status, nil)
+	})
+}
+
+func (s *HTTPServer) auditEnabled() bool {
+	return s.eventAuditor != nil && s.eventAuditor.Enabled()
+}
+
+// auditReceived writes the audit event of a request before it's handled. An
+// error is returned if the request must not be handled because the event
+// couldn't be written.
+func (s *HTTPServer) auditReceived(req *http.Request) (*event.Event, error) {
+	var namespace, region string
+	parseNamespace(req, &namespace)
+	s.parseRegion(req, &region)
+
+	ev := &event.Event{
+		ID:        uuid.Generate(),
+		Stage:     event.OperationReceived,
+		Type:      event.AuditEventType,
+		Timestamp: time.Now().UTC(),
+		Version:   event.AuditEventVersion,
+		Auth:      s.auditAuth(req),
+		Request: &event.Request{
+			ID:        uuid.Generate(),
+			Operation: req.Method,
+			Endpoint:  req.URL.RequestURI(),
+			Namespace: &event.Namespace{ID: namespace},
+			Region:    region,
+			RequestMeta: &event.RequestMeta{
+				RemoteAddr: req.RemoteAddr,
+				UserAgent:  req.UserAgent(),
+			},
+			NodeMeta: &event.NodeMeta{
+				IP: s.Addr,
+			},
+		},
+	}
+
+	if err := s.eventAuditor.Event(req.Context(), event.AuditEventType, ev); err != nil {
+		s.logger.Error("failed to write audit event", "stage", ev.Stage, "error", err)
+		return nil, CodedError(http.StatusInternalServerError, "failed to write audit event")
+	}
+	return ev, nil
+}
+
+// auditComplete writes the audit event of a request after it was handled. An
+// error is returned if the event couldn't be written, in which case the
+// response of the request must be replaced by an error.
+func (s *HTTPServer) auditComplete(req *http.Request, received *event.Event, status int, rspErr error) error {
+	ev := *received
+	ev.Stage = event.OperationComplete
+	ev.Response = &event.Response{StatusCode: status}
+	if rspErr != nil {
+		ev.Response.StatusCode, ev.Response.Error = errCodeFromHandler(rspErr)
+	}
+
+	if err := s.eventAuditor.Event(req.Context(), event.AuditEventType, &ev); err != nil {
+		s.logger.Error("failed to write audit event", "stage", ev.Stage, "error", err)
+		return CodedError(http.StatusInternalServerError, "failed to write audit event")
+	}
+	return nil
+}
+
+// auditAuth returns the identity that made the request. Resolution errors
+// are recorded in the event instead of failing the request, since the
+// handler reports them to the caller.
+func (s *HTTPServer) auditAuth(req *http.Request) *event.Auth {
+	var secret string
+	s.parseToken(req, &secret)
+
+	var ident *structs.AuthenticatedIdentity
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		args := structs.GenericRequest{QueryOptions: structs.QueryOptions{AuthToken: secret}}
+		err = srv.Authenticate(nil, &args)
+		ident = args.GetIdentity()
+	} else {
+		ident, err = s.agent.Client().ResolveIdentity(secret)
+	}
+
+	auth := &event.Auth{}
+	if err != nil {
+		auth.Error = err.Error()
+	}
+	if ident == nil {
+		return auth
+	}
+
+	switch {
+	case ident.ACLToken != nil:
+		token := ident.ACLToken
+		auth.AccessorID = token.AccessorID
+		auth.Name = token.Name
+		auth.Type = token.Type
+		auth.Policies = token.Policies
+		auth.Global = token.Global
+		auth.CreateTime = token.CreateTime
+		for _, role := range token.Roles {
+			auth.Roles = append(auth.Roles, role.ID)
+		}
+	case ident.Claims != nil:
+		auth.Workload = &event.WorkloadIdentity{
+			Namespace:    ident.Claims.Namespace,
+			JobID:        ident.Claims.JobID,
+			AllocationID: ident.Claims.AllocationID,
+			TaskName:     ident.Claims.TaskName,
+			ServiceName:  ident.Claims.ServiceName,
+		}
+	case ident.ClientID != "":
+		auth.ClientID = ident.ClientID
+	}
+	return auth
+}
+
+// auditResponseWriter records the status code of a response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
 }
@@ -5,6 +5,7 @@ package agent
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -16,6 +17,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -28,6 +30,7 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -1059,6 +1062,129 @@ func TestHTTP_VerifyHTTPSClient_AfterConfigReload(t *testing.T) {
 	}
 }
 
+// TestHTTP_VerifyHTTPSClient_CertAuthMethod asserts that servers verifying
+// HTTPS clients accept certificates issued by the CA of a cert auth method,
+// but only to log in.
+func TestHTTP_VerifyHTTPSClient_CertAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		must.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		return path
+	}
+	generateCA := func() (string, crypto.Signer) {
+		signer, _, err := tlsutil.GeneratePrivateKey()
+		must.NoError(t, err)
+		caPEM, _, err := tlsutil.GenerateCA(tlsutil.CAOpts{Signer: signer})
+		must.NoError(t, err)
+		return caPEM, signer
+	}
+	generateCert := func(caPEM string, signer crypto.Signer, name string) tls.Certificate {
+		certPEM, keyPEM, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+			Signer: signer,
+			CA:     caPEM,
+			Name:   name,
+			Days:   1,
+			ExtKeyUsage: []x509.ExtKeyUsage{
+				x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		})
+		must.NoError(t, err)
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		must.NoError(t, err)
+		return cert
+	}
+
+	agentCAPEM, agentCASigner := generateCA()
+	agentCertPEM, agentKeyPEM, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer:   agentCASigner,
+		CA:       agentCAPEM,
+		Name:     "server.global.nomad",
+		DNSNames: []string{"server.global.nomad"},
+		Days:     1,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	must.NoError(t, err)
+	methodCAPEM, methodCASigner := generateCA()
+	otherCAPEM, otherCASigner := generateCA()
+
+	httpACLTest(t, func(c *Config) {
+		c.TLSConfig = &config.TLSConfig{
+			EnableHTTP:        true,
+			EnableRPC:         true,
+			VerifyHTTPSClient: true,
+			CAFile:            writeFile("ca.pem", agentCAPEM),
+			CertFile:          writeFile("agent.pem", agentCertPEM),
+			KeyFile:           writeFile("agent-key.pem", agentKeyPEM),
+		}
+	}, func(s *TestAgent) {
+		state := s.Agent.server.State()
+		authMethod := mock.ACLCertAuthMethod()
+		authMethod.Config.CertCACerts = []string{methodCAPEM}
+		authMethod.SetHash()
+		must.NoError(t, state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{authMethod}))
+
+		policy := mock.ACLPolicy()
+		must.NoError(t, state.UpsertACLPolicies(
+			structs.MsgTypeTestSetup, 1010, []*structs.ACLPolicy{policy}))
+
+		bindingRule := mock.ACLBindingRule()
+		bindingRule.AuthMethod = authMethod.Name
+		bindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+		bindingRule.Selector = `value.common_name == "deployer"`
+		bindingRule.BindName = policy.Name
+		must.NoError(t, state.UpsertACLBindingRules(
+			1020, []*structs.ACLBindingRule{bindingRule}, true))
+
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM([]byte(agentCAPEM))
+		do := func(cert tls.Certificate, method, path string, body io.Reader) (*http.Response, error) {
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				ServerName: "server.global.nomad",
+				RootCAs:    roots,
+				GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					return &cert, nil
+				},
+			}}}
+			req, err := http.NewRequest(method,
+				fmt.Sprintf("https://%s%s", s.Agent.config.AdvertiseAddrs.HTTP, path), body)
+			must.NoError(t, err)
+			return client.Do(req)
+		}
+		login := fmt.Sprintf(`{"AuthMethodName": %q}`, authMethod.Name)
+
+		// Certificates issued by the CA of the agent are accepted
+		agentCert := generateCert(agentCAPEM, agentCASigner, "client.global.nomad")
+		resp, err := do(agentCert, http.MethodGet, "/v1/agent/health", nil)
+		must.NoError(t, err)
+		resp.Body.Close()
+		must.Eq(t, http.StatusOK, resp.StatusCode)
+
+		// Certificates issued by the CA of the auth method can log in
+		methodCert := generateCert(methodCAPEM, methodCASigner, "deployer")
+		resp, err = do(methodCert, http.MethodPost, "/v1/acl/login", strings.NewReader(login))
+		must.NoError(t, err)
+		var token structs.ACLToken
+		must.NoError(t, json.NewDecoder(resp.Body).Decode(&token))
+		resp.Body.Close()
+		must.Eq(t, http.StatusOK, resp.StatusCode)
+		must.Eq(t, []string{policy.Name}, token.Policies)
+
+		// but can't be used for anything else
+		resp, err = do(methodCert, http.MethodGet, "/v1/agent/health", nil)
+		must.NoError(t, err)
+		resp.Body.Close()
+		must.Eq(t, http.StatusForbidden, resp.StatusCode)
+
+		// Certificates issued by other CAs are rejected during the handshake
+		otherCert := generateCert(otherCAPEM, otherCASigner, "deployer")
+		_, err = do(otherCert, http.MethodPost, "/v1/acl/login", strings.NewReader(login))
+		must.Error(t, err)
+	})
+}
+
 // TestHTTPServer_Limits_Error asserts invalid Limits cause errors. This is the
 // HTTP counterpart to TestAgent_ServerConfig_Limits_Error.
 func TestHTTPServer_Limits_Error(t *testing.T) {
@@ -106,6 +106,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/scale"):
 		jobID := strings.TrimSuffix(path, "/scale")
 		return s.jobScale(resp, req, jobID)
+	case strings.HasSuffix(path, "/prefetch"):
+		jobID := strings.TrimSuffix(path, "/prefetch")
+		return s.jobPrefetch(resp, req, jobID)
 	case strings.HasSuffix(path, "/services"):
 		jobID := strings.TrimSuffix(path, "/services")
 		return s.jobServiceRegistrations(resp, req, jobID)
@@ -681,6 +684,58 @@ func (s *HTTPServer) jobScaleAction(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobPrefetch(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.jobPrefetchStatus(resp, req, jobID)
+	case http.MethodPut, http.MethodPost:
+		return s.jobPrefetchStart(resp, req, jobID)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) jobPrefetchStatus(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+
+	args := structs.JobPrefetchStatusRequest{
+		JobID: jobID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobPrefetchStatusResponse
+	if err := s.agent.RPC("Job.PrefetchStatus", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Nodes == nil {
+		out.Nodes = make([]*structs.NodePrefetch, 0)
+	}
+	return out, nil
+}
+
+func (s *HTTPServer) jobPrefetchStart(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+
+	args := structs.JobPrefetchRequest{
+		JobID: jobID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobPrefetchResponse
+	if err := s.agent.RPC("Job.Prefetch", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	if out.NodeIDs == nil {
+		out.NodeIDs = make([]string, 0)
+	}
+	return out, nil
+}
+
 func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
 
 	diffsStr := req.URL.Query().Get("diffs")
@@ -830,9 +885,10 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 		return nil, err
 	}
 
-	// Check job parse permissions
+	// Check job parse permissions. The job ID is only known once the job is
+	// parsed, so allow tokens that can submit any job in the namespace.
 	hasParseJob := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityParseJob)
-	hasSubmitJob := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilitySubmitJob)
+	hasSubmitJob := aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilitySubmitJob)
 
 	allowed := hasParseJob || hasSubmitJob
 	if !allowed {
@@ -865,6 +921,17 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 		return nil, CodedError(400, err.Error())
 	}
 
+	// Job rules may deny the token access to the parsed job
+	if !hasParseJob {
+		var jobID string
+		if jobStruct.ID != nil {
+			jobID = *jobStruct.ID
+		}
+		if !aclObj.AllowJobOp(namespace, jobID, acl.NamespaceCapabilitySubmitJob) {
+			return nil, structs.ErrPermissionDenied
+		}
+	}
+
 	if args.Canonicalize {
 		jobStruct.Canonicalize()
 	}
@@ -1189,6 +1256,16 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		}
 	}
 
+	if taskGroup.TemplateRestart != nil {
+		tg.TemplateRestart = &structs.TemplateRestartPolicy{
+			MaxParallel:     *taskGroup.TemplateRestart.MaxParallel,
+			Stagger:         *taskGroup.TemplateRestart.Stagger,
+			HealthCheck:     *taskGroup.TemplateRestart.HealthCheck,
+			MinHealthyTime:  *taskGroup.TemplateRestart.MinHealthyTime,
+			HealthyDeadline: *taskGroup.TemplateRestart.HealthyDeadline,
+		}
+	}
+
 	if taskGroup.Scaling != nil {
 		tg.Scaling = ApiScalingPolicyToStructs(tg.Count, taskGroup.Scaling).TargetTaskGroup(job, tg)
 	}
@@ -1340,15 +1417,22 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	if len(apiTask.Artifacts) > 0 {
 		structsTask.Artifacts = []*structs.TaskArtifact{}
 		for _, ta := range apiTask.Artifacts {
-			structsTask.Artifacts = append(structsTask.Artifacts,
-				&structs.TaskArtifact{
-					GetterSource:   *ta.GetterSource,
-					GetterOptions:  maps.Clone(ta.GetterOptions),
-					GetterHeaders:  maps.Clone(ta.GetterHeaders),
-					GetterMode:     *ta.GetterMode,
-					GetterInsecure: *ta.GetterInsecure,
-					RelativeDest:   *ta.RelativeDest,
-				})
+			artifact := &structs.TaskArtifact{
+				GetterSource:   *ta.GetterSource,
+				GetterOptions:  maps.Clone(ta.GetterOptions),
+				GetterHeaders:  maps.Clone(ta.GetterHeaders),
+				GetterMode:     *ta.GetterMode,
+				GetterInsecure: *ta.GetterInsecure,
+				RelativeDest:   *ta.RelativeDest,
+			}
+			if ta.Signature != nil {
+				artifact.Signature = &structs.TaskArtifactSignature{
+					Source: *ta.Signature.Source,
+					Format: *ta.Signature.Format,
+					Keys:   slices.Clone(ta.Signature.Keys),
+				}
+			}
+			structsTask.Artifacts = append(structsTask.Artifacts, artifact)
 		}
 	}
 
@@ -1370,6 +1454,17 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		structsTask.Consul = apiConsulToStructs(apiTask.Consul)
 	}
 
+	if apiTask.Nomad != nil {
+		structsTask.Nomad = &structs.Nomad{
+			Roles:        apiTask.Nomad.Roles,
+			TTL:          *apiTask.Nomad.TTL,
+			Env:          *apiTask.Nomad.Env,
+			DisableFile:  *apiTask.Nomad.DisableFile,
+			ChangeMode:   *apiTask.Nomad.ChangeMode,
+			ChangeSignal: *apiTask.Nomad.ChangeSignal,
+		}
+	}
+
 	if len(apiTask.Templates) > 0 {
 		structsTask.Templates = []*structs.Template{}
 		for _, template := range apiTask.Templates {
@@ -1395,6 +1490,24 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		}
 	}
 
+	if len(apiTask.Secrets) > 0 {
+		structsTask.Secrets = []*structs.Secret{}
+		for _, secret := range apiTask.Secrets {
+			structsTask.Secrets = append(structsTask.Secrets,
+				&structs.Secret{
+					Name:         secret.Name,
+					Provider:     *secret.Provider,
+					Path:         *secret.Path,
+					Env:          *secret.Env,
+					ChangeMode:   *secret.ChangeMode,
+					ChangeSignal: *secret.ChangeSignal,
+					Perms:        *secret.Perms,
+					Uid:          secret.Uid,
+					Gid:          secret.Gid,
+				})
+		}
+	}
+
 	if apiTask.DispatchPayload != nil {
 		structsTask.DispatchPayload = &structs.DispatchPayloadConfig{
 			File: apiTask.DispatchPayload.File,
@@ -1553,6 +1666,13 @@ func ApiNetworkResourceToStructs(in []*api.NetworkResource) []*structs.NetworkRe
 			}
 		}
 
+		if nw.Bandwidth != nil {
+			out[i].Bandwidth = &structs.NetworkBandwidth{
+				IngressMBits: nw.Bandwidth.IngressMBits,
+				EgressMBits:  nw.Bandwidth.EgressMBits,
+			}
+		}
+
 		if l := len(nw.DynamicPorts); l != 0 {
 			out[i].DynamicPorts = make([]structs.Port, l)
 			for j, dp := range nw.DynamicPorts {
@@ -1679,6 +1799,7 @@ func apiWorkloadIdentityToStructs(in *api.WorkloadIdentity) *structs.WorkloadIde
 		File:         in.File,
 		ServiceName:  in.ServiceName,
 		TTL:          in.TTL,
+		X509:         in.X509,
 	}
 }
 
@@ -1247,6 +1247,55 @@ func TestHTTP_Job_ScaleStatus(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobPrefetch(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		job := mock.Job()
+		job.TaskGroups[0].Tasks[0].Artifacts = []*structs.TaskArtifact{
+			{GetterSource: "https://example.com/file.txt"},
+		}
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request to prefetch the job
+		req, err := http.NewRequest(http.MethodPut, "/v1/job/"+job.ID+"/prefetch", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+		prefetch := obj.(structs.JobPrefetchResponse)
+		must.NotNil(t, prefetch.NodeIDs)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// Make the HTTP request to read the progress
+		req, err = http.NewRequest(http.MethodGet, "/v1/job/"+job.ID+"/prefetch", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.JobSpecificRequest(respW, req)
+		must.NoError(t, err)
+		status := obj.(structs.JobPrefetchStatusResponse)
+		must.NotNil(t, status.Nodes)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// Other methods are not allowed
+		req, err = http.NewRequest(http.MethodDelete, "/v1/job/"+job.ID+"/prefetch", nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_JobActions(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -2664,6 +2713,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					MinHealthyTime:  pointer.Of(12 * time.Hour),
 					HealthyDeadline: pointer.Of(12 * time.Hour),
 				},
+				TemplateRestart: &api.TemplateRestartPolicy{
+					MaxParallel:     pointer.Of(5),
+					Stagger:         pointer.Of(time.Minute),
+					HealthCheck:     pointer.Of("task_states"),
+					MinHealthyTime:  pointer.Of(20 * time.Second),
+					HealthyDeadline: pointer.Of(10 * time.Minute),
+				},
 				Spreads: []*api.Spread{
 					{
 						Attribute: "${node.datacenter}",
@@ -2925,6 +2981,16 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								GetterMode:   pointer.Of("dir"),
 								RelativeDest: pointer.Of("dest"),
 							},
+							{
+								GetterSource: pointer.Of("source2"),
+								GetterMode:   pointer.Of("file"),
+								RelativeDest: pointer.Of("local/app.tar.gz"),
+								Signature: &api.TaskArtifactSignature{
+									Source: pointer.Of("source2.sig"),
+									Format: pointer.Of("cosign"),
+									Keys:   []string{"release"},
+								},
+							},
 						},
 						Vault: &api.Vault{
 							Role:         "nomad-task",
@@ -2962,6 +3028,26 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								ErrMissingKey: pointer.Of(true),
 							},
 						},
+						Nomad: &api.Nomad{
+							Roles:        []string{"ops"},
+							TTL:          pointer.Of(30 * time.Minute),
+							Env:          pointer.Of(false),
+							DisableFile:  pointer.Of(false),
+							ChangeMode:   pointer.Of("signal"),
+							ChangeSignal: pointer.Of("sighup"),
+						},
+						Secrets: []*api.Secret{
+							{
+								Name:         "db",
+								Provider:     pointer.Of("nomad"),
+								Path:         pointer.Of("nomad/jobs/db"),
+								Env:          pointer.Of(false),
+								ChangeMode:   pointer.Of("signal"),
+								ChangeSignal: pointer.Of("sighup"),
+								Perms:        pointer.Of("0400"),
+								Uid:          pointer.Of(1000),
+							},
+						},
 						DispatchPayload: &api.DispatchPayloadConfig{
 							File: "fileA",
 						},
@@ -3107,6 +3193,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					MinHealthyTime:  12 * time.Hour,
 					HealthyDeadline: 12 * time.Hour,
 				},
+				TemplateRestart: &structs.TemplateRestartPolicy{
+					MaxParallel:     5,
+					Stagger:         time.Minute,
+					HealthCheck:     "task_states",
+					MinHealthyTime:  20 * time.Second,
+					HealthyDeadline: 10 * time.Minute,
+				},
 				EphemeralDisk: &structs.EphemeralDisk{
 					SizeMB:  100,
 					Sticky:  true,
@@ -3372,6 +3465,16 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								GetterMode:   "dir",
 								RelativeDest: "dest",
 							},
+							{
+								GetterSource: "source2",
+								GetterMode:   "file",
+								RelativeDest: "local/app.tar.gz",
+								Signature: &structs.TaskArtifactSignature{
+									Source: "source2.sig",
+									Format: "cosign",
+									Keys:   []string{"release"},
+								},
+							},
 						},
 						Vault: &structs.Vault{
 							Role:                 "nomad-task",
@@ -3411,6 +3514,23 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								ErrMissingKey: true,
 							},
 						},
+						Nomad: &structs.Nomad{
+							Roles:        []string{"ops"},
+							TTL:          30 * time.Minute,
+							ChangeMode:   "signal",
+							ChangeSignal: "sighup",
+						},
+						Secrets: []*structs.Secret{
+							{
+								Name:         "db",
+								Provider:     "nomad",
+								Path:         "nomad/jobs/db",
+								ChangeMode:   "signal",
+								ChangeSignal: "SIGHUP",
+								Perms:        "0400",
+								Uid:          pointer.Of(1000),
+							},
+						},
 						DispatchPayload: &structs.DispatchPayloadConfig{
 							File: "fileA",
 						},
@@ -4,6 +4,7 @@
 package agent
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"strings"
@@ -77,6 +78,74 @@ func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (a
 	return out, nil
 }
 
+// SPIFFEBundle is a SPIFFE trust bundle in its JWKS form, as defined by the
+// SPIFFE trust domain and bundle specification.
+type SPIFFEBundle struct {
+	Keys        []jose.JSONWebKey `json:"keys"`
+	Sequence    uint64            `json:"spiffe_sequence"`
+	RefreshHint int               `json:"spiffe_refresh_hint,omitempty"`
+}
+
+// SPIFFEBundleRequest is used to handle SPIFFE trust bundle requests. It
+// returns the CA certificates which sign workload identity X.509 SVIDs, so
+// that third parties may authenticate workloads using mTLS. Keys created
+// before X.509 SVIDs were supported are omitted.
+func (s *HTTPServer) SPIFFEBundleRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var rpcReply structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &rpcReply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &rpcReply.QueryMeta)
+
+	var newestKey int64
+	keys := make([]jose.JSONWebKey, 0, len(rpcReply.PublicKeys))
+	for _, pubKey := range rpcReply.PublicKeys {
+		if len(pubKey.X509CACert) == 0 {
+			continue
+		}
+
+		caCert, err := x509.ParseCertificate(pubKey.X509CACert)
+		if err != nil {
+			s.logger.Warn("error parsing ca certificate", "key_id", pubKey.KeyID, "error", err)
+			continue
+		}
+
+		if pubKey.CreateTime > newestKey {
+			newestKey = pubKey.CreateTime
+		}
+		keys = append(keys, jose.JSONWebKey{
+			Key:          caCert.PublicKey,
+			KeyID:        pubKey.KeyID,
+			Use:          structs.PubKeyUseX509SVID,
+			Certificates: []*x509.Certificate{caCert},
+		})
+	}
+
+	out := &SPIFFEBundle{
+		Keys:     keys,
+		Sequence: rpcReply.Index,
+	}
+
+	// Hint consumers to refresh the bundle when the next key is expected.
+	if newestKey > 0 && rpcReply.RotationThreshold > 0 {
+		exp := time.Unix(0, newestKey).Add(rpcReply.RotationThreshold)
+		refresh := helper.ExpiryToRenewTime(exp, time.Now, jwksMinMaxAge)
+		out.RefreshHint = int(refresh.Seconds())
+		resp.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", out.RefreshHint))
+	}
+
+	return out, nil
+}
+
 // OIDCDiscoveryRequest implements the OIDC Discovery protocol for using
 // workload identity JWTs with external services.
 //
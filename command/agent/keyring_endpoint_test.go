@@ -116,6 +116,35 @@ func TestHTTP_Keyring_JWKS(t *testing.T) {
 	})
 }
 
+// TestHTTP_Keyring_SPIFFEBundle asserts the SPIFFE trust bundle endpoint
+// publishes the workload identity CA certificates of the keyring.
+func TestHTTP_Keyring_SPIFFEBundle(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		respW := httptest.NewRecorder()
+
+		req, err := http.NewRequest(http.MethodGet, structs.SPIFFEBundlePath, nil)
+		must.NoError(t, err)
+
+		obj, err := s.Server.SPIFFEBundleRequest(respW, req)
+		must.NoError(t, err)
+
+		bundle := obj.(*SPIFFEBundle)
+		must.SliceLen(t, 1, bundle.Keys)
+		must.Eq(t, structs.PubKeyUseX509SVID, bundle.Keys[0].Use)
+		must.SliceLen(t, 1, bundle.Keys[0].Certificates)
+		must.True(t, bundle.Keys[0].Certificates[0].IsCA)
+		must.Positive(t, bundle.Sequence)
+		must.SliceLen(t, 1, respW.Header().Values("Cache-Control"))
+
+		req, err = http.NewRequest(http.MethodPost, structs.SPIFFEBundlePath, nil)
+		must.NoError(t, err)
+		_, err = s.Server.SPIFFEBundleRequest(respW, req)
+		must.EqError(t, err, ErrInvalidMethod)
+	})
+}
+
 // TestHTTP_Keyring_OIDCDisco_Disabled asserts that the OIDC Discovery endpoint
 // is disabled by default.
 func TestHTTP_Keyring_OIDCDisco_Disabled(t *testing.T) {
@@ -49,6 +49,9 @@ type logFile struct {
 	// Max rotated files to keep before removing them.
 	MaxFiles int
 
+	// mode is the permission mode of new log files, 0640 if unset
+	mode os.FileMode
+
 	//acquire is the mutex utilized to ensure we have no concurrency issues
 	acquire sync.Mutex
 }
@@ -70,7 +73,11 @@ func (l *logFile) openNew() error {
 	// Try creating or opening the active log file. Since the active log file
 	// always has the same name, append log entries to prevent overwriting
 	// previous log data.
-	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	mode := l.mode
+	if mode == 0 {
+		mode = 0640
+	}
+	filePointer, err := os.OpenFile(newfilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, mode)
 	if err != nil {
 		return err
 	}
@@ -138,7 +145,7 @@ func (l *logFile) pruneFiles() error {
 // Write is used to implement io.Writer
 func (l *logFile) Write(b []byte) (int, error) {
 	// Filter out log entries that do not match log level criteria
-	if !l.logFilter.Check(b) {
+	if l.logFilter != nil && !l.logFilter.Check(b) {
 		return 0, nil
 	}
 
@@ -159,3 +166,17 @@ func (l *logFile) Write(b []byte) (int, error) {
 	l.BytesWritten += int64(n)
 	return n, err
 }
+
+// Close closes the current log file. The next write opens the file again,
+// which allows it to be moved by external tools.
+func (l *logFile) Close() error {
+	l.acquire.Lock()
+	defer l.acquire.Unlock()
+
+	if l.FileInfo == nil {
+		return nil
+	}
+	err := l.FileInfo.Close()
+	l.FileInfo = nil
+	return err
+}
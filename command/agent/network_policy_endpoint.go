@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NetworkPoliciesRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.networkPolicyList(resp, req)
+	case http.MethodPut, http.MethodPost:
+		return s.networkPolicyUpsert(resp, req, "")
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) NetworkPolicySpecificRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/network-policy/")
+	if name == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing network policy name")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.networkPolicyQuery(resp, req, name)
+	case http.MethodPut, http.MethodPost:
+		return s.networkPolicyUpsert(resp, req, name)
+	case http.MethodDelete:
+		return s.networkPolicyDelete(resp, req, name)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) networkPolicyList(resp http.ResponseWriter, req *http.Request) (any, error) {
+	args := structs.NetworkPolicyListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NetworkPolicyListResponse
+	if err := s.agent.RPC("NetworkPolicy.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.NetworkPolicies == nil {
+		out.NetworkPolicies = make([]*structs.NetworkPolicy, 0)
+	}
+	return out.NetworkPolicies, nil
+}
+
+func (s *HTTPServer) networkPolicyQuery(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	args := structs.NetworkPolicySpecificRequest{
+		Name: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleNetworkPolicyResponse
+	if err := s.agent.RPC("NetworkPolicy.GetNetworkPolicy", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.NetworkPolicy == nil {
+		return nil, CodedError(http.StatusNotFound, "network policy not found")
+	}
+	return out.NetworkPolicy, nil
+}
+
+func (s *HTTPServer) networkPolicyUpsert(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	var policy structs.NetworkPolicy
+	if err := decodeBody(req, &policy); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	if name != "" && policy.Name != name {
+		return nil, CodedError(http.StatusBadRequest, "Network policy name does not match request path")
+	}
+
+	args := structs.NetworkPolicyUpsertRequest{
+		NetworkPolicies: []*structs.NetworkPolicy{&policy},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	// The namespace of the policy takes precedence over the request namespace,
+	// which is only used as a default.
+	if policy.Namespace != "" {
+		args.Namespace = policy.Namespace
+	}
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("NetworkPolicy.UpsertNetworkPolicies", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) networkPolicyDelete(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	args := structs.NetworkPolicyDeleteRequest{
+		Names: []string{name},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("NetworkPolicy.DeleteNetworkPolicies", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_NetworkPolicy_CRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		policy := mock.NetworkPolicy()
+		path := fmt.Sprintf("/v1/network-policy/%s", policy.Name)
+
+		// Register the policy.
+		buf, err := json.Marshal(policy)
+		must.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPut, path, bytes.NewReader(buf))
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.NetworkPolicySpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// A name that doesn't match the path is rejected.
+		req, err = http.NewRequest(http.MethodPut, "/v1/network-policy/other", bytes.NewReader(buf))
+		must.NoError(t, err)
+		_, err = s.Server.NetworkPolicySpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "does not match")
+
+		// List the policies.
+		req, err = http.NewRequest(http.MethodGet, "/v1/network-policies", nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err := s.Server.NetworkPoliciesRequest(respW, req)
+		must.NoError(t, err)
+		must.SliceLen(t, 1, obj.([]*structs.NetworkPolicy))
+
+		// Read the policy.
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.NetworkPolicySpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, policy.Ingress, obj.(*structs.NetworkPolicy).Ingress)
+
+		// Delete the policy.
+		req, err = http.NewRequest(http.MethodDelete, path, nil)
+		must.NoError(t, err)
+		_, err = s.Server.NetworkPolicySpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		must.NoError(t, err)
+		_, err = s.Server.NetworkPolicySpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "not found")
+	})
+}
@@ -0,0 +1,19 @@
+server {
+  enabled = true
+
+  keyring "aead" {}
+
+  keyring "transit" {
+    active = true
+
+    config {
+      address  = "https://vault.example.com:8200"
+      key_name = "nomad-keyring"
+    }
+  }
+
+  raft_encryption {
+    enabled            = true
+    rotation_threshold = "168h"
+  }
+}
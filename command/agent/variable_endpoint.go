@@ -144,6 +144,14 @@ func (s *HTTPServer) variableLockOperation(resp http.ResponseWriter, req *http.R
 
 func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	path string) (interface{}, error) {
+	query := req.URL.Query()
+	if _, ok := query["versions"]; ok {
+		return s.variableVersionsQuery(resp, req, path)
+	}
+	if query.Get("version") != "" {
+		return s.variableVersionQuery(resp, req, path)
+	}
+
 	args := structs.VariablesReadRequest{
 		Path: path,
 	}
@@ -163,6 +171,54 @@ func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Data, nil
 }
 
+func (s *HTTPServer) variableVersionsQuery(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesListVersionsRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, CodedError(http.StatusBadRequest, "failed to parse parameters")
+	}
+	var out structs.VariablesListVersionsResponse
+	if err := s.agent.RPC(structs.VariablesListVersionsRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if len(out.Versions) == 0 {
+		return nil, CodedError(http.StatusNotFound, "variable not found")
+	}
+	return out.Versions, nil
+}
+
+func (s *HTTPServer) variableVersionQuery(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	version, err := strconv.ParseUint(req.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("failed to parse version: %v", err))
+	}
+
+	args := structs.VariablesReadVersionRequest{
+		Path:    path,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, CodedError(http.StatusBadRequest, "failed to parse parameters")
+	}
+	var out structs.VariablesReadResponse
+	if err := s.agent.RPC(structs.VariablesReadVersionRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if out.Data == nil {
+		return nil, CodedError(http.StatusNotFound, "variable version not found")
+	}
+	return out.Data, nil
+}
+
 func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request,
 	path string) (interface{}, error) {
 
@@ -170,6 +170,54 @@ func TestHTTP_Variables(t *testing.T) {
 			// Check the output
 			must.Eq(t, out, obj.(*structs.VariableDecrypted))
 		})
+		t.Run("query_versions", func(t *testing.T) {
+			// Use RPC to make a test variable with a previous version
+			sv1 := mock.Variable()
+			must.NoError(t, rpcWriteSV(s, sv1, nil))
+			sv1.Items["new"] = "value"
+			out := new(structs.VariableDecrypted)
+			must.NoError(t, rpcWriteSV(s, sv1, out))
+			must.Eq(t, 2, out.Version)
+
+			// List the versions of the variable
+			req, err := http.NewRequest(http.MethodGet, "/v1/var/"+sv1.Path+"?versions", nil)
+			must.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			must.NoError(t, err)
+			must.NonZero(t, len(respW.HeaderMap.Get("X-Nomad-Index")))
+
+			versions := obj.([]*structs.VariableMetadata)
+			must.Len(t, 2, versions)
+			must.Eq(t, 2, versions[0].Version)
+			must.Eq(t, 1, versions[1].Version)
+
+			// Read the previous version of the variable
+			req, err = http.NewRequest(http.MethodGet, "/v1/var/"+sv1.Path+"?version=1", nil)
+			must.NoError(t, err)
+			respW = httptest.NewRecorder()
+			obj, err = s.Server.VariableSpecificRequest(respW, req)
+			must.NoError(t, err)
+			previous := obj.(*structs.VariableDecrypted)
+			must.Eq(t, 1, previous.Version)
+			must.MapNotContainsKey(t, previous.Items, "new")
+
+			// Read a version that doesn't exist
+			req, err = http.NewRequest(http.MethodGet, "/v1/var/"+sv1.Path+"?version=10", nil)
+			must.NoError(t, err)
+			respW = httptest.NewRecorder()
+			obj, err = s.Server.VariableSpecificRequest(respW, req)
+			must.ErrorContains(t, err, "variable version not found")
+			must.Nil(t, obj)
+		})
+		t.Run("error_parse_version", func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/v1/var/foo?version=abc", nil)
+			must.NoError(t, err)
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			must.ErrorContains(t, err, "failed to parse version")
+			must.Nil(t, obj)
+		})
 		rpcResetSV(s)
 
 		sv1 := mock.Variable()
@@ -298,6 +346,7 @@ func TestHTTP_Variables(t *testing.T) {
 				// can use a simple equality check
 				svU.ModifyIndex = out.ModifyIndex
 				svU.ModifyTime = out.ModifyTime
+				svU.Version = out.Version
 				must.Eq(t, &svU, out)
 			}
 		})
@@ -377,6 +426,7 @@ func TestHTTP_Variables(t *testing.T) {
 				// can use a simple equality check
 				svU.CreateIndex, svU.ModifyIndex = out.CreateIndex, out.ModifyIndex
 				svU.CreateTime, svU.ModifyTime = out.CreateTime, out.ModifyTime
+				svU.Version = out.Version
 				must.Eq(t, svU.VariableMetadata, out.VariableMetadata)
 
 				// fmt writes sorted output of maps for testability.
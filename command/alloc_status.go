@@ -498,6 +498,12 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		} else {
 			desc = "Failed to download artifacts"
 		}
+	case api.TaskArtifactVerificationFailed:
+		if v := event.Details["verification_error"]; v != "" {
+			desc = v
+		} else {
+			desc = "Failed to verify artifact signature"
+		}
 	case api.TaskKilling:
 		if event.KillReason != "" {
 			desc = fmt.Sprintf("Killing task: %v", event.KillReason)
@@ -230,6 +230,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl token explain": func() (cli.Command, error) {
+			return &ACLTokenExplainCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl token renew": func() (cli.Command, error) {
+			return &ACLTokenRenewCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl token self": func() (cli.Command, error) {
 			return &ACLTokenSelfCommand{
 				Meta: meta,
@@ -486,6 +496,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job prefetch": func() (cli.Command, error) {
+			return &JobPrefetchCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job promote": func() (cli.Command, error) {
 			return &JobPromoteCommand{
 				Meta: meta,
@@ -591,6 +606,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node artifact-cache": func() (cli.Command, error) {
+			return &NodeArtifactCacheCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node artifact-cache inspect": func() (cli.Command, error) {
+			return &NodeArtifactCacheInspectCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node artifact-cache purge": func() (cli.Command, error) {
+			return &NodeArtifactCachePurgeCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-drain": func() (cli.Command, error) {
 			return &NodeDrainCommand{
 				Meta: meta,
@@ -716,6 +746,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator event-sink": func() (cli.Command, error) {
+			return &OperatorEventSinkCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator event-sink apply": func() (cli.Command, error) {
+			return &OperatorEventSinkApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator event-sink delete": func() (cli.Command, error) {
+			return &OperatorEventSinkDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator event-sink info": func() (cli.Command, error) {
+			return &OperatorEventSinkInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator event-sink list": func() (cli.Command, error) {
+			return &OperatorEventSinkListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator gossip keyring": func() (cli.Command, error) {
 			return &OperatorGossipKeyringCommand{
 				Meta: meta,
@@ -1146,6 +1201,36 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"var history": func() (cli.Command, error) {
+			return &VarHistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var rollback": func() (cli.Command, error) {
+			return &VarRollbackCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var export": func() (cli.Command, error) {
+			return &VarExportCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var import": func() (cli.Command, error) {
+			return &VarImportCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var keygen": func() (cli.Command, error) {
+			return &VarKeygenCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var sync": func() (cli.Command, error) {
+			return &VarSyncCommand{
+				Meta: meta,
+			}, nil
+		},
 		"var init": func() (cli.Command, error) {
 			return &VarInitCommand{
 				Meta: meta,
@@ -0,0 +1,262 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+// jobPrefetchPollInterval is how often the progress of a prefetch is polled
+// while monitoring it.
+var jobPrefetchPollInterval = time.Second
+
+type JobPrefetchCommand struct {
+	Meta
+}
+
+func (c *JobPrefetchCommand) Help() string {
+	helpText := `
+Usage: nomad job prefetch [options] <job>
+
+  Prefetch pulls the container images and downloads the artifacts of a job onto
+  the ready nodes in its datacenters and node pool, ahead of its placement, so
+  that its tasks start faster. Images are pulled with the docker driver and
+  artifacts are downloaded into the client's artifact cache. Images and
+  artifacts interpolated at runtime are not prefetched.
+
+  The scheduler prefers nodes holding the prefetched images of a task group
+  when placing its allocations.
+
+  When ACLs are enabled, this command requires a token with the 'submit-job'
+  and 'read-job' capabilities for the job's namespace. The 'list-jobs'
+  capability is required to run the command with a job prefix instead of the
+  exact job ID.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Prefetch Options:
+
+  -detach
+    Return immediately instead of monitoring the progress of the prefetch on
+    each node.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobPrefetchCommand) Synopsis() string {
+	return "Prefetch the images and artifacts of a job onto nodes"
+}
+
+func (c *JobPrefetchCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-detach":  complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobPrefetchCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobPrefetchCommand) Name() string { return "job prefetch" }
+
+func (c *JobPrefetchCommand) Run(args []string) int {
+	var detach, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobIDPrefix := strings.TrimSpace(args[0])
+	jobID, namespace, err := c.JobIDByPrefix(client, jobIDPrefix, nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	resp, _, err := client.Jobs().Prefetch(jobID, &api.WriteOptions{Namespace: namespace})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error prefetching job %q: %s", jobID, err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Prefetching job %q on %d node(s)", jobID, len(resp.NodeIDs)))
+	code := c.outputErrors(resp.Errors, length)
+
+	if detach || len(resp.NodeIDs) == 0 {
+		return code
+	}
+
+	// Monitor the progress until the prefetch finished on every node
+	q := &api.QueryOptions{Namespace: namespace}
+	statuses := make(map[string]string, len(resp.NodeIDs))
+	var nodes []*api.NodePrefetch
+	for {
+		status, _, err := client.Jobs().PrefetchStatus(jobID, q)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading prefetch status: %s", err))
+			return 1
+		}
+
+		nodes = nodes[:0]
+		done := true
+		for _, node := range status.Nodes {
+			if !slices.Contains(resp.NodeIDs, node.NodeID) {
+				continue
+			}
+			nodes = append(nodes, node)
+			if statuses[node.NodeID] != node.Status {
+				statuses[node.NodeID] = node.Status
+				c.Ui.Output(fmt.Sprintf("    %s: Node %q prefetch %s",
+					formatTime(time.Now()), limit(node.NodeID, length), node.Status))
+			}
+			done = done && node.Terminal()
+		}
+
+		// nodes which restarted lose the progress of their prefetch
+		if done || len(nodes) == 0 {
+			break
+		}
+		time.Sleep(jobPrefetchPollInterval)
+	}
+
+	c.Ui.Output("")
+	c.Ui.Output(c.Colorize().Color("[bold]Prefetch Status[reset]"))
+	c.Ui.Output(formatNodePrefetches(nodes, length))
+
+	if failures := formatPrefetchFailures(nodes, length); failures != "" {
+		c.Ui.Output("")
+		c.Ui.Output(c.Colorize().Color("[bold]Failures[reset]"))
+		c.Ui.Output(failures)
+		code = 2
+	}
+	return code
+}
+
+// outputErrors outputs the nodes on which the prefetch could not be started,
+// and returns the exit code.
+func (c *JobPrefetchCommand) outputErrors(errs map[string]string, length int) int {
+	if len(errs) == 0 {
+		return 0
+	}
+
+	nodeIDs := make([]string, 0, len(errs))
+	for nodeID := range errs {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	for _, nodeID := range nodeIDs {
+		c.Ui.Error(fmt.Sprintf("Error prefetching on node %q: %s", limit(nodeID, length), errs[nodeID]))
+	}
+	return 2
+}
+
+// formatNodePrefetches formats the progress of the prefetch on each node.
+func formatNodePrefetches(nodes []*api.NodePrefetch, length int) string {
+	rows := make([]string, 0, len(nodes)+1)
+	rows = append(rows, "Node ID|Status|Images|Artifacts|Duration")
+	for _, node := range nodes {
+		duration := "-"
+		if node.EndTime != 0 {
+			duration = time.Duration(node.EndTime - node.StartTime).Round(time.Second).String()
+		}
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%s",
+			limit(node.NodeID, length),
+			node.Status,
+			formatPrefetchItems(node.Images),
+			formatPrefetchItems(node.Artifacts),
+			duration,
+		))
+	}
+	return formatList(rows)
+}
+
+// formatPrefetchItems formats the number of images or artifacts fetched, with
+// the number skipped if any.
+func formatPrefetchItems(items []*api.NodePrefetchItem) string {
+	var complete, skipped int
+	for _, item := range items {
+		switch item.Status {
+		case api.NodePrefetchStatusComplete:
+			complete++
+		case api.NodePrefetchStatusSkipped:
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		return fmt.Sprintf("%d/%d (%d skipped)", complete, len(items), skipped)
+	}
+	return fmt.Sprintf("%d/%d", complete, len(items))
+}
+
+// formatPrefetchFailures formats the images and artifacts which failed to be
+// fetched, or returns an empty string if none failed.
+func formatPrefetchFailures(nodes []*api.NodePrefetch, length int) string {
+	rows := []string{"Node ID|Source|Error"}
+	for _, node := range nodes {
+		for _, item := range append(slices.Clone(node.Images), node.Artifacts...) {
+			if item.Status == api.NodePrefetchStatusFailed {
+				rows = append(rows, fmt.Sprintf("%s|%s|%s",
+					limit(node.NodeID, length), item.Source, item.Error))
+			}
+		}
+	}
+	if len(rows) == 1 {
+		return ""
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobPrefetchCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobPrefetchCommand{}
+}
+
+func TestJobPrefetchCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobPrefetchCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "example"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error querying job prefix")
+}
+
+func TestJobPrefetchCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+	waitForNodes(t, client)
+
+	// Jobs are upserted so they are not placed
+	state := srv.Agent.Server().State()
+
+	// Jobs without images or artifacts cannot be prefetched
+	job := mock.Job()
+	job.ID = "job1"
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	ui := cli.NewMockUi()
+	cmd := &JobPrefetchCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code := cmd.Run([]string{"-address=" + url, "job1"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "no container images or artifacts")
+
+	// Artifacts are skipped as the agent has no artifact cache
+	job = mock.Job()
+	job.ID = "job2"
+	job.TaskGroups[0].Tasks[0].Artifacts = []*structs.TaskArtifact{{
+		GetterSource: "https://example.com/file.txt",
+	}}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, job))
+
+	ui = cli.NewMockUi()
+	cmd = &JobPrefetchCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	code = cmd.Run([]string{"-address=" + url, "job2"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `Prefetching job "job2" on 1 node(s)`)
+	must.StrContains(t, out, "Prefetch Status")
+	must.StrContains(t, out, "0/1 (1 skipped)")
+}
@@ -5,10 +5,12 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/cap/util"
 	"github.com/mitchellh/cli"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/lib/auth/oidc"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // Ensure LoginCommand satisfies the cli.Command interface.
@@ -28,7 +31,9 @@ type LoginCommand struct {
 	authMethodType string // deprecated in 1.5.2, left for backwards compat
 	authMethodName string
 	callbackAddr   string
+	oidcDevice     bool
 	loginToken     string
+	username       string
 
 	template string
 	json     bool
@@ -42,6 +47,10 @@ Usage: nomad login [options]
   The login command will exchange the provided third party credentials with the
   requested auth method for a newly minted Nomad ACL token.
 
+  Logging in with a CERT auth method exchanges the TLS client certificate
+  given with the -client-cert and -client-key flags, and does not need a
+  login token.
+
 General Options:
 
   ` + generalOptionsUsage(usageOptsNoNamespace) + `
@@ -56,9 +65,20 @@ Login Options:
     The address to use for the local OIDC callback server. This should be given
     in the form of <IP>:<PORT> and defaults to "localhost:4649".
 
+  -oidc-device
+    Login with the OIDC device authorization flow instead of opening a browser
+    and starting a local callback server. The command prints a verification URL
+    and user code to enter on any device, and waits for the login to complete.
+    This is useful when logging in over SSH or from hosts without a browser.
+
   -login-token
     Login token used for authentication that will be exchanged for a Nomad ACL
-    Token. It is only required if using auth method type other than OIDC. 
+    Token. It is only required if using the JWT auth method type.
+
+  -username
+    Username used for authentication with the LDAP auth method type. If not
+    provided, the username will be prompted for. The password is always
+    prompted for.
 
   -json
     Output the ACL token in JSON format.
@@ -79,7 +99,9 @@ func (l *LoginCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-method":             complete.PredictAnything,
 			"-oidc-callback-addr": complete.PredictAnything,
+			"-oidc-device":        complete.PredictNothing,
 			"-login-token":        complete.PredictAnything,
+			"-username":           complete.PredictAnything,
 			"-json":               complete.PredictNothing,
 			"-t":                  complete.PredictAnything,
 		})
@@ -96,7 +118,9 @@ func (l *LoginCommand) Run(args []string) int {
 	flags.StringVar(&l.authMethodName, "method", "", "")
 	flags.StringVar(&l.authMethodType, "type", "", "")
 	flags.StringVar(&l.loginToken, "login-token", "", "")
+	flags.StringVar(&l.username, "username", "", "")
 	flags.StringVar(&l.callbackAddr, "oidc-callback-addr", "localhost:4649", "")
+	flags.BoolVar(&l.oidcDevice, "oidc-device", false, "")
 	flags.BoolVar(&l.json, "json", false, "")
 	flags.StringVar(&l.template, "t", "", "")
 	if err := flags.Parse(args); err != nil {
@@ -164,8 +188,11 @@ func (l *LoginCommand) Run(args []string) int {
 		}
 	}
 
-	// Make sure we got the login token if we're not using OIDC
-	if methodType != api.ACLAuthMethodTypeOIDC && l.loginToken == "" {
+	// Make sure we got the login token if we're not using OIDC, LDAP or cert
+	// auth methods, which don't exchange a login token.
+	if methodType != api.ACLAuthMethodTypeOIDC &&
+		methodType != api.ACLAuthMethodTypeLDAP &&
+		methodType != api.ACLAuthMethodTypeCert && l.loginToken == "" {
 		l.Ui.Error("You need to provide a login token.")
 		return 1
 	}
@@ -178,8 +205,15 @@ func (l *LoginCommand) Run(args []string) int {
 	switch methodType {
 	case api.ACLAuthMethodTypeOIDC:
 		authFn = l.loginOIDC
+		if l.oidcDevice {
+			authFn = l.loginOIDCDevice
+		}
 	case api.ACLAuthMethodTypeJWT:
 		authFn = l.loginJWT
+	case api.ACLAuthMethodTypeLDAP:
+		authFn = l.loginLDAP
+	case api.ACLAuthMethodTypeCert:
+		authFn = l.loginCert
 	default:
 		l.Ui.Error(fmt.Sprintf("Unsupported authentication type %q", methodType))
 		return 1
@@ -221,6 +255,7 @@ func (l *LoginCommand) loginOIDC(ctx context.Context, client *api.Client) (*api.
 		AuthMethodName: l.authMethodName,
 		RedirectURI:    callbackServer.RedirectURI(),
 		ClientNonce:    callbackServer.Nonce(),
+		CodeChallenge:  callbackServer.CodeChallenge(),
 	}
 
 	getAuthURLResp, _, err := client.ACLAuth().GetAuthURL(&getAuthArgs, nil)
@@ -256,12 +291,67 @@ func (l *LoginCommand) loginOIDC(ctx context.Context, client *api.Client) (*api.
 		ClientNonce:    callbackServer.Nonce(),
 		Code:           req.Code,
 		State:          req.State,
+		CodeVerifier:   callbackServer.CodeVerifier(),
 	}
 
 	token, _, err := client.ACLAuth().CompleteAuth(&cbArgs, nil)
 	return token, err
 }
 
+func (l *LoginCommand) loginOIDCDevice(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
+
+	deviceAuth, _, err := client.ACLAuth().DeviceAuth(&api.ACLOIDCDeviceAuthRequest{
+		AuthMethodName: l.authMethodName,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// We purposely use fmt here and NOT c.ui because the ui will truncate
+	// our URL (a known bug).
+	fmt.Printf(strings.TrimSpace(oidcDeviceVisitURLMsg)+"\n\n",
+		deviceAuth.VerificationURI, deviceAuth.UserCode)
+	if deviceAuth.VerificationURIComplete != "" {
+		fmt.Printf(strings.TrimSpace(oidcDeviceVisitCompleteURLMsg)+"\n\n",
+			deviceAuth.VerificationURIComplete)
+	}
+
+	if !deviceAuth.Expiry.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deviceAuth.Expiry)
+		defer cancel()
+	}
+
+	// Poll for the completion of the authorization, backing off whenever the
+	// provider asks us to slow down.
+	interval := deviceAuth.Interval
+	completeArgs := api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: l.authMethodName,
+		DeviceCode:     deviceAuth.DeviceCode,
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, errors.New("device authorization expired before the login was completed")
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		token, _, err := client.ACLAuth().CompleteAuth(&completeArgs, nil)
+		switch {
+		case structs.IsErrOIDCDeviceAuthPending(err):
+		case structs.IsErrOIDCDeviceAuthSlowDown(err):
+			interval += 5 * time.Second
+		case err != nil:
+			return nil, err
+		default:
+			return token, nil
+		}
+	}
+}
+
 func (l *LoginCommand) loginJWT(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
 	authArgs := api.ACLLoginRequest{
 		AuthMethodName: l.authMethodName,
@@ -271,6 +361,44 @@ func (l *LoginCommand) loginJWT(ctx context.Context, client *api.Client) (*api.A
 	return token, err
 }
 
+func (l *LoginCommand) loginLDAP(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
+	username := l.username
+	if username == "" {
+		var err error
+		username, err = l.Ui.Ask("Username:")
+		if err != nil {
+			return nil, err
+		}
+		if username == "" {
+			return nil, errors.New("username cannot be empty")
+		}
+	}
+
+	password, err := l.Ui.AskSecret("Password:")
+	if err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return nil, errors.New("password cannot be empty")
+	}
+
+	authArgs := api.ACLLoginRequest{
+		AuthMethodName: l.authMethodName,
+		Username:       username,
+		Password:       password,
+	}
+	token, _, err := client.ACLAuth().Login(&authArgs, nil)
+	return token, err
+}
+
+// loginCert exchanges the TLS client certificate of the API client, which the
+// agent passes along from the HTTPS connection.
+func (l *LoginCommand) loginCert(_ context.Context, client *api.Client) (*api.ACLToken, error) {
+	authArgs := api.ACLLoginRequest{AuthMethodName: l.authMethodName}
+	token, _, err := client.ACLAuth().Login(&authArgs, nil)
+	return token, err
+}
+
 const (
 	// oidcErrorVisitURLMsg is a message to show users when opening the OIDC
 	// provider URL automatically fails. This type of message is otherwise not
@@ -279,6 +407,24 @@ const (
 Automatic opening of the OIDC provider for login has failed. To complete the
 authentication, please visit your provider using the URL below:
 
+%s
+`
+
+	// oidcDeviceVisitURLMsg is the message shown to users logging in with the
+	// OIDC device authorization flow.
+	oidcDeviceVisitURLMsg = `
+To complete the authentication, please visit your provider using the URL below
+on any device and enter the code %[2]s:
+
+%[1]s
+`
+
+	// oidcDeviceVisitCompleteURLMsg is the message shown to users logging in
+	// with the OIDC device authorization flow, when the provider returns a URL
+	// which includes the code.
+	oidcDeviceVisitCompleteURLMsg = `
+Alternatively, visit the URL below which already includes the code:
+
 %s
 `
 )
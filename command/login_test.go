@@ -4,6 +4,7 @@
 package command
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
@@ -74,6 +75,26 @@ func TestLoginCommand_Run(t *testing.T) {
 	ui.OutputWriter.Reset()
 	ui.ErrorWriter.Reset()
 
+	// Store an LDAP auth method, which prompts for credentials instead of
+	// requiring a login token.
+	ldapMethod := &structs.ACLAuthMethod{
+		Name: "test-ldap-auth-method",
+		Type: "LDAP",
+		Config: &structs.ACLAuthMethodConfig{
+			LDAPURLs:   []string{"ldap://127.0.0.1:389"},
+			LDAPUserDN: "ou=people,dc=example,dc=org",
+		},
+	}
+	ldapMethod.SetHash()
+	must.NoError(t, state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{ldapMethod}))
+
+	// Try logging in with an empty password (expected error)
+	ui.InputReader = strings.NewReader("\n")
+	must.Eq(t, 1, cmd.Run([]string{"-address=" + agentURL, "-method", ldapMethod.Name, "-username", "alice"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "password cannot be empty")
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
 	// TODO(jrasell) find a way to test the full login flow from the CLI
 	//  perspective.
 }
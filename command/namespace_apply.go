@@ -229,6 +229,7 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 	delete(m, "node_pool_config")
 	delete(m, "vault")
 	delete(m, "consul")
+	delete(m, "variables")
 
 	// Decode the rest
 	if err := mapstructure.WeakDecode(m, result); err != nil {
@@ -299,6 +300,22 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 		}
 	}
 
+	varObj := list.Filter("variables")
+	if len(varObj.Items) > 0 {
+		for _, o := range varObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			var varConfig *api.NamespaceVariablesConfiguration
+			if err := hcl.DecodeObject(&varConfig, ot.List); err != nil {
+				return err
+			}
+			result.VariablesConfiguration = varConfig
+			break
+		}
+	}
+
 	if metaO := list.Filter("meta"); len(metaO.Items) > 0 {
 		for _, o := range metaO.Elem().Items {
 			var m map[string]interface{}
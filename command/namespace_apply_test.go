@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/mitchellh/cli"
 	"github.com/shoenig/test/must"
 )
@@ -97,6 +98,10 @@ consul {
   allowed = ["prod", "apps*"]
 }
 
+variables {
+  max_versions = 3
+}
+
 meta {
   dept = "eng"
 }`,
@@ -120,6 +125,9 @@ meta {
 					Default: "prod",
 					Allowed: []string{"prod", "apps*"},
 				},
+				VariablesConfiguration: &api.NamespaceVariablesConfiguration{
+					MaxVersions: pointer.Of(3),
+				},
 				Meta: map[string]string{
 					"dept": "eng",
 				},
@@ -194,6 +194,13 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatKV(cConfigOut))
 	}
 
+	if ns.VariablesConfiguration != nil && ns.VariablesConfiguration.MaxVersions != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Variables Configuration[reset]"))
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("Max Versions|%d", *ns.VariablesConfiguration.MaxVersions),
+		}))
+	}
+
 	return 0
 }
 
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type NodeArtifactCacheCommand struct {
+	Meta
+}
+
+func (c *NodeArtifactCacheCommand) Help() string {
+	helpText := `
+Usage: nomad node artifact-cache [subcommand]
+
+  Interact with a node's artifact cache. When the artifact cache is enabled
+  on a client, artifacts downloaded over HTTP are shared by every task on the
+  client. The inspect subcommand lists the cached artifacts and the purge
+  subcommand removes them. All commands interact directly with a client and
+  allow setting a custom target with the -node-id option.
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeArtifactCacheCommand) Synopsis() string {
+	return "Interact with the node artifact cache"
+}
+
+func (c *NodeArtifactCacheCommand) Name() string { return "node artifact-cache" }
+
+func (c *NodeArtifactCacheCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodeArtifactCacheInspectCommand struct {
+	Meta
+}
+
+func (c *NodeArtifactCacheInspectCommand) Help() string {
+	helpText := `
+Usage: nomad node artifact-cache inspect [options]
+
+  List the artifacts in a node's artifact cache, most recently used first.
+  This command only works on client agents with the artifact cache enabled.
+
+  When ACLs are enabled, this command requires a token with the 'node:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Artifact Cache Inspect Options:
+
+  -node-id
+    Inspects the artifact cache of the specified node. If not specified the
+    node receiving the request will be used by default.
+
+  -verbose
+    Display full artifact keys.
+
+  -json
+    Output the artifact cache in its JSON format.
+
+  -t
+    Format and display the artifact cache using a Go template.
+
+    Example:
+      $ nomad node artifact-cache inspect -node-id 3b58b0a6
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeArtifactCacheInspectCommand) Synopsis() string {
+	return "List the artifacts in the node artifact cache"
+}
+
+func (c *NodeArtifactCacheInspectCommand) Name() string { return "node artifact-cache inspect" }
+
+func (c *NodeArtifactCacheInspectCommand) Run(args []string) int {
+	var nodeID, tmpl string
+	var verbose, json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Lookup nodeID
+	if nodeID != "" {
+		nodeID, err = lookupNodeID(client.Nodes(), nodeID)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	cache, err := client.Nodes().ArtifactCache().List(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading artifact cache: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, cache)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Size|%s", humanize.IBytes(uint64(cache.Size))),
+		fmt.Sprintf("Max Size|%s", humanize.IBytes(uint64(cache.MaxSize))),
+		fmt.Sprintf("Artifacts|%d", len(cache.Entries)),
+	}))
+
+	if len(cache.Entries) == 0 {
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Artifacts[reset]"))
+	c.Ui.Output(formatArtifactCacheEntries(cache.Entries, verbose))
+	return 0
+}
+
+func formatArtifactCacheEntries(entries []*api.ArtifactCacheEntry, verbose bool) string {
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	now := time.Now()
+	rows := make([]string, len(entries)+1)
+	rows[0] = "Key|Source|Size|References|Last Used"
+	for i, entry := range entries {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%d|%s",
+			limit(entry.Key, length),
+			entry.Source,
+			humanize.IBytes(uint64(entry.Size)),
+			entry.References,
+			prettyTimeDiff(time.Unix(0, entry.LastUseTime), now),
+		)
+	}
+	return formatList(rows)
+}
+
+func (c *NodeArtifactCacheInspectCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeArtifactCacheInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodeArtifactCachePurgeCommand struct {
+	Meta
+}
+
+func (c *NodeArtifactCachePurgeCommand) Help() string {
+	helpText := `
+Usage: nomad node artifact-cache purge [options] [<key>...]
+
+  Remove artifacts from a node's artifact cache. Artifacts are identified by
+  their key, or a prefix of it, as listed by the "node artifact-cache inspect"
+  command. Artifacts in use by tasks are not removed. This command only works
+  on client agents with the artifact cache enabled.
+
+  When ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Artifact Cache Purge Options:
+
+  -node-id
+    Purges the artifact cache of the specified node. If not specified the
+    node receiving the request will be used by default.
+
+  -all
+    Remove every artifact not in use from the artifact cache. Cannot be used
+    with artifact keys.
+
+    Example:
+      $ nomad node artifact-cache purge -node-id 3b58b0a6 -all
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeArtifactCachePurgeCommand) Synopsis() string {
+	return "Remove artifacts from the node artifact cache"
+}
+
+func (c *NodeArtifactCachePurgeCommand) Name() string { return "node artifact-cache purge" }
+
+func (c *NodeArtifactCachePurgeCommand) Run(args []string) int {
+	var nodeID string
+	var all bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.BoolVar(&all, "all", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	prefixes := flags.Args()
+	if all == (len(prefixes) != 0) {
+		c.Ui.Error("This command takes either the -all flag or artifact keys")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Lookup nodeID
+	if nodeID != "" {
+		nodeID, err = lookupNodeID(client.Nodes(), nodeID)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	cache := client.Nodes().ArtifactCache()
+
+	// Resolve the key prefixes against the cached artifacts
+	var keys []string
+	if !all {
+		list, err := cache.List(nodeID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading artifact cache: %s", err))
+			return 1
+		}
+
+		for _, prefix := range prefixes {
+			key, err := lookupArtifactCacheKey(list.Entries, prefix)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	resp, err := cache.Purge(&api.ArtifactCachePurgeRequest{
+		NodeID: nodeID,
+		Keys:   keys,
+	}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error purging artifact cache: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Purged %d artifact(s) from the artifact cache", len(resp.Purged)))
+	for _, key := range resp.InUse {
+		c.Ui.Warn(fmt.Sprintf("Artifact %q is in use and was not purged", limit(key, shortId)))
+	}
+	for _, key := range resp.NotFound {
+		c.Ui.Warn(fmt.Sprintf("Artifact %q was no longer cached", limit(key, shortId)))
+	}
+	return 0
+}
+
+// lookupArtifactCacheKey returns the key of the only cached artifact whose key
+// starts with prefix.
+func lookupArtifactCacheKey(entries []*api.ArtifactCacheEntry, prefix string) (string, error) {
+	var matches []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Key, prefix) {
+			matches = append(matches, entry.Key)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("No cached artifact with key prefix %q found", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple artifacts: %s", prefix, strings.Join(matches, ", "))
+	}
+}
+
+func (c *NodeArtifactCachePurgeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-all":     complete.PredictNothing,
+		})
+}
+
+func (c *NodeArtifactCachePurgeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
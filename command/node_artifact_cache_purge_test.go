@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestNodeArtifactCachePurgeCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodeArtifactCachePurgeCommand{}
+}
+
+func TestNodeArtifactCachePurgeCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &NodeArtifactCachePurgeCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without keys or -all
+	code := cmd.Run(nil)
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "either the -all flag or artifact keys")
+	ui.ErrorWriter.Reset()
+
+	// Fails with both keys and -all
+	code = cmd.Run([]string{"-all", "abcd"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "either the -all flag or artifact keys")
+}
+
+func TestNodeArtifactCache_lookupArtifactCacheKey(t *testing.T) {
+	ci.Parallel(t)
+
+	entries := []*api.ArtifactCacheEntry{
+		{Key: "abcd1234"},
+		{Key: "abcd5678"},
+		{Key: "ef901234"},
+	}
+
+	key, err := lookupArtifactCacheKey(entries, "ef")
+	must.NoError(t, err)
+	must.Eq(t, "ef901234", key)
+
+	key, err = lookupArtifactCacheKey(entries, "abcd5678")
+	must.NoError(t, err)
+	must.Eq(t, "abcd5678", key)
+
+	_, err = lookupArtifactCacheKey(entries, "abcd")
+	must.ErrorContains(t, err, "matched multiple artifacts")
+
+	_, err = lookupArtifactCacheKey(entries, "00")
+	must.ErrorContains(t, err, "No cached artifact")
+}
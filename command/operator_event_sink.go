@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// OperatorEventSinkCommand is a Command implementation that groups the
+// commands used to manage event sinks.
+type OperatorEventSinkCommand struct {
+	Meta
+}
+
+func (c *OperatorEventSinkCommand) Help() string {
+	helpText := `
+Usage: nomad operator event-sink <subcommand> [options] [args]
+
+  This command groups subcommands for managing event sinks. Event sinks are
+  destinations the leader delivers the event stream to. The progress of every
+  sink is stored in raft so delivery resumes after a leader election, and
+  events are delivered at least once.
+
+  If ACLs are enabled, listing event sinks requires a token with the
+  'operator:read' capability and all other subcommands require a management
+  token.
+
+  Create or update an event sink:
+
+      $ nomad operator event-sink apply sink.nomad.hcl
+
+  List all event sinks:
+
+      $ nomad operator event-sink list
+
+  Show the configuration of an event sink:
+
+      $ nomad operator event-sink info <sink ID>
+
+  Delete an event sink:
+
+      $ nomad operator event-sink delete <sink ID>
+
+  Please see individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorEventSinkCommand) Synopsis() string {
+	return "Manage event sinks"
+}
+
+func (c *OperatorEventSinkCommand) Name() string {
+	return "operator event-sink"
+}
+
+func (c *OperatorEventSinkCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorEventSinkCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorEventSinkCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// formatEventSinkTopics returns the topics of an event sink in the format
+// used by the topic parameter of the event stream API.
+func formatEventSinkTopics(topics map[api.Topic][]string) string {
+	var out []string
+	for topic, keys := range topics {
+		for _, key := range keys {
+			out = append(out, fmt.Sprintf("%s:%s", topic, key))
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
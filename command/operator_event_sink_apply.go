@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorEventSinkApplyCommand struct {
+	Meta
+}
+
+func (c *OperatorEventSinkApplyCommand) Help() string {
+	helpText := `
+Usage: nomad operator event-sink apply [options] <input>
+
+  Apply is used to create or update an event sink. The specification file is
+  read from stdin by specifying "-", otherwise a path to the file is expected.
+
+  Updating the topics or namespace of an existing sink restarts its delivery
+  at the current index.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Apply Options:
+
+  -json
+    Parse the input as a JSON event sink specification.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorEventSinkApplyCommand) Synopsis() string {
+	return "Create or update an event sink"
+}
+
+func (c *OperatorEventSinkApplyCommand) Name() string {
+	return "operator event-sink apply"
+}
+
+func (c *OperatorEventSinkApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorEventSinkApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.hcl"),
+		complete.PredictFiles("*.json"),
+	)
+}
+
+func (c *OperatorEventSinkApplyCommand) Run(args []string) int {
+	var jsonInput bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&jsonInput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <input>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Read input content.
+	path := args[0]
+	var content []byte
+	var err error
+	switch path {
+	case "-":
+		content, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read stdin: %v", err))
+			return 1
+		}
+		// Set .hcl extension so the decoder doesn't fail.
+		if !jsonInput {
+			path = "stdin.nomad.hcl"
+		}
+	default:
+		content, err = os.ReadFile(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read file %q: %v", path, err))
+			return 1
+		}
+	}
+
+	// Parse input.
+	sink, err := parseEventSinkSpec(path, content, jsonInput)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse input content: %v", err))
+		return 1
+	}
+
+	// Make API request.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.EventSinks().Register(sink, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying event sink: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully applied event sink %q!", sink.ID))
+	return 0
+}
+
+// eventSinkSpec is the HCL specification of an event sink.
+type eventSinkSpec struct {
+	Sink *struct {
+		ID            string              `hcl:"id,label"`
+		Type          string              `hcl:"type"`
+		Topics        map[string][]string `hcl:"topics,optional"`
+		Namespace     string              `hcl:"namespace,optional"`
+		Address       string              `hcl:"address,optional"`
+		Headers       map[string]string   `hcl:"headers,optional"`
+		Path          string              `hcl:"path,optional"`
+		Command       string              `hcl:"command,optional"`
+		Args          []string            `hcl:"args,optional"`
+		BatchSize     int                 `hcl:"batch_size,optional"`
+		BatchInterval string              `hcl:"batch_interval,optional"`
+	} `hcl:"event_sink,block"`
+}
+
+// parseEventSinkSpec parses an HCL or JSON event sink specification.
+func parseEventSinkSpec(path string, content []byte, jsonInput bool) (*api.EventSink, error) {
+	if jsonInput {
+		var sink api.EventSink
+		if err := json.Unmarshal(content, &sink); err != nil {
+			return nil, err
+		}
+		return &sink, nil
+	}
+
+	var spec eventSinkSpec
+	if err := hclsimple.Decode(path, content, nil, &spec); err != nil {
+		return nil, err
+	}
+
+	s := spec.Sink
+	sink := &api.EventSink{
+		ID:        s.ID,
+		Type:      s.Type,
+		Namespace: s.Namespace,
+		Address:   s.Address,
+		Headers:   s.Headers,
+		Path:      s.Path,
+		Command:   s.Command,
+		Args:      s.Args,
+		BatchSize: s.BatchSize,
+	}
+	if len(s.Topics) > 0 {
+		sink.Topics = make(map[api.Topic][]string, len(s.Topics))
+		for topic, keys := range s.Topics {
+			sink.Topics[api.Topic(topic)] = keys
+		}
+	}
+	if s.BatchInterval != "" {
+		d, err := time.ParseDuration(s.BatchInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch_interval: %w", err)
+		}
+		sink.BatchInterval = d
+	}
+	return sink, nil
+}
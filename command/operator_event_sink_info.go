@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorEventSinkInfoCommand struct {
+	Meta
+}
+
+func (c *OperatorEventSinkInfoCommand) Help() string {
+	helpText := `
+Usage: nomad operator event-sink info [options] <sink ID>
+
+  Info is used to show the configuration and delivery progress of an event
+  sink.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Info Options:
+
+  -json
+    Output the event sink in JSON format.
+
+  -t
+    Format and display the event sink using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorEventSinkInfoCommand) Synopsis() string {
+	return "Show the configuration of an event sink"
+}
+
+func (c *OperatorEventSinkInfoCommand) Name() string {
+	return "operator event-sink info"
+}
+
+func (c *OperatorEventSinkInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *OperatorEventSinkInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorEventSinkInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <sink ID>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	sink, _, err := client.EventSinks().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading event sink: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, sink)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV(formatEventSink(sink)))
+	return 0
+}
+
+func formatEventSink(sink *api.EventSink) []string {
+	out := []string{
+		fmt.Sprintf("ID|%s", sink.ID),
+		fmt.Sprintf("Type|%s", sink.Type),
+		fmt.Sprintf("Namespace|%s", sink.Namespace),
+		fmt.Sprintf("Topics|%s", formatEventSinkTopics(sink.Topics)),
+	}
+
+	switch sink.Type {
+	case api.EventSinkWebhook:
+		out = append(out, fmt.Sprintf("Address|%s", sink.Address))
+	case api.EventSinkFile:
+		out = append(out, fmt.Sprintf("Path|%s", sink.Path))
+	case api.EventSinkExec:
+		out = append(out, fmt.Sprintf("Command|%s", strings.Join(append([]string{sink.Command}, sink.Args...), " ")))
+	}
+
+	out = append(out,
+		fmt.Sprintf("Batch Size|%d", sink.BatchSize),
+		fmt.Sprintf("Batch Interval|%s", sink.BatchInterval),
+		fmt.Sprintf("Latest Index|%d", sink.LatestIndex),
+		fmt.Sprintf("Gaps|%d", sink.Gaps),
+	)
+
+	if gap := sink.LastGap; gap != nil {
+		out = append(out, fmt.Sprintf("Last Gap|indexes %d to %d at %s",
+			gap.FromIndex, gap.ToIndex, formatTime(gap.Time)))
+	}
+
+	return out
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorEventSinkCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorEventSinkCommand{}
+	var _ cli.Command = &OperatorEventSinkApplyCommand{}
+	var _ cli.Command = &OperatorEventSinkDeleteCommand{}
+	var _ cli.Command = &OperatorEventSinkInfoCommand{}
+	var _ cli.Command = &OperatorEventSinkListCommand{}
+}
+
+func TestOperatorEventSink_parseEventSinkSpec(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := `
+event_sink "jobs" {
+  type    = "webhook"
+  address = "https://example.com/events"
+
+  headers = {
+    Authorization = "Bearer secret"
+  }
+
+  topics = {
+    Job        = ["*"]
+    Deployment = ["web", "api"]
+  }
+
+  batch_size     = 10
+  batch_interval = "5s"
+}
+`
+	sink, err := parseEventSinkSpec("sink.nomad.hcl", []byte(spec), false)
+	must.NoError(t, err)
+	must.Eq(t, &api.EventSink{
+		ID:      "jobs",
+		Type:    api.EventSinkWebhook,
+		Address: "https://example.com/events",
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+		Topics: map[api.Topic][]string{
+			api.TopicJob:        {"*"},
+			api.TopicDeployment: {"web", "api"},
+		},
+		BatchSize:     10,
+		BatchInterval: 5 * time.Second,
+	}, sink)
+
+	_, err = parseEventSinkSpec("sink.nomad.hcl", []byte(`event_sink "jobs" {
+  type           = "file"
+  path           = "/var/log/events.json"
+  batch_interval = "soon"
+}`), false)
+	must.ErrorContains(t, err, "invalid batch_interval")
+
+	sink, err = parseEventSinkSpec("-", []byte(`{"ID":"exec","Type":"exec","Command":"ship","Args":["-v"]}`), true)
+	must.NoError(t, err)
+	must.Eq(t, "exec", sink.ID)
+	must.Eq(t, []string{"-v"}, sink.Args)
+}
+
+func TestOperatorEventSinkCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.Server.EventSinks = &agent.EventSinksConfig{
+			AllowedDirectories: []string{"/var/log"},
+		}
+	})
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	meta := Meta{Ui: ui, flagAddress: url}
+
+	path := filepath.Join(t.TempDir(), "sink.nomad.hcl")
+	must.NoError(t, os.WriteFile(path, []byte(`event_sink "audit" {
+  type = "file"
+  path = "/var/log/nomad-events.json"
+}`), 0o600))
+
+	apply := &OperatorEventSinkApplyCommand{Meta: meta}
+	must.Zero(t, apply.Run([]string{"-address=" + url, path}))
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully applied event sink "audit"!`)
+	ui.OutputWriter.Reset()
+
+	list := &OperatorEventSinkListCommand{Meta: meta}
+	must.Zero(t, list.Run([]string{"-address=" + url}))
+	must.StrContains(t, ui.OutputWriter.String(), "audit")
+	must.StrContains(t, ui.OutputWriter.String(), "*:*")
+	ui.OutputWriter.Reset()
+
+	info := &OperatorEventSinkInfoCommand{Meta: meta}
+	must.Zero(t, info.Run([]string{"-address=" + url, "audit"}))
+	must.StrContains(t, ui.OutputWriter.String(), "/var/log/nomad-events.json")
+	must.StrContains(t, ui.OutputWriter.String(), "Gaps")
+	ui.OutputWriter.Reset()
+
+	del := &OperatorEventSinkDeleteCommand{Meta: meta}
+	must.Zero(t, del.Run([]string{"-address=" + url, "audit"}))
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully deleted event sink "audit"!`)
+	ui.OutputWriter.Reset()
+
+	must.One(t, info.Run([]string{"-address=" + url, "audit"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "not found")
+}
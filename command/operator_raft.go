@@ -4,8 +4,13 @@
 package command
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/mitchellh/cli"
 )
 
@@ -58,3 +63,31 @@ func (c *OperatorRaftCommand) Name() string { return "operator raft" }
 func (c *OperatorRaftCommand) Run(args []string) int {
 	return cli.RunResultHelp
 }
+
+// raftKeyringHelp documents the flags of the commands that read raft data
+// offline and may need to decrypt it.
+const raftKeyringHelp = `
+  -encryption-key=<key>
+    A base64 encoded key that encrypts the raft data. May be specified multiple
+    times. Only needed if the raft keyring in the data directory is wrapped by
+    a keyring provider that isn't configured with -keyring-config.
+
+  -keyring-config=<path>
+    The path to the server agent configuration file or directory, whose
+    "keyring" blocks are used to unwrap the raft keyring in the data
+    directory when raft encryption is enabled.`
+
+// loadRaftKeyring loads the keyring to decrypt the raft data in raftDir.
+func loadRaftKeyring(raftDir, keyringConfig string, keys []string) (*raftcrypt.Keyring, error) {
+	var providers []*config.KEKProviderConfig
+	if keyringConfig != "" {
+		agentConfig, err := agent.LoadConfig(keyringConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load keyring config: %w", err)
+		}
+		if agentConfig.Server != nil {
+			providers = agentConfig.Server.KEKProviders
+		}
+	}
+	return raftutil.LoadKeyring(raftDir, providers, keys)
+}
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	"github.com/hashicorp/nomad/helper/raftutil"
 	"github.com/posener/complete"
 )
@@ -36,12 +38,16 @@ Raft Logs Options:
   -pretty
     By default this command outputs newline delimited JSON. If the -pretty flag
     is passed, each entry will be pretty-printed.
-`
+` + raftKeyringHelp
 	return strings.TrimSpace(helpText)
 }
 
 func (c *OperatorRaftLogsCommand) AutocompleteFlags() complete.Flags {
-	return complete.Flags{}
+	return complete.Flags{
+		"-pretty":         complete.PredictNothing,
+		"-encryption-key": complete.PredictAnything,
+		"-keyring-config": complete.PredictFiles("*"),
+	}
 }
 
 func (c *OperatorRaftLogsCommand) AutocompleteArgs() complete.Predictor {
@@ -57,9 +63,13 @@ func (c *OperatorRaftLogsCommand) Name() string { return "operator raft logs" }
 func (c *OperatorRaftLogsCommand) Run(args []string) int {
 
 	var pretty bool
+	var keyringConfig string
+	var encryptionKeys []string
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&pretty, "pretty", false, "")
+	flagSet.StringVar(&keyringConfig, "keyring-config", "", "")
+	flagSet.Var((*flaghelper.StringFlag)(&encryptionKeys), "encryption-key", "")
 
 	if err := flagSet.Parse(args); err != nil {
 		return 1
@@ -78,12 +88,18 @@ func (c *OperatorRaftLogsCommand) Run(args []string) int {
 		return 1
 	}
 
+	keyring, err := loadRaftKeyring(filepath.Dir(raftPath), keyringConfig, encryptionKeys)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	enc := json.NewEncoder(os.Stdout)
 	if pretty {
 		enc.SetIndent("", "  ")
 	}
 
-	logChan, warningsChan, err := raftutil.LogEntries(raftPath)
+	logChan, warningsChan, err := raftutil.LogEntries(raftPath, keyring)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	"github.com/hashicorp/nomad/helper/raftutil"
 	"github.com/posener/complete"
 )
@@ -37,13 +38,15 @@ Options:
     Set the last log index to be applied, to drop spurious log entries not
     properly committed. If passed last_index is zero or negative, it's perceived
     as an offset from the last index seen in raft.
-`
+` + raftKeyringHelp
 	return strings.TrimSpace(helpText)
 }
 
 func (c *OperatorRaftStateCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		"-last-index": complete.PredictNothing,
+		"-last-index":     complete.PredictNothing,
+		"-encryption-key": complete.PredictAnything,
+		"-keyring-config": complete.PredictFiles("*"),
 	}
 }
 
@@ -59,10 +62,14 @@ func (c *OperatorRaftStateCommand) Name() string { return "operator raft state"
 
 func (c *OperatorRaftStateCommand) Run(args []string) int {
 	var fLastIdx int64
+	var keyringConfig string
+	var encryptionKeys []string
 
 	flags := c.Meta.FlagSet(c.Name(), 0)
 	flags.Usage = func() { fmt.Println(c.Help()) }
 	flags.Int64Var(&fLastIdx, "last-index", 0, "")
+	flags.StringVar(&keyringConfig, "keyring-config", "", "")
+	flags.Var((*flaghelper.StringFlag)(&encryptionKeys), "encryption-key", "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
@@ -84,7 +91,13 @@ func (c *OperatorRaftStateCommand) Run(args []string) int {
 		return 1
 	}
 
-	fsm, err := raftutil.NewFSM(raftPath)
+	keyring, err := loadRaftKeyring(raftPath, keyringConfig, encryptionKeys)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	fsm, err := raftutil.NewFSM(raftPath, keyring)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -60,6 +60,26 @@ Usage: nomad var <subcommand> [options] [args]
 
       $ nomad var purge <path>
 
+  List the versions of a variable:
+
+      $ nomad var history <path>
+
+  Restore a previous version of a variable:
+
+      $ nomad var rollback <path> <version>
+
+  Export variables to a sealed bundle:
+
+      $ nomad var export -recipient <public key> -out <file> [<prefix>]
+
+  Import variables from a sealed bundle:
+
+      $ nomad var import -identity <file> <file>
+
+  Reconcile variables with a directory of sealed bundles:
+
+      $ nomad var sync -identity <file> -dry-run <dir>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -104,12 +124,20 @@ func renderSVAsUiTable(sv *api.Variable, c VarUI) {
 	meta := []string{
 		fmt.Sprintf("Namespace|%s", sv.Namespace),
 		fmt.Sprintf("Path|%s", sv.Path),
+		fmt.Sprintf("Version|%d", sv.Version),
 		fmt.Sprintf("Create Time|%v", formatUnixNanoTime(sv.ModifyTime)),
 	}
 	if sv.CreateTime != sv.ModifyTime {
 		meta = append(meta, fmt.Sprintf("Modify Time|%v", time.Unix(0, sv.ModifyTime)))
 	}
 	meta = append(meta, fmt.Sprintf("Check Index|%v", sv.ModifyIndex))
+	if sv.ExpireTime != 0 {
+		expireTime := time.Unix(0, sv.ExpireTime)
+		remaining := max(time.Until(expireTime), 0).Truncate(time.Second)
+		meta = append(meta,
+			fmt.Sprintf("TTL|%v", sv.TTL),
+			fmt.Sprintf("Expire Time|%v (%v remaining)", formatTime(expireTime), remaining))
+	}
 	ui := c.GetConcurrentUI()
 	ui.Output(formatKV(meta))
 	ui.Output(c.Colorize().Color("\n[bold]Items[reset]"))
@@ -131,10 +159,15 @@ func renderAsHCL(sv *api.Variable) string {
 	const tpl = `
 namespace    = "{{.Namespace}}"
 path         = "{{.Path}}"
+version      = {{.Version}}  # Set by server
 create_index = {{.CreateIndex}}  # Set by server
 modify_index = {{.ModifyIndex}}  # Set by server; consulted for check-and-set
 create_time  = {{.CreateTime}}   # Set by server
 modify_time  = {{.ModifyTime}}   # Set by server
+{{- if .TTL}}
+ttl          = "{{.TTL}}"
+expire_time  = {{.ExpireTime}}   # Set by server
+{{- end}}
 
 items = {
 {{- $PAD := 0 -}}{{- range $k,$v := .Items}}{{if gt (len $k) $PAD}}{{$PAD = (len $k)}}{{end}}{{end -}}
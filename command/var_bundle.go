@@ -0,0 +1,528 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Variable bundles hold the items of a set of variables. The bundle is always
+// sealed: its contents are encrypted with a random file key, and the file key
+// is wrapped once for each passphrase or recipient public key that can open
+// the bundle, similar to the age file format.
+
+const (
+	varBundleVersion = 1
+
+	// varBundleExt is the file extension of variable bundles read by the
+	// "var sync" command.
+	varBundleExt = ".nvbundle"
+
+	varBundleStanzaScrypt = "scrypt"
+	varBundleStanzaX25519 = "x25519"
+
+	// varBundleRecipientPrefix and varBundleIdentityPrefix prefix the encoded
+	// public and private keys created by the "var keygen" command.
+	varBundleRecipientPrefix = "nomad-var-pub-"
+	varBundleIdentityPrefix  = "NOMAD-VAR-SECRET-KEY-"
+
+	// varBundleScryptLogN is the scrypt work factor used to derive the key
+	// from a passphrase.
+	varBundleScryptLogN = 15
+
+	varBundleX25519Info = "nomad-var-bundle-x25519"
+	varBundleAAD        = "nomad-var-bundle-v1"
+)
+
+var errVarBundleNoKey = errors.New("no passphrase or identity can open the bundle")
+
+// varBundle is the sealed form of a variable bundle as written to disk.
+type varBundle struct {
+	Version    int
+	Stanzas    []*varBundleStanza
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// varBundleStanza holds the file key wrapped for one passphrase or recipient.
+type varBundleStanza struct {
+	Type string
+
+	// Salt and LogN are the scrypt parameters of passphrase stanzas
+	Salt []byte `json:",omitempty"`
+	LogN int    `json:",omitempty"`
+
+	// EphemeralKey is the ephemeral public key of recipient stanzas
+	EphemeralKey []byte `json:",omitempty"`
+
+	Nonce      []byte
+	WrappedKey []byte
+}
+
+// varBundleContents is the plaintext content of a variable bundle.
+type varBundleContents struct {
+	// Namespace and Prefix are the scope the variables were exported from.
+	Namespace string
+	Prefix    string
+
+	Variables []*varBundleEntry
+}
+
+// varBundleEntry is a single variable of a bundle.
+type varBundleEntry struct {
+	Namespace string
+	Path      string
+	Items     api.VariableItems
+}
+
+// varBundleKeys holds the keys used to seal or open bundles.
+type varBundleKeys struct {
+	passphrase string
+	recipients [][]byte
+	identities [][]byte
+}
+
+// loadVarBundleKeys reads the passphrase and identity files and decodes the
+// recipient public keys passed to the bundle commands.
+func loadVarBundleKeys(passphraseFile string, recipients, identityFiles []string) (*varBundleKeys, error) {
+	keys := &varBundleKeys{}
+
+	if passphraseFile != "" {
+		buf, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		keys.passphrase = strings.TrimRight(string(buf), "\r\n")
+		if keys.passphrase == "" {
+			return nil, errors.New("passphrase file is empty")
+		}
+	}
+
+	for _, recipient := range recipients {
+		key, err := decodeVarBundleKey(recipient, varBundleRecipientPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+		keys.recipients = append(keys.recipients, key)
+	}
+
+	for _, identityFile := range identityFiles {
+		buf, err := os.ReadFile(identityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity file: %w", err)
+		}
+		for _, line := range strings.Split(string(buf), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, err := decodeVarBundleKey(line, varBundleIdentityPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("invalid identity in %q: %w", identityFile, err)
+			}
+			keys.identities = append(keys.identities, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// generateVarBundleIdentity returns a new encoded private key and its public
+// key for use as a bundle recipient.
+func generateVarBundleIdentity() (identity, recipient string, err error) {
+	private := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(private); err != nil {
+		return "", "", err
+	}
+	public, err := curve25519.X25519(private, curve25519.Basepoint)
+	if err != nil {
+		return "", "", err
+	}
+
+	return varBundleIdentityPrefix + base64.RawURLEncoding.EncodeToString(private),
+		varBundleRecipientPrefix + base64.RawURLEncoding.EncodeToString(public), nil
+}
+
+func decodeVarBundleKey(encoded, prefix string) ([]byte, error) {
+	if !strings.HasPrefix(encoded, prefix) {
+		return nil, fmt.Errorf("key must start with %q", prefix)
+	}
+	key, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(encoded, prefix))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != curve25519.PointSize {
+		return nil, fmt.Errorf("key must be %d bytes", curve25519.PointSize)
+	}
+	return key, nil
+}
+
+// sealVarBundle encrypts the contents of a bundle for the passphrase and
+// recipients of keys.
+func sealVarBundle(contents *varBundleContents, keys *varBundleKeys) ([]byte, error) {
+	if keys.passphrase == "" && len(keys.recipients) == 0 {
+		return nil, errors.New("a passphrase file or recipient is required to seal the bundle")
+	}
+
+	fileKey := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+
+	bundle := &varBundle{Version: varBundleVersion}
+
+	if keys.passphrase != "" {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		wrapKey, err := scrypt.Key([]byte(keys.passphrase), salt, 1<<varBundleScryptLogN, 8, 1, chacha20poly1305.KeySize)
+		if err != nil {
+			return nil, err
+		}
+		stanza := &varBundleStanza{
+			Type: varBundleStanzaScrypt,
+			Salt: salt,
+			LogN: varBundleScryptLogN,
+		}
+		if stanza.Nonce, stanza.WrappedKey, err = varBundleEncrypt(wrapKey, fileKey); err != nil {
+			return nil, err
+		}
+		bundle.Stanzas = append(bundle.Stanzas, stanza)
+	}
+
+	for _, recipient := range keys.recipients {
+		ephemeral := make([]byte, curve25519.ScalarSize)
+		if _, err := rand.Read(ephemeral); err != nil {
+			return nil, err
+		}
+		ephemeralPublic, err := curve25519.X25519(ephemeral, curve25519.Basepoint)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := curve25519.X25519(ephemeral, recipient)
+		if err != nil {
+			return nil, err
+		}
+		wrapKey, err := varBundleX25519Key(shared, ephemeralPublic, recipient)
+		if err != nil {
+			return nil, err
+		}
+		stanza := &varBundleStanza{
+			Type:         varBundleStanzaX25519,
+			EphemeralKey: ephemeralPublic,
+		}
+		if stanza.Nonce, stanza.WrappedKey, err = varBundleEncrypt(wrapKey, fileKey); err != nil {
+			return nil, err
+		}
+		bundle.Stanzas = append(bundle.Stanzas, stanza)
+	}
+
+	plaintext, err := json.Marshal(contents)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Nonce, bundle.Ciphertext, err = varBundleEncrypt(fileKey, plaintext); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+// openVarBundle decrypts a sealed bundle with the passphrase or identities of
+// keys.
+func openVarBundle(data []byte, keys *varBundleKeys) (*varBundleContents, error) {
+	var bundle varBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if bundle.Version != varBundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+
+	var fileKey []byte
+	for _, stanza := range bundle.Stanzas {
+		var wrapKey []byte
+		switch stanza.Type {
+		case varBundleStanzaScrypt:
+			if keys.passphrase == "" {
+				continue
+			}
+			// The work factor is read from the bundle, so bound it to the one
+			// used when sealing to avoid exhausting memory on crafted bundles
+			if stanza.LogN <= 0 || stanza.LogN > varBundleScryptLogN {
+				return nil, fmt.Errorf("unsupported scrypt work factor %d", stanza.LogN)
+			}
+			var err error
+			wrapKey, err = scrypt.Key([]byte(keys.passphrase), stanza.Salt, 1<<stanza.LogN, 8, 1, chacha20poly1305.KeySize)
+			if err != nil {
+				return nil, err
+			}
+			if key, err := varBundleDecrypt(wrapKey, stanza.Nonce, stanza.WrappedKey); err == nil {
+				fileKey = key
+			}
+
+		case varBundleStanzaX25519:
+			for _, identity := range keys.identities {
+				public, err := curve25519.X25519(identity, curve25519.Basepoint)
+				if err != nil {
+					return nil, err
+				}
+				shared, err := curve25519.X25519(identity, stanza.EphemeralKey)
+				if err != nil {
+					continue
+				}
+				wrapKey, err = varBundleX25519Key(shared, stanza.EphemeralKey, public)
+				if err != nil {
+					return nil, err
+				}
+				if key, err := varBundleDecrypt(wrapKey, stanza.Nonce, stanza.WrappedKey); err == nil {
+					fileKey = key
+					break
+				}
+			}
+		}
+		if fileKey != nil {
+			break
+		}
+	}
+	if fileKey == nil {
+		return nil, errVarBundleNoKey
+	}
+
+	plaintext, err := varBundleDecrypt(fileKey, bundle.Nonce, bundle.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+
+	var contents varBundleContents
+	if err := json.Unmarshal(plaintext, &contents); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle contents: %w", err)
+	}
+	return &contents, nil
+}
+
+func varBundleX25519Key(shared, ephemeralPublic, recipient []byte) ([]byte, error) {
+	salt := slices.Concat(ephemeralPublic, recipient)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(varBundleX25519Info)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func varBundleEncrypt(key, plaintext []byte) (nonce, ciphertext []byte, err error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, aead.Seal(nil, nonce, plaintext, []byte(varBundleAAD)), nil
+}
+
+func varBundleDecrypt(key, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return aead.Open(nil, nonce, ciphertext, []byte(varBundleAAD))
+}
+
+// varItemsDiff returns the keys that were added, changed, and removed between
+// two sets of variable items. Each list is sorted.
+func varItemsDiff(old, new api.VariableItems) (added, changed, removed []string) {
+	for k, v := range new {
+		oldV, ok := old[k]
+		switch {
+		case !ok:
+			added = append(added, k)
+		case oldV != v:
+			changed = append(changed, k)
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(changed)
+	slices.Sort(removed)
+	return
+}
+
+// varEntryID returns the namespace qualified path of a variable.
+func varEntryID(namespace, path string) string {
+	return namespace + "/" + path
+}
+
+const (
+	varChangeCreate = "create"
+	varChangeUpdate = "update"
+	varChangeDelete = "delete"
+)
+
+// varChange is a planned write of a bundle entry to the cluster.
+type varChange struct {
+	Kind      string
+	Namespace string
+	Path      string
+
+	// Items is the desired content of the variable, and ModifyIndex is the
+	// index it was read at, used to check-and-set the write.
+	Items       api.VariableItems
+	ModifyIndex uint64
+
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+// String returns the change as a line of diff output. Values are never
+// included, only the keys that changed.
+func (vc *varChange) String() string {
+	id := varEntryID(vc.Namespace, vc.Path)
+	switch vc.Kind {
+	case varChangeCreate:
+		return "+ " + id
+	case varChangeDelete:
+		return "- " + id
+	}
+
+	var parts []string
+	if len(vc.Added) > 0 {
+		parts = append(parts, "added: "+strings.Join(vc.Added, ", "))
+	}
+	if len(vc.Changed) > 0 {
+		parts = append(parts, "changed: "+strings.Join(vc.Changed, ", "))
+	}
+	if len(vc.Removed) > 0 {
+		parts = append(parts, "removed: "+strings.Join(vc.Removed, ", "))
+	}
+	return fmt.Sprintf("~ %s (%s)", id, strings.Join(parts, "; "))
+}
+
+// planVarEntry compares a bundle entry with the variable in the cluster and
+// returns the change needed to apply it, or nil if the variable is already up
+// to date. If replace is false, the entry items are merged into the existing
+// items instead of replacing them.
+func planVarEntry(client *api.Client, entry *varBundleEntry, replace bool) (*varChange, error) {
+	current, _, err := client.Variables().Read(entry.Path,
+		&api.QueryOptions{Namespace: entry.Namespace})
+	if err != nil {
+		if errors.Is(err, api.ErrVariablePathNotFound) {
+			return &varChange{
+				Kind:      varChangeCreate,
+				Namespace: entry.Namespace,
+				Path:      entry.Path,
+				Items:     entry.Items,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to read variable %q: %w",
+			varEntryID(entry.Namespace, entry.Path), err)
+	}
+
+	desired := entry.Items
+	if !replace {
+		desired = make(api.VariableItems, len(current.Items)+len(entry.Items))
+		maps.Copy(desired, current.Items)
+		maps.Copy(desired, entry.Items)
+	}
+
+	added, changed, removed := varItemsDiff(current.Items, desired)
+	if len(added)+len(changed)+len(removed) == 0 {
+		return nil, nil
+	}
+	return &varChange{
+		Kind:        varChangeUpdate,
+		Namespace:   entry.Namespace,
+		Path:        entry.Path,
+		Items:       desired,
+		ModifyIndex: current.ModifyIndex,
+		Added:       added,
+		Changed:     changed,
+		Removed:     removed,
+	}, nil
+}
+
+// applyVarChange writes a planned change to the cluster. Every write is
+// checked against the index the variable was read at, so concurrent changes
+// are reported as api.ErrCASConflict rather than overwritten.
+func applyVarChange(client *api.Client, vc *varChange) error {
+	wo := &api.WriteOptions{Namespace: vc.Namespace}
+
+	var err error
+	switch vc.Kind {
+	case varChangeCreate:
+		_, _, err = client.Variables().CheckedCreate(&api.Variable{
+			Namespace: vc.Namespace,
+			Path:      vc.Path,
+			Items:     vc.Items,
+		}, wo)
+	case varChangeUpdate:
+		_, _, err = client.Variables().CheckedUpdate(&api.Variable{
+			Namespace:   vc.Namespace,
+			Path:        vc.Path,
+			Items:       vc.Items,
+			ModifyIndex: vc.ModifyIndex,
+		}, wo)
+	case varChangeDelete:
+		_, err = client.Variables().CheckedDelete(vc.Path, vc.ModifyIndex, wo)
+	default:
+		err = fmt.Errorf("unknown change %q", vc.Kind)
+	}
+	return err
+}
+
+// applyVarChanges writes the planned changes and reports conflicts. All
+// changes are attempted even if some of them fail.
+func applyVarChanges(ui cli.Ui, client *api.Client, changes []*varChange) int {
+	var failed int
+	for _, vc := range changes {
+		err := applyVarChange(client, vc)
+		if err == nil {
+			continue
+		}
+		failed++
+
+		if errors.As(err, &api.ErrCASConflict{}) {
+			ui.Error(fmt.Sprintf("Conflict writing variable %q: %s",
+				varEntryID(vc.Namespace, vc.Path), err))
+			continue
+		}
+		ui.Error(fmt.Sprintf("Error writing variable %q: %s",
+			varEntryID(vc.Namespace, vc.Path), err))
+	}
+
+	if failed > 0 {
+		ui.Error(fmt.Sprintf("Failed to apply %d of %d changes", failed, len(changes)))
+		return 1
+	}
+
+	ui.Output(fmt.Sprintf("Applied %d changes", len(changes)))
+	return 0
+}
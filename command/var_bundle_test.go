@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func testVarBundleContents() *varBundleContents {
+	return &varBundleContents{
+		Namespace: "default",
+		Prefix:    "test",
+		Variables: []*varBundleEntry{{
+			Namespace: "default",
+			Path:      "test/var",
+			Items:     api.VariableItems{"keyA": "secretA"},
+		}},
+	}
+}
+
+func TestVarBundle_SealOpen(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	must.NoError(t, os.WriteFile(passphraseFile, []byte("correct horse\n"), 0o600))
+
+	identity, recipient, err := generateVarBundleIdentity()
+	must.NoError(t, err)
+	identityFile := filepath.Join(dir, "key")
+	must.NoError(t, os.WriteFile(identityFile, []byte("# comment\n"+identity+"\n"), 0o600))
+
+	sealKeys, err := loadVarBundleKeys(passphraseFile, []string{recipient}, nil)
+	must.NoError(t, err)
+	sealed, err := sealVarBundle(testVarBundleContents(), sealKeys)
+	must.NoError(t, err)
+	must.StrNotContains(t, string(sealed), "secretA")
+	must.StrNotContains(t, string(sealed), "test/var")
+
+	t.Run("passphrase", func(t *testing.T) {
+		keys, err := loadVarBundleKeys(passphraseFile, nil, nil)
+		must.NoError(t, err)
+		contents, err := openVarBundle(sealed, keys)
+		must.NoError(t, err)
+		must.Eq(t, testVarBundleContents(), contents)
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		keys, err := loadVarBundleKeys("", nil, []string{identityFile})
+		must.NoError(t, err)
+		contents, err := openVarBundle(sealed, keys)
+		must.NoError(t, err)
+		must.Eq(t, testVarBundleContents(), contents)
+	})
+
+	t.Run("wrong_keys", func(t *testing.T) {
+		other, _, err := generateVarBundleIdentity()
+		must.NoError(t, err)
+		otherFile := filepath.Join(dir, "other")
+		must.NoError(t, os.WriteFile(otherFile, []byte(other), 0o600))
+		wrongPassphrase := filepath.Join(dir, "wrong")
+		must.NoError(t, os.WriteFile(wrongPassphrase, []byte("wrong"), 0o600))
+
+		keys, err := loadVarBundleKeys(wrongPassphrase, nil, []string{otherFile})
+		must.NoError(t, err)
+		_, err = openVarBundle(sealed, keys)
+		must.ErrorIs(t, err, errVarBundleNoKey)
+	})
+
+	t.Run("work_factor", func(t *testing.T) {
+		var bundle varBundle
+		must.NoError(t, json.Unmarshal(sealed, &bundle))
+		for _, stanza := range bundle.Stanzas {
+			if stanza.Type == varBundleStanzaScrypt {
+				stanza.LogN = 22
+			}
+		}
+		crafted, err := json.Marshal(bundle)
+		must.NoError(t, err)
+
+		keys, err := loadVarBundleKeys(passphraseFile, nil, nil)
+		must.NoError(t, err)
+		_, err = openVarBundle(crafted, keys)
+		must.ErrorContains(t, err, "unsupported scrypt work factor 22")
+	})
+
+	t.Run("no_keys", func(t *testing.T) {
+		_, err := sealVarBundle(testVarBundleContents(), &varBundleKeys{})
+		must.Error(t, err)
+	})
+}
+
+func TestVarBundle_LoadKeys(t *testing.T) {
+	ci.Parallel(t)
+
+	identity, recipient, err := generateVarBundleIdentity()
+	must.NoError(t, err)
+
+	_, err = loadVarBundleKeys("", []string{identity}, nil)
+	must.ErrorContains(t, err, "invalid recipient")
+
+	_, err = loadVarBundleKeys("", []string{strings.TrimSuffix(recipient, "A") + "!"}, nil)
+	must.ErrorContains(t, err, "invalid recipient")
+
+	keys, err := loadVarBundleKeys("", []string{recipient}, nil)
+	must.NoError(t, err)
+	must.Len(t, 1, keys.recipients)
+}
+
+func TestVarBundle_ChangeString(t *testing.T) {
+	ci.Parallel(t)
+
+	added, changed, removed := varItemsDiff(
+		api.VariableItems{"a": "1", "b": "2", "c": "3"},
+		api.VariableItems{"b": "2", "c": "4", "d": "5"},
+	)
+	must.Eq(t, []string{"d"}, added)
+	must.Eq(t, []string{"c"}, changed)
+	must.Eq(t, []string{"a"}, removed)
+
+	vc := &varChange{
+		Kind:      varChangeUpdate,
+		Namespace: "default",
+		Path:      "test/var",
+		Added:     added,
+		Changed:   changed,
+		Removed:   removed,
+	}
+	must.Eq(t, "~ default/test/var (added: d; changed: c; removed: a)", vc.String())
+
+	vc.Kind = varChangeCreate
+	must.Eq(t, "+ default/test/var", vc.String())
+	vc.Kind = varChangeDelete
+	must.Eq(t, "- default/test/var", vc.String())
+}
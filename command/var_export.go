@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type VarExportCommand struct {
+	Meta
+}
+
+func (c *VarExportCommand) Help() string {
+	helpText := `
+Usage: nomad var export [options] [<prefix>]
+
+  Export is used to write the variables of a namespace to a sealed bundle. If
+  a prefix is given, only variables whose path starts with the prefix are
+  exported. The bundle is always encrypted, with a passphrase, with one or more
+  recipient public keys created by the "nomad var keygen" command, or both.
+  Bundles are restored with the "nomad var import" and "nomad var sync"
+  commands.
+
+  Use the -namespace flag with the wildcard "*" to export the variables of all
+  namespaces.
+
+  If ACLs are enabled, this command requires a token with the 'variables:list'
+  and 'variables:read' capabilities for the exported namespace and paths.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Export Options:
+
+  -out <file>
+    Write the bundle to the given file instead of standard output. The file is
+    created with permissions that only allow its owner to read it.
+
+  -passphrase-file <file>
+    Seal the bundle with the passphrase read from the given file.
+
+  -recipient <public key>
+    Seal the bundle for the given public key. This flag can be repeated to
+    seal the bundle for several recipients.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarExportCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-out":             complete.PredictFiles("*"),
+			"-passphrase-file": complete.PredictFiles("*"),
+			"-recipient":       complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarExportCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarExportCommand) Synopsis() string {
+	return "Export variables to a sealed bundle"
+}
+
+func (c *VarExportCommand) Name() string { return "var export" }
+
+func (c *VarExportCommand) Run(args []string) int {
+	var out, passphraseFile string
+	var recipients flaghelper.StringFlag
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&out, "out", "", "")
+	flags.StringVar(&passphraseFile, "passphrase-file", "", "")
+	flags.Var(&recipients, "recipient", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no more than one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes either no arguments or one: <prefix>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var prefix string
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	keys, err := loadVarBundleKeys(passphraseFile, recipients, nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if keys.passphrase == "" && len(keys.recipients) == 0 {
+		c.Ui.Error("A -passphrase-file or -recipient is required to seal the bundle")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	vars, _, err := client.Variables().PrefixList(prefix,
+		&api.QueryOptions{Namespace: c.Meta.namespace})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing variables: %s", err))
+		return 1
+	}
+
+	contents := &varBundleContents{
+		Namespace: c.Meta.namespace,
+		Prefix:    prefix,
+		Variables: make([]*varBundleEntry, 0, len(vars)),
+	}
+	for _, meta := range vars {
+		sv, _, err := client.Variables().Read(meta.Path,
+			&api.QueryOptions{Namespace: meta.Namespace})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading variable %q: %s",
+				varEntryID(meta.Namespace, meta.Path), err))
+			return 1
+		}
+		contents.Variables = append(contents.Variables, &varBundleEntry{
+			Namespace: sv.Namespace,
+			Path:      sv.Path,
+			Items:     sv.Items,
+		})
+	}
+
+	sealed, err := sealVarBundle(contents, keys)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error sealing bundle: %s", err))
+		return 1
+	}
+
+	if out == "" {
+		c.Ui.Output(string(sealed))
+		return 0
+	}
+
+	if err := os.WriteFile(out, append(sealed, '\n'), 0o600); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing bundle: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Exported %d variables to %q", len(contents.Variables), out))
+	return 0
+}
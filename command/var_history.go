@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarHistoryCommand struct {
+	outFmt string
+	tmpl   string
+	Meta
+}
+
+func (c *VarHistoryCommand) Help() string {
+	helpText := `
+Usage: nomad var history [options] <path>
+
+  History is used to list the current and previous versions of a variable,
+  with the newest version first. Previous versions are kept when a variable is
+  updated or purged, up to the limit configured for the namespace, and can be
+  restored with the "nomad var rollback" command.
+
+  If ACLs are enabled, this command requires a token with the 'variables:list'
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+History Options:
+
+  -out (go-template | json | table)
+    Format to render the versions. Defaults to "table" when stdout is a
+    terminal and "json" when the output is redirected.
+
+  -template
+    Template to render output with. Required when format is "go-template",
+    invalid for other formats.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarHistoryCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-out":      complete.PredictSet("go-template", "json", "table"),
+			"-template": complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarHistoryCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarHistoryCommand) Synopsis() string {
+	return "List the versions of a variable"
+}
+
+func (c *VarHistoryCommand) Name() string { return "var history" }
+
+func (c *VarHistoryCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&c.tmpl, "template", "", "")
+
+	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
+		flags.StringVar(&c.outFmt, "out", "table", "")
+	} else {
+		flags.StringVar(&c.outFmt, "out", "json", "")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if err := c.validateOutputFlag(); err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	path := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	qo := &api.QueryOptions{
+		Namespace: c.Meta.namespace,
+	}
+
+	versions, _, err := client.Variables().ListVersions(path, qo)
+	if err != nil {
+		if strings.Contains(err.Error(), "variable not found") {
+			c.Ui.Warn(errVariableNotFound)
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error retrieving variable versions: %s", err))
+		return 1
+	}
+
+	switch c.outFmt {
+	case "json", "go-template":
+		out, err := Format(c.outFmt == "json", c.tmpl, versions)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+	default:
+		c.Ui.Output(formatVarVersions(versions))
+	}
+	return 0
+}
+
+func formatVarVersions(versions []*api.VariableMetadata) string {
+	rows := make([]string, len(versions)+1)
+	rows[0] = "Version|Modify Index|Modify Time"
+	for i, version := range versions {
+		rows[i+1] = fmt.Sprintf("%d|%d|%s",
+			version.Version,
+			version.ModifyIndex,
+			formatUnixNanoTime(version.ModifyTime),
+		)
+	}
+	return formatList(rows)
+}
+
+func (c *VarHistoryCommand) validateOutputFlag() error {
+	if c.outFmt != "go-template" && c.tmpl != "" {
+		return errors.New(errUnexpectedTemplate)
+	}
+	switch c.outFmt {
+	case "json", "table":
+		return nil
+	case "go-template":
+		if c.tmpl == "" {
+			return errors.New(errMissingTemplate)
+		}
+		return nil
+	default:
+		return errors.New(errInvalidOutFormat)
+	}
+}
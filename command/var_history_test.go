@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarHistoryCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarHistoryCommand{}
+}
+
+func TestVarHistoryCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"some", "bad", "args"})
+		out := ui.ErrorWriter.String()
+		must.One(t, code)
+		must.StrContains(t, out, commandErrorText(cmd))
+	})
+	t.Run("bad_address", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=nope", "foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "retrieving variable versions")
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+	t.Run("unexpected_template", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-out=json", "-template=bad", "foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), errUnexpectedTemplate)
+	})
+}
+
+func TestVarHistoryCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	// Create a variable and update it to create a second version
+	sv := testVariable()
+	sv, _, err := client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+	sv.Items["keyA"] = "updated"
+	sv, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-out=json", sv.Path})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+		var versions []*api.VariableMetadata
+		must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &versions))
+		must.Len(t, 2, versions)
+		must.Eq(t, 2, versions[0].Version)
+		must.Eq(t, 1, versions[1].Version)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-out=table", sv.Path})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), "Version  Modify Index  Modify Time")
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "does/not/exist"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), errVariableNotFound)
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type VarImportCommand struct {
+	Meta
+}
+
+func (c *VarImportCommand) Help() string {
+	helpText := `
+Usage: nomad var import [options] <bundle>
+
+  Import is used to write the variables of a sealed bundle created by the
+  "nomad var export" command. If the bundle path is "-", the bundle is read
+  from stdin.
+
+  By default the items of each variable in the bundle are merged into the
+  items of the variable in the cluster: items of the bundle are added or
+  updated, and items that only exist in the cluster are kept. Every write is
+  checked against the index the variable was read at, so variables that are
+  modified while the import runs are reported as conflicts and left unchanged.
+
+  If ACLs are enabled, this command requires a token with the 'variables:read'
+  and 'variables:write' capabilities for the namespaces and paths of the
+  bundle.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Import Options:
+
+  -passphrase-file <file>
+    Open the bundle with the passphrase read from the given file.
+
+  -identity <file>
+    Open the bundle with a private key file created by the "nomad var keygen"
+    command. This flag can be repeated.
+
+  -replace
+    Replace the items of existing variables with the items of the bundle
+    instead of merging them.
+
+  -dry-run
+    Print the changes the import would make without writing any variable.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarImportCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-passphrase-file": complete.PredictFiles("*"),
+			"-identity":        complete.PredictFiles("*"),
+			"-replace":         complete.PredictNothing,
+			"-dry-run":         complete.PredictNothing,
+		},
+	)
+}
+
+func (c *VarImportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*" + varBundleExt)
+}
+
+func (c *VarImportCommand) Synopsis() string {
+	return "Import variables from a sealed bundle"
+}
+
+func (c *VarImportCommand) Name() string { return "var import" }
+
+func (c *VarImportCommand) Run(args []string) int {
+	var passphraseFile string
+	var identities flaghelper.StringFlag
+	var replace, dryRun bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&passphraseFile, "passphrase-file", "", "")
+	flags.Var(&identities, "identity", "")
+	flags.BoolVar(&replace, "replace", false, "")
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <bundle>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	keys, err := loadVarBundleKeys(passphraseFile, nil, identities)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if keys.passphrase == "" && len(keys.identities) == 0 {
+		c.Ui.Error("A -passphrase-file or -identity is required to open the bundle")
+		return 1
+	}
+
+	var data []byte
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading bundle: %s", err))
+		return 1
+	}
+
+	contents, err := openVarBundle(data, keys)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening bundle: %s", err))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var changes []*varChange
+	for _, entry := range contents.Variables {
+		vc, err := planVarEntry(client, entry, replace)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error planning import: %s", err))
+			return 1
+		}
+		if vc != nil {
+			changes = append(changes, vc)
+		}
+	}
+
+	if len(changes) == 0 {
+		c.Ui.Output("All variables are up to date")
+		return 0
+	}
+
+	for _, vc := range changes {
+		c.Ui.Output(vc.String())
+	}
+	if dryRun {
+		return 0
+	}
+
+	return applyVarChanges(c.Ui, client, changes)
+}
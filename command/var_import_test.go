@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarImportCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarImportCommand{}
+	var _ cli.Command = &VarExportCommand{}
+	var _ cli.Command = &VarKeygenCommand{}
+}
+
+func TestVarImportCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarImportCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo", "bar"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	})
+	t.Run("no_keys", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarImportCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "-passphrase-file or -identity is required")
+	})
+	t.Run("export_no_keys", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarExportCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "-passphrase-file or -recipient is required")
+	})
+}
+
+func TestVarImportCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	bundleFile := filepath.Join(dir, "vars"+varBundleExt)
+
+	// Generate a key pair
+	ui := cli.NewMockUi()
+	code := (&VarKeygenCommand{Meta: Meta{Ui: ui}}).Run([]string{"-out=" + keyFile})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	recipient := ui.OutputWriter.String()[len("Public key: "):]
+	recipient = recipient[:len(recipient)-1]
+
+	// Create a variable and export it
+	sv := testVariable()
+	sv, _, err := client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+
+	ui = cli.NewMockUi()
+	code = (&VarExportCommand{Meta: Meta{Ui: ui}}).Run([]string{
+		"-address=" + url, "-recipient=" + recipient, "-out=" + bundleFile, "test"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), "Exported 1 variables")
+
+	data, err := os.ReadFile(bundleFile)
+	must.NoError(t, err)
+	must.StrNotContains(t, string(data), "valueA")
+
+	// Change the variable in the cluster
+	sv.Items["keyA"] = "changed"
+	sv.Items["keyC"] = "valueC"
+	_, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+
+	t.Run("dry_run", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarImportCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-identity=" + keyFile, "-dry-run", bundleFile})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.Eq(t, "~ default/test/var (changed: keyA)\n", ui.OutputWriter.String())
+
+		current, _, err := client.Variables().Read(sv.Path, nil)
+		must.NoError(t, err)
+		must.Eq(t, "changed", current.Items["keyA"])
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarImportCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-identity=" + keyFile, bundleFile})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), "Applied 1 changes")
+
+		current, _, err := client.Variables().Read(sv.Path, nil)
+		must.NoError(t, err)
+		must.Eq(t, "valueA", current.Items["keyA"])
+		must.Eq(t, "valueC", current.Items["keyC"])
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarImportCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-identity=" + keyFile, "-replace", bundleFile})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), "(removed: keyC)")
+
+		current, _, err := client.Variables().Read(sv.Path, nil)
+		must.NoError(t, err)
+		must.MapNotContainsKey(t, current.Items, "keyC")
+
+		ui = cli.NewMockUi()
+		cmd = &VarImportCommand{Meta: Meta{Ui: ui}}
+		code = cmd.Run([]string{"-address=" + url, "-identity=" + keyFile, "-replace", bundleFile})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), "All variables are up to date")
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VarKeygenCommand struct {
+	Meta
+}
+
+func (c *VarKeygenCommand) Help() string {
+	helpText := `
+Usage: nomad var keygen [options]
+
+  Keygen generates a key pair for sealing variable bundles created by the
+  "nomad var export" command. The public key is passed to the export command
+  with the -recipient flag, and the private key file is passed to the import
+  and sync commands with the -identity flag.
+
+  This command doesn't contact the Nomad cluster.
+
+Keygen Options:
+
+  -out <file>
+    Write the private key to the given file instead of standard output. The
+    file is created with permissions that only allow its owner to read it, and
+    the public key is printed to standard output.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarKeygenCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-out": complete.PredictFiles("*"),
+	}
+}
+
+func (c *VarKeygenCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarKeygenCommand) Synopsis() string {
+	return "Generate a key pair for variable bundles"
+}
+
+func (c *VarKeygenCommand) Name() string { return "var keygen" }
+
+func (c *VarKeygenCommand) Run(args []string) int {
+	var out string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&out, "out", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	identity, recipient, err := generateVarBundleIdentity()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error generating key: %s", err))
+		return 1
+	}
+
+	contents := fmt.Sprintf("# public key: %s\n%s\n", recipient, identity)
+	if out == "" {
+		c.Ui.Output(strings.TrimSpace(contents))
+		return 0
+	}
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating key file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(contents); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing key file: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Public key: %s", recipient))
+	return 0
+}
@@ -21,7 +21,9 @@ func (c *VarPurgeCommand) Help() string {
 	helpText := `
 Usage: nomad var purge [options] <path>
 
-  Purge is used to permanently delete an existing variable.
+  Purge is used to delete an existing variable. The deleted variable is kept
+  in the variable's history until it is garbage collected, and can be restored
+  with the "nomad var rollback" command.
 
   If ACLs are enabled, this command requires a token with the 'variables:destroy'
   capability for the target variable's namespace and path.
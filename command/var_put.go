@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-set/v2"
@@ -98,6 +99,12 @@ Var put Options:
      Template to render output with. Required when format is "go-template",
      invalid for other formats.
 
+  -ttl
+     Duration after which the variable expires and is deleted, such as "1h".
+     The TTL restarts each time the variable is written, and a variable
+     written without a TTL doesn't expire. Overrides the TTL of a variable
+     specification. Must be at least 10s.
+
   -verbose
      Provides additional information via standard error to preserve standard
      output (stdout) for redirected output.
@@ -111,6 +118,7 @@ func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-in":  complete.PredictSet("hcl", "json"),
 			"-out": complete.PredictSet("none", "hcl", "json", "go-template", "table"),
+			"-ttl": complete.PredictAnything,
 		},
 	)
 }
@@ -128,6 +136,7 @@ func (c *VarPutCommand) Name() string { return "var put" }
 func (c *VarPutCommand) Run(args []string) int {
 	var force, enforce, doVerbose bool
 	var path, checkIndexStr string
+	var ttl time.Duration
 	var checkIndex uint64
 	var err error
 
@@ -139,6 +148,7 @@ func (c *VarPutCommand) Run(args []string) int {
 	flags.StringVar(&checkIndexStr, "check-index", "", "")
 	flags.StringVar(&c.inFmt, "in", "json", "")
 	flags.StringVar(&c.tmpl, "template", "", "")
+	flags.DurationVar(&ttl, "ttl", 0, "")
 
 	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
 		flags.StringVar(&c.outFmt, "out", "none", "")
@@ -280,6 +290,9 @@ func (c *VarPutCommand) Run(args []string) int {
 		c.Ui.Error(fmt.Sprintf("Failed to parse variable data: %s", err))
 		return 1
 	}
+	if ttl != 0 {
+		sv.TTL = ttl
+	}
 
 	var warnings *multierror.Error
 	if len(args) > 0 {
@@ -460,6 +473,9 @@ func parseVariableSpecImpl(result *api.Variable, list *ast.ObjectList) error {
 	valid := []string{
 		"namespace",
 		"path",
+		"version",
+		"ttl",
+		"expire_time",
 		"create_index",
 		"modify_index",
 		"create_time",
@@ -470,7 +486,7 @@ func parseVariableSpecImpl(result *api.Variable, list *ast.ObjectList) error {
 		return err
 	}
 
-	for _, index := range []string{"create_index", "modify_index"} {
+	for _, index := range []string{"version", "create_index", "modify_index"} {
 		if value, ok := m[index]; ok {
 			vInt, ok := value.(int)
 			if !ok {
@@ -483,7 +499,20 @@ func parseVariableSpecImpl(result *api.Variable, list *ast.ObjectList) error {
 		}
 	}
 
-	for _, index := range []string{"create_time", "modify_time"} {
+	if value, ok := m["ttl"]; ok {
+		vStr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("ttl must be a duration string; got (%T) %[1]v", value)
+		}
+		ttl, err := time.ParseDuration(vStr)
+		if err != nil {
+			return fmt.Errorf("ttl must be a duration string: %v", err)
+		}
+		m["TTL"] = ttl
+		delete(m, "ttl")
+	}
+
+	for _, index := range []string{"expire_time", "create_time", "modify_time"} {
 		if value, ok := m[index]; ok {
 			vInt, ok := value.(int)
 			if !ok {
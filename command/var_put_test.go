@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
@@ -107,6 +108,36 @@ func TestVarPutCommand_GoodJson(t *testing.T) {
 	must.Eq(t, api.VariableItems{"k1": "v1", "k2": "v2"}, outVar.Items)
 }
 
+func TestVarPutCommand_TTL(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &VarPutCommand{Meta: Meta{Ui: ui}}
+
+	// A TTL below the minimum is rejected by the server
+	code := cmd.Run([]string{"-address=" + url, "-ttl=1s", "test/var", "k1=v1"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "variable TTL must be zero or at least")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-ttl=1h", "-out=json", "test/var", "k1=v1"})
+	must.Zero(t, code)
+
+	t.Cleanup(func() {
+		_, _ = client.Variables().Delete("test/var", nil)
+	})
+
+	var outVar api.Variable
+	err := json.Unmarshal(ui.OutputWriter.Bytes(), &outVar)
+	must.NoError(t, err)
+	must.Eq(t, time.Hour, outVar.TTL)
+	must.NonZero(t, outVar.ExpireTime)
+}
+
 func TestVarPutCommand_FlagsWithSpec(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type VarRollbackCommand struct {
+	Meta
+}
+
+func (c *VarRollbackCommand) Help() string {
+	helpText := `
+Usage: nomad var rollback [options] <path> <version>
+
+  Rollback is used to restore the items of a previous version of a variable.
+  The restored items are written as a new version of the variable, so the
+  rollback itself can be undone. A purged variable can be restored as long
+  as its previous versions have not been garbage collected.
+
+  If ACLs are enabled, this command requires a token with the 'variables:read'
+  and 'variables:write' capabilities for the target variable's namespace and
+  path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Rollback Options:
+
+  -check-index
+    If set, the variable is only acted upon if the server side version's modify
+    index matches the provided value. Use 0 to only restore a purged variable.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarRollbackCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *VarRollbackCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarRollbackCommand) Synopsis() string {
+	return "Restore a previous version of a variable"
+}
+
+func (c *VarRollbackCommand) Name() string { return "var rollback" }
+
+func (c *VarRollbackCommand) Run(args []string) int {
+	var checkIndexStr string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&checkIndexStr, "check-index", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got two arguments
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error("This command takes two arguments: <path> <version>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Parse the check-index
+	checkIndex, enforce, err := parseCheckIndex(checkIndexStr)
+	if err != nil {
+		switch {
+		case errors.Is(err, strconv.ErrRange):
+			c.Ui.Error(fmt.Sprintf("Invalid -check-index value %q: out of range for uint64", checkIndexStr))
+		case errors.Is(err, strconv.ErrSyntax):
+			c.Ui.Error(fmt.Sprintf("Invalid -check-index value %q: not parsable as uint64", checkIndexStr))
+		default:
+			c.Ui.Error(fmt.Sprintf("Error parsing -check-index value %q: %v", checkIndexStr, err))
+		}
+		return 1
+	}
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	path := args[0]
+	version, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid version %q: %v", args[1], err))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	previous, _, err := client.Variables().ReadVersion(path, version,
+		&api.QueryOptions{Namespace: c.Meta.namespace})
+	if err != nil {
+		if errors.Is(err, api.ErrVariablePathNotFound) {
+			c.Ui.Error(fmt.Sprintf("Version %d of variable %q not found", version, path))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error retrieving variable version: %s", err))
+		return 1
+	}
+
+	sv := &api.Variable{
+		Namespace: c.Meta.namespace,
+		Path:      path,
+		Items:     previous.Items,
+	}
+	wo := &api.WriteOptions{Namespace: c.Meta.namespace}
+
+	if enforce {
+		sv.ModifyIndex = checkIndex
+		if checkIndex == 0 {
+			sv, _, err = client.Variables().CheckedCreate(sv, wo)
+		} else {
+			sv, _, err = client.Variables().CheckedUpdate(sv, wo)
+		}
+	} else {
+		sv, _, err = client.Variables().Create(sv, wo)
+	}
+	if err != nil {
+		if handled := handleCASError(err, c); handled {
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error restoring variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Restored version %d of variable %q as version %d",
+		version, path, sv.Version))
+	return 0
+}
+
+func (c *VarRollbackCommand) GetConcurrentUI() cli.ConcurrentUi {
+	return cli.ConcurrentUi{Ui: c.Ui}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarRollbackCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarRollbackCommand{}
+}
+
+func TestVarRollbackCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo"})
+		out := ui.ErrorWriter.String()
+		must.One(t, code)
+		must.StrContains(t, out, commandErrorText(cmd))
+	})
+	t.Run("bad_version", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo", "bar"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), `Invalid version "bar"`)
+	})
+}
+
+func TestVarRollbackCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	// Create a variable, update it and then purge it
+	sv := testVariable()
+	sv, _, err := client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+	sv.Items["keyA"] = "updated"
+	sv, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+	_, err = client.Variables().Delete(sv.Path, nil)
+	must.NoError(t, err)
+
+	t.Run("missing_version", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, sv.Path, "10"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "Version 10 of variable")
+	})
+
+	t.Run("restore_purged", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-check-index=0", sv.Path, "1"})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), "as version 3")
+
+		restored, _, err := client.Variables().Read(sv.Path, nil)
+		must.NoError(t, err)
+		must.Eq(t, "valueA", restored.Items["keyA"])
+		must.Eq(t, 3, restored.Version)
+	})
+
+	t.Run("check_index_conflict", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-check-index=1", sv.Path, "2"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "Check-and-Set conflict")
+	})
+}
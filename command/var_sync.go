@@ -0,0 +1,253 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type VarSyncCommand struct {
+	Meta
+}
+
+func (c *VarSyncCommand) Help() string {
+	helpText := `
+Usage: nomad var sync [options] <dir>
+
+  Sync is used to reconcile the variables of the cluster with a directory of
+  sealed bundles created by the "nomad var export" command. Every file with
+  the ".nvbundle" extension in the directory and its subdirectories is opened,
+  and the items of each variable in the cluster are replaced with the items of
+  the bundles. A variable may only appear in one bundle.
+
+  Sync prints the changes as a diff of variable paths and item keys. Item
+  values are never printed. Every write is checked against the index the
+  variable was read at, so variables that are modified while the sync runs are
+  reported as conflicts and left unchanged.
+
+  If ACLs are enabled, this command requires a token with the 'variables:list',
+  'variables:read' and 'variables:write' capabilities for the namespaces and
+  paths of the bundles. The -prune flag also requires the 'variables:destroy'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Sync Options:
+
+  -passphrase-file <file>
+    Open the bundles with the passphrase read from the given file.
+
+  -identity <file>
+    Open the bundles with a private key file created by the "nomad var keygen"
+    command. This flag can be repeated.
+
+  -prune
+    Delete variables that are within the namespace and prefix a bundle was
+    exported from but that are not in any bundle.
+
+  -dry-run
+    Print the changes the sync would make without writing any variable.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarSyncCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-passphrase-file": complete.PredictFiles("*"),
+			"-identity":        complete.PredictFiles("*"),
+			"-prune":           complete.PredictNothing,
+			"-dry-run":         complete.PredictNothing,
+		},
+	)
+}
+
+func (c *VarSyncCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *VarSyncCommand) Synopsis() string {
+	return "Reconcile variables with a directory of sealed bundles"
+}
+
+func (c *VarSyncCommand) Name() string { return "var sync" }
+
+func (c *VarSyncCommand) Run(args []string) int {
+	var passphraseFile string
+	var identities flaghelper.StringFlag
+	var prune, dryRun bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&passphraseFile, "passphrase-file", "", "")
+	flags.Var(&identities, "identity", "")
+	flags.BoolVar(&prune, "prune", false, "")
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <dir>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	keys, err := loadVarBundleKeys(passphraseFile, nil, identities)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if keys.passphrase == "" && len(keys.identities) == 0 {
+		c.Ui.Error("A -passphrase-file or -identity is required to open the bundles")
+		return 1
+	}
+
+	bundles, err := readVarBundleDir(args[0], keys)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading bundles: %s", err))
+		return 1
+	}
+	if len(bundles) == 0 {
+		c.Ui.Error(fmt.Sprintf("No %s files found in %q", varBundleExt, args[0]))
+		return 1
+	}
+
+	// Index the entries of all bundles so each variable is only defined once
+	desired := map[string]string{}
+	for _, bundle := range bundles {
+		for _, entry := range bundle.Contents.Variables {
+			id := varEntryID(entry.Namespace, entry.Path)
+			if other, ok := desired[id]; ok {
+				c.Ui.Error(fmt.Sprintf("Variable %q is defined in both %q and %q", id, other, bundle.Path))
+				return 1
+			}
+			desired[id] = bundle.Path
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var changes []*varChange
+	for _, bundle := range bundles {
+		for _, entry := range bundle.Contents.Variables {
+			vc, err := planVarEntry(client, entry, true)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error planning sync: %s", err))
+				return 1
+			}
+			if vc != nil {
+				changes = append(changes, vc)
+			}
+		}
+	}
+
+	if prune {
+		deletes, err := planVarPrune(client, bundles, desired)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error planning sync: %s", err))
+			return 1
+		}
+		changes = append(changes, deletes...)
+	}
+
+	if len(changes) == 0 {
+		c.Ui.Output("All variables are up to date")
+		return 0
+	}
+
+	for _, vc := range changes {
+		c.Ui.Output(vc.String())
+	}
+	if dryRun {
+		return 0
+	}
+
+	return applyVarChanges(c.Ui, client, changes)
+}
+
+// varBundleFile is a bundle read from a file by the sync command.
+type varBundleFile struct {
+	Path     string
+	Contents *varBundleContents
+}
+
+// readVarBundleDir opens every bundle in dir and its subdirectories, in
+// lexical order of their paths.
+func readVarBundleDir(dir string, keys *varBundleKeys) ([]*varBundleFile, error) {
+	var bundles []*varBundleFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != varBundleExt {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read bundle %q: %w", path, err)
+		}
+		contents, err := openVarBundle(data, keys)
+		if err != nil {
+			return fmt.Errorf("failed to open bundle %q: %w", path, err)
+		}
+		bundles = append(bundles, &varBundleFile{Path: path, Contents: contents})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+// planVarPrune returns the deletes of the variables that are within the scope
+// of a bundle but not defined by any bundle.
+func planVarPrune(client *api.Client, bundles []*varBundleFile, desired map[string]string) ([]*varChange, error) {
+	seen := map[string]struct{}{}
+	var deletes []*varChange
+	for _, bundle := range bundles {
+		contents := bundle.Contents
+		vars, _, err := client.Variables().PrefixList(contents.Prefix,
+			&api.QueryOptions{Namespace: contents.Namespace})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variables: %w", err)
+		}
+		for _, meta := range vars {
+			id := varEntryID(meta.Namespace, meta.Path)
+			if _, ok := desired[id]; ok {
+				continue
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			deletes = append(deletes, &varChange{
+				Kind:        varChangeDelete,
+				Namespace:   meta.Namespace,
+				Path:        meta.Path,
+				ModifyIndex: meta.ModifyIndex,
+			})
+		}
+	}
+	return deletes, nil
+}
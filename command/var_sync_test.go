@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarSyncCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarSyncCommand{}
+}
+
+func TestVarSyncCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	must.NoError(t, os.WriteFile(passphraseFile, []byte("correct horse"), 0o600))
+	keys, err := loadVarBundleKeys(passphraseFile, nil, nil)
+	must.NoError(t, err)
+
+	bundleDir := filepath.Join(dir, "bundles")
+	must.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "nested"), 0o700))
+	writeBundle := func(name string, contents *varBundleContents) {
+		sealed, err := sealVarBundle(contents, keys)
+		must.NoError(t, err)
+		must.NoError(t, os.WriteFile(filepath.Join(bundleDir, name), sealed, 0o600))
+	}
+	writeBundle("nested/app"+varBundleExt, &varBundleContents{
+		Namespace: "default",
+		Prefix:    "app",
+		Variables: []*varBundleEntry{{
+			Namespace: "default",
+			Path:      "app/db",
+			Items:     api.VariableItems{"password": "hunter2"},
+		}},
+	})
+
+	// Create a variable that is up to date and one that is not in the bundle
+	for _, path := range []string{"app/db", "app/stale"} {
+		_, _, err := client.Variables().Create(&api.Variable{
+			Path:  path,
+			Items: api.VariableItems{"password": "old"},
+		}, nil)
+		must.NoError(t, err)
+	}
+
+	t.Run("dry_run", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarSyncCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-passphrase-file=" + passphraseFile,
+			"-prune", "-dry-run", bundleDir})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		out := ui.OutputWriter.String()
+		must.Eq(t, "~ default/app/db (changed: password)\n- default/app/stale\n", out)
+		must.StrNotContains(t, out, "hunter2")
+
+		current, _, err := client.Variables().Read("app/db", nil)
+		must.NoError(t, err)
+		must.Eq(t, "old", current.Items["password"])
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarSyncCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-passphrase-file=" + passphraseFile,
+			"-prune", bundleDir})
+		must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+		must.StrContains(t, ui.OutputWriter.String(), "Applied 2 changes")
+
+		current, _, err := client.Variables().Read("app/db", nil)
+		must.NoError(t, err)
+		must.Eq(t, "hunter2", current.Items["password"])
+		_, _, err = client.Variables().Read("app/stale", nil)
+		must.ErrorIs(t, err, api.ErrVariablePathNotFound)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		writeBundle("dup"+varBundleExt, &varBundleContents{
+			Namespace: "default",
+			Variables: []*varBundleEntry{{
+				Namespace: "default",
+				Path:      "app/db",
+				Items:     api.VariableItems{"password": "other"},
+			}},
+		})
+		defer os.Remove(filepath.Join(bundleDir, "dup"+varBundleExt))
+
+		ui := cli.NewMockUi()
+		cmd := &VarSyncCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-passphrase-file=" + passphraseFile, bundleDir})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "is defined in both")
+	})
+}
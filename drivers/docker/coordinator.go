@@ -296,6 +296,24 @@ func (d *dockerCoordinator) RemoveImage(imageID, callerID string) {
 	delete(d.imageRefCount, imageID)
 }
 
+// RemoveImageAfter removes the reference of the caller to the given image once
+// the delay has passed, as done by RemoveImage. It is used by callers which
+// hold an image for a limited time, such as prefetching an image before the
+// tasks using it are placed.
+func (d *dockerCoordinator) RemoveImageAfter(imageID, callerID string, delay time.Duration) {
+	if !d.cleanup {
+		return
+	}
+
+	go func() {
+		select {
+		case <-d.ctx.Done():
+		case <-time.After(delay):
+			d.RemoveImage(imageID, callerID)
+		}
+	}()
+}
+
 // removeImageImpl is used to remove an image. It wil wait the specified remove
 // delay to remove the image. If the context is cancelled before that the image
 // removal will be cancelled.
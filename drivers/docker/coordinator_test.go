@@ -268,6 +268,74 @@ func TestDockerCoordinator_No_Cleanup(t *testing.T) {
 	}
 }
 
+func TestDockerCoordinator_RemoveImageAfter(t *testing.T) {
+	ci.Parallel(t)
+	image := "foo"
+	imageID := uuid.Generate()
+	mapping := map[string]string{imageID: image}
+
+	mock := newMockImageClient(mapping, 1*time.Millisecond)
+	config := &dockerCoordinatorConfig{
+		ctx:         context.Background(),
+		logger:      testlog.HCLogger(t),
+		cleanup:     true,
+		client:      mock,
+		removeDelay: 1 * time.Millisecond,
+	}
+
+	// Create a coordinator
+	coordinator := newDockerCoordinator(config)
+
+	// Pull image for a prefetch, which releases its reference after a delay
+	prefetchID := uuid.Generate()
+	id, err := coordinator.PullImage(image, nil, prefetchID, nil, 5*time.Minute, 2*time.Minute)
+	require.NoError(t, err)
+	coordinator.RemoveImageAfter(id, prefetchID, 50*time.Millisecond)
+
+	// Pull image for a task before the delay passes
+	callerID := uuid.Generate()
+	_, err = coordinator.PullImage(image, nil, callerID, nil, 5*time.Minute, 2*time.Minute)
+	require.NoError(t, err)
+
+	// Check the prefetch reference is released but the image is kept
+	testutil.WaitForResult(func() (bool, error) {
+		coordinator.imageLock.Lock()
+		defer coordinator.imageLock.Unlock()
+		references := len(coordinator.imageRefCount[id])
+		return references == 1, fmt.Errorf("Got reference count %d; want %d", references, 1)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	mock.lock.Lock()
+	require.Zero(t, mock.removed[id])
+	mock.lock.Unlock()
+
+	// Check the image is removed once the task releases it
+	coordinator.RemoveImage(id, callerID)
+	testutil.WaitForResult(func() (bool, error) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+		removes := mock.removed[id]
+		return removes == 1, fmt.Errorf("Wrong number of removes: %d", removes)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Check an image only held by a prefetch is removed after the delay
+	prefetchID = uuid.Generate()
+	id, err = coordinator.PullImage(image, nil, prefetchID, nil, 5*time.Minute, 2*time.Minute)
+	require.NoError(t, err)
+	coordinator.RemoveImageAfter(id, prefetchID, 10*time.Millisecond)
+	testutil.WaitForResult(func() (bool, error) {
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+		removes := mock.removed[id]
+		return removes == 2, fmt.Errorf("Wrong number of removes: %d", removes)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 func TestDockerCoordinator_Cleanup_HonorsCtx(t *testing.T) {
 	ci.Parallel(t)
 	image1ID := uuid.Generate()
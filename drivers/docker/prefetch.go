@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package docker
+
+import (
+	"fmt"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// prefetchImagePullTimeout is the timeout of prefetching an image, which
+// matches the default image_pull_timeout of tasks.
+const prefetchImagePullTimeout = 5 * time.Minute
+
+// prefetchImageTTL is how long a prefetched image is referenced, so that it
+// isn't garbage collected before tasks using it are placed on the client.
+const prefetchImageTTL = 1 * time.Hour
+
+var _ drivers.ImagePrefetchDriver = (*Driver)(nil)
+
+// PrefetchImage pulls an image before the tasks using it are placed on the
+// client. Images already present are not pulled again unless their tag is
+// latest. Each prefetch references the image for prefetchImageTTL, after which
+// the image is garbage collected like any other image once no task uses it.
+func (d *Driver) PrefetchImage(image string, auth *structs.PrefetchImageAuth) error {
+	repo, tag := parseDockerImage(image)
+
+	client, err := d.getDockerClient()
+	if err != nil {
+		return err
+	}
+
+	callerID := "prefetch-" + uuid.Generate()
+
+	if tag != "latest" {
+		if dockerImage, _ := client.InspectImage(image); dockerImage != nil {
+			d.coordinator.IncrementImageReference(dockerImage.ID, image, callerID)
+			d.coordinator.RemoveImageAfter(dockerImage.ID, callerID, prefetchImageTTL)
+			return nil
+		}
+	}
+
+	authOptions, err := firstValidAuth(repo, []authBackend{
+		authFromPrefetch(auth),
+		authFromDockerConfig(d.config.Auth.Config),
+		authFromHelper(d.config.Auth.Helper),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to find docker auth for repo %q: %v", repo, err)
+	}
+
+	d.logger.Debug("prefetching image", "image_ref", dockerImageRef(repo, tag))
+	id, err := d.coordinator.PullImage(image, authOptions, callerID, noopLogEventFn,
+		prefetchImagePullTimeout, d.config.pullActivityTimeoutDuration)
+	if err != nil {
+		return err
+	}
+	d.coordinator.RemoveImageAfter(id, callerID, prefetchImageTTL)
+	return nil
+}
+
+// authFromPrefetch returns an authBackend for the registry authentication
+// of the tasks using a prefetched image, as set in their auth block.
+func authFromPrefetch(auth *structs.PrefetchImageAuth) authBackend {
+	return func(string) (*docker.AuthConfiguration, error) {
+		if auth == nil {
+			return nil, nil
+		}
+		return &docker.AuthConfiguration{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			Email:         auth.Email,
+			ServerAddress: auth.ServerAddress,
+		}, nil
+	}
+}
+
+// ImagePresent returns whether the image is present, since prefetched images
+// are removed by image garbage collection once they are no longer referenced.
+func (d *Driver) ImagePresent(image string) (bool, error) {
+	client, err := d.getDockerClient()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.InspectImage(image)
+	if err == docker.ErrNoSuchImage {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,363 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package raftcrypt implements authenticated encryption of the raft log store
+// and snapshot store. Each server encrypts its raft data with its own keyring
+// of data keys, which is written to the raft directory wrapped by a key
+// encryption key (KEK).
+package raftcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/crypto"
+)
+
+const (
+	// KeyringFile is the name of the keyring file in the raft directory.
+	KeyringFile = "keyring.json"
+
+	// KeySize is the size of the AES-256 data keys.
+	KeySize = 32
+
+	// keyIDSize is the size of key IDs in encrypted records.
+	keyIDSize = 8
+
+	// version is the version of the encrypted record formats.
+	version byte = 1
+)
+
+// magic prefixes encrypted log entries and snapshots. The first byte can't
+// begin a msgpack encoded raft command or a gzip snapshot, so plaintext data
+// written before encryption was enabled can be read transparently.
+var magic = []byte{0xff, 'N', 'R', 'E'}
+
+// ErrUnknownKey is returned when data is encrypted with a key that is not in
+// the keyring.
+var ErrUnknownKey = errors.New("raft data is encrypted with a key not in the keyring")
+
+// IsEncrypted returns true if the raft data was encrypted by this package.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// KeyID returns the ID of a data key, which is derived from the key so that
+// operators only need the key material for offline decryption.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:keyIDSize])
+}
+
+// Keyring is a set of data keys used to encrypt raft data. New data is
+// encrypted with the active key, while data encrypted with any key in the
+// keyring can be decrypted. A keyring without an active key only decrypts.
+type Keyring struct {
+	keys   map[string]*keyringKey
+	active string
+	lock   sync.RWMutex
+}
+
+type keyringKey struct {
+	key        []byte
+	createTime time.Time
+	aead       cipher.AEAD
+}
+
+// NewKeyring returns an empty keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{keys: map[string]*keyringKey{}}
+}
+
+// AddKey adds a data key to the keyring and returns its ID.
+func (k *Keyring) AddKey(key []byte, createTime time.Time) (string, error) {
+	if len(key) != KeySize {
+		return "", fmt.Errorf("raft encryption keys must be %d bytes", KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	id := KeyID(key)
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.keys[id] = &keyringKey{key: key, createTime: createTime, aead: aead}
+	return id, nil
+}
+
+// GenerateKey returns a new random data key.
+func GenerateKey() ([]byte, error) {
+	key, err := crypto.Bytes(KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate raft encryption key: %w", err)
+	}
+	return key, nil
+}
+
+// SetActive sets the key used to encrypt new data. An empty ID disables
+// encryption of new data.
+func (k *Keyring) SetActive(id string) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if _, ok := k.keys[id]; !ok && id != "" {
+		return fmt.Errorf("raft encryption key %q is not in the keyring", id)
+	}
+	k.active = id
+	return nil
+}
+
+// Active returns the ID and creation time of the active key, or an empty ID
+// if new data is not encrypted.
+func (k *Keyring) Active() (string, time.Time) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	if k.active == "" {
+		return "", time.Time{}
+	}
+	return k.active, k.keys[k.active].createTime
+}
+
+// Newest returns the ID and creation time of the most recently created key, or
+// an empty ID if the keyring is empty.
+func (k *Keyring) Newest() (string, time.Time) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	var newestID string
+	var newest time.Time
+	for id, key := range k.keys {
+		if newestID == "" || key.createTime.After(newest) {
+			newestID, newest = id, key.createTime
+		}
+	}
+	return newestID, newest
+}
+
+// Len returns the number of keys in the keyring.
+func (k *Keyring) Len() int {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return len(k.keys)
+}
+
+func (k *Keyring) activeKey() (string, *keyringKey) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	if k.active == "" {
+		return "", nil
+	}
+	return k.active, k.keys[k.active]
+}
+
+func (k *Keyring) key(id string) (*keyringKey, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	return key, nil
+}
+
+// Encrypt encrypts plaintext with the active key, binding it to the additional
+// data. Plaintext is returned unchanged if there is no active key.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	id, key := k.activeKey()
+	if key == nil {
+		return plaintext, nil
+	}
+
+	nonce, err := crypto.Bytes(key.aead.NonceSize())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, headerSize+len(nonce)+len(plaintext)+key.aead.Overhead())
+	out = appendHeader(out, id)
+	out = append(out, nonce...)
+	return key.aead.Seal(out, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt decrypts data returned by Encrypt with the same additional data.
+// Data that was not encrypted is returned unchanged.
+func (k *Keyring) Decrypt(data, additionalData []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	id, rest, err := parseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.key(id)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := key.aead.NonceSize()
+	if len(rest) < nonceSize {
+		return nil, errors.New("encrypted raft data is truncated")
+	}
+	plaintext, err := key.aead.Open(nil, rest[:nonceSize], rest[nonceSize:], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt raft data: %w", err)
+	}
+	return plaintext, nil
+}
+
+// headerSize is the size of the header of encrypted records: the magic bytes,
+// the format version, and the key ID.
+var headerSize = len(magic) + 1 + keyIDSize
+
+func appendHeader(out []byte, id string) []byte {
+	out = append(out, magic...)
+	out = append(out, version)
+	rawID, _ := hex.DecodeString(id)
+	return append(out, rawID...)
+}
+
+func parseHeader(data []byte) (string, []byte, error) {
+	if len(data) < headerSize {
+		return "", nil, errors.New("encrypted raft data is truncated")
+	}
+	if v := data[len(magic)]; v != version {
+		return "", nil, fmt.Errorf("unsupported raft encryption version %d", v)
+	}
+	id := hex.EncodeToString(data[len(magic)+1 : headerSize])
+	return id, data[headerSize:], nil
+}
+
+// KEKWrapper wraps the key encryption key of a keyring file with an external
+// provider. Without a KEKWrapper the key encryption key is stored in the
+// clear in the keyring file.
+type KEKWrapper interface {
+	// WrapKEK wraps the key encryption key, returning the ID of the provider
+	// that wrapped it. A nil wrapped key stores the KEK in the clear.
+	WrapKEK(kek []byte) (providerID string, wrapped []byte, err error)
+
+	// UnwrapKEK unwraps a key encryption key wrapped by the provider.
+	UnwrapKEK(providerID string, wrapped []byte) ([]byte, error)
+}
+
+// keyringFile is the on-disk format of the keyring.
+type keyringFile struct {
+	Keys                    []*keyringFileKey
+	KeyEncryptionKey        []byte `json:"KEK,omitempty"`
+	ProviderID              string `json:",omitempty"`
+	WrappedKeyEncryptionKey []byte `json:"WrappedKEK,omitempty"`
+}
+
+type keyringFileKey struct {
+	ID           string
+	CreateTime   int64
+	EncryptedKey []byte
+}
+
+// Save writes the keyring to path, with its keys encrypted by a new key
+// encryption key that is wrapped by the KEKWrapper if one is given.
+func (k *Keyring) Save(path string, wrapper KEKWrapper) error {
+	kek, err := crypto.Bytes(KeySize)
+	if err != nil {
+		return fmt.Errorf("failed to generate key encryption key: %w", err)
+	}
+	kekring := NewKeyring()
+	kekID, err := kekring.AddKey(kek, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := kekring.SetActive(kekID); err != nil {
+		return err
+	}
+
+	file := &keyringFile{}
+	if wrapper != nil {
+		file.ProviderID, file.WrappedKeyEncryptionKey, err = wrapper.WrapKEK(kek)
+		if err != nil {
+			return fmt.Errorf("failed to wrap key encryption key: %w", err)
+		}
+	}
+	if file.WrappedKeyEncryptionKey == nil {
+		file.KeyEncryptionKey = kek
+	}
+
+	k.lock.RLock()
+	for id, key := range k.keys {
+		encrypted, err := kekring.Encrypt(key.key, []byte(id))
+		if err != nil {
+			k.lock.RUnlock()
+			return err
+		}
+		file.Keys = append(file.Keys, &keyringFileKey{
+			ID:           id,
+			CreateTime:   key.createTime.UnixNano(),
+			EncryptedKey: encrypted,
+		})
+	}
+	k.lock.RUnlock()
+
+	buf, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it so that a crash can't leave a
+	// partially written keyring, which would make the raft data unreadable.
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// LoadKeyring reads a keyring written by Save. The wrapper may be nil if the
+// key encryption key was stored in the clear. The loaded keyring has no active
+// key, so it only decrypts until SetActive is called.
+func LoadKeyring(path string, wrapper KEKWrapper) (*Keyring, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &keyringFile{}
+	if err := json.Unmarshal(raw, file); err != nil {
+		return nil, fmt.Errorf("failed to decode raft keyring: %w", err)
+	}
+
+	kek := file.KeyEncryptionKey
+	if len(file.WrappedKeyEncryptionKey) > 0 {
+		if wrapper == nil {
+			return nil, fmt.Errorf("raft keyring is wrapped by keyring provider %q", file.ProviderID)
+		}
+		kek, err = wrapper.UnwrapKEK(file.ProviderID, file.WrappedKeyEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap raft keyring: %w", err)
+		}
+	}
+
+	kekring := NewKeyring()
+	if _, err := kekring.AddKey(kek, time.Now()); err != nil {
+		return nil, fmt.Errorf("invalid raft keyring key encryption key: %w", err)
+	}
+
+	keyring := NewKeyring()
+	for _, fileKey := range file.Keys {
+		key, err := kekring.Decrypt(fileKey.EncryptedKey, []byte(fileKey.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt raft key %s: %w", fileKey.ID, err)
+		}
+		if _, err := keyring.AddKey(key, time.Unix(0, fileKey.CreateTime)); err != nil {
+			return nil, err
+		}
+	}
+	return keyring, nil
+}
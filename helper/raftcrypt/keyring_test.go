@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftcrypt
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+// testRotate adds a new key to the keyring and makes it active.
+func testRotate(t *testing.T, keyring *Keyring) string {
+	t.Helper()
+	key, err := GenerateKey()
+	must.NoError(t, err)
+	id, err := keyring.AddKey(key, time.Now())
+	must.NoError(t, err)
+	must.NoError(t, keyring.SetActive(id))
+	return id
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	ci.Parallel(t)
+
+	keyring := NewKeyring()
+
+	// without an active key data is written in the clear
+	out, err := keyring.Encrypt([]byte("plaintext"), nil)
+	must.NoError(t, err)
+	must.Eq(t, "plaintext", string(out))
+
+	oldID := testRotate(t, keyring)
+	oldCiphertext, err := keyring.Encrypt([]byte("secret"), []byte("ad"))
+	must.NoError(t, err)
+	must.True(t, IsEncrypted(oldCiphertext))
+
+	newID := testRotate(t, keyring)
+	must.NotEq(t, oldID, newID)
+	active, _ := keyring.Active()
+	must.Eq(t, newID, active)
+	newest, _ := keyring.Newest()
+	must.Eq(t, newID, newest)
+
+	// data encrypted with a rotated key can still be read
+	out, err = keyring.Decrypt(oldCiphertext, []byte("ad"))
+	must.NoError(t, err)
+	must.Eq(t, "secret", string(out))
+
+	_, err = keyring.Decrypt(oldCiphertext, []byte("other"))
+	must.ErrorContains(t, err, "failed to decrypt raft data")
+
+	_, err = NewKeyring().Decrypt(oldCiphertext, []byte("ad"))
+	must.True(t, errors.Is(err, ErrUnknownKey))
+
+	// plaintext data is passed through
+	out, err = keyring.Decrypt([]byte("plaintext"), nil)
+	must.NoError(t, err)
+	must.Eq(t, "plaintext", string(out))
+}
+
+type testKEKWrapper struct {
+	kek *Keyring
+}
+
+func (w *testKEKWrapper) WrapKEK(kek []byte) (string, []byte, error) {
+	wrapped, err := w.kek.Encrypt(kek, nil)
+	return "test", wrapped, err
+}
+
+func (w *testKEKWrapper) UnwrapKEK(providerID string, wrapped []byte) ([]byte, error) {
+	if providerID != "test" {
+		return nil, errors.New("unknown provider")
+	}
+	return w.kek.Decrypt(wrapped, nil)
+}
+
+func TestKeyring_SaveLoad(t *testing.T) {
+	ci.Parallel(t)
+
+	keyring := NewKeyring()
+	testRotate(t, keyring)
+	newID := testRotate(t, keyring)
+	ciphertext, err := keyring.Encrypt([]byte("secret"), nil)
+	must.NoError(t, err)
+
+	t.Run("clear", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keyring.json")
+		must.NoError(t, keyring.Save(path, nil))
+
+		loaded, err := LoadKeyring(path, nil)
+		must.NoError(t, err)
+		must.Eq(t, 2, loaded.Len())
+
+		// a loaded keyring only decrypts until a key is activated
+		active, _ := loaded.Active()
+		must.Eq(t, "", active)
+		newest, createTime := loaded.Newest()
+		must.Eq(t, newID, newest)
+		_, expectCreateTime := keyring.Active()
+		must.Eq(t, expectCreateTime.UnixNano(), createTime.UnixNano())
+
+		out, err := loaded.Decrypt(ciphertext, nil)
+		must.NoError(t, err)
+		must.Eq(t, "secret", string(out))
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		kek := NewKeyring()
+		testRotate(t, kek)
+		wrapper := &testKEKWrapper{kek: kek}
+
+		path := filepath.Join(t.TempDir(), "keyring.json")
+		must.NoError(t, keyring.Save(path, wrapper))
+
+		_, err := LoadKeyring(path, nil)
+		must.ErrorContains(t, err, `raft keyring is wrapped by keyring provider "test"`)
+
+		loaded, err := LoadKeyring(path, wrapper)
+		must.NoError(t, err)
+		out, err := loaded.Decrypt(ciphertext, nil)
+		must.NoError(t, err)
+		must.Eq(t, "secret", string(out))
+	})
+}
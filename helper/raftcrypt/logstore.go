@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftcrypt
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hashicorp/raft"
+)
+
+// LogStore wraps a raft.LogStore to encrypt the data of log entries with the
+// active key of the keyring. Entries written without encryption are read
+// unchanged, so encryption can be enabled on an existing raft store.
+type LogStore struct {
+	raft.LogStore
+	keyring *Keyring
+}
+
+// NewLogStore returns a LogStore that encrypts entries written to store.
+func NewLogStore(store raft.LogStore, keyring *Keyring) *LogStore {
+	return &LogStore{LogStore: store, keyring: keyring}
+}
+
+// logAdditionalData binds an encrypted entry to its index and type so that
+// entries can't be swapped or replayed at another position in the log.
+func logAdditionalData(log *raft.Log) []byte {
+	ad := make([]byte, 9)
+	binary.BigEndian.PutUint64(ad, log.Index)
+	ad[8] = byte(log.Type)
+	return ad
+}
+
+// GetLog reads and decrypts the log entry at index.
+func (s *LogStore) GetLog(index uint64, log *raft.Log) error {
+	if err := s.LogStore.GetLog(index, log); err != nil {
+		return err
+	}
+	return DecryptLog(s.keyring, log)
+}
+
+// StoreLog encrypts and stores a log entry.
+func (s *LogStore) StoreLog(log *raft.Log) error {
+	return s.StoreLogs([]*raft.Log{log})
+}
+
+// StoreLogs encrypts and stores log entries. The entries passed in are not
+// modified, as raft continues to use them after they are stored.
+func (s *LogStore) StoreLogs(logs []*raft.Log) error {
+	encrypted := make([]*raft.Log, len(logs))
+	for i, log := range logs {
+		data, err := s.keyring.Encrypt(log.Data, logAdditionalData(log))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt raft log %d: %w", log.Index, err)
+		}
+		out := *log
+		out.Data = data
+		encrypted[i] = &out
+	}
+	return s.LogStore.StoreLogs(encrypted)
+}
+
+// DecryptLog decrypts the data of a log entry read from a raft store in
+// place. Entries that were not encrypted are left unchanged.
+func DecryptLog(keyring *Keyring, log *raft.Log) error {
+	if !IsEncrypted(log.Data) {
+		return nil
+	}
+	if keyring == nil {
+		return fmt.Errorf("raft log %d is encrypted", log.Index)
+	}
+	data, err := keyring.Decrypt(log.Data, logAdditionalData(log))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt raft log %d: %w", log.Index, err)
+	}
+	log.Data = data
+	return nil
+}
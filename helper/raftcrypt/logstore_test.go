@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftcrypt
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/raft"
+	"github.com/shoenig/test/must"
+)
+
+func TestLogStore(t *testing.T) {
+	ci.Parallel(t)
+
+	inner := raft.NewInmemStore()
+	keyring := NewKeyring()
+	store := NewLogStore(inner, keyring)
+
+	// entries written before encryption is enabled
+	must.NoError(t, store.StoreLog(&raft.Log{Index: 1, Type: raft.LogCommand, Data: []byte("one")}))
+
+	testRotate(t, keyring)
+
+	entry := &raft.Log{Index: 2, Type: raft.LogCommand, Data: []byte("two")}
+	must.NoError(t, store.StoreLogs([]*raft.Log{entry}))
+	must.Eq(t, "two", string(entry.Data), must.Sprint("stored entry must not be modified"))
+
+	var raw raft.Log
+	must.NoError(t, inner.GetLog(1, &raw))
+	must.Eq(t, "one", string(raw.Data))
+	must.NoError(t, inner.GetLog(2, &raw))
+	must.True(t, IsEncrypted(raw.Data))
+
+	var out raft.Log
+	must.NoError(t, store.GetLog(1, &out))
+	must.Eq(t, "one", string(out.Data))
+	must.NoError(t, store.GetLog(2, &out))
+	must.Eq(t, "two", string(out.Data))
+
+	// an encrypted entry moved to another index is rejected
+	raw.Index = 3
+	must.NoError(t, inner.StoreLog(&raw))
+	must.ErrorContains(t, store.GetLog(3, &out), "failed to decrypt raft log 3")
+
+	must.ErrorContains(t, DecryptLog(nil, &raw), "raft log 3 is encrypted")
+}
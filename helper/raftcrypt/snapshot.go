@@ -0,0 +1,278 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftcrypt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/nomad/helper/crypto"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	// snapshotChunkSize is the size of the plaintext chunks that snapshots
+	// are encrypted in, so that they can be streamed.
+	snapshotChunkSize = 64 * 1024
+
+	// snapshotNonceSize is the size of the GCM nonces.
+	snapshotNonceSize = 12
+
+	// snapshotChunkOverhead is the flag byte and GCM tag of each chunk.
+	snapshotChunkOverhead = 1 + 16
+
+	chunkFlagMore  byte = 0
+	chunkFlagFinal byte = 1
+)
+
+// snapshotPrefixSize is the size of the header and base nonce that prefix an
+// encrypted snapshot.
+var snapshotPrefixSize = headerSize + snapshotNonceSize
+
+// SnapshotStore wraps a raft.SnapshotStore to encrypt snapshots with the
+// active key of the keyring. Snapshots written without encryption are read
+// unchanged.
+//
+// Snapshots are encrypted in chunks, each sealed with a nonce derived from a
+// random base nonce and the chunk's position, with the last chunk flagged so
+// that truncation is detected.
+type SnapshotStore struct {
+	raft.SnapshotStore
+	keyring *Keyring
+}
+
+// NewSnapshotStore returns a SnapshotStore that encrypts snapshots written to
+// store.
+func NewSnapshotStore(store raft.SnapshotStore, keyring *Keyring) *SnapshotStore {
+	return &SnapshotStore{SnapshotStore: store, keyring: keyring}
+}
+
+// Create returns a sink that encrypts the snapshot written to it.
+func (s *SnapshotStore) Create(version raft.SnapshotVersion, index, term uint64,
+	configuration raft.Configuration, configurationIndex uint64,
+	trans raft.Transport) (raft.SnapshotSink, error) {
+
+	sink, err := s.SnapshotStore.Create(version, index, term,
+		configuration, configurationIndex, trans)
+	if err != nil {
+		return nil, err
+	}
+
+	id, key := s.keyring.activeKey()
+	if key == nil {
+		return sink, nil
+	}
+
+	nonce, err := crypto.Bytes(snapshotNonceSize)
+	if err != nil {
+		sink.Cancel()
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	prefix := appendHeader(make([]byte, 0, snapshotPrefixSize), id)
+	prefix = append(prefix, nonce...)
+	if _, err := sink.Write(prefix); err != nil {
+		sink.Cancel()
+		return nil, err
+	}
+
+	return &encryptedSink{
+		SnapshotSink: sink,
+		key:          key,
+		baseNonce:    nonce,
+		buf:          make([]byte, 0, snapshotChunkSize),
+	}, nil
+}
+
+// Open returns a reader that decrypts the snapshot. The size in the returned
+// metadata is the size of the decrypted snapshot.
+func (s *SnapshotStore) Open(id string) (*raft.SnapshotMeta, io.ReadCloser, error) {
+	meta, rc, err := s.SnapshotStore.Open(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta, r, err := DecryptSnapshot(s.keyring, meta, rc)
+	if err != nil {
+		rc.Close()
+		return nil, nil, err
+	}
+	return meta, &readCloser{Reader: r, Closer: rc}, nil
+}
+
+// DecryptSnapshot returns a reader that decrypts a snapshot read from a raft
+// snapshot store, along with a copy of its metadata with the decrypted size.
+// Snapshots that were not encrypted are returned unchanged.
+func DecryptSnapshot(keyring *Keyring, meta *raft.SnapshotMeta, r io.Reader) (*raft.SnapshotMeta, io.Reader, error) {
+	br := bufio.NewReader(r)
+	peek, err := br.Peek(len(magic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+	if !IsEncrypted(peek) {
+		return meta, br, nil
+	}
+	if keyring == nil {
+		return nil, nil, fmt.Errorf("snapshot %s is encrypted", meta.ID)
+	}
+
+	prefix := make([]byte, snapshotPrefixSize)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	id, nonce, err := parseHeader(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := keyring.key(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decryptedMeta := *meta
+	decryptedMeta.Size = plaintextSize(meta.Size)
+
+	return &decryptedMeta, &encryptedReader{
+		r:         br,
+		key:       key,
+		baseNonce: nonce,
+	}, nil
+}
+
+// plaintextSize returns the size of an encrypted snapshot once decrypted.
+// Every chunk but the last holds a full chunk of plaintext, and the last
+// chunk may be empty, so the number of chunks follows from the size.
+func plaintextSize(size int64) int64 {
+	body := size - int64(snapshotPrefixSize)
+	sealedChunk := int64(snapshotChunkSize + snapshotChunkOverhead)
+	chunks := (body + sealedChunk - 1) / sealedChunk
+	return body - chunks*snapshotChunkOverhead
+}
+
+func chunkNonce(base []byte, counter uint64) []byte {
+	nonce := make([]byte, len(base))
+	copy(nonce, base)
+	ctr := binary.BigEndian.Uint64(nonce[4:])
+	binary.BigEndian.PutUint64(nonce[4:], ctr^counter)
+	return nonce
+}
+
+type encryptedSink struct {
+	raft.SnapshotSink
+	key       *keyringKey
+	baseNonce []byte
+	counter   uint64
+	buf       []byte
+	err       error
+}
+
+func (s *encryptedSink) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	written := 0
+	for len(p) > 0 {
+		// Only seal a full chunk once more data arrives, so that the final
+		// chunk is always the one sealed by Close.
+		if len(s.buf) == snapshotChunkSize {
+			if err := s.seal(chunkFlagMore); err != nil {
+				return written, err
+			}
+		}
+		n := copy(s.buf[len(s.buf):snapshotChunkSize], p)
+		s.buf = s.buf[:len(s.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (s *encryptedSink) seal(flag byte) error {
+	out := make([]byte, 1, 1+len(s.buf)+s.key.aead.Overhead())
+	out[0] = flag
+	out = s.key.aead.Seal(out, chunkNonce(s.baseNonce, s.counter), s.buf, []byte{flag})
+	s.counter++
+	s.buf = s.buf[:0]
+	if _, err := s.SnapshotSink.Write(out); err != nil {
+		s.err = err
+		return err
+	}
+	return nil
+}
+
+func (s *encryptedSink) Close() error {
+	if s.err == nil {
+		if err := s.seal(chunkFlagFinal); err != nil {
+			s.SnapshotSink.Cancel()
+			return err
+		}
+	}
+	return s.SnapshotSink.Close()
+}
+
+type encryptedReader struct {
+	r         io.Reader
+	key       *keyringKey
+	baseNonce []byte
+	counter   uint64
+	buf       []byte
+	final     bool
+	sealed    []byte
+}
+
+func (r *encryptedReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.final {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *encryptedReader) next() error {
+	var flag [1]byte
+	if _, err := io.ReadFull(r.r, flag[:]); err != nil {
+		return fmt.Errorf("encrypted snapshot is truncated: %w", err)
+	}
+
+	var sealed []byte
+	switch flag[0] {
+	case chunkFlagMore:
+		if r.sealed == nil {
+			r.sealed = make([]byte, snapshotChunkSize+r.key.aead.Overhead())
+		}
+		if _, err := io.ReadFull(r.r, r.sealed); err != nil {
+			return fmt.Errorf("encrypted snapshot is truncated: %w", err)
+		}
+		sealed = r.sealed
+	case chunkFlagFinal:
+		var err error
+		sealed, err = io.ReadAll(io.LimitReader(r.r, int64(snapshotChunkSize+r.key.aead.Overhead())))
+		if err != nil {
+			return err
+		}
+		r.final = true
+	default:
+		return fmt.Errorf("invalid encrypted snapshot chunk flag %d", flag[0])
+	}
+
+	plaintext, err := r.key.aead.Open(sealed[:0], chunkNonce(r.baseNonce, r.counter), sealed, flag[:])
+	if err != nil {
+		return fmt.Errorf("failed to decrypt snapshot: %w", err)
+	}
+	r.counter++
+	r.buf = plaintext
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftcrypt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/crypto"
+	"github.com/hashicorp/raft"
+	"github.com/shoenig/test/must"
+)
+
+func writeSnapshot(t *testing.T, store raft.SnapshotStore, data []byte) string {
+	t.Helper()
+	sink, err := store.Create(1, 10, 1, raft.Configuration{}, 1, nil)
+	must.NoError(t, err)
+	_, err = sink.Write(data)
+	must.NoError(t, err)
+	must.NoError(t, sink.Close())
+	return sink.ID()
+}
+
+func TestSnapshotStore(t *testing.T) {
+	ci.Parallel(t)
+
+	sizes := []int{0, 1, snapshotChunkSize - 1, snapshotChunkSize,
+		snapshotChunkSize + 1, 3*snapshotChunkSize + 17}
+
+	for _, size := range sizes {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			data, err := crypto.Bytes(size)
+			must.NoError(t, err)
+
+			inner := raft.NewInmemSnapshotStore()
+			keyring := NewKeyring()
+			testRotate(t, keyring)
+			store := NewSnapshotStore(inner, keyring)
+
+			id := writeSnapshot(t, store, data)
+
+			_, rc, err := inner.Open(id)
+			must.NoError(t, err)
+			raw, err := io.ReadAll(rc)
+			must.NoError(t, err)
+			must.True(t, IsEncrypted(raw))
+			if size > 16 {
+				must.False(t, bytes.Contains(raw, data))
+			}
+
+			meta, rc, err := store.Open(id)
+			must.NoError(t, err)
+			defer rc.Close()
+			must.Eq(t, int64(size), meta.Size)
+			out, err := io.ReadAll(rc)
+			must.NoError(t, err)
+			must.Eq(t, data, out)
+		})
+	}
+}
+
+func TestSnapshotStore_Plaintext(t *testing.T) {
+	ci.Parallel(t)
+
+	inner := raft.NewInmemSnapshotStore()
+	store := NewSnapshotStore(inner, NewKeyring())
+	id := writeSnapshot(t, store, []byte("plaintext"))
+
+	meta, rc, err := store.Open(id)
+	must.NoError(t, err)
+	defer rc.Close()
+	must.Eq(t, int64(9), meta.Size)
+	out, err := io.ReadAll(rc)
+	must.NoError(t, err)
+	must.Eq(t, "plaintext", string(out))
+}
+
+func TestSnapshotStore_Truncated(t *testing.T) {
+	ci.Parallel(t)
+
+	data, err := crypto.Bytes(2*snapshotChunkSize + 10)
+	must.NoError(t, err)
+
+	keyring := NewKeyring()
+	testRotate(t, keyring)
+	inner := raft.NewInmemSnapshotStore()
+	id := writeSnapshot(t, NewSnapshotStore(inner, keyring), data)
+
+	meta, rc, err := inner.Open(id)
+	must.NoError(t, err)
+	raw, err := io.ReadAll(rc)
+	must.NoError(t, err)
+
+	// dropping the final chunk must not go unnoticed
+	truncated := raw[:snapshotPrefixSize+2*(snapshotChunkSize+snapshotChunkOverhead)]
+	_, r, err := DecryptSnapshot(keyring, meta, bytes.NewReader(truncated))
+	must.NoError(t, err)
+	_, err = io.ReadAll(r)
+	must.ErrorContains(t, err, "encrypted snapshot is truncated")
+}
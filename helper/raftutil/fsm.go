@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
 	"github.com/hashicorp/nomad/nomad"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/raft"
@@ -37,13 +38,19 @@ type FSMHelper struct {
 	fsm   nomadFSM
 	snaps *raft.FileSnapshotStore
 
+	// keyring decrypts encrypted logs and snapshots
+	keyring *raftcrypt.Keyring
+
 	// raft
 	logFirstIdx uint64
 	logLastIdx  uint64
 	nextIdx     uint64
 }
 
-func NewFSM(p string) (*FSMHelper, error) {
+// NewFSM returns a helper to replay the raft state found in the raft
+// directory p. Encrypted logs and snapshots are decrypted with the keyring,
+// which may be nil if the raft data is not encrypted.
+func NewFSM(p string, keyring *raftcrypt.Keyring) (*FSMHelper, error) {
 	store, firstIdx, lastIdx, err := RaftStateInfo(filepath.Join(p, "raft.db"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open raft database %v: %v", p, err)
@@ -70,6 +77,8 @@ func NewFSM(p string) (*FSMHelper, error) {
 		fsm:    fsm,
 		snaps:  snaps,
 
+		keyring: keyring,
+
 		logFirstIdx: firstIdx,
 		logLastIdx:  lastIdx,
 		nextIdx:     uint64(1),
@@ -129,6 +138,9 @@ func (f *FSMHelper) ApplyNext() (index uint64, term uint64, err error) {
 
 	var e raft.Log
 	err = f.store.GetLog(f.nextIdx, &e)
+	if err == nil {
+		err = raftcrypt.DecryptLog(f.keyring, &e)
+	}
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to read log entry at index %d: %v", f.nextIdx, err)
 	}
@@ -229,13 +241,20 @@ func (f *FSMHelper) restoreFromSnapshot() (index uint64, term uint64, err error)
 	f.logger.Debug("found snapshots", "count", len(snapshots))
 
 	for _, snapshot := range snapshots {
-		_, source, err := f.snaps.Open(snapshot.ID)
+		meta, source, err := f.snaps.Open(snapshot.ID)
 		if err != nil {
 			f.logger.Warn("failed to open a snapshot", "snapshot_id", snapshot.ID, "error", err)
 			continue
 		}
 
-		err = f.fsm.Restore(source)
+		_, r, err := raftcrypt.DecryptSnapshot(f.keyring, meta, source)
+		if err != nil {
+			source.Close()
+			f.logger.Warn("failed to decrypt a snapshot", "snapshot_id", snapshot.ID, "error", err)
+			continue
+		}
+
+		err = f.fsm.Restore(io.NopCloser(r))
 		source.Close()
 		if err != nil {
 			f.logger.Warn("failed to restore a snapshot", "snapshot_id", snapshot.ID, "error", err)
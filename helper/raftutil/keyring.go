@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftutil
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
+	"github.com/hashicorp/nomad/nomad/kekprovider"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// LoadKeyring returns a keyring to decrypt the raft data found in raftDir for
+// offline inspection. The keyring file in raftDir is loaded if its key
+// encryption key can be unwrapped, either because it is stored in the clear
+// or with one of the keyring providers. Base64 encoded data keys are added to
+// the keyring as well. A nil keyring is returned if there are neither keys nor
+// a keyring file, in which case the raft data is expected to be unencrypted.
+func LoadKeyring(raftDir string, providers []*config.KEKProviderConfig, keys []string) (*raftcrypt.Keyring, error) {
+	keyring, err := loadKeyringFile(raftDir, providers)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil && len(keys) == 0:
+		return nil, err
+	case err != nil:
+		// The explicit keys may be all that's needed to read the data
+		hclog.L().Warn("failed to load raft keyring", "error", err)
+	}
+
+	if len(keys) == 0 {
+		return keyring, nil
+	}
+	if keyring == nil {
+		keyring = raftcrypt.NewKeyring()
+	}
+	for _, encoded := range keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode raft encryption key: %w", err)
+		}
+		if _, err := keyring.AddKey(key, time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	return keyring, nil
+}
+
+func loadKeyringFile(raftDir string, providers []*config.KEKProviderConfig) (*raftcrypt.Keyring, error) {
+	path := filepath.Join(raftDir, raftcrypt.KeyringFile)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	wrapper := &providerWrapper{providers: map[string]kekprovider.Provider{}}
+	defer wrapper.close()
+	for _, cfg := range providers {
+		if cfg.Provider == config.KEKProviderAEAD {
+			continue
+		}
+		provider, err := kekprovider.New(cfg, hclog.L())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create keyring provider %q: %w", cfg.ID(), err)
+		}
+		wrapper.providers[cfg.ID()] = provider
+	}
+
+	return raftcrypt.LoadKeyring(path, wrapper)
+}
+
+// providerWrapper unwraps the raft keyring with keyring providers. It can't
+// wrap keys, as the offline tools never write the keyring.
+type providerWrapper struct {
+	providers map[string]kekprovider.Provider
+}
+
+func (w *providerWrapper) WrapKEK([]byte) (string, []byte, error) {
+	return "", nil, errors.New("raft keyring is read-only")
+}
+
+func (w *providerWrapper) UnwrapKEK(providerID string, wrapped []byte) ([]byte, error) {
+	provider, ok := w.providers[providerID]
+	if !ok {
+		return nil, fmt.Errorf("keyring provider %q is not configured", providerID)
+	}
+	return provider.Unwrap(context.Background(), wrapped)
+}
+
+func (w *providerWrapper) close() {
+	for _, provider := range w.providers {
+		provider.Close()
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftutil
+
+import (
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
+	"github.com/hashicorp/nomad/nomad/kekprovider"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/shoenig/test/must"
+)
+
+// testEncryptedRaftDir writes an encrypted log entry to a new raft directory
+// and returns the directory and the key the entry is encrypted with.
+func testEncryptedRaftDir(t *testing.T) (string, []byte) {
+	t.Helper()
+	dir := t.TempDir()
+
+	key, err := raftcrypt.GenerateKey()
+	must.NoError(t, err)
+	keyring := raftcrypt.NewKeyring()
+	id, err := keyring.AddKey(key, time.Now())
+	must.NoError(t, err)
+	must.NoError(t, keyring.SetActive(id))
+
+	data, err := structs.Encode(structs.NodeRegisterRequestType, &structs.NodeRegisterRequest{
+		Node: &structs.Node{ID: "node-1"},
+	})
+	must.NoError(t, err)
+
+	bolt, err := raftboltdb.NewBoltStore(filepath.Join(dir, "raft.db"))
+	must.NoError(t, err)
+	store := raftcrypt.NewLogStore(bolt, keyring)
+	must.NoError(t, store.StoreLog(&raft.Log{Index: 1, Term: 1, Type: raft.LogCommand, Data: data}))
+	must.NoError(t, bolt.Close())
+
+	return dir, key
+}
+
+func readLogEntries(t *testing.T, dir string, keyring *raftcrypt.Keyring) ([]any, []error) {
+	t.Helper()
+	logCh, errCh, err := LogEntries(filepath.Join(dir, "raft.db"), keyring)
+	must.NoError(t, err)
+
+	var entries []any
+	var warnings []error
+	for {
+		select {
+		case entry, ok := <-logCh:
+			if !ok {
+				return entries, warnings
+			}
+			entries = append(entries, entry)
+		case warning := <-errCh:
+			warnings = append(warnings, warning)
+		}
+	}
+}
+
+// testProviderWrapper wraps keys with a single keyring provider.
+type testProviderWrapper struct {
+	provider kekprovider.Provider
+}
+
+func (w *testProviderWrapper) WrapKEK(kek []byte) (string, []byte, error) {
+	wrapped, err := w.provider.Wrap(context.Background(), kek)
+	return w.provider.ID(), wrapped, err
+}
+
+func (w *testProviderWrapper) UnwrapKEK(_ string, wrapped []byte) ([]byte, error) {
+	return w.provider.Unwrap(context.Background(), wrapped)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("unencrypted", func(t *testing.T) {
+		keyring, err := LoadKeyring(t.TempDir(), nil, nil)
+		must.NoError(t, err)
+		must.Nil(t, keyring)
+	})
+
+	t.Run("encrypted without keys", func(t *testing.T) {
+		dir, _ := testEncryptedRaftDir(t)
+		entries, warnings := readLogEntries(t, dir, nil)
+		must.SliceEmpty(t, entries)
+		must.Len(t, 1, warnings)
+		must.ErrorContains(t, warnings[0], "raft log 1 is encrypted")
+	})
+
+	t.Run("explicit key", func(t *testing.T) {
+		dir, key := testEncryptedRaftDir(t)
+		keyring, err := LoadKeyring(dir, nil, []string{base64.StdEncoding.EncodeToString(key)})
+		must.NoError(t, err)
+
+		entries, warnings := readLogEntries(t, dir, keyring)
+		must.SliceEmpty(t, warnings)
+		must.Len(t, 1, entries)
+		must.Eq(t, "NodeRegisterRequestType", entries[0].(*logMessage).CommandType)
+	})
+
+	t.Run("keyring file", func(t *testing.T) {
+		dir, key := testEncryptedRaftDir(t)
+		saved := raftcrypt.NewKeyring()
+		_, err := saved.AddKey(key, time.Now())
+		must.NoError(t, err)
+		must.NoError(t, saved.Save(filepath.Join(dir, raftcrypt.KeyringFile), nil))
+
+		keyring, err := LoadKeyring(dir, nil, nil)
+		must.NoError(t, err)
+		entries, warnings := readLogEntries(t, dir, keyring)
+		must.SliceEmpty(t, warnings)
+		must.Len(t, 1, entries)
+	})
+
+	t.Run("keyring file wrapped by provider", func(t *testing.T) {
+		_, transitConfig := kekprovider.TestTransitServer(t, "nomad")
+		transit, err := kekprovider.New(transitConfig, hclog.NewNullLogger())
+		must.NoError(t, err)
+
+		dir, key := testEncryptedRaftDir(t)
+		saved := raftcrypt.NewKeyring()
+		_, err = saved.AddKey(key, time.Now())
+		must.NoError(t, err)
+		must.NoError(t, saved.Save(filepath.Join(dir, raftcrypt.KeyringFile),
+			&testProviderWrapper{provider: transit},
+		))
+
+		_, err = LoadKeyring(dir, nil, nil)
+		must.ErrorContains(t, err, `keyring provider "transit" is not configured`)
+
+		keyring, err := LoadKeyring(dir, []*config.KEKProviderConfig{transitConfig}, nil)
+		must.NoError(t, err)
+		entries, warnings := readLogEntries(t, dir, keyring)
+		must.SliceEmpty(t, warnings)
+		must.Len(t, 1, entries)
+	})
+}
@@ -65,6 +65,12 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.ACLBindingRulesDeleteRequestType:             "ACLBindingRulesDeleteRequestType",
 	structs.NodePoolUpsertRequestType:                    "NodePoolUpsertRequestType",
 	structs.NodePoolDeleteRequestType:                    "NodePoolDeleteRequestType",
+	structs.NetworkPolicyUpsertRequestType:               "NetworkPolicyUpsertRequestType",
+	structs.NetworkPolicyDeleteRequestType:               "NetworkPolicyDeleteRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
+	structs.EventSinksUpsertRequestType:                  "EventSinksUpsertRequestType",
+	structs.EventSinksDeleteRequestType:                  "EventSinksDeleteRequestType",
+	structs.EventSinksProgressRequestType:                "EventSinksProgressRequestType",
+	structs.VariableVersionsDeleteRequestType:            "VariableVersionsDeleteRequestType",
 }
@@ -22,7 +22,7 @@ func TestSampleInvariant(t *testing.T) {
 	ns := "default"
 	parentID := "myjob"
 
-	fsm, err := NewFSM(path)
+	fsm, err := NewFSM(path, nil)
 	require.NoError(t, err)
 
 	for {
@@ -76,7 +76,7 @@ func TestSchedulerLogic(t *testing.T) {
 	jobID := "myjob"
 	testIdx := uint64(3234)
 
-	fsm, err := NewFSM(path)
+	fsm, err := NewFSM(path, nil)
 	require.NoError(t, err)
 
 	_, _, err = fsm.ApplyUntil(testIdx)
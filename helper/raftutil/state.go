@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-msgpack/v2/codec"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
 	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
@@ -56,9 +57,10 @@ func RaftStateInfo(p string) (store *raftboltdb.BoltStore, firstIdx uint64, last
 
 // LogEntries reads the raft logs found in the data directory found at
 // the path `p`, and returns a channel of logs, and a channel of
-// warnings. If opening the raft state returns an error, both channels
-// will be nil.
-func LogEntries(p string) (<-chan interface{}, <-chan error, error) {
+// warnings. Encrypted logs are decrypted with the keyring, which may be nil
+// if the logs are not encrypted. If opening the raft state returns an error,
+// both channels will be nil.
+func LogEntries(p string, keyring *raftcrypt.Keyring) (<-chan interface{}, <-chan error, error) {
 	store, firstIdx, lastIdx, err := RaftStateInfo(p)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open raft logs: %v", err)
@@ -73,6 +75,9 @@ func LogEntries(p string) (<-chan interface{}, <-chan error, error) {
 		for i := firstIdx; i <= lastIdx; i++ {
 			var e raft.Log
 			err := store.GetLog(i, &e)
+			if err == nil {
+				err = raftcrypt.DecryptLog(keyring, &e)
+			}
 			if err != nil {
 				warnings <- fmt.Errorf(
 					"failed to read log entry at index %d (firstIdx: %d, lastIdx: %d): %v",
@@ -32,7 +32,7 @@ func TestRaftStateInfo_InUse(t *testing.T) {
 	require.EqualError(t, err, errAlreadyOpen.Error())
 
 	// LogEntries should produce the same error
-	_, _, err = LogEntries(dir)
+	_, _, err = LogEntries(dir, nil)
 	require.EqualError(t, err, "failed to open raft logs: "+errAlreadyOpen.Error())
 
 	// Commands should work once the db is closed
@@ -43,7 +43,7 @@ func TestRaftStateInfo_InUse(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, s.Close())
 
-	logCh, errCh, err := LogEntries(dir)
+	logCh, errCh, err := LogEntries(dir, nil)
 	require.NoError(t, err)
 
 	// Consume entries to cleanly close db
@@ -1232,3 +1232,121 @@ func TestIdentity(t *testing.T) {
 	must.Eq(t, "sighup", altID.ChangeSignal)
 	must.Eq(t, 2*time.Hour, altID.TTL)
 }
+
+func TestSecrets(t *testing.T) {
+	ci.Parallel(t)
+	hclBytes, err := os.ReadFile("test-fixtures/secrets.nomad.hcl")
+	must.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/secrets.nomad.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	secrets := job.TaskGroups[0].Tasks[0].Secrets
+	must.Len(t, 2, secrets)
+
+	must.Eq(t, "db", secrets[0].Name)
+	must.Nil(t, secrets[0].Provider)
+	must.Eq(t, "nomad/jobs/secrets/web/db", *secrets[0].Path)
+	must.Eq(t, "0400", *secrets[0].Perms)
+	must.Eq(t, 1000, *secrets[0].Uid)
+	must.Eq(t, "signal", *secrets[0].ChangeMode)
+	must.Eq(t, "sighup", *secrets[0].ChangeSignal)
+
+	must.Eq(t, "api", secrets[1].Name)
+	must.Eq(t, "vault", *secrets[1].Provider)
+	must.True(t, *secrets[1].Env)
+
+	// Canonicalization fills in the defaults
+	job.Canonicalize()
+	must.Eq(t, "nomad", *secrets[0].Provider)
+	must.Eq(t, "SIGHUP", *secrets[0].ChangeSignal)
+	must.Eq(t, "restart", *secrets[1].ChangeMode)
+	must.Eq(t, "0644", *secrets[1].Perms)
+}
+
+func TestNomadToken(t *testing.T) {
+	ci.Parallel(t)
+	hclBytes, err := os.ReadFile("test-fixtures/nomad-token.nomad.hcl")
+	must.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/nomad-token.nomad.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	web := job.TaskGroups[0].Tasks[0].Nomad
+	must.NotNil(t, web)
+	must.Eq(t, []string{"ops", "deploy"}, web.Roles)
+	must.Eq(t, 30*time.Minute, *web.TTL)
+	must.False(t, *web.Env)
+	must.Eq(t, "signal", *web.ChangeMode)
+	must.Eq(t, "sighup", *web.ChangeSignal)
+
+	sidecar := job.TaskGroups[0].Tasks[1].Nomad
+	must.NotNil(t, sidecar)
+	must.Nil(t, sidecar.TTL)
+
+	// Canonicalization fills in the defaults
+	job.Canonicalize()
+	must.Eq(t, time.Hour, *sidecar.TTL)
+	must.True(t, *sidecar.Env)
+	must.False(t, *sidecar.DisableFile)
+	must.Eq(t, "restart", *sidecar.ChangeMode)
+}
+
+func TestArtifactSignature(t *testing.T) {
+	ci.Parallel(t)
+	hclBytes, err := os.ReadFile("test-fixtures/artifact-signature.nomad.hcl")
+	must.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/artifact-signature.nomad.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	artifacts := job.TaskGroups[0].Tasks[0].Artifacts
+	must.Len(t, 2, artifacts)
+
+	sig := artifacts[0].Signature
+	must.NotNil(t, sig)
+	must.Eq(t, "cosign", *sig.Format)
+	must.Eq(t, "https://example.com/app.tar.gz.sig", *sig.Source)
+	must.Eq(t, []string{"release"}, sig.Keys)
+
+	// Canonicalization fills in the defaults
+	job.Canonicalize()
+	sig = artifacts[1].Signature
+	must.NotNil(t, sig)
+	must.Eq(t, "minisign", *sig.Format)
+	must.Eq(t, "", *sig.Source)
+	must.SliceEmpty(t, sig.Keys)
+}
+
+func TestTemplateRestart(t *testing.T) {
+	ci.Parallel(t)
+	hclBytes, err := os.ReadFile("test-fixtures/template-restart.nomad.hcl")
+	must.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/template-restart.nomad.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	policy := job.TaskGroups[0].TemplateRestart
+	must.NotNil(t, policy)
+	must.Eq(t, 10, *policy.MaxParallel)
+	must.Eq(t, time.Minute, *policy.Stagger)
+	must.Eq(t, "task_states", *policy.HealthCheck)
+	must.Nil(t, policy.MinHealthyTime)
+
+	// Canonicalization fills in the defaults
+	job.Canonicalize()
+	must.Eq(t, 10*time.Second, *policy.MinHealthyTime)
+	must.Eq(t, 5*time.Minute, *policy.HealthyDeadline)
+}
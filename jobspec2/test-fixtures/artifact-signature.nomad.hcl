@@ -0,0 +1,30 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "artifact-signature" {
+  group "web" {
+    task "web" {
+      driver = "exec"
+
+      config {
+        command = "local/app"
+      }
+
+      artifact {
+        source = "https://example.com/app.tar.gz"
+
+        signature {
+          format = "cosign"
+          source = "https://example.com/app.tar.gz.sig"
+          keys   = ["release"]
+        }
+      }
+
+      artifact {
+        source = "https://example.com/config.zip"
+
+        signature {}
+      }
+    }
+  }
+}
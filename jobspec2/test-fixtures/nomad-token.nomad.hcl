@@ -0,0 +1,34 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "nomad-token" {
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = "nginx:1"
+      }
+
+      nomad {
+        roles         = ["ops", "deploy"]
+        ttl           = "30m"
+        env           = false
+        change_mode   = "signal"
+        change_signal = "sighup"
+      }
+    }
+
+    task "sidecar" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+
+      nomad {
+        roles = ["ops"]
+      }
+    }
+  }
+}
@@ -0,0 +1,30 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "secrets" {
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = "nginx:1"
+      }
+
+      vault {}
+
+      secret "db" {
+        path          = "nomad/jobs/secrets/web/db"
+        perms         = "0400"
+        uid           = 1000
+        change_mode   = "signal"
+        change_signal = "sighup"
+      }
+
+      secret "api" {
+        provider = "vault"
+        path     = "secret/data/api"
+        env      = true
+      }
+    }
+  }
+}
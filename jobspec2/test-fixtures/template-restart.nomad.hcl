@@ -0,0 +1,28 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "template-restart" {
+  group "web" {
+    count = 500
+
+    template_restart {
+      max_parallel = 10
+      stagger      = "1m"
+      health_check = "task_states"
+    }
+
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = "nginx:1"
+      }
+
+      template {
+        data        = "{{ with nomadVar \"nomad/jobs/template-restart\" }}{{ .password }}{{ end }}"
+        destination = "secrets/password"
+        change_mode = "restart"
+      }
+    }
+  }
+}
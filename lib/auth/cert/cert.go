@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package cert
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Validate verifies the client certificate chain against the CA certs of the
+// auth method, and returns the claims of the leaf certificate. The chain is
+// DER encoded, leaf first, as presented by the client during the TLS
+// handshake.
+//
+// The claims are the subject common name, organizations and organizational
+// units, the DNS, email, IP and URI SANs, and the serial number and issuer
+// common name of the certificate.
+func Validate(chain [][]byte, methodConf *structs.ACLAuthMethodConfig) (map[string]any, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("no client certificate presented")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(chain))
+	for _, der := range chain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	roots := x509.NewCertPool()
+	for _, caCert := range methodConf.CertCACerts {
+		if !roots.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("failed to parse CA certs of auth method")
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	leaf := certs[0]
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   time.Now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to verify client certificate: %w", err)
+	}
+
+	return claims(leaf), nil
+}
+
+// claims returns the claims of the certificate.
+func claims(cert *x509.Certificate) map[string]any {
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	uris := make([]string, 0, len(cert.URIs))
+	for _, uri := range cert.URIs {
+		uris = append(uris, uri.String())
+	}
+
+	return map[string]any{
+		"common_name":          cert.Subject.CommonName,
+		"serial_number":        cert.SerialNumber.Text(16),
+		"issuer_common_name":   cert.Issuer.CommonName,
+		"organizations":        toList(cert.Subject.Organization),
+		"organizational_units": toList(cert.Subject.OrganizationalUnit),
+		"dns_sans":             toList(cert.DNSNames),
+		"email_sans":           toList(cert.EmailAddresses),
+		"ip_sans":              toList(ips),
+		"uri_sans":             toList(uris),
+	}
+}
+
+// toList converts the values to the list type expected by list claim
+// mappings.
+func toList(values []string) []any {
+	list := make([]any, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
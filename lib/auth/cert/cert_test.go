@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestValidate(t *testing.T) {
+	ci.Parallel(t)
+
+	caPEM, caSigner := testCA(t)
+	otherCAPEM, otherCASigner := testCA(t)
+	conf := &structs.ACLAuthMethodConfig{CertCACerts: []string{caPEM}}
+
+	uri, err := url.Parse("spiffe://example.org/automation/deployer")
+	must.NoError(t, err)
+
+	template := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "deployer",
+			Organization:       []string{"Example"},
+			OrganizationalUnit: []string{"automation", "platform"},
+		},
+		DNSNames:       []string{"deployer.example.org"},
+		EmailAddresses: []string{"deployer@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{uri},
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		leaf := testCert(t, caPEM, caSigner, template)
+
+		claims, err := Validate([][]byte{leaf}, conf)
+		must.NoError(t, err)
+		must.Eq[any](t, "deployer", claims["common_name"])
+		must.Eq[any](t, []any{"Example"}, claims["organizations"])
+		must.SliceContainsAll(t, []any{"automation", "platform"}, claims["organizational_units"].([]any))
+		must.Eq[any](t, []any{"deployer.example.org"}, claims["dns_sans"])
+		must.Eq[any](t, []any{"deployer@example.org"}, claims["email_sans"])
+		must.Eq[any](t, []any{"10.0.0.1"}, claims["ip_sans"])
+		must.Eq[any](t, []any{"spiffe://example.org/automation/deployer"}, claims["uri_sans"])
+		must.StrContains(t, claims["issuer_common_name"].(string), "Nomad Agent CA")
+	})
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		leaf := testCert(t, otherCAPEM, otherCASigner, template)
+
+		_, err := Validate([][]byte{leaf}, conf)
+		must.ErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("server only certificate", func(t *testing.T) {
+		serverTemplate := *template
+		serverTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		leaf := testCert(t, caPEM, caSigner, &serverTemplate)
+
+		_, err := Validate([][]byte{leaf}, conf)
+		must.ErrorContains(t, err, "failed to verify client certificate")
+	})
+
+	t.Run("no certificate", func(t *testing.T) {
+		_, err := Validate(nil, conf)
+		must.ErrorContains(t, err, "no client certificate presented")
+	})
+
+	t.Run("invalid certificate", func(t *testing.T) {
+		_, err := Validate([][]byte{[]byte("not a certificate")}, conf)
+		must.ErrorContains(t, err, "failed to parse client certificate")
+	})
+}
+
+// testCA returns a PEM encoded CA cert and its signer.
+func testCA(t *testing.T) (string, tlsutil.CAOpts) {
+	t.Helper()
+
+	signer, _, err := tlsutil.GeneratePrivateKey()
+	must.NoError(t, err)
+	opts := tlsutil.CAOpts{Signer: signer}
+	caPEM, _, err := tlsutil.GenerateCA(opts)
+	must.NoError(t, err)
+	return caPEM, opts
+}
+
+// testCert returns a DER encoded certificate from the template, signed by the
+// CA.
+func testCert(t *testing.T, caPEM string, ca tlsutil.CAOpts, template *x509.Certificate) []byte {
+	t.Helper()
+
+	parent, err := tlsutil.ParseCert(caPEM)
+	must.NoError(t, err)
+	signer, _, err := tlsutil.GeneratePrivateKey()
+	must.NoError(t, err)
+	serial, err := tlsutil.GenerateSerialNumber()
+	must.NoError(t, err)
+
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, signer.Public(), ca.Signer)
+	must.NoError(t, err)
+	return der
+}
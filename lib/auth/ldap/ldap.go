@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// defaultUserAttr is the attribute of user entries matching the username
+	// when the auth method doesn't configure one.
+	defaultUserAttr = "cn"
+
+	// defaultGroupAttr is the attribute of group entries holding the group
+	// name when the auth method doesn't configure one.
+	defaultGroupAttr = "cn"
+
+	// defaultUserFilter is the filter used to search for the user logging in
+	// when the auth method doesn't configure one.
+	defaultUserFilter = "({{.UserAttr}}={{.Username}})"
+
+	// defaultGroupFilter is the filter used to search for the groups of the
+	// user when the auth method doesn't configure one. It matches the group
+	// schemas of both Active Directory and OpenLDAP.
+	defaultGroupFilter = "(|(member={{.UserDN}})(uniqueMember={{.UserDN}})(memberUid={{.Username}}))"
+)
+
+// ErrInvalidCredentials is returned when the LDAP server rejects the
+// credentials of the user, or the user can't be found.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// filterData is the data available to the user and group filter templates.
+// All values are escaped for use within an LDAP filter.
+type filterData struct {
+	UserAttr string
+	Username string
+	UserDN   string
+}
+
+// Authenticate authenticates the user against the LDAP directory of the auth
+// method, and returns the claims of the user. The claims are the username,
+// the DN and attributes of the user entry, and the names and DNs of the
+// groups the user is a member of.
+func Authenticate(ctx context.Context, username, password string, conf *structs.ACLAuthMethodConfig) (map[string]any, error) {
+	defer metrics.MeasureSince([]string{"nomad", "acl", "ldap", "authenticate"}, time.Now())
+
+	if conf == nil {
+		return nil, errors.New("missing LDAP config")
+	}
+
+	// LDAP servers treat a bind with an empty password as an unauthenticated
+	// bind, which succeeds regardless of the username, so it must never be
+	// used to authenticate.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	userAttr := conf.LDAPUserAttr
+	if userAttr == "" {
+		userAttr = defaultUserAttr
+	}
+
+	conn, err := dial(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Search for the user either as the configured bind DN, or as the user
+	// itself when there is none.
+	if conf.LDAPBindDN != "" {
+		if err := conn.Bind(conf.LDAPBindDN, conf.LDAPBindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind as %q: %w", conf.LDAPBindDN, err)
+		}
+	} else {
+		userDN := fmt.Sprintf("%s=%s,%s", userAttr, ldap.EscapeDN(username), conf.LDAPUserDN)
+		if err := conn.Bind(userDN, password); err != nil {
+			return nil, bindError(err)
+		}
+	}
+
+	data := filterData{
+		UserAttr: userAttr,
+		Username: ldap.EscapeFilter(username),
+	}
+	entry, err := searchUser(conn, conf, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.LDAPBindDN != "" {
+		if err := conn.Bind(entry.DN, password); err != nil {
+			return nil, bindError(err)
+		}
+
+		// Rebind as the bind DN, so the groups are searched with the same
+		// permissions as the user.
+		if err := conn.Bind(conf.LDAPBindDN, conf.LDAPBindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind as %q: %w", conf.LDAPBindDN, err)
+		}
+	}
+
+	data.UserDN = ldap.EscapeFilter(entry.DN)
+	groupDNs, err := searchGroups(conn, conf, entry, data)
+	if err != nil {
+		return nil, err
+	}
+
+	groupAttr := conf.LDAPGroupAttr
+	if groupAttr == "" {
+		groupAttr = defaultGroupAttr
+	}
+	groups := make([]any, 0, len(groupDNs))
+	for _, dn := range groupDNs {
+		groups = append(groups, groupName(dn, groupAttr))
+	}
+
+	attributes := make(map[string]any, len(entry.Attributes))
+	for _, attr := range entry.Attributes {
+		switch len(attr.Values) {
+		case 0:
+		case 1:
+			attributes[attr.Name] = attr.Values[0]
+		default:
+			attributes[attr.Name] = toList(attr.Values)
+		}
+	}
+
+	return map[string]any{
+		"username":   username,
+		"dn":         entry.DN,
+		"attributes": attributes,
+		"groups":     groups,
+		"group_dns":  toList(groupDNs),
+	}, nil
+}
+
+// dial connects to the first reachable LDAP server of the auth method.
+func dial(ctx context.Context, conf *structs.ACLAuthMethodConfig) (*ldap.Conn, error) {
+	if len(conf.LDAPURLs) == 0 {
+		return nil, errors.New("missing LDAP URLs")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: conf.LDAPInsecureSkipVerify,
+	}
+	if conf.LDAPCACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(conf.LDAPCACert)) {
+			return nil, errors.New("could not parse LDAP CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	var mErr *multierror.Error
+	for _, rawURL := range conf.LDAPURLs {
+		conn, err := dialURL(ctx, rawURL, conf.LDAPStartTLS, tlsConfig)
+		if err == nil {
+			return conn, nil
+		}
+		mErr = multierror.Append(mErr, fmt.Errorf("%s: %w", rawURL, err))
+	}
+	return nil, fmt.Errorf("failed to connect to LDAP server: %w", mErr.ErrorOrNil())
+}
+
+// dialURL connects to the LDAP server at the URL, upgrading the connection to
+// TLS if required.
+func dialURL(ctx context.Context, rawURL string, startTLS bool, tlsConfig *tls.Config) (*ldap.Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.ServerName = u.Hostname()
+
+	dialer := &net.Dialer{}
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		dialer.Deadline = deadline
+	}
+
+	conn, err := ldap.DialURL(rawURL, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	if hasDeadline {
+		conn.SetTimeout(time.Until(deadline))
+	}
+
+	if startTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// searchUser returns the entry of the user logging in.
+func searchUser(conn *ldap.Conn, conf *structs.ACLAuthMethodConfig, data filterData) (*ldap.Entry, error) {
+	filter, err := renderFilter(conf.LDAPUserFilter, defaultUserFilter, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP user filter: %w", err)
+	}
+
+	result, err := search(conn, ldap.NewSearchRequest(
+		conf.LDAPUserDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, filter, nil, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for user: %w", err)
+	}
+
+	switch len(result.Entries) {
+	case 0:
+		return nil, ErrInvalidCredentials
+	case 1:
+		return result.Entries[0], nil
+	default:
+		return nil, fmt.Errorf("LDAP user filter %q matched %d entries", filter, len(result.Entries))
+	}
+}
+
+// searchGroups returns the DNs of the groups the user is a member of. These
+// are the groups listed in the memberOf attribute of the user entry, along
+// with the groups under the group DN of the auth method matching the group
+// filter.
+func searchGroups(conn *ldap.Conn, conf *structs.ACLAuthMethodConfig,
+	entry *ldap.Entry, data filterData) ([]string, error) {
+
+	groupDNs := entry.GetAttributeValues("memberOf")
+	if conf.LDAPGroupDN != "" {
+		filter, err := renderFilter(conf.LDAPGroupFilter, defaultGroupFilter, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LDAP group filter: %w", err)
+		}
+
+		groupAttr := conf.LDAPGroupAttr
+		if groupAttr == "" {
+			groupAttr = defaultGroupAttr
+		}
+		result, err := search(conn, ldap.NewSearchRequest(
+			conf.LDAPGroupDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			0, 0, false, filter, []string{groupAttr}, nil,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for groups: %w", err)
+		}
+		for _, group := range result.Entries {
+			groupDNs = append(groupDNs, group.DN)
+		}
+	}
+
+	slices.Sort(groupDNs)
+	return slices.Compact(groupDNs), nil
+}
+
+// search performs the search request. Some servers answer searches without
+// results with a "no such object" error, which is treated as an empty result.
+func search(conn *ldap.Conn, req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	result, err := conn.Search(req)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return &ldap.SearchResult{}, nil
+	}
+	return result, err
+}
+
+// renderFilter renders the filter template, or the default template when
+// the filter is empty.
+func renderFilter(filter, defaultFilter string, data filterData) (string, error) {
+	if filter == "" {
+		filter = defaultFilter
+	}
+	tmpl, err := template.New("filter").Option("missingkey=error").Parse(filter)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// groupName returns the name of the group with the DN, which is the value of
+// the first relative DN if it uses the group attribute, or the DN otherwise.
+func groupName(dn, groupAttr string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return dn
+	}
+	for _, attr := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(attr.Type, groupAttr) {
+			return attr.Value
+		}
+	}
+	return dn
+}
+
+// bindError converts the error of a user bind, hiding whether the user exists.
+func bindError(err error) error {
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return ErrInvalidCredentials
+	}
+	return fmt.Errorf("failed to bind: %w", err)
+}
+
+// toList converts the strings to a list of claim values.
+func toList(values []string) []any {
+	list := make([]any, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/shoenig/test/must"
+)
+
+// testDirectory starts an in-process LDAP directory with the users alice and
+// bob, where alice is a member of the "engineering" group through the
+// memberOf attribute and of the "admins" group through the group entry.
+func testDirectory(t *testing.T, opts ...testdirectory.Option) *testdirectory.Directory {
+	t.Helper()
+
+	users := testdirectory.NewUsers(t, []string{"alice"},
+		testdirectory.WithMembersOf(t, testdirectory.NewMemberOf(t, []string{"engineering"})...))
+	users = append(users, testdirectory.NewUsers(t, []string{"bob", "nomad"})...)
+
+	opts = append(opts, testdirectory.WithDefaults(t, &testdirectory.Defaults{
+		Users:  users,
+		Groups: []*gldap.Entry{testdirectory.NewGroup(t, "admins", []string{"alice"})},
+	}))
+	return testdirectory.Start(t, opts...)
+}
+
+func testConfig(d *testdirectory.Directory) *structs.ACLAuthMethodConfig {
+	return &structs.ACLAuthMethodConfig{
+		LDAPURLs:    []string{fmt.Sprintf("ldaps://%s:%d", d.Host(), d.Port())},
+		LDAPCACert:  d.Cert(),
+		LDAPUserDN:  testdirectory.DefaultUserDN,
+		LDAPGroupDN: testdirectory.DefaultGroupDN,
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ci.Parallel(t)
+
+	d := testDirectory(t)
+
+	testCases := []struct {
+		name           string
+		username       string
+		password       string
+		modifyConfig   func(*structs.ACLAuthMethodConfig)
+		expectedGroups []any
+		expectedErr    string
+	}{
+		{
+			name:           "user bind",
+			username:       "alice",
+			password:       "password",
+			expectedGroups: []any{"engineering", "admins"},
+		},
+		{
+			name:     "service bind",
+			username: "alice",
+			password: "password",
+			modifyConfig: func(conf *structs.ACLAuthMethodConfig) {
+				conf.LDAPBindDN = "cn=nomad," + testdirectory.DefaultUserDN
+				conf.LDAPBindPassword = "password"
+			},
+			expectedGroups: []any{"engineering", "admins"},
+		},
+		{
+			name:     "memberOf only",
+			username: "alice",
+			password: "password",
+			modifyConfig: func(conf *structs.ACLAuthMethodConfig) {
+				conf.LDAPGroupDN = ""
+			},
+			expectedGroups: []any{"engineering"},
+		},
+		{
+			name:           "no groups",
+			username:       "bob",
+			password:       "password",
+			expectedGroups: []any{},
+		},
+		{
+			name:        "invalid password",
+			username:    "alice",
+			password:    "wrong",
+			expectedErr: ErrInvalidCredentials.Error(),
+		},
+		{
+			name:        "empty password",
+			username:    "alice",
+			password:    "",
+			expectedErr: ErrInvalidCredentials.Error(),
+		},
+		{
+			name:     "unknown user with service bind",
+			username: "mallory",
+			password: "password",
+			modifyConfig: func(conf *structs.ACLAuthMethodConfig) {
+				conf.LDAPBindDN = "cn=nomad," + testdirectory.DefaultUserDN
+				conf.LDAPBindPassword = "password"
+			},
+			expectedErr: ErrInvalidCredentials.Error(),
+		},
+		{
+			name:     "invalid service credentials",
+			username: "alice",
+			password: "password",
+			modifyConfig: func(conf *structs.ACLAuthMethodConfig) {
+				conf.LDAPBindDN = "cn=nomad," + testdirectory.DefaultUserDN
+				conf.LDAPBindPassword = "wrong"
+			},
+			expectedErr: "failed to bind as",
+		},
+		{
+			name:     "untrusted certificate",
+			username: "alice",
+			password: "password",
+			modifyConfig: func(conf *structs.ACLAuthMethodConfig) {
+				conf.LDAPCACert = ""
+			},
+			expectedErr: "failed to connect to LDAP server",
+		},
+		{
+			name:     "invalid filter",
+			username: "alice",
+			password: "password",
+			modifyConfig: func(conf *structs.ACLAuthMethodConfig) {
+				conf.LDAPUserFilter = "({{.Bogus}}={{.Username}})"
+			},
+			expectedErr: "invalid LDAP user filter",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf := testConfig(d)
+			if tc.modifyConfig != nil {
+				tc.modifyConfig(conf)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			claims, err := Authenticate(ctx, tc.username, tc.password, conf)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq[any](t, tc.username, claims["username"])
+			must.Eq[any](t, fmt.Sprintf("cn=%s,%s", tc.username, testdirectory.DefaultUserDN), claims["dn"])
+			must.SliceContainsAll(t, tc.expectedGroups, claims["groups"].([]any))
+
+			attributes := claims["attributes"].(map[string]any)
+			must.Eq[any](t, tc.username+"@example.com", attributes["email"])
+		})
+	}
+}
+
+func TestAuthenticate_StartTLS(t *testing.T) {
+	ci.Parallel(t)
+
+	d := testDirectory(t, testdirectory.WithNoTLS(t))
+
+	conf := testConfig(d)
+	conf.LDAPURLs = []string{
+		// The first server is unreachable, so the second one is used.
+		"ldap://127.0.0.1:1",
+		fmt.Sprintf("ldap://%s:%d", d.Host(), d.Port()),
+	}
+	conf.LDAPStartTLS = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claims, err := Authenticate(ctx, "alice", "password", conf)
+	must.NoError(t, err)
+	must.Eq[any](t, "alice", claims["username"])
+}
+
+func TestGroupName(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, "admins", groupName("cn=admins,ou=groups,dc=example,dc=org", "cn"))
+	must.Eq(t, "admins", groupName("CN=admins,OU=groups,DC=example,DC=org", "cn"))
+	must.Eq(t, "ou=admins,dc=example,dc=org", groupName("ou=admins,dc=example,dc=org", "cn"))
+	must.Eq(t, "not a dn", groupName("not a dn", "cn"))
+}
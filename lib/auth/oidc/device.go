@@ -0,0 +1,235 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/cap/oidc"
+	"github.com/hashicorp/nomad/lib/auth/jwt"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/oauth2"
+)
+
+// deviceCodeGrantType is the grant type of the token requests which poll for
+// the completion of a device authorization.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+var (
+	// ErrDeviceAuthPending is returned when the user hasn't yet completed the
+	// device authorization.
+	ErrDeviceAuthPending = errors.New("device authorization pending")
+
+	// ErrDeviceAuthSlowDown is returned when the device authorization is
+	// polled too often. The polling interval must be increased by 5 seconds.
+	ErrDeviceAuthSlowDown = errors.New("device authorization polled too often")
+)
+
+// DeviceAuthorization is the response of the OIDC provider to a device
+// authorization request. The user visits the verification URI and enters the
+// user code, while the device code is used to poll for the completion of the
+// authorization.
+type DeviceAuthorization struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	Expiry                  time.Time
+	Interval                time.Duration
+}
+
+// discoveryInfo is the part of the discovery document of the OIDC provider
+// used by the device authorization grant.
+type discoveryInfo struct {
+	Issuer        string `json:"issuer"`
+	TokenURL      string `json:"token_endpoint"`
+	DeviceAuthURL string `json:"device_authorization_endpoint"`
+}
+
+// discover returns the discovery document of the OIDC provider of the auth
+// method.
+func discover(ctx context.Context, client *http.Client, authMethod *structs.ACLAuthMethod) (*discoveryInfo, error) {
+	wellKnown := strings.TrimSuffix(authMethod.Config.OIDCDiscoveryURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OIDC discovery URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query OIDC discovery URL: %s", resp.Status)
+	}
+
+	var info discoveryInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC discovery document: %w", err)
+	}
+	if info.DeviceAuthURL == "" {
+		return nil, errors.New("OIDC provider does not support the device authorization grant")
+	}
+	return &info, nil
+}
+
+// StartDeviceAuth starts the OAuth 2.0 device authorization grant of the auth
+// method with the OIDC provider.
+func StartDeviceAuth(
+	ctx context.Context, provider *oidc.Provider, authMethod *structs.ACLAuthMethod) (*DeviceAuthorization, error) {
+
+	client, err := provider.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	info, err := discover(ctx, client, authMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := oauth2.Config{
+		ClientID: authMethod.Config.OIDCClientID,
+		Endpoint: oauth2.Endpoint{
+			DeviceAuthURL: info.DeviceAuthURL,
+			TokenURL:      info.TokenURL,
+		},
+		Scopes: append([]string{"openid"}, authMethod.Config.OIDCScopes...),
+	}
+	resp, err := conf.DeviceAuth(context.WithValue(ctx, oauth2.HTTPClient, client))
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+
+	// "If no value is provided, clients MUST use 5 as the default."
+	interval := 5 * time.Second
+	if resp.Interval > 0 {
+		interval = time.Duration(resp.Interval) * time.Second
+	}
+
+	return &DeviceAuthorization{
+		DeviceCode:              resp.DeviceCode,
+		UserCode:                resp.UserCode,
+		VerificationURI:         resp.VerificationURI,
+		VerificationURIComplete: resp.VerificationURIComplete,
+		Expiry:                  resp.Expiry,
+		Interval:                interval,
+	}, nil
+}
+
+// deviceTokenResponse is the response of the token endpoint to a device
+// access token request.
+type deviceTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// CompleteDeviceAuth polls the OIDC provider once for the completion of the
+// device authorization. It returns ErrDeviceAuthPending or
+// ErrDeviceAuthSlowDown if the user hasn't completed the authorization yet.
+// Once completed, it returns the verified claims of the ID token and a token
+// source for the access token, which can be used to query the user info.
+func CompleteDeviceAuth(
+	ctx context.Context, provider *oidc.Provider, authMethod *structs.ACLAuthMethod, deviceCode string,
+) (map[string]any, oauth2.TokenSource, error) {
+
+	client, err := provider.HTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := discover(ctx, client, authMethod)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceCode},
+		"client_id":   {authMethod.Config.OIDCClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, info.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if authMethod.Config.OIDCClientSecret != "" {
+		req.SetBasicAuth(
+			url.QueryEscape(authMethod.Config.OIDCClientID),
+			url.QueryEscape(authMethod.Config.OIDCClientSecret))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to poll device authorization: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read device access token response: %w", err)
+	}
+	var tokenResp deviceTokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode device access token response: %w", err)
+	}
+
+	// https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+	switch tokenResp.Error {
+	case "":
+	case "authorization_pending":
+		return nil, nil, ErrDeviceAuthPending
+	case "slow_down":
+		return nil, nil, ErrDeviceAuthSlowDown
+	case "access_denied":
+		return nil, nil, errors.New("device authorization was denied")
+	case "expired_token":
+		return nil, nil, errors.New("device authorization expired")
+	default:
+		return nil, nil, fmt.Errorf("device authorization failed: %s: %s",
+			tokenResp.Error, tokenResp.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("device authorization failed: %s", resp.Status)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, nil, errors.New("OIDC provider did not return an ID token")
+	}
+
+	// The device authorization grant doesn't support a nonce, so the ID token
+	// is verified against the keys of the provider, the issuer and the client
+	// ID of the auth method instead.
+	verifyConf := &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL: authMethod.Config.OIDCDiscoveryURL,
+		DiscoveryCaPem:   authMethod.Config.DiscoveryCaPem,
+		SigningAlgs:      authMethod.Config.SigningAlgs,
+		BoundAudiences:   authMethod.Config.BoundAudiences,
+		BoundIssuer:      []string{info.Issuer},
+		ClockSkewLeeway:  authMethod.Config.ClockSkewLeeway,
+	}
+	if len(verifyConf.BoundAudiences) == 0 {
+		verifyConf.BoundAudiences = []string{authMethod.Config.OIDCClientID}
+	}
+	claims, err := jwt.Validate(ctx, tokenResp.IDToken, verifyConf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to verify ID token: %w", err)
+	}
+
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{
+		AccessToken: tokenResp.AccessToken,
+		TokenType:   tokenResp.TokenType,
+	})
+	return claims, tokenSource, nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/shoenig/test/must"
+)
+
+func TestDeviceAuth(t *testing.T) {
+	ci.Parallel(t)
+
+	provider := newTestDeviceProvider(t)
+
+	authMethod := mock.ACLOIDCAuthMethod()
+	authMethod.Config.OIDCDiscoveryURL = provider.server.URL
+	authMethod.Config.OIDCClientID = "mock"
+	authMethod.Config.OIDCClientSecret = "secret"
+	authMethod.Config.SigningAlgs = []string{"ES256"}
+	authMethod.Config.BoundAudiences = nil
+	authMethod.Config.DiscoveryCaPem = nil
+
+	cache := NewProviderCache()
+	t.Cleanup(cache.Shutdown)
+	oidcProvider, err := cache.Get(authMethod)
+	must.NoError(t, err)
+
+	ctx := context.Background()
+
+	deviceAuth, err := StartDeviceAuth(ctx, oidcProvider, authMethod)
+	must.NoError(t, err)
+	must.Eq(t, "device-code", deviceAuth.DeviceCode)
+	must.Eq(t, "ABCD-EFGH", deviceAuth.UserCode)
+	must.Eq(t, provider.server.URL+"/device", deviceAuth.VerificationURI)
+	must.Eq(t, 5*time.Second, deviceAuth.Interval)
+
+	// The user hasn't completed the authorization yet.
+	_, _, err = CompleteDeviceAuth(ctx, oidcProvider, authMethod, deviceAuth.DeviceCode)
+	must.ErrorIs(t, err, ErrDeviceAuthPending)
+
+	provider.setTokenError("slow_down")
+	_, _, err = CompleteDeviceAuth(ctx, oidcProvider, authMethod, deviceAuth.DeviceCode)
+	must.ErrorIs(t, err, ErrDeviceAuthSlowDown)
+
+	// An ID token issued for another client must be rejected.
+	provider.setIDToken(t, "other", "alice")
+	_, _, err = CompleteDeviceAuth(ctx, oidcProvider, authMethod, deviceAuth.DeviceCode)
+	must.ErrorContains(t, err, "failed to verify ID token")
+
+	provider.setIDToken(t, "mock", "alice")
+	claims, tokenSource, err := CompleteDeviceAuth(ctx, oidcProvider, authMethod, deviceAuth.DeviceCode)
+	must.NoError(t, err)
+	must.Eq[any](t, "alice", claims["sub"])
+	token, err := tokenSource.Token()
+	must.NoError(t, err)
+	must.Eq(t, "access-token", token.AccessToken)
+
+	provider.setTokenError("access_denied")
+	_, _, err = CompleteDeviceAuth(ctx, oidcProvider, authMethod, deviceAuth.DeviceCode)
+	must.ErrorContains(t, err, "device authorization was denied")
+}
+
+// testDeviceProvider is a minimal OIDC provider which supports the device
+// authorization grant.
+type testDeviceProvider struct {
+	server *httptest.Server
+	key    *ecdsa.PrivateKey
+
+	lock       sync.Mutex
+	tokenError string
+	idToken    string
+}
+
+func newTestDeviceProvider(t *testing.T) *testDeviceProvider {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+
+	p := &testDeviceProvider{key: key, tokenError: "authorization_pending"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"device_authorization_endpoint":         p.server.URL + "/device/code",
+			"jwks_uri":                              p.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"ES256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key:       key.Public(),
+			KeyID:     "test",
+			Algorithm: string(jose.ES256),
+			Use:       "sig",
+		}}})
+	})
+	mux.HandleFunc("/device/code", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": p.server.URL + "/device",
+			"expires_in":       600,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != deviceCodeGrantType || r.FormValue("device_code") != "device-code" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+			return
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "mock" || secret != "secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+			return
+		}
+
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		if p.tokenError != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": p.tokenError})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"id_token":     p.idToken,
+		})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// setTokenError sets the error returned by the token endpoint.
+func (p *testDeviceProvider) setTokenError(tokenError string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.tokenError = tokenError
+}
+
+// setIDToken completes the device authorization, with an ID token issued for
+// the audience and subject.
+func (p *testDeviceProvider) setIDToken(t *testing.T, audience, subject string) {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"))
+	must.NoError(t, err)
+
+	now := time.Now()
+	idToken, err := josejwt.Signed(signer).Claims(josejwt.Claims{
+		Issuer:   p.server.URL,
+		Subject:  subject,
+		Audience: josejwt.Audience{audience},
+		IssuedAt: josejwt.NewNumericDate(now),
+		Expiry:   josejwt.NewNumericDate(now.Add(time.Minute)),
+	}).CompactSerialize()
+	must.NoError(t, err)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.tokenError = ""
+	p.idToken = idToken
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package oidc
+
+import (
+	"github.com/hashicorp/cap/oidc"
+)
+
+// codeVerifier is a PKCE code verifier rebuilt from the values passed by the
+// client. The client generates the verifier and only sends its challenge when
+// requesting the auth URL, then sends the verifier itself when completing the
+// authentication. It implements the oidc.CodeVerifier interface.
+type codeVerifier struct {
+	verifier  string
+	challenge string
+}
+
+// NewCodeChallenge returns a PKCE code verifier which only knows the S256
+// challenge of the verifier held by the client. It's used to generate the
+// auth URL.
+func NewCodeChallenge(challenge string) oidc.CodeVerifier {
+	return &codeVerifier{challenge: challenge}
+}
+
+// NewCodeVerifier returns the PKCE code verifier sent by the client, which is
+// used to exchange the authorization code.
+func NewCodeVerifier(verifier string) (oidc.CodeVerifier, error) {
+	v := &codeVerifier{verifier: verifier}
+	challenge, err := oidc.CreateCodeChallenge(v)
+	if err != nil {
+		return nil, err
+	}
+	v.challenge = challenge
+	return v, nil
+}
+
+func (v *codeVerifier) Verifier() string             { return v.verifier }
+func (v *codeVerifier) Challenge() string            { return v.challenge }
+func (v *codeVerifier) Method() oidc.ChallengeMethod { return oidc.S256 }
+
+func (v *codeVerifier) Copy() oidc.CodeVerifier {
+	return &codeVerifier{verifier: v.verifier, challenge: v.challenge}
+}
@@ -18,6 +18,7 @@ type CallbackServer struct {
 	ln          net.Listener
 	url         string
 	clientNonce string
+	verifier    *oidc.S256Verifier
 	errCh       chan error
 	successCh   chan *api.ACLOIDCCompleteAuthRequest
 }
@@ -32,6 +33,12 @@ func NewCallbackServer(addr string) (*CallbackServer, error) {
 		return nil, err
 	}
 
+	// Generate the PKCE code verifier of the authorization code flow.
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, err
@@ -42,6 +49,7 @@ func NewCallbackServer(addr string) (*CallbackServer, error) {
 		url:         fmt.Sprintf("http://%s/oidc/callback", addr),
 		ln:          ln,
 		clientNonce: nonce,
+		verifier:    verifier,
 		errCh:       make(chan error, 5),
 		successCh:   make(chan *api.ACLOIDCCompleteAuthRequest, 5),
 	}
@@ -69,6 +77,14 @@ func (s *CallbackServer) RedirectURI() string { return s.url }
 // Nonce returns a generated nonce that can be used for the request.
 func (s *CallbackServer) Nonce() string { return s.clientNonce }
 
+// CodeChallenge returns the PKCE code challenge that should be provided for
+// the auth URL.
+func (s *CallbackServer) CodeChallenge() string { return s.verifier.Challenge() }
+
+// CodeVerifier returns the PKCE code verifier that should be provided to
+// complete the auth.
+func (s *CallbackServer) CodeVerifier() string { return s.verifier.Verifier() }
+
 // ErrorCh returns a channel where any errors are sent. Errors may be
 // sent after Close and should be disregarded.
 func (s *CallbackServer) ErrorCh() <-chan error { return s.errCh }
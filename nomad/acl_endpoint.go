@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,11 +25,14 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/cert"
 	"github.com/hashicorp/nomad/lib/auth/jwt"
+	"github.com/hashicorp/nomad/lib/auth/ldap"
 	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/oauth2"
 )
 
 var (
@@ -687,7 +692,10 @@ func (a *ACL) upsertTokens(
 			return structs.NewErrRPCCodedf(http.StatusBadRequest, "token %d invalid: %v", idx, err)
 		}
 
-		var normalizedRoleLinks []*structs.ACLTokenRoleLink
+		var (
+			normalizedRoleLinks []*structs.ACLTokenRoleLink
+			linkedRoles         []*structs.ACLRole
+		)
 		uniqueRoleIDs := make(map[string]struct{})
 
 		// Iterate, check, and normalize the ACL role links that the token has.
@@ -728,6 +736,7 @@ func (a *ACL) upsertTokens(
 			// Deduplicate role links by their ID.
 			if _, ok := uniqueRoleIDs[roleLink.ID]; !ok {
 				normalizedRoleLinks = append(normalizedRoleLinks, roleLink)
+				linkedRoles = append(linkedRoles, existing)
 				uniqueRoleIDs[roleLink.ID] = struct{}{}
 			}
 		}
@@ -735,6 +744,20 @@ func (a *ACL) upsertTokens(
 		// Write the normalized array of ACL role links back to the token.
 		token.Roles = normalizedRoleLinks
 
+		// New tokens with an expiration TTL inherit the renewal settings of
+		// the roles they are linked to. The smallest max TTL wins.
+		if existingToken == nil && token.ExpirationTTL > 0 {
+			for _, role := range linkedRoles {
+				token.Renewable = token.Renewable || role.TokenRenewable
+			}
+			for _, role := range linkedRoles {
+				if token.Renewable && role.TokenMaxTTL > 0 &&
+					(token.MaxTTL == 0 || role.TokenMaxTTL < token.MaxTTL) {
+					token.MaxTTL = role.TokenMaxTTL
+				}
+			}
+		}
+
 		// Compute the token hash
 		token.SetHash()
 	}
@@ -848,6 +871,189 @@ func (a *ACL) DeleteTokens(args *structs.ACLTokenDeleteRequest, reply *structs.G
 	return nil
 }
 
+// RenewToken is used to renew a renewable token, extending its expiration
+// time. Tokens can renew themselves, while management tokens can renew any
+// token.
+func (a *ACL) RenewToken(args *structs.ACLTokenRenewRequest, reply *structs.ACLTokenRenewResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	authErr := a.srv.Authenticate(a.ctx, args)
+	if done, err := a.srv.forward(structs.ACLRenewTokenRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "renew_token"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), AllRegions, minACLTokenRenewVersion, false) {
+		return fmt.Errorf("all servers should be running version %v or later to renew ACL tokens",
+			minACLTokenRenewVersion)
+	}
+
+	aclObj, err := a.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+
+	// Workload identities can't be renewed, so only ACL tokens are allowed to
+	// use this endpoint.
+	caller := args.GetIdentity().GetACLToken()
+	if caller == nil {
+		return structs.ErrPermissionDenied
+	}
+	accessorID := args.AccessorID
+	if accessorID == "" {
+		accessorID = caller.AccessorID
+	}
+	if accessorID != caller.AccessorID && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	token, err := stateSnapshot.ACLTokenByAccessorID(nil, accessorID)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+	}
+	if token == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find token %s", accessorID)
+	}
+
+	// Global tokens can only be modified within the authoritative region,
+	// from where the renewal is replicated.
+	if token.Global && a.srv.config.Region != a.srv.config.AuthoritativeRegion {
+		args.Region = a.srv.config.AuthoritativeRegion
+		_, err := a.srv.forward(structs.ACLRenewTokenRPCMethod, args, args, reply)
+		return err
+	}
+
+	expirationTime, err := token.RenewedExpirationTime(time.Now(), a.srv.config.ACLTokenMaxExpirationTTL)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot renew token %s: %v", accessorID, err)
+	}
+
+	renewed := token.Copy()
+	renewed.ExpirationTime = &expirationTime
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, &structs.ACLTokenUpsertRequest{
+		Tokens:       []*structs.ACLToken{renewed},
+		WriteRequest: args.WriteRequest,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper modify index.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	reply.Token, err = stateSnapshot.ACLTokenByAccessorID(nil, accessorID)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+	}
+	reply.Index = index
+	return nil
+}
+
+// DeriveTaskToken is used by clients to create the ACL token requested by the
+// nomad block of a task. The token is local to the region, linked to the roles
+// of the block, and revoked once the allocation stops. Only the client running
+// the allocation can derive its tokens.
+func (a *ACL) DeriveTaskToken(args *structs.ACLDeriveTaskTokenRequest, reply *structs.ACLDeriveTaskTokenResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	aclObj, authErr := a.srv.AuthenticateClientOnly(a.ctx, args)
+	if done, err := a.srv.forward(structs.ACLDeriveTaskTokenRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricWrite, args)
+	if authErr != nil || !aclObj.AllowClientOp() {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "derive_task_token"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), a.srv.Region(), minACLTaskTokenVersion, false) {
+		return fmt.Errorf("all servers should be running version %v or later to derive task ACL tokens",
+			minACLTaskTokenVersion)
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid request: %v", err)
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := stateSnapshot.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "alloc lookup failed: %v", err)
+	}
+	if alloc == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find allocation %s", args.AllocID)
+	}
+	if alloc.NodeID != args.GetIdentity().ClientID {
+		return structs.ErrPermissionDenied
+	}
+	if alloc.Terminated() {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "allocation %s is terminal", alloc.ID)
+	}
+
+	task := alloc.LookupTask(args.Task)
+	if task == nil || task.Nomad == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"task %q of allocation %s does not request a token", args.Task, alloc.ID)
+	}
+
+	// The roles were checked when the job was registered, but may have been
+	// deleted since. Never link the token to a role created after that.
+	roles := make([]*structs.ACLTokenRoleLink, 0, len(task.Nomad.Roles))
+	for _, name := range task.Nomad.Roles {
+		role, err := stateSnapshot.GetACLRoleByName(nil, name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "role lookup failed: %v", err)
+		}
+		if role == nil || role.CreateIndex > alloc.Job.JobModifyIndex {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest,
+				"role %q requested by task %q does not exist", name, task.Name)
+		}
+		roles = append(roles, &structs.ACLTokenRoleLink{ID: role.ID})
+	}
+
+	tokenUpsertRequest := structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{{
+			Name:          fmt.Sprintf("%s/%s/%s alloc %s", alloc.JobID, alloc.TaskGroup, task.Name, alloc.ID[:8]),
+			Type:          structs.ACLClientToken,
+			Roles:         roles,
+			ExpirationTTL: task.Nomad.TTL,
+			Renewable:     true,
+			AllocID:       alloc.ID,
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    a.srv.Region(),
+			AuthToken: a.srv.getLeaderAcl(),
+		},
+	}
+
+	var tokenUpsertReply structs.ACLTokenUpsertResponse
+	if err := a.upsertTokens(&tokenUpsertRequest, &tokenUpsertReply, stateSnapshot); err != nil {
+		return err
+	}
+
+	reply.Token = tokenUpsertReply.Tokens[0]
+	reply.Index = tokenUpsertReply.Index
+	return nil
+}
+
 // ListTokens is used to list the tokens
 func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTokenListResponse) error {
 	if !a.srv.config.ACLEnabled {
@@ -1892,6 +2098,19 @@ func (a *ACL) UpsertAuthMethods(
 		existingMethod, _ := stateSnapshot.GetACLAuthMethodByName(nil, authMethod.Name)
 		authMethod.Merge(existingMethod)
 
+		// LDAP and cert auth methods can only be used once all servers in all
+		// federated regions have been upgraded to support them.
+		if authMethod.Type == structs.ACLAuthMethodTypeLDAP &&
+			!ServersMeetMinimumVersion(a.srv.Members(), AllRegions, minACLLDAPAuthMethodVersion, false) {
+			return fmt.Errorf("all servers should be running version %v or later to use LDAP ACL auth methods",
+				minACLLDAPAuthMethodVersion)
+		}
+		if authMethod.Type == structs.ACLAuthMethodTypeCert &&
+			!ServersMeetMinimumVersion(a.srv.Members(), AllRegions, minACLCertAuthMethodVersion, false) {
+			return fmt.Errorf("all servers should be running version %v or later to use cert ACL auth methods",
+				minACLCertAuthMethodVersion)
+		}
+
 		if err := authMethod.Validate(
 			a.srv.config.ACLTokenMinExpirationTTL,
 			a.srv.config.ACLTokenMaxExpirationTTL); err != nil {
@@ -2194,6 +2413,188 @@ func (a *ACL) WhoAmI(args *structs.GenericRequest, reply *structs.ACLWhoAmIRespo
 	return nil
 }
 
+// Explain returns the ACL decision of a token for an operation, along with the
+// policy rules that produced it and how each policy came to be attached to the
+// token. Callers can explain their own token, while explaining any other token
+// requires a management token.
+func (a *ACL) Explain(args *structs.ACLExplainRequest, reply *structs.ACLExplainResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	authErr := a.srv.Authenticate(a.ctx, args)
+	if done, err := a.srv.forward(structs.ACLExplainRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "explain"}, time.Now())
+
+	if err := args.Operation.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid operation: %v", err)
+	}
+
+	aclObj, err := a.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+
+	// Workload identities don't have policies that can be explained, so only
+	// ACL tokens are allowed to use this endpoint.
+	caller := args.GetIdentity().GetACLToken()
+	if caller == nil {
+		return structs.ErrPermissionDenied
+	}
+	if args.AccessorID != "" && args.AccessorID != caller.AccessorID && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+			reply.Explanation = nil
+
+			token := caller
+			if args.AccessorID != "" && args.AccessorID != caller.AccessorID {
+				token, err = stateStore.ACLTokenByAccessorID(ws, args.AccessorID)
+				if err != nil {
+					return err
+				}
+			}
+
+			if token != nil {
+				explanation, err := explainACLToken(ws, stateStore, token, args.Operation)
+				if err != nil {
+					return err
+				}
+				reply.Explanation = explanation
+			}
+
+			// The explanation depends on tokens, policies, roles and binding
+			// rules, so use the highest index of their tables.
+			for _, table := range []string{
+				"acl_token",
+				"acl_policy",
+				state.TableACLRoles,
+				state.TableACLBindingRules,
+			} {
+				index, err := stateStore.Index(table)
+				if err != nil {
+					return err
+				}
+				reply.Index = max(reply.Index, index)
+			}
+			a.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		},
+	})
+}
+
+// explainACLToken returns the ACL decision of the token for the operation,
+// using the policies, roles and binding rules currently held in state.
+func explainACLToken(ws memdb.WatchSet, stateStore *state.StateStore,
+	token *structs.ACLToken, op *policy.Operation) (*structs.ACLExplanation, error) {
+
+	out := &structs.ACLExplanation{
+		AccessorID: token.AccessorID,
+		Name:       token.Name,
+		Type:       token.Type,
+		Expired:    token.IsExpired(time.Now().UTC()),
+	}
+	if token.Type == structs.ACLManagementToken {
+		out.Management = true
+		out.Allowed = !out.Expired
+		return out, nil
+	}
+
+	// Track how each policy came to be attached to the token, either directly
+	// or through one of its roles.
+	provenance := make(map[string]*structs.ACLExplanationPolicy)
+	policyProvenance := func(name string) *structs.ACLExplanationPolicy {
+		p, ok := provenance[name]
+		if !ok {
+			p = &structs.ACLExplanationPolicy{Name: name}
+			provenance[name] = p
+		}
+		return p
+	}
+	for _, name := range token.Policies {
+		policyProvenance(name).Token = true
+	}
+	for _, link := range token.Roles {
+		role, err := stateStore.GetACLRoleByID(ws, link.ID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		for _, policyLink := range role.Policies {
+			p := policyProvenance(policyLink.Name)
+			p.Roles = append(p.Roles, role.Name)
+		}
+	}
+
+	// Binding rules are matched on their bind name, so rules that use a
+	// templated bind name can't be attributed.
+	iter, err := stateStore.GetACLBindingRules(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypePolicy:
+			if p, ok := provenance[rule.BindName]; ok {
+				p.BindingRules = append(p.BindingRules, rule.ID)
+			}
+		case structs.ACLBindingRuleBindTypeRole:
+			for _, p := range provenance {
+				if slices.Contains(p.Roles, rule.BindName) {
+					p.BindingRules = append(p.BindingRules, rule.ID)
+				}
+			}
+		}
+	}
+
+	policies := make(map[string]*policy.Policy, len(provenance))
+	for name, p := range provenance {
+		aclPolicy, err := stateStore.ACLPolicyByName(ws, name)
+		if err != nil {
+			return nil, err
+		}
+		if aclPolicy == nil {
+			p.Missing = true
+			continue
+		}
+		parsed, err := policy.Parse(aclPolicy.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %v", name, err)
+		}
+		policies[name] = parsed
+		out.Policies = append(out.Policies, p)
+	}
+
+	explanation, err := policy.Explain(op, policies)
+	if err != nil {
+		return nil, err
+	}
+	out.Allowed = explanation.Allowed && !out.Expired
+	out.Rules = explanation.Rules
+
+	for _, p := range provenance {
+		if p.Missing {
+			out.Policies = append(out.Policies, p)
+		}
+	}
+	sort.Slice(out.Policies, func(i, j int) bool {
+		return out.Policies[i].Name < out.Policies[j].Name
+	})
+	return out, nil
+}
+
 // UpsertBindingRules creates or updates ACL binding rules held within Nomad.
 func (a *ACL) UpsertBindingRules(
 	args *structs.ACLBindingRulesUpsertRequest, reply *structs.ACLBindingRulesUpsertResponse) error {
@@ -2611,6 +3012,15 @@ func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.AC
 		oidcReqOpts = append(oidcReqOpts, capOIDC.WithScopes(authMethod.Config.OIDCScopes...))
 	}
 
+	// Use PKCE if the client sent a code challenge, which is required if the
+	// auth method enables it.
+	if args.CodeChallenge != "" {
+		oidcReqOpts = append(oidcReqOpts, capOIDC.WithPKCE(oidc.NewCodeChallenge(args.CodeChallenge)))
+	} else if authMethod.Config.OIDCEnablePKCE {
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			"invalid OIDC auth-url request: auth method requires a PKCE code challenge")
+	}
+
 	oidcReq, err := capOIDC.NewRequest(
 		aclOIDCAuthURLRequestExpiryTime,
 		args.RedirectURI,
@@ -2643,6 +3053,84 @@ func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.AC
 	return nil
 }
 
+// OIDCDeviceAuth starts the OIDC device authorization workflow. The user
+// should visit the verification URI of the response and enter the user code,
+// while the caller polls OIDCCompleteAuth with the device code until the
+// authorization has been completed.
+func (a *ACL) OIDCDeviceAuth(args *structs.ACLOIDCDeviceAuthRequest, reply *structs.ACLOIDCDeviceAuthResponse) error {
+
+	// The OIDC flow can only be used when the Nomad cluster has ACL enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Perform the initial forwarding within the region. This ensures we
+	// respect stale queries.
+	if done, err := a.srv.forward(structs.ACLOIDCDeviceAuthRPCMethod, args, args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_device_auth"}, time.Now())
+
+	if !ServersMeetMinimumVersion(a.srv.Members(), AllRegions, minACLOIDCDeviceAuthVersion, false) {
+		return fmt.Errorf("all servers should be running version %v or later to use the OIDC device authorization flow",
+			minACLOIDCDeviceAuthVersion)
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC device-auth request: %v", err)
+	}
+
+	// Grab a snapshot of the state, so we can query it safely.
+	stateSnapshot, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	authMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+	if authMethod == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth-method %q not found", args.AuthMethodName)
+	}
+	if authMethod.Type != structs.ACLAuthMethodTypeOIDC {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth-method %q is not an OIDC auth method", args.AuthMethodName)
+	}
+
+	// If the authentication method generates global ACL tokens, we need to
+	// forward the request onto the authoritative regional leader, which will
+	// also complete the authorization.
+	if authMethod.TokenLocalityIsGlobal() {
+		args.Region = a.srv.config.AuthoritativeRegion
+
+		if done, err := a.srv.forward(structs.ACLOIDCDeviceAuthRPCMethod, args, args, reply); done {
+			return err
+		}
+	}
+
+	oidcProvider, err := a.oidcProviderCache.Get(authMethod)
+	if err != nil {
+		return fmt.Errorf("failed to generate OIDC provider: %v", err)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(aclOIDCAuthURLRequestExpiryTime))
+	defer cancel()
+
+	deviceAuth, err := oidc.StartDeviceAuth(ctx, oidcProvider, authMethod)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to start device authorization: %v", err)
+	}
+
+	reply.DeviceCode = deviceAuth.DeviceCode
+	reply.UserCode = deviceAuth.UserCode
+	reply.VerificationURI = deviceAuth.VerificationURI
+	reply.VerificationURIComplete = deviceAuth.VerificationURIComplete
+	reply.Expiry = deviceAuth.Expiry
+	reply.Interval = deviceAuth.Interval
+	return nil
+}
+
 // OIDCCompleteAuth complete the OIDC login workflow. It will exchange the OIDC
 // provider token for a Nomad ACL token, using the configured ACL role and
 // policy claims to provide authorization.
@@ -2708,49 +3196,38 @@ func (a *ACL) OIDCCompleteAuth(
 		return fmt.Errorf("failed to generate OIDC provider: %v", err)
 	}
 
-	// Build our OIDC request options and request object.
-	oidcReqOpts := []capOIDC.Option{
-		capOIDC.WithNonce(args.ClientNonce),
-		capOIDC.WithState(args.State),
-	}
-
-	if len(authMethod.Config.OIDCScopes) > 0 {
-		oidcReqOpts = append(oidcReqOpts, capOIDC.WithScopes(authMethod.Config.OIDCScopes...))
-	}
-	if len(authMethod.Config.BoundAudiences) > 0 {
-		oidcReqOpts = append(oidcReqOpts, capOIDC.WithAudiences(authMethod.Config.BoundAudiences...))
-	}
-
-	oidcReq, err := capOIDC.NewRequest(aclOIDCCallbackRequestExpiryTime, args.RedirectURI, oidcReqOpts...)
-	if err != nil {
-		return fmt.Errorf("failed to generate OIDC request: %v", err)
-	}
-
 	// Generate a context with a deadline. This is passed to the OIDC provider
 	// and used when making remote HTTP requests.
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(aclOIDCCallbackRequestExpiryTime))
 	defer cancel()
 
-	// Exchange the state and code for an OIDC provider token.
-	oidcToken, err := oidcProvider.Exchange(ctx, oidcReq, args.State, args.Code)
-	if err != nil {
-		return fmt.Errorf("failed to exchange token with provider: %v", err)
-	}
-	if !oidcToken.Valid() {
-		return errors.New("exchanged token is not valid; potentially expired or empty")
-	}
-
-	var idTokenClaims map[string]interface{}
-	if err := oidcToken.IDToken().Claims(&idTokenClaims); err != nil {
-		return fmt.Errorf("failed to retrieve the ID token claims: %v", err)
+	// Get the claims of the user either from the completed device
+	// authorization, or by exchanging the authorization code.
+	var (
+		idTokenClaims   map[string]interface{}
+		userTokenSource oauth2.TokenSource
+	)
+	if args.DeviceCode != "" {
+		idTokenClaims, userTokenSource, err = oidc.CompleteDeviceAuth(ctx, oidcProvider, authMethod, args.DeviceCode)
+		switch {
+		case errors.Is(err, oidc.ErrDeviceAuthPending):
+			return structs.NewErrRPCCoded(http.StatusBadRequest, structs.ErrOIDCDeviceAuthPending.Error())
+		case errors.Is(err, oidc.ErrDeviceAuthSlowDown):
+			return structs.NewErrRPCCoded(http.StatusBadRequest, structs.ErrOIDCDeviceAuthSlowDown.Error())
+		case err != nil:
+			return fmt.Errorf("failed to complete device authorization with provider: %v", err)
+		}
+	} else {
+		idTokenClaims, userTokenSource, err = a.oidcExchange(ctx, args, authMethod, oidcProvider)
+		if err != nil {
+			return err
+		}
 	}
 
 	var userClaims map[string]interface{}
-	if !authMethod.Config.OIDCDisableUserInfo {
-		if userTokenSource := oidcToken.StaticTokenSource(); userTokenSource != nil {
-			if err := oidcProvider.UserInfo(ctx, userTokenSource, idTokenClaims["sub"].(string), &userClaims); err != nil {
-				return fmt.Errorf("failed to retrieve the user info claims: %v", err)
-			}
+	if !authMethod.Config.OIDCDisableUserInfo && userTokenSource != nil {
+		if err := oidcProvider.UserInfo(ctx, userTokenSource, idTokenClaims["sub"].(string), &userClaims); err != nil {
+			return fmt.Errorf("failed to retrieve the user info claims: %v", err)
 		}
 	}
 
@@ -2785,11 +3262,7 @@ func (a *ACL) OIDCCompleteAuth(
 		return err
 	}
 
-	token := structs.ACLToken{
-		Name:          name,
-		Global:        authMethod.TokenLocalityIsGlobal(),
-		ExpirationTTL: authMethod.MaxTokenTTL,
-	}
+	token := authMethod.NewToken(name)
 
 	if tokenBindings.Management {
 		token.Type = structs.ACLManagementToken
@@ -2800,7 +3273,7 @@ func (a *ACL) OIDCCompleteAuth(
 	}
 
 	tokenUpsertRequest := structs.ACLTokenUpsertRequest{
-		Tokens: []*structs.ACLToken{&token},
+		Tokens: []*structs.ACLToken{token},
 		WriteRequest: structs.WriteRequest{
 			Region:    a.srv.Region(),
 			AuthToken: a.srv.getLeaderAcl(),
@@ -2820,6 +3293,87 @@ func (a *ACL) OIDCCompleteAuth(
 	return nil
 }
 
+// oidcExchange exchanges the authorization code of the OIDC complete-auth
+// request for an OIDC provider token. It returns the claims of the ID token,
+// and a token source which can be used to query the user info.
+func (a *ACL) oidcExchange(
+	ctx context.Context,
+	args *structs.ACLOIDCCompleteAuthRequest,
+	authMethod *structs.ACLAuthMethod,
+	oidcProvider *capOIDC.Provider,
+) (map[string]interface{}, oauth2.TokenSource, error) {
+
+	// Build our OIDC request options and request object.
+	oidcReqOpts := []capOIDC.Option{
+		capOIDC.WithNonce(args.ClientNonce),
+		capOIDC.WithState(args.State),
+	}
+
+	if len(authMethod.Config.OIDCScopes) > 0 {
+		oidcReqOpts = append(oidcReqOpts, capOIDC.WithScopes(authMethod.Config.OIDCScopes...))
+	}
+	if len(authMethod.Config.BoundAudiences) > 0 {
+		oidcReqOpts = append(oidcReqOpts, capOIDC.WithAudiences(authMethod.Config.BoundAudiences...))
+	}
+
+	// Send the PKCE code verifier of the challenge used to generate the auth
+	// URL, which is required if the auth method enables PKCE.
+	if args.CodeVerifier != "" {
+		verifier, err := oidc.NewCodeVerifier(args.CodeVerifier)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate PKCE code verifier: %v", err)
+		}
+		oidcReqOpts = append(oidcReqOpts, capOIDC.WithPKCE(verifier))
+	} else if authMethod.Config.OIDCEnablePKCE {
+		return nil, nil, structs.NewErrRPCCoded(http.StatusBadRequest,
+			"invalid OIDC complete-auth request: auth method requires a PKCE code verifier")
+	}
+
+	oidcReq, err := capOIDC.NewRequest(aclOIDCCallbackRequestExpiryTime, args.RedirectURI, oidcReqOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate OIDC request: %v", err)
+	}
+
+	// Exchange the state and code for an OIDC provider token.
+	oidcToken, err := oidcProvider.Exchange(ctx, oidcReq, args.State, args.Code)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to exchange token with provider: %v", err)
+	}
+	if !oidcToken.Valid() {
+		return nil, nil, errors.New("exchanged token is not valid; potentially expired or empty")
+	}
+
+	var idTokenClaims map[string]interface{}
+	if err := oidcToken.IDToken().Claims(&idTokenClaims); err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve the ID token claims: %v", err)
+	}
+	return idTokenClaims, oidcToken.StaticTokenSource(), nil
+}
+
+// validateCertLoginPeer ensures a login request carrying client certificates
+// was sent by a server, either in-process from the server agent's HTTP API or
+// forwarded over RPC by another server. Any agent holding a certificate
+// signed by the cluster CA can open an RPC connection, so accepting the
+// certificates from client agents would allow them to log in with a
+// certificate chain they don't hold the private key for.
+func (a *ACL) validateCertLoginPeer() error {
+	if a.ctx.IsStatic() {
+		return nil
+	}
+
+	if cert := a.ctx.Certificate(); cert != nil {
+		names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+		for _, name := range names {
+			if strings.HasPrefix(name, "server.") && strings.HasSuffix(name, ".nomad") {
+				return nil
+			}
+		}
+	}
+
+	return structs.NewErrRPCCoded(http.StatusForbidden,
+		"cert auth method logins must be sent to the HTTP API of a server agent")
+}
+
 // Login RPC performs non-interactive auth using a given AuthMethod. This method
 // can not be used for OIDC login flow.
 func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
@@ -2829,6 +3383,15 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 		return aclDisabled
 	}
 
+	// Client certificates are only trusted when they were read from the TLS
+	// connection of the HTTP request by a server agent. This must be checked
+	// before forwarding, since the next hop only sees the forwarding server.
+	if len(args.ClientCertificates) > 0 {
+		if err := a.validateCertLoginPeer(); err != nil {
+			return err
+		}
+	}
+
 	// Perform the initial forwarding within the region. This ensures we
 	// respect stale queries.
 	if done, err := a.srv.forward(structs.ACLLoginRPCMethod, args, args, reply); done {
@@ -2894,6 +3457,9 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 	// Validate the token depending on its method type
 	switch authMethod.Type {
 	case structs.ACLAuthMethodTypeJWT:
+		if args.LoginToken == "" {
+			return structs.NewErrRPCCoded(http.StatusBadRequest, "invalid login request: missing login token")
+		}
 		claims, err = jwt.Validate(ctx, args.LoginToken, authMethod.Config)
 		if err != nil {
 			return structs.NewErrRPCCodedf(
@@ -2902,6 +3468,49 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 				err,
 			)
 		}
+	case structs.ACLAuthMethodTypeLDAP:
+		if !ServersMeetMinimumVersion(a.srv.Members(), AllRegions, minACLLDAPAuthMethodVersion, false) {
+			return fmt.Errorf("all servers should be running version %v or later to use LDAP ACL auth methods",
+				minACLLDAPAuthMethodVersion)
+		}
+		if args.Username == "" {
+			return structs.NewErrRPCCoded(http.StatusBadRequest, "invalid login request: missing username")
+		}
+		claims, err = ldap.Authenticate(ctx, args.Username, args.Password, authMethod.Config)
+		if err != nil {
+			return structs.NewErrRPCCodedf(
+				http.StatusUnauthorized,
+				"unable to authenticate with LDAP: %v",
+				err,
+			)
+		}
+	case structs.ACLAuthMethodTypeCert:
+		if !ServersMeetMinimumVersion(a.srv.Members(), AllRegions, minACLCertAuthMethodVersion, false) {
+			return fmt.Errorf("all servers should be running version %v or later to use cert ACL auth methods",
+				minACLCertAuthMethodVersion)
+		}
+
+		// The client certificate is passed along by the agent which terminated
+		// the TLS connection of the HTTP request. Only trust it if RPC
+		// connections are themselves authenticated with mTLS, so the request
+		// can only have come from a Nomad agent.
+		tlsConfig := a.srv.config.TLSConfig
+		if tlsConfig == nil || !tlsConfig.EnableRPC || tlsConfig.RPCUpgradeMode {
+			return structs.NewErrRPCCoded(http.StatusBadRequest,
+				"cert auth methods require mTLS to be enabled for RPC")
+		}
+		if len(args.ClientCertificates) == 0 {
+			return structs.NewErrRPCCoded(http.StatusBadRequest,
+				"invalid login request: missing client certificate")
+		}
+		claims, err = cert.Validate(args.ClientCertificates, authMethod.Config)
+		if err != nil {
+			return structs.NewErrRPCCodedf(
+				http.StatusUnauthorized,
+				"unable to authenticate with client certificate: %v",
+				err,
+			)
+		}
 	default:
 		return structs.NewErrRPCCodedf(
 			http.StatusBadRequest,
@@ -2933,16 +3542,12 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 	// logic, so we do not want to call Raft directly or copy that here. In the
 	// future we should try and extract out the logic into an interface, or at
 	// least a separate function.
-	name, err := formatTokenName(authMethod.TokenNameFormat, structs.ACLAuthMethodTypeJWT, authMethod.Name, jwtClaims.Value)
+	name, err := formatTokenName(authMethod.TokenNameFormat, authMethod.Type, authMethod.Name, jwtClaims.Value)
 	if err != nil {
 		return err
 	}
 
-	token := structs.ACLToken{
-		Name:          name,
-		Global:        authMethod.TokenLocalityIsGlobal(),
-		ExpirationTTL: authMethod.MaxTokenTTL,
-	}
+	token := authMethod.NewToken(name)
 
 	if tokenBindings.Management {
 		token.Type = structs.ACLManagementToken
@@ -2953,7 +3558,7 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 	}
 
 	tokenUpsertRequest := structs.ACLTokenUpsertRequest{
-		Tokens: []*structs.ACLToken{&token},
+		Tokens: []*structs.ACLToken{token},
 		WriteRequest: structs.WriteRequest{
 			Region:    a.srv.Region(),
 			AuthToken: a.srv.getLeaderAcl(),
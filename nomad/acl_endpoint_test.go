@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net/url"
 	"os"
@@ -15,11 +16,17 @@ import (
 	capOIDC "github.com/hashicorp/cap/oidc"
 	"github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1448,6 +1455,242 @@ func TestACLEndpoint_DeleteTokens_WithNonexistentToken(t *testing.T) {
 	assert.Contains(err.Error(), expectedError)
 }
 
+func TestACLEndpoint_RenewToken(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a renewable token which expires in an hour, a non-renewable
+	// token, and a token which has no relation to either.
+	renewable := mock.ACLToken()
+	renewable.ExpirationTTL = time.Hour
+	renewable.ExpirationTime = pointer.Of(renewable.CreateTime.Add(time.Hour))
+	renewable.Renewable = true
+	renewable.MaxTTL = 2 * time.Hour
+
+	nonRenewable := mock.ACLToken()
+	nonRenewable.ExpirationTTL = time.Hour
+	nonRenewable.ExpirationTime = pointer.Of(nonRenewable.CreateTime.Add(time.Hour))
+
+	other := mock.ACLToken()
+
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLToken{renewable, nonRenewable, other}))
+
+	renew := func(accessorID, authToken string) (*structs.ACLTokenRenewResponse, error) {
+		req := &structs.ACLTokenRenewRequest{
+			AccessorID: accessorID,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: authToken,
+			},
+		}
+		var resp structs.ACLTokenRenewResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLRenewTokenRPCMethod, req, &resp)
+		return &resp, err
+	}
+
+	// A token can renew itself, without specifying its accessor ID.
+	resp, err := renew("", renewable.SecretID)
+	must.NoError(t, err)
+	must.NotNil(t, resp.Token)
+	must.Eq(t, renewable.AccessorID, resp.Token.AccessorID)
+	must.True(t, resp.Token.ExpirationTime.After(*renewable.ExpirationTime))
+	must.False(t, resp.Token.ExpirationTime.After(renewable.CreateTime.Add(2*time.Hour)))
+	must.NonZero(t, resp.Index)
+
+	// Only management tokens can renew other tokens.
+	_, err = renew(renewable.AccessorID, other.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	resp, err = renew(renewable.AccessorID, root.SecretID)
+	must.NoError(t, err)
+	must.Eq(t, renewable.AccessorID, resp.Token.AccessorID)
+
+	// Tokens which are not renewable can't be renewed.
+	_, err = renew("", nonRenewable.SecretID)
+	must.ErrorContains(t, err, "token is not renewable")
+
+	_, err = renew("", other.SecretID)
+	must.ErrorContains(t, err, "token is not renewable")
+
+	// Unknown tokens can't be renewed.
+	_, err = renew(uuid.Generate(), root.SecretID)
+	must.ErrorContains(t, err, "cannot find token")
+}
+
+func TestACLEndpoint_UpsertTokens_RoleRenewable(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	policy := mock.ACLPolicy()
+	must.NoError(t, s1.fsm.State().UpsertACLPolicies(structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy}))
+
+	role1 := mock.ACLRole()
+	role1.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	role1.TokenRenewable = true
+	role1.TokenMaxTTL = 4 * time.Hour
+
+	role2 := mock.ACLRole()
+	role2.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	role2.TokenMaxTTL = 2 * time.Hour
+
+	must.NoError(t, s1.fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 20,
+		[]*structs.ACLRole{role1, role2}, false))
+
+	upsert := func(token *structs.ACLToken) *structs.ACLToken {
+		req := &structs.ACLTokenUpsertRequest{
+			Tokens: []*structs.ACLToken{token},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.ACLTokenUpsertResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, req, &resp))
+		must.Len(t, 1, resp.Tokens)
+		return resp.Tokens[0]
+	}
+
+	// An expiring token linked to a renewable role is renewable, and uses the
+	// smallest max TTL of its roles.
+	token := upsert(&structs.ACLToken{
+		Name:          "renewable",
+		Type:          structs.ACLClientToken,
+		Roles:         []*structs.ACLTokenRoleLink{{ID: role1.ID}, {ID: role2.ID}},
+		ExpirationTTL: time.Hour,
+	})
+	must.True(t, token.Renewable)
+	must.Eq(t, 2*time.Hour, token.MaxTTL)
+
+	// Tokens which don't expire can't be renewable.
+	token = upsert(&structs.ACLToken{
+		Name:  "non-expiring",
+		Type:  structs.ACLClientToken,
+		Roles: []*structs.ACLTokenRoleLink{{ID: role1.ID}},
+	})
+	must.False(t, token.Renewable)
+	must.Zero(t, token.MaxTTL)
+}
+
+func TestACLEndpoint_DeriveTaskToken(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	policy := mock.ACLPolicy()
+	must.NoError(t, s1.fsm.State().UpsertACLPolicies(structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy}))
+
+	role := mock.ACLRole()
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	must.NoError(t, s1.fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 20, []*structs.ACLRole{role}, false))
+
+	node := mock.Node()
+	otherNode := mock.Node()
+	must.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 30, node))
+	must.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 31, otherNode))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Nomad = &structs.Nomad{
+		Roles:      []string{role.Name},
+		TTL:        30 * time.Minute,
+		ChangeMode: structs.NomadChangeModeRestart,
+	}
+	lateTask := task.Copy()
+	lateTask.Name = "late"
+	lateTask.Nomad.Roles = []string{"late-role"}
+	alloc.Job.TaskGroups[0].Tasks = append(alloc.Job.TaskGroups[0].Tasks, lateTask)
+	alloc.Job.JobModifyIndex = 35
+	must.NoError(t, s1.fsm.State().UpsertJobSummary(39, mock.JobSummary(alloc.JobID)))
+	must.NoError(t, s1.fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 40, []*structs.Allocation{alloc}))
+
+	derive := func(taskName, authToken string) (*structs.ACLDeriveTaskTokenResponse, error) {
+		req := &structs.ACLDeriveTaskTokenRequest{
+			AllocID: alloc.ID,
+			Task:    taskName,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: authToken,
+			},
+		}
+		var resp structs.ACLDeriveTaskTokenResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLDeriveTaskTokenRPCMethod, req, &resp)
+		return &resp, err
+	}
+
+	// Only the node running the allocation can derive a token.
+	_, err := derive(task.Name, root.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	_, err = derive(task.Name, otherNode.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Tasks without a nomad block can't derive a token.
+	_, err = derive("unknown", node.SecretID)
+	must.ErrorContains(t, err, "does not request a token")
+
+	// Roles that don't exist, or that were created after the job was
+	// registered and so weren't checked against the submitting token, are
+	// never linked to a token.
+	_, err = derive(lateTask.Name, node.SecretID)
+	must.ErrorContains(t, err, `role "late-role" requested by task "late" does not exist`)
+
+	lateRole := mock.ACLRole()
+	lateRole.Name = "late-role"
+	lateRole.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	must.NoError(t, s1.fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 45, []*structs.ACLRole{lateRole}, false))
+	_, err = derive(lateTask.Name, node.SecretID)
+	must.ErrorContains(t, err, `role "late-role" requested by task "late" does not exist`)
+
+	resp, err := derive(task.Name, node.SecretID)
+	must.NoError(t, err)
+	must.NotNil(t, resp.Token)
+	must.NonZero(t, resp.Index)
+	must.Eq(t, alloc.ID, resp.Token.AllocID)
+	must.Eq(t, structs.ACLClientToken, resp.Token.Type)
+	must.True(t, resp.Token.Renewable)
+	must.Eq(t, 30*time.Minute, resp.Token.ExpirationTTL)
+	must.Len(t, 1, resp.Token.Roles)
+	must.Eq(t, role.ID, resp.Token.Roles[0].ID)
+
+	tokens, err := s1.fsm.State().ACLTokensByAllocID(nil, alloc.ID)
+	must.NoError(t, err)
+	must.Len(t, 1, tokens)
+
+	// The token is revoked once the allocation is terminal on the client.
+	clientAlloc := alloc.Copy()
+	clientAlloc.ClientStatus = structs.AllocClientStatusComplete
+	update := &structs.AllocUpdateRequest{
+		Alloc: []*structs.Allocation{clientAlloc},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var updateResp structs.NodeAllocsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAlloc", update, &updateResp))
+
+	tokens, err = s1.fsm.State().ACLTokensByAllocID(nil, alloc.ID)
+	must.NoError(t, err)
+	must.Len(t, 0, tokens)
+
+	// Terminal allocations can't derive new tokens.
+	_, err = derive(task.Name, node.SecretID)
+	must.ErrorContains(t, err, "is terminal")
+}
+
 func TestACLEndpoint_Bootstrap(t *testing.T) {
 	ci.Parallel(t)
 	s1, cleanupS1 := TestServer(t, func(c *Config) {
@@ -1958,6 +2201,136 @@ func TestACLEndpoint_WhoAmI(t *testing.T) {
 	must.Eq(t, alloc.ID, resp3.Identity.Claims.AllocationID)
 }
 
+func TestACLEndpoint_Explain(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// Create a policy linked directly to the token and one linked through a
+	// role that is bound by a binding rule.
+	readPolicy := &structs.ACLPolicy{
+		Name:  "read-default",
+		Rules: `namespace "default" { policy = "read" }`,
+	}
+	readPolicy.SetHash()
+	deployPolicy := &structs.ACLPolicy{
+		Name: "deploy-payments",
+		Rules: `namespace "default" {
+  job "payments-*" { capabilities = ["submit-job"] }
+}`,
+	}
+	deployPolicy.SetHash()
+	must.NoError(t, store.UpsertACLPolicies(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLPolicy{readPolicy, deployPolicy}))
+
+	role := mock.ACLRole()
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: deployPolicy.Name}}
+	must.NoError(t, store.UpsertACLRoles(structs.MsgTypeTestSetup, 1010,
+		[]*structs.ACLRole{role}, false))
+
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.BindName = role.Name
+	must.NoError(t, store.UpsertACLBindingRules(1020,
+		[]*structs.ACLBindingRule{bindingRule}, true))
+
+	token := mock.ACLToken()
+	token.Policies = []string{readPolicy.Name, "missing"}
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: role.ID}}
+	otherToken := mock.ACLToken()
+	must.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 1030,
+		[]*structs.ACLToken{token, otherToken}))
+
+	req := &structs.ACLExplainRequest{
+		Operation: &acl.Operation{
+			Type:       acl.OperationTypeNamespace,
+			Namespace:  "default",
+			JobID:      "payments-api",
+			Capability: acl.NamespaceCapabilitySubmitJob,
+		},
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+
+	// Explain the caller's own token.
+	var resp structs.ACLExplainResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp))
+	must.Eq(t, 1030, resp.Index)
+	must.NotNil(t, resp.Explanation)
+	must.Eq(t, token.AccessorID, resp.Explanation.AccessorID)
+	must.True(t, resp.Explanation.Allowed)
+	must.Eq(t, []*acl.ExplainedRule{
+		{
+			Policy:       deployPolicy.Name,
+			Rule:         `namespace "default" job "payments-*"`,
+			Capabilities: []string{acl.NamespaceCapabilitySubmitJob},
+			Allowed:      true,
+		},
+		{
+			Policy:       deployPolicy.Name,
+			Rule:         `namespace "default"`,
+			Capabilities: []string{},
+		},
+		{
+			Policy: readPolicy.Name,
+			Rule:   `namespace "default"`,
+			Capabilities: []string{
+				acl.NamespaceCapabilityCSIListVolume,
+				acl.NamespaceCapabilityCSIReadVolume,
+				acl.NamespaceCapabilityListJobs,
+				acl.NamespaceCapabilityListScalingPolicies,
+				acl.NamespaceCapabilityParseJob,
+				acl.NamespaceCapabilityReadJob,
+				acl.NamespaceCapabilityReadJobScaling,
+				acl.NamespaceCapabilityReadScalingPolicy,
+			},
+		},
+	}, resp.Explanation.Rules)
+	must.Eq(t, []*structs.ACLExplanationPolicy{
+		{
+			Name:         deployPolicy.Name,
+			Roles:        []string{role.Name},
+			BindingRules: []string{bindingRule.ID},
+		},
+		{Name: "missing", Token: true, Missing: true},
+		{Name: readPolicy.Name, Token: true},
+	}, resp.Explanation.Policies)
+
+	// A job outside of the job rule is denied.
+	req.Operation.JobID = "web"
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp))
+	must.False(t, resp.Explanation.Allowed)
+	must.Len(t, 2, resp.Explanation.Rules)
+
+	// Only management tokens can explain other tokens.
+	req.AccessorID = otherToken.AccessorID
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = root.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp))
+	must.Eq(t, otherToken.AccessorID, resp.Explanation.AccessorID)
+
+	// Management tokens are always allowed.
+	req.AccessorID = ""
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp))
+	must.True(t, resp.Explanation.Management)
+	must.True(t, resp.Explanation.Allowed)
+	must.SliceEmpty(t, resp.Explanation.Rules)
+
+	// Unknown tokens return no explanation.
+	req.AccessorID = uuid.Generate()
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp))
+	must.Nil(t, resp.Explanation)
+
+	// Invalid operations are rejected.
+	req.Operation.Capability = "bogus"
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLExplainRPCMethod, req, &resp)
+	must.ErrorContains(t, err, "invalid operation")
+}
+
 func TestACLEndpoint_OneTimeToken(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3797,6 +4170,382 @@ func TestACL_OIDCCompleteAuth(t *testing.T) {
 	must.Eq(t, structs.ACLManagementToken, completeAuthResp5.ACLToken.Type)
 }
 
+func TestACL_OIDCCompleteAuth_PKCE(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	oidcTestProvider := capOIDC.StartTestProvider(t)
+	defer oidcTestProvider.Stop()
+	oidcTestProvider.SetAllowedRedirectURIs([]string{"http://127.0.0.1:4649/oidc/callback"})
+
+	// Generate and upsert an ACL auth method which requires PKCE, along with
+	// a binding rule generating management tokens.
+	mockedAuthMethod := mock.ACLOIDCAuthMethod()
+	mockedAuthMethod.Config.BoundAudiences = []string{"mock"}
+	mockedAuthMethod.Config.AllowedRedirectURIs = []string{"http://127.0.0.1:4649/oidc/callback"}
+	mockedAuthMethod.Config.OIDCDiscoveryURL = oidcTestProvider.Addr()
+	mockedAuthMethod.Config.SigningAlgs = []string{"ES256"}
+	mockedAuthMethod.Config.DiscoveryCaPem = []string{oidcTestProvider.CACert()}
+	mockedAuthMethod.Config.OIDCEnablePKCE = true
+	mockedAuthMethod.Config.ClaimMappings = map[string]string{}
+	mockedAuthMethod.Config.ListClaimMappings = map[string]string{}
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+	mockBindingRule := mock.ACLBindingRule()
+	mockBindingRule.AuthMethod = mockedAuthMethod.Name
+	mockBindingRule.BindType = structs.ACLBindingRuleBindTypeManagement
+	mockBindingRule.Selector = ""
+	mockBindingRule.BindName = ""
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		20, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+	verifier, err := capOIDC.NewCodeVerifier()
+	must.NoError(t, err)
+
+	oidcTestProvider.SetExpectedAuthNonce("fsSPuaodKevKfDU3IeXa")
+	oidcTestProvider.SetExpectedAuthCode("codeABC")
+	oidcTestProvider.SetCustomAudience("mock")
+	oidcTestProvider.SetExpectedState("st_someweirdstateid")
+	oidcTestProvider.SetCustomClaims(map[string]interface{}{"azp": "mock"})
+	oidcTestProvider.SetPKCEVerifier(verifier)
+
+	// The auth URL request must include the code challenge.
+	authURLReq := structs.ACLOIDCAuthURLRequest{
+		AuthMethodName: mockedAuthMethod.Name,
+		RedirectURI:    mockedAuthMethod.Config.AllowedRedirectURIs[0],
+		ClientNonce:    "fsSPuaodKevKfDU3IeXa",
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+
+	var authURLResp structs.ACLOIDCAuthURLResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, &authURLReq, &authURLResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "auth method requires a PKCE code challenge")
+
+	authURLReq.CodeChallenge = verifier.Challenge()
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, &authURLReq, &authURLResp)
+	must.NoError(t, err)
+	must.StrContains(t, authURLResp.AuthURL, "code_challenge="+verifier.Challenge())
+	must.StrContains(t, authURLResp.AuthURL, "code_challenge_method=S256")
+
+	// The complete auth request must include the code verifier.
+	completeAuthReq := structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: mockedAuthMethod.Name,
+		ClientNonce:    "fsSPuaodKevKfDU3IeXa",
+		State:          "st_someweirdstateid",
+		Code:           "codeABC",
+		RedirectURI:    mockedAuthMethod.Config.AllowedRedirectURIs[0],
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+
+	var completeAuthResp structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, &completeAuthReq, &completeAuthResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "auth method requires a PKCE code verifier")
+
+	completeAuthReq.CodeVerifier = verifier.Verifier()
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, &completeAuthReq, &completeAuthResp)
+	must.NoError(t, err)
+	must.NotNil(t, completeAuthResp.ACLToken)
+	must.Eq(t, structs.ACLManagementToken, completeAuthResp.ACLToken.Type)
+}
+
+func TestACL_OIDCDeviceAuth(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	oidcTestProvider := capOIDC.StartTestProvider(t)
+	defer oidcTestProvider.Stop()
+
+	// Send an empty request to ensure the RPC handler runs the validation
+	// func.
+	deviceAuthReq := structs.ACLOIDCDeviceAuthRequest{
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+
+	var deviceAuthResp structs.ACLOIDCDeviceAuthResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLOIDCDeviceAuthRPCMethod, &deviceAuthReq, &deviceAuthResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "invalid OIDC device-auth request")
+
+	// The device authorization flow is only supported by OIDC auth methods.
+	mockedJWTAuthMethod := mock.ACLJWTAuthMethod()
+	mockedAuthMethod := mock.ACLOIDCAuthMethod()
+	mockedAuthMethod.Config.OIDCDiscoveryURL = oidcTestProvider.Addr()
+	mockedAuthMethod.Config.SigningAlgs = []string{"ES256"}
+	mockedAuthMethod.Config.DiscoveryCaPem = []string{oidcTestProvider.CACert()}
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		10, []*structs.ACLAuthMethod{mockedJWTAuthMethod, mockedAuthMethod}))
+
+	deviceAuthReq.AuthMethodName = mockedJWTAuthMethod.Name
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCDeviceAuthRPCMethod, &deviceAuthReq, &deviceAuthResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "is not an OIDC auth method")
+
+	// The test provider doesn't advertise a device authorization endpoint.
+	deviceAuthReq.AuthMethodName = mockedAuthMethod.Name
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCDeviceAuthRPCMethod, &deviceAuthReq, &deviceAuthResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "does not support the device authorization grant")
+}
+
+func TestACL_Login_LDAP(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Start an in-process LDAP directory where alice is a member of the
+	// engineering group.
+	directory := testdirectory.Start(t, testdirectory.WithDefaults(t, &testdirectory.Defaults{
+		Users: testdirectory.NewUsers(t, []string{"alice", "bob"}),
+		Groups: []*gldap.Entry{
+			testdirectory.NewGroup(t, "engineering", []string{"alice"}),
+		},
+	}))
+
+	mockedAuthMethod := mock.ACLLDAPAuthMethod()
+	mockedAuthMethod.Config.LDAPURLs = []string{
+		fmt.Sprintf("ldaps://%s:%d", directory.Host(), directory.Port())}
+	mockedAuthMethod.Config.LDAPCACert = directory.Cert()
+	mockedAuthMethod.Config.LDAPUserDN = testdirectory.DefaultUserDN
+	mockedAuthMethod.Config.LDAPGroupDN = testdirectory.DefaultGroupDN
+	mockedAuthMethod.SetHash()
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+	mockACLPolicy := mock.ACLPolicy()
+	must.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{mockACLPolicy}))
+
+	mockBindingRule := mock.ACLBindingRule()
+	mockBindingRule.AuthMethod = mockedAuthMethod.Name
+	mockBindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	mockBindingRule.Selector = "engineering in list.groups"
+	mockBindingRule.BindName = mockACLPolicy.Name
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		30, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+	login := func(username, password string) (*structs.ACLLoginResponse, error) {
+		req := structs.ACLLoginRequest{
+			AuthMethodName: mockedAuthMethod.Name,
+			Username:       username,
+			Password:       password,
+			WriteRequest: structs.WriteRequest{
+				Region: DefaultRegion,
+			},
+		}
+		var resp structs.ACLLoginResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &req, &resp)
+		return &resp, err
+	}
+
+	// A missing password fails validation.
+	_, err := login("alice", "")
+	must.ErrorContains(t, err, "missing password")
+
+	// Invalid credentials are rejected.
+	_, err = login("alice", "wrong")
+	must.ErrorContains(t, err, "401")
+
+	// Users without matching groups don't get a token.
+	_, err = login("bob", "password")
+	must.ErrorContains(t, err, "no role or policy bindings matched")
+
+	// Members of the engineering group are bound to the policy.
+	resp, err := login("alice", "password")
+	must.NoError(t, err)
+	must.NotNil(t, resp.ACLToken)
+	must.Eq(t, []string{mockACLPolicy.Name}, resp.ACLToken.Policies)
+	must.Eq(t, "LDAP-"+mockedAuthMethod.Name, resp.ACLToken.Name)
+}
+
+func TestACL_Login_Cert(t *testing.T) {
+	ci.Parallel(t)
+
+	// Generate the certificates of the cluster, since cert auth methods
+	// require mTLS for RPC.
+	dir := t.TempDir()
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		must.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		return path
+	}
+	clusterCASigner, _, err := tlsutil.GeneratePrivateKey()
+	must.NoError(t, err)
+	clusterCAPEM, _, err := tlsutil.GenerateCA(tlsutil.CAOpts{Signer: clusterCASigner})
+	must.NoError(t, err)
+	serverCertPEM, serverKeyPEM, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer: clusterCASigner,
+		CA:     clusterCAPEM,
+		Name:   "server.regionFoo.nomad",
+		Days:   1,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	must.NoError(t, err)
+
+	tlsCfg := &config.TLSConfig{
+		EnableHTTP: true,
+		EnableRPC:  true,
+		CAFile:     writeFile("ca.pem", clusterCAPEM),
+		CertFile:   writeFile("server.pem", serverCertPEM),
+		KeyFile:    writeFile("server-key.pem", serverKeyPEM),
+	}
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, func(c *Config) {
+		c.Region = "regionFoo"
+		c.AuthoritativeRegion = "regionFoo"
+		c.TLSConfig = tlsCfg
+	})
+	defer testServerCleanupFn()
+	codec := rpcClientWithTLS(t, testServer, tlsCfg)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the CA trusted by the auth method, and the client certificate
+	// of the automation logging in.
+	caSigner, _, err := tlsutil.GeneratePrivateKey()
+	must.NoError(t, err)
+	caPEM, _, err := tlsutil.GenerateCA(tlsutil.CAOpts{Signer: caSigner})
+	must.NoError(t, err)
+
+	clientCert := func(name string) [][]byte {
+		certPEM, _, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+			Signer:      caSigner,
+			CA:          caPEM,
+			Name:        name,
+			Days:        1,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		must.NoError(t, err)
+		cert, err := tlsutil.ParseCert(certPEM)
+		must.NoError(t, err)
+		return [][]byte{cert.Raw}
+	}
+
+	mockedAuthMethod := mock.ACLCertAuthMethod()
+	mockedAuthMethod.Config.CertCACerts = []string{caPEM}
+	mockedAuthMethod.SetHash()
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+	mockACLPolicy := mock.ACLPolicy()
+	must.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{mockACLPolicy}))
+
+	mockBindingRule := mock.ACLBindingRule()
+	mockBindingRule.AuthMethod = mockedAuthMethod.Name
+	mockBindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	mockBindingRule.Selector = `value.common_name == "deployer"`
+	mockBindingRule.BindName = mockACLPolicy.Name
+	must.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		30, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+	login := func(chain [][]byte) (*structs.ACLLoginResponse, error) {
+		req := structs.ACLLoginRequest{
+			AuthMethodName:     mockedAuthMethod.Name,
+			ClientCertificates: chain,
+			WriteRequest: structs.WriteRequest{
+				Region: "regionFoo",
+			},
+		}
+		var resp structs.ACLLoginResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &req, &resp)
+		return &resp, err
+	}
+
+	// A request without a certificate fails validation.
+	_, err = login(nil)
+	must.ErrorContains(t, err, "missing login token")
+
+	// Certificates not issued by the trusted CA are rejected.
+	untrusted, err := tlsutil.ParseCert(serverCertPEM)
+	must.NoError(t, err)
+	_, err = login([][]byte{untrusted.Raw})
+	must.ErrorContains(t, err, "401")
+
+	// Certificates without matching claims don't get a token.
+	_, err = login(clientCert("someone-else"))
+	must.ErrorContains(t, err, "no role or policy bindings matched")
+
+	// The certificate of the automation is bound to the policy.
+	resp, err := login(clientCert("deployer"))
+	must.NoError(t, err)
+	must.NotNil(t, resp.ACLToken)
+	must.Eq(t, []string{mockACLPolicy.Name}, resp.ACLToken.Policies)
+	must.Eq(t, "CERT-"+mockedAuthMethod.Name, resp.ACLToken.Name)
+
+	// Client agents can't forward certificates, since they could pass along
+	// the chain of a certificate they don't hold the private key for.
+	clientCertPEM, clientKeyPEM, err := tlsutil.GenerateCert(tlsutil.CertOpts{
+		Signer: clusterCASigner,
+		CA:     clusterCAPEM,
+		Name:   "client.regionFoo.nomad",
+		Days:   1,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	must.NoError(t, err)
+	clientCodec := rpcClientWithTLS(t, testServer, &config.TLSConfig{
+		EnableHTTP: true,
+		EnableRPC:  true,
+		CAFile:     tlsCfg.CAFile,
+		CertFile:   writeFile("client.pem", clientCertPEM),
+		KeyFile:    writeFile("client-key.pem", clientKeyPEM),
+	})
+	req := structs.ACLLoginRequest{
+		AuthMethodName:     mockedAuthMethod.Name,
+		ClientCertificates: clientCert("deployer"),
+		WriteRequest: structs.WriteRequest{
+			Region: "regionFoo",
+		},
+	}
+	err = msgpackrpc.CallWithCodec(clientCodec, structs.ACLLoginRPCMethod, &req, &resp)
+	must.ErrorContains(t, err, "must be sent to the HTTP API of a server agent")
+}
+
+func TestACL_Login_Cert_RequiresRPCTLS(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	mockedAuthMethod := mock.ACLCertAuthMethod()
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+	req := structs.ACLLoginRequest{
+		AuthMethodName:     mockedAuthMethod.Name,
+		ClientCertificates: [][]byte{[]byte("spoofed")},
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+	var resp structs.ACLLoginResponse
+	err := testServer.RPC(structs.ACLLoginRPCMethod, &req, &resp)
+	must.ErrorContains(t, err, "cert auth methods require mTLS to be enabled for RPC")
+
+	// Certificates received over RPC connections that aren't authenticated
+	// as a server are never trusted.
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLLoginRPCMethod, &req, &resp)
+	must.ErrorContains(t, err, "must be sent to the HTTP API of a server agent")
+}
+
 func TestACL_Login(t *testing.T) {
 	ci.Parallel(t)
 
@@ -50,10 +50,11 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := aclObj.AllowNsOpAnyJobFunc(acl.NamespaceCapabilityReadJob)
+	allowJob := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadJob)
 
 	// Setup the blocking query
 	sort := state.SortOption(args.Reverse)
@@ -102,6 +103,9 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 					paginator.NamespaceFilter{
 						AllowableNamespaces: allowableNamespaces,
 					},
+					paginator.JobFilter{
+						Allow: allowJob,
+					},
 				}
 
 				var stubs []*structs.AllocListStub
@@ -154,7 +158,6 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "get_alloc"}, time.Now())
 
 	// Check namespace read-job permissions before performing blocking query.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := a.srv.ResolveACL(args)
 	if err != nil {
 		return err
@@ -174,8 +177,10 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 			// Setup the output
 			reply.Alloc = out
 			if out != nil {
-				// Re-check namespace in case it differs from request.
-				if !aclObj.AllowClientOp() && !allowNsOp(aclObj, out.Namespace) {
+				// Re-check namespace in case it differs from request, and the
+				// job of the allocation.
+				if !aclObj.AllowClientOp() &&
+					!aclObj.AllowJobOp(out.Namespace, out.JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.NewErrUnknownAllocation(args.AllocID)
 				}
 
@@ -292,11 +297,10 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 	}
 
 	// Check for namespace alloc-lifecycle permissions.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityAllocLifecycle)
 	aclObj, err := a.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityAllocLifecycle) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -397,7 +401,7 @@ func (a *Alloc) GetServiceRegistrations(
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowNsOpAnyJob(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -420,6 +424,12 @@ func (a *Alloc) GetServiceRegistrations(
 				return nil
 			}
 
+			// Ensure the caller has the read-job capability for the job of
+			// the allocation.
+			if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadJob) {
+				return structs.ErrPermissionDenied
+			}
+
 			// Perform the state query to get an iterator.
 			iter, err := stateStore.GetServiceRegistrationsByAllocID(ws, args.AllocID)
 			if err != nil {
@@ -664,11 +674,24 @@ func (a *Alloc) signIdentities(
 	if err != nil {
 		return err
 	}
-	reply.SignedIdentities = append(reply.SignedIdentities, &structs.SignedWorkloadIdentity{
+	signedWID := &structs.SignedWorkloadIdentity{
 		WorkloadIdentityRequest: *idReq,
 		JWT:                     token,
 		Expiration:              claims.Expiry.Time(),
-	})
+	}
+
+	// Sign an X.509 SVID for the identity if it was requested. Older clients
+	// don't send a CSR and only get the JWT.
+	if wid.X509 && len(idReq.CSR) > 0 {
+		signedWID.X509SVID, signedWID.X509Bundle, err = a.srv.encrypter.SignX509SVID(claims, idReq.CSR)
+		if err != nil {
+			return err
+		}
+
+		// No need to send the CSR back to the client.
+		signedWID.CSR = nil
+	}
+	reply.SignedIdentities = append(reply.SignedIdentities, signedWID)
 
 	return nil
 }
@@ -4,6 +4,12 @@
 package nomad
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -562,6 +568,72 @@ func TestAllocEndpoint_List_ACL(t *testing.T) {
 	assert.Equal(stubAllocs, resp.Allocations, "Returned alloc list not equal")
 }
 
+func TestAllocEndpoint_JobRulesACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	payments := mock.Alloc()
+	payments.JobID = "payments-api"
+	payments.Job.ID = payments.JobID
+	billing := mock.Alloc()
+	billing.JobID = "billing"
+	billing.Job.ID = billing.JobID
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{payments, billing}))
+
+	token := mock.CreatePolicyAndToken(t, store, 1001, "payments", `
+namespace "default" {
+  job "payments-*" {
+    capabilities = ["read-job", "alloc-lifecycle"]
+  }
+}`)
+
+	list := &structs.AllocListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var listResp structs.AllocListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.List", list, &listResp))
+	must.Len(t, 1, listResp.Allocations)
+	must.Eq(t, payments.ID, listResp.Allocations[0].ID)
+
+	get := &structs.AllocSpecificRequest{
+		AllocID: payments.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleAllocResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", get, &getResp))
+	must.Eq(t, payments.ID, getResp.Alloc.ID)
+
+	get.AllocID = billing.ID
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", get, &getResp)
+	must.ErrorContains(t, err, "Unknown allocation")
+
+	stop := &structs.AllocStopRequest{
+		AllocID: billing.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var stopResp structs.AllocStopResponse
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", stop, &stopResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestAllocEndpoint_List_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1874,3 +1946,81 @@ func TestAlloc_SignIdentities_Blocking(t *testing.T) {
 		t.Fatalf("result not returned when expected")
 	}
 }
+
+// TestAlloc_SignIdentities_X509 asserts identities which request an X.509 SVID
+// are signed with one when the client sends a CSR.
+func TestAlloc_SignIdentities_X509(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForKeyring(t, s1.RPC, "global")
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job.TaskGroups[0].Tasks[0].Identities = []*structs.WorkloadIdentity{
+		{
+			Name:     "svid",
+			Audience: []string{"test"},
+			X509:     true,
+			TTL:      time.Hour,
+		},
+	}
+	must.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	csrKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, csrKey)
+	must.NoError(t, err)
+
+	req := &structs.AllocIdentitiesRequest{
+		Identities: []*structs.WorkloadIdentityRequest{
+			{
+				AllocID: alloc.ID,
+				WIHandle: structs.WIHandle{
+					WorkloadIdentifier: "web",
+					IdentityName:       "svid",
+				},
+				CSR: csr,
+			},
+		},
+		QueryOptions: structs.QueryOptions{
+			Region:     "global",
+			Namespace:  structs.DefaultNamespace,
+			AllowStale: true,
+			AuthToken:  node.SecretID,
+		},
+	}
+	var resp structs.AllocIdentitiesResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentities", req, &resp))
+	must.Len(t, 0, resp.Rejections)
+	must.Len(t, 1, resp.SignedIdentities)
+
+	sid := resp.SignedIdentities[0]
+	must.NotEq(t, "", sid.JWT)
+	must.Nil(t, sid.CSR)
+	must.Nil(t, sid.X509Key)
+
+	block, _ := pem.Decode(sid.X509SVID)
+	must.NotNil(t, block)
+	svid, err := x509.ParseCertificate(block.Bytes)
+	must.NoError(t, err)
+	must.SliceLen(t, 1, svid.URIs)
+	must.Eq(t, fmt.Sprintf("spiffe://global.nomad/ns/default/job/%s/task/web", alloc.JobID),
+		svid.URIs[0].String())
+
+	roots := x509.NewCertPool()
+	must.True(t, roots.AppendCertsFromPEM(sid.X509Bundle))
+	_, err = svid.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	must.NoError(t, err)
+}
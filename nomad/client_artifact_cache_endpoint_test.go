@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestArtifactCache_ForwardToClient(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		c.Artifact = &config.ArtifactConfig{
+			CacheEnabled:  true,
+			CacheMaxBytes: 1024,
+		}
+	})
+	defer cleanupC()
+	testutil.WaitForClient(t, s.RPC, c.NodeID(), c.Region())
+
+	listReq := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ArtifactCacheResponse
+	must.NoError(t, s.RPC("ArtifactCache.List", listReq, &listResp))
+	must.Len(t, 0, listResp.Entries)
+	must.Eq(t, 1024, listResp.MaxSize)
+
+	purgeReq := &structs.ArtifactCachePurgeRequest{
+		NodeID:       c.NodeID(),
+		Keys:         []string{"unknown"},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var purgeResp structs.ArtifactCachePurgeResponse
+	must.NoError(t, s.RPC("ArtifactCache.Purge", purgeReq, &purgeResp))
+	must.Eq(t, []string{"unknown"}, purgeResp.NotFound)
+
+	// Requests without a node are rejected
+	listReq.NodeID = ""
+	must.ErrorContains(t, s.RPC("ArtifactCache.List", listReq, &listResp), "missing NodeID")
+}
@@ -138,11 +138,10 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 	}
 
 	// Check namespace filesystem read permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadFS)
 	aclObj, err := f.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -200,7 +199,7 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	// Check filesystem read permissions
 	if aclObj, err := f.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -277,7 +276,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	if aclObj, err := f.srv.ResolveACL(&args); err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadFS) {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
@@ -399,13 +398,12 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	}
 
 	// Check namespace read-logs *or* read-fs permissions.
-	allowNsOp := acl.NamespaceValidator(
-		acl.NamespaceCapabilityReadFS, acl.NamespaceCapabilityReadLogs)
 	aclObj, err := f.srv.ResolveACL(&args)
 	if err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadFS,
+		acl.NamespaceCapabilityReadLogs)(alloc.Namespace, alloc.JobID) {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
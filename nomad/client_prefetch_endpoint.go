@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodePrefetch endpoint is used to forward requests for prefetching the
+// images and artifacts of a job on a node to its client.
+type NodePrefetch struct {
+	srv    *Server
+	logger log.Logger
+}
+
+func newNodePrefetchEndpoint(srv *Server) *NodePrefetch {
+	return &NodePrefetch{
+		srv:    srv,
+		logger: srv.logger.Named("node_prefetch"),
+	}
+}
+
+func (n *NodePrefetch) Start(args *structs.NodePrefetchRequest, reply *structs.NodePrefetchResponse) error {
+	const method = "NodePrefetch.Start"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := n.srv.Authenticate(nil, args)
+	if done, err := n.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("node_prefetch", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_prefetch", "start"}, time.Now())
+
+	// Check submit job permissions
+	if aclObj, err := n.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Only prefetch the images and artifacts of the stored job onto nodes it
+	// may be placed on, so that callers can't make nodes fetch arbitrary
+	// sources.
+	snap, err := n.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	job, err := snap.JobByIDAndVersion(nil, args.RequestNamespace(), args.JobID, args.JobVersion)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound,
+			fmt.Sprintf("job %q at version %d not found", args.JobID, args.JobVersion))
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil || !prefetchNodeEligible(node, job, false) {
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			fmt.Sprintf("job %q cannot be placed on node %q", args.JobID, args.NodeID))
+	}
+	args.Images, args.Artifacts = prefetchSources(job)
+	args.ImageAuths = prefetchImageAuths(job)
+
+	return n.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
+
+func (n *NodePrefetch) Status(args *structs.NodePrefetchStatusRequest, reply *structs.NodePrefetchResponse) error {
+	const method = "NodePrefetch.Status"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := n.srv.Authenticate(nil, args)
+	if done, err := n.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("node_prefetch", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_prefetch", "status"}, time.Now())
+
+	// Check read job permissions
+	if aclObj, err := n.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	return n.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
@@ -235,6 +235,14 @@ type Config struct {
 	// rekey any variables associated with a key in the Rekeying state
 	VariablesRekeyInterval time.Duration
 
+	// VariableVersionGCInterval is how often we dispatch a job to GC the
+	// previous versions of variables.
+	VariableVersionGCInterval time.Duration
+
+	// VariableVersionGCThreshold is how long a variable must have been
+	// deleted for its previous versions to be eligible for GC.
+	VariableVersionGCThreshold time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -411,6 +419,15 @@ type Config struct {
 	// RaftBoltNoFreelistSync configures whether freelist syncing is enabled.
 	RaftBoltNoFreelistSync bool
 
+	// RaftEncryptionEnabled configures whether raft logs and snapshots are
+	// encrypted at rest. Data that is already encrypted can be read while
+	// encryption is disabled.
+	RaftEncryptionEnabled bool
+
+	// RaftEncryptionRotationThreshold is how "old" the key encrypting raft
+	// data can be before it's rotated.
+	RaftEncryptionRotationThreshold time.Duration
+
 	// AgentShutdown is used to call agent.Shutdown from the context of a Server
 	// It is used primarily for licensing
 	AgentShutdown func() error
@@ -434,6 +451,16 @@ type Config struct {
 	// If this is not configured the /.well-known/openid-configuration endpoint
 	// will not be available.
 	OIDCIssuer string
+
+	// KEKProviderConfigs configure the providers that wrap the key encryption
+	// keys of the keystore. If empty, the keys are stored with the aead
+	// provider.
+	KEKProviderConfigs []*config.KEKProviderConfig
+
+	// EventSinkAllowlist restricts the commands exec event sinks may run and
+	// the directories file event sinks may write to. Exec and file sinks are
+	// refused if it is nil.
+	EventSinkAllowlist *structs.EventSinkAllowlist
 }
 
 func (c *Config) Copy() *Config {
@@ -462,6 +489,8 @@ func (c *Config) Copy() *Config {
 	nc.AutopilotConfig = c.AutopilotConfig.Copy()
 	nc.LicenseConfig = c.LicenseConfig.Copy()
 	nc.SearchConfig = c.SearchConfig.Copy()
+	nc.KEKProviderConfigs = helper.CopySlice(c.KEKProviderConfigs)
+	nc.EventSinkAllowlist = c.EventSinkAllowlist.Copy()
 
 	return &nc
 }
@@ -572,7 +601,10 @@ func DefaultConfig() *Config {
 		RootKeyGCInterval:                10 * time.Minute,
 		RootKeyGCThreshold:               1 * time.Hour,
 		RootKeyRotationThreshold:         720 * time.Hour, // 30 days
+		RaftEncryptionRotationThreshold:  720 * time.Hour, // 30 days
 		VariablesRekeyInterval:           10 * time.Minute,
+		VariableVersionGCInterval:        10 * time.Minute,
+		VariableVersionGCThreshold:       72 * time.Hour,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -66,6 +67,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobVariablesRekey:
 		return c.variablesRekey(eval)
+	case structs.CoreJobVariableVersionGC:
+		return c.variableVersionGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -102,6 +105,9 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.rootKeyGC(eval); err != nil {
 		return err
 	}
+	if err := c.variableVersionGC(eval); err != nil {
+		return err
+	}
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
 	return c.nodeGC(eval)
@@ -856,8 +862,10 @@ func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation, global bool)
 		}
 
 		// Check if the token is recent enough to skip, otherwise we'll delete
-		// it.
-		if token.CreateIndex > expiryThresholdIdx {
+		// it. Renewable tokens are as recent as their last renewal, as the
+		// renewal moves their expiration time.
+		if token.CreateIndex > expiryThresholdIdx ||
+			(token.Renewable && token.ModifyIndex > expiryThresholdIdx) {
 			continue
 		}
 
@@ -1026,18 +1034,37 @@ func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 			return err
 		}
 
+		// Previous versions of variables can only be rekeyed without being
+		// written as new versions once all servers support versioning.
+		if !ServersMeetMinimumVersion(c.srv.Members(), c.srv.Region(), minVariableVersionsVersion, true) {
+			continue
+		}
+		versionIter, err := c.snap.GetVariableVersionsByKeyID(ws, keyMeta.KeyID)
+		if err != nil {
+			return err
+		}
+		err = c.rotateVariables(versionIter, eval)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// rotateVariables runs over an iterator of variables and decrypts them, and
-// then sends them back to be re-encrypted with the currently active key,
-// checking for conflicts
+// rotateVariables runs over an iterator of variables or previous versions of
+// variables and decrypts them, and then sends them back to be re-encrypted
+// with the currently active key, checking for conflicts
 func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs.Evaluation) error {
 
+	// Older servers can't rekey a variable in place, so rewrite it instead.
+	op := structs.VarOpRekey
+	if !ServersMeetMinimumVersion(c.srv.Members(), c.srv.Region(), minVariableVersionsVersion, true) {
+		op = structs.VarOpCAS
+	}
+
 	args := &structs.VariablesApplyRequest{
-		Op: structs.VarOpCAS,
+		Op: op,
 		WriteRequest: structs.WriteRequest{
 			Region:    c.srv.config.Region,
 			AuthToken: eval.LeaderACL,
@@ -1088,7 +1115,13 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 		default:
 		}
 
-		ev := raw.(*structs.VariableEncrypted)
+		var ev *structs.VariableEncrypted
+		switch v := raw.(type) {
+		case *structs.VariableEncrypted:
+			ev = v
+		case *structs.VariableVersion:
+			ev = &v.VariableEncrypted
+		}
 		cleartext, err := c.srv.encrypter.Decrypt(ev.Data, ev.KeyID)
 		if err != nil {
 			return err
@@ -1123,6 +1156,95 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 	return nil
 }
 
+// variableVersionGC is used to garbage collect the previous versions of
+// variables. The history of a deleted variable is kept until the variable has
+// been deleted for longer than the GC threshold, and versions beyond the limit
+// of their namespace are always collected.
+func (c *CoreScheduler) variableVersionGC(eval *structs.Evaluation) error {
+	if !ServersMeetMinimumVersion(c.srv.Members(), c.srv.Region(), minVariableVersionsVersion, true) {
+		return nil
+	}
+
+	oldThreshold := c.getThreshold(eval, "variable version",
+		"variable_version_gc_threshold", c.srv.config.VariableVersionGCThreshold)
+
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.VariableVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	// Group the versions by variable.
+	type variableID struct {
+		namespace, path string
+	}
+	var ids []variableID
+	versions := map[variableID][]*structs.VariableVersion{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		vv := raw.(*structs.VariableVersion)
+		id := variableID{vv.Namespace, vv.Path}
+		if _, ok := versions[id]; !ok {
+			ids = append(ids, id)
+		}
+		versions[id] = append(versions[id], vv)
+	}
+
+	var gc []*structs.VariableMetadata
+	for _, id := range ids {
+		history := versions[id]
+		sort.Slice(history, func(i, j int) bool {
+			return history[i].Version < history[j].Version
+		})
+
+		ns, err := c.snap.NamespaceByName(ws, id.namespace)
+		if err != nil {
+			return err
+		}
+		current, err := c.snap.GetVariable(ws, id.namespace, id.path)
+		if err != nil {
+			return err
+		}
+
+		// The newest version of a deleted variable was archived when it was
+		// deleted.
+		keep := ns.VariableMaxVersions()
+		if ns == nil || (current == nil && history[len(history)-1].ArchiveIndex <= oldThreshold) {
+			keep = 0
+		}
+		for _, vv := range history[:max(len(history)-keep, 0)] {
+			gc = append(gc, &structs.VariableMetadata{
+				Namespace: vv.Namespace,
+				Path:      vv.Path,
+				Version:   vv.Version,
+			})
+		}
+	}
+
+	if len(gc) == 0 {
+		return nil
+	}
+	c.logger.Debug("variable version GC found eligible versions", "versions", len(gc))
+
+	for len(gc) > 0 {
+		batch := gc[:min(len(gc), structs.MaxUUIDsPerWriteRequest)]
+		gc = gc[len(batch):]
+
+		req := &structs.VariablesDeleteVersionsRequest{
+			Versions: batch,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.Region(),
+				AuthToken: eval.LeaderACL,
+			},
+		}
+		if err := c.srv.RPC(structs.VariablesDeleteVersionsRPCMethod, req, &structs.GenericResponse{}); err != nil {
+			c.logger.Error("variable version delete failed", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
 // getThreshold returns the index threshold for determining whether an
 // object is old enough to GC
 func (c *CoreScheduler) getThreshold(eval *structs.Evaluation, objectName, configName string, configThreshold time.Duration) uint64 {
@@ -2779,6 +2779,93 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 
 }
 
+func TestCoreScheduler_VariableVersionGC(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	store := srv.fsm.State()
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		MaxVersions: pointer.Of(3),
+	}
+	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	// Write a variable that still exists and one that gets deleted, each
+	// with three previous versions.
+	index := uint64(1000)
+	writeVar := func(path string) *structs.VariableEncrypted {
+		sv := mock.VariableEncrypted()
+		sv.Namespace = ns.Name
+		sv.Path = path
+		for i := 0; i < 4; i++ {
+			index++
+			update := sv.Copy()
+			update.Data = []byte(uuid.Generate())
+			resp := store.VarSet(index, &structs.VarApplyStateRequest{
+				Op:  structs.VarOpSet,
+				Var: &update,
+			})
+			must.NoError(t, resp.Error)
+		}
+		out, err := store.GetVariable(nil, ns.Name, path)
+		must.NoError(t, err)
+		return out
+	}
+	writeVar("live")
+	deleted := writeVar("deleted")
+
+	index++
+	resp := store.VarDelete(index, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: deleted,
+	})
+	must.NoError(t, resp.Error)
+
+	// Lower the number of versions kept after they were written
+	nsUpdate := ns.Copy()
+	nsUpdate.VariablesConfiguration.MaxVersions = pointer.Of(1)
+	must.NoError(t, store.UpsertNamespaces(2000, []*structs.Namespace{nsUpdate}))
+
+	snap, err := store.Snapshot()
+	must.NoError(t, err)
+	core := NewCoreScheduler(srv, snap)
+
+	// The deleted variable isn't old enough to be GC'd yet, so only the
+	// versions beyond the namespace's new limit are GC'd
+	gc := srv.coreJobEval(structs.CoreJobVariableVersionGC, 2000)
+	must.NoError(t, core.Process(gc))
+
+	versions, err := store.GetVariableVersions(nil, ns.Name, "live")
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, 3, versions[0].Version)
+
+	versions, err = store.GetVariableVersions(nil, ns.Name, "deleted")
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, 4, versions[0].Version)
+
+	// Forcing a GC removes the history of the deleted variable
+	snap, err = store.Snapshot()
+	must.NoError(t, err)
+	core = NewCoreScheduler(srv, snap)
+	gc = srv.coreJobEval(structs.CoreJobForceGC, 2001)
+	must.NoError(t, core.Process(gc))
+
+	versions, err = store.GetVariableVersions(nil, ns.Name, "live")
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+
+	versions, err = store.GetVariableVersions(nil, ns.Name, "deleted")
+	must.NoError(t, err)
+	must.Len(t, 0, versions)
+}
+
 func TestCoreScheduler_FailLoop(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2866,11 +2953,20 @@ func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
 	unexpiredLocal := mock.ACLToken()
 	unexpiredLocal.ExpirationTime = pointer.Of(now.Add(2 * time.Hour))
 
+	// Craft an expired renewable token, which was renewed more recently than
+	// the GC threshold.
+	renewedLocal := mock.ACLToken()
+	renewedLocal.Renewable = true
+	renewedLocal.ExpirationTTL = time.Hour
+	renewedLocal.ExpirationTime = pointer.Of(now.Add(-time.Minute))
+
 	// Upsert these into state.
 	err := testServer.State().UpsertACLTokens(structs.MsgTypeTestSetup, 10, []*structs.ACLToken{
-		expiredGlobal, unexpiredGlobal, expiredLocal, unexpiredLocal,
+		expiredGlobal, unexpiredGlobal, expiredLocal, unexpiredLocal, renewedLocal,
 	})
 	require.NoError(t, err)
+	err = testServer.State().UpsertACLTokens(structs.MsgTypeTestSetup, 30, []*structs.ACLToken{renewedLocal})
+	require.NoError(t, err)
 
 	// Overwrite the timetable. The existing timetable has an entry due to the
 	// ACL bootstrapping which makes witnessing a new index at a timestamp in
@@ -2903,7 +2999,7 @@ func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
 		tokens = append(tokens, raw.(*structs.ACLToken))
 	}
-	require.ElementsMatch(t, []*structs.ACLToken{rootACLToken, unexpiredGlobal, unexpiredLocal}, tokens)
+	require.ElementsMatch(t, []*structs.ACLToken{rootACLToken, unexpiredGlobal, unexpiredLocal, renewedLocal}, tokens)
 }
 
 func TestCoreScheduler_ExpiredACLTokenGC_Force(t *testing.T) {
@@ -1801,7 +1801,7 @@ func (v *CSIPlugin) Get(args *structs.CSIPluginGetRequest, reply *structs.CSIPlu
 	}
 
 	ns := args.RequestNamespace()
-	withAllocs := aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+	withAllocs := aclObj.AllowNsOpAnyJob(ns, acl.NamespaceCapabilityReadJob)
 
 	if args.ID == "" {
 		return fmt.Errorf("missing plugin ID")
@@ -1838,7 +1838,7 @@ func (v *CSIPlugin) Get(args *structs.CSIPluginGetRequest, reply *structs.CSIPlu
 				var as []*structs.AllocListStub
 				for _, a := range plug.Allocations {
 					if ns == structs.AllNamespacesSentinel || a.Namespace == ns {
-						if aclObj.AllowNsOp(a.Namespace, acl.NamespaceCapabilityReadJob) {
+						if aclObj.AllowJobOp(a.Namespace, a.JobID, acl.NamespaceCapabilityReadJob) {
 							as = append(as, a)
 						}
 					}
@@ -45,11 +45,10 @@ func (d *Deployment) GetDeployment(args *structs.DeploymentSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "deployment", "get_deployment"}, time.Now())
 
 	// Check namespace read-job permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := d.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowNsOpAnyJob(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -69,8 +68,9 @@ func (d *Deployment) GetDeployment(args *structs.DeploymentSpecificRequest,
 				return err
 			}
 
-			// Re-check namespace in case it differs from request.
-			if out != nil && !allowNsOp(aclObj, out.Namespace) {
+			// Re-check namespace in case it differs from request, and the
+			// job of the deployment.
+			if out != nil && !aclObj.AllowJobOp(out.Namespace, out.JobID, acl.NamespaceCapabilityReadJob) {
 				// hide this deployment, caller is not authorized to view it
 				out = nil
 			}
@@ -131,7 +131,7 @@ func (d *Deployment) Fail(args *structs.DeploymentFailRequest, reply *structs.De
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -178,7 +178,7 @@ func (d *Deployment) Pause(args *structs.DeploymentPauseRequest, reply *structs.
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -229,7 +229,7 @@ func (d *Deployment) Promote(args *structs.DeploymentPromoteRequest, reply *stru
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -276,7 +276,7 @@ func (d *Deployment) Run(args *structs.DeploymentRunRequest, reply *structs.Depl
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -323,7 +323,7 @@ func (d *Deployment) Unblock(args *structs.DeploymentUnblockRequest, reply *stru
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -370,7 +370,7 @@ func (d *Deployment) Cancel(args *structs.DeploymentCancelRequest, reply *struct
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -422,7 +422,7 @@ func (d *Deployment) SetAllocHealth(args *structs.DeploymentAllocHealthRequest,
 	// Check namespace submit-job permissions
 	if aclObj, err := d.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(deploy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOp(deploy.Namespace, deploy.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -454,11 +454,12 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := aclObj.AllowNsOpAnyJobFunc(acl.NamespaceCapabilityReadJob)
+	allowJob := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadJob)
 
 	// Setup the blocking query
 	sort := state.SortOption(args.Reverse)
@@ -507,6 +508,9 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 				paginator.NamespaceFilter{
 					AllowableNamespaces: allowableNamespaces,
 				},
+				paginator.JobFilter{
+					Allow: allowJob,
+				},
 			}
 
 			var deploys []*structs.Deployment
@@ -561,11 +565,10 @@ func (d *Deployment) Allocations(args *structs.DeploymentSpecificRequest, reply
 	// Check namespace read-job permissions against the request namespace.
 	// Must re-check against the alloc namespace when they return to ensure
 	// there's no namespace mismatch.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := d.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowNsOpAnyJob(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -580,11 +583,11 @@ func (d *Deployment) Allocations(args *structs.DeploymentSpecificRequest, reply
 				return err
 			}
 
-			// Deployments do not span namespaces so just check the
-			// first allocs namespace.
+			// Deployments do not span namespaces or jobs so just check
+			// the first allocs namespace and job.
 			if len(allocs) > 0 {
-				ns := allocs[0].Namespace
-				if ns != args.RequestNamespace() && !allowNsOp(aclObj, ns) {
+				alloc := allocs[0]
+				if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.ErrPermissionDenied
 				}
 			}
@@ -4,17 +4,24 @@
 package nomad
 
 import (
+	"bytes"
 	"context"
+	gocrypto "crypto"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io/fs"
+	"math/big"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -24,12 +31,15 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	kms "github.com/hashicorp/go-kms-wrapping/v2"
 	"github.com/hashicorp/go-kms-wrapping/v2/aead"
+	"github.com/hashicorp/go-multierror"
 	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/crypto"
 	"github.com/hashicorp/nomad/helper/joseutil"
+	"github.com/hashicorp/nomad/nomad/kekprovider"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 const nomadKeystoreExtension = ".nks.json"
@@ -49,6 +59,12 @@ type Encrypter struct {
 	// issuer is the OIDC Issuer to use for workload identities if configured
 	issuer string
 
+	// kekProviderConfigs are the providers that root keys are saved with, the
+	// active provider first. kekProviders holds the external providers by ID;
+	// the aead provider has no entry because it stores the KEK in the clear.
+	kekProviderConfigs []*config.KEKProviderConfig
+	kekProviders       map[string]kekprovider.Provider
+
 	keyring map[string]*keyset
 	lock    sync.RWMutex
 }
@@ -63,6 +79,8 @@ type keyset struct {
 	eddsaPrivateKey   ed25519.PrivateKey
 	rsaPrivateKey     *rsa.PrivateKey
 	rsaPKCS1PublicKey []byte // PKCS #1 DER encoded public key for JWKS
+	caPrivateKey      gocrypto.Signer
+	caCert            *x509.Certificate
 }
 
 // NewEncrypter loads or creates a new local keystore and returns an
@@ -70,26 +88,71 @@ type keyset struct {
 func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
 
 	encrypter := &Encrypter{
-		srv:          srv,
-		keystorePath: keystorePath,
-		keyring:      make(map[string]*keyset),
-		issuer:       srv.GetConfig().OIDCIssuer,
+		srv:                srv,
+		keystorePath:       keystorePath,
+		keyring:            make(map[string]*keyset),
+		issuer:             srv.GetConfig().OIDCIssuer,
+		kekProviderConfigs: sortedKEKProviderConfigs(srv.GetConfig().KEKProviderConfigs),
+		kekProviders:       make(map[string]kekprovider.Provider),
+	}
+
+	for _, cfg := range encrypter.kekProviderConfigs {
+		if cfg.Provider == config.KEKProviderAEAD {
+			continue
+		}
+		provider, err := kekprovider.New(cfg, srv.logger)
+		if err != nil {
+			encrypter.Close()
+			return nil, fmt.Errorf("could not configure keyring provider %q: %w", cfg.ID(), err)
+		}
+		encrypter.kekProviders[cfg.ID()] = provider
 	}
 
 	err := encrypter.loadKeystore()
 	if err != nil {
+		encrypter.Close()
 		return nil, err
 	}
 	return encrypter, nil
 }
 
+// sortedKEKProviderConfigs returns the configured keyring providers with the
+// active provider first, or the aead provider if none are configured.
+func sortedKEKProviderConfigs(configs []*config.KEKProviderConfig) []*config.KEKProviderConfig {
+	if len(configs) == 0 {
+		return []*config.KEKProviderConfig{{Provider: config.KEKProviderAEAD, Active: true}}
+	}
+	sorted := slices.Clone(configs)
+	slices.SortStableFunc(sorted, func(a, b *config.KEKProviderConfig) int {
+		switch {
+		case a.Active == b.Active:
+			return 0
+		case a.Active:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return sorted
+}
+
+// Close releases the external keyring providers.
+func (e *Encrypter) Close() {
+	for _, provider := range e.kekProviders {
+		provider.Close()
+	}
+}
+
 func (e *Encrypter) loadKeystore() error {
 
 	if err := os.MkdirAll(e.keystorePath, 0o700); err != nil {
 		return err
 	}
 
-	return filepath.Walk(e.keystorePath, func(path string, info fs.FileInfo, err error) error {
+	// A root key is saved once for each keyring provider, so collect the
+	// files for each key by provider ID before loading them.
+	keyFiles := map[string]map[string]string{}
+	err := filepath.Walk(e.keystorePath, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("could not read path %s from keystore: %v", path, err)
 		}
@@ -103,27 +166,111 @@ func (e *Encrypter) loadKeystore() error {
 		if !strings.HasSuffix(path, nomadKeystoreExtension) {
 			return nil
 		}
-		id := strings.TrimSuffix(filepath.Base(path), nomadKeystoreExtension)
+		id, providerID, _ := strings.Cut(
+			strings.TrimSuffix(filepath.Base(path), nomadKeystoreExtension), ".")
 		if !helper.IsUUID(id) {
 			return nil
 		}
+		if providerID == "" {
+			providerID = config.KEKProviderAEAD
+		}
+		if keyFiles[id] == nil {
+			keyFiles[id] = map[string]string{}
+		}
+		keyFiles[id][providerID] = path
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		key, err := e.loadKeyFromStore(path)
+	for id, files := range keyFiles {
+		key, err := e.loadKeyFromFiles(files)
 		if err != nil {
-			return fmt.Errorf("could not load key file %s from keystore: %w", path, err)
+			return fmt.Errorf("could not load key %s from keystore: %w", id, err)
 		}
 		if key.Meta.KeyID != id {
-			return fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, path)
+			return fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, id)
 		}
 
 		err = e.addCipher(key)
 		if err != nil {
-			return fmt.Errorf("could not add key file %s to keystore: %w", path, err)
+			return fmt.Errorf("could not add key %s to keystore: %w", id, err)
 		}
-		return nil
+
+		// Save the key for any provider that was added since the key was last
+		// saved, so that operators can migrate to a new provider by adding it
+		// and then removing the old one.
+		for _, cfg := range e.kekProviderConfigs {
+			if _, ok := files[cfg.ID()]; !ok {
+				if err := e.saveKeyToStore(key); err != nil {
+					return fmt.Errorf("could not save key %s to keystore: %w", id, err)
+				}
+				break
+			}
+		}
+
+		// The aead key file holds the KEK in the clear, so remove it once the
+		// aead provider is no longer configured and the key can be loaded
+		// with the active provider.
+		if path, ok := files[config.KEKProviderAEAD]; ok && !e.hasKEKProvider(config.KEKProviderAEAD) {
+			e.removeAEADKeyFile(key, path)
+		}
+	}
+	return nil
+}
+
+// hasKEKProvider returns true if the provider with the given ID is configured.
+func (e *Encrypter) hasKEKProvider(id string) bool {
+	return slices.ContainsFunc(e.kekProviderConfigs, func(cfg *config.KEKProviderConfig) bool {
+		return cfg.ID() == id
 	})
 }
 
+// removeAEADKeyFile removes the aead key file of a root key after verifying
+// that the key file of the active provider decrypts to the same key. The file
+// is kept if the verification fails, so the key isn't lost.
+func (e *Encrypter) removeAEADKeyFile(key *structs.RootKey, path string) {
+	logger := e.srv.logger.Named("keyring")
+
+	cfg := e.kekProviderConfigs[0]
+	activePath := filepath.Join(e.keystorePath, keystoreFilename(key.Meta.KeyID, cfg))
+	activeKey, err := e.loadKeyFromStore(activePath)
+	if err != nil || !bytes.Equal(activeKey.Key, key.Key) {
+		logger.Warn("not removing aead key file, the key could not be verified with the active keyring provider",
+			"key_id", key.Meta.KeyID, "provider", cfg.ID(), "path", path, "error", err)
+		return
+	}
+
+	if err := os.Remove(path); err != nil {
+		logger.Warn("failed to remove aead key file", "key_id", key.Meta.KeyID, "path", path, "error", err)
+		return
+	}
+	logger.Info("removed aead key file after migrating key to keyring provider",
+		"key_id", key.Meta.KeyID, "provider", cfg.ID())
+}
+
+// loadKeyFromFiles loads a root key from the first of its keystore files that
+// can be decrypted by a configured provider, trying the active provider first.
+func (e *Encrypter) loadKeyFromFiles(files map[string]string) (*structs.RootKey, error) {
+	var mErr *multierror.Error
+	for _, cfg := range e.kekProviderConfigs {
+		path, ok := files[cfg.ID()]
+		if !ok {
+			continue
+		}
+		key, err := e.loadKeyFromStore(path)
+		if err == nil {
+			return key, nil
+		}
+		mErr = multierror.Append(mErr, fmt.Errorf("%s: %w", path, err))
+	}
+	if mErr == nil {
+		return nil, fmt.Errorf("no key file in keystore can be decrypted by the configured keyring providers")
+	}
+	return nil, mErr.ErrorOrNil()
+}
+
 // Encrypt encrypts the clear data with the cipher for the current
 // root key, and returns the cipher text (including the nonce), and
 // the key ID used to encrypt it
@@ -231,6 +378,80 @@ func (e *Encrypter) SignClaims(claims *structs.IdentityClaims) (string, string,
 	return raw, keyset.rootKey.Meta.KeyID, nil
 }
 
+// SignX509SVID signs an X.509 SVID for the identity claims with the CA of the
+// active root key. The csr is the ASN.1 DER encoded certificate signing
+// request of the workload, and the SVID expires with the claims. It returns
+// the PEM encoded SVID, and the PEM encoded CA certificates of all the keys in
+// the keyring which the SVID can be verified against.
+func (e *Encrypter) SignX509SVID(claims *structs.IdentityClaims, csr []byte) ([]byte, []byte, error) {
+	if claims.Expiry == nil {
+		return nil, nil, fmt.Errorf("x509 svids must expire")
+	}
+
+	req, err := x509.ParseCertificateRequest(csr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse certificate signing request: %w", err)
+	}
+	if err := req.CheckSignature(); err != nil {
+		return nil, nil, fmt.Errorf("invalid certificate signing request signature: %w", err)
+	}
+
+	keyset, err := e.activeKeySet()
+	if err != nil {
+		return nil, nil, err
+	}
+	if keyset.caPrivateKey == nil {
+		return nil, nil, fmt.Errorf("active root key %s has no ca to sign x509 svids; rotate the keyring",
+			keyset.rootKey.Meta.KeyID)
+	}
+
+	spiffeID, err := url.Parse(claims.SPIFFEID(e.srv.Region()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid spiffe id: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		NotBefore:    claims.NotBefore.Time().Add(-time.Minute),
+		NotAfter:     claims.Expiry.Time(),
+		URIs:         []*url.URL{spiffeID},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		BasicConstraintsValid: true,
+	}
+
+	svid, err := x509.CreateCertificate(rand.Reader, template, keyset.caCert,
+		req.PublicKey, keyset.caPrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign x509 svid: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svid}), e.x509Bundle(), nil
+}
+
+// x509Bundle returns the PEM encoded CA certificates of all the keys in the
+// keyring, so that SVIDs remain verifiable while the keyring is rotated.
+func (e *Encrypter) x509Bundle() []byte {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	var bundle []byte
+	for _, ks := range e.keyring {
+		if ks.caCert != nil {
+			bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ks.caCert.Raw})...)
+		}
+	}
+	return bundle
+}
+
 // VerifyClaim accepts a previously-signed encoded claim and validates
 // it before returning the claim
 func (e *Encrypter) VerifyClaim(tokenString string) (*structs.IdentityClaims, error) {
@@ -331,22 +552,43 @@ func (e *Encrypter) addCipher(rootKey *structs.RootKey) error {
 		ks.rsaPKCS1PublicKey = x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
 	}
 
+	// Unmarshal the CA for X.509 SVID signing if one exists. Keys created
+	// before X.509 SVIDs were supported have no CA.
+	if len(rootKey.CAKey) > 0 && len(rootKey.CACert) > 0 {
+		caKey, err := x509.ParsePKCS8PrivateKey(rootKey.CAKey)
+		if err != nil {
+			return fmt.Errorf("error parsing ca key: %w", err)
+		}
+		signer, ok := caKey.(gocrypto.Signer)
+		if !ok {
+			return fmt.Errorf("invalid ca key type %T", caKey)
+		}
+		caCert, err := x509.ParseCertificate(rootKey.CACert)
+		if err != nil {
+			return fmt.Errorf("error parsing ca certificate: %w", err)
+		}
+
+		ks.caPrivateKey = signer
+		ks.caCert = caCert
+	}
+
 	e.lock.Lock()
 	defer e.lock.Unlock()
 	e.keyring[rootKey.Meta.KeyID] = &ks
 	return nil
 }
 
-// GetKey retrieves the key material by ID from the keyring
-func (e *Encrypter) GetKey(keyID string) ([]byte, []byte, error) {
+// GetKey retrieves the key material by ID from the keyring. The returned root
+// key must not be modified.
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
 	e.lock.RLock()
 	defer e.lock.RUnlock()
 
 	keyset, err := e.keysetByIDLocked(keyID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return keyset.rootKey.Key, keyset.rootKey.RSAKey, nil
+	return keyset.rootKey, nil
 }
 
 // activeKeySetLocked returns the keyset that belongs to the key marked as
@@ -403,7 +645,6 @@ func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
 	kekWrapper := &structs.KeyEncryptionKeyWrapper{
 		Meta:                       rootKey.Meta,
 		EncryptedDataEncryptionKey: rootBlob.Ciphertext,
-		KeyEncryptionKey:           kek,
 	}
 
 	// Only keysets created after 1.7.0 will contain an RSA key.
@@ -415,19 +656,88 @@ func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
 		kekWrapper.EncryptedRSAKey = rsaBlob.Ciphertext
 	}
 
-	buf, err := json.Marshal(kekWrapper)
-	if err != nil {
-		return err
+	// Only keysets created with X.509 SVID support will contain a CA.
+	if len(rootKey.CAKey) > 0 {
+		caBlob, err := wrapper.Encrypt(e.srv.shutdownCtx, rootKey.CAKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt ca key: %w", err)
+		}
+		kekWrapper.EncryptedCAKey = caBlob.Ciphertext
+		kekWrapper.CACert = rootKey.CACert
 	}
 
-	path := filepath.Join(e.keystorePath, rootKey.Meta.KeyID+nomadKeystoreExtension)
-	err = os.WriteFile(path, buf, 0o600)
-	if err != nil {
-		return err
+	// Write a key file for each provider. Only the KEK differs between them.
+	for _, cfg := range e.kekProviderConfigs {
+		providerWrapper := *kekWrapper
+		if provider, ok := e.kekProviders[cfg.ID()]; ok {
+			wrappedKEK, err := provider.Wrap(e.srv.shutdownCtx, kek)
+			if err != nil {
+				return fmt.Errorf("failed to wrap key encryption key with provider %q: %w", cfg.ID(), err)
+			}
+			providerWrapper.WrappedKeyEncryptionKey = wrappedKEK
+		} else {
+			providerWrapper.KeyEncryptionKey = kek
+		}
+		if cfg.ID() != config.KEKProviderAEAD {
+			providerWrapper.ProviderID = cfg.ID()
+		}
+
+		buf, err := json.Marshal(&providerWrapper)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(e.keystorePath, keystoreFilename(rootKey.Meta.KeyID, cfg))
+		err = os.WriteFile(path, buf, 0o600)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// keystoreFilename returns the name of the key file for a root key saved with
+// a provider. The default aead provider uses the name from before providers
+// were configurable.
+func keystoreFilename(keyID string, cfg *config.KEKProviderConfig) string {
+	if cfg.ID() == config.KEKProviderAEAD {
+		return keyID + nomadKeystoreExtension
+	}
+	return keyID + "." + cfg.ID() + nomadKeystoreExtension
+}
+
+// hasExternalKEKProvider returns true if the active keyring provider holds the
+// key material outside of Nomad.
+func (e *Encrypter) hasExternalKEKProvider() bool {
+	_, ok := e.kekProviders[e.kekProviderConfigs[0].ID()]
+	return ok
+}
+
+// WrapKEK wraps a key encryption key for local use, such as the raft keyring,
+// with the active keyring provider. The default aead provider doesn't wrap the
+// key, so it is stored in the clear alongside the data it protects.
+func (e *Encrypter) WrapKEK(kek []byte) (string, []byte, error) {
+	cfg := e.kekProviderConfigs[0]
+	provider, ok := e.kekProviders[cfg.ID()]
+	if !ok {
+		return "", nil, nil
+	}
+	wrapped, err := provider.Wrap(e.srv.shutdownCtx, kek)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to wrap key encryption key with provider %q: %w", cfg.ID(), err)
+	}
+	return cfg.ID(), wrapped, nil
+}
+
+// UnwrapKEK unwraps a key encryption key wrapped by WrapKEK.
+func (e *Encrypter) UnwrapKEK(providerID string, wrapped []byte) ([]byte, error) {
+	provider, ok := e.kekProviders[providerID]
+	if !ok {
+		return nil, fmt.Errorf("keyring provider %q is not configured", providerID)
+	}
+	return provider.Unwrap(e.srv.shutdownCtx, wrapped)
+}
+
 // loadKeyFromStore deserializes a root key from disk.
 func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 
@@ -446,9 +756,21 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		return nil, err
 	}
 
+	kek := kekWrapper.KeyEncryptionKey
+	if len(kekWrapper.WrappedKeyEncryptionKey) > 0 {
+		provider, ok := e.kekProviders[kekWrapper.ProviderID]
+		if !ok {
+			return nil, fmt.Errorf("keyring provider %q is not configured", kekWrapper.ProviderID)
+		}
+		kek, err = provider.Unwrap(e.srv.shutdownCtx, kekWrapper.WrappedKeyEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unwrap key encryption key: %w", err)
+		}
+	}
+
 	// the errors that bubble up from this library can be a bit opaque, so make
 	// sure we wrap them with as much context as possible
-	wrapper, err := e.newKMSWrapper(meta.KeyID, kekWrapper.KeyEncryptionKey)
+	wrapper, err := e.newKMSWrapper(meta.KeyID, kek)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create key wrapper cipher: %w", err)
 	}
@@ -472,10 +794,23 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		}
 	}
 
+	// Decrypt the CA key for X.509 SVID signing if one exists.
+	var caKey []byte
+	if len(kekWrapper.EncryptedCAKey) > 0 {
+		caKey, err = wrapper.Decrypt(e.srv.shutdownCtx, &kms.BlobInfo{
+			Ciphertext: kekWrapper.EncryptedCAKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt wrapped ca key: %w", err)
+		}
+	}
+
 	return &structs.RootKey{
 		Meta:   meta,
 		Key:    key,
 		RSAKey: rsaKey,
+		CAKey:  caKey,
+		CACert: kekWrapper.CACert,
 	}, nil
 }
 
@@ -504,13 +839,17 @@ func (e *Encrypter) GetPublicKey(keyID string) (*structs.KeyringPublicKey, error
 		pubKey.Algorithm = structs.PubKeyAlgEdDSA
 	}
 
+	if ks.caCert != nil {
+		pubKey.X509CACert = ks.caCert.Raw
+	}
+
 	return pubKey, nil
 }
 
 // newKMSWrapper returns a go-kms-wrapping interface the caller can use to
 // encrypt the RootKey with a key encryption key (KEK). This is a bit of
-// security theatre for local on-disk key material, but gives us a shim for
-// external KMS providers in the future.
+// security theatre for local on-disk key material unless the KEK is wrapped
+// by an external keyring provider.
 func (e *Encrypter) newKMSWrapper(keyID string, kek []byte) (kms.Wrapper, error) {
 	wrapper := aead.NewWrapper()
 	wrapper.SetConfig(context.Background(),
@@ -582,7 +921,7 @@ func (krr *KeyringReplicator) run(ctx context.Context) {
 				}
 
 				keyMeta := raw.(*structs.RootKeyMeta)
-				if key, _, err := krr.encrypter.GetKey(keyMeta.KeyID); err == nil && len(key) > 0 {
+				if key, err := krr.encrypter.GetKey(keyMeta.KeyID); err == nil && len(key.Key) > 0 {
 					// the key material is immutable so if we've already got it
 					// we can move on to the next key
 					continue
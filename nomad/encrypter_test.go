@@ -6,8 +6,13 @@ package nomad
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,8 +27,10 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/kekprovider"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 )
 
@@ -76,15 +83,87 @@ func TestEncrypter_LoadSave(t *testing.T) {
 			must.NoError(t, err)
 			must.NoError(t, encrypter.addCipher(gotKey))
 			must.Greater(t, 0, len(gotKey.RSAKey))
+			must.Eq(t, key.CAKey, gotKey.CAKey)
+			must.Eq(t, key.CACert, gotKey.CACert)
 			must.NoError(t, encrypter.saveKeyToStore(key))
 
 			active, err := encrypter.keysetByIDLocked(key.Meta.KeyID)
 			must.NoError(t, err)
 			must.Greater(t, 0, len(active.rootKey.RSAKey))
+			must.NotNil(t, active.caPrivateKey)
+			must.NotNil(t, active.caCert)
 		})
 	}
 }
 
+// TestEncrypter_KEKProviders exercises migrating the keystore from the default
+// aead provider to an external provider.
+func TestEncrypter_KEKProviders(t *testing.T) {
+	ci.Parallel(t)
+
+	_, transitConfig := kekprovider.TestTransitServer(t, "nomad")
+	transitConfig.Active = true
+
+	srv, cleanupSrv := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	t.Cleanup(cleanupSrv)
+
+	tmpDir := t.TempDir()
+	encrypter, err := NewEncrypter(srv, tmpDir)
+	must.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	must.NoError(t, err)
+	must.NoError(t, encrypter.saveKeyToStore(key))
+
+	aeadPath := filepath.Join(tmpDir, key.Meta.KeyID+".nks.json")
+	transitPath := filepath.Join(tmpDir, key.Meta.KeyID+".transit.nks.json")
+	must.FileExists(t, aeadPath)
+	must.FileNotExists(t, transitPath)
+
+	// Adding the transit provider saves existing keys with it on startup
+	srv.config.KEKProviderConfigs = []*config.KEKProviderConfig{
+		{Provider: config.KEKProviderAEAD},
+		transitConfig,
+	}
+	encrypter, err = NewEncrypter(srv, tmpDir)
+	must.NoError(t, err)
+	must.FileExists(t, transitPath)
+
+	raw, err := os.ReadFile(transitPath)
+	must.NoError(t, err)
+	var kekWrapper structs.KeyEncryptionKeyWrapper
+	must.NoError(t, json.Unmarshal(raw, &kekWrapper))
+	must.Eq(t, "transit", kekWrapper.ProviderID)
+	must.Nil(t, kekWrapper.KeyEncryptionKey)
+	must.SliceNotEmpty(t, kekWrapper.WrappedKeyEncryptionKey)
+
+	// Once the aead provider is removed, keys are loaded with transit alone
+	// and the aead key files are removed
+	srv.config.KEKProviderConfigs = []*config.KEKProviderConfig{transitConfig}
+	encrypter, err = NewEncrypter(srv, tmpDir)
+	must.NoError(t, err)
+	gotKey, err := encrypter.GetKey(key.Meta.KeyID)
+	must.NoError(t, err)
+	must.Eq(t, key.Key, gotKey.Key)
+	must.Eq(t, key.RSAKey, gotKey.RSAKey)
+	must.FileNotExists(t, aeadPath)
+
+	// Local key encryption keys are wrapped with the active provider
+	providerID, wrapped, err := encrypter.WrapKEK(key.Key)
+	must.NoError(t, err)
+	must.Eq(t, "transit", providerID)
+	unwrapped, err := encrypter.UnwrapKEK(providerID, wrapped)
+	must.NoError(t, err)
+	must.Eq(t, key.Key, unwrapped)
+
+	// Without the transit provider the keys can't be loaded
+	srv.config.KEKProviderConfigs = nil
+	_, err = NewEncrypter(srv, tmpDir)
+	must.ErrorContains(t, err, "no key file in keystore can be decrypted")
+}
+
 // TestEncrypter_Restore exercises the entire reload of a keystore,
 // including pairing metadata with key material
 func TestEncrypter_Restore(t *testing.T) {
@@ -438,6 +517,55 @@ func TestEncrypter_SignVerify(t *testing.T) {
 	must.Eq(t, "", got.Issuer)
 }
 
+// TestEncrypter_SignX509SVID asserts that the encrypter signs X.509 SVIDs
+// with the CA of the active root key and the SPIFFE ID of the workload.
+func TestEncrypter_SignX509SVID(t *testing.T) {
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	alloc := mock.Alloc()
+	wid := &structs.WorkloadIdentity{Name: "svid", X509: true, TTL: time.Hour}
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, wiHandle, wid, time.Now())
+	e := srv.encrypter
+
+	csrKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, csrKey)
+	must.NoError(t, err)
+
+	// claims without an expiration can't be signed
+	noExpiry := *claims
+	noExpiry.Expiry = nil
+	_, _, err = e.SignX509SVID(&noExpiry, csr)
+	must.ErrorContains(t, err, "must expire")
+
+	_, _, err = e.SignX509SVID(claims, []byte("garbage"))
+	must.Error(t, err)
+
+	svidPEM, bundlePEM, err := e.SignX509SVID(claims, csr)
+	must.NoError(t, err)
+
+	block, _ := pem.Decode(svidPEM)
+	must.NotNil(t, block)
+	svid, err := x509.ParseCertificate(block.Bytes)
+	must.NoError(t, err)
+	must.SliceLen(t, 1, svid.URIs)
+	must.Eq(t, claims.SPIFFEID("global"), svid.URIs[0].String())
+	must.Eq(t, claims.Expiry.Time().Unix(), svid.NotAfter.Unix())
+
+	roots := x509.NewCertPool()
+	must.True(t, roots.AppendCertsFromPEM(bundlePEM))
+	_, err = svid.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	must.NoError(t, err)
+}
+
 // TestEncrypter_SignVerify_Issuer asserts that the signer adds an issuer if it
 // is configured.
 func TestEncrypter_SignVerify_Issuer(t *testing.T) {
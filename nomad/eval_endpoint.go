@@ -56,11 +56,10 @@ func (e *Eval) GetEval(args *structs.EvalSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "eval", "get_eval"}, time.Now())
 
 	// Check for read-job permissions before performing blocking query.
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := e.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowNsOpAnyJob(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -78,8 +77,9 @@ func (e *Eval) GetEval(args *structs.EvalSpecificRequest,
 			}
 
 			if eval != nil {
-				// Re-check namespace in case it differs from request.
-				if !allowNsOp(aclObj, eval.Namespace) {
+				// Re-check namespace in case it differs from request, and the
+				// job of the evaluation.
+				if !aclObj.AllowJobOp(eval.Namespace, eval.JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.ErrPermissionDenied
 				}
 
@@ -656,10 +656,11 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := aclObj.AllowNsOpAnyJobFunc(acl.NamespaceCapabilityReadJob)
+	allowJob := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadJob)
 
 	if args.Filter != "" {
 		// Check for incompatible filtering.
@@ -723,6 +724,9 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 					paginator.NamespaceFilter{
 						AllowableNamespaces: allowableNamespaces,
 					},
+					paginator.JobFilter{
+						Allow: allowJob,
+					},
 				}
 
 				var evals []*structs.Evaluation
@@ -780,10 +784,11 @@ func (e *Eval) Count(args *structs.EvalCountRequest, reply *structs.EvalCountRes
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := aclObj.AllowNsOpAnyJobFunc(acl.NamespaceCapabilityReadJob)
+	allowJob := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadJob)
 
 	var filter *bexpr.Evaluator
 	if args.Filter != "" {
@@ -831,6 +836,9 @@ func (e *Eval) Count(args *structs.EvalCountRequest, reply *structs.EvalCountRes
 				if allowableNamespaces != nil && !allowableNamespaces[eval.Namespace] {
 					return true
 				}
+				if !allowJob(eval.Namespace, eval.JobID) {
+					return true
+				}
 				if filter != nil {
 					ok, err := filter.Evaluate(eval)
 					if err != nil {
@@ -880,11 +888,10 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "eval", "allocations"}, time.Now())
 
 	// Check for read-job permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
 	aclObj, err := e.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+	} else if !aclObj.AllowNsOpAnyJob(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -901,10 +908,10 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 
 			// Convert to a stub
 			if len(allocs) > 0 {
-				// Evaluations do not span namespaces so just check the
-				// first allocs namespace.
-				ns := allocs[0].Namespace
-				if ns != args.RequestNamespace() && !allowNsOp(aclObj, ns) {
+				// Evaluations do not span namespaces or jobs so just check
+				// the first allocs namespace and job.
+				alloc := allocs[0]
+				if !aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadJob) {
 					return structs.ErrPermissionDenied
 				}
 
@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventSink endpoint is used for event sink management. Event sinks are
+// destinations the leader delivers the event stream to. Since sinks can
+// write files and run commands on the servers, reading their full
+// configuration and modifying them requires a management token, and file and
+// exec sinks must be allowed by the agent configuration of the leader.
+type EventSink struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewEventSinkEndpoint(srv *Server, ctx *RPCContext) *EventSink {
+	return &EventSink{srv: srv, ctx: ctx}
+}
+
+// List is used to list the event sinks. The response only includes the sink
+// stubs, which don't include the delivery configuration.
+func (e *EventSink) List(args *structs.EventSinkListRequest, reply *structs.EventSinkListResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.List", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "list"}, time.Now())
+
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.EventSinks(ws)
+			if err != nil {
+				return err
+			}
+
+			sinks := []*structs.EventSinkListStub{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				sinks = append(sinks, raw.(*structs.EventSink).Stub())
+			}
+			reply.Sinks = sinks
+
+			// Use the last index that affected the event sinks table.
+			return e.srv.setReplyQueryMeta(store, state.TableEventSinks, &reply.QueryMeta)
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
+// GetSink returns the specific event sink requested or nil if the sink
+// doesn't exist.
+func (e *EventSink) GetSink(args *structs.EventSinkSpecificRequest, reply *structs.EventSinkResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.GetSink", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "get_sink"}, time.Now())
+
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			sink, err := store.EventSinkByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+
+			reply.Sink = sink
+			if sink != nil {
+				reply.Index = sink.ModifyIndex
+				e.srv.setQueryMeta(&reply.QueryMeta)
+				return nil
+			}
+
+			// Return the last index that affected the event sinks table if
+			// the requested sink doesn't exist.
+			return e.srv.setReplyQueryMeta(store, state.TableEventSinks, &reply.QueryMeta)
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
+// UpsertSinks creates or updates the given event sinks.
+func (e *EventSink) UpsertSinks(args *structs.EventSinkUpsertRequest, reply *structs.GenericResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.UpsertSinks", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "upsert_sinks"}, time.Now())
+
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		e.srv.serf.Members(), e.srv.Region(), minEventSinksVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to upsert event sinks", minEventSinksVersion)
+	}
+
+	if len(args.Sinks) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one event sink")
+	}
+
+	var mErr multierror.Error
+	for _, sink := range args.Sinks {
+		if sink == nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "event sink is empty")
+		}
+		sink.Canonicalize()
+		if err := sink.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("invalid event sink %q: %w", sink.ID, err))
+			continue
+		}
+		if err := e.srv.config.EventSinkAllowlist.Allows(sink); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("invalid event sink %q: %w", sink.ID, err))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "%v", err)
+	}
+
+	_, index, err := e.srv.raftApply(structs.EventSinksUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// DeleteSinks deletes the event sinks with the given IDs.
+func (e *EventSink) DeleteSinks(args *structs.EventSinkDeleteRequest, reply *structs.GenericResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.DeleteSinks", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "delete_sinks"}, time.Now())
+
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		e.srv.serf.Members(), e.srv.Region(), minEventSinksVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to delete event sinks", minEventSinksVersion)
+	}
+
+	if len(args.IDs) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one event sink to delete")
+	}
+	for _, id := range args.IDs {
+		if id == "" {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "event sink ID is empty")
+		}
+	}
+
+	_, index, err := e.srv.raftApply(structs.EventSinksDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestEventSinkEndpoint_CRUD(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.EventSinkAllowlist = &structs.EventSinkAllowlist{
+			Directories: []string{"/var/log"},
+		}
+	})
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Upsert a sink without defaults, which are set by the server.
+	sink := &structs.EventSink{
+		ID:   "audit",
+		Type: structs.EventSinkFile,
+		Path: "/var/log/nomad-events.json",
+	}
+	upsertReq := &structs.EventSinkUpsertRequest{
+		Sinks:        []*structs.EventSink{sink},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "EventSink.UpsertSinks", upsertReq, &upsertResp)
+	must.NoError(t, err)
+	must.NonZero(t, upsertResp.Index)
+
+	getReq := &structs.EventSinkSpecificRequest{
+		ID:           sink.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.EventSinkResponse
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.GetSink", getReq, &getResp)
+	must.NoError(t, err)
+	must.NotNil(t, getResp.Sink)
+	must.Eq(t, structs.AllNamespacesSentinel, getResp.Sink.Namespace)
+	must.Eq(t, structs.DefaultEventSinkBatchSize, getResp.Sink.BatchSize)
+	must.Eq(t, upsertResp.Index, getResp.Sink.LatestIndex)
+	must.Eq(t, upsertResp.Index, getResp.Index)
+
+	listReq := &structs.EventSinkListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.EventSinkListResponse
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.List", listReq, &listResp)
+	must.NoError(t, err)
+	must.Len(t, 1, listResp.Sinks)
+	must.Eq(t, sink.ID, listResp.Sinks[0].ID)
+
+	// Invalid sinks are rejected.
+	upsertReq.Sinks = []*structs.EventSink{{ID: "bad", Type: structs.EventSinkFile, Path: "relative"}}
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.UpsertSinks", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "path must be absolute")
+
+	// File and exec sinks must be allowed by the server configuration.
+	upsertReq.Sinks = []*structs.EventSink{{ID: "bad", Type: structs.EventSinkFile, Path: "/etc/passwd"}}
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.UpsertSinks", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "not in a directory allowed by the server configuration")
+
+	upsertReq.Sinks = []*structs.EventSink{{ID: "bad", Type: structs.EventSinkExec, Command: "/bin/sh"}}
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.UpsertSinks", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "is not allowed by the server configuration")
+
+	deleteReq := &structs.EventSinkDeleteRequest{
+		IDs:          []string{sink.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deleteResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.DeleteSinks", deleteReq, &deleteResp)
+	must.NoError(t, err)
+
+	getResp = structs.EventSinkResponse{}
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.GetSink", getReq, &getResp)
+	must.NoError(t, err)
+	must.Nil(t, getResp.Sink)
+
+	// Deleting a sink that doesn't exist is an error.
+	err = msgpackrpc.CallWithCodec(codec, "EventSink.DeleteSinks", deleteReq, &deleteResp)
+	must.ErrorContains(t, err, "not found")
+}
+
+func TestEventSinkEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	sink := mock.EventSink()
+	must.NoError(t, store.UpsertEventSinks(structs.MsgTypeTestSetup, 1000, []*structs.EventSink{sink}))
+
+	operatorToken := mock.CreatePolicyAndToken(t, store, 1001, "operator-write",
+		`operator { policy = "write" }`)
+	nsToken := mock.CreatePolicyAndToken(t, store, 1003, "default-write",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil))
+
+	testCases := []struct {
+		name      string
+		token     string
+		expList   bool
+		expManage bool
+	}{
+		{
+			name:      "management token",
+			token:     root.SecretID,
+			expList:   true,
+			expManage: true,
+		},
+		{
+			name:    "operator token can only list",
+			token:   operatorToken.SecretID,
+			expList: true,
+		},
+		{
+			name:  "namespace token denied",
+			token: nsToken.SecretID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listReq := &structs.EventSinkListRequest{
+				QueryOptions: structs.QueryOptions{Region: "global", AuthToken: tc.token},
+			}
+			var listResp structs.EventSinkListResponse
+			err := msgpackrpc.CallWithCodec(codec, "EventSink.List", listReq, &listResp)
+			if tc.expList {
+				must.NoError(t, err)
+				must.SliceNotEmpty(t, listResp.Sinks)
+			} else {
+				must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+			}
+
+			getReq := &structs.EventSinkSpecificRequest{
+				ID:           sink.ID,
+				QueryOptions: structs.QueryOptions{Region: "global", AuthToken: tc.token},
+			}
+			var getResp structs.EventSinkResponse
+			err = msgpackrpc.CallWithCodec(codec, "EventSink.GetSink", getReq, &getResp)
+			if tc.expManage {
+				must.NoError(t, err)
+				must.NotNil(t, getResp.Sink)
+			} else {
+				must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+			}
+
+			upsertReq := &structs.EventSinkUpsertRequest{
+				Sinks:        []*structs.EventSink{mock.EventSink()},
+				WriteRequest: structs.WriteRequest{Region: "global", AuthToken: tc.token},
+			}
+			var upsertResp structs.GenericResponse
+			err = msgpackrpc.CallWithCodec(codec, "EventSink.UpsertSinks", upsertReq, &upsertResp)
+			if tc.expManage {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+			}
+		})
+	}
+}
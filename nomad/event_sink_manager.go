@@ -0,0 +1,430 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// eventSinkProgressInterval is how often the leader records the delivery
+	// progress of the event sinks in raft. Events delivered since the last
+	// update are delivered again after a leader election.
+	eventSinkProgressInterval = 5 * time.Second
+
+	// eventSinkRetryBase and eventSinkRetryLimit bound the backoff between
+	// attempts to deliver a batch of events to a failing sink.
+	eventSinkRetryBase  = time.Second
+	eventSinkRetryLimit = time.Minute
+)
+
+// eventSinkManager runs on the leader and delivers the event stream to every
+// registered event sink. Each sink is served by its own runner, which
+// subscribes to the event broker starting after the last index recorded for
+// the sink and delivers batches until it is stopped. Delivery is at least
+// once: a batch is retried until the sink accepts it, and the manager
+// periodically writes the progress of the runners to raft.
+type eventSinkManager struct {
+	srv    *Server
+	logger log.Logger
+
+	// runners is only accessed from the run goroutine.
+	runners map[string]*eventSinkRunner
+
+	// failed maps the IDs of the sinks whose runner failed to start to the
+	// ModifyIndex of the failed version, so the failure is only logged once.
+	// It is only accessed from the run goroutine.
+	failed map[string]uint64
+}
+
+func newEventSinkManager(srv *Server) *eventSinkManager {
+	return &eventSinkManager{
+		srv:     srv,
+		logger:  srv.logger.Named("event_sinks"),
+		runners: make(map[string]*eventSinkRunner),
+		failed:  make(map[string]uint64),
+	}
+}
+
+// run reconciles the runners with the event sinks in the state store until
+// stopCh is closed.
+func (m *eventSinkManager) run(stopCh <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if _, err := m.srv.State().EventBroker(); err != nil {
+		m.logger.Warn("event broker is disabled, events will not be delivered to event sinks")
+		return
+	}
+
+	defer m.stopAll()
+
+	ticker := time.NewTicker(eventSinkProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		store := m.srv.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+
+		iter, err := store.EventSinks(ws)
+		if err != nil {
+			m.logger.Error("failed to read event sinks", "error", err)
+		} else {
+			var sinks []*structs.EventSink
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				sinks = append(sinks, raw.(*structs.EventSink))
+			}
+			m.reconcile(ctx, sinks)
+		}
+
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		watchCh := ws.WatchCh(watchCtx)
+
+		select {
+		case <-ctx.Done():
+			watchCancel()
+			return
+		case <-ticker.C:
+			m.recordProgress()
+		case <-watchCh:
+		}
+		watchCancel()
+	}
+}
+
+// reconcile starts a runner for every new sink, restarts the runners of
+// sinks that were modified and stops the runners of deleted sinks.
+func (m *eventSinkManager) reconcile(ctx context.Context, sinks []*structs.EventSink) {
+	seen := make(map[string]struct{}, len(sinks))
+	for _, sink := range sinks {
+		seen[sink.ID] = struct{}{}
+
+		runner, ok := m.runners[sink.ID]
+		if ok && runner.sink.ModifyIndex == sink.ModifyIndex {
+			continue
+		}
+		if index, failed := m.failed[sink.ID]; failed && index == sink.ModifyIndex {
+			continue
+		}
+		if ok {
+			runner.stop()
+		}
+
+		runner, err := newEventSinkRunner(m.logger, sink.Copy(), m.srv.config.EventSinkAllowlist)
+		if err != nil {
+			m.logger.Error("failed to start event sink", "sink_id", sink.ID, "error", err)
+			delete(m.runners, sink.ID)
+			m.failed[sink.ID] = sink.ModifyIndex
+			continue
+		}
+		delete(m.failed, sink.ID)
+		runCtx, runCancel := context.WithCancel(ctx)
+		runner.cancel = runCancel
+		m.runners[sink.ID] = runner
+		go runner.run(runCtx, m.srv.State)
+	}
+
+	for id, runner := range m.runners {
+		if _, ok := seen[id]; !ok {
+			runner.stop()
+			delete(m.runners, id)
+		}
+	}
+	for id := range m.failed {
+		if _, ok := seen[id]; !ok {
+			delete(m.failed, id)
+		}
+	}
+}
+
+// recordProgress writes the index of the last event set delivered by every
+// runner that made progress since the last update to raft, and the gaps the
+// runners detected.
+func (m *eventSinkManager) recordProgress() {
+	var progress []*structs.EventSinkProgress
+	for _, runner := range m.runners {
+		latest := runner.latest.Load()
+		gaps, lastGap := runner.takeGaps()
+		if latest > runner.recorded || gaps > 0 {
+			progress = append(progress, &structs.EventSinkProgress{
+				ID:          runner.sink.ID,
+				ModifyIndex: runner.sink.ModifyIndex,
+				LatestIndex: latest,
+				Gaps:        gaps,
+				LastGap:     lastGap,
+			})
+		}
+	}
+	if len(progress) == 0 {
+		return
+	}
+
+	req := structs.EventSinkProgressRequest{
+		Progress:     progress,
+		WriteRequest: structs.WriteRequest{Region: m.srv.Region()},
+	}
+	if _, _, err := m.srv.raftApply(structs.EventSinksProgressRequestType, &req); err != nil {
+		m.logger.Error("failed to record event sink progress", "error", err)
+		for _, p := range progress {
+			m.runners[p.ID].addGaps(p.Gaps, p.LastGap)
+		}
+		return
+	}
+
+	for _, p := range progress {
+		m.runners[p.ID].recorded = p.LatestIndex
+	}
+}
+
+// stopAll stops every runner. Progress that was not recorded yet is lost and
+// the events are delivered again by the next leader.
+func (m *eventSinkManager) stopAll() {
+	for id, runner := range m.runners {
+		runner.stop()
+		delete(m.runners, id)
+	}
+}
+
+// eventSinkRunner delivers events to a single version of an event sink.
+type eventSinkRunner struct {
+	sink   *structs.EventSink
+	writer stream.SinkWriter
+	logger log.Logger
+
+	// latest is the index of the last event set delivered to the sink.
+	latest atomic.Uint64
+
+	// recorded is the last index written to raft. It is only accessed by the
+	// manager.
+	recorded uint64
+
+	// gaps is the number of gaps detected since they were last written to
+	// raft, and lastGap the most recent of them. Must hold gapLock.
+	gaps    uint64
+	lastGap *structs.EventSinkGap
+	gapLock sync.Mutex
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+func newEventSinkRunner(logger log.Logger, sink *structs.EventSink, allowlist *structs.EventSinkAllowlist) (*eventSinkRunner, error) {
+	// The sink was allowed by the leader it was registered with, which may
+	// have a different configuration than this one
+	if err := allowlist.Allows(sink); err != nil {
+		return nil, err
+	}
+
+	writer, err := stream.NewSinkWriter(sink)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &eventSinkRunner{
+		sink:     sink,
+		writer:   writer,
+		logger:   logger.With("sink_id", sink.ID, "sink_type", sink.Type),
+		recorded: sink.LatestIndex,
+		doneCh:   make(chan struct{}),
+	}
+	r.latest.Store(sink.LatestIndex)
+	return r, nil
+}
+
+// run delivers events until the context is canceled. The state store is
+// looked up every time the runner subscribes since it is replaced when a
+// snapshot is restored.
+func (r *eventSinkRunner) run(ctx context.Context, stateFn func() *state.StateStore) {
+	defer close(r.doneCh)
+	defer r.writer.Close()
+
+	var attempt uint64
+	for {
+		before := r.latest.Load()
+		err := r.deliver(ctx, stateFn)
+		if ctx.Err() != nil {
+			return
+		}
+		if r.latest.Load() != before {
+			attempt = 0
+		}
+
+		backoff := helper.Backoff(eventSinkRetryBase, eventSinkRetryLimit, attempt)
+		attempt++
+		r.logger.Warn("event sink subscription failed", "error", err, "retry_in", backoff)
+
+		timer, timerStop := helper.NewSafeTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timerStop()
+			return
+		case <-timer.C:
+		}
+		timerStop()
+	}
+}
+
+// deliver subscribes to the event broker after the last delivered index and
+// delivers batches of events until the subscription fails or the context is
+// canceled. Events of a partially filled batch are received again by the
+// next subscription.
+func (r *eventSinkRunner) deliver(ctx context.Context, stateFn func() *state.StateStore) error {
+	broker, err := stateFn().EventBroker()
+	if err != nil {
+		return err
+	}
+
+	latest := r.latest.Load()
+	sub, err := broker.Subscribe(&stream.SubscribeRequest{
+		Index:     latest + 1,
+		Namespace: r.sink.Namespace,
+		Topics:    r.sink.Topics,
+	})
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	// The subscription starts at the closest index still in the buffer, so
+	// events published after the last delivered index may have been dropped
+	if dropped := broker.DroppedIndex(); dropped > latest {
+		r.reportGap(latest, dropped)
+	}
+
+	batchSize := r.sink.BatchSize
+	if batchSize <= 0 {
+		batchSize = structs.DefaultEventSinkBatchSize
+	}
+
+	var batch []*structs.Events
+	var deadline time.Time
+	for {
+		nextCtx, nextCancel := ctx, context.CancelFunc(func() {})
+		if len(batch) > 0 {
+			nextCtx, nextCancel = context.WithDeadline(ctx, deadline)
+		}
+		events, err := sub.Next(nextCtx)
+		nextCancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			// The batch interval elapsed, so deliver what we have.
+		case err != nil:
+			return err
+		case events.Index <= latest:
+			// The broker starts at the closest index it holds, which may
+			// have been delivered already.
+			continue
+		default:
+			if len(batch) == 0 {
+				deadline = time.Now().Add(r.sink.BatchInterval)
+			}
+			batch = append(batch, &events)
+			if len(batch) < batchSize {
+				continue
+			}
+		}
+
+		if err := r.send(ctx, batch); err != nil {
+			return err
+		}
+		latest = batch[len(batch)-1].Index
+		r.latest.Store(latest)
+		batch = nil
+	}
+}
+
+// send delivers the batch, retrying with a backoff until it succeeds or the
+// context is canceled.
+func (r *eventSinkRunner) send(ctx context.Context, batch []*structs.Events) error {
+	var attempt uint64
+	for {
+		err := r.writer.Send(ctx, batch)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		backoff := helper.Backoff(eventSinkRetryBase, eventSinkRetryLimit, attempt)
+		attempt++
+		r.logger.Warn("failed to deliver events", "error", err, "retry_in", backoff)
+
+		timer, timerStop := helper.NewSafeTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timerStop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		timerStop()
+	}
+}
+
+// reportGap records that the events published after the from index and up to
+// the to index may have been dropped before they could be delivered.
+func (r *eventSinkRunner) reportGap(from, to uint64) {
+	r.logger.Error("events were dropped from the event buffer before they could be delivered",
+		"from_index", from, "to_index", to)
+	metrics.IncrCounterWithLabels([]string{"nomad", "event_sink", "gap"}, 1,
+		[]metrics.Label{{Name: "sink_id", Value: r.sink.ID}})
+
+	r.addGaps(1, &structs.EventSinkGap{
+		FromIndex: from,
+		ToIndex:   to,
+		Time:      time.Now().UTC(),
+	})
+}
+
+// addGaps adds gaps to record in raft.
+func (r *eventSinkRunner) addGaps(gaps uint64, lastGap *structs.EventSinkGap) {
+	if gaps == 0 {
+		return
+	}
+
+	r.gapLock.Lock()
+	defer r.gapLock.Unlock()
+	r.gaps += gaps
+	if r.lastGap == nil || lastGap.ToIndex >= r.lastGap.ToIndex {
+		r.lastGap = lastGap
+	}
+}
+
+// takeGaps returns the gaps detected since the last call.
+func (r *eventSinkRunner) takeGaps() (uint64, *structs.EventSinkGap) {
+	r.gapLock.Lock()
+	defer r.gapLock.Unlock()
+	gaps, lastGap := r.gaps, r.lastGap
+	r.gaps, r.lastGap = 0, nil
+	return gaps, lastGap
+}
+
+// stop stops the runner and waits for it to exit.
+func (r *eventSinkRunner) stop() {
+	r.cancel()
+	<-r.doneCh
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestEventSinkManager_FileSink(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.EventSinkAllowlist = &structs.EventSinkAllowlist{
+			Directories: []string{dir},
+		}
+	})
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	path := filepath.Join(dir, "events.json")
+	sink := &structs.EventSink{
+		ID:            "jobs",
+		Type:          structs.EventSinkFile,
+		Path:          path,
+		Topics:        map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		BatchSize:     1,
+		BatchInterval: 10 * time.Millisecond,
+	}
+	sink.Canonicalize()
+	must.NoError(t, store.UpsertEventSinks(structs.MsgTypeTestSetup, 1000, []*structs.EventSink{sink}))
+
+	// Jobs registered after the index the sink was created at are delivered,
+	// even if they are published before the leader subscribed for the sink.
+	job1 := mock.Job()
+	job2 := mock.Job()
+	must.NoError(t, store.UpsertJob(structs.JobRegisterRequestType, 1001, nil, job1))
+	must.NoError(t, store.UpsertJob(structs.JobRegisterRequestType, 1002, nil, job2))
+
+	// Both job events are appended to the file.
+	readJobIDs := func() []string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			var events structs.Events
+			must.NoError(t, json.Unmarshal([]byte(line), &events))
+			for _, e := range events.Events {
+				must.Eq(t, structs.TopicJob, e.Topic)
+				ids = append(ids, e.Key)
+			}
+		}
+		return ids
+	}
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			ids := readJobIDs()
+			if len(ids) != 2 {
+				return fmt.Errorf("expected 2 job events, got %v", ids)
+			}
+			return nil
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+	must.Eq(t, []string{job1.ID, job2.ID}, readJobIDs())
+
+	// The progress of the sink is recorded in raft.
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			out, err := store.EventSinkByID(nil, sink.ID)
+			must.NoError(t, err)
+			if out.LatestIndex != 1002 {
+				return fmt.Errorf("expected latest index 1002, got %d", out.LatestIndex)
+			}
+			return nil
+		}),
+		wait.Timeout(3*eventSinkProgressInterval),
+		wait.Gap(100*time.Millisecond),
+	))
+}
+
+func TestEventSinkManager_Gap(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.EventBufferSize = 2
+		c.EventSinkAllowlist = &structs.EventSinkAllowlist{
+			Directories: []string{dir},
+		}
+	})
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	// The file can't be created until its directory exists, so the first
+	// delivery is retried while the events after it are dropped from the
+	// event buffer.
+	path := filepath.Join(dir, "sub", "events.json")
+	sink := &structs.EventSink{
+		ID:            "jobs",
+		Type:          structs.EventSinkFile,
+		Path:          path,
+		Topics:        map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		BatchSize:     1,
+		BatchInterval: 10 * time.Millisecond,
+	}
+	sink.Canonicalize()
+	must.NoError(t, store.UpsertEventSinks(structs.MsgTypeTestSetup, 1000, []*structs.EventSink{sink}))
+
+	for i := uint64(1); i <= 5; i++ {
+		must.NoError(t, store.UpsertJob(structs.JobRegisterRequestType, 1000+i, nil, mock.Job()))
+	}
+	must.NoError(t, os.Mkdir(filepath.Dir(path), 0o700))
+
+	// The gap is recorded in raft.
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			out, err := store.EventSinkByID(nil, sink.ID)
+			must.NoError(t, err)
+			if out.Gaps == 0 {
+				return fmt.Errorf("expected gap to be recorded")
+			}
+			return nil
+		}),
+		wait.Timeout(4*eventSinkProgressInterval),
+		wait.Gap(100*time.Millisecond),
+	))
+
+	out, err := store.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out.LastGap)
+	must.GreaterEq(t, 1000, out.LastGap.FromIndex)
+	must.Greater(t, out.LastGap.FromIndex, out.LastGap.ToIndex)
+}
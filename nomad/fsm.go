@@ -66,6 +66,9 @@ const (
 	ACLBindingRuleSnapshot               SnapshotType = 27
 	NodePoolSnapshot                     SnapshotType = 28
 	JobSubmissionSnapshot                SnapshotType = 29
+	NetworkPolicySnapshot                SnapshotType = 30
+	EventSinksSnapshot                   SnapshotType = 31
+	VariableVersionSnapshot              SnapshotType = 32
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -102,6 +105,9 @@ var snapshotTypeStrings = map[SnapshotType]string{
 	ACLBindingRuleSnapshot:               "ACLBindingRule",
 	NodePoolSnapshot:                     "NodePool",
 	JobSubmissionSnapshot:                "JobSubmission",
+	NetworkPolicySnapshot:                "NetworkPolicy",
+	EventSinksSnapshot:                   "EventSinks",
+	VariableVersionSnapshot:              "VariableVersion",
 	NamespaceSnapshot:                    "Namespace",
 }
 
@@ -271,6 +277,16 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyNodePoolUpsert(msgType, buf[1:], log.Index)
 	case structs.NodePoolDeleteRequestType:
 		return n.applyNodePoolDelete(msgType, buf[1:], log.Index)
+	case structs.NetworkPolicyUpsertRequestType:
+		return n.applyNetworkPolicyUpsert(msgType, buf[1:], log.Index)
+	case structs.NetworkPolicyDeleteRequestType:
+		return n.applyNetworkPolicyDelete(msgType, buf[1:], log.Index)
+	case structs.EventSinksUpsertRequestType:
+		return n.applyEventSinksUpsert(msgType, buf[1:], log.Index)
+	case structs.EventSinksDeleteRequestType:
+		return n.applyEventSinksDelete(msgType, buf[1:], log.Index)
+	case structs.EventSinksProgressRequestType:
+		return n.applyEventSinksProgress(msgType, buf[1:], log.Index)
 	case structs.JobRegisterRequestType:
 		return n.applyUpsertJob(msgType, buf[1:], log.Index)
 	case structs.JobDeregisterRequestType:
@@ -369,6 +385,8 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeleteServiceRegistrationByNodeID(msgType, buf[1:], log.Index)
 	case structs.VarApplyStateRequestType:
 		return n.applyVariableOperation(msgType, buf[1:], log.Index)
+	case structs.VariableVersionsDeleteRequestType:
+		return n.applyVariableVersionsDelete(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaUpsertRequestType:
 		return n.applyRootKeyMetaUpsert(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaDeleteRequestType:
@@ -597,6 +615,81 @@ func (n *nomadFSM) applyNodePoolDelete(msgType structs.MessageType, buf []byte,
 	return nil
 }
 
+func (n *nomadFSM) applyNetworkPolicyUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_network_policy_upsert"}, time.Now())
+	var req structs.NetworkPolicyUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNetworkPolicies(msgType, index, req.NetworkPolicies); err != nil {
+		n.logger.Error("UpsertNetworkPolicies failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyNetworkPolicyDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_network_policy_delete"}, time.Now())
+	var req structs.NetworkPolicyDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteNetworkPolicies(msgType, index, req.RequestNamespace(), req.Names); err != nil {
+		n.logger.Error("DeleteNetworkPolicies failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyEventSinksUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sinks_upsert"}, time.Now())
+	var req structs.EventSinkUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertEventSinks(msgType, index, req.Sinks); err != nil {
+		n.logger.Error("UpsertEventSinks failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyEventSinksDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sinks_delete"}, time.Now())
+	var req structs.EventSinkDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteEventSinks(msgType, index, req.IDs); err != nil {
+		n.logger.Error("DeleteEventSinks failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyEventSinksProgress(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sinks_progress"}, time.Now())
+	var req structs.EventSinkProgressRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateEventSinksProgress(msgType, index, req.Progress); err != nil {
+		n.logger.Error("UpdateEventSinksProgress failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -1811,6 +1904,16 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case VariableVersionSnapshot:
+			version := new(structs.VariableVersion)
+			if err := dec.Decode(version); err != nil {
+				return err
+			}
+
+			if err := restore.VariableVersionRestore(version); err != nil {
+				return err
+			}
+
 		case VariablesQuotaSnapshot:
 			quota := new(structs.VariablesQuota)
 			if err := dec.Decode(quota); err != nil {
@@ -1892,6 +1995,30 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case NetworkPolicySnapshot:
+			policy := new(structs.NetworkPolicy)
+
+			if err := dec.Decode(policy); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.NetworkPolicyRestore(policy); err != nil {
+				return err
+			}
+
+		case EventSinksSnapshot:
+			sink := new(structs.EventSink)
+
+			if err := dec.Decode(sink); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.EventSinkRestore(sink); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -2288,6 +2415,8 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 		return n.state.VarLockAcquire(index, &req)
 	case structs.VarOpLockRelease:
 		return n.state.VarLockRelease(index, &req)
+	case structs.VarOpRekey:
+		return n.state.VarRekey(index, &req)
 	default:
 		err := fmt.Errorf("Invalid variable operation '%s'", req.Op)
 		n.logger.Warn("Invalid variable operation", "operation", req.Op)
@@ -2295,6 +2424,22 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 	}
 }
 
+func (n *nomadFSM) applyVariableVersionsDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_versions_delete"}, time.Now())
+
+	var req structs.VariablesDeleteVersionsRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteVariableVersions(msgType, index, req.Versions); err != nil {
+		n.logger.Error("DeleteVariableVersions failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
 
@@ -2447,6 +2592,10 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistVariableVersions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistRootKeyMeta(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2467,6 +2616,14 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistNetworkPolicies(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistEventSinks(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -3069,6 +3226,29 @@ func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistVariableVersions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	versions, err := s.snap.VariableVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := versions.Next()
+		if raw == nil {
+			break
+		}
+		version := raw.(*structs.VariableVersion)
+		sink.Write([]byte{byte(VariableVersionSnapshot)})
+		if err := encoder.Encode(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistVariablesQuotas(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 
@@ -3204,6 +3384,48 @@ func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink, encoder *c
 	return nil
 }
 
+func (s *nomadSnapshot) persistNetworkPolicies(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+
+	// Get all the network policies.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.NetworkPolicies(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		policy := raw.(*structs.NetworkPolicy)
+
+		// write the snapshot
+		sink.Write([]byte{byte(NetworkPolicySnapshot)})
+		if err := encoder.Encode(policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistEventSinks(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+
+	// Get all the event sinks.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.EventSinks(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		eventSink := raw.(*structs.EventSink)
+
+		// write the snapshot
+		sink.Write([]byte{byte(EventSinksSnapshot)})
+		if err := encoder.Encode(eventSink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
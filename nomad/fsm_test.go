@@ -674,6 +674,90 @@ func TestFSM_NodePoolDelete(t *testing.T) {
 	}
 }
 
+func TestFSM_NetworkPolicyUpsertDelete(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	policies := []*structs.NetworkPolicy{
+		mock.NetworkPolicy(),
+		mock.NetworkPolicy(),
+	}
+	req := structs.NetworkPolicyUpsertRequest{
+		NetworkPolicies: policies,
+	}
+	buf, err := structs.Encode(structs.NetworkPolicyUpsertRequestType, req)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	for _, policy := range policies {
+		got, err := fsm.State().NetworkPolicyByName(nil, policy.Namespace, policy.Name)
+		must.NoError(t, err)
+		must.NotNil(t, got)
+	}
+
+	delReq := structs.NetworkPolicyDeleteRequest{
+		Names: []string{policies[0].Name},
+		WriteRequest: structs.WriteRequest{
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	buf, err = structs.Encode(structs.NetworkPolicyDeleteRequestType, delReq)
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	got, err := fsm.State().NetworkPolicyByName(nil, policies[0].Namespace, policies[0].Name)
+	must.NoError(t, err)
+	must.Nil(t, got)
+
+	got, err = fsm.State().NetworkPolicyByName(nil, policies[1].Namespace, policies[1].Name)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+}
+
+func TestFSM_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	sinks := []*structs.EventSink{mock.EventSink(), mock.EventSink()}
+	buf, err := structs.Encode(structs.EventSinksUpsertRequestType,
+		structs.EventSinkUpsertRequest{Sinks: sinks})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	got, err := fsm.State().EventSinkByID(nil, sinks[0].ID)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+	modifyIndex := got.ModifyIndex
+
+	// Progress updates only move the latest index of the sink.
+	buf, err = structs.Encode(structs.EventSinksProgressRequestType,
+		structs.EventSinkProgressRequest{Progress: []*structs.EventSinkProgress{{
+			ID:          sinks[0].ID,
+			ModifyIndex: modifyIndex,
+			LatestIndex: 100,
+		}}})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	got, err = fsm.State().EventSinkByID(nil, sinks[0].ID)
+	must.NoError(t, err)
+	must.Eq(t, 100, got.LatestIndex)
+	must.Eq(t, modifyIndex, got.ModifyIndex)
+
+	buf, err = structs.Encode(structs.EventSinksDeleteRequestType,
+		structs.EventSinkDeleteRequest{IDs: []string{sinks[0].ID}})
+	must.NoError(t, err)
+	must.Nil(t, fsm.Apply(makeLog(buf)))
+
+	got, err = fsm.State().EventSinkByID(nil, sinks[0].ID)
+	must.NoError(t, err)
+	must.Nil(t, got)
+
+	got, err = fsm.State().EventSinkByID(nil, sinks[1].ID)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+}
+
 func TestFSM_NodePoolUpsert(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2445,6 +2529,42 @@ func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
 	must.Eq(t, pool, out)
 }
 
+func TestFSM_SnapshotRestore_NetworkPolicies(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	policy := mock.NetworkPolicy()
+	must.NoError(t, state.UpsertNetworkPolicies(structs.MsgTypeTestSetup, 1000,
+		[]*structs.NetworkPolicy{policy}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.NetworkPolicyByName(nil, policy.Namespace, policy.Name)
+	must.NoError(t, err)
+	must.Eq(t, policy, out)
+}
+
+func TestFSM_SnapshotRestore_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	sink := mock.EventSink()
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1000,
+		[]*structs.EventSink{sink}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, sink, out)
+}
+
 func TestFSM_SnapshotRestore_Jobs(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -3515,6 +3635,7 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 		msvs[sv.Path].CreateTime = sv.CreateTime
 		msvs[sv.Path].ModifyIndex = sv.ModifyIndex
 		msvs[sv.Path].ModifyTime = sv.ModifyTime
+		msvs[sv.Path].Version = sv.Version
 	}
 	svs = msvs.List()
 
@@ -3538,6 +3659,38 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 	require.ElementsMatch(t, restoredSVs, svs)
 }
 
+func TestFSM_SnapshotRestore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Write a variable a few times to create previous versions.
+	sv := mock.VariableEncrypted()
+	for i := uint64(0); i < 3; i++ {
+		update := sv.Copy()
+		update.Data = []byte(uuid.Generate())
+		setResp := testState.VarSet(10+i, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: &update,
+		})
+		must.NoError(t, setResp.Error)
+	}
+
+	versions, err := testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	restoredVersions, err := restoredState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, versions, restoredVersions)
+}
+
 func TestFSM_ApplyACLRolesUpsert(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -27,6 +27,9 @@ const (
 	attrLoopbackCNI       = `${attr.plugins.cni.version.loopback}`
 	attrPortMapCNI        = `${attr.plugins.cni.version.portmap}`
 	attrConsulCNI         = `${attr.plugins.cni.version.consul-cni}`
+	attrBandwidthCNI      = `${attr.plugins.cni.version.bandwidth}`
+	attrMacvlanCNI        = `${attr.plugins.cni.version.macvlan}`
+	attrDHCPCNI           = `${attr.plugins.cni.version.dhcp}`
 )
 
 // cniMinVersion is the version expression for the minimum CNI version supported
@@ -136,6 +139,33 @@ var (
 		Operand: structs.ConstraintSemver,
 	}
 
+	// cniBandwidthConstraint is an implicit constraint added to jobs limiting
+	// the bandwidth of bridge networks. The CNI bandwidth plugin configures
+	// the traffic shaping of the allocation interface.
+	cniBandwidthConstraint = &structs.Constraint{
+		LTarget: attrBandwidthCNI,
+		RTarget: cniMinVersion,
+		Operand: structs.ConstraintSemver,
+	}
+
+	// cniMacvlanConstraint is an implicit constraint added to jobs attaching
+	// host networks to their allocations. The CNI macvlan plugin creates the
+	// interface on the device of the host network.
+	cniMacvlanConstraint = &structs.Constraint{
+		LTarget: attrMacvlanCNI,
+		RTarget: cniMinVersion,
+		Operand: structs.ConstraintSemver,
+	}
+
+	// cniDHCPConstraint is an implicit constraint added to jobs attaching host
+	// networks to their allocations. The CNI dhcp plugin requests the address
+	// of the interface from the DHCP server of the host network.
+	cniDHCPConstraint = &structs.Constraint{
+		LTarget: attrDHCPCNI,
+		RTarget: cniMinVersion,
+		Operand: structs.ConstraintSemver,
+	}
+
 	// cniConsulConstraint is an implicit constraint added to jobs making use of
 	// transparent proxy mode.
 	cniConsulConstraint = &structs.Constraint{
@@ -275,6 +305,10 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 
 	bridgeNetworkingTaskGroups := j.RequiredBridgeNetwork()
 
+	bandwidthTaskGroups := j.RequiredBandwidthPlugin()
+
+	hostNetworkTaskGroups := j.RequiredHostNetworkPlugins()
+
 	transparentProxyTaskGroups := j.RequiredTransparentProxy()
 
 	taskScheduleTaskGroups := j.RequiredScheduleTask()
@@ -285,6 +319,7 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 	if len(signals) == 0 && len(vaultBlocks) == 0 &&
 		nativeServiceDisco.Empty() && len(consulServiceDisco) == 0 &&
 		numaTaskGroups.Empty() && bridgeNetworkingTaskGroups.Empty() &&
+		bandwidthTaskGroups.Empty() && hostNetworkTaskGroups.Empty() &&
 		transparentProxyTaskGroups.Empty() &&
 		taskScheduleTaskGroups.Empty() {
 		return j, nil, nil
@@ -352,6 +387,15 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 			mutateConstraint(constraintMatcherLeft, tg, cniPortMapConstraint)
 		}
 
+		if bandwidthTaskGroups.Contains(tg.Name) {
+			mutateConstraint(constraintMatcherLeft, tg, cniBandwidthConstraint)
+		}
+
+		if hostNetworkTaskGroups.Contains(tg.Name) {
+			mutateConstraint(constraintMatcherLeft, tg, cniMacvlanConstraint)
+			mutateConstraint(constraintMatcherLeft, tg, cniDHCPConstraint)
+		}
+
 		if transparentProxyTaskGroups.Contains(tg.Name) {
 			mutateConstraint(constraintMatcherLeft, tg, cniConsulConstraint)
 			mutateConstraint(constraintMatcherLeft, tg, tproxyConstraint)
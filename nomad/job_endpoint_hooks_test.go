@@ -1236,6 +1236,79 @@ func Test_jobImpliedConstraints_Mutate(t *testing.T) {
 			expectedOutputError:    nil,
 			name:                   "task group with bridge network",
 		},
+		{
+			inputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-bandwidth",
+						Networks: []*structs.NetworkResource{
+							{Mode: "bridge", Bandwidth: &structs.NetworkBandwidth{EgressMBits: 10}},
+						},
+					},
+				},
+			},
+			expectedOutputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-bandwidth",
+						Networks: []*structs.NetworkResource{
+							{Mode: "bridge", Bandwidth: &structs.NetworkBandwidth{EgressMBits: 10}},
+						},
+						Constraints: []*structs.Constraint{
+							cniBridgeConstraint,
+							cniFirewallConstraint,
+							cniHostLocalConstraint,
+							cniLoopbackConstraint,
+							cniPortMapConstraint,
+							cniBandwidthConstraint,
+						},
+					},
+				},
+			},
+			expectedOutputWarnings: nil,
+			expectedOutputError:    nil,
+			name:                   "task group with bridge network bandwidth",
+		},
+		{
+			inputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-host-network",
+						Networks: []*structs.NetworkResource{
+							{Mode: "bridge"},
+							{Mode: "host/storage"},
+						},
+					},
+				},
+			},
+			expectedOutputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-host-network",
+						Networks: []*structs.NetworkResource{
+							{Mode: "bridge"},
+							{Mode: "host/storage"},
+						},
+						Constraints: []*structs.Constraint{
+							cniBridgeConstraint,
+							cniFirewallConstraint,
+							cniHostLocalConstraint,
+							cniLoopbackConstraint,
+							cniPortMapConstraint,
+							cniMacvlanConstraint,
+							cniDHCPConstraint,
+						},
+					},
+				},
+			},
+			expectedOutputWarnings: nil,
+			expectedOutputError:    nil,
+			name:                   "task group attaching a host network",
+		},
 		{
 			inputJob: &structs.Job{
 				Name: "example",
@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// prefetchConcurrency is the maximum number of nodes contacted concurrently
+// when prefetching a job.
+const prefetchConcurrency = 16
+
+// Prefetch starts pulling the container images and downloading the artifacts
+// of a job onto the nodes it may be placed on, ahead of its placement.
+func (j *Job) Prefetch(args *structs.JobPrefetchRequest, reply *structs.JobPrefetchResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("Job.Prefetch", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "prefetch"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	snap, err := j.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound, fmt.Sprintf("job %q not found", args.JobID))
+	}
+
+	images, artifacts := prefetchSources(job)
+	if len(images) == 0 && len(artifacts) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			fmt.Sprintf("job %q has no container images or artifacts to prefetch", args.JobID))
+	}
+
+	nodes, err := prefetchNodes(snap, job, len(images) > 0 && len(artifacts) == 0)
+	if err != nil {
+		return err
+	}
+
+	var lock sync.Mutex
+	j.prefetchFanOut(nodes, func(nodeID string) {
+		req := &structs.NodePrefetchRequest{
+			NodeID:     nodeID,
+			JobID:      job.ID,
+			JobVersion: job.Version,
+			QueryOptions: structs.QueryOptions{
+				Region:    j.srv.Region(),
+				Namespace: job.Namespace,
+				AuthToken: args.AuthToken,
+			},
+		}
+		var resp structs.NodePrefetchResponse
+		err := j.srv.RPC("NodePrefetch.Start", req, &resp)
+
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			if reply.Errors == nil {
+				reply.Errors = make(map[string]string)
+			}
+			reply.Errors[nodeID] = err.Error()
+			return
+		}
+		reply.NodeIDs = append(reply.NodeIDs, nodeID)
+	})
+	sort.Strings(reply.NodeIDs)
+
+	reply.Index = job.ModifyIndex
+	return nil
+}
+
+// PrefetchStatus returns the progress of prefetching a job on the nodes it
+// may be placed on.
+func (j *Job) PrefetchStatus(args *structs.JobPrefetchStatusRequest, reply *structs.JobPrefetchStatusResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("Job.PrefetchStatus", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "prefetch_status"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	snap, err := j.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCoded(http.StatusNotFound, fmt.Sprintf("job %q not found", args.JobID))
+	}
+
+	nodes, err := prefetchNodes(snap, job, false)
+	if err != nil {
+		return err
+	}
+
+	var lock sync.Mutex
+	j.prefetchFanOut(nodes, func(nodeID string) {
+		req := &structs.NodePrefetchStatusRequest{
+			NodeID: nodeID,
+			JobID:  job.ID,
+			QueryOptions: structs.QueryOptions{
+				Region:    j.srv.Region(),
+				Namespace: job.Namespace,
+				AuthToken: args.AuthToken,
+			},
+		}
+		var resp structs.NodePrefetchResponse
+		err := j.srv.RPC("NodePrefetch.Status", req, &resp)
+
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			if reply.Errors == nil {
+				reply.Errors = make(map[string]string)
+			}
+			reply.Errors[nodeID] = err.Error()
+			return
+		}
+		if resp.Prefetch != nil {
+			reply.Nodes = append(reply.Nodes, resp.Prefetch)
+		}
+	})
+	sort.Slice(reply.Nodes, func(i, k int) bool {
+		return reply.Nodes[i].NodeID < reply.Nodes[k].NodeID
+	})
+
+	reply.Index = job.ModifyIndex
+	j.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// prefetchFanOut calls fn for each node, with at most prefetchConcurrency
+// calls running at once, and waits for all calls to return.
+func (j *Job) prefetchFanOut(nodeIDs []string, fn func(nodeID string)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, prefetchConcurrency)
+	for _, nodeID := range nodeIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(nodeID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(nodeID)
+		}(nodeID)
+	}
+	wg.Wait()
+}
+
+// prefetchSources returns the container images and artifacts of the job which
+// can be prefetched.
+func prefetchSources(job *structs.Job) ([]string, []*structs.TaskArtifact) {
+	var images []string
+	var artifacts []*structs.TaskArtifact
+	for _, tg := range job.TaskGroups {
+		for _, image := range tg.PrefetchImages() {
+			if !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+		}
+		for _, artifact := range tg.PrefetchArtifacts() {
+			if !slices.ContainsFunc(artifacts, artifact.Equal) {
+				artifacts = append(artifacts, artifact)
+			}
+		}
+	}
+	return images, artifacts
+}
+
+// prefetchImageAuths returns the registry authentication of the container
+// images of the job whose tasks set it.
+func prefetchImageAuths(job *structs.Job) map[string]*structs.PrefetchImageAuth {
+	auths := make(map[string]*structs.PrefetchImageAuth)
+	for _, tg := range job.TaskGroups {
+		for image, auth := range tg.PrefetchImageAuths() {
+			if _, ok := auths[image]; !ok {
+				auths[image] = auth
+			}
+		}
+	}
+	return auths
+}
+
+// prefetchNodes returns the IDs of the ready nodes in the datacenters and node
+// pool of the job. If requireDocker is set, nodes without a healthy docker
+// driver are excluded as they cannot pull any of the job's images.
+func prefetchNodes(snap *state.StateSnapshot, job *structs.Job, requireDocker bool) ([]string, error) {
+	var iter memdb.ResultIterator
+	var err error
+	if job.NodePool == structs.NodePoolAll || job.NodePool == "" {
+		iter, err = snap.Nodes(nil)
+	} else {
+		iter, err = snap.NodesByNodePool(nil, job.NodePool)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeIDs []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if prefetchNodeEligible(node, job, requireDocker) {
+			nodeIDs = append(nodeIDs, node.ID)
+		}
+	}
+	return nodeIDs, nil
+}
+
+// prefetchNodeEligible returns whether the node is ready and in the
+// datacenters and node pool of the job. If requireDocker is set, nodes without
+// a healthy docker driver are not eligible.
+func prefetchNodeEligible(node *structs.Node, job *structs.Job, requireDocker bool) bool {
+	if !node.Ready() || !node.IsInAnyDC(job.Datacenters) {
+		return false
+	}
+	if job.NodePool != structs.NodePoolAll && job.NodePool != "" && node.NodePool != job.NodePool {
+		return false
+	}
+	if requireDocker {
+		if driver, ok := node.Drivers["docker"]; !ok || !driver.Detected || !driver.Healthy {
+			return false
+		}
+	}
+	return true
+}
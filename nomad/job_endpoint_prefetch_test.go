@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestJobEndpoint_Prefetch(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+	testutil.WaitForClient(t, s.RPC, c.NodeID(), c.Region())
+
+	// Jobs without images or artifacts cannot be prefetched
+	job := mock.Job()
+	must.NoError(t, s.State().UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	req := &structs.JobPrefetchRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobPrefetchResponse
+	must.ErrorContains(t, s.RPC("Job.Prefetch", req, &resp), "no container images or artifacts")
+
+	job = job.Copy()
+	job.TaskGroups[0].Tasks[0].Artifacts = []*structs.TaskArtifact{
+		{GetterSource: "https://example.com/file.txt"},
+		{GetterSource: "https://example.com/${NOMAD_ALLOC_ID}.txt"},
+	}
+	must.NoError(t, s.State().UpsertJob(structs.MsgTypeTestSetup, 1001, nil, job))
+
+	must.NoError(t, s.RPC("Job.Prefetch", req, &resp))
+	must.Eq(t, []string{c.NodeID()}, resp.NodeIDs)
+	must.MapEmpty(t, resp.Errors)
+
+	// Without the artifact cache the artifact is skipped
+	statusReq := &structs.JobPrefetchStatusRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var statusResp structs.JobPrefetchStatusResponse
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			if err := s.RPC("Job.PrefetchStatus", statusReq, &statusResp); err != nil {
+				return err
+			}
+			if len(statusResp.Nodes) != 1 || !statusResp.Nodes[0].Terminal() {
+				return fmt.Errorf("prefetch not finished: %#v", statusResp.Nodes)
+			}
+			return nil
+		}),
+		wait.Timeout(10*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	prefetch := statusResp.Nodes[0]
+	must.Eq(t, c.NodeID(), prefetch.NodeID)
+	must.Eq(t, job.Version, prefetch.JobVersion)
+	must.Eq(t, structs.NodePrefetchStatusComplete, prefetch.Status)
+	must.Len(t, 1, prefetch.Artifacts)
+	must.Eq(t, "https://example.com/file.txt", prefetch.Artifacts[0].Source)
+	must.Eq(t, structs.NodePrefetchStatusSkipped, prefetch.Artifacts[0].Status)
+
+	// Nodes only prefetch the sources of the stored job version, not those
+	// set by the caller
+	nodeReq := &structs.NodePrefetchRequest{
+		NodeID:     c.NodeID(),
+		JobID:      job.ID,
+		JobVersion: 0,
+		Images:     []string{"example/unknown:1"},
+		Artifacts:  []*structs.TaskArtifact{{GetterSource: "https://example.com/other.txt"}},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var nodeResp structs.NodePrefetchResponse
+	must.NoError(t, s.RPC("NodePrefetch.Start", nodeReq, &nodeResp))
+	must.SliceEmpty(t, nodeResp.Prefetch.Images)
+	must.SliceEmpty(t, nodeResp.Prefetch.Artifacts)
+
+	nodeReq.JobVersion = 5
+	must.ErrorContains(t, s.RPC("NodePrefetch.Start", nodeReq, &nodeResp), "not found")
+
+	// Nodes the job can't be placed on don't prefetch it
+	nodeReq.JobVersion = job.Version
+	nodeReq.NodeID = uuid.Generate()
+	must.ErrorContains(t, s.RPC("NodePrefetch.Start", nodeReq, &nodeResp), "cannot be placed on node")
+
+	// Unknown jobs are not found
+	req.JobID = "unknown"
+	must.ErrorContains(t, s.RPC("Job.Prefetch", req, &resp), "not found")
+}
+
+func TestJobEndpoint_Prefetch_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Artifacts = []*structs.TaskArtifact{
+		{GetterSource: "https://example.com/file.txt"},
+	}
+	must.NoError(t, s.State().UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	req := &structs.JobPrefetchRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobPrefetchResponse
+	must.ErrorContains(t, s.RPC("Job.Prefetch", req, &resp), structs.ErrPermissionDenied.Error())
+
+	statusReq := &structs.JobPrefetchStatusRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var statusResp structs.JobPrefetchStatusResponse
+	must.ErrorContains(t, s.RPC("Job.PrefetchStatus", statusReq, &statusResp), structs.ErrPermissionDenied.Error())
+
+	// Reading the status requires read-job
+	readToken := mock.CreatePolicyAndToken(t, s.State(), 1001, "read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	statusReq.AuthToken = readToken.SecretID
+	must.NoError(t, s.RPC("Job.PrefetchStatus", statusReq, &statusResp))
+	must.SliceEmpty(t, statusResp.Nodes)
+
+	req.AuthToken = readToken.SecretID
+	must.ErrorContains(t, s.RPC("Job.Prefetch", req, &resp), structs.ErrPermissionDenied.Error())
+
+	// Prefetching requires submit-job
+	req.AuthToken = root.SecretID
+	must.NoError(t, s.RPC("Job.Prefetch", req, &resp))
+	must.SliceEmpty(t, resp.NodeIDs)
+}
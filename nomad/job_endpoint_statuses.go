@@ -53,10 +53,11 @@ func (j *Job) Statuses(
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+	if !aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
-	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+	allow := aclObj.AllowNsOpAnyJobFunc(acl.NamespaceCapabilityReadJob)
+	allowJob := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadJob)
 
 	store := j.srv.State()
 
@@ -121,6 +122,9 @@ func (j *Job) Statuses(
 				paginator.NamespaceFilter{
 					AllowableNamespaces: allowableNamespaces,
 				},
+				paginator.JobFilter{
+					Allow: allowJob,
+				},
 				// skip child jobs unless requested to include them
 				paginator.GenericFilter{Allow: func(i interface{}) (bool, error) {
 					if args.IncludeChildren {
@@ -1084,6 +1084,69 @@ func TestJobEndpoint_Register_ACL(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_NomadTokenRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	submitJobPolicy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob})
+	policy := mock.ACLPolicy()
+	policy.Rules = submitJobPolicy
+	policy.SetHash()
+	must.NoError(t, s1.State().UpsertACLPolicies(structs.MsgTypeTestSetup, 1000, []*structs.ACLPolicy{policy}))
+
+	deployRole := mock.ACLRole()
+	deployRole.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	adminRole := mock.ACLRole()
+	adminRole.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	must.NoError(t, s1.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 1001, []*structs.ACLRole{deployRole, adminRole}, false))
+
+	// The submitting token is linked to the deploy role only
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: deployRole.ID}}
+	must.NoError(t, s1.State().UpsertACLTokens(structs.MsgTypeTestSetup, 1002, []*structs.ACLToken{token}))
+
+	register := func(authToken string, roles ...string) error {
+		job := mock.Job()
+		job.TaskGroups[0].Tasks[0].Nomad = &structs.Nomad{
+			Roles:      roles,
+			ChangeMode: structs.NomadChangeModeRestart,
+		}
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+				AuthToken: authToken,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// Tokens can request the roles they are linked to
+	must.NoError(t, register(token.SecretID, deployRole.Name))
+
+	// Tokens can't request other roles
+	err := register(token.SecretID, deployRole.Name, adminRole.Name)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+	must.ErrorContains(t, err, fmt.Sprintf("role %q is not linked to the submitting token", adminRole.Name))
+
+	// Roles must exist, even for management tokens
+	err = register(root.SecretID, "unknown")
+	must.ErrorContains(t, err, `task token role "unknown" does not exist`)
+
+	// Management tokens can request any role
+	must.NoError(t, register(root.SecretID, deployRole.Name, adminRole.Name))
+}
+
 func TestJobEndpoint_Register_InvalidNamespace(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5371,6 +5434,101 @@ func TestJobEndpoint_ListJobs_WithACL(t *testing.T) {
 	require.Equal(job.ID, validResp.Jobs[0].ID)
 }
 
+func TestJobEndpoint_JobRulesACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	payments := mock.Job()
+	payments.ID = "payments-api"
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, payments))
+
+	billing := mock.Job()
+	billing.ID = "billing"
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, billing))
+
+	// The token can only list and read the payments jobs.
+	token := mock.CreatePolicyAndToken(t, store, 1002, "payments", `
+namespace "default" {
+  job "payments-*" {
+    capabilities = ["list-jobs", "read-job"]
+  }
+}`)
+
+	list := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var listResp structs.JobListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", list, &listResp))
+	must.Len(t, 1, listResp.Jobs)
+	must.Eq(t, payments.ID, listResp.Jobs[0].ID)
+
+	list.Namespace = structs.AllNamespacesSentinel
+	var listAllResp structs.JobListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", list, &listAllResp))
+	must.Len(t, 1, listAllResp.Jobs)
+	must.Eq(t, payments.ID, listAllResp.Jobs[0].ID)
+
+	get := &structs.JobSpecificRequest{
+		JobID: payments.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleJobResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJob", get, &getResp))
+	must.Eq(t, payments.ID, getResp.Job.ID)
+
+	get.JobID = billing.ID
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJob", get, &getResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	// The job rules don't grant the submit-job capability.
+	dereg := &structs.JobDeregisterRequest{
+		JobID: payments.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+
+	// A job rule that denies access takes precedence over the namespace
+	// capabilities.
+	denyToken := mock.CreatePolicyAndToken(t, store, 1003, "deny-billing", `
+namespace "default" {
+  policy = "read"
+  job "billing" {
+    capabilities = ["deny"]
+  }
+}`)
+	list.Namespace = structs.DefaultNamespace
+	list.AuthToken = denyToken.SecretID
+	var denyListResp structs.JobListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.List", list, &denyListResp))
+	must.Len(t, 1, denyListResp.Jobs)
+	must.Eq(t, payments.ID, denyListResp.Jobs[0].ID)
+
+	get.AuthToken = denyToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJob", get, &getResp)
+	must.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestJobEndpoint_ListJobs_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build cgo
+
+package kekprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/hashicorp/nomad/helper/crypto"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	pkcs11GCMNonceSize = 12
+	pkcs11GCMTagBits   = 128
+)
+
+// pkcs11Provider wraps key encryption keys with AES-GCM using a secret key
+// that never leaves the PKCS#11 token. The nonce is generated by Nomad and
+// prepended to the ciphertext.
+type pkcs11Provider struct {
+	id       string
+	ctx      *pkcs11.Ctx
+	slot     uint
+	pin      string
+	keyLabel string
+
+	// lock serializes sessions, as not every module supports concurrent
+	// logins to the same token
+	lock sync.Mutex
+}
+
+func newPKCS11Provider(cfg *config.KEKProviderConfig) (Provider, error) {
+	ctx := pkcs11.New(cfg.Config["lib"])
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load pkcs11 library %q", cfg.Config["lib"])
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize pkcs11 library: %w", err)
+	}
+
+	p := &pkcs11Provider{
+		id:       cfg.ID(),
+		ctx:      ctx,
+		pin:      cfg.Config["pin"],
+		keyLabel: cfg.Config["key_label"],
+	}
+
+	slot, err := p.findSlot(cfg.Config["slot"], cfg.Config["token_label"])
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.slot = slot
+	return p, nil
+}
+
+// findSlot returns the configured slot, the slot holding the token with the
+// configured label, or the first slot with a token present.
+func (p *pkcs11Provider) findSlot(slot, tokenLabel string) (uint, error) {
+	if slot != "" {
+		id, err := strconv.ParseUint(slot, 10, 0)
+		if err != nil {
+			return 0, fmt.Errorf("invalid pkcs11 slot %q: %w", slot, err)
+		}
+		return uint(id), nil
+	}
+
+	slots, err := p.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pkcs11 slots: %w", err)
+	}
+	for _, id := range slots {
+		if tokenLabel == "" {
+			return id, nil
+		}
+		info, err := p.ctx.GetTokenInfo(id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read pkcs11 token info: %w", err)
+		}
+		if info.Label == tokenLabel {
+			return id, nil
+		}
+	}
+	if tokenLabel != "" {
+		return 0, fmt.Errorf("no pkcs11 token with label %q", tokenLabel)
+	}
+	return 0, errors.New("no pkcs11 slot with a token present")
+}
+
+func (p *pkcs11Provider) ID() string { return p.id }
+
+func (p *pkcs11Provider) Wrap(_ context.Context, kek []byte) ([]byte, error) {
+	nonce, err := crypto.Bytes(pkcs11GCMNonceSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	var ciphertext []byte
+	err = p.withKey(func(sh pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(nonce, nil, pkcs11GCMTagBits)
+		defer params.Free()
+
+		mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+		if err := p.ctx.EncryptInit(sh, mech, key); err != nil {
+			return err
+		}
+		ciphertext, err = p.ctx.Encrypt(sh, kek)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 encrypt failed: %w", err)
+	}
+	return append(nonce, ciphertext...), nil
+}
+
+func (p *pkcs11Provider) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) <= pkcs11GCMNonceSize {
+		return nil, errors.New("pkcs11 wrapped key is too short")
+	}
+	nonce, ciphertext := wrapped[:pkcs11GCMNonceSize], wrapped[pkcs11GCMNonceSize:]
+
+	var kek []byte
+	err := p.withKey(func(sh pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(nonce, nil, pkcs11GCMTagBits)
+		defer params.Free()
+
+		mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+		if err := p.ctx.DecryptInit(sh, mech, key); err != nil {
+			return err
+		}
+		var err error
+		kek, err = p.ctx.Decrypt(sh, ciphertext)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 decrypt failed: %w", err)
+	}
+	return kek, nil
+}
+
+// withKey opens a logged in session and calls fn with the secret key.
+func (p *pkcs11Provider) withKey(fn func(pkcs11.SessionHandle, pkcs11.ObjectHandle) error) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	sh, err := p.ctx.OpenSession(p.slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	defer p.ctx.CloseSession(sh)
+
+	if p.pin != "" {
+		err := p.ctx.Login(sh, pkcs11.CKU_USER, p.pin)
+		if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+			return fmt.Errorf("failed to log in: %w", err)
+		}
+		defer p.ctx.Logout(sh)
+	}
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, p.keyLabel),
+	}
+	if err := p.ctx.FindObjectsInit(sh, template); err != nil {
+		return fmt.Errorf("failed to find key: %w", err)
+	}
+	keys, _, err := p.ctx.FindObjects(sh, 1)
+	finalErr := p.ctx.FindObjectsFinal(sh)
+	if err != nil {
+		return fmt.Errorf("failed to find key: %w", err)
+	}
+	if finalErr != nil {
+		return fmt.Errorf("failed to find key: %w", finalErr)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no secret key with label %q", p.keyLabel)
+	}
+
+	return fn(sh, keys[0])
+}
+
+func (p *pkcs11Provider) Close() {
+	_ = p.ctx.Finalize()
+	p.ctx.Destroy()
+}
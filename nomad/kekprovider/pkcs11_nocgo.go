@@ -0,0 +1,16 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !cgo
+
+package kekprovider
+
+import (
+	"errors"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+func newPKCS11Provider(*config.KEKProviderConfig) (Provider, error) {
+	return nil, errors.New("pkcs11 provider requires a build of Nomad with cgo enabled")
+}
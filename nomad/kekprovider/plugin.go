@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package kekprovider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/rpc"
+	"os/exec"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const kmsPluginName = "kms"
+
+// KMSPluginHandshake is the handshake that KMS plugins must serve. Plugins
+// built with ServeKMSPlugin use it automatically.
+var KMSPluginHandshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "NOMAD_KMS_PLUGIN_MAGIC_COOKIE",
+	MagicCookieValue: "4bf4cbf5a3d34b1a09c5e25e27cd61b12f0e6ea2bd00a1d1d3f4b6d0a5c7f3e1",
+}
+
+// KMS is the interface external KMS plugins implement to wrap key encryption
+// keys for the keyring.
+type KMS interface {
+	// SetConfig is called once after the plugin is launched with the
+	// provider's config, minus the command and args used to launch it.
+	SetConfig(config map[string]string) error
+
+	// Encrypt wraps a key encryption key.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt unwraps a key encryption key returned by Encrypt.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// ServeKMSPlugin serves a KMS implementation as a plugin. It should be called
+// from the main function of the plugin binary.
+func ServeKMSPlugin(impl KMS) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: KMSPluginHandshake,
+		Plugins: plugin.PluginSet{
+			kmsPluginName: &KMSPlugin{Impl: impl},
+		},
+	})
+}
+
+// KMSPlugin implements plugin.Plugin for the KMS interface over net/rpc.
+type KMSPlugin struct {
+	Impl KMS
+}
+
+func (p *KMSPlugin) Server(*plugin.MuxBroker) (any, error) {
+	return &kmsRPCServer{impl: p.Impl}, nil
+}
+
+func (p *KMSPlugin) Client(_ *plugin.MuxBroker, c *rpc.Client) (any, error) {
+	return &kmsRPCClient{client: c}, nil
+}
+
+type kmsRPCServer struct {
+	impl KMS
+}
+
+func (s *kmsRPCServer) SetConfig(args map[string]string, _ *struct{}) error {
+	return s.impl.SetConfig(args)
+}
+
+func (s *kmsRPCServer) Encrypt(args []byte, reply *[]byte) error {
+	out, err := s.impl.Encrypt(args)
+	*reply = out
+	return err
+}
+
+func (s *kmsRPCServer) Decrypt(args []byte, reply *[]byte) error {
+	out, err := s.impl.Decrypt(args)
+	*reply = out
+	return err
+}
+
+type kmsRPCClient struct {
+	client *rpc.Client
+}
+
+func (c *kmsRPCClient) SetConfig(config map[string]string) error {
+	return c.client.Call("Plugin.SetConfig", config, &struct{}{})
+}
+
+func (c *kmsRPCClient) Encrypt(plaintext []byte) ([]byte, error) {
+	var reply []byte
+	err := c.client.Call("Plugin.Encrypt", plaintext, &reply)
+	return reply, err
+}
+
+func (c *kmsRPCClient) Decrypt(ciphertext []byte) ([]byte, error) {
+	var reply []byte
+	err := c.client.Call("Plugin.Decrypt", ciphertext, &reply)
+	return reply, err
+}
+
+// pluginProvider wraps key encryption keys with an external KMS plugin, which
+// is launched when the provider is created and killed when it is closed.
+type pluginProvider struct {
+	id     string
+	client *plugin.Client
+	kms    KMS
+}
+
+func newPluginProvider(cfg *config.KEKProviderConfig, logger log.Logger) (Provider, error) {
+	pluginConfig := maps.Clone(cfg.Config)
+	delete(pluginConfig, "command")
+	delete(pluginConfig, "args")
+
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  KMSPluginHandshake,
+		Plugins:          plugin.PluginSet{kmsPluginName: &KMSPlugin{}},
+		Cmd:              exec.Command(cfg.Config["command"], strings.Fields(cfg.Config["args"])...),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolNetRPC},
+		Logger:           logger,
+	})
+
+	kms, err := dispenseKMS(client, pluginConfig)
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	return &pluginProvider{
+		id:     cfg.ID(),
+		client: client,
+		kms:    kms,
+	}, nil
+}
+
+func dispenseKMS(client *plugin.Client, pluginConfig map[string]string) (KMS, error) {
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch kms plugin: %w", err)
+	}
+	raw, err := rpcClient.Dispense(kmsPluginName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense kms plugin: %w", err)
+	}
+	kms, ok := raw.(KMS)
+	if !ok {
+		return nil, fmt.Errorf("unexpected kms plugin type %T", raw)
+	}
+	if err := kms.SetConfig(pluginConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure kms plugin: %w", err)
+	}
+	return kms, nil
+}
+
+func (p *pluginProvider) ID() string { return p.id }
+
+func (p *pluginProvider) Wrap(_ context.Context, kek []byte) ([]byte, error) {
+	wrapped, err := p.kms.Encrypt(kek)
+	if err != nil {
+		return nil, fmt.Errorf("kms plugin encrypt failed: %w", err)
+	}
+	return wrapped, nil
+}
+
+func (p *pluginProvider) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	kek, err := p.kms.Decrypt(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("kms plugin decrypt failed: %w", err)
+	}
+	return kek, nil
+}
+
+func (p *pluginProvider) Close() {
+	p.client.Kill()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package kekprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+// xorKMS is a toy KMS for exercising the plugin RPC layer.
+type xorKMS struct {
+	key byte
+}
+
+func (k *xorKMS) SetConfig(config map[string]string) error {
+	if config["key"] == "" {
+		return errors.New("missing key")
+	}
+	k.key = config["key"][0]
+	return nil
+}
+
+func (k *xorKMS) Encrypt(plaintext []byte) ([]byte, error) {
+	out := bytes.Clone(plaintext)
+	for i := range out {
+		out[i] ^= k.key
+	}
+	return out, nil
+}
+
+func (k *xorKMS) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, errors.New("empty ciphertext")
+	}
+	return k.Encrypt(ciphertext)
+}
+
+func TestPluginProvider_RPC(t *testing.T) {
+	ci.Parallel(t)
+
+	client, _ := plugin.TestPluginRPCConn(t, plugin.PluginSet{
+		kmsPluginName: &KMSPlugin{Impl: &xorKMS{}},
+	}, nil)
+	t.Cleanup(func() { client.Close() })
+
+	raw, err := client.Dispense(kmsPluginName)
+	must.NoError(t, err)
+	kms := raw.(KMS)
+
+	must.ErrorContains(t, kms.SetConfig(map[string]string{}), "missing key")
+	must.NoError(t, kms.SetConfig(map[string]string{"key": "k"}))
+
+	provider := &pluginProvider{id: "plugin", kms: kms}
+
+	kek := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := provider.Wrap(context.Background(), kek)
+	must.NoError(t, err)
+	must.NotEq(t, kek, wrapped)
+
+	got, err := provider.Unwrap(context.Background(), wrapped)
+	must.NoError(t, err)
+	must.Eq(t, kek, got)
+
+	_, err = provider.Unwrap(context.Background(), nil)
+	must.ErrorContains(t, err, "empty ciphertext")
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package kekprovider implements the external providers that wrap the key
+// encryption keys (KEK) protecting root keys in the server keystore. Without
+// an external provider, the KEK is written to disk next to the root key it
+// encrypts.
+package kekprovider
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// Provider wraps and unwraps key encryption keys with key material that is
+// not stored in the keystore.
+type Provider interface {
+	// ID returns the unique ID of the provider configuration.
+	ID() string
+
+	// Wrap encrypts a key encryption key.
+	Wrap(ctx context.Context, kek []byte) ([]byte, error)
+
+	// Unwrap decrypts a key encryption key previously returned by Wrap.
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+
+	// Close releases any resources held by the provider.
+	Close()
+}
+
+// New returns the Provider for the configuration. The aead provider stores its
+// key encryption keys in the clear and so has no Provider implementation.
+func New(cfg *config.KEKProviderConfig, logger log.Logger) (Provider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	logger = logger.Named("kek_provider").With("provider", cfg.ID())
+
+	switch cfg.Provider {
+	case config.KEKProviderTransit:
+		return newTransitProvider(cfg)
+	case config.KEKProviderPKCS11:
+		return newPKCS11Provider(cfg)
+	case config.KEKProviderPlugin:
+		return newPluginProvider(cfg, logger)
+	default:
+		return nil, fmt.Errorf("keyring provider %q does not wrap keys", cfg.Provider)
+	}
+}
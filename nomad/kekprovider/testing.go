@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package kekprovider
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/crypto"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// TestTransitServer starts a stand-in for the encrypt and decrypt endpoints of
+// a Vault Transit secrets engine mounted at the default path, with a single
+// key. It returns the server and a keyring provider config that uses it.
+func TestTransitServer(t testing.TB, keyName string) (*httptest.Server, *config.KEKProviderConfig) {
+	t.Helper()
+
+	key, err := crypto.Bytes(32)
+	if err != nil {
+		t.Fatalf("failed to generate transit key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("failed to create transit cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("failed to create transit cipher: %v", err)
+	}
+
+	const prefix = "vault:v1:"
+	respond := func(w http.ResponseWriter, data map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/transit/encrypt/"+keyName, func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Plaintext string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		nonce, err := crypto.Bytes(aead.NonceSize())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ciphertext := aead.Seal(nonce, nonce, plaintext, nil)
+		respond(w, map[string]string{
+			"ciphertext": prefix + base64.StdEncoding.EncodeToString(ciphertext),
+		})
+	})
+	mux.HandleFunc("/v1/transit/decrypt/"+keyName, func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Ciphertext string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(req.Ciphertext, prefix))
+		if err != nil || len(raw) < aead.NonceSize() {
+			http.Error(w, "invalid ciphertext", http.StatusBadRequest)
+			return
+		}
+		plaintext, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+		if err != nil {
+			http.Error(w, "invalid ciphertext", http.StatusBadRequest)
+			return
+		}
+		respond(w, map[string]string{
+			"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+		})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, &config.KEKProviderConfig{
+		Provider: config.KEKProviderTransit,
+		Config: map[string]string{
+			"address":  srv.URL,
+			"token":    "test-token",
+			"key_name": keyName,
+		},
+	}
+}
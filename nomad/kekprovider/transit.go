@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package kekprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strconv"
+
+	vapi "github.com/hashicorp/vault/api"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const transitDefaultMountPath = "transit"
+
+// transitProvider wraps key encryption keys with the encrypt and decrypt
+// endpoints of a Vault Transit compatible secrets engine. The provider only
+// needs update capability on those two paths.
+type transitProvider struct {
+	id        string
+	client    *vapi.Client
+	mountPath string
+	keyName   string
+}
+
+func newTransitProvider(cfg *config.KEKProviderConfig) (Provider, error) {
+	// The default config reads the standard VAULT_ environment variables,
+	// which the explicit configuration overrides.
+	vc := vapi.DefaultConfig()
+	if vc.Error != nil {
+		return nil, fmt.Errorf("invalid transit environment: %w", vc.Error)
+	}
+	if addr := cfg.Config["address"]; addr != "" {
+		vc.Address = addr
+	}
+
+	tlsConfig := &vapi.TLSConfig{
+		CACert:        cfg.Config["tls_ca_file"],
+		ClientCert:    cfg.Config["tls_cert_file"],
+		ClientKey:     cfg.Config["tls_key_file"],
+		TLSServerName: cfg.Config["tls_server_name"],
+	}
+	if skip := cfg.Config["tls_skip_verify"]; skip != "" {
+		insecure, err := strconv.ParseBool(skip)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls_skip_verify: %w", err)
+		}
+		tlsConfig.Insecure = insecure
+	}
+	if err := vc.ConfigureTLS(tlsConfig); err != nil {
+		return nil, fmt.Errorf("invalid transit tls configuration: %w", err)
+	}
+
+	client, err := vapi.NewClient(vc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transit client: %w", err)
+	}
+	if token := cfg.Config["token"]; token != "" {
+		client.SetToken(token)
+	}
+	if ns := cfg.Config["namespace"]; ns != "" {
+		client.SetNamespace(ns)
+	}
+
+	mountPath := cfg.Config["mount_path"]
+	if mountPath == "" {
+		mountPath = transitDefaultMountPath
+	}
+
+	return &transitProvider{
+		id:        cfg.ID(),
+		client:    client,
+		mountPath: mountPath,
+		keyName:   cfg.Config["key_name"],
+	}, nil
+}
+
+func (p *transitProvider) ID() string { return p.id }
+
+func (p *transitProvider) Wrap(ctx context.Context, kek []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		path.Join(p.mountPath, "encrypt", p.keyName),
+		map[string]any{"plaintext": base64.StdEncoding.EncodeToString(kek)})
+	if err != nil {
+		return nil, fmt.Errorf("transit encrypt failed: %w", err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("transit encrypt returned no data")
+	}
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok || ciphertext == "" {
+		return nil, fmt.Errorf("transit encrypt returned no ciphertext")
+	}
+	return []byte(ciphertext), nil
+}
+
+func (p *transitProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		path.Join(p.mountPath, "decrypt", p.keyName),
+		map[string]any{"ciphertext": string(wrapped)})
+	if err != nil {
+		return nil, fmt.Errorf("transit decrypt failed: %w", err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("transit decrypt returned no data")
+	}
+	plaintext, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("transit decrypt returned no plaintext")
+	}
+	kek, err := base64.StdEncoding.DecodeString(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("transit decrypt returned invalid plaintext: %w", err)
+	}
+	return kek, nil
+}
+
+func (p *transitProvider) Close() {}
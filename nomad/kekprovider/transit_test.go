@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package kekprovider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+func TestTransitProvider(t *testing.T) {
+	ci.Parallel(t)
+
+	_, cfg := TestTransitServer(t, "nomad")
+	cfg.Name = "primary"
+
+	provider, err := New(cfg, testlog.HCLogger(t))
+	must.NoError(t, err)
+	t.Cleanup(provider.Close)
+	must.Eq(t, "transit.primary", provider.ID())
+
+	kek := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := provider.Wrap(context.Background(), kek)
+	must.NoError(t, err)
+	must.True(t, strings.HasPrefix(string(wrapped), "vault:v1:"))
+
+	got, err := provider.Unwrap(context.Background(), wrapped)
+	must.NoError(t, err)
+	must.Eq(t, kek, got)
+
+	_, err = provider.Unwrap(context.Background(), []byte("vault:v1:Zm9v"))
+	must.ErrorContains(t, err, "transit decrypt failed")
+
+	// a key the server doesn't know can't be used to wrap
+	cfg.Config["key_name"] = "unknown"
+	provider, err = New(cfg, testlog.HCLogger(t))
+	must.NoError(t, err)
+	_, err = provider.Wrap(context.Background(), kek)
+	must.ErrorContains(t, err, "transit encrypt failed")
+}
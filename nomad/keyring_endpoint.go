@@ -268,14 +268,16 @@ func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.Key
 			}
 
 			// retrieve the key material from the keyring
-			key, rsaKey, err := k.encrypter.GetKey(keyMeta.KeyID)
+			key, err := k.encrypter.GetKey(keyMeta.KeyID)
 			if err != nil {
 				return err
 			}
 			rootKey := &structs.RootKey{
 				Meta:   keyMeta,
-				Key:    key,
-				RSAKey: rsaKey,
+				Key:    key.Key,
+				RSAKey: key.RSAKey,
+				CAKey:  key.CAKey,
+				CACert: key.CACert,
 			}
 			reply.Key = rootKey
 
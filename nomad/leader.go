@@ -65,6 +65,31 @@ var minACLAuthMethodVersion = version.Must(version.NewVersion("1.5.0"))
 // meet before the feature can be used.
 var minACLJWTAuthMethodVersion = version.Must(version.NewVersion("1.5.4"))
 
+// minACLLDAPAuthMethodVersion is the Nomad version at which the ACL LDAP auth
+// method type was introduced. It forms the minimum version all federated
+// servers must meet before the feature can be used.
+var minACLLDAPAuthMethodVersion = version.Must(version.NewVersion("1.8.1"))
+
+// minACLCertAuthMethodVersion is the Nomad version at which the ACL cert auth
+// method type was introduced. It forms the minimum version all federated
+// servers must meet before the feature can be used.
+var minACLCertAuthMethodVersion = version.Must(version.NewVersion("1.8.1"))
+
+// minACLOIDCDeviceAuthVersion is the Nomad version at which the OIDC device
+// authorization flow was introduced. It forms the minimum version all
+// federated servers must meet before the feature can be used.
+var minACLOIDCDeviceAuthVersion = version.Must(version.NewVersion("1.8.1"))
+
+// minACLTokenRenewVersion is the Nomad version at which renewable ACL tokens
+// were introduced. It forms the minimum version all federated servers must
+// meet before the feature can be used.
+var minACLTokenRenewVersion = version.Must(version.NewVersion("1.8.1"))
+
+// minACLTaskTokenVersion is the Nomad version at which the nomad block of
+// tasks was introduced. It forms the minimum version all servers must meet
+// before tokens can be derived for tasks.
+var minACLTaskTokenVersion = version.Must(version.NewVersion("1.8.1"))
+
 // minACLBindingRuleVersion is the Nomad version at which the ACL binding rules
 // table was introduced. It forms the minimum version all federated servers
 // must meet before the feature can be used.
@@ -79,6 +104,21 @@ var minNomadServiceRegistrationVersion = version.Must(version.NewVersion("1.3.0"
 // prevent older versions of the server from crashing.
 var minNodePoolsVersion = version.Must(version.NewVersion("1.6.0"))
 
+// minNetworkPolicyVersion is the Nomad version at which the network policies
+// table was introduced. It forms the minimum version all local servers must
+// meet before the feature can be used.
+var minNetworkPolicyVersion = version.Must(version.NewVersion("1.8.1"))
+
+// minEventSinksVersion is the Nomad version at which the event sinks table
+// was introduced. It forms the minimum version all local servers must meet
+// before the feature can be used.
+var minEventSinksVersion = version.Must(version.NewVersion("1.8.1"))
+
+// minVariableVersionsVersion is the Nomad version at which the history of
+// variable versions was introduced. It forms the minimum version all local
+// servers must meet before versions can be rekeyed or garbage collected.
+var minVariableVersionsVersion = version.Must(version.NewVersion("1.8.1"))
+
 // minVersionMultiIdentities is the Nomad version at which users can add
 // multiple identity blocks to tasks and workload identities can be
 // automatically added to jobs that need access to Consul or Vault
@@ -448,12 +488,21 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job status metrics
 	go s.publishJobStatusMetrics(stopCh)
 
+	// Deliver events to the registered event sinks
+	go newEventSinkManager(s).run(stopCh)
+
 	// Populate the variable lock TTL timers, so we can start tracking renewals
 	// and expirations.
 	if err := s.restoreLockTTLTimers(); err != nil {
 		return err
 	}
 
+	// Populate the variable expiry timers, so that variables with a TTL are
+	// deleted when they expire.
+	if err := s.restoreVariableExpiryTimers(); err != nil {
+		return err
+	}
+
 	// Periodically publish metrics for the lock timer trackers which are only
 	// run on the leader.
 	go s.lockTTLTimer.EmitMetrics(1*time.Second, stopCh)
@@ -1048,6 +1097,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer rootKeyGC.Stop()
 	variablesRekey := time.NewTicker(s.config.VariablesRekeyInterval)
 	defer variablesRekey.Stop()
+	variableVersionGC := time.NewTicker(s.config.VariableVersionGCInterval)
+	defer variableVersionGC.Stop()
 
 	// Set up the expired ACL local token garbage collection timer.
 	localTokenExpiredGC, localTokenExpiredGCStop := helper.NewSafeTimer(s.config.ACLTokenExpirationGCInterval)
@@ -1101,6 +1152,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesRekey, index))
 			}
+		case <-variableVersionGC.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariableVersionGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -1522,6 +1577,9 @@ func (s *Server) revokeLeadership() error {
 	// Disable the volume watcher
 	s.volumeWatcher.SetEnabled(false, nil, "")
 
+	// Forget the template restarts in progress
+	s.templateRestarts.reset()
+
 	// Disable any enterprise systems required.
 	if err := s.revokeEnterpriseLeadership(); err != nil {
 		return err
@@ -1530,6 +1588,7 @@ func (s *Server) revokeLeadership() error {
 	// Stop all the tracked variable lock TTL and delay timers.
 	s.lockTTLTimer.StopAndRemoveAll()
 	s.lockDelayTimer.RemoveAll()
+	s.variableExpiryTimer.StopAndRemoveAll()
 
 	// Clear the heartbeat timers on either shutdown or step down,
 	// since we are no longer responsible for TTL expirations.
@@ -1980,7 +2039,7 @@ ERR_WAIT:
 // be deleted or updated.
 func diffACLTokens(store *state.StateStore, minIndex uint64, remoteList []*structs.ACLTokenListStub) (delete []string, update []string) {
 	// Construct a set of the local and remote policies
-	local := make(map[string][]byte)
+	local := make(map[string]*structs.ACLToken)
 	remote := make(map[string]struct{})
 
 	// Add all the local global tokens
@@ -1994,7 +2053,7 @@ func diffACLTokens(store *state.StateStore, minIndex uint64, remoteList []*struc
 			break
 		}
 		token := raw.(*structs.ACLToken)
-		local[token.AccessorID] = token.Hash
+		local[token.AccessorID] = token
 	}
 
 	// Iterate over the remote tokens
@@ -2002,11 +2061,14 @@ func diffACLTokens(store *state.StateStore, minIndex uint64, remoteList []*struc
 		remote[rp.AccessorID] = struct{}{}
 
 		// Check if the token is missing locally
-		if localHash, ok := local[rp.AccessorID]; !ok {
+		if localToken, ok := local[rp.AccessorID]; !ok {
 			update = append(update, rp.AccessorID)
 
-			// Check if policy is newer remotely and there is a hash mis-match.
-		} else if rp.ModifyIndex > minIndex && !bytes.Equal(localHash, rp.Hash) {
+			// Check if policy is newer remotely and there is a hash mis-match,
+			// or the token has been renewed. The hash doesn't include the
+			// expiration time.
+		} else if rp.ModifyIndex > minIndex && (!bytes.Equal(localToken.Hash, rp.Hash) ||
+			!expirationTimesEqual(localToken.ExpirationTime, rp.ExpirationTime)) {
 			update = append(update, rp.AccessorID)
 		}
 	}
@@ -2020,6 +2082,15 @@ func diffACLTokens(store *state.StateStore, minIndex uint64, remoteList []*struc
 	return
 }
 
+// expirationTimesEqual returns whether the two ACL token expiration times are
+// equal. It handles nil times.
+func expirationTimesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 // replicateACLRoles is used to replicate ACL Roles from the authoritative
 // region to this region. The loop should only be run on the leader within the
 // federated region.
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -1085,7 +1086,10 @@ func TestLeader_DiffACLTokens(t *testing.T) {
 	p2.Global = true
 	p3 := mock.ACLToken()
 	p3.Global = true
-	assert.Nil(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 100, []*structs.ACLToken{p0, p1, p2, p3}))
+	p5 := mock.ACLToken()
+	p5.Global = true
+	p5.ExpirationTime = pointer.Of(p5.CreateTime.Add(time.Hour))
+	assert.Nil(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 100, []*structs.ACLToken{p0, p1, p2, p3, p5}))
 
 	// Simulate a remote list
 	p2Stub := p2.Stub()
@@ -1095,10 +1099,14 @@ func TestLeader_DiffACLTokens(t *testing.T) {
 	p3Stub.Hash = []byte{0, 1, 2, 3}
 	p4 := mock.ACLToken()
 	p4.Global = true
+	p5Stub := p5.Stub()
+	p5Stub.ModifyIndex = 100 // Renewed, same hash with a later expiration
+	p5Stub.ExpirationTime = pointer.Of(p5.CreateTime.Add(2 * time.Hour))
 	remoteList := []*structs.ACLTokenListStub{
 		p2Stub,
 		p3Stub,
 		p4.Stub(),
+		p5Stub,
 	}
 	delete, update := diffACLTokens(state, 50, remoteList)
 
@@ -1106,8 +1114,8 @@ func TestLeader_DiffACLTokens(t *testing.T) {
 	// P1 does not exist on the remote side, should delete
 	assert.Equal(t, []string{p1.AccessorID}, delete)
 
-	// P2 is un-modified - ignore. P3 modified, P4 new.
-	assert.Equal(t, []string{p3.AccessorID, p4.AccessorID}, update)
+	// P2 is un-modified - ignore. P3 modified, P4 new, P5 renewed.
+	assert.Equal(t, []string{p3.AccessorID, p4.AccessorID, p5.AccessorID}, update)
 }
 
 func TestServer_replicationBackoffContinue(t *testing.T) {
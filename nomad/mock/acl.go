@@ -19,6 +19,7 @@ import (
 	testing "github.com/mitchellh/go-testing-interface"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -319,6 +320,53 @@ func ACLJWTAuthMethod() *structs.ACLAuthMethod {
 	return &method
 }
 
+func ACLLDAPAuthMethod() *structs.ACLAuthMethod {
+	maxTokenTTL, _ := time.ParseDuration("3600s")
+	method := structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Short()),
+		Type:          "LDAP",
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       false,
+		Config: &structs.ACLAuthMethodConfig{
+			LDAPURLs:          []string{"ldaps://ldap.example.com"},
+			LDAPUserDN:        "ou=people,dc=example,dc=org",
+			LDAPGroupDN:       "ou=groups,dc=example,dc=org",
+			ClaimMappings:     map[string]string{"username": "username"},
+			ListClaimMappings: map[string]string{"groups": "groups"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.Canonicalize()
+	method.SetHash()
+	return &method
+}
+
+func ACLCertAuthMethod() *structs.ACLAuthMethod {
+	caPEM, _, _ := tlsutil.GenerateCA(tlsutil.CAOpts{})
+	maxTokenTTL, _ := time.ParseDuration("3600s")
+	method := structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Short()),
+		Type:          "CERT",
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       false,
+		Config: &structs.ACLAuthMethodConfig{
+			CertCACerts:       []string{caPEM},
+			ClaimMappings:     map[string]string{"common_name": "common_name"},
+			ListClaimMappings: map[string]string{"organizational_units": "organizational_units"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.Canonicalize()
+	method.SetHash()
+	return &method
+}
+
 // SampleJWTokenWithKeys takes a set of claims (can be nil) and optionally
 // a private RSA key that should be used for signing the JWT, and returns:
 // - a JWT signed with a randomly generated RSA key
@@ -262,6 +262,41 @@ func NodePool() *structs.NodePool {
 	return pool
 }
 
+// NetworkPolicy returns a network policy in the default namespace that only
+// allows the "api" job to connect to the "db" job.
+func NetworkPolicy() *structs.NetworkPolicy {
+	return &structs.NetworkPolicy{
+		Name:        fmt.Sprintf("policy-%s", uuid.Short()),
+		Namespace:   structs.DefaultNamespace,
+		Description: "test network policy",
+		Selector: &structs.NetworkPolicySelector{
+			Job: "db",
+		},
+		Ingress: []*structs.NetworkPolicyIngressRule{
+			{
+				From: []*structs.NetworkPolicySelector{
+					{Job: "api"},
+				},
+				Ports:    []int{5432},
+				Protocol: structs.NetworkPolicyProtocolTCP,
+			},
+		},
+	}
+}
+
+func EventSink() *structs.EventSink {
+	sink := &structs.EventSink{
+		ID:      fmt.Sprintf("sink-%s", uuid.Short()),
+		Type:    structs.EventSinkWebhook,
+		Address: "http://127.0.0.1:8080/events",
+		Topics: map[structs.Topic][]string{
+			structs.TopicJob: {"*"},
+		},
+	}
+	sink.Canonicalize()
+	return sink
+}
+
 // ServiceRegistrations generates an array containing two unique service
 // registrations.
 func ServiceRegistrations() []*structs.ServiceRegistration {
@@ -0,0 +1,258 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/state/paginator"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NetworkPolicy endpoint is used for network policy management. Network
+// policies are read by clients to restrict traffic between allocations on
+// their bridge network.
+type NetworkPolicy struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewNetworkPolicyEndpoint(srv *Server, ctx *RPCContext) *NetworkPolicy {
+	return &NetworkPolicy{srv: srv, ctx: ctx}
+}
+
+// List is used to retrieve the network policies of a namespace, or of all
+// namespaces when using the wildcard namespace. Clients are allowed to list
+// all network policies.
+func (n *NetworkPolicy) List(args *structs.NetworkPolicyListRequest, reply *structs.NetworkPolicyListResponse) error {
+	authErr := n.srv.Authenticate(n.ctx, args)
+	if done, err := n.srv.forward("NetworkPolicy.List", args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("network_policy", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "network_policy", "list"}, time.Now())
+
+	aclObj, err := n.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	allowNs := func(ns string) bool {
+		return aclObj.AllowClientOp() || aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+	}
+	if !allowNs(args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+
+			if namespace := args.RequestNamespace(); namespace == structs.AllNamespacesSentinel {
+				iter, err = store.NetworkPolicies(ws)
+			} else {
+				iter, err = store.NetworkPoliciesByNamespace(ws, namespace, args.Prefix)
+			}
+			if err != nil {
+				return err
+			}
+
+			tokenizer := paginator.NewStructsTokenizer(iter,
+				paginator.StructsTokenizerOptions{
+					WithNamespace: true,
+					WithID:        true,
+				},
+			)
+			filters := []paginator.Filter{
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						policy := raw.(*structs.NetworkPolicy)
+						return allowNs(policy.Namespace), nil
+					},
+				},
+			}
+
+			var policies []*structs.NetworkPolicy
+			pager, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
+				func(raw interface{}) error {
+					policies = append(policies, raw.(*structs.NetworkPolicy))
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := pager.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
+			reply.NetworkPolicies = policies
+
+			// Use the last index that affected the network policies table.
+			return n.srv.setReplyQueryMeta(store, state.TableNetworkPolicies, &reply.QueryMeta)
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// GetNetworkPolicy returns the specific network policy requested or nil if
+// the network policy doesn't exist.
+func (n *NetworkPolicy) GetNetworkPolicy(args *structs.NetworkPolicySpecificRequest, reply *structs.SingleNetworkPolicyResponse) error {
+	authErr := n.srv.Authenticate(n.ctx, args)
+	if done, err := n.srv.forward("NetworkPolicy.GetNetworkPolicy", args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("network_policy", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "network_policy", "get_network_policy"}, time.Now())
+
+	aclObj, err := n.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			policy, err := store.NetworkPolicyByName(ws, args.RequestNamespace(), args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.NetworkPolicy = policy
+			if policy != nil {
+				reply.Index = policy.ModifyIndex
+				n.srv.setQueryMeta(&reply.QueryMeta)
+				return nil
+			}
+
+			// Return the last index that affected the network policies table
+			// if the requested policy doesn't exist.
+			return n.srv.setReplyQueryMeta(store, state.TableNetworkPolicies, &reply.QueryMeta)
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// UpsertNetworkPolicies creates or updates the given network policies.
+func (n *NetworkPolicy) UpsertNetworkPolicies(args *structs.NetworkPolicyUpsertRequest, reply *structs.GenericResponse) error {
+	authErr := n.srv.Authenticate(n.ctx, args)
+	if done, err := n.srv.forward("NetworkPolicy.UpsertNetworkPolicies", args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("network_policy", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "network_policy", "upsert_network_policies"}, time.Now())
+
+	if len(args.NetworkPolicies) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one network policy")
+	}
+
+	// Policies without a namespace are written to the request namespace.
+	for _, policy := range args.NetworkPolicies {
+		if policy == nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "network policy is empty")
+		}
+		if policy.Namespace == "" {
+			policy.Namespace = args.RequestNamespace()
+		}
+	}
+
+	// Resolve ACL token and verify it can submit jobs in the namespace of
+	// every policy in the request.
+	aclObj, err := n.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	for _, policy := range args.NetworkPolicies {
+		if !aclObj.AllowNsOp(policy.Namespace, acl.NamespaceCapabilitySubmitJob) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	if !ServersMeetMinimumVersion(
+		n.srv.serf.Members(), n.srv.Region(), minNetworkPolicyVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to upsert network policies", minNetworkPolicyVersion)
+	}
+
+	var mErr multierror.Error
+	for _, policy := range args.NetworkPolicies {
+		if err := policy.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("invalid network policy %q: %w", policy.Name, err))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "%v", err)
+	}
+
+	_, index, err := n.srv.raftApply(structs.NetworkPolicyUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// DeleteNetworkPolicies deletes the named network policies from the request
+// namespace.
+func (n *NetworkPolicy) DeleteNetworkPolicies(args *structs.NetworkPolicyDeleteRequest, reply *structs.GenericResponse) error {
+	authErr := n.srv.Authenticate(n.ctx, args)
+	if done, err := n.srv.forward("NetworkPolicy.DeleteNetworkPolicies", args, args, reply); done {
+		return err
+	}
+	n.srv.MeasureRPCRate("network_policy", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "network_policy", "delete_network_policies"}, time.Now())
+
+	aclObj, err := n.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		n.srv.serf.Members(), n.srv.Region(), minNetworkPolicyVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to delete network policies", minNetworkPolicyVersion)
+	}
+
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one network policy to delete")
+	}
+	for _, name := range args.Names {
+		if name == "" {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "network policy name is empty")
+		}
+	}
+
+	_, index, err := n.srv.raftApply(structs.NetworkPolicyDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestNetworkPolicyEndpoint_CRUD(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Upsert a policy without a namespace, which should be written to the
+	// request namespace.
+	policy := mock.NetworkPolicy()
+	policy.Namespace = ""
+	upsertReq := &structs.NetworkPolicyUpsertRequest{
+		NetworkPolicies: []*structs.NetworkPolicy{policy},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var upsertResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "NetworkPolicy.UpsertNetworkPolicies", upsertReq, &upsertResp)
+	must.NoError(t, err)
+	must.NonZero(t, upsertResp.Index)
+
+	getReq := &structs.NetworkPolicySpecificRequest{
+		Name: policy.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var getResp structs.SingleNetworkPolicyResponse
+	err = msgpackrpc.CallWithCodec(codec, "NetworkPolicy.GetNetworkPolicy", getReq, &getResp)
+	must.NoError(t, err)
+	must.NotNil(t, getResp.NetworkPolicy)
+	must.Eq(t, structs.DefaultNamespace, getResp.NetworkPolicy.Namespace)
+	must.Eq(t, upsertResp.Index, getResp.Index)
+
+	// Invalid policies are rejected.
+	invalid := mock.NetworkPolicy()
+	invalid.Ingress[0].Protocol = "sctp"
+	upsertReq.NetworkPolicies = []*structs.NetworkPolicy{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "NetworkPolicy.UpsertNetworkPolicies", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "invalid protocol")
+
+	// Policies in a nonexistent namespace are rejected.
+	orphan := mock.NetworkPolicy()
+	orphan.Namespace = "nonexistent"
+	upsertReq.NetworkPolicies = []*structs.NetworkPolicy{orphan}
+	err = msgpackrpc.CallWithCodec(codec, "NetworkPolicy.UpsertNetworkPolicies", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "nonexistent namespace")
+
+	deleteReq := &structs.NetworkPolicyDeleteRequest{
+		Names: []string{policy.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var deleteResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "NetworkPolicy.DeleteNetworkPolicies", deleteReq, &deleteResp)
+	must.NoError(t, err)
+
+	getReq.MinQueryIndex = 0
+	getResp = structs.SingleNetworkPolicyResponse{}
+	err = msgpackrpc.CallWithCodec(codec, "NetworkPolicy.GetNetworkPolicy", getReq, &getResp)
+	must.NoError(t, err)
+	must.Nil(t, getResp.NetworkPolicy)
+
+	// Deleting a policy that doesn't exist is an error.
+	err = msgpackrpc.CallWithCodec(codec, "NetworkPolicy.DeleteNetworkPolicies", deleteReq, &deleteResp)
+	must.ErrorContains(t, err, "not found")
+}
+
+func TestNetworkPolicyEndpoint_List_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{{Name: "prod"}}))
+
+	defaultPolicy := mock.NetworkPolicy()
+	prodPolicy := mock.NetworkPolicy()
+	prodPolicy.Namespace = "prod"
+	must.NoError(t, store.UpsertNetworkPolicies(structs.MsgTypeTestSetup, 1001,
+		[]*structs.NetworkPolicy{defaultPolicy, prodPolicy}))
+
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1002, node))
+
+	prodToken := mock.CreatePolicyAndToken(t, store, 1003, "prod-read",
+		mock.NamespacePolicy("prod", "", []string{acl.NamespaceCapabilityReadJob}))
+	noPolicyToken := mock.CreateToken(t, store, 1005, nil)
+
+	testCases := []struct {
+		name        string
+		token       string
+		namespace   string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:      "management token lists all namespaces",
+			token:     root.SecretID,
+			namespace: structs.AllNamespacesSentinel,
+			expected:  []string{defaultPolicy.Name, prodPolicy.Name},
+		},
+		{
+			name:      "client node lists all namespaces",
+			token:     node.SecretID,
+			namespace: structs.AllNamespacesSentinel,
+			expected:  []string{defaultPolicy.Name, prodPolicy.Name},
+		},
+		{
+			name:      "namespace token filters wildcard",
+			token:     prodToken.SecretID,
+			namespace: structs.AllNamespacesSentinel,
+			expected:  []string{prodPolicy.Name},
+		},
+		{
+			name:      "namespace token lists own namespace",
+			token:     prodToken.SecretID,
+			namespace: "prod",
+			expected:  []string{prodPolicy.Name},
+		},
+		{
+			name:        "namespace token denied other namespace",
+			token:       prodToken.SecretID,
+			namespace:   structs.DefaultNamespace,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:        "no policy token",
+			token:       noPolicyToken.SecretID,
+			namespace:   structs.AllNamespacesSentinel,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &structs.NetworkPolicyListRequest{
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Namespace: tc.namespace,
+					AuthToken: tc.token,
+				},
+			}
+			var resp structs.NetworkPolicyListResponse
+			err := msgpackrpc.CallWithCodec(codec, "NetworkPolicy.List", req, &resp)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+
+			got := make([]string, len(resp.NetworkPolicies))
+			for i, policy := range resp.NetworkPolicies {
+				got[i] = policy.Name
+			}
+			must.SliceContainsAll(t, tc.expected, got)
+		})
+	}
+}
+
+func TestNetworkPolicyEndpoint_Upsert_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, store, 1001, "default-read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "read", nil))
+	writeToken := mock.CreatePolicyAndToken(t, store, 1003, "default-write",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil))
+
+	for _, tc := range []struct {
+		name        string
+		token       string
+		expectedErr string
+	}{
+		{
+			name:        "read token denied",
+			token:       readToken.SecretID,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:  "write token allowed",
+			token: writeToken.SecretID,
+		},
+		{
+			name:  "management token allowed",
+			token: root.SecretID,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &structs.NetworkPolicyUpsertRequest{
+				NetworkPolicies: []*structs.NetworkPolicy{mock.NetworkPolicy()},
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					AuthToken: tc.token,
+				},
+			}
+			var resp structs.GenericResponse
+			err := msgpackrpc.CallWithCodec(codec, "NetworkPolicy.UpsertNetworkPolicies", req, &resp)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+}
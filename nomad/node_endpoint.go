@@ -1074,19 +1074,20 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 		return structs.ErrPermissionDenied
 	}
 
-	// cache namespace perms
-	readableNamespaces := map[string]bool{}
+	// cache job perms
+	readableJobs := map[structs.NamespacedID]bool{}
 
-	// readNS is a caching namespace read-job helper
-	readNS := func(ns string) bool {
-		if readable, ok := readableNamespaces[ns]; ok {
+	// readJob is a caching job read-job helper
+	readJob := func(ns, jobID string) bool {
+		key := structs.NamespacedID{Namespace: ns, ID: jobID}
+		if readable, ok := readableJobs[key]; ok {
 			// cache hit
 			return readable
 		}
 
 		// cache miss
-		readable := aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
-		readableNamespaces[ns] = readable
+		readable := aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob)
+		readableJobs[key] = readable
 		return readable
 	}
 
@@ -1110,7 +1111,7 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 			if n := len(allocs); n != 0 {
 				reply.Allocs = make([]*structs.Allocation, 0, n)
 				for _, alloc := range allocs {
-					if readNS(alloc.Namespace) {
+					if readJob(alloc.Namespace, alloc.JobID) {
 						reply.Allocs = append(reply.Allocs, alloc)
 					}
 
@@ -1511,9 +1512,11 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 	// For each allocation we are updating, check if we should revoke any
 	// - Vault token accessors
 	// - Service Identity token accessors
+	// - Nomad ACL tokens derived for tasks
 	var (
 		revokeVault []*structs.VaultAccessor
 		revokeSI    []*structs.SITokenAccessor
+		revokeACL   []string
 	)
 
 	for _, alloc := range updates {
@@ -1539,6 +1542,16 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 		} else {
 			revokeSI = append(revokeSI, accessors...)
 		}
+
+		// Determine if there are any ACL tokens derived for the allocation
+		if tokens, err := n.srv.State().ACLTokensByAllocID(ws, alloc.ID); err != nil {
+			n.logger.Error("looking up acl tokens for alloc failed", "alloc_id", alloc.ID, "error", err)
+			mErr.Errors = append(mErr.Errors, err)
+		} else {
+			for _, token := range tokens {
+				revokeACL = append(revokeACL, token.AccessorID)
+			}
+		}
 	}
 
 	// Revoke any orphaned Vault token accessors
@@ -1556,6 +1569,18 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 		_ = n.srv.consulACLs.RevokeTokens(context.Background(), revokeSI, true)
 	}
 
+	// Revoke any ACL tokens derived for the terminal allocations
+	if l := len(revokeACL); l > 0 {
+		n.logger.Debug("revoking acl tokens due to terminal allocations", "num_tokens", l)
+		if _, _, err := n.srv.raftApply(structs.ACLTokenDeleteRequestType, &structs.ACLTokenDeleteRequest{
+			AccessorIDs:  revokeACL,
+			WriteRequest: structs.WriteRequest{Region: n.srv.config.Region},
+		}); err != nil {
+			n.logger.Error("acl token revocation failed", "error", err)
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	// Respond to the future
 	future.Respond(index, mErr.ErrorOrNil())
 }
@@ -410,7 +410,8 @@ func (n *NodePool) ListJobs(args *structs.NodePoolJobsRequest, reply *structs.No
 	if !aclObj.AllowNodePoolOperation(args.Name, acl.NodePoolCapabilityRead) {
 		return structs.ErrPermissionDenied
 	}
-	allowNsFunc := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityListJobs)
+	allowNsFunc := aclObj.AllowNsOpAnyJobFunc(acl.NamespaceCapabilityListJobs)
+	allowJobFunc := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityListJobs)
 	namespace := args.RequestNamespace()
 	sort := state.QueryOptionSort(args.QueryOptions)
 
@@ -445,6 +446,9 @@ func (n *NodePool) ListJobs(args *structs.NodePoolJobsRequest, reply *structs.No
 					paginator.NamespaceFilter{
 						AllowableNamespaces: allowableNamespaces,
 					},
+					paginator.JobFilter{
+						Allow: allowJobFunc,
+					},
 				}
 
 				if namespace == structs.AllNamespacesSentinel {
@@ -42,7 +42,7 @@ func (p *Periodic) Force(args *structs.PeriodicForceRequest, reply *structs.Peri
 	// Check for write-job permissions
 	if aclObj, err := p.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityDispatchJob) && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if !aclObj.AllowJobOpFunc(acl.NamespaceCapabilityDispatchJob, acl.NamespaceCapabilitySubmitJob)(args.RequestNamespace(), args.JobID) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/raftcrypt"
+)
+
+// setupRaftKeyring loads the keyring that encrypts the raft data in path. A
+// new keyring is created when encryption is enabled for the first time, and
+// the active key is rotated if it's older than the rotation threshold. The
+// keyring is returned even if encryption is disabled, so that data encrypted
+// before it was disabled can be read. A nil keyring means no raft data has
+// ever been encrypted.
+func (s *Server) setupRaftKeyring(path string) (*raftcrypt.Keyring, error) {
+	keyringPath := filepath.Join(path, raftcrypt.KeyringFile)
+
+	keyring, err := raftcrypt.LoadKeyring(keyringPath, s.encrypter)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if !s.config.RaftEncryptionEnabled {
+			return nil, nil
+		}
+		keyring = raftcrypt.NewKeyring()
+	case err != nil:
+		return nil, fmt.Errorf("failed to load raft keyring: %w", err)
+	case !s.config.RaftEncryptionEnabled:
+		s.logger.Warn("raft encryption is disabled; new raft data will be written unencrypted")
+		return keyring, nil
+	}
+
+	// The agent refuses to enable raft encryption without an external keyring
+	// provider, but servers can still be configured directly.
+	if !s.encrypter.hasExternalKEKProvider() {
+		s.logger.Warn("raft encryption is enabled without an external keyring provider; the raft keyring is stored unwrapped next to the data it encrypts")
+	}
+
+	id, createTime := keyring.Newest()
+	if id == "" || time.Since(createTime) > s.config.RaftEncryptionRotationThreshold {
+		return keyring, s.rotateRaftKey(keyring, keyringPath)
+	}
+
+	// Save the keyring again so that it's wrapped by the active keyring
+	// provider if that has changed since it was last saved.
+	if err := keyring.Save(keyringPath, s.encrypter); err != nil {
+		return nil, fmt.Errorf("failed to save raft keyring: %w", err)
+	}
+	return keyring, keyring.SetActive(id)
+}
+
+// rotateRaftKey adds a new key to the raft keyring and makes it the active
+// key. The key is only used once the keyring has been saved with it, so that
+// raft data can never be encrypted with a key that could be lost.
+func (s *Server) rotateRaftKey(keyring *raftcrypt.Keyring, keyringPath string) error {
+	key, err := raftcrypt.GenerateKey()
+	if err != nil {
+		return err
+	}
+	id, err := keyring.AddKey(key, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := keyring.Save(keyringPath, s.encrypter); err != nil {
+		return fmt.Errorf("failed to save raft keyring: %w", err)
+	}
+	if err := keyring.SetActive(id); err != nil {
+		return err
+	}
+	s.logger.Info("rotated raft encryption key", "key_id", id)
+	return nil
+}
+
+// raftKeyRotationLoop periodically rotates the active raft encryption key
+// once it's older than the rotation threshold.
+func (s *Server) raftKeyRotationLoop(keyring *raftcrypt.Keyring, keyringPath string) {
+	threshold := s.config.RaftEncryptionRotationThreshold
+	interval := min(threshold/10, time.Hour)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCtx.Done():
+			return
+		case <-ticker.C:
+			_, createTime := keyring.Active()
+			if time.Since(createTime) <= threshold {
+				continue
+			}
+			if err := s.rotateRaftKey(keyring, keyringPath); err != nil {
+				s.logger.Error("failed to rotate raft encryption key", "error", err)
+			}
+		}
+	}
+}
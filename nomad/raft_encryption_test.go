@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestRaftEncryption(t *testing.T) {
+	ci.Parallel(t)
+
+	dataDir := t.TempDir()
+	raftDir := filepath.Join(dataDir, raftState)
+	nodeID := uuid.Generate()
+
+	startServer := func(encrypt bool) (*Server, func()) {
+		return TestServer(t, func(c *Config) {
+			c.NodeID = nodeID
+			c.NodeName = "node1"
+			c.NumSchedulers = 0
+			c.DevMode = false
+			c.DataDir = dataDir
+			c.RaftEncryptionEnabled = encrypt
+		})
+	}
+
+	registerJob := func(srv *Server, secret string) *structs.Job {
+		job := mock.Job()
+		job.TaskGroups[0].Tasks[0].Env["SECRET"] = secret
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(rpcClient(t, srv), "Job.Register", req, &resp))
+		return job
+	}
+
+	requireJob := func(srv *Server, job *structs.Job) {
+		out, err := srv.State().JobByID(nil, job.Namespace, job.ID)
+		must.NoError(t, err)
+		must.NotNil(t, out)
+	}
+
+	// Start without encryption to write plaintext raft data
+	srv, shutdown := startServer(false)
+	testutil.WaitForLeader(t, srv.RPC)
+	plaintextJob := registerJob(srv, "plaintext-secret")
+	shutdown()
+	must.FileNotExists(t, filepath.Join(raftDir, raftcrypt.KeyringFile))
+
+	// Enabling encryption keeps the existing data readable
+	srv, shutdown = startServer(true)
+	testutil.WaitForLeader(t, srv.RPC)
+	requireJob(srv, plaintextJob)
+
+	must.FileExists(t, filepath.Join(raftDir, raftcrypt.KeyringFile))
+	activeID, _ := srv.raftKeyring.Active()
+	must.NotEq(t, "", activeID)
+
+	const secret = "encrypted-secret"
+	encryptedJob := registerJob(srv, secret)
+	must.NoError(t, srv.raft.Snapshot().Error())
+	shutdown()
+
+	// Neither the log store nor the snapshots contain the secret
+	raw, err := os.ReadFile(filepath.Join(raftDir, "raft.db"))
+	must.NoError(t, err)
+	must.False(t, bytes.Contains(raw, []byte(secret)))
+
+	snapshots, err := filepath.Glob(filepath.Join(raftDir, "snapshots", "*", "state.bin"))
+	must.NoError(t, err)
+	must.SliceNotEmpty(t, snapshots)
+	for _, snapshot := range snapshots {
+		raw, err := os.ReadFile(snapshot)
+		must.NoError(t, err)
+		must.True(t, raftcrypt.IsEncrypted(raw))
+	}
+
+	// Disabling encryption keeps the encrypted data readable
+	srv, shutdown = startServer(false)
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	requireJob(srv, plaintextJob)
+	requireJob(srv, encryptedJob)
+	activeID, _ = srv.raftKeyring.Active()
+	must.Eq(t, "", activeID)
+}
@@ -43,16 +43,14 @@ func (p *Scaling) ListPolicies(args *structs.ScalingPolicyListRequest, reply *st
 		return p.listAllNamespaces(args, reply)
 	}
 
-	if aclObj, err := p.srv.ResolveACL(args); err != nil {
+	aclObj, err := p.srv.ResolveACL(args)
+	if err != nil {
 		return err
-	} else {
-		hasListScalingPolicies := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListScalingPolicies)
-		hasListAndReadJobs := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) &&
-			aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob)
-		if !(hasListScalingPolicies || hasListAndReadJobs) {
-			return structs.ErrPermissionDenied
-		}
 	}
+	if !allowScalingPoliciesNs(aclObj, acl.NamespaceCapabilityListScalingPolicies)(args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+	allowJob := allowScalingPoliciesJob(aclObj, acl.NamespaceCapabilityListScalingPolicies)
 
 	// Setup the blocking query
 	opts := blockingOptions{
@@ -78,6 +76,9 @@ func (p *Scaling) ListPolicies(args *structs.ScalingPolicyListRequest, reply *st
 			reply.Policies = nil
 			for raw := iter.Next(); raw != nil; raw = iter.Next() {
 				policy := raw.(*structs.ScalingPolicy)
+				if !allowJob(args.RequestNamespace(), policy.Target[structs.ScalingTargetJob]) {
+					continue
+				}
 				reply.Policies = append(reply.Policies, policy.Stub())
 			}
 
@@ -112,16 +113,14 @@ func (p *Scaling) GetPolicy(args *structs.ScalingPolicySpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "scaling", "get_policy"}, time.Now())
 
 	// Check for list-job permissions
-	if aclObj, err := p.srv.ResolveACL(args); err != nil {
+	aclObj, err := p.srv.ResolveACL(args)
+	if err != nil {
 		return err
-	} else {
-		hasReadScalingPolicy := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadScalingPolicy)
-		hasListAndReadJobs := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) &&
-			aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob)
-		if !(hasReadScalingPolicy || hasListAndReadJobs) {
-			return structs.ErrPermissionDenied
-		}
 	}
+	if !allowScalingPoliciesNs(aclObj, acl.NamespaceCapabilityReadScalingPolicy)(args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+	allowJob := allowScalingPoliciesJob(aclObj, acl.NamespaceCapabilityReadScalingPolicy)
 
 	// Setup the blocking query
 	opts := blockingOptions{
@@ -134,6 +133,11 @@ func (p *Scaling) GetPolicy(args *structs.ScalingPolicySpecificRequest,
 				return err
 			}
 
+			// Hide the policy if the caller can't access its job.
+			if p != nil && !allowJob(p.Target[structs.ScalingTargetNamespace], p.Target[structs.ScalingTargetJob]) {
+				p = nil
+			}
+
 			reply.Policy = p
 
 			// If the state lookup returned a policy object, use the modify
@@ -160,10 +164,8 @@ func (p *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 		return err
 	}
 	prefix := args.QueryOptions.Prefix
-	allow := func(ns string) bool {
-		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListScalingPolicies) ||
-			(aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListJobs) && aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob))
-	}
+	allow := allowScalingPoliciesNs(aclObj, acl.NamespaceCapabilityListScalingPolicies)
+	allowJob := allowScalingPoliciesJob(aclObj, acl.NamespaceCapabilityListScalingPolicies)
 
 	// Setup the blocking query
 	opts := blockingOptions{
@@ -198,6 +200,10 @@ func (p *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 					// not permitted to this name namespace
 					continue
 				}
+				if !allowJob(policy.Target[structs.ScalingTargetNamespace], policy.Target[structs.ScalingTargetJob]) {
+					// not permitted to this job
+					continue
+				}
 				if prefix != "" && !strings.HasPrefix(policy.ID, prefix) {
 					continue
 				}
@@ -218,3 +224,26 @@ func (p *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 		}}
 	return p.srv.blockingRPC(&opts)
 }
+
+// allowScalingPoliciesNs returns a function that checks if the ACL object
+// allows the scaling policy operation, or listing and reading jobs, for the
+// namespace or any job in it. The results must be filtered with
+// allowScalingPoliciesJob.
+func allowScalingPoliciesNs(aclObj *acl.ACL, op string) func(string) bool {
+	return func(ns string) bool {
+		return aclObj.AllowNsOpAnyJob(ns, op) ||
+			(aclObj.AllowNsOpAnyJob(ns, acl.NamespaceCapabilityListJobs) &&
+				aclObj.AllowNsOpAnyJob(ns, acl.NamespaceCapabilityReadJob))
+	}
+}
+
+// allowScalingPoliciesJob returns a function that checks if the ACL object
+// allows the scaling policy operation, or listing and reading the job, for the
+// job in the namespace.
+func allowScalingPoliciesJob(aclObj *acl.ACL, op string) func(string, string) bool {
+	return func(ns, jobID string) bool {
+		return aclObj.AllowJobOp(ns, jobID, op) ||
+			(aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityListJobs) &&
+				aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob))
+	}
+}
@@ -381,11 +381,14 @@ func sortSet(matches []fuzzyMatch) {
 func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix string, ws memdb.WatchSet, store *state.StateStore) (memdb.ResultIterator, error) {
 	switch context {
 	case structs.Jobs:
-		return store.JobsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.JobsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return jobCapIterFilter(iter, err, aclObj)
 	case structs.Evals:
-		return store.EvalsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.EvalsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return jobCapIterFilter(iter, err, aclObj)
 	case structs.Allocs:
-		return store.AllocsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.AllocsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return jobCapIterFilter(iter, err, aclObj)
 	case structs.Nodes:
 		return store.NodesByIDPrefix(ws, prefix)
 	case structs.NodePools:
@@ -398,11 +401,13 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 		}
 		return memdb.NewFilterIterator(iter, nodePoolCapFilter(aclObj)), nil
 	case structs.Deployments:
-		return store.DeploymentsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		iter, err := store.DeploymentsByIDPrefix(ws, namespace, prefix, state.SortDefault)
+		return jobCapIterFilter(iter, err, aclObj)
 	case structs.Plugins:
 		return store.CSIPluginsByIDPrefix(ws, prefix)
 	case structs.ScalingPolicies:
-		return store.ScalingPoliciesByIDPrefix(ws, namespace, prefix)
+		iter, err := store.ScalingPoliciesByIDPrefix(ws, namespace, prefix)
+		return jobCapIterFilter(iter, err, aclObj)
 	case structs.Volumes:
 		return store.CSIVolumesByIDPrefix(ws, namespace, prefix)
 	case structs.Namespaces:
@@ -436,14 +441,16 @@ func getFuzzyResourceIterator(context structs.Context, aclObj *acl.ACL, namespac
 			iter, err := store.Jobs(ws, state.SortDefault)
 			return nsCapIterFilter(iter, err, aclObj)
 		}
-		return store.JobsByNamespace(ws, namespace, state.SortDefault)
+		iter, err := store.JobsByNamespace(ws, namespace, state.SortDefault)
+		return jobCapIterFilter(iter, err, aclObj)
 
 	case structs.Allocs:
 		if wildcard(namespace) {
 			iter, err := store.Allocs(ws, state.SortDefault)
 			return nsCapIterFilter(iter, err, aclObj)
 		}
-		return store.AllocsByNamespace(ws, namespace)
+		iter, err := store.AllocsByNamespace(ws, namespace)
+		return jobCapIterFilter(iter, err, aclObj)
 
 	case structs.Variables:
 		if wildcard(namespace) {
@@ -496,16 +503,41 @@ func nsCapIterFilter(iter memdb.ResultIterator, err error, aclObj *acl.ACL) (mem
 	return memdb.NewFilterIterator(iter, nsCapFilter(aclObj)), nil
 }
 
+// jobCapIterFilter wraps an iterator over objects that belong to a job with a
+// filter for removing the objects of the jobs the token does not have
+// permission to read. The namespace was already checked by the caller, but
+// job rules may deny access to some of its jobs.
+func jobCapIterFilter(iter memdb.ResultIterator, err error, aclObj *acl.ACL) (memdb.ResultIterator, error) {
+	if err != nil {
+		return nil, err
+	}
+	if aclObj.IsManagement() {
+		return iter, nil
+	}
+	return memdb.NewFilterIterator(iter, nsCapFilter(aclObj)), nil
+}
+
 // nsCapFilter produces a memdb.FilterFunc for removing objects not accessible
 // by aclObj during a table scan.
 func nsCapFilter(aclObj *acl.ACL) memdb.FilterFunc {
 	return func(v interface{}) bool {
 		switch t := v.(type) {
 		case *structs.Job:
-			return !aclObj.AllowNsOp(t.Namespace, acl.NamespaceCapabilityReadJob)
+			return !aclObj.AllowJobOp(t.Namespace, t.ID, acl.NamespaceCapabilityReadJob)
 
 		case *structs.Allocation:
-			return !aclObj.AllowNsOp(t.Namespace, acl.NamespaceCapabilityReadJob)
+			return !aclObj.AllowJobOp(t.Namespace, t.JobID, acl.NamespaceCapabilityReadJob)
+
+		case *structs.Evaluation:
+			return !aclObj.AllowJobOp(t.Namespace, t.JobID, acl.NamespaceCapabilityReadJob)
+
+		case *structs.Deployment:
+			return !aclObj.AllowJobOp(t.Namespace, t.JobID, acl.NamespaceCapabilityReadJob)
+
+		case *structs.ScalingPolicy:
+			ns, jobID := t.Target[structs.ScalingTargetNamespace], t.Target[structs.ScalingTargetJob]
+			return !aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityListScalingPolicies) &&
+				!aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob)
 
 		case *structs.VariableEncrypted:
 			return !aclObj.AllowVariableSearch(t.Namespace)
@@ -678,7 +710,7 @@ func sufficientSearchPerms(aclObj *acl.ACL, namespace string, context structs.Co
 		return aclObj.AllowNamespace(namespace)
 	case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs,
 		structs.ScalingPolicies, structs.Recommendations:
-		return aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+		return aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob)
 	case structs.Volumes:
 		return acl.NamespaceValidator(acl.NamespaceCapabilityCSIListVolume,
 			acl.NamespaceCapabilityCSIReadVolume,
@@ -887,13 +919,13 @@ func filteredSearchContexts(aclObj *acl.ACL, namespace string, context structs.C
 	if aclObj.IsManagement() {
 		return desired
 	}
-	jobRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
+	jobRead := aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityReadJob)
 	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityCSIListVolume,
 		acl.NamespaceCapabilityCSIReadVolume,
 		acl.NamespaceCapabilityListJobs,
 		acl.NamespaceCapabilityReadJob)
 	volRead := allowVolume(aclObj, namespace)
-	policyRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityListScalingPolicies)
+	policyRead := aclObj.AllowNsOpAnyJob(namespace, acl.NamespaceCapabilityListScalingPolicies)
 
 	// Filter contexts down to those the ACL grants access to
 	available := make([]structs.Context, 0, len(desired))
@@ -262,6 +262,57 @@ func TestSearch_PrefixSearch_ACL(t *testing.T) {
 	}
 }
 
+func TestSearch_PrefixSearch_JobRules_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, _, cleanupS := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	allowed := mock.Job()
+	allowed.ID = "web-api"
+	registerJob(s, t, allowed)
+	denied := mock.Job()
+	denied.ID = "web-secret"
+	registerJob(s, t, denied)
+
+	alloc := mock.Alloc()
+	alloc.JobID = denied.ID
+	alloc.Job = denied
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	// A token with job rules only can search the jobs it can read.
+	token := mock.CreatePolicyAndToken(t, store, 1002, "web", `
+namespace "default" {
+  job "web-*" { capabilities = ["read-job"] }
+  job "web-secret" { capabilities = ["deny"] }
+}`)
+
+	req := &structs.SearchRequest{
+		Prefix:  "web",
+		Context: structs.Jobs,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SearchResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp))
+	must.Eq(t, []string{allowed.ID}, resp.Matches[structs.Jobs])
+
+	// The allocations of denied jobs are filtered out as well.
+	req.Prefix = alloc.ID[:8]
+	req.Context = structs.Allocs
+	resp = structs.SearchResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp))
+	must.Len(t, 0, resp.Matches[structs.Allocs])
+}
+
 func TestSearch_PrefixSearch_All_JobWithHyphen(t *testing.T) {
 	ci.Parallel(t)
 
@@ -38,6 +38,7 @@ import (
 	"github.com/hashicorp/nomad/helper/group"
 	"github.com/hashicorp/nomad/helper/iterator"
 	"github.com/hashicorp/nomad/helper/pool"
+	"github.com/hashicorp/nomad/helper/raftcrypt"
 	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/auth"
@@ -118,6 +119,10 @@ type Server struct {
 	raftInmem     *raft.InmemStore
 	raftTransport *raft.NetworkTransport
 
+	// raftKeyring encrypts the raft logs and snapshots at rest. It is nil if
+	// no raft data has ever been encrypted.
+	raftKeyring *raftcrypt.Keyring
+
 	// reassertLeaderCh is used to signal that the leader loop must
 	// re-establish leadership.
 	//
@@ -224,6 +229,10 @@ type Server struct {
 	// volumeWatcher is used to release volume claims
 	volumeWatcher *volumewatcher.Watcher
 
+	// templateRestarts is used by the leader to roll the restarts triggered
+	// by templates across the allocations of task groups
+	templateRestarts *templateRestarts
+
 	// volumeControllerFutures is a map of plugin IDs to pending controller RPCs. If
 	// no RPC is pending for a given plugin, this may be nil.
 	volumeControllerFutures map[string]context.Context
@@ -283,6 +292,10 @@ type Server struct {
 	lockTTLTimer   *lock.TTLTimer
 	lockDelayTimer *lock.DelayTimer
 
+	// variableExpiryTimer is used to track when variables with a TTL expire.
+	// Like the lock timers, it's only populated on the leader.
+	variableExpiryTimer *lock.TTLTimer
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
@@ -364,11 +377,14 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 		workersEventCh:          make(chan interface{}, 1),
 		lockTTLTimer:            lock.NewTTLTimer(),
 		lockDelayTimer:          lock.NewDelayTimer(),
+		variableExpiryTimer:     lock.NewTTLTimer(),
 	}
 
 	s.shutdownCtx, s.shutdownCancel = context.WithCancel(context.Background())
 	s.shutdownCh = s.shutdownCtx.Done()
 
+	s.templateRestarts = newTemplateRestarts(s.logger)
+
 	// Create an eval broker
 	evalBroker, err := NewEvalBroker(
 		s.shutdownCtx,
@@ -758,6 +774,11 @@ func (s *Server) Shutdown() error {
 		s.fsm.Close()
 	}
 
+	// Release any external keyring providers
+	if s.encrypter != nil {
+		s.encrypter.Close()
+	}
+
 	// Stop Vault token renewal and revocations
 	if s.vault != nil {
 		s.vault.Stop()
@@ -1321,6 +1342,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	// These endpoints are client RPCs and don't include a connection context
 	_ = server.Register(NewClientStatsEndpoint(s))
 	_ = server.Register(newNodeMetaEndpoint(s))
+	_ = server.Register(newArtifactCacheEndpoint(s))
+	_ = server.Register(newNodePrefetchEndpoint(s))
 
 	// These endpoints have their streaming component registered in
 	// setupStreamingEndpoints, but their non-streaming RPCs are registered
@@ -1340,9 +1363,11 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	_ = server.Register(NewCSIPluginEndpoint(s, ctx))
 	_ = server.Register(NewDeploymentEndpoint(s, ctx))
 	_ = server.Register(NewEvalEndpoint(s, ctx))
+	_ = server.Register(NewEventSinkEndpoint(s, ctx))
 	_ = server.Register(NewJobEndpoints(s, ctx))
 	_ = server.Register(NewKeyringEndpoint(s, ctx, s.encrypter))
 	_ = server.Register(NewNamespaceEndpoint(s, ctx))
+	_ = server.Register(NewNetworkPolicyEndpoint(s, ctx))
 	_ = server.Register(NewNodeEndpoint(s, ctx))
 	_ = server.Register(NewNodePoolEndpoint(s, ctx))
 	_ = server.Register(NewPeriodicEndpoint(s, ctx))
@@ -1353,6 +1378,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	_ = server.Register(NewServiceRegistrationEndpoint(s, ctx))
 	_ = server.Register(NewStatusEndpoint(s, ctx))
 	_ = server.Register(NewSystemEndpoint(s, ctx))
+	_ = server.Register(NewTemplateRestartEndpoint(s, ctx))
 	_ = server.Register(NewVariablesEndpoint(s, ctx, s.encrypter))
 
 	// Register non-streaming
@@ -1465,8 +1491,23 @@ func (s *Server) setupRaft() error {
 		// Start publishing bboltdb metrics
 		go store.RunMetrics(s.shutdownCtx, 0)
 
+		// Load the keyring encrypting the raft data at rest, if any
+		raftKeyring, err := s.setupRaftKeyring(path)
+		if err != nil {
+			store.Close()
+			return err
+		}
+		s.raftKeyring = raftKeyring
+		var logStore raft.LogStore = store
+		if raftKeyring != nil {
+			logStore = raftcrypt.NewLogStore(store, raftKeyring)
+			if s.config.RaftEncryptionEnabled {
+				go s.raftKeyRotationLoop(raftKeyring, filepath.Join(path, raftcrypt.KeyringFile))
+			}
+		}
+
 		// Wrap the store in a LogCache to improve performance
-		cacheStore, err := raft.NewLogCache(raftLogCacheSize, store)
+		cacheStore, err := raft.NewLogCache(raftLogCacheSize, logStore)
 		if err != nil {
 			store.Close()
 			return err
@@ -1482,6 +1523,9 @@ func (s *Server) setupRaft() error {
 			return err
 		}
 		snap = snapshots
+		if raftKeyring != nil {
+			snap = raftcrypt.NewSnapshotStore(snapshots, raftKeyring)
+		}
 
 		// For an existing cluster being upgraded to the new version of
 		// Raft, we almost never want to run recovery based on the old
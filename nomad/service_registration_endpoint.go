@@ -113,12 +113,25 @@ func (s *ServiceRegistration) DeleteByID(
 			minNomadServiceRegistrationVersion)
 	}
 
-	if aclObj, err := s.srv.ResolveACL(args); err != nil {
-		return structs.ErrPermissionDenied
-	} else if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) &&
-		!aclObj.AllowClientOp() {
+	aclObj, err := s.srv.ResolveACL(args)
+	if err != nil {
 		return structs.ErrPermissionDenied
 	}
+	if !aclObj.AllowClientOp() {
+		// Job rules may deny access to the job of the registration, so use it
+		// for the check if it exists.
+		reg, err := s.srv.State().GetServiceRegistrationByID(nil, args.RequestNamespace(), args.ID)
+		if err != nil {
+			return err
+		}
+		allowed := aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob)
+		if reg != nil {
+			allowed = aclObj.AllowJobOp(reg.Namespace, reg.JobID, acl.NamespaceCapabilitySubmitJob)
+		}
+		if !allowed {
+			return structs.ErrPermissionDenied
+		}
+	}
 
 	// Update via Raft.
 	_, index, err := s.srv.raftApply(structs.ServiceRegistrationDeleteByIDRequestType, args)
@@ -179,8 +192,8 @@ func (s *ServiceRegistration) List(
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowServiceRegistrationReadList(args.RequestNamespace(),
-		args.GetIdentity().Claims != nil) {
+	isWorkload := args.GetIdentity().Claims != nil
+	if !aclObj.AllowServiceRegistrationReadList(args.RequestNamespace(), isWorkload) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -201,6 +214,9 @@ func (s *ServiceRegistration) List(
 
 			for raw := iter.Next(); raw != nil; raw = iter.Next() {
 				serviceReg := raw.(*structs.ServiceRegistration)
+				if !aclObj.AllowServiceRegistrationRead(serviceReg.Namespace, serviceReg.JobID, isWorkload) {
+					continue
+				}
 				tagSet.add(serviceReg.ServiceName, serviceReg.Tags)
 			}
 
@@ -248,10 +264,12 @@ func (s *ServiceRegistration) listAllServiceRegistrations(
 	}
 
 	// allowFunc checks whether the caller has the read-job capability on the
-	// passed namespace.
+	// passed namespace or on any of its jobs, and allowJob whether it has it
+	// on a job.
 	allowFunc := func(ns string) bool {
-		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+		return aclObj.AllowNsOpAnyJob(ns, acl.NamespaceCapabilityReadJob)
 	}
+	allowJob := aclObj.AllowJobOpFunc(acl.NamespaceCapabilityReadJob)
 
 	// Set up and return the blocking query.
 	return s.srv.blockingRPC(&blockingOptions{
@@ -292,6 +310,9 @@ func (s *ServiceRegistration) listAllServiceRegistrations(
 				if allowedNSes != nil && !allowedNSes[reg.Namespace] {
 					continue
 				}
+				if !allowJob(reg.Namespace, reg.JobID) {
+					continue
+				}
 
 				// Accumulate the set of tags associated with a particular service name in a particular namespace
 				nsSvcTagSet.add(reg.Namespace, reg.ServiceName, reg.Tags)
@@ -344,8 +365,8 @@ func (s *ServiceRegistration) GetService(
 	if err != nil {
 		return structs.ErrPermissionDenied
 	}
-	if !aclObj.AllowServiceRegistrationReadList(args.RequestNamespace(),
-		args.GetIdentity().Claims != nil) {
+	isWorkload := args.GetIdentity().Claims != nil
+	if !aclObj.AllowServiceRegistrationReadList(args.RequestNamespace(), isWorkload) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -375,7 +396,15 @@ func (s *ServiceRegistration) GetService(
 
 			// Build the paginator. This includes the function that is
 			// responsible for appending a registration to the services array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			filters := []paginator.Filter{
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						reg := raw.(*structs.ServiceRegistration)
+						return aclObj.AllowServiceRegistrationRead(reg.Namespace, reg.JobID, isWorkload), nil
+					},
+				},
+			}
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					services = append(services, raw.(*structs.ServiceRegistration))
 					return nil
@@ -759,6 +759,46 @@ func TestServiceRegistration_List(t *testing.T) {
 				codec := rpcClient(t, s)
 				testutil.WaitForKeyring(t, s.RPC, "global")
 
+				// Create a policy and grab the token which has the read-job
+				// capability on the default namespace, except for the job of
+				// the service registration.
+				customToken := mock.CreatePolicyAndToken(t, s.State(), 10, "test-job-deny", `
+namespace "default" {
+  capabilities = ["read-job"]
+  job "example" { capabilities = ["deny"] }
+}`).SecretID
+
+				// Generate and upsert some service registrations.
+				services := mock.ServiceRegistrations()
+				require.NoError(t, s.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 20, services))
+
+				// The registrations of the denied job are filtered out of
+				// both the namespace and the wildcard listing.
+				for _, ns := range []string{structs.DefaultNamespace, structs.AllNamespacesSentinel} {
+					serviceRegReq := &structs.ServiceRegistrationListRequest{
+						QueryOptions: structs.QueryOptions{
+							Namespace: ns,
+							Region:    DefaultRegion,
+							AuthToken: customToken,
+						},
+					}
+					var serviceRegResp structs.ServiceRegistrationListResponse
+					err := msgpackrpc.CallWithCodec(
+						codec, structs.ServiceRegistrationListRPCMethod, serviceRegReq, &serviceRegResp)
+					require.NoError(t, err)
+					require.Empty(t, serviceRegResp.Services)
+				}
+			},
+			name: "ACLs enabled with job deny policy token",
+		},
+		{
+			serverFn: func(t *testing.T) (*Server, *structs.ACLToken, func()) {
+				return TestACLServer(t, nil)
+			},
+			testFn: func(t *testing.T, s *Server, token *structs.ACLToken) {
+				codec := rpcClient(t, s)
+				testutil.WaitForKeyring(t, s.RPC, "global")
+
 				// Create a namespace as this is needed when using an ACL like
 				// we do in this test.
 				ns := &structs.Namespace{
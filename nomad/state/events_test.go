@@ -1272,3 +1272,167 @@ func testNodeID() string {
 func testNodeIDTwo() string {
 	return "694ff31d-8c59-4030-ac83-e15692560c8d"
 }
+
+func Test_eventsFromChanges_Variable(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	sv := mock.VariableEncrypted()
+	sv.Lock = &structs.VariableLock{ID: uuid.Generate(), TTL: time.Minute}
+	resp := s.VarSet(1000, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+	must.NoError(t, resp.Error)
+
+	events := WaitForEvents(t, s, 1000, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	e := events[0]
+	must.Eq(t, structs.TopicVariable, e.Topic)
+	must.Eq(t, structs.TypeVariableUpserted, e.Type)
+	must.Eq(t, sv.Path, e.Key)
+	must.Eq(t, sv.Namespace, e.Namespace)
+
+	// The payload only holds the metadata without the lock ID.
+	payload := e.Payload.(*structs.VariableStreamEvent)
+	must.Eq(t, sv.Path, payload.Variable.Path)
+	must.Eq(t, "", payload.Variable.Lock.ID)
+	must.Eq(t, time.Minute, payload.Variable.Lock.TTL)
+	must.NotEq(t, "", sv.Lock.ID)
+
+	resp = s.VarDelete(1001, &structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: sv})
+	must.NoError(t, resp.Error)
+
+	events = WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TopicVariable, events[0].Topic)
+	must.Eq(t, structs.TypeVariableDeleted, events[0].Type)
+	must.Eq(t, sv.Path, events[0].Key)
+}
+
+func Test_eventsFromChanges_Namespace(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	// Namespace events are scoped to the namespace itself, so pull the events
+	// from the changes directly rather than subscribing to "default".
+	ns := mock.Namespace()
+	ns.SetHash()
+	writeTxn := s.db.WriteTxnMsgT(structs.NamespaceUpsertRequestType, 1000)
+	must.NoError(t, s.upsertNamespaceImpl(1000, writeTxn, ns))
+	writeTxn.Txn.Commit()
+
+	got := eventsFromChanges(writeTxn, Changes{
+		Changes: writeTxn.Changes(), Index: 1000, MsgType: structs.NamespaceUpsertRequestType})
+	must.Len(t, 1, got.Events)
+	must.Eq(t, structs.TopicNamespace, got.Events[0].Topic)
+	must.Eq(t, structs.TypeNamespaceUpserted, got.Events[0].Type)
+	must.Eq(t, ns.Name, got.Events[0].Key)
+	must.Eq(t, ns.Name, got.Events[0].Namespace)
+	must.Eq(t, ns.Name, got.Events[0].Payload.(*structs.NamespaceEvent).Namespace.Name)
+
+	deleteTxn := s.db.WriteTxnMsgT(structs.NamespaceDeleteRequestType, 1001)
+	must.NoError(t, deleteTxn.Delete(TableNamespaces, ns))
+	deleteTxn.Txn.Commit()
+
+	got = eventsFromChanges(deleteTxn, Changes{
+		Changes: deleteTxn.Changes(), Index: 1001, MsgType: structs.NamespaceDeleteRequestType})
+	must.Len(t, 1, got.Events)
+	must.Eq(t, structs.TopicNamespace, got.Events[0].Topic)
+	must.Eq(t, structs.TypeNamespaceDeleted, got.Events[0].Type)
+	must.Eq(t, ns.Name, got.Events[0].Key)
+}
+
+func Test_eventsFromChanges_ScalingPolicy(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	// Scaling policies are upserted along with their job and set their own
+	// event type.
+	job, policy := mock.JobWithScalingPolicy()
+	must.NoError(t, s.UpsertJob(structs.JobRegisterRequestType, 1000, nil, job))
+
+	events := WaitForEvents(t, s, 1000, 2, 1*time.Second)
+	must.Len(t, 2, events)
+
+	var policyEvent *structs.Event
+	for _, e := range events {
+		switch e.Topic {
+		case structs.TopicJob:
+			must.Eq(t, structs.TypeJobRegistered, e.Type)
+		case structs.TopicScalingPolicy:
+			policyEvent = &e
+		}
+	}
+	must.NotNil(t, policyEvent)
+	must.Eq(t, structs.TypeScalingPolicyUpserted, policyEvent.Type)
+	must.Eq(t, job.Namespace, policyEvent.Namespace)
+	must.Eq(t, []string{job.ID}, policyEvent.FilterKeys)
+	must.Eq(t, policy.Target, policyEvent.Payload.(*structs.ScalingPolicyEvent).ScalingPolicy.Target)
+
+	must.NoError(t, s.UpsertScalingEvent(1001, &structs.ScalingEventRequest{
+		Namespace:    job.Namespace,
+		JobID:        job.ID,
+		TaskGroup:    job.TaskGroups[0].Name,
+		ScalingEvent: structs.NewScalingEvent("scaled"),
+	}))
+
+	events = WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TopicScalingEvent, events[0].Topic)
+	must.Eq(t, structs.TypeScalingEventRegistered, events[0].Type)
+	must.Eq(t, job.ID, events[0].Key)
+	payload := events[0].Payload.(*structs.ScalingEventStreamEvent)
+	must.Len(t, 1, payload.ScalingEvents.ScalingEvents[job.TaskGroups[0].Name])
+}
+
+func Test_eventsFromChanges_RootKey(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	key := structs.NewRootKeyMeta()
+	key.SetActive()
+	must.NoError(t, s.UpsertRootKeyMeta(1000, key, false))
+
+	events := WaitForEvents(t, s, 1000, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TopicRootKey, events[0].Topic)
+	must.Eq(t, structs.TypeRootKeyUpserted, events[0].Type)
+	must.Eq(t, key.KeyID, events[0].Key)
+	must.Eq(t, key.KeyID, events[0].Payload.(*structs.RootKeyEvent).RootKeyMeta.KeyID)
+
+	must.NoError(t, s.DeleteRootKeyMeta(1001, key.KeyID))
+
+	events = WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TypeRootKeyDeleted, events[0].Type)
+}
+
+func Test_eventsFromChanges_CSIVolume(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	vol := mock.CSIVolume(mock.CSIPlugin())
+	vol.Secrets = structs.CSISecrets{"password": "secret"}
+	must.NoError(t, s.UpsertCSIVolume(1000, []*structs.CSIVolume{vol}))
+
+	events := WaitForEvents(t, s, 1000, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TopicCSIVolume, events[0].Topic)
+	must.Eq(t, structs.TypeCSIVolumeUpserted, events[0].Type)
+	must.Eq(t, vol.ID, events[0].Key)
+	must.Eq(t, vol.Namespace, events[0].Namespace)
+	must.Eq(t, []string{vol.PluginID}, events[0].FilterKeys)
+
+	// The payload is the volume stub that doesn't include the secrets.
+	must.Eq(t, vol.ID, events[0].Payload.(*structs.CSIVolumeEvent).Volume.ID)
+
+	must.NoError(t, s.CSIVolumeDeregister(1001, vol.Namespace, []string{vol.ID}, false))
+
+	events = WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.TypeCSIVolumeDeleted, events[0].Type)
+}
@@ -42,3 +42,28 @@ func (f NamespaceFilter) Evaluate(raw interface{}) (bool, error) {
 	}
 	return false, nil
 }
+
+// JobFilter skips elements whose job is not allowed by the Allow function,
+// which receives the namespace and job ID of the element.
+type JobFilter struct {
+	Allow func(namespace, jobID string) bool
+}
+
+func (f JobFilter) Evaluate(raw interface{}) (bool, error) {
+	if raw == nil {
+		return false, nil
+	}
+	if f.Allow == nil {
+		return true, nil
+	}
+
+	item, ok := raw.(JobIDGetter)
+	if !ok {
+		return false, nil
+	}
+	namespace := ""
+	if nsItem, ok := raw.(NamespaceGetter); ok {
+		namespace = nsItem.GetNamespace()
+	}
+	return f.Allow(namespace, item.GetJobID()), nil
+}
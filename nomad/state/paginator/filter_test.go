@@ -4,6 +4,7 @@
 package paginator
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -108,6 +109,42 @@ func TestNamespaceFilter(t *testing.T) {
 	}
 }
 
+func TestJobFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	mocks := []*mockObject{
+		{namespace: "default", jobID: "payments-api"},
+		{namespace: "default", jobID: "billing"},
+		{namespace: "dev", jobID: "payments-api"},
+	}
+
+	filters := []Filter{JobFilter{
+		Allow: func(namespace, jobID string) bool {
+			return namespace == "default" && strings.HasPrefix(jobID, "payments-")
+		},
+	}}
+	iter := newTestIteratorWithMocks(mocks)
+	tokenizer := testTokenizer{}
+	opts := structs.QueryOptions{
+		PerPage: int32(len(mocks)),
+	}
+
+	results := []string{}
+	paginator, err := NewPaginator(iter, tokenizer, filters, opts,
+		func(raw interface{}) error {
+			result := raw.(*mockObject)
+			results = append(results, result.namespace+"/"+result.jobID)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	nextToken, err := paginator.Page()
+	require.NoError(t, err)
+	require.Equal(t, "", nextToken)
+	require.Equal(t, []string{"default/payments-api"}, results)
+}
+
 func BenchmarkEvalListFilter(b *testing.B) {
 	const evalCount = 100_000
 
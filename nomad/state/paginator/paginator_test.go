@@ -146,12 +146,17 @@ type mockObject struct {
 	index     uint64
 	id        string
 	namespace string
+	jobID     string
 }
 
 func (m *mockObject) GetNamespace() string {
 	return m.namespace
 }
 
+func (m *mockObject) GetJobID() string {
+	return m.jobID
+}
+
 func newTestIterator(ids []string) testResultIterator {
 	iter := testResultIterator{results: make(chan interface{}, 20)}
 	for x, id := range ids {
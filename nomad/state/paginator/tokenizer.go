@@ -27,6 +27,12 @@ type NamespaceGetter interface {
 	GetNamespace() string
 }
 
+// JobIDGetter is the interface that must be implemented by structs that need
+// to be filtered by their job using the JobFilter.
+type JobIDGetter interface {
+	GetJobID() string
+}
+
 // CreateIndexGetter is the interface that must be implemented by structs that
 // need to have their CreateIndex as part of the pagination token.
 type CreateIndexGetter interface {
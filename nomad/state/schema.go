@@ -17,9 +17,12 @@ const (
 
 	TableNamespaces           = "namespaces"
 	TableNodePools            = "node_pools"
+	TableNetworkPolicies      = "network_policies"
+	TableEventSinks           = "event_sinks"
 	TableServiceRegistrations = "service_registrations"
 	TableVariables            = "variables"
 	TableVariablesQuotas      = "variables_quota"
+	TableVariableVersions     = "variable_versions"
 	TableRootKeyMeta          = "root_key_meta"
 	TableACLRoles             = "acl_roles"
 	TableACLAuthMethods       = "acl_auth_methods"
@@ -93,10 +96,13 @@ func init() {
 		serviceRegistrationsTableSchema,
 		variablesTableSchema,
 		variablesQuotasTableSchema,
+		variableVersionsTableSchema,
 		variablesRootKeyMetaSchema,
 		aclRolesTableSchema,
 		aclAuthMethodsTableSchema,
 		bindingRulesTableSchema,
+		networkPoliciesTableSchema,
+		eventSinksTableSchema,
 	}...)
 }
 
@@ -1040,6 +1046,14 @@ func aclTokenTableSchema() *memdb.TableSchema {
 					WriteIndex: indexer.WriteIndex(indexExpiresLocalFromACLToken),
 				},
 			},
+			"alloc_id": {
+				Name:         "alloc_id",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AllocID",
+				},
+			},
 		},
 	}
 }
@@ -1523,12 +1537,16 @@ func (s *variableKeyIDFieldIndexer) PrefixFromArgs(args ...interface{}) ([]byte,
 // an index value from an object or to indicate that the index value
 // is missing.
 func (s *variableKeyIDFieldIndexer) FromObject(obj interface{}) (bool, []byte, error) {
-	variable, ok := obj.(*structs.VariableEncrypted)
-	if !ok {
+	var keyID string
+	switch variable := obj.(type) {
+	case *structs.VariableEncrypted:
+		keyID = variable.KeyID
+	case *structs.VariableVersion:
+		keyID = variable.KeyID
+	default:
 		return false, nil, fmt.Errorf("object %#v is not a Variable", obj)
 	}
 
-	keyID := variable.KeyID
 	if keyID == "" {
 		return false, nil, nil
 	}
@@ -1538,6 +1556,42 @@ func (s *variableKeyIDFieldIndexer) FromObject(obj interface{}) (bool, []byte, e
 	return true, []byte(keyID), nil
 }
 
+// variableVersionsTableSchema returns the MemDB schema for the previous
+// versions of Nomad variables.
+func variableVersionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariableVersions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, Path,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Indexer:      &variableKeyIDFieldIndexer{},
+			},
+		},
+	}
+}
+
 // variablesQuotasTableSchema returns the MemDB schema for Nomad variables
 // quotas tracking
 func variablesQuotasTableSchema() *memdb.TableSchema {
@@ -1638,3 +1692,46 @@ func bindingRulesTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// networkPoliciesTableSchema returns the MemDB schema for network policies.
+func networkPoliciesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNetworkPolicies,
+		Indexes: map[string]*memdb.IndexSchema{
+			// The name in combination with the namespace forms a unique
+			// identifier for a network policy.
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Name",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// eventSinksTableSchema returns the MemDB schema for event sinks.
+func eventSinksTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableEventSinks,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
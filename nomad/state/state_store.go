@@ -864,7 +864,7 @@ func (s *StateStore) deleteAllocsForJobTxn(txn Txn, index uint64, namespace, job
 // UpsertScalingEvent is used to insert a new scaling event.
 // Only the most recent JobTrackedScalingEvents will be kept.
 func (s *StateStore) UpsertScalingEvent(index uint64, req *structs.ScalingEventRequest) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.ScalingEventRegisterRequestType, index)
 	defer txn.Abort()
 
 	// Get the existing events
@@ -2520,7 +2520,7 @@ func (s *StateStore) JobSummaryByPrefix(ws memdb.WatchSet, namespace, id string)
 
 // UpsertCSIVolume inserts a volume in the state store.
 func (s *StateStore) UpsertCSIVolume(index uint64, volumes []*structs.CSIVolume) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.CSIVolumeRegisterRequestType, index)
 	defer txn.Abort()
 
 	for _, v := range volumes {
@@ -2742,7 +2742,7 @@ func (s *StateStore) csiVolumesByNamespaceImpl(txn *txn, ws memdb.WatchSet, name
 
 // CSIVolumeClaim updates the volume's claim count and allocation list
 func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *structs.CSIVolumeClaim) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.CSIVolumeClaimRequestType, index)
 	defer txn.Abort()
 
 	row, err := txn.First("csi_volumes", "id", namespace, id)
@@ -2818,7 +2818,7 @@ func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *s
 
 // CSIVolumeDeregister removes the volume from the server
 func (s *StateStore) CSIVolumeDeregister(index uint64, namespace string, ids []string, force bool) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.CSIVolumeDeregisterRequestType, index)
 	defer txn.Abort()
 
 	for _, id := range ids {
@@ -3150,7 +3150,7 @@ func (s *StateStore) UpsertCSIPlugin(index uint64, plug *structs.CSIPlugin) erro
 
 // DeleteCSIPlugin deletes the plugin if it's not in use.
 func (s *StateStore) DeleteCSIPlugin(index uint64, id string) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.CSIPluginDeleteRequestType, index)
 	defer txn.Abort()
 
 	plug, err := s.CSIPluginByIDTxn(txn, nil, id)
@@ -6385,6 +6385,25 @@ func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool, sort S
 	return iter, nil
 }
 
+// ACLTokensByAllocID returns the ACL tokens derived for the tasks of an
+// allocation.
+func (s *StateStore) ACLTokensByAllocID(ws memdb.WatchSet, allocID string) ([]*structs.ACLToken, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("acl_token", "alloc_id", allocID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+
+	var out []*structs.ACLToken
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.ACLToken))
+	}
+	return out, nil
+}
+
 // CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
 func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
 	txn := s.db.ReadTxn()
@@ -6872,7 +6891,7 @@ func (s *StateStore) NamespaceNames() ([]string, error) {
 
 // UpsertNamespaces is used to register or update a set of namespaces.
 func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.NamespaceUpsertRequestType, index)
 	defer txn.Abort()
 
 	for _, ns := range namespaces {
@@ -6941,7 +6960,7 @@ func (s *StateStore) upsertNamespaceImpl(index uint64, txn *txn, namespace *stru
 
 // DeleteNamespaces is used to remove a set of namespaces
 func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.NamespaceDeleteRequestType, index)
 	defer txn.Abort()
 
 	for _, name := range names {
@@ -7000,6 +7019,16 @@ func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
 				"All variables in namespace must be deleted before it can be deleted", name)
 		}
 
+		npIter, err := s.networkPoliciesByNamespaceTxn(txn, name)
+		if err != nil {
+			return err
+		}
+		if rawPolicy := npIter.Next(); rawPolicy != nil {
+			policy := rawPolicy.(*structs.NetworkPolicy)
+			return fmt.Errorf("namespace %q contains at least one network policy %q. "+
+				"All network policies in namespace must be deleted before it can be deleted", name, policy.Name)
+		}
+
 		// Delete the namespace
 		if err := txn.Delete(TableNamespaces, existing); err != nil {
 			return fmt.Errorf("namespace deletion failed: %v", err)
@@ -7325,7 +7354,7 @@ func getPreemptedAllocDesiredDescription(preemptedByAllocID string) string {
 
 // UpsertRootKeyMeta saves root key meta or updates it in-place.
 func (s *StateStore) UpsertRootKeyMeta(index uint64, rootKeyMeta *structs.RootKeyMeta, rekey bool) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.RootKeyMetaUpsertRequestType, index)
 	defer txn.Abort()
 
 	// get any existing key for updating
@@ -7407,7 +7436,7 @@ func (s *StateStore) UpsertRootKeyMeta(index uint64, rootKeyMeta *structs.RootKe
 // DeleteRootKeyMeta deletes a single root key, or returns an error if
 // it doesn't exist.
 func (s *StateStore) DeleteRootKeyMeta(index uint64, keyID string) error {
-	txn := s.db.WriteTxn(index)
+	txn := s.db.WriteTxnMsgT(structs.RootKeyMetaDeleteRequestType, index)
 	defer txn.Abort()
 
 	// find the old key
@@ -7483,7 +7512,8 @@ func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMe
 }
 
 // IsRootKeyMetaInUse determines whether a key has been used to sign a workload
-// identity for a live allocation or encrypt any variables
+// identity for a live allocation or encrypt any variables or their previous
+// versions
 func (s *StateStore) IsRootKeyMetaInUse(keyID string) (bool, error) {
 	txn := s.db.ReadTxn()
 
@@ -7505,5 +7535,14 @@ func (s *StateStore) IsRootKeyMetaInUse(keyID string) (bool, error) {
 		return true, nil
 	}
 
+	iter, err = txn.Get(TableVariableVersions, indexKeyID, keyID)
+	if err != nil {
+		return false, err
+	}
+	version := iter.Next()
+	if version != nil {
+		return true, nil
+	}
+
 	return false, nil
 }
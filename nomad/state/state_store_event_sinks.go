@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventSinks returns an iterator over all event sinks.
+func (s *StateStore) EventSinks(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableEventSinks, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("event sinks lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// EventSinkByID returns the event sink with the given ID or nil if there is
+// no match.
+func (s *StateStore) EventSinkByID(ws memdb.WatchSet, id string) (*structs.EventSink, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableEventSinks, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("event sink lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.EventSink), nil
+}
+
+// UpsertEventSinks inserts or updates the given set of event sinks. New sinks
+// receive the events published after they are created. The delivery progress
+// of existing sinks is kept unless the sink's topics or namespace change, in
+// which case delivery starts again at the index of the update.
+func (s *StateStore) UpsertEventSinks(msgType structs.MessageType, index uint64, sinks []*structs.EventSink) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, sink := range sinks {
+		if sink == nil {
+			continue
+		}
+
+		existing, err := txn.First(TableEventSinks, indexID, sink.ID)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %w", err)
+		}
+
+		if existing != nil {
+			exist := existing.(*structs.EventSink)
+			sink.CreateIndex = exist.CreateIndex
+			sink.ModifyIndex = index
+			sink.LatestIndex = exist.LatestIndex
+			sink.Gaps = exist.Gaps
+			sink.LastGap = exist.LastGap
+			if sink.Namespace != exist.Namespace || !maps.EqualFunc(sink.Topics, exist.Topics, slices.Equal[[]string]) {
+				sink.LatestIndex = index
+			}
+		} else {
+			sink.CreateIndex = index
+			sink.ModifyIndex = index
+			sink.LatestIndex = index
+			sink.Gaps = 0
+			sink.LastGap = nil
+		}
+
+		if err := txn.Insert(TableEventSinks, sink); err != nil {
+			return fmt.Errorf("event sink insert failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// DeleteEventSinks removes the event sinks with the given IDs.
+func (s *StateStore) DeleteEventSinks(msgType structs.MessageType, index uint64, ids []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First(TableEventSinks, indexID, id)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %w", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("event sink %s not found", id)
+		}
+
+		if err := txn.Delete(TableEventSinks, existing); err != nil {
+			return fmt.Errorf("event sink deletion failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// UpdateEventSinksProgress records the index of the last event set delivered
+// to each sink and the gaps detected in delivery. Progress never moves
+// backwards. Progress for sinks that no longer exist or that were modified
+// since delivery started is ignored, since the leader may still be delivering
+// to the previous version of a sink.
+// The ModifyIndex of the sinks is not changed.
+func (s *StateStore) UpdateEventSinksProgress(msgType structs.MessageType, index uint64, progress []*structs.EventSinkProgress) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, p := range progress {
+		existing, err := txn.First(TableEventSinks, indexID, p.ID)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %w", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		exist := existing.(*structs.EventSink)
+		if exist.ModifyIndex != p.ModifyIndex || (p.LatestIndex <= exist.LatestIndex && p.Gaps == 0) {
+			continue
+		}
+
+		sink := exist.Copy()
+		sink.LatestIndex = max(sink.LatestIndex, p.LatestIndex)
+		if p.Gaps > 0 {
+			sink.Gaps += p.Gaps
+			sink.LastGap = p.LastGap.Copy()
+		}
+		if err := txn.Insert(TableEventSinks, sink); err != nil {
+			return fmt.Errorf("event sink insert failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
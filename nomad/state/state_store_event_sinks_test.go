@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	sink1 := mock.EventSink()
+	sink2 := mock.EventSink()
+	sinks := []*structs.EventSink{sink1, sink2}
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1000, sinks))
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.EventSinks(ws)
+	must.NoError(t, err)
+
+	var got []*structs.EventSink
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		got = append(got, raw.(*structs.EventSink))
+	}
+	must.SliceContainsAll(t, sinks, got)
+
+	// New sinks start delivering at the index they were created.
+	out, err := state.EventSinkByID(ws, sink1.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1000, out.CreateIndex)
+	must.Eq(t, 1000, out.ModifyIndex)
+	must.Eq(t, 1000, out.LatestIndex)
+	must.False(t, watchFired(ws))
+
+	// Progress is recorded for the current version of the sink and never
+	// moves backwards.
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1001,
+		[]*structs.EventSinkProgress{
+			{ID: sink1.ID, ModifyIndex: 1000, LatestIndex: 1500},
+			{ID: sink2.ID, ModifyIndex: 999, LatestIndex: 1500},
+			{ID: "unknown", ModifyIndex: 1000, LatestIndex: 1500},
+		}))
+	must.True(t, watchFired(ws))
+
+	out, err = state.EventSinkByID(nil, sink1.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1500, out.LatestIndex)
+	must.Eq(t, 1000, out.ModifyIndex)
+
+	out, err = state.EventSinkByID(nil, sink2.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1000, out.LatestIndex)
+
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1002,
+		[]*structs.EventSinkProgress{{ID: sink1.ID, ModifyIndex: 1000, LatestIndex: 1200}}))
+	out, err = state.EventSinkByID(nil, sink1.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1500, out.LatestIndex)
+
+	// Updating a sink keeps its progress unless the events it receives
+	// change.
+	update := sink1.Copy()
+	update.Address = "http://127.0.0.1:9090/events"
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1003, []*structs.EventSink{update}))
+
+	out, err = state.EventSinkByID(nil, sink1.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1000, out.CreateIndex)
+	must.Eq(t, 1003, out.ModifyIndex)
+	must.Eq(t, 1500, out.LatestIndex)
+
+	update = update.Copy()
+	update.Topics = map[structs.Topic][]string{structs.TopicNode: {"*"}}
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1004, []*structs.EventSink{update}))
+
+	out, err = state.EventSinkByID(nil, sink1.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1004, out.LatestIndex)
+
+	// Delete the sinks.
+	must.NoError(t, state.DeleteEventSinks(structs.MsgTypeTestSetup, 1005, []string{sink1.ID}))
+	out, err = state.EventSinkByID(nil, sink1.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	err = state.DeleteEventSinks(structs.MsgTypeTestSetup, 1006, []string{sink1.ID, sink2.ID})
+	must.ErrorContains(t, err, "not found")
+
+	// The failed delete didn't remove the other sink.
+	out, err = state.EventSinkByID(nil, sink2.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	index, err := state.Index(TableEventSinks)
+	must.NoError(t, err)
+	must.Eq(t, 1005, index)
+}
+
+func TestStateStore_UpdateEventSinksProgress_Gaps(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	sink := mock.EventSink()
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1000, []*structs.EventSink{sink}))
+
+	// Gaps are recorded even if the sink made no progress.
+	gap1 := &structs.EventSinkGap{FromIndex: 1000, ToIndex: 1200, Time: time.Now().UTC()}
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1001,
+		[]*structs.EventSinkProgress{{ID: sink.ID, ModifyIndex: 1000, LatestIndex: 1000, Gaps: 1, LastGap: gap1}}))
+
+	out, err := state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1000, out.LatestIndex)
+	must.Eq(t, 1, out.Gaps)
+	must.Eq(t, gap1, out.LastGap)
+
+	gap2 := &structs.EventSinkGap{FromIndex: 1300, ToIndex: 1400, Time: time.Now().UTC()}
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1002,
+		[]*structs.EventSinkProgress{{ID: sink.ID, ModifyIndex: 1000, LatestIndex: 1500, Gaps: 2, LastGap: gap2}}))
+
+	out, err = state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1500, out.LatestIndex)
+	must.Eq(t, 3, out.Gaps)
+	must.Eq(t, gap2, out.LastGap)
+
+	// Updating the sink keeps its gaps, and the gaps can't be set by the
+	// update.
+	update := sink.Copy()
+	update.Gaps = 0
+	update.LastGap = nil
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1003, []*structs.EventSink{update}))
+
+	out, err = state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, 3, out.Gaps)
+	must.Eq(t, gap2, out.LastGap)
+}
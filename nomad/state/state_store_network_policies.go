@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NetworkPolicies returns an iterator over all network policies in all
+// namespaces.
+func (s *StateStore) NetworkPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNetworkPolicies, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("network policies lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NetworkPoliciesByNamespace returns an iterator over all network policies in
+// the given namespace whose name matches the prefix.
+func (s *StateStore) NetworkPoliciesByNamespace(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNetworkPolicies, indexID+"_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("network policies lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NetworkPolicyByName returns the network policy in the namespace that
+// matches the given name or nil if there is no match.
+func (s *StateStore) NetworkPolicyByName(ws memdb.WatchSet, namespace, name string) (*structs.NetworkPolicy, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableNetworkPolicies, indexID, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("network policy lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.NetworkPolicy), nil
+}
+
+// UpsertNetworkPolicies inserts or updates the given set of network policies.
+func (s *StateStore) UpsertNetworkPolicies(msgType structs.MessageType, index uint64, policies []*structs.NetworkPolicy) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, policy := range policies {
+		if err := s.upsertNetworkPolicyTxn(txn, index, policy); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNetworkPolicies, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+func (s *StateStore) upsertNetworkPolicyTxn(txn *txn, index uint64, policy *structs.NetworkPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	ns, err := s.namespaceByNameImpl(nil, txn, policy.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil {
+		return fmt.Errorf("network policy %q is in nonexistent namespace %q", policy.Name, policy.Namespace)
+	}
+
+	existing, err := txn.First(TableNetworkPolicies, indexID, policy.Namespace, policy.Name)
+	if err != nil {
+		return fmt.Errorf("network policy lookup failed: %w", err)
+	}
+
+	if existing != nil {
+		exist := existing.(*structs.NetworkPolicy)
+		policy.CreateIndex = exist.CreateIndex
+		policy.ModifyIndex = index
+	} else {
+		policy.CreateIndex = index
+		policy.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableNetworkPolicies, policy); err != nil {
+		return fmt.Errorf("network policy insert failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteNetworkPolicies removes the named network policies from the
+// namespace.
+func (s *StateStore) DeleteNetworkPolicies(msgType structs.MessageType, index uint64, namespace string, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableNetworkPolicies, indexID, namespace, name)
+		if err != nil {
+			return fmt.Errorf("network policy lookup failed: %w", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("network policy %s not found", name)
+		}
+
+		if err := txn.Delete(TableNetworkPolicies, existing); err != nil {
+			return fmt.Errorf("network policy deletion failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNetworkPolicies, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// networkPoliciesByNamespaceTxn returns an iterator over all network policies
+// in the given namespace.
+func (s *StateStore) networkPoliciesByNamespaceTxn(txn *txn, namespace string) (memdb.ResultIterator, error) {
+	return txn.Get(TableNetworkPolicies, indexID+"_prefix", namespace, "")
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_NetworkPolicies(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	must.NoError(t, state.UpsertNamespaces(999, []*structs.Namespace{{Name: "prod"}}))
+
+	// Create test network policies in two namespaces.
+	policy1 := mock.NetworkPolicy()
+	policy2 := mock.NetworkPolicy()
+	policy3 := mock.NetworkPolicy()
+	policy3.Namespace = "prod"
+
+	policies := []*structs.NetworkPolicy{policy1, policy2, policy3}
+	must.NoError(t, state.UpsertNetworkPolicies(structs.MsgTypeTestSetup, 1000, policies))
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.NetworkPolicies(ws)
+	must.NoError(t, err)
+
+	var got []*structs.NetworkPolicy
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		got = append(got, raw.(*structs.NetworkPolicy))
+	}
+	must.SliceContainsAll(t, policies, got)
+
+	iter, err = state.NetworkPoliciesByNamespace(ws, "prod", "")
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, policy3.Name, raw.(*structs.NetworkPolicy).Name)
+	must.Nil(t, iter.Next())
+
+	// Lookups are scoped to the namespace.
+	out, err := state.NetworkPolicyByName(ws, structs.DefaultNamespace, policy3.Name)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	out, err = state.NetworkPolicyByName(ws, "prod", policy3.Name)
+	must.NoError(t, err)
+	must.Eq(t, policy3, out)
+	must.Eq(t, 1000, out.CreateIndex)
+	must.Eq(t, 1000, out.ModifyIndex)
+	must.False(t, watchFired(ws))
+
+	// Updating a policy keeps the create index and fires the watch.
+	updated := policy3.Copy()
+	updated.Description = "updated"
+	must.NoError(t, state.UpsertNetworkPolicies(structs.MsgTypeTestSetup, 1001,
+		[]*structs.NetworkPolicy{updated}))
+	must.True(t, watchFired(ws))
+
+	out, err = state.NetworkPolicyByName(nil, "prod", policy3.Name)
+	must.NoError(t, err)
+	must.Eq(t, "updated", out.Description)
+	must.Eq(t, 1000, out.CreateIndex)
+	must.Eq(t, 1001, out.ModifyIndex)
+
+	index, err := state.Index(TableNetworkPolicies)
+	must.NoError(t, err)
+	must.Eq(t, 1001, index)
+
+	// Policies can't be written to nonexistent namespaces.
+	orphan := mock.NetworkPolicy()
+	orphan.Namespace = "nonexistent"
+	err = state.UpsertNetworkPolicies(structs.MsgTypeTestSetup, 1002,
+		[]*structs.NetworkPolicy{orphan})
+	must.ErrorContains(t, err, "nonexistent namespace")
+
+	// Namespaces with network policies can't be deleted.
+	err = state.DeleteNamespaces(1003, []string{"prod"})
+	must.ErrorContains(t, err, "contains at least one network policy")
+
+	must.NoError(t, state.DeleteNetworkPolicies(structs.MsgTypeTestSetup, 1004,
+		"prod", []string{policy3.Name}))
+	out, err = state.NetworkPolicyByName(nil, "prod", policy3.Name)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	// Deleting a missing policy fails the whole transaction.
+	err = state.DeleteNetworkPolicies(structs.MsgTypeTestSetup, 1005,
+		structs.DefaultNamespace, []string{policy1.Name, policy3.Name})
+	must.ErrorContains(t, err, "not found")
+
+	out, err = state.NetworkPolicyByName(nil, structs.DefaultNamespace, policy1.Name)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	must.NoError(t, state.DeleteNamespaces(1006, []string{"prod"}))
+}
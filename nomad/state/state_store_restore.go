@@ -240,6 +240,15 @@ func (r *StateRestore) VariablesQuotaRestore(quota *structs.VariablesQuota) erro
 	return nil
 }
 
+// VariableVersionRestore is used to restore a single previous version of a
+// variable into the variable_versions table.
+func (r *StateRestore) VariableVersionRestore(version *structs.VariableVersion) error {
+	if err := r.txn.Insert(TableVariableVersions, version); err != nil {
+		return fmt.Errorf("variable version insert failed: %v", err)
+	}
+	return nil
+}
+
 // RootKeyMetaQuotaRestore is used to restore a single root key meta into the
 // root_key_meta table.
 func (r *StateRestore) RootKeyMetaRestore(quota *structs.RootKeyMeta) error {
@@ -284,3 +293,21 @@ func (r *StateRestore) JobSubmissionRestore(jobSubmission *structs.JobSubmission
 	}
 	return nil
 }
+
+// NetworkPolicyRestore is used to restore a single network policy into the
+// network_policies table.
+func (r *StateRestore) NetworkPolicyRestore(policy *structs.NetworkPolicy) error {
+	if err := r.txn.Insert(TableNetworkPolicies, policy); err != nil {
+		return fmt.Errorf("network policy insert failed: %v", err)
+	}
+	return nil
+}
+
+// EventSinkRestore is used to restore a single event sink into the
+// event_sinks table.
+func (r *StateRestore) EventSinkRestore(sink *structs.EventSink) error {
+	if err := r.txn.Insert(TableEventSinks, sink); err != nil {
+		return fmt.Errorf("event sink insert failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariableVersions queries all the previous versions of variables and is used
+// only for snapshot/restore, key rotation and garbage collection.
+func (s *StateStore) VariableVersions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariableVersionsByKeyID returns an iterator that contains all previous
+// versions of variables that were encrypted with a particular key.
+func (s *StateStore) GetVariableVersionsByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariableVersions returns the previous versions of the variable at a
+// given namespace and path, with the highest version first.
+func (s *StateStore) GetVariableVersions(
+	ws memdb.WatchSet, namespace, path string) ([]*structs.VariableVersion, error) {
+	txn := s.db.ReadTxn()
+	return s.variableVersionsTxn(txn, ws, namespace, path)
+}
+
+// variableVersionsTxn is the underlying implementation for retrieving the
+// previous versions of a variable within an existing transaction.
+func (s *StateStore) variableVersionsTxn(txn ReadTxn, ws memdb.WatchSet,
+	namespace, path string) ([]*structs.VariableVersion, error) {
+
+	iter, err := txn.Get(TableVariableVersions, indexID+"_prefix", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var all []*structs.VariableVersion
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// Ensure the path is an exact match
+		version := raw.(*structs.VariableVersion)
+		if version.Path != path {
+			continue
+		}
+		all = append(all, version)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Version > all[j].Version
+	})
+
+	return all, nil
+}
+
+// GetVariableVersion returns a single previous version of the variable at a
+// given namespace and path.
+func (s *StateStore) GetVariableVersion(ws memdb.WatchSet,
+	namespace, path string, version uint64) (*structs.VariableVersion, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableVariableVersions, indexID, namespace, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*structs.VariableVersion), nil
+}
+
+// variableMaxVersionsTxn returns the number of previous versions of each
+// variable kept in the namespace.
+func (s *StateStore) variableMaxVersionsTxn(txn ReadTxn, namespace string) (int, error) {
+	raw, err := txn.First(TableNamespaces, indexID, namespace)
+	if err != nil {
+		return 0, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	ns, _ := raw.(*structs.Namespace)
+	return ns.VariableMaxVersions(), nil
+}
+
+// archiveVariableTxn adds a variable that is being replaced or deleted to its
+// history and deletes the oldest versions beyond the namespace's limit.
+func (s *StateStore) archiveVariableTxn(tx WriteTxn, idx uint64, sv *structs.VariableEncrypted) error {
+	maxVersions, err := s.variableMaxVersionsTxn(tx, sv.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if maxVersions > 0 {
+		version := &structs.VariableVersion{
+			VariableEncrypted: sv.Copy(),
+			ArchiveIndex:      idx,
+		}
+
+		// Variables written before versioning was introduced are the first
+		// version of their variable.
+		version.Version = max(version.Version, 1)
+
+		// The lock is not part of the contents of the variable, and only the
+		// current version of a variable expires.
+		version.Lock = nil
+		version.ExpireTime = 0
+
+		if err := tx.Insert(TableVariableVersions, version); err != nil {
+			return fmt.Errorf("failed inserting variable version: %v", err)
+		}
+	}
+
+	versions, err := s.variableVersionsTxn(tx, nil, sv.Namespace, sv.Path)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions[min(maxVersions, len(versions)):] {
+		if err := tx.Delete(TableVariableVersions, version); err != nil {
+			return fmt.Errorf("failed deleting variable version: %v", err)
+		}
+	}
+
+	if err := tx.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+		return fmt.Errorf("failed updating variable versions index: %v", err)
+	}
+	return nil
+}
+
+// VarRekey is used to replace the encrypted data of a variable, or of one of
+// its previous versions, with the same data encrypted by another key. It
+// doesn't create a new version of the variable. The ModifyIndex in the
+// provided entry is used to detect concurrent writes.
+func (s *StateStore) VarRekey(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
+	defer tx.Abort()
+
+	sv := req.Var
+	raw, err := tx.First(TableVariables, indexID, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	existing, _ := raw.(*structs.VariableEncrypted)
+
+	if existing != nil && existing.Version == sv.Version {
+		if existing.ModifyIndex != sv.ModifyIndex {
+			return req.ConflictResponse(idx, existing)
+		}
+
+		// The size of the data is unchanged by rekeying, so there's no quota
+		// to update.
+		updated := existing.Copy()
+		updated.VariableData = sv.VariableData.Copy()
+		if err := s.updateVarsAndIndexTxn(tx, idx, &updated); err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+	} else {
+		raw, err := tx.First(TableVariableVersions, indexID, sv.Namespace, sv.Path, sv.Version)
+		if err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed variable version lookup: %s", err))
+		}
+
+		// The version may have been garbage collected since it was read, in
+		// which case there's nothing left to rekey.
+		version, ok := raw.(*structs.VariableVersion)
+		if !ok {
+			return req.SuccessResponse(idx, nil)
+		}
+		if version.ModifyIndex != sv.ModifyIndex {
+			return req.ConflictResponse(idx, &version.VariableEncrypted)
+		}
+
+		updated := version.Copy()
+		updated.VariableData = sv.VariableData.Copy()
+		if err := tx.Insert(TableVariableVersions, updated); err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable version: %s", err))
+		}
+		if err := tx.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed updating variable versions index: %s", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return req.SuccessResponse(idx, nil)
+}
+
+// DeleteVariableVersions is used to delete previous versions of variables,
+// identified by their namespace, path and version. Versions that don't exist
+// are ignored.
+func (s *StateStore) DeleteVariableVersions(msgType structs.MessageType, idx uint64,
+	versions []*structs.VariableMetadata) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, idx)
+	defer txn.Abort()
+
+	for _, version := range versions {
+		raw, err := txn.First(TableVariableVersions, indexID,
+			version.Namespace, version.Path, version.Version)
+		if err != nil {
+			return fmt.Errorf("variable version lookup failed: %v", err)
+		}
+		if raw == nil {
+			continue
+		}
+		if err := txn.Delete(TableVariableVersions, raw); err != nil {
+			return fmt.Errorf("variable version deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
@@ -0,0 +1,223 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestStateStore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	ws := memdb.NewWatchSet()
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		MaxVersions: pointer.Of(2),
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	sv := mock.VariableEncrypted()
+	sv.Namespace = ns.Name
+	sv.Path = "foo/bar"
+
+	// Write the variable with different data a few times. The data differs
+	// each time, so every write creates a new version.
+	for i := uint64(0); i < 4; i++ {
+		update := sv.Copy()
+		update.Data = []byte(uuid.Generate())
+		resp := testState.VarSet(20+i, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: &update,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	current, err := testState.GetVariable(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, 4, current.Version)
+
+	// Only the configured number of previous versions is kept
+	versions, err := testState.GetVariableVersions(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+	must.Eq(t, 3, versions[0].Version)
+	must.Eq(t, 23, versions[0].ArchiveIndex)
+	must.Eq(t, 2, versions[1].Version)
+
+	// Writing identical data doesn't create a new version
+	unchanged := current.Copy()
+	resp := testState.VarSet(30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &unchanged,
+	})
+	must.NoError(t, resp.Error)
+	versions, err = testState.GetVariableVersions(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+
+	// Deleting the variable archives it, and writing it again continues from
+	// the latest version in its history
+	resp = testState.VarDelete(31, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDelete,
+		Var: current,
+	})
+	must.NoError(t, resp.Error)
+
+	version, err := testState.GetVariableVersion(ws, ns.Name, sv.Path, 4)
+	must.NoError(t, err)
+	must.NotNil(t, version)
+	must.Eq(t, current.Data, version.Data)
+	must.Eq(t, 31, version.ArchiveIndex)
+
+	recreate := sv.Copy()
+	resp = testState.VarSet(32, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: &recreate,
+	})
+	must.NoError(t, resp.Error)
+	current, err = testState.GetVariable(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, 5, current.Version)
+
+	// Deleting versions by namespace, path and version ignores missing ones
+	must.NoError(t, testState.DeleteVariableVersions(
+		structs.VariableVersionsDeleteRequestType, 33,
+		[]*structs.VariableMetadata{
+			{Namespace: ns.Name, Path: sv.Path, Version: 4},
+			{Namespace: ns.Name, Path: sv.Path, Version: 1},
+		}))
+	versions, err = testState.GetVariableVersions(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+	must.Eq(t, 3, versions[0].Version)
+
+	index, err := testState.Index(TableVariableVersions)
+	must.NoError(t, err)
+	must.Eq(t, 33, index)
+}
+
+func TestStateStore_VariableVersions_Disabled(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	ws := memdb.NewWatchSet()
+
+	ns := mock.Namespace()
+	ns.VariablesConfiguration = &structs.NamespaceVariablesConfiguration{
+		MaxVersions: pointer.Of(0),
+	}
+	must.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	sv := mock.VariableEncrypted()
+	sv.Namespace = ns.Name
+	for i := uint64(0); i < 3; i++ {
+		update := sv.Copy()
+		update.Data = []byte(uuid.Generate())
+		resp := testState.VarSet(20+i, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: &update,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	versions, err := testState.GetVariableVersions(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 0, versions)
+
+	// The version number still increases so that enabling history later
+	// doesn't reuse version numbers
+	current, err := testState.GetVariable(ws, ns.Name, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, 3, current.Version)
+}
+
+func TestStateStore_VarRekey(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	ws := memdb.NewWatchSet()
+
+	sv := mock.VariableEncrypted()
+	for i := uint64(0); i < 2; i++ {
+		update := sv.Copy()
+		update.Data = []byte(uuid.Generate())
+		resp := testState.VarSet(20+i, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: &update,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	current, err := testState.GetVariable(ws, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	previous, err := testState.GetVariableVersion(ws, sv.Namespace, sv.Path, 1)
+	must.NoError(t, err)
+	must.NotNil(t, previous)
+
+	newKeyID := uuid.Generate()
+
+	// Rekeying the current version replaces only its data
+	rekey := current.Copy()
+	rekey.KeyID = newKeyID
+	resp := testState.VarRekey(30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpRekey,
+		Var: &rekey,
+	})
+	must.NoError(t, resp.Error)
+	must.Eq(t, structs.VarOpResultOk, resp.Result)
+
+	got, err := testState.GetVariable(ws, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, newKeyID, got.KeyID)
+	must.Eq(t, current.Version, got.Version)
+	versions, err := testState.GetVariableVersions(ws, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+
+	// Rekeying a previous version replaces its data in the history
+	rekey = previous.VariableEncrypted.Copy()
+	rekey.KeyID = newKeyID
+	resp = testState.VarRekey(31, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpRekey,
+		Var: &rekey,
+	})
+	must.NoError(t, resp.Error)
+	must.Eq(t, structs.VarOpResultOk, resp.Result)
+
+	gotVersion, err := testState.GetVariableVersion(ws, sv.Namespace, sv.Path, 1)
+	must.NoError(t, err)
+	must.Eq(t, newKeyID, gotVersion.KeyID)
+
+	iter, err := testState.GetVariableVersionsByKeyID(ws, newKeyID)
+	must.NoError(t, err)
+	must.NotNil(t, iter.Next())
+
+	// A stale modify index is a conflict
+	rekey = previous.VariableEncrypted.Copy()
+	rekey.ModifyIndex = 1
+	resp = testState.VarRekey(32, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpRekey,
+		Var: &rekey,
+	})
+	must.NoError(t, resp.Error)
+	must.Eq(t, structs.VarOpResultConflict, resp.Result)
+
+	// A version that no longer exists is ignored
+	rekey = previous.VariableEncrypted.Copy()
+	rekey.Version = 100
+	resp = testState.VarRekey(33, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpRekey,
+		Var: &rekey,
+	})
+	must.NoError(t, resp.Error)
+	must.Eq(t, structs.VarOpResultOk, resp.Result)
+}
@@ -122,7 +122,7 @@ func (s *StateStore) GetVariable(
 
 // VarSet is used to store a variable object.
 func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
 	defer tx.Abort()
 
 	// Perform the actual set.
@@ -141,7 +141,7 @@ func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *struc
 // variable. The ModifyIndex in the provided entry is used to determine if
 // we should write the entry to the state store or not.
 func (s *StateStore) VarSetCAS(idx uint64, sv *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
 	defer tx.Abort()
 
 	resp := s.varSetCASTxn(tx, idx, sv)
@@ -228,6 +228,7 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 
 		sv.CreateIndex = existing.CreateIndex
 		sv.CreateTime = existing.CreateTime
+		sv.Version = existing.Version
 
 		if existing.Equal(*sv) {
 			// Skip further writing in the state store if the entry is not actually
@@ -238,11 +239,27 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 			return req.SuccessResponse(idx, nil)
 		}
 		sv.ModifyIndex = idx
+		sv.Version = max(existing.Version, 1) + 1
 		quotaChange = int64(len(sv.Data) - len(existing.Data))
+
+		if err := s.archiveVariableTxn(tx, idx, existing); err != nil {
+			return req.ErrorResponse(idx, err)
+		}
 	} else {
 		sv.CreateIndex = idx
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data))
+
+		// A variable that was deleted keeps its history, so continue its
+		// version numbers when it is written again.
+		versions, err := s.variableVersionsTxn(tx, nil, sv.Namespace, sv.Path)
+		if err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+		sv.Version = 1
+		if len(versions) > 0 {
+			sv.Version = versions[0].Version + 1
+		}
 	}
 
 	if err := tx.Insert(TableVariables, sv); err != nil {
@@ -300,7 +317,7 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 // VarDelete is used to delete a single variable in the
 // the state store.
 func (s *StateStore) VarDelete(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
 	defer tx.Abort()
 
 	// Perform the actual delete
@@ -322,7 +339,7 @@ func (s *StateStore) VarDelete(idx uint64, req *structs.VarApplyStateRequest) *s
 // last observed index for the given variable, then the call is a noop,
 // otherwise a normal delete is invoked.
 func (s *StateStore) VarDeleteCAS(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
 	defer tx.Abort()
 
 	resp := s.svDeleteCASTxn(tx, idx, req)
@@ -413,6 +430,11 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 		return req.ConflictResponse(idx, zeroVal)
 	}
 
+	// Keep the deleted variable in its history so that it can be restored.
+	if err := s.archiveVariableTxn(tx, idx, sv); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+
 	existingQuota, err := tx.First(TableVariablesQuotas, indexID, req.Var.Namespace)
 	if err != nil {
 		return req.ErrorResponse(idx, fmt.Errorf("variable quota lookup failed: %v", err))
@@ -486,7 +508,7 @@ func (s *StateStore) VariablesQuotaByNamespace(ws memdb.WatchSet, namespace stri
 // IMPORTANT: this method overwrites the variable, data included.
 func (s *StateStore) VarLockAcquire(idx uint64,
 	req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
 	defer tx.Abort()
 
 	// Try to fetch the variable.
@@ -523,7 +545,7 @@ func (s *StateStore) VarLockAcquire(idx uint64,
 
 func (s *StateStore) VarLockRelease(idx uint64,
 	req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
-	tx := s.db.WriteTxn(idx)
+	tx := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, idx)
 	defer tx.Abort()
 
 	// Look up the entry in the state store.
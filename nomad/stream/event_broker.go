@@ -89,6 +89,13 @@ func (e *EventBroker) Len() int {
 	return e.eventBuf.Len()
 }
 
+// DroppedIndex returns the index of the most recent set of events dropped from
+// the event buffer. Subscriptions requesting an index lower than or equal to
+// it may not receive all the events published since that index.
+func (e *EventBroker) DroppedIndex() uint64 {
+	return e.eventBuf.Dropped()
+}
+
 // Publish events to all subscribers of the event Topic.
 func (e *EventBroker) Publish(events *structs.Events) {
 	if len(events.Events) == 0 {
@@ -124,6 +131,7 @@ func (e *EventBroker) SubscribeWithACLCheck(req *SubscribeRequest) (*Subscriptio
 	if err != nil {
 		return nil, nil, err
 	}
+	sub.setACL(aclObj)
 	return sub, expiryTime, nil
 }
 
@@ -220,7 +228,11 @@ func (e *EventBroker) handleACLUpdates(ctx context.Context) {
 				}
 
 				e.subscriptions.closeSubscriptionFunc(tokenSecretID, func(sub *Subscription) bool {
-					return !aclAllowsSubscription(aclObj, sub.req)
+					if !aclAllowsSubscription(aclObj, sub.req) {
+						return true
+					}
+					sub.setACL(aclObj)
+					return false
 				})
 
 			case *structs.ACLPolicyEvent, *structs.ACLRoleStreamEvent:
@@ -268,7 +280,11 @@ func (e *EventBroker) checkSubscriptionsAgainstACLChange() {
 		}
 
 		e.subscriptions.closeSubscriptionFunc(tokenSecretID, func(sub *Subscription) bool {
-			return !aclAllowsSubscription(aclObj, sub.req)
+			if !aclAllowsSubscription(aclObj, sub.req) {
+				return true
+			}
+			sub.setACL(aclObj)
+			return false
 		})
 	}
 }
@@ -360,7 +376,9 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 			structs.TopicAllocation,
 			structs.TopicJob,
 			structs.TopicService:
-			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadJob); !ok {
+			// Events of jobs the token can't read are filtered by
+			// aclAllowsEvent.
+			if ok := aclObj.AllowNsOpAnyJob(subReq.Namespace, acl.NamespaceCapabilityReadJob); !ok {
 				return false
 			}
 		case structs.TopicNode:
@@ -373,6 +391,41 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 			if ok := aclObj.IsManagement(); !ok {
 				return false
 			}
+		case structs.TopicVariable:
+			// Events can't be filtered by the variable paths the token has
+			// access to, so require the list capability on every path.
+			if ok := aclObj.AllowVariableOperation(subReq.Namespace, "*", acl.PolicyList, nil); !ok {
+				return false
+			}
+		case structs.TopicNamespace:
+			if ok := aclObj.AllowNamespace(subReq.Namespace); !ok {
+				return false
+			}
+		case structs.TopicCSIVolume:
+			allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityCSIListVolume,
+				acl.NamespaceCapabilityCSIReadVolume,
+				acl.NamespaceCapabilityCSIMountVolume,
+				acl.NamespaceCapabilityListJobs)
+			if ok := allowVolume(aclObj, subReq.Namespace); !ok {
+				return false
+			}
+		case structs.TopicCSIPlugin:
+			if ok := aclObj.AllowPluginList(); !ok {
+				return false
+			}
+		case structs.TopicScalingPolicy:
+			hasListScalingPolicies := aclObj.AllowNsOpAnyJob(subReq.Namespace, acl.NamespaceCapabilityListScalingPolicies)
+			hasListAndReadJobs := aclObj.AllowNsOpAnyJob(subReq.Namespace, acl.NamespaceCapabilityListJobs) &&
+				aclObj.AllowNsOpAnyJob(subReq.Namespace, acl.NamespaceCapabilityReadJob)
+			if !(hasListScalingPolicies || hasListAndReadJobs) {
+				return false
+			}
+		case structs.TopicScalingEvent:
+			hasReadJob := aclObj.AllowNsOpAnyJob(subReq.Namespace, acl.NamespaceCapabilityReadJob)
+			hasReadJobScaling := aclObj.AllowNsOpAnyJob(subReq.Namespace, acl.NamespaceCapabilityReadJobScaling)
+			if !(hasReadJob || hasReadJobScaling) {
+				return false
+			}
 		default:
 			if ok := aclObj.IsManagement(); !ok {
 				return false
@@ -383,6 +436,37 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 	return true
 }
 
+// aclAllowsEvent returns true if the token is allowed to receive an event of a
+// subscription allowed by aclAllowsSubscription. The namespace capabilities
+// were already checked, but job rules may deny access to some of the jobs of
+// the namespace.
+func aclAllowsEvent(aclObj *acl.ACL, event *structs.Event) bool {
+	switch payload := event.Payload.(type) {
+	case *structs.JobEvent:
+		return aclObj.AllowJobOp(payload.Job.Namespace, payload.Job.ID, acl.NamespaceCapabilityReadJob)
+	case *structs.EvaluationEvent:
+		return aclObj.AllowJobOp(payload.Evaluation.Namespace, payload.Evaluation.JobID, acl.NamespaceCapabilityReadJob)
+	case *structs.AllocationEvent:
+		return aclObj.AllowJobOp(payload.Allocation.Namespace, payload.Allocation.JobID, acl.NamespaceCapabilityReadJob)
+	case *structs.DeploymentEvent:
+		return aclObj.AllowJobOp(payload.Deployment.Namespace, payload.Deployment.JobID, acl.NamespaceCapabilityReadJob)
+	case *structs.ServiceRegistrationStreamEvent:
+		return aclObj.AllowJobOp(payload.Service.Namespace, payload.Service.JobID, acl.NamespaceCapabilityReadJob)
+	case *structs.ScalingPolicyEvent:
+		ns := payload.ScalingPolicy.Target[structs.ScalingTargetNamespace]
+		jobID := payload.ScalingPolicy.Target[structs.ScalingTargetJob]
+		hasListScalingPolicies := aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityListScalingPolicies)
+		hasListAndReadJobs := aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityListJobs) &&
+			aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob)
+		return hasListScalingPolicies || hasListAndReadJobs
+	case *structs.ScalingEventStreamEvent:
+		ns, jobID := payload.ScalingEvents.Namespace, payload.ScalingEvents.JobID
+		return aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJob) ||
+			aclObj.AllowJobOp(ns, jobID, acl.NamespaceCapabilityReadJobScaling)
+	}
+	return true
+}
+
 func (s *Subscription) forceClose() {
 	if atomic.CompareAndSwapUint32(&s.state, subscriptionStateOpen, subscriptionStateClosed) {
 		close(s.forceClosed)
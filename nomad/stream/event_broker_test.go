@@ -1072,6 +1072,171 @@ func TestEventBroker_NodePool_ACL(t *testing.T) {
 
 }
 
+func TestEventBroker_Topics_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	testCases := []struct {
+		name        string
+		topic       structs.Topic
+		rules       string
+		expectedErr string
+	}{
+		{
+			name:  "variable list all paths",
+			topic: structs.TopicVariable,
+			rules: `namespace "default" { variables { path "*" { capabilities = ["list"] } } }`,
+		},
+		{
+			name:        "variable list some paths",
+			topic:       structs.TopicVariable,
+			rules:       `namespace "default" { variables { path "app/*" { capabilities = ["list"] } } }`,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:  "namespace read",
+			topic: structs.TopicNamespace,
+			rules: `namespace "default" { policy = "read" }`,
+		},
+		{
+			name:        "namespace other",
+			topic:       structs.TopicNamespace,
+			rules:       `namespace "other" { policy = "read" }`,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:  "csi volume list",
+			topic: structs.TopicCSIVolume,
+			rules: `namespace "default" { capabilities = ["csi-list-volume"] }`,
+		},
+		{
+			name:        "csi volume without capability",
+			topic:       structs.TopicCSIVolume,
+			rules:       `namespace "default" { capabilities = ["submit-job"] }`,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:  "csi plugin read",
+			topic: structs.TopicCSIPlugin,
+			rules: `plugin { policy = "read" }`,
+		},
+		{
+			name:        "csi plugin without policy",
+			topic:       structs.TopicCSIPlugin,
+			rules:       `namespace "default" { policy = "read" }`,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:  "scaling policy list",
+			topic: structs.TopicScalingPolicy,
+			rules: `namespace "default" { capabilities = ["list-scaling-policies"] }`,
+		},
+		{
+			name:        "scaling policy without capability",
+			topic:       structs.TopicScalingPolicy,
+			rules:       `namespace "default" { capabilities = ["read-job-scaling"] }`,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			name:  "scaling event read",
+			topic: structs.TopicScalingEvent,
+			rules: `namespace "default" { capabilities = ["read-job-scaling"] }`,
+		},
+		{
+			name:        "root key requires management",
+			topic:       structs.TopicRootKey,
+			rules:       `namespace "default" { policy = "write" } operator { policy = "write" }`,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token := &structs.ACLToken{
+				AccessorID: uuid.Generate(),
+				SecretID:   uuid.Generate(),
+				Type:       structs.ACLClientToken,
+				Policies:   []string{"test"},
+			}
+			policy := &structs.ACLPolicy{Name: "test", Rules: tc.rules}
+			tokenProvider := &fakeACLTokenProvider{token: token, policy: policy}
+			aclDelegate := &fakeACLDelegate{tokenProvider: tokenProvider}
+
+			publisher, err := NewEventBroker(ctx, aclDelegate, EventBrokerCfg{})
+			must.NoError(t, err)
+
+			_, _, err = publisher.SubscribeWithACLCheck(&SubscribeRequest{
+				Topics:    map[structs.Topic][]string{tc.topic: {"*"}},
+				Namespace: structs.DefaultNamespace,
+				Token:     token.SecretID,
+			})
+
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEventBroker_JobRules_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	token := &structs.ACLToken{
+		AccessorID: uuid.Generate(),
+		SecretID:   uuid.Generate(),
+		Type:       structs.ACLClientToken,
+		Policies:   []string{"test"},
+	}
+	policy := &structs.ACLPolicy{Name: "test", Rules: `
+namespace "default" {
+  policy = "read"
+  job "secret" { capabilities = ["deny"] }
+}`}
+	tokenProvider := &fakeACLTokenProvider{token: token, policy: policy}
+	aclDelegate := &fakeACLDelegate{tokenProvider: tokenProvider}
+
+	publisher, err := NewEventBroker(ctx, aclDelegate, EventBrokerCfg{})
+	must.NoError(t, err)
+
+	sub, _, err := publisher.SubscribeWithACLCheck(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicAllocation: {"*"}},
+		Namespace: structs.DefaultNamespace,
+		Token:     token.SecretID,
+	})
+	must.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	// The events of the denied job and its dispatched children are filtered
+	// out of the event set.
+	allowed := mock.Alloc()
+	denied := mock.Alloc()
+	denied.JobID = "secret"
+	child := mock.Alloc()
+	child.JobID = "secret/dispatch-1700000000-4b9e10a2"
+	publisher.Publish(&structs.Events{Index: 1, Events: []structs.Event{
+		{Topic: structs.TopicAllocation, Key: denied.ID, Namespace: denied.Namespace,
+			Payload: &structs.AllocationEvent{Allocation: denied}},
+		{Topic: structs.TopicAllocation, Key: allowed.ID, Namespace: allowed.Namespace,
+			Payload: &structs.AllocationEvent{Allocation: allowed}},
+		{Topic: structs.TopicAllocation, Key: child.ID, Namespace: child.Namespace,
+			Payload: &structs.AllocationEvent{Allocation: child}},
+	}})
+
+	nextCtx, nextCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer nextCancel()
+	events, err := sub.Next(nextCtx)
+	must.NoError(t, err)
+	must.Len(t, 1, events.Events)
+	must.Eq(t, allowed.ID, events.Events[0].Key)
+}
+
 func consumeSubscription(ctx context.Context, sub *Subscription) <-chan subNextResult {
 	eventCh := make(chan subNextResult, 1)
 	go func() {
@@ -51,6 +51,10 @@ type eventBuffer struct {
 	head atomic.Value
 	tail atomic.Value
 
+	// dropped is the index of the most recent set of events dropped from
+	// the head of the buffer.
+	dropped atomic.Uint64
+
 	maxSize int64
 }
 
@@ -118,6 +122,9 @@ func (b *eventBuffer) advanceHead() {
 
 	// notify readers that old is being dropped
 	close(old.link.droppedCh)
+	if old.Events != nil && old.Events.Index > b.dropped.Load() {
+		b.dropped.Store(old.Events.Index)
+	}
 
 	// store the next value to head
 	b.head.Store(next)
@@ -137,6 +144,12 @@ func (b *eventBuffer) advanceHead() {
 	}
 }
 
+// Dropped returns the index of the most recent set of events dropped from the
+// buffer, or zero if no events were dropped.
+func (b *eventBuffer) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
 // Head returns the current head of the buffer. It will always exist but it may
 // be a "sentinel" empty item with a nil Events slice to allow consumers to
 // watch for the next update. Consumers should always check for empty Events and
@@ -151,6 +151,26 @@ func TestEventBuffer_MaxSize(t *testing.T) {
 	require.Equal(t, 1, b.Len())
 }
 
+func TestEventBuffer_Dropped(t *testing.T) {
+	ci.Parallel(t)
+
+	b := newEventBuffer(3)
+
+	for i := 1; i <= 3; i++ {
+		b.Append(&structs.Events{Index: uint64(i * 10), Events: []structs.Event{{}}})
+	}
+	require.Zero(t, b.Dropped())
+
+	// The initial sentinel and the oldest events are dropped as the buffer
+	// fills up
+	b.Append(&structs.Events{Index: 40, Events: []structs.Event{{}}})
+	require.Zero(t, b.Dropped())
+	b.Append(&structs.Events{Index: 50, Events: []structs.Event{{}}})
+	require.Equal(t, uint64(10), b.Dropped())
+	b.Append(&structs.Events{Index: 60, Events: []structs.Event{{}}})
+	require.Equal(t, uint64(20), b.Dropped())
+}
+
 // TestEventBuffer_Emptying_Buffer tests the behavior when all items
 // are removed, the event buffer should advance its head down to the last message
 // and insert a placeholder sentinel value.
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package stream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/v2/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// sinkRequestTimeout is the maximum amount of time a single delivery to a
+	// webhook or exec sink may take.
+	sinkRequestTimeout = 30 * time.Second
+
+	// sinkMaxErrorOutput is the maximum number of bytes of a failed webhook
+	// response body or exec output included in the returned error.
+	sinkMaxErrorOutput = 512
+)
+
+// SinkWriter delivers batches of events to the destination of an event sink.
+// A batch is delivered successfully only if Send returns nil, in which case
+// it must not be delivered again.
+type SinkWriter interface {
+	Send(ctx context.Context, batch []*structs.Events) error
+	Close() error
+}
+
+// NewSinkWriter returns the SinkWriter for the type of the given sink.
+func NewSinkWriter(sink *structs.EventSink) (SinkWriter, error) {
+	switch sink.Type {
+	case structs.EventSinkWebhook:
+		return &webhookSink{
+			address: sink.Address,
+			headers: sink.Headers,
+			client:  &http.Client{Timeout: sinkRequestTimeout},
+		}, nil
+	case structs.EventSinkFile:
+		return &fileSink{path: sink.Path}, nil
+	case structs.EventSinkExec:
+		return &execSink{command: sink.Command, args: sink.Args}, nil
+	default:
+		return nil, fmt.Errorf("unsupported event sink type %q", sink.Type)
+	}
+}
+
+// encodeNDJSON encodes each set of events in the batch as a line of JSON,
+// using the same format as the event stream API.
+func encodeNDJSON(batch []*structs.Events) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, structs.JsonHandleWithExtensions)
+	for _, events := range batch {
+		if err := enc.Encode(events); err != nil {
+			return nil, fmt.Errorf("error marshaling json for sink: %w", err)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// webhookSink sends every batch as a JSON array in the body of a POST
+// request. Any response status other than 2xx fails the delivery.
+type webhookSink struct {
+	address string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (w *webhookSink) Send(ctx context.Context, batch []*structs.Events) error {
+	var body bytes.Buffer
+	enc := codec.NewEncoder(&body, structs.JsonHandleWithExtensions)
+	if err := enc.Encode(batch); err != nil {
+		return fmt.Errorf("error marshaling json for sink: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.address, &body)
+	if err != nil {
+		return err
+	}
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		out, _ := io.ReadAll(io.LimitReader(resp.Body, sinkMaxErrorOutput))
+		return fmt.Errorf("webhook returned unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(out))
+	}
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (w *webhookSink) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// fileSink appends every batch as newline delimited JSON to a file. The file
+// is synced before the delivery is acknowledged.
+type fileSink struct {
+	path string
+	f    *os.File
+}
+
+func (s *fileSink) Send(_ context.Context, batch []*structs.Events) error {
+	if s.f == nil {
+		f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open event sink file: %w", err)
+		}
+		s.f = f
+	}
+
+	buf, err := encodeNDJSON(batch)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.f.Write(buf); err != nil {
+		// Reopen the file on the next delivery in case it was removed or
+		// rotated.
+		s.Close()
+		return fmt.Errorf("failed to write to event sink file: %w", err)
+	}
+	return s.f.Sync()
+}
+
+func (s *fileSink) Close() error {
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// execSink runs a command for every batch and writes the batch as newline
+// delimited JSON to its stdin. A non-zero exit code fails the delivery.
+type execSink struct {
+	command string
+	args    []string
+}
+
+func (e *execSink) Send(ctx context.Context, batch []*structs.Events) error {
+	buf, err := encodeNDJSON(batch)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sinkRequestTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.command, e.args...)
+	cmd.Stdin = bytes.NewReader(buf)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(out) > sinkMaxErrorOutput {
+			out = out[:sinkMaxErrorOutput]
+		}
+		return fmt.Errorf("event sink command failed: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+func (e *execSink) Close() error {
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func testSinkBatch() []*structs.Events {
+	return []*structs.Events{
+		{Index: 10, Events: []structs.Event{{Topic: structs.TopicJob, Key: "web", Index: 10}}},
+		{Index: 11, Events: []structs.Event{{Topic: structs.TopicJob, Key: "api", Index: 11}}},
+	}
+}
+
+func TestSinkWriter_Webhook(t *testing.T) {
+	ci.Parallel(t)
+
+	var fail atomic.Bool
+	fail.Store(true)
+	gotCh := make(chan []structs.Events, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, "try again")
+			return
+		}
+		must.Eq(t, http.MethodPost, r.Method)
+		must.Eq(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var got []structs.Events
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		gotCh <- got
+	}))
+	defer srv.Close()
+
+	w, err := NewSinkWriter(&structs.EventSink{
+		Type:    structs.EventSinkWebhook,
+		Address: srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	})
+	must.NoError(t, err)
+	defer w.Close()
+
+	err = w.Send(context.Background(), testSinkBatch())
+	must.ErrorContains(t, err, "unexpected status 503: try again")
+
+	fail.Store(false)
+	must.NoError(t, w.Send(context.Background(), testSinkBatch()))
+	got := <-gotCh
+	must.Len(t, 2, got)
+	must.Eq(t, 10, got[0].Index)
+	must.Eq(t, "api", got[1].Events[0].Key)
+}
+
+func TestSinkWriter_File(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "events.json")
+	w, err := NewSinkWriter(&structs.EventSink{Type: structs.EventSinkFile, Path: path})
+	must.NoError(t, err)
+
+	must.NoError(t, w.Send(context.Background(), testSinkBatch()))
+	must.NoError(t, w.Send(context.Background(), testSinkBatch()[:1]))
+	must.NoError(t, w.Close())
+
+	content, err := os.ReadFile(path)
+	must.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	must.Len(t, 3, lines)
+	for i, idx := range []uint64{10, 11, 10} {
+		var events structs.Events
+		must.NoError(t, json.Unmarshal([]byte(lines[i]), &events))
+		must.Eq(t, idx, events.Index)
+	}
+}
+
+func TestSinkWriter_Exec(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires a POSIX shell")
+	}
+
+	path := filepath.Join(t.TempDir(), "events.json")
+	w, err := NewSinkWriter(&structs.EventSink{
+		Type:    structs.EventSinkExec,
+		Command: "/bin/sh",
+		Args:    []string{"-c", `cat >> "$0"`, path},
+	})
+	must.NoError(t, err)
+	defer w.Close()
+
+	must.NoError(t, w.Send(context.Background(), testSinkBatch()))
+
+	content, err := os.ReadFile(path)
+	must.NoError(t, err)
+	must.Len(t, 2, strings.Split(strings.TrimSpace(string(content)), "\n"))
+
+	// A non-zero exit code fails the delivery.
+	w, err = NewSinkWriter(&structs.EventSink{
+		Type:    structs.EventSinkExec,
+		Command: "/bin/sh",
+		Args:    []string{"-c", "echo boom; exit 1"},
+	})
+	must.NoError(t, err)
+	err = w.Send(context.Background(), testSinkBatch())
+	must.ErrorContains(t, err, "boom")
+}
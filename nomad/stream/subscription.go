@@ -6,8 +6,10 @@ package stream
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync/atomic"
 
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -45,6 +47,12 @@ type Subscription struct {
 	// It must be safe to call the function from multiple goroutines and the function
 	// must be idempotent.
 	unsub func()
+
+	// aclObj is the ACL of the token of the subscription, used to filter
+	// events of jobs the token is not allowed to read. It is nil if the
+	// subscription was not checked against a token. It is updated by the
+	// EventBroker when the token or its policies change.
+	aclObj atomic.Pointer[acl.ACL]
 }
 
 type SubscribeRequest struct {
@@ -85,7 +93,7 @@ func (s *Subscription) Next(ctx context.Context) (structs.Events, error) {
 		}
 		s.currentItem = next
 
-		events := filter(s.req, next.Events.Events)
+		events := s.filterACL(filter(s.req, next.Events.Events))
 		if len(events) == 0 {
 			continue
 		}
@@ -105,7 +113,7 @@ func (s *Subscription) NextNoBlock() ([]structs.Event, error) {
 		}
 		s.currentItem = next
 
-		events := filter(s.req, next.Events.Events)
+		events := s.filterACL(filter(s.req, next.Events.Events))
 		if len(events) == 0 {
 			continue
 		}
@@ -117,6 +125,39 @@ func (s *Subscription) Unsubscribe() {
 	s.unsub()
 }
 
+// setACL sets the ACL used to filter the events of the subscription.
+func (s *Subscription) setACL(aclObj *acl.ACL) {
+	if aclObj.IsManagement() {
+		aclObj = nil
+	}
+	s.aclObj.Store(aclObj)
+}
+
+// filterACL removes the events the token of the subscription is not allowed
+// to receive. The events are shared with other subscriptions, so a new slice is
+// returned if any event is removed.
+func (s *Subscription) filterACL(events []structs.Event) []structs.Event {
+	aclObj := s.aclObj.Load()
+	if aclObj == nil {
+		return events
+	}
+
+	for i := range events {
+		if aclAllowsEvent(aclObj, &events[i]) {
+			continue
+		}
+
+		result := slices.Clone(events[:i])
+		for j := i + 1; j < len(events); j++ {
+			if aclAllowsEvent(aclObj, &events[j]) {
+				result = append(result, events[j])
+			}
+		}
+		return result
+	}
+	return events
+}
+
 // filter events to only those that match a subscriptions topic/keys/namespace
 func filter(req *SubscribeRequest, events []structs.Event) []structs.Event {
 	if len(events) == 0 {
@@ -5,9 +5,11 @@ package structs
 
 import (
 	"bytes"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"slices"
 	"strconv"
@@ -17,6 +19,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-set/v2"
 	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -173,6 +176,15 @@ const (
 	// Reply: ACLOIDCCompleteAuthResponse
 	ACLOIDCCompleteAuthRPCMethod = "ACL.OIDCCompleteAuth"
 
+	// ACLOIDCDeviceAuthRPCMethod is the RPC method for starting the OIDC
+	// device authorization workflow, used by clients that can't receive the
+	// redirect of the OIDC provider. The workflow is completed by polling
+	// ACLOIDCCompleteAuthRPCMethod with the device code.
+	//
+	// Args: ACLOIDCDeviceAuthRequest
+	// Reply: ACLOIDCDeviceAuthResponse
+	ACLOIDCDeviceAuthRPCMethod = "ACL.OIDCDeviceAuth"
+
 	// ACLLoginRPCMethod is the RPC method for performing a non-OIDC login
 	// workflow. It exchanges the provided token for a Nomad ACL token with
 	// roles as defined within the remote provider.
@@ -180,6 +192,27 @@ const (
 	// Args: ACLLoginRequest
 	// Reply: ACLLoginResponse
 	ACLLoginRPCMethod = "ACL.Login"
+
+	// ACLExplainRPCMethod is the RPC method for explaining the ACL decision
+	// of a token for an operation.
+	//
+	// Args: ACLExplainRequest
+	// Reply: ACLExplainResponse
+	ACLExplainRPCMethod = "ACL.Explain"
+
+	// ACLRenewTokenRPCMethod is the RPC method for renewing a renewable ACL
+	// token, extending its expiration time.
+	//
+	// Args: ACLTokenRenewRequest
+	// Reply: ACLTokenRenewResponse
+	ACLRenewTokenRPCMethod = "ACL.RenewToken"
+
+	// ACLDeriveTaskTokenRPCMethod is the RPC method used by clients to create
+	// the ACL token requested by the nomad block of a task.
+	//
+	// Args: ACLDeriveTaskTokenRequest
+	// Reply: ACLDeriveTaskTokenResponse
+	ACLDeriveTaskTokenRPCMethod = "ACL.DeriveTaskToken"
 )
 
 const (
@@ -214,6 +247,14 @@ const (
 	// which uses the JWT type.
 	ACLAuthMethodTypeJWT = "JWT"
 
+	// ACLAuthMethodTypeLDAP the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates users against an LDAP directory.
+	ACLAuthMethodTypeLDAP = "LDAP"
+
+	// ACLAuthMethodTypeCert the ACLAuthMethod.Type and represents an
+	// auth-method which authenticates TLS client certificates.
+	ACLAuthMethodTypeCert = "CERT"
+
 	DefaultACLAuthMethodTokenNameFormat = "${auth_method_type}-${auth_method_name}"
 )
 
@@ -225,7 +266,8 @@ var (
 	ValidACLAuthMethod = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 
 	// ValidACLAuthMethodTypes lists supported auth method types.
-	ValidACLAuthMethodTypes = []string{ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT}
+	ValidACLAuthMethodTypes = []string{
+		ACLAuthMethodTypeOIDC, ACLAuthMethodTypeJWT, ACLAuthMethodTypeLDAP, ACLAuthMethodTypeCert}
 )
 
 type ACLCacheEntry[T any] lang.Pair[T, time.Time]
@@ -338,6 +380,22 @@ func (a *ACLToken) Validate(minTTL, maxTTL time.Duration, existing *ACLToken) er
 				fmt.Errorf("token expiration TTL '%s' should not be negative", a.ExpirationTTL))
 		}
 
+		if a.Renewable && a.ExpirationTTL <= 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("renewable token must have an expiration TTL"))
+		}
+		if a.MaxTTL != 0 {
+			switch {
+			case !a.Renewable:
+				mErr.Errors = append(mErr.Errors, errors.New("max TTL can only be set on renewable tokens"))
+			case a.MaxTTL < a.ExpirationTTL:
+				mErr.Errors = append(mErr.Errors,
+					fmt.Errorf("max TTL '%s' cannot be less than the expiration TTL '%s'", a.MaxTTL, a.ExpirationTTL))
+			case a.MaxTTL > maxTTL:
+				mErr.Errors = append(mErr.Errors,
+					fmt.Errorf("max TTL cannot be more than %s (was %s)", maxTTL, a.MaxTTL))
+			}
+		}
+
 		if a.ExpirationTime != nil && !a.ExpirationTime.IsZero() {
 
 			if a.CreateTime.After(*a.ExpirationTime) {
@@ -365,6 +423,15 @@ func (a *ACLToken) Validate(minTTL, maxTTL time.Duration, existing *ACLToken) er
 		if existing.ExpirationTTL != a.ExpirationTTL {
 			mErr.Errors = append(mErr.Errors, errors.New("cannot update expiration TTL"))
 		}
+		if existing.Renewable != a.Renewable {
+			mErr.Errors = append(mErr.Errors, errors.New("cannot toggle renewable mode"))
+		}
+		if existing.MaxTTL != a.MaxTTL {
+			mErr.Errors = append(mErr.Errors, errors.New("cannot update max TTL"))
+		}
+		if existing.AllocID != a.AllocID {
+			mErr.Errors = append(mErr.Errors, errors.New("cannot update alloc ID"))
+		}
 		if a.ExpirationTime != nil {
 			if !existing.ExpirationTime.Equal(*a.ExpirationTime) {
 				mErr.Errors = append(mErr.Errors, errors.New("cannot update expiration time"))
@@ -405,6 +472,39 @@ func (a *ACLToken) IsExpired(t time.Time) bool {
 	return a.ExpirationTime.Before(t) || t.IsZero()
 }
 
+// RenewedExpirationTime returns the expiration time of the renewable ACL token
+// once renewed at the passed time. The token is extended by its ExpirationTTL,
+// but not beyond its MaxTTL since creation, which defaults to the passed
+// maxTTL. Renewals never shorten the expiration time.
+func (a *ACLToken) RenewedExpirationTime(now time.Time, maxTTL time.Duration) (time.Time, error) {
+	if !a.Renewable {
+		return time.Time{}, errors.New("token is not renewable")
+	}
+	if !a.HasExpirationTime() {
+		return time.Time{}, errors.New("token does not expire")
+	}
+	if a.IsExpired(now) {
+		return time.Time{}, errors.New("token has expired")
+	}
+
+	if a.MaxTTL != 0 {
+		maxTTL = a.MaxTTL
+	}
+	maxExpirationTime := a.CreateTime.Add(maxTTL)
+
+	expirationTime := now.UTC().Add(a.ExpirationTTL)
+	if expirationTime.After(maxExpirationTime) {
+		expirationTime = maxExpirationTime
+	}
+	if !expirationTime.After(*a.ExpirationTime) {
+		if !a.ExpirationTime.Before(maxExpirationTime) {
+			return time.Time{}, errors.New("token has reached its max TTL")
+		}
+		return *a.ExpirationTime, nil
+	}
+	return expirationTime, nil
+}
+
 // HasRoles checks if a given set of role IDs are assigned to the ACL token. It
 // does not account for management tokens, therefore it is the responsibility
 // of the caller to perform this check, if required.
@@ -429,14 +529,19 @@ func (a *ACLToken) MarshalJSON() ([]byte, error) {
 	type Alias ACLToken
 	exported := &struct {
 		ExpirationTTL string
+		MaxTTL        string
 		*Alias
 	}{
 		ExpirationTTL: a.ExpirationTTL.String(),
+		MaxTTL:        a.MaxTTL.String(),
 		Alias:         (*Alias)(a),
 	}
 	if a.ExpirationTTL == 0 {
 		exported.ExpirationTTL = ""
 	}
+	if a.MaxTTL == 0 {
+		exported.MaxTTL = ""
+	}
 	return json.Marshal(exported)
 }
 
@@ -446,6 +551,7 @@ func (a *ACLToken) UnmarshalJSON(data []byte) (err error) {
 	type Alias ACLToken
 	aux := &struct {
 		ExpirationTTL interface{}
+		MaxTTL        interface{}
 		Hash          string
 		*Alias
 	}{
@@ -468,12 +574,29 @@ func (a *ACLToken) UnmarshalJSON(data []byte) (err error) {
 		}
 
 	}
+	if a.MaxTTL, err = unmarshalJSONDuration(aux.MaxTTL); err != nil {
+		return err
+	}
 	if aux.Hash != "" {
 		a.Hash = []byte(aux.Hash)
 	}
 	return nil
 }
 
+// unmarshalJSONDuration converts a duration decoded from JSON, which can be
+// either a duration string like "2m" or a number of nanoseconds.
+func unmarshalJSONDuration(v interface{}) (time.Duration, error) {
+	switch v := v.(type) {
+	case string:
+		if v != "" {
+			return time.ParseDuration(v)
+		}
+	case float64:
+		return time.Duration(v), nil
+	}
+	return 0, nil
+}
+
 // ACLRole is an abstraction for the ACL system which allows the grouping of
 // ACL policies into a single object. ACL tokens can be created and linked to
 // a role; the token then inherits all the permissions granted by the policies.
@@ -500,6 +623,14 @@ type ACLRole struct {
 	// both.
 	Policies []*ACLRolePolicyLink
 
+	// TokenRenewable makes the ACL tokens created with a link to this role
+	// renewable, as long as they have an expiration TTL.
+	TokenRenewable bool
+
+	// TokenMaxTTL limits the MaxTTL of the renewable ACL tokens created with a
+	// link to this role. If zero, the role doesn't limit the MaxTTL.
+	TokenMaxTTL time.Duration
+
 	// Hash is the hashed value of the role and is generated using all fields
 	// above this point.
 	Hash []byte
@@ -536,6 +667,13 @@ func (a *ACLRole) SetHash() []byte {
 		_, _ = hash.Write([]byte(policyLink.Name))
 	}
 
+	// The token settings are only written when set, so the hash of roles
+	// which do not use them is unchanged.
+	if a.TokenRenewable || a.TokenMaxTTL != 0 {
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.TokenRenewable)))
+		_, _ = hash.Write([]byte(a.TokenMaxTTL.String()))
+	}
+
 	// Finalize the hash.
 	hashVal := hash.Sum(nil)
 
@@ -563,9 +701,48 @@ func (a *ACLRole) Validate() error {
 		mErr.Errors = append(mErr.Errors, errors.New("at least one policy should be specified"))
 	}
 
+	if a.TokenMaxTTL < 0 {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("token max TTL '%s' should not be negative", a.TokenMaxTTL))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// MarshalJSON implements the json.Marshaler interface and allows
+// ACLRole.TokenMaxTTL to be marshaled correctly.
+func (a *ACLRole) MarshalJSON() ([]byte, error) {
+	type Alias ACLRole
+	exported := &struct {
+		TokenMaxTTL string
+		*Alias
+	}{
+		TokenMaxTTL: a.TokenMaxTTL.String(),
+		Alias:       (*Alias)(a),
+	}
+	if a.TokenMaxTTL == 0 {
+		exported.TokenMaxTTL = ""
+	}
+	return json.Marshal(exported)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and allows
+// ACLRole.TokenMaxTTL to be unmarshalled correctly.
+func (a *ACLRole) UnmarshalJSON(data []byte) (err error) {
+	type Alias ACLRole
+	aux := &struct {
+		TokenMaxTTL interface{}
+		*Alias
+	}{
+		Alias: (*Alias)(a),
+	}
+	if err = json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.TokenMaxTTL, err = unmarshalJSONDuration(aux.TokenMaxTTL)
+	return err
+}
+
 // Canonicalize performs basic canonicalization on the ACL role object. It is
 // important for callers to understand certain fields such as ID are set if it
 // is empty, so copies should be taken if needed before calling this function.
@@ -752,6 +929,14 @@ type ACLAuthMethod struct {
 	Default         bool
 	Config          *ACLAuthMethodConfig
 
+	// TokenTTL is the expiration TTL of the tokens created by the method. If
+	// zero, MaxTokenTTL is used.
+	TokenTTL time.Duration
+
+	// TokenRenewable makes the tokens created by the method renewable, up to
+	// MaxTokenTTL since their creation.
+	TokenRenewable bool
+
 	Hash []byte
 
 	CreateTime  time.Time
@@ -777,6 +962,10 @@ func (a *ACLAuthMethod) SetHash() []byte {
 	_, _ = hash.Write([]byte(a.TokenNameFormat))
 	_, _ = hash.Write([]byte(a.MaxTokenTTL.String()))
 	_, _ = hash.Write([]byte(strconv.FormatBool(a.Default)))
+	if a.TokenTTL != 0 || a.TokenRenewable {
+		_, _ = hash.Write([]byte(a.TokenTTL.String()))
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.TokenRenewable)))
+	}
 
 	if a.Config != nil {
 		_, _ = hash.Write([]byte(a.Config.JWKSURL))
@@ -785,6 +974,7 @@ func (a *ACLAuthMethod) SetHash() []byte {
 		_, _ = hash.Write([]byte(a.Config.OIDCClientID))
 		_, _ = hash.Write([]byte(a.Config.OIDCClientSecret))
 		_, _ = hash.Write([]byte(strconv.FormatBool(a.Config.OIDCDisableUserInfo)))
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.Config.OIDCEnablePKCE)))
 		_, _ = hash.Write([]byte(a.Config.ExpirationLeeway.String()))
 		_, _ = hash.Write([]byte(a.Config.NotBeforeLeeway.String()))
 		_, _ = hash.Write([]byte(a.Config.ClockSkewLeeway.String()))
@@ -817,6 +1007,23 @@ func (a *ACLAuthMethod) SetHash() []byte {
 			_, _ = hash.Write([]byte(k))
 			_, _ = hash.Write([]byte(v))
 		}
+		for _, u := range a.Config.LDAPURLs {
+			_, _ = hash.Write([]byte(u))
+		}
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.Config.LDAPStartTLS)))
+		_, _ = hash.Write([]byte(strconv.FormatBool(a.Config.LDAPInsecureSkipVerify)))
+		_, _ = hash.Write([]byte(a.Config.LDAPCACert))
+		_, _ = hash.Write([]byte(a.Config.LDAPBindDN))
+		_, _ = hash.Write([]byte(a.Config.LDAPBindPassword))
+		_, _ = hash.Write([]byte(a.Config.LDAPUserDN))
+		_, _ = hash.Write([]byte(a.Config.LDAPUserAttr))
+		_, _ = hash.Write([]byte(a.Config.LDAPUserFilter))
+		_, _ = hash.Write([]byte(a.Config.LDAPGroupDN))
+		_, _ = hash.Write([]byte(a.Config.LDAPGroupAttr))
+		_, _ = hash.Write([]byte(a.Config.LDAPGroupFilter))
+		for _, pem := range a.Config.CertCACerts {
+			_, _ = hash.Write([]byte(pem))
+		}
 	}
 
 	// Finalize the hash.
@@ -833,14 +1040,19 @@ func (a *ACLAuthMethod) MarshalJSON() ([]byte, error) {
 	type Alias ACLAuthMethod
 	exported := &struct {
 		MaxTokenTTL string
+		TokenTTL    string
 		*Alias
 	}{
 		MaxTokenTTL: a.MaxTokenTTL.String(),
+		TokenTTL:    a.TokenTTL.String(),
 		Alias:       (*Alias)(a),
 	}
 	if a.MaxTokenTTL == 0 {
 		exported.MaxTokenTTL = ""
 	}
+	if a.TokenTTL == 0 {
+		exported.TokenTTL = ""
+	}
 	return json.Marshal(exported)
 }
 
@@ -850,6 +1062,7 @@ func (a *ACLAuthMethod) UnmarshalJSON(data []byte) (err error) {
 	type Alias ACLAuthMethod
 	aux := &struct {
 		MaxTokenTTL interface{}
+		TokenTTL    interface{}
 		*Alias
 	}{
 		Alias: (*Alias)(a),
@@ -867,7 +1080,8 @@ func (a *ACLAuthMethod) UnmarshalJSON(data []byte) (err error) {
 			a.MaxTokenTTL = time.Duration(v)
 		}
 	}
-	return nil
+	a.TokenTTL, err = unmarshalJSONDuration(aux.TokenTTL)
+	return err
 }
 
 func (a *ACLAuthMethod) Stub() *ACLAuthMethodStub {
@@ -934,6 +1148,7 @@ func (a *ACLAuthMethod) Merge(b *ACLAuthMethod) {
 		a.TokenLocality = helper.Merge(a.TokenLocality, b.TokenLocality)
 		a.TokenNameFormat = helper.Merge(a.TokenNameFormat, b.TokenNameFormat)
 		a.MaxTokenTTL = helper.Merge(a.MaxTokenTTL, b.MaxTokenTTL)
+		a.TokenTTL = helper.Merge(a.TokenTTL, b.TokenTTL)
 		a.Config = helper.Merge(a.Config, b.Config)
 	}
 }
@@ -964,9 +1179,44 @@ func (a *ACLAuthMethod) Validate(minTTL, maxTTL time.Duration) error {
 			a.MaxTokenTTL.String(), minTTL.String(), maxTTL.String()))
 	}
 
+	if a.TokenTTL != 0 && (minTTL > a.TokenTTL || a.TokenTTL > a.MaxTokenTTL) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf(
+			"invalid TokenTTL value '%s' (should be between %s and MaxTokenTTL)",
+			a.TokenTTL.String(), minTTL.String()))
+	}
+
+	switch a.Type {
+	case ACLAuthMethodTypeLDAP:
+		if err := a.Config.validateLDAP(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	case ACLAuthMethodTypeCert:
+		if err := a.Config.validateCert(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// NewToken returns a new ACL token with the expiration and renewal settings of
+// the tokens created by the auth method.
+func (a *ACLAuthMethod) NewToken(name string) *ACLToken {
+	token := &ACLToken{
+		Name:          name,
+		Global:        a.TokenLocalityIsGlobal(),
+		ExpirationTTL: a.MaxTokenTTL,
+	}
+	if a.TokenTTL != 0 {
+		token.ExpirationTTL = a.TokenTTL
+	}
+	if a.TokenRenewable {
+		token.Renewable = true
+		token.MaxTTL = a.MaxTokenTTL
+	}
+	return token
+}
+
 // TokenLocalityIsGlobal returns whether the auth method creates global ACL
 // tokens or not.
 func (a *ACLAuthMethod) TokenLocalityIsGlobal() bool {
@@ -994,6 +1244,9 @@ type ACLAuthMethodConfig struct {
 	// Disable claims from the OIDC UserInfo endpoint
 	OIDCDisableUserInfo bool
 
+	// Require the PKCE extension for the OIDC authorization code flow
+	OIDCEnablePKCE bool
+
 	// List of OIDC scopes
 	OIDCScopes []string
 
@@ -1033,6 +1286,110 @@ type ACLAuthMethodConfig struct {
 	// (value).
 	ClaimMappings     map[string]string
 	ListClaimMappings map[string]string
+
+	// The URLs of the LDAP servers, such as "ldaps://ldap.example.com". The
+	// servers are tried in order until a connection succeeds.
+	LDAPURLs []string
+
+	// Upgrade "ldap://" connections to TLS using the StartTLS operation.
+	LDAPStartTLS bool
+
+	// Skip the verification of the LDAP server certificate.
+	LDAPInsecureSkipVerify bool
+
+	// PEM encoded CA cert for use by the TLS client used to talk with the
+	// LDAP servers.
+	LDAPCACert string
+
+	// The credentials used to search for users and groups. If the bind DN is
+	// empty, searches are performed as the user logging in.
+	LDAPBindDN       string
+	LDAPBindPassword string
+
+	// The base DN under which to search for users.
+	LDAPUserDN string
+
+	// The attribute of user entries matching the username, which defaults to
+	// "cn".
+	LDAPUserAttr string
+
+	// The Go template of the filter used to search for the user logging in.
+	// Defaults to "({{.UserAttr}}={{.Username}})".
+	LDAPUserFilter string
+
+	// The base DN under which to search for groups. If empty, only the groups
+	// listed in the memberOf attribute of the user are resolved.
+	LDAPGroupDN string
+
+	// The attribute of group entries holding the group name, which defaults
+	// to "cn".
+	LDAPGroupAttr string
+
+	// The Go template of the filter used to search for the groups of the user.
+	// Defaults to a filter matching the member, uniqueMember and memberUid
+	// attributes.
+	LDAPGroupFilter string
+
+	// PEM encoded CA certs trusted to issue the client certificates
+	// authenticated by the cert auth method.
+	CertCACerts []string
+}
+
+// validateLDAP returns an error if the config is invalid for an LDAP auth
+// method.
+func (a *ACLAuthMethodConfig) validateLDAP() error {
+	if a == nil {
+		return errors.New("missing LDAP config")
+	}
+
+	var mErr multierror.Error
+	if len(a.LDAPURLs) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("missing LDAP URLs"))
+	}
+	for _, rawURL := range a.LDAPURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid LDAP URL '%s': %v", rawURL, err))
+			continue
+		}
+		switch u.Scheme {
+		case "ldap":
+		case "ldaps":
+			if a.LDAPStartTLS {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf(
+					"invalid LDAP URL '%s': StartTLS can't be used with ldaps", rawURL))
+			}
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"invalid LDAP URL '%s': scheme must be ldap or ldaps", rawURL))
+		}
+	}
+	if a.LDAPUserDN == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing LDAP user DN"))
+	}
+	if a.LDAPBindDN != "" && a.LDAPBindPassword == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing LDAP bind password"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// validateCert returns an error if the config is invalid for a cert auth
+// method.
+func (a *ACLAuthMethodConfig) validateCert() error {
+	if a == nil {
+		return errors.New("missing cert config")
+	}
+
+	var mErr multierror.Error
+	if len(a.CertCACerts) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("missing CA certs"))
+	}
+	for i, caCert := range a.CertCACerts {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(caCert)) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid CA cert %d: no PEM encoded certificates found", i))
+		}
+	}
+	return mErr.ErrorOrNil()
 }
 
 func (a *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
@@ -1050,6 +1407,8 @@ func (a *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
 	c.AllowedRedirectURIs = slices.Clone(a.AllowedRedirectURIs)
 	c.DiscoveryCaPem = slices.Clone(a.DiscoveryCaPem)
 	c.SigningAlgs = slices.Clone(a.SigningAlgs)
+	c.LDAPURLs = slices.Clone(a.LDAPURLs)
+	c.CertCACerts = slices.Clone(a.CertCACerts)
 
 	return c
 }
@@ -1551,6 +1910,12 @@ type ACLOIDCAuthURLRequest struct {
 	// passed back to ACLOIDCCompleteAuthRequest. This is a required parameter.
 	ClientNonce string
 
+	// CodeChallenge is the S256 PKCE code challenge of the code verifier
+	// generated by the client, which must then be passed back to
+	// ACLOIDCCompleteAuthRequest. It is required if the auth method enables
+	// PKCE.
+	CodeChallenge string
+
 	// WriteRequest is used due to the requirement by the RPC forwarding
 	// mechanism. This request doesn't write anything to Nomad's internal
 	// state.
@@ -1603,6 +1968,16 @@ type ACLOIDCCompleteAuthRequest struct {
 	// required parameter.
 	RedirectURI string
 
+	// CodeVerifier is the PKCE code verifier of the challenge passed to
+	// ACLOIDCAuthURLRequest. It is required if the auth method enables PKCE.
+	CodeVerifier string
+
+	// DeviceCode is the device code returned by ACLOIDCDeviceAuthRequest.
+	// When set, the request polls for the completion of the device
+	// authorization and the nonce, state, code and redirect URI are not
+	// required.
+	DeviceCode string
+
 	WriteRequest
 }
 
@@ -1615,6 +1990,9 @@ func (a *ACLOIDCCompleteAuthRequest) Validate() error {
 	if a.AuthMethodName == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("missing auth method name"))
 	}
+	if a.DeviceCode != "" {
+		return mErr.ErrorOrNil()
+	}
 	if a.ClientNonce == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("missing client nonce"))
 	}
@@ -1630,6 +2008,54 @@ func (a *ACLOIDCCompleteAuthRequest) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// ACLOIDCDeviceAuthRequest is the request to make when starting the OIDC
+// device authorization flow.
+type ACLOIDCDeviceAuthRequest struct {
+
+	// AuthMethodName is the OIDC auth-method to use. This is a required
+	// parameter.
+	AuthMethodName string
+
+	// WriteRequest is used due to the requirement by the RPC forwarding
+	// mechanism. This request doesn't write anything to Nomad's internal
+	// state.
+	WriteRequest
+}
+
+// Validate ensures the request object contains all the required fields in
+// order to start the OIDC device authorization flow.
+func (a *ACLOIDCDeviceAuthRequest) Validate() error {
+	if a.AuthMethodName == "" {
+		return errors.New("missing auth method name")
+	}
+	return nil
+}
+
+// ACLOIDCDeviceAuthResponse is the response when starting the OIDC device
+// authorization flow.
+type ACLOIDCDeviceAuthResponse struct {
+
+	// DeviceCode is passed to ACLOIDCCompleteAuthRequest to poll for the
+	// completion of the authorization.
+	DeviceCode string
+
+	// UserCode is the code the user logging in should enter at the
+	// verification URI.
+	UserCode string
+
+	// VerificationURI is where the user logging in should go, and
+	// VerificationURIComplete optionally includes the user code.
+	VerificationURI         string
+	VerificationURIComplete string
+
+	// Expiry is the time at which the device code expires.
+	Expiry time.Time
+
+	// Interval is the minimum duration between polls for the completion of
+	// the authorization.
+	Interval time.Duration
+}
+
 // ACLLoginResponse is the response when the auth flow has been
 // completed successfully.
 type ACLLoginResponse struct {
@@ -1646,9 +2072,23 @@ type ACLLoginRequest struct {
 	AuthMethodName string
 
 	// LoginToken is the 3rd party token that we use to exchange for Nomad ACL
-	// Token in order to authenticate. This is a required parameter.
+	// Token in order to authenticate. This is a required parameter, unless
+	// logging in with a username and password.
 	LoginToken string
 
+	// Username and Password are the credentials used to authenticate with
+	// auth methods which don't use tokens, such as LDAP.
+	Username string
+	Password string
+
+	// ClientCertificates is the DER encoded certificate chain, leaf first,
+	// presented by the client when authenticating with a cert auth method.
+	// It is set by the agent from the TLS connection of the HTTP request and
+	// never decoded from the request body. Servers only trust it when the
+	// request was received over their own HTTP API or forwarded by another
+	// server.
+	ClientCertificates [][]byte `json:"-"`
+
 	WriteRequest
 }
 
@@ -1661,8 +2101,122 @@ func (a *ACLLoginRequest) Validate() error {
 	if a.AuthMethodName == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("missing auth method name"))
 	}
-	if a.LoginToken == "" {
+	if a.LoginToken == "" && a.Username == "" && len(a.ClientCertificates) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("missing login token"))
 	}
+	if a.Username != "" && a.Password == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing password"))
+	}
 	return mErr.ErrorOrNil()
 }
+
+// ACLTokenRenewRequest is the request object used to renew a renewable ACL
+// token.
+type ACLTokenRenewRequest struct {
+	// AccessorID is the accessor ID of the token to renew. If empty, the token
+	// used to make the request is renewed.
+	AccessorID string
+
+	WriteRequest
+}
+
+// ACLTokenRenewResponse is the response object when renewing an ACL token.
+type ACLTokenRenewResponse struct {
+	Token *ACLToken
+	WriteMeta
+}
+
+// ACLDeriveTaskTokenRequest is the request object used by clients to create
+// the ACL token requested by the nomad block of a task.
+type ACLDeriveTaskTokenRequest struct {
+	AllocID string
+	Task    string
+
+	WriteRequest
+}
+
+// Validate ensures the request contains the allocation and task.
+func (a *ACLDeriveTaskTokenRequest) Validate() error {
+	var mErr multierror.Error
+	if a.AllocID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing alloc ID"))
+	}
+	if a.Task == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing task"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLDeriveTaskTokenResponse is the response object when creating the ACL
+// token of a task.
+type ACLDeriveTaskTokenResponse struct {
+	Token *ACLToken
+	WriteMeta
+}
+
+// ACLExplainRequest is the request object used to explain the ACL decision
+// of a token for an operation.
+type ACLExplainRequest struct {
+	// AccessorID is the accessor ID of the token to explain. If empty, the
+	// token used to make the request is explained.
+	AccessorID string
+
+	// Operation is the operation whose ACL decision is explained.
+	Operation *acl.Operation
+
+	QueryOptions
+}
+
+// ACLExplainResponse is the response object when explaining the ACL decision
+// of a token for an operation.
+type ACLExplainResponse struct {
+	Explanation *ACLExplanation
+	QueryMeta
+}
+
+// ACLExplanation is the ACL decision of a token for an operation, along with
+// the policy rules that produced it.
+type ACLExplanation struct {
+	// AccessorID, Name and Type identify the explained token.
+	AccessorID string
+	Name       string
+	Type       string
+
+	// Allowed is the decision of the token for the operation.
+	Allowed bool
+
+	// Management is true if the token is a management token, in which case
+	// every operation is allowed and no rules are reported.
+	Management bool
+
+	// Expired is true if the token has expired, in which case every
+	// operation is denied regardless of the rules reported.
+	Expired bool
+
+	// Rules are the rules of the token policies that apply to the operation.
+	Rules []*acl.ExplainedRule
+
+	// Policies describes how each policy came to be attached to the token.
+	Policies []*ACLExplanationPolicy
+}
+
+// ACLExplanationPolicy describes how a policy came to be attached to a token.
+type ACLExplanationPolicy struct {
+	// Name is the name of the policy.
+	Name string
+
+	// Token is true if the policy is linked directly to the token.
+	Token bool
+
+	// Roles are the names of the token roles that link the policy.
+	Roles []string
+
+	// BindingRules are the IDs of the binding rules that bind either the
+	// policy or one of the roles linking it. Binding rules with templated
+	// bind names are not reported.
+	BindingRules []string
+
+	// Missing is true if the policy no longer exists, in which case it
+	// doesn't contribute to the decision.
+	Missing bool
+}
@@ -4,8 +4,15 @@
 package structs
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -170,6 +177,76 @@ func TestACLTokenValidate(t *testing.T) {
 			inputExistingACLToken: nil,
 			expectedErrorContains: "expiration time cannot be more than",
 		},
+		{
+			name: "renewable without TTL",
+			inputACLToken: &ACLToken{
+				Type:      ACLManagementToken,
+				Name:      "foo",
+				Renewable: true,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "must have an expiration TTL",
+		},
+		{
+			name: "max TTL on non-renewable",
+			inputACLToken: &ACLToken{
+				Type:          ACLManagementToken,
+				Name:          "foo",
+				ExpirationTTL: time.Hour,
+				MaxTTL:        2 * time.Hour,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "can only be set on renewable tokens",
+		},
+		{
+			name: "max TTL less than TTL",
+			inputACLToken: &ACLToken{
+				Type:          ACLManagementToken,
+				Name:          "foo",
+				ExpirationTTL: 2 * time.Hour,
+				Renewable:     true,
+				MaxTTL:        time.Hour,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "cannot be less than the expiration TTL",
+		},
+		{
+			name: "max TTL too large",
+			inputACLToken: &ACLToken{
+				Type:          ACLManagementToken,
+				Name:          "foo",
+				ExpirationTTL: time.Hour,
+				Renewable:     true,
+				MaxTTL:        48 * time.Hour,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "max TTL cannot be more than",
+		},
+		{
+			name: "toggle renewable",
+			inputACLToken: &ACLToken{
+				Type:      ACLManagementToken,
+				Name:      "foo",
+				Renewable: true,
+			},
+			inputExistingACLToken: &ACLToken{
+				Type: ACLManagementToken,
+				Name: "foo",
+			},
+			expectedErrorContains: "cannot toggle renewable mode",
+		},
+		{
+			name: "valid renewable",
+			inputACLToken: &ACLToken{
+				Type:          ACLManagementToken,
+				Name:          "foo",
+				ExpirationTTL: time.Hour,
+				Renewable:     true,
+				MaxTTL:        12 * time.Hour,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "",
+		},
 		{
 			name: "valid management",
 			inputACLToken: &ACLToken{
@@ -303,6 +380,113 @@ func TestACLToken_IsExpired(t *testing.T) {
 	}
 }
 
+func TestACLToken_RenewedExpirationTime(t *testing.T) {
+	ci.Parallel(t)
+
+	createTime := time.Date(2024, time.May, 9, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                  string
+		inputACLToken         *ACLToken
+		inputTime             time.Time
+		expectedOutput        time.Time
+		expectedErrorContains string
+	}{
+		{
+			name:                  "not renewable",
+			inputACLToken:         &ACLToken{},
+			inputTime:             createTime,
+			expectedErrorContains: "not renewable",
+		},
+		{
+			name:                  "no expiration",
+			inputACLToken:         &ACLToken{Renewable: true},
+			inputTime:             createTime,
+			expectedErrorContains: "does not expire",
+		},
+		{
+			name: "expired",
+			inputACLToken: &ACLToken{
+				Renewable:      true,
+				CreateTime:     createTime,
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: pointer.Of(createTime.Add(time.Hour)),
+			},
+			inputTime:             createTime.Add(2 * time.Hour),
+			expectedErrorContains: "has expired",
+		},
+		{
+			name: "renewed",
+			inputACLToken: &ACLToken{
+				Renewable:      true,
+				CreateTime:     createTime,
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: pointer.Of(createTime.Add(time.Hour)),
+			},
+			inputTime:      createTime.Add(30 * time.Minute),
+			expectedOutput: createTime.Add(90 * time.Minute),
+		},
+		{
+			name: "capped at token max TTL",
+			inputACLToken: &ACLToken{
+				Renewable:      true,
+				CreateTime:     createTime,
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: pointer.Of(createTime.Add(time.Hour)),
+				MaxTTL:         80 * time.Minute,
+			},
+			inputTime:      createTime.Add(30 * time.Minute),
+			expectedOutput: createTime.Add(80 * time.Minute),
+		},
+		{
+			name: "capped at region max TTL",
+			inputACLToken: &ACLToken{
+				Renewable:      true,
+				CreateTime:     createTime,
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: pointer.Of(createTime.Add(23*time.Hour + 45*time.Minute)),
+			},
+			inputTime:      createTime.Add(23*time.Hour + 30*time.Minute),
+			expectedOutput: createTime.Add(24 * time.Hour),
+		},
+		{
+			name: "max TTL reached",
+			inputACLToken: &ACLToken{
+				Renewable:      true,
+				CreateTime:     createTime,
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: pointer.Of(createTime.Add(2 * time.Hour)),
+				MaxTTL:         2 * time.Hour,
+			},
+			inputTime:             createTime.Add(90 * time.Minute),
+			expectedErrorContains: "reached its max TTL",
+		},
+		{
+			name: "never shortened",
+			inputACLToken: &ACLToken{
+				Renewable:      true,
+				CreateTime:     createTime,
+				ExpirationTTL:  time.Hour,
+				ExpirationTime: pointer.Of(createTime.Add(3 * time.Hour)),
+			},
+			inputTime:      createTime.Add(time.Hour),
+			expectedOutput: createTime.Add(3 * time.Hour),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput, err := tc.inputACLToken.RenewedExpirationTime(tc.inputTime, 24*time.Hour)
+			if tc.expectedErrorContains != "" {
+				must.ErrorContains(t, err, tc.expectedErrorContains)
+			} else {
+				must.NoError(t, err)
+				must.Eq(t, tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
 func TestACLToken_HasRoles(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -1222,6 +1406,20 @@ func TestACLAuthMethod_Validate(t *testing.T) {
 	goodTTL, _ := time.ParseDuration("3600s")
 	badTTL, _ := time.ParseDuration("3600h")
 
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mock-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	must.NoError(t, err)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+
 	tests := []struct {
 		name        string
 		method      *ACLAuthMethod
@@ -1243,6 +1441,72 @@ func TestACLAuthMethod_Validate(t *testing.T) {
 		{"invalid token locality", &ACLAuthMethod{TokenLocality: "regional"}, true, "invalid token locality"},
 		{"invalid type", &ACLAuthMethod{Type: "groovy"}, true, "invalid token type"},
 		{"invalid max ttl", &ACLAuthMethod{MaxTokenTTL: badTTL}, true, "invalid token type"},
+		{
+			"valid ldap method",
+			&ACLAuthMethod{
+				Name:          "mock-auth-method",
+				Type:          "LDAP",
+				TokenLocality: "local",
+				MaxTokenTTL:   goodTTL,
+				Config: &ACLAuthMethodConfig{
+					LDAPURLs:   []string{"ldap://ldap.example.com", "ldaps://ldap.example.com"},
+					LDAPUserDN: "ou=people,dc=example,dc=org",
+				},
+			},
+			false,
+			"",
+		},
+		{"missing ldap config", &ACLAuthMethod{Type: "LDAP"}, true, "missing LDAP config"},
+		{
+			"invalid ldap config",
+			&ACLAuthMethod{
+				Type: "LDAP",
+				Config: &ACLAuthMethodConfig{
+					LDAPURLs:   []string{"https://ldap.example.com"},
+					LDAPBindDN: "cn=nomad,dc=example,dc=org",
+				},
+			},
+			true,
+			"scheme must be ldap or ldaps",
+		},
+		{
+			"ldap starttls with ldaps",
+			&ACLAuthMethod{
+				Type: "LDAP",
+				Config: &ACLAuthMethodConfig{
+					LDAPURLs:     []string{"ldaps://ldap.example.com"},
+					LDAPUserDN:   "ou=people,dc=example,dc=org",
+					LDAPStartTLS: true,
+				},
+			},
+			true,
+			"StartTLS can't be used with ldaps",
+		},
+		{
+			"valid cert method",
+			&ACLAuthMethod{
+				Name:          "mock-auth-method",
+				Type:          "CERT",
+				TokenLocality: "local",
+				MaxTokenTTL:   goodTTL,
+				Config:        &ACLAuthMethodConfig{CertCACerts: []string{caPEM}},
+			},
+			false,
+			"",
+		},
+		{"missing cert config", &ACLAuthMethod{Type: "CERT"}, true, "missing cert config"},
+		{
+			"missing cert CA certs",
+			&ACLAuthMethod{Type: "CERT", Config: &ACLAuthMethodConfig{}},
+			true,
+			"missing CA certs",
+		},
+		{
+			"invalid cert CA certs",
+			&ACLAuthMethod{Type: "CERT", Config: &ACLAuthMethodConfig{CertCACerts: []string{"not a cert"}}},
+			true,
+			"invalid CA cert 0",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1385,6 +1649,28 @@ func TestACLAuthMethod_TokenLocalityIsGlobal(t *testing.T) {
 	must.False(t, localAuthMethod.TokenLocalityIsGlobal())
 }
 
+func TestACLAuthMethod_NewToken(t *testing.T) {
+	ci.Parallel(t)
+
+	authMethod := &ACLAuthMethod{
+		TokenLocality: ACLAuthMethodTokenLocalityGlobal,
+		MaxTokenTTL:   8 * time.Hour,
+	}
+	token := authMethod.NewToken("alice")
+	must.Eq(t, "alice", token.Name)
+	must.True(t, token.Global)
+	must.Eq(t, 8*time.Hour, token.ExpirationTTL)
+	must.False(t, token.Renewable)
+	must.Zero(t, token.MaxTTL)
+
+	authMethod.TokenTTL = time.Hour
+	authMethod.TokenRenewable = true
+	token = authMethod.NewToken("alice")
+	must.Eq(t, time.Hour, token.ExpirationTTL)
+	must.True(t, token.Renewable)
+	must.Eq(t, 8*time.Hour, token.MaxTTL)
+}
+
 func TestACLBindingRule_Canonicalize(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1767,4 +2053,22 @@ func TestACLOIDCCompleteAuthRequest_Validate(t *testing.T) {
 	must.StrContains(t, err.Error(), "missing state")
 	must.StrContains(t, err.Error(), "missing code")
 	must.StrContains(t, err.Error(), "missing redirect URI")
+
+	// Requests polling for a device authorization only need the auth method
+	// name and the device code.
+	testRequest = &ACLOIDCCompleteAuthRequest{
+		AuthMethodName: "oidc",
+		DeviceCode:     "device-code",
+	}
+	must.NoError(t, testRequest.Validate())
+}
+
+func TestACLOIDCDeviceAuthRequest_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testRequest := &ACLOIDCDeviceAuthRequest{}
+	must.ErrorContains(t, testRequest.Validate(), "missing auth method name")
+
+	testRequest.AuthMethodName = "oidc"
+	must.NoError(t, testRequest.Validate())
 }
@@ -5,8 +5,10 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"math"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -66,6 +68,31 @@ type ArtifactConfig struct {
 	// variable names to inherit from the Nomad Client and set in the artifact
 	// download sandbox process.
 	SetEnvironmentVariables *string `hcl:"set_environment_variables"`
+
+	// RequireSignature rejects artifacts that don't have a signature block,
+	// so that every artifact must be signed by a trusted key.
+	RequireSignature *bool `hcl:"require_signature"`
+
+	// TrustedKeys maps the names of the public keys trusted to sign
+	// artifacts to the paths of their files. Minisign public keys and
+	// PEM-encoded ECDSA public keys are supported.
+	TrustedKeys map[string]string `hcl:"trusted_keys"`
+
+	// CacheEnabled enables the cache of downloaded artifacts shared by every
+	// allocation on the client. Defaults to false.
+	CacheEnabled *bool `hcl:"cache_enabled"`
+
+	// CacheMaxSize is the maximum size of the artifact cache, beyond which
+	// the least recently used artifacts are evicted. Defaults to 10GB.
+	CacheMaxSize *string `hcl:"cache_max_size"`
+
+	// OCIAuth maps the hosts of OCI registries to the credentials used to
+	// pull artifacts from them, in the form username:password.
+	OCIAuth map[string]string `hcl:"oci_auth"`
+
+	// OCIAuthFile is the path to a Docker-style credentials file holding the
+	// credentials of OCI registries not set in OCIAuth.
+	OCIAuthFile *string `hcl:"oci_auth_file"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -84,6 +111,12 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 		DisableFilesystemIsolation:    pointer.Copy(a.DisableFilesystemIsolation),
 		FilesystemIsolationExtraPaths: slices.Clone(a.FilesystemIsolationExtraPaths),
 		SetEnvironmentVariables:       pointer.Copy(a.SetEnvironmentVariables),
+		RequireSignature:              pointer.Copy(a.RequireSignature),
+		TrustedKeys:                   maps.Clone(a.TrustedKeys),
+		CacheEnabled:                  pointer.Copy(a.CacheEnabled),
+		CacheMaxSize:                  pointer.Copy(a.CacheMaxSize),
+		OCIAuth:                       maps.Clone(a.OCIAuth),
+		OCIAuthFile:                   pointer.Copy(a.OCIAuthFile),
 	}
 }
 
@@ -105,6 +138,10 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 			DecompressionSizeLimit:      pointer.Merge(a.DecompressionSizeLimit, o.DecompressionSizeLimit),
 			DisableFilesystemIsolation:  pointer.Merge(a.DisableFilesystemIsolation, o.DisableFilesystemIsolation),
 			SetEnvironmentVariables:     pointer.Merge(a.SetEnvironmentVariables, o.SetEnvironmentVariables),
+			RequireSignature:            pointer.Merge(a.RequireSignature, o.RequireSignature),
+			CacheEnabled:                pointer.Merge(a.CacheEnabled, o.CacheEnabled),
+			CacheMaxSize:                pointer.Merge(a.CacheMaxSize, o.CacheMaxSize),
+			OCIAuthFile:                 pointer.Merge(a.OCIAuthFile, o.OCIAuthFile),
 		}
 
 		if o.FilesystemIsolationExtraPaths != nil {
@@ -113,6 +150,18 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 			result.FilesystemIsolationExtraPaths = slices.Clone(a.FilesystemIsolationExtraPaths)
 		}
 
+		if o.TrustedKeys != nil {
+			result.TrustedKeys = maps.Clone(o.TrustedKeys)
+		} else {
+			result.TrustedKeys = maps.Clone(a.TrustedKeys)
+		}
+
+		if o.OCIAuth != nil {
+			result.OCIAuth = maps.Clone(o.OCIAuth)
+		} else {
+			result.OCIAuth = maps.Clone(a.OCIAuth)
+		}
+
 		return result
 	}
 }
@@ -144,6 +193,18 @@ func (a *ArtifactConfig) Equal(o *ArtifactConfig) bool {
 		return false
 	case !pointer.Eq(a.SetEnvironmentVariables, o.SetEnvironmentVariables):
 		return false
+	case !pointer.Eq(a.RequireSignature, o.RequireSignature):
+		return false
+	case !maps.Equal(a.TrustedKeys, o.TrustedKeys):
+		return false
+	case !pointer.Eq(a.CacheEnabled, o.CacheEnabled):
+		return false
+	case !pointer.Eq(a.CacheMaxSize, o.CacheMaxSize):
+		return false
+	case !maps.Equal(a.OCIAuth, o.OCIAuth):
+		return false
+	case !pointer.Eq(a.OCIAuthFile, o.OCIAuthFile):
+		return false
 	}
 	return true
 }
@@ -237,6 +298,45 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("set_environment_variables must be set")
 	}
 
+	if a.RequireSignature == nil {
+		return fmt.Errorf("require_signature must be set")
+	}
+
+	for name, path := range a.TrustedKeys {
+		if name == "" {
+			return fmt.Errorf("trusted_keys names must not be empty")
+		}
+		if path == "" {
+			return fmt.Errorf("trusted_keys path of key %q must not be empty", name)
+		}
+	}
+
+	if a.CacheEnabled == nil {
+		return fmt.Errorf("cache_enabled must be set")
+	}
+
+	if a.CacheMaxSize == nil {
+		return fmt.Errorf("cache_max_size must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.CacheMaxSize); err != nil {
+		return fmt.Errorf("cache_max_size is not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("cache_max_size must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	for registry, auth := range a.OCIAuth {
+		if registry == "" {
+			return fmt.Errorf("oci_auth registries must not be empty")
+		}
+		if !strings.Contains(auth, ":") {
+			return fmt.Errorf("oci_auth credentials of registry %q must be of the form username:password", registry)
+		}
+	}
+
+	if a.OCIAuthFile == nil {
+		return fmt.Errorf("oci_auth_file must be set")
+	}
+
 	return nil
 }
 
@@ -283,5 +383,24 @@ func DefaultArtifactConfig() *ArtifactConfig {
 
 		// No environment variables are inherited from Client by default.
 		SetEnvironmentVariables: pointer.Of(""),
+
+		// Unsigned artifacts are allowed by default.
+		RequireSignature: pointer.Of(false),
+
+		// No keys are trusted to sign artifacts by default.
+		TrustedKeys: nil,
+
+		// Artifacts are downloaded into each task directory by default.
+		CacheEnabled: pointer.Of(false),
+
+		// Maximum size of the artifact cache. Must be large enough to hold
+		// the artifacts shared by the allocations on the client.
+		CacheMaxSize: pointer.Of("10GB"),
+
+		// No credentials for OCI registries by default.
+		OCIAuth: nil,
+
+		// No credentials file for OCI registries by default.
+		OCIAuthFile: pointer.Of(""),
 	}
 }
@@ -499,6 +499,48 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expErr: "set_environment_variables must be set",
 		},
+		{
+			name: "cache enabled not set",
+			config: func(a *ArtifactConfig) {
+				a.CacheEnabled = nil
+			},
+			expErr: "cache_enabled must be set",
+		},
+		{
+			name: "cache max size not set",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = nil
+			},
+			expErr: "cache_max_size must be set",
+		},
+		{
+			name: "cache max size not a valid size",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = pointer.Of("huge")
+			},
+			expErr: "cache_max_size is not a valid size",
+		},
+		{
+			name: "oci auth registry empty",
+			config: func(a *ArtifactConfig) {
+				a.OCIAuth = map[string]string{"": "user:pass"}
+			},
+			expErr: "oci_auth registries must not be empty",
+		},
+		{
+			name: "oci auth without password",
+			config: func(a *ArtifactConfig) {
+				a.OCIAuth = map[string]string{"registry.example.com": "user"}
+			},
+			expErr: "must be of the form username:password",
+		},
+		{
+			name: "oci auth file not set",
+			config: func(a *ArtifactConfig) {
+				a.OCIAuthFile = nil
+			},
+			expErr: "oci_auth_file must be set",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// KEKProviderAEAD is the default provider, which stores the key encryption
+	// key alongside the root key in the keystore.
+	KEKProviderAEAD = "aead"
+
+	// KEKProviderTransit wraps key encryption keys with a Vault Transit
+	// compatible HTTP API.
+	KEKProviderTransit = "transit"
+
+	// KEKProviderPKCS11 wraps key encryption keys with an AES key held in a
+	// PKCS#11 hardware security module.
+	KEKProviderPKCS11 = "pkcs11"
+
+	// KEKProviderPlugin wraps key encryption keys with an external KMS plugin.
+	KEKProviderPlugin = "plugin"
+)
+
+// KEKProviderConfig is the configuration for a provider that wraps the key
+// encryption keys (KEK) protecting root keys in the on-disk keystore.
+type KEKProviderConfig struct {
+	// Provider is the type of provider: aead, transit, pkcs11, or plugin.
+	Provider string `hcl:",key"`
+
+	// Name distinguishes multiple providers of the same type. Optional.
+	Name string `hcl:"name"`
+
+	// Active marks the provider tried first when loading the keystore. When
+	// more than one provider is configured, keys are written for all of them
+	// so that operators can migrate between providers.
+	Active bool `hcl:"active"`
+
+	// Config is the provider specific configuration.
+	Config map[string]string `hcl:"config"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// ID returns the unique identifier of the provider, which is recorded in the
+// keystore file names.
+func (k *KEKProviderConfig) ID() string {
+	if k.Name == "" {
+		return k.Provider
+	}
+	return k.Provider + "." + k.Name
+}
+
+func (k *KEKProviderConfig) Copy() *KEKProviderConfig {
+	if k == nil {
+		return nil
+	}
+
+	nk := *k
+	nk.Config = maps.Clone(k.Config)
+	return &nk
+}
+
+func (k *KEKProviderConfig) Validate() error {
+	if k == nil {
+		return nil
+	}
+
+	var mErr *multierror.Error
+	switch k.Provider {
+	case KEKProviderAEAD:
+	case KEKProviderTransit:
+		if k.Config["key_name"] == "" {
+			mErr = multierror.Append(mErr, fmt.Errorf("transit provider requires key_name"))
+		}
+	case KEKProviderPKCS11:
+		for _, key := range []string{"lib", "key_label"} {
+			if k.Config[key] == "" {
+				mErr = multierror.Append(mErr, fmt.Errorf("pkcs11 provider requires %s", key))
+			}
+		}
+	case KEKProviderPlugin:
+		if k.Config["command"] == "" {
+			mErr = multierror.Append(mErr, fmt.Errorf("plugin provider requires command"))
+		}
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("unknown keyring provider %q", k.Provider))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ValidateKEKProviders checks a set of keyring providers for errors, including
+// duplicate IDs and the number of active providers.
+func ValidateKEKProviders(providers []*KEKProviderConfig) error {
+	var mErr *multierror.Error
+	seen := map[string]struct{}{}
+	active := 0
+	for _, p := range providers {
+		if err := p.Validate(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("keyring %q: %w", p.ID(), err))
+		}
+		if _, ok := seen[p.ID()]; ok {
+			mErr = multierror.Append(mErr, fmt.Errorf("keyring %q is defined more than once", p.ID()))
+		}
+		seen[p.ID()] = struct{}{}
+		if p.Active {
+			active++
+		}
+	}
+	if len(providers) > 1 && active != 1 {
+		mErr = multierror.Append(mErr,
+			fmt.Errorf("exactly one keyring provider must be active, found %d", active))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ActiveKEKProvider returns the active keyring provider, which is the only
+// provider if just one is configured. It returns nil if no provider is
+// configured, in which case the default aead provider is used.
+func ActiveKEKProvider(providers []*KEKProviderConfig) *KEKProviderConfig {
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	for _, p := range providers {
+		if p.Active {
+			return p
+		}
+	}
+	return nil
+}
+
+// KEKProvidersMerge merges two sets of keyring providers, replacing providers
+// in a with those in b that have the same ID.
+func KEKProvidersMerge(a, b []*KEKProviderConfig) []*KEKProviderConfig {
+	n := make([]*KEKProviderConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.ID()] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.ID()]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	return n
+}
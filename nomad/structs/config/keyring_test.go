@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestKEKProviders_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	transit := &KEKProviderConfig{
+		Provider: KEKProviderTransit,
+		Active:   true,
+		Config:   map[string]string{"key_name": "nomad"},
+	}
+
+	cases := []struct {
+		name      string
+		providers []*KEKProviderConfig
+		expectErr string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:      "single provider need not be active",
+			providers: []*KEKProviderConfig{{Provider: KEKProviderAEAD}},
+		},
+		{
+			name:      "migration",
+			providers: []*KEKProviderConfig{{Provider: KEKProviderAEAD}, transit},
+		},
+		{
+			name: "no active provider",
+			providers: []*KEKProviderConfig{
+				{Provider: KEKProviderAEAD},
+				{Provider: KEKProviderAEAD, Name: "other"},
+			},
+			expectErr: "exactly one keyring provider must be active, found 0",
+		},
+		{
+			name:      "duplicate",
+			providers: []*KEKProviderConfig{transit, transit},
+			expectErr: `keyring "transit" is defined more than once`,
+		},
+		{
+			name:      "unknown",
+			providers: []*KEKProviderConfig{{Provider: "awskms"}},
+			expectErr: `unknown keyring provider "awskms"`,
+		},
+		{
+			name:      "transit without key",
+			providers: []*KEKProviderConfig{{Provider: KEKProviderTransit}},
+			expectErr: "transit provider requires key_name",
+		},
+		{
+			name:      "pkcs11 without lib",
+			providers: []*KEKProviderConfig{{Provider: KEKProviderPKCS11, Config: map[string]string{"key_label": "nomad"}}},
+			expectErr: "pkcs11 provider requires lib",
+		},
+		{
+			name:      "plugin without command",
+			providers: []*KEKProviderConfig{{Provider: KEKProviderPlugin}},
+			expectErr: "plugin provider requires command",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateKEKProviders(tc.providers)
+			if tc.expectErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestKEKProviders_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := []*KEKProviderConfig{
+		{Provider: KEKProviderAEAD, Active: true},
+	}
+	b := []*KEKProviderConfig{
+		{Provider: KEKProviderAEAD},
+		{Provider: KEKProviderTransit, Name: "vault", Active: true},
+	}
+
+	merged := KEKProvidersMerge(a, b)
+	must.Len(t, 2, merged)
+	must.False(t, merged[0].Active)
+	must.Eq(t, "transit.vault", merged[1].ID())
+
+	// the merged providers are copies
+	merged[0].Active = true
+	must.False(t, b[0].Active)
+}
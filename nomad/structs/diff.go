@@ -344,6 +344,11 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
+	// Template restart diff
+	if trDiff := primitiveObjectDiff(tg.TemplateRestart, other.TemplateRestart, nil, "TemplateRestart", contextual); trDiff != nil {
+		diff.Objects = append(diff.Objects, trDiff)
+	}
+
 	// Disconnect diff
 	if disconnectDiff := disconectStrategyDiffs(tg.Disconnect, other.Disconnect, contextual); disconnectDiff != nil {
 		diff.Objects = append(diff.Objects, disconnectDiff)
@@ -538,12 +543,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// Artifacts diff
-	diffs := primitiveObjectSetDiff(
-		interfaceSlice(t.Artifacts),
-		interfaceSlice(other.Artifacts),
-		nil,
-		"Artifact",
-		contextual)
+	diffs := artifactDiffs(t.Artifacts, other.Artifacts, contextual)
 	if diffs != nil {
 		diff.Objects = append(diff.Objects, diffs...)
 	}
@@ -559,6 +559,11 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, vDiff)
 	}
 
+	// Nomad diff
+	if nDiff := nomadDiff(t.Nomad, other.Nomad, contextual); nDiff != nil {
+		diff.Objects = append(diff.Objects, nDiff)
+	}
+
 	// Consul diff
 	consulDiff := primitiveObjectDiff(t.Consul, other.Consul, nil, "Consul", contextual)
 	if consulDiff != nil {
@@ -571,6 +576,11 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, tmplDiffs...)
 	}
 
+	// Secret diff
+	if secretDiffs := secretSliceDiffs(t.Secrets, other.Secrets, contextual); secretDiffs != nil {
+		diff.Objects = append(diff.Objects, secretDiffs...)
+	}
+
 	// Identity diff
 	idDiffs := idDiff(t.Identity, other.Identity, contextual)
 	if idDiffs != nil {
@@ -1995,6 +2005,130 @@ func vaultDiff(old, new *Vault, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// artifactDiffs returns the diffs of two sets of artifacts, matched by their
+// destination. If contextual diff is enabled, all fields will be returned,
+// even if no diff occurred.
+func artifactDiffs(old, new []*TaskArtifact, contextual bool) []*ObjectDiff {
+	oldSet := make(map[string]*TaskArtifact, len(old))
+	for _, artifact := range old {
+		oldSet[artifact.DiffID()] = artifact
+	}
+	newSet := make(map[string]*TaskArtifact, len(new))
+	for _, artifact := range new {
+		newSet[artifact.DiffID()] = artifact
+	}
+
+	var diffs []*ObjectDiff
+	for id, oldArtifact := range oldSet {
+		if diff := artifactDiff(oldArtifact, newSet[id], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	for id, newArtifact := range newSet {
+		if _, ok := oldSet[id]; !ok {
+			diffs = append(diffs, artifactDiff(nil, newArtifact, contextual))
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// artifactDiff returns the diff of two artifacts, including their signature
+// blocks.
+func artifactDiff(old, new *TaskArtifact, contextual bool) *ObjectDiff {
+	var oldObj, newObj interface{}
+	var oldSig, newSig *TaskArtifactSignature
+	if old != nil {
+		oldObj = old
+		oldSig = old.Signature
+	}
+	if new != nil {
+		newObj = new
+		newSig = new.Signature
+	}
+
+	diff := primitiveObjectDiff(oldObj, newObj, nil, "Artifact", contextual)
+
+	sigDiff := artifactSignatureDiff(oldSig, newSig, contextual)
+	if sigDiff == nil {
+		return diff
+	}
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Artifact"}
+		diff.Fields = fieldDiffs(flatmap.Flatten(oldObj, nil, true), flatmap.Flatten(newObj, nil, true), contextual)
+	}
+	diff.Objects = append(diff.Objects, sigDiff)
+	return diff
+}
+
+// artifactSignatureDiff returns the diff of two artifact signature objects.
+// If contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func artifactSignatureDiff(old, new *TaskArtifactSignature, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Signature"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &TaskArtifactSignature{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &TaskArtifactSignature{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Keys diffs
+	if setDiff := stringSetDiff(old.Keys, new.Keys, "Keys", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
+	return diff
+}
+
+// nomadDiff returns the diff of two nomad objects. If contextual diff is
+// enabled, all fields will be returned, even if no diff occurred.
+func nomadDiff(old, new *Nomad, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Nomad"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &Nomad{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &Nomad{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Roles diffs
+	if setDiff := stringSetDiff(old.Roles, new.Roles, "Roles", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
+	return diff
+}
+
 // waitConfigDiff returns the diff of two WaitConfig objects. If contextual diff is
 // enabled, all fields will be returned, even if no diff occurred.
 func waitConfigDiff(old, new *WaitConfig, contextual bool) *ObjectDiff {
@@ -2663,6 +2797,36 @@ func (n *NetworkResource) Diff(other *NetworkResource, contextual bool) *ObjectD
 		diff.Objects = append(diff.Objects, dnsDiff)
 	}
 
+	if bwDiff := n.Bandwidth.Diff(other.Bandwidth, contextual); bwDiff != nil {
+		diff.Objects = append(diff.Objects, bwDiff)
+	}
+
+	return diff
+}
+
+// Diff returns a diff of two NetworkBandwidth structs
+func (b *NetworkBandwidth) Diff(other *NetworkBandwidth, contextual bool) *ObjectDiff {
+	if reflect.DeepEqual(b, other) {
+		return nil
+	}
+
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Bandwidth"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+	if b == nil {
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(other, nil, true)
+	} else if other == nil {
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(b, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(b, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(other, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
 	return diff
 }
 
@@ -2932,6 +3096,79 @@ func configDiff(old, new map[string]interface{}, contextual bool) *ObjectDiff {
 // idSliceDiff returns the diff of two slices of identity objects. If
 // contextual diff is enabled, all fields will be returned, even if no diff
 // occurred.
+func secretSliceDiffs(old, new []*Secret, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*Secret, len(old))
+	newMap := make(map[string]*Secret, len(new))
+
+	for _, o := range old {
+		oldMap[o.Name] = o
+	}
+	for _, n := range new {
+		newMap[n.Name] = n
+	}
+
+	var diffs []*ObjectDiff
+	for name, oldSecret := range oldMap {
+		// Diff the same, deleted, and edited
+		if diff := secretDiff(oldSecret, newMap[name], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	for name, newSecret := range newMap {
+		// Diff the added
+		if _, exists := oldMap[name]; !exists {
+			if diff := secretDiff(nil, newSecret, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// secretDiff returns the diff of two secret objects. If contextual diff is
+// enabled, all fields will be returned, even if no diff occurred.
+func secretDiff(old, new *Secret, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Secret"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Add the pointer primitive fields.
+	if old != nil {
+		if old.Uid != nil {
+			oldPrimitiveFlat["Uid"] = fmt.Sprintf("%v", *old.Uid)
+		}
+		if old.Gid != nil {
+			oldPrimitiveFlat["Gid"] = fmt.Sprintf("%v", *old.Gid)
+		}
+	}
+	if new != nil {
+		if new.Uid != nil {
+			newPrimitiveFlat["Uid"] = fmt.Sprintf("%v", *new.Uid)
+		}
+		if new.Gid != nil {
+			newPrimitiveFlat["Gid"] = fmt.Sprintf("%v", *new.Gid)
+		}
+	}
+
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+	return diff
+}
+
 func idSliceDiffs(old, new []*WorkloadIdentity, contextual bool) []*ObjectDiff {
 	oldMap := make(map[string]*WorkloadIdentity, len(old))
 	newMap := make(map[string]*WorkloadIdentity, len(new))
@@ -5955,6 +5955,83 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Artifact signature edited",
+			Old: &Task{
+				Artifacts: []*TaskArtifact{
+					{
+						GetterSource: "foo",
+						RelativeDest: "foo",
+						Signature: &TaskArtifactSignature{
+							Format: ArtifactSignatureFormatMinisign,
+							Keys:   []string{"release"},
+						},
+					},
+				},
+			},
+			New: &Task{
+				Artifacts: []*TaskArtifact{
+					{
+						GetterSource: "foo",
+						RelativeDest: "foo",
+						Signature: &TaskArtifactSignature{
+							Source: "foo.sig",
+							Format: ArtifactSignatureFormatCosign,
+							Keys:   []string{"ci"},
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Artifact",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Signature",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "Format",
+										Old:  "minisign",
+										New:  "cosign",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Source",
+										Old:  "",
+										New:  "foo.sig",
+									},
+								},
+								Objects: []*ObjectDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "Keys",
+										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeAdded,
+												Name: "Keys",
+												Old:  "",
+												New:  "ci",
+											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "Keys",
+												Old:  "release",
+												New:  "",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Resources edited (no networks)",
 			Old: &Task{
@@ -9109,6 +9186,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "X509",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 				},
@@ -9145,6 +9228,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "X509",
+								Old:  "false",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -9252,6 +9341,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "3600000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "X509",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 				},
@@ -9383,6 +9478,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "3600000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "X509",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 					{
@@ -9427,6 +9528,96 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "X509",
+								Old:  "false",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "Secrets edited",
+			Old: &Task{
+				Secrets: []*Secret{
+					{
+						Name:       "db",
+						Provider:   SecretProviderNomad,
+						Path:       "db",
+						ChangeMode: SecretChangeModeRestart,
+					},
+				},
+			},
+			New: &Task{
+				Secrets: []*Secret{
+					{
+						Name:       "db",
+						Provider:   SecretProviderNomad,
+						Path:       "db",
+						ChangeMode: SecretChangeModeRestart,
+						Uid:        pointer.Of(1000),
+					},
+					{
+						Name:       "api",
+						Provider:   SecretProviderNomad,
+						Path:       "api",
+						Env:        true,
+						ChangeMode: SecretChangeModeNoop,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Secret",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Uid",
+								Old:  "",
+								New:  "1000",
+							},
+						},
+					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Secret",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "ChangeMode",
+								Old:  "",
+								New:  "noop",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Env",
+								Old:  "",
+								New:  "true",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Name",
+								Old:  "",
+								New:  "api",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Path",
+								Old:  "",
+								New:  "api",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Provider",
+								Old:  "",
+								New:  "nomad",
+							},
 						},
 					},
 				},
@@ -26,6 +26,8 @@ const (
 	errMissingAllocID             = "Missing allocation ID"
 	errIncompatibleFiltering      = "Filter expression cannot be used with other filter parameters"
 	errMalformedChooseParameter   = "Parameter for choose must be in form '<number>|<key>'"
+	errOIDCDeviceAuthPending      = "OIDC device authorization pending"
+	errOIDCDeviceAuthSlowDown     = "OIDC device authorization polled too often"
 
 	// Prefix based errors that are used to check if the error is of a given
 	// type. These errors should be created with the associated constructor.
@@ -64,6 +66,8 @@ var (
 	ErrMissingAllocID             = errors.New(errMissingAllocID)
 	ErrIncompatibleFiltering      = errors.New(errIncompatibleFiltering)
 	ErrMalformedChooseParameter   = errors.New(errMalformedChooseParameter)
+	ErrOIDCDeviceAuthPending      = errors.New(errOIDCDeviceAuthPending)
+	ErrOIDCDeviceAuthSlowDown     = errors.New(errOIDCDeviceAuthSlowDown)
 
 	ErrUnknownNode = errors.New(ErrUnknownNodePrefix)
 
@@ -119,6 +123,18 @@ func IsErrUnknownMethod(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errUnknownMethod)
 }
 
+// IsErrOIDCDeviceAuthPending returns whether the error is due to the user not
+// having completed the OIDC device authorization yet.
+func IsErrOIDCDeviceAuthPending(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errOIDCDeviceAuthPending)
+}
+
+// IsErrOIDCDeviceAuthSlowDown returns whether the error is due to the OIDC
+// device authorization being polled too often.
+func IsErrOIDCDeviceAuthSlowDown(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errOIDCDeviceAuthSlowDown)
+}
+
 func IsErrRPCCoded(err error) bool {
 	return err != nil && strings.HasPrefix(err.Error(), errRPCCodedErrorPrefix)
 }
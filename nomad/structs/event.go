@@ -31,6 +31,13 @@ const (
 	TopicACLAuthMethod  Topic = "ACLAuthMethod"
 	TopicACLBindingRule Topic = "ACLBindingRule"
 	TopicService        Topic = "Service"
+	TopicVariable       Topic = "Variable"
+	TopicNamespace      Topic = "Namespace"
+	TopicCSIVolume      Topic = "CSIVolume"
+	TopicCSIPlugin      Topic = "CSIPlugin"
+	TopicScalingPolicy  Topic = "ScalingPolicy"
+	TopicScalingEvent   Topic = "ScalingEvent"
+	TopicRootKey        Topic = "RootKey"
 	TopicAll            Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
@@ -63,6 +70,20 @@ const (
 	TypeACLBindingRuleDeleted         = "ACLBindingRuleDeleted"
 	TypeServiceRegistration           = "ServiceRegistration"
 	TypeServiceDeregistration         = "ServiceDeregistration"
+	TypeVariableUpserted              = "VariableUpserted"
+	TypeVariableDeleted               = "VariableDeleted"
+	TypeNamespaceUpserted             = "NamespaceUpserted"
+	TypeNamespaceDeleted              = "NamespaceDeleted"
+	TypeCSIVolumeUpserted             = "CSIVolumeUpserted"
+	TypeCSIVolumeDeleted              = "CSIVolumeDeleted"
+	TypeCSIPluginUpserted             = "CSIPluginUpserted"
+	TypeCSIPluginDeleted              = "CSIPluginDeleted"
+	TypeScalingPolicyUpserted         = "ScalingPolicyUpserted"
+	TypeScalingPolicyDeleted          = "ScalingPolicyDeleted"
+	TypeScalingEventRegistered        = "ScalingEventRegistered"
+	TypeScalingEventDeleted           = "ScalingEventDeleted"
+	TypeRootKeyUpserted               = "RootKeyUpserted"
+	TypeRootKeyDeleted                = "RootKeyDeleted"
 )
 
 // Event represents a change in Nomads state.
@@ -189,3 +210,57 @@ type ACLAuthMethodEvent struct {
 type ACLBindingRuleEvent struct {
 	ACLBindingRule *ACLBindingRule
 }
+
+// VariableStreamEvent holds the metadata of a newly updated or deleted
+// variable. The encrypted data of the variable is never included and the lock
+// ID is removed since it grants the lock holder's privileges.
+type VariableStreamEvent struct {
+	Variable *VariableMetadata
+}
+
+// NewVariableStreamEvent takes an encrypted variable and creates a new
+// VariableStreamEvent from a copy of its metadata.
+func NewVariableStreamEvent(v *VariableEncrypted) *VariableStreamEvent {
+	meta := v.VariableMetadata
+	if meta.Lock != nil {
+		lock := *meta.Lock
+		lock.ID = ""
+		meta.Lock = &lock
+	}
+	return &VariableStreamEvent{
+		Variable: &meta,
+	}
+}
+
+// NamespaceEvent holds a newly updated or deleted namespace.
+type NamespaceEvent struct {
+	Namespace *Namespace
+}
+
+// CSIVolumeEvent holds the stub of a newly updated or deleted CSI volume. The
+// stub is used since the volume may contain secrets.
+type CSIVolumeEvent struct {
+	Volume *CSIVolListStub
+}
+
+// CSIPluginEvent holds the stub of a newly updated or deleted CSI plugin.
+type CSIPluginEvent struct {
+	Plugin *CSIPluginListStub
+}
+
+// ScalingPolicyEvent holds a newly updated or deleted scaling policy.
+type ScalingPolicyEvent struct {
+	ScalingPolicy *ScalingPolicy
+}
+
+// ScalingEventStreamEvent holds the scaling events of a job when a new event
+// is registered or the events are deleted.
+type ScalingEventStreamEvent struct {
+	ScalingEvents *JobScalingEvents
+}
+
+// RootKeyEvent holds the metadata of a newly updated or deleted root key. The
+// key material is never included.
+type RootKeyEvent struct {
+	RootKeyMeta *RootKeyMeta
+}
@@ -0,0 +1,344 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/escapingfs"
+)
+
+// EventSinkType is the type of destination an event sink delivers events to.
+type EventSinkType string
+
+const (
+	// EventSinkWebhook sinks send batches of events as a JSON array in the
+	// body of an HTTP POST request.
+	EventSinkWebhook EventSinkType = "webhook"
+
+	// EventSinkFile sinks append batches of events as newline delimited JSON
+	// to a file on the leader.
+	EventSinkFile EventSinkType = "file"
+
+	// EventSinkExec sinks run a command on the leader for every batch of
+	// events and write the batch as newline delimited JSON to its stdin.
+	EventSinkExec EventSinkType = "exec"
+)
+
+const (
+	// DefaultEventSinkBatchSize is the maximum number of event sets sent to a
+	// sink in a single batch if the sink doesn't set one.
+	DefaultEventSinkBatchSize = 64
+
+	// DefaultEventSinkBatchInterval is the maximum amount of time events are
+	// held back to fill a batch if the sink doesn't set one.
+	DefaultEventSinkBatchInterval = time.Second
+
+	// maxEventSinkBatchSize is the largest batch size a sink may request.
+	maxEventSinkBatchSize = 4096
+)
+
+var (
+	// validEventSinkID is the rule used to validate an event sink ID.
+	validEventSinkID = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// EventSink is a server managed destination for the event stream. The leader
+// subscribes to the event broker on behalf of every sink and delivers events
+// at least once, recording the index of the last delivered event in raft so
+// delivery resumes from the same point after a leader election.
+type EventSink struct {
+	// ID is the unique name of the sink.
+	ID string
+
+	// Type is the kind of destination the sink delivers events to.
+	Type EventSinkType
+
+	// Topics is the set of topics and filter keys the sink subscribes to,
+	// using the same format as the event stream API.
+	Topics map[Topic][]string
+
+	// Namespace is the namespace events are filtered on. The wildcard
+	// namespace "*" includes events from all namespaces.
+	Namespace string
+
+	// Address is the URL webhook sinks send events to.
+	Address string
+
+	// Headers are additional HTTP headers set on webhook requests.
+	Headers map[string]string
+
+	// Path is the absolute path of the file that file sinks append events to.
+	Path string
+
+	// Command and Args are the command exec sinks run for every batch.
+	Command string
+	Args    []string
+
+	// BatchSize is the maximum number of event sets delivered in a single
+	// batch.
+	BatchSize int
+
+	// BatchInterval is the maximum amount of time events are held back while
+	// waiting for a batch to fill.
+	BatchInterval time.Duration
+
+	// LatestIndex is the raft index of the last event set that was delivered
+	// successfully.
+	LatestIndex uint64
+
+	// Gaps is the number of times events were dropped from the event buffer
+	// of the leader before they could be delivered, and LastGap is the most
+	// recent of these gaps. Events dropped in a gap are never delivered.
+	Gaps    uint64
+	LastGap *EventSinkGap
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Canonicalize sets the defaults of the sink.
+func (s *EventSink) Canonicalize() {
+	if len(s.Topics) == 0 {
+		s.Topics = map[Topic][]string{TopicAll: {"*"}}
+	}
+	if s.Namespace == "" {
+		s.Namespace = AllNamespacesSentinel
+	}
+	if s.BatchSize == 0 {
+		s.BatchSize = DefaultEventSinkBatchSize
+	}
+	if s.BatchInterval == 0 {
+		s.BatchInterval = DefaultEventSinkBatchInterval
+	}
+}
+
+// Validate returns an error if the sink is invalid.
+func (s *EventSink) Validate() error {
+	var mErr *multierror.Error
+
+	if !validEventSinkID.MatchString(s.ID) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid ID %q, must match regex %s", s.ID, validEventSinkID))
+	}
+
+	switch s.Type {
+	case EventSinkWebhook:
+		u, err := url.Parse(s.Address)
+		if err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("invalid webhook address: %w", err))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			mErr = multierror.Append(mErr, fmt.Errorf("webhook address must use the http or https scheme"))
+		}
+	case EventSinkFile:
+		if s.Path == "" || !filepath.IsAbs(s.Path) {
+			mErr = multierror.Append(mErr, errors.New("file sink path must be absolute"))
+		}
+	case EventSinkExec:
+		if s.Command == "" {
+			mErr = multierror.Append(mErr, errors.New("exec sink command must be set"))
+		}
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid type %q", s.Type))
+	}
+
+	for topic, keys := range s.Topics {
+		if topic == "" {
+			mErr = multierror.Append(mErr, errors.New("topic must not be empty"))
+		}
+		if len(keys) == 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("topic %q must have at least one filter key", topic))
+		}
+	}
+
+	if s.BatchSize < 0 || s.BatchSize > maxEventSinkBatchSize {
+		mErr = multierror.Append(mErr, fmt.Errorf("batch size must be between 1 and %d", maxEventSinkBatchSize))
+	}
+	if s.BatchInterval < 0 {
+		mErr = multierror.Append(mErr, errors.New("batch interval must not be negative"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the sink.
+func (s *EventSink) Copy() *EventSink {
+	if s == nil {
+		return nil
+	}
+
+	ns := new(EventSink)
+	*ns = *s
+
+	if s.Topics != nil {
+		ns.Topics = make(map[Topic][]string, len(s.Topics))
+		for topic, keys := range s.Topics {
+			ns.Topics[topic] = slices.Clone(keys)
+		}
+	}
+	ns.Headers = maps.Clone(s.Headers)
+	ns.Args = slices.Clone(s.Args)
+	ns.LastGap = s.LastGap.Copy()
+
+	return ns
+}
+
+// Stub returns a summary of the sink that does not include the delivery
+// configuration, which may hold credentials.
+func (s *EventSink) Stub() *EventSinkListStub {
+	topics := make(map[Topic][]string, len(s.Topics))
+	for topic, keys := range s.Topics {
+		topics[topic] = slices.Clone(keys)
+	}
+
+	return &EventSinkListStub{
+		ID:          s.ID,
+		Type:        s.Type,
+		Topics:      topics,
+		Namespace:   s.Namespace,
+		LatestIndex: s.LatestIndex,
+		CreateIndex: s.CreateIndex,
+		ModifyIndex: s.ModifyIndex,
+	}
+}
+
+// EventSinkAllowlist restricts the commands exec sinks may run and the
+// directories file sinks may write to. It is set in the agent configuration
+// of the servers, since these sinks run as the server process on the leader.
+type EventSinkAllowlist struct {
+	// Commands are the absolute paths of the commands exec sinks may run.
+	Commands []string
+
+	// Directories are the absolute paths of the directories file sinks may
+	// write to.
+	Directories []string
+}
+
+func (a *EventSinkAllowlist) Copy() *EventSinkAllowlist {
+	if a == nil {
+		return nil
+	}
+	return &EventSinkAllowlist{
+		Commands:    slices.Clone(a.Commands),
+		Directories: slices.Clone(a.Directories),
+	}
+}
+
+// Allows returns an error if the sink runs a command or writes to a file that
+// is not allowed. Webhook sinks are always allowed, and exec and file sinks
+// are refused by a nil allowlist.
+func (a *EventSinkAllowlist) Allows(sink *EventSink) error {
+	var commands, directories []string
+	if a != nil {
+		commands, directories = a.Commands, a.Directories
+	}
+
+	switch sink.Type {
+	case EventSinkFile:
+		path := filepath.Clean(sink.Path)
+		for _, dir := range directories {
+			dir = filepath.Clean(dir)
+			if path != dir && !escapingfs.PathEscapesSandbox(dir, path) {
+				return nil
+			}
+		}
+		return fmt.Errorf("file sink path %q is not in a directory allowed by the server configuration", sink.Path)
+	case EventSinkExec:
+		if slices.Contains(commands, sink.Command) {
+			return nil
+		}
+		return fmt.Errorf("exec sink command %q is not allowed by the server configuration", sink.Command)
+	}
+	return nil
+}
+
+// EventSinkListStub is the summary of an event sink returned when listing
+// sinks.
+type EventSinkListStub struct {
+	ID          string
+	Type        EventSinkType
+	Topics      map[Topic][]string
+	Namespace   string
+	LatestIndex uint64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EventSinkGap describes events that were dropped from the event buffer of the
+// leader before they could be delivered to a sink. The events published after
+// FromIndex and up to ToIndex may not have been delivered.
+type EventSinkGap struct {
+	FromIndex uint64
+	ToIndex   uint64
+	Time      time.Time
+}
+
+func (g *EventSinkGap) Copy() *EventSinkGap {
+	if g == nil {
+		return nil
+	}
+	ng := *g
+	return &ng
+}
+
+// EventSinkProgress is the index of the last event set delivered to a sink.
+// ModifyIndex is the version of the sink the events were delivered to. Gaps
+// is the number of gaps detected since the progress was last recorded, and
+// LastGap the most recent of them.
+type EventSinkProgress struct {
+	ID          string
+	ModifyIndex uint64
+	LatestIndex uint64
+	Gaps        uint64
+	LastGap     *EventSinkGap
+}
+
+// EventSinkListRequest is used to list all event sinks.
+type EventSinkListRequest struct {
+	QueryOptions
+}
+
+// EventSinkListResponse is the response to an EventSinkListRequest.
+type EventSinkListResponse struct {
+	Sinks []*EventSinkListStub
+	QueryMeta
+}
+
+// EventSinkSpecificRequest is used to read a single event sink.
+type EventSinkSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// EventSinkResponse is the response to an EventSinkSpecificRequest.
+type EventSinkResponse struct {
+	Sink *EventSink
+	QueryMeta
+}
+
+// EventSinkUpsertRequest is used to create or update event sinks.
+type EventSinkUpsertRequest struct {
+	Sinks []*EventSink
+	WriteRequest
+}
+
+// EventSinkDeleteRequest is used to delete event sinks.
+type EventSinkDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// EventSinkProgressRequest is used by the leader to record the delivery
+// progress of event sinks.
+type EventSinkProgressRequest struct {
+	Progress []*EventSinkProgress
+	WriteRequest
+}
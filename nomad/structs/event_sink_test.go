@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestEventSink_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	sink := &EventSink{ID: "sink", Type: EventSinkFile, Path: "/var/log/events.json"}
+	sink.Canonicalize()
+
+	must.Eq(t, map[Topic][]string{TopicAll: {"*"}}, sink.Topics)
+	must.Eq(t, AllNamespacesSentinel, sink.Namespace)
+	must.Eq(t, DefaultEventSinkBatchSize, sink.BatchSize)
+	must.Eq(t, DefaultEventSinkBatchInterval, sink.BatchInterval)
+}
+
+func TestEventSink_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		sink   *EventSink
+		expErr []string
+	}{
+		{
+			name: "valid webhook",
+			sink: &EventSink{ID: "hook", Type: EventSinkWebhook, Address: "https://example.com/events"},
+		},
+		{
+			name: "valid file",
+			sink: &EventSink{ID: "file", Type: EventSinkFile, Path: "/var/log/events.json"},
+		},
+		{
+			name: "valid exec",
+			sink: &EventSink{ID: "exec", Type: EventSinkExec, Command: "/usr/local/bin/ship"},
+		},
+		{
+			name: "invalid id and type",
+			sink: &EventSink{ID: "not valid", Type: "kafka"},
+			expErr: []string{
+				`invalid ID "not valid"`,
+				`invalid type "kafka"`,
+			},
+		},
+		{
+			name:   "webhook scheme",
+			sink:   &EventSink{ID: "hook", Type: EventSinkWebhook, Address: "ftp://example.com"},
+			expErr: []string{"must use the http or https scheme"},
+		},
+		{
+			name:   "relative file path",
+			sink:   &EventSink{ID: "file", Type: EventSinkFile, Path: "events.json"},
+			expErr: []string{"path must be absolute"},
+		},
+		{
+			name:   "exec without command",
+			sink:   &EventSink{ID: "exec", Type: EventSinkExec},
+			expErr: []string{"command must be set"},
+		},
+		{
+			name: "invalid topics and batching",
+			sink: &EventSink{
+				ID:            "file",
+				Type:          EventSinkFile,
+				Path:          "/var/log/events.json",
+				Topics:        map[Topic][]string{TopicJob: {}},
+				BatchSize:     maxEventSinkBatchSize + 1,
+				BatchInterval: -time.Second,
+			},
+			expErr: []string{
+				`topic "Job" must have at least one filter key`,
+				"batch size must be between",
+				"batch interval must not be negative",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.sink.Canonicalize()
+			err := tc.sink.Validate()
+			if len(tc.expErr) == 0 {
+				must.NoError(t, err)
+				return
+			}
+			must.Error(t, err)
+			for _, exp := range tc.expErr {
+				must.ErrorContains(t, err, exp)
+			}
+		})
+	}
+}
+
+func TestEventSink_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	sink := &EventSink{
+		ID:      "exec",
+		Type:    EventSinkExec,
+		Command: "ship",
+		Args:    []string{"-v"},
+		Headers: map[string]string{"a": "b"},
+		Topics:  map[Topic][]string{TopicJob: {"web"}},
+	}
+	c := sink.Copy()
+	must.Eq(t, sink, c)
+
+	c.Args[0] = "-q"
+	c.Headers["a"] = "c"
+	c.Topics[TopicJob][0] = "api"
+	must.Eq(t, "-v", sink.Args[0])
+	must.Eq(t, "b", sink.Headers["a"])
+	must.Eq(t, "web", sink.Topics[TopicJob][0])
+}
+
+func TestEventSinkAllowlist_Allows(t *testing.T) {
+	ci.Parallel(t)
+
+	allowlist := &EventSinkAllowlist{
+		Commands:    []string{"/usr/local/bin/ship"},
+		Directories: []string{"/var/log/nomad"},
+	}
+
+	testCases := []struct {
+		name      string
+		allowlist *EventSinkAllowlist
+		sink      *EventSink
+		expErr    string
+	}{
+		{
+			name:      "webhook always allowed",
+			allowlist: nil,
+			sink:      &EventSink{Type: EventSinkWebhook, Address: "https://example.com"},
+		},
+		{
+			name:      "file in allowed directory",
+			allowlist: allowlist,
+			sink:      &EventSink{Type: EventSinkFile, Path: "/var/log/nomad/events.json"},
+		},
+		{
+			name:      "file escaping allowed directory",
+			allowlist: allowlist,
+			sink:      &EventSink{Type: EventSinkFile, Path: "/var/log/nomad/../../../etc/passwd"},
+			expErr:    "not in a directory allowed",
+		},
+		{
+			name:      "file is allowed directory",
+			allowlist: allowlist,
+			sink:      &EventSink{Type: EventSinkFile, Path: "/var/log/nomad"},
+			expErr:    "not in a directory allowed",
+		},
+		{
+			name:      "file without allowlist",
+			allowlist: nil,
+			sink:      &EventSink{Type: EventSinkFile, Path: "/var/log/nomad/events.json"},
+			expErr:    "not in a directory allowed",
+		},
+		{
+			name:      "allowed command",
+			allowlist: allowlist,
+			sink:      &EventSink{Type: EventSinkExec, Command: "/usr/local/bin/ship"},
+		},
+		{
+			name:      "command not allowed",
+			allowlist: allowlist,
+			sink:      &EventSink{Type: EventSinkExec, Command: "/bin/sh"},
+			expErr:    "is not allowed",
+		},
+		{
+			name:      "command without allowlist",
+			allowlist: nil,
+			sink:      &EventSink{Type: EventSinkExec, Command: "/usr/local/bin/ship"},
+			expErr:    "is not allowed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.allowlist.Allows(tc.sink)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -259,6 +259,35 @@ func (j *Job) RequiredBridgeNetwork() set.Collection[string] {
 	return result
 }
 
+// RequiredBandwidthPlugin identifies which task groups, if any, within the job
+// limit the bandwidth of a bridge network.
+func (j *Job) RequiredBandwidthPlugin() set.Collection[string] {
+	result := set.New[string](len(j.TaskGroups))
+	for _, tg := range j.TaskGroups {
+		if len(tg.Networks) > 0 && tg.Networks[0].Mode == "bridge" &&
+			!tg.Networks[0].Bandwidth.IsZero() {
+			result.Insert(tg.Name)
+		}
+	}
+	return result
+}
+
+// RequiredHostNetworkPlugins identifies which task groups, if any, within the
+// job attach host networks to their allocations, which requires the CNI
+// macvlan and dhcp plugins.
+func (j *Job) RequiredHostNetworkPlugins() set.Collection[string] {
+	result := set.New[string](len(j.TaskGroups))
+	for _, tg := range j.TaskGroups {
+		for _, network := range tg.Networks {
+			if _, ok := network.AttachedHostNetwork(); ok {
+				result.Insert(tg.Name)
+				break
+			}
+		}
+	}
+	return result
+}
+
 // RequiredTransparentProxy identifies which task groups, if any, within the job
 // contain Connect blocks using transparent proxy
 func (j *Job) RequiredTransparentProxy() set.Collection[string] {
@@ -4,11 +4,15 @@
 package structs
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"math/big"
 	"net/url"
 	"time"
 
@@ -31,8 +35,21 @@ const (
 	// signatures.
 	PubKeyUseSig = "sig"
 
+	// PubKeyUseX509SVID is the JWK "use" parameter value for the CA
+	// certificates which sign X.509 SVIDs, as defined by the SPIFFE trust
+	// domain and bundle specification.
+	PubKeyUseX509SVID = "x509-svid"
+
 	// JWKSPath is the path component of the URL to Nomad's JWKS endpoint.
 	JWKSPath = "/.well-known/jwks.json"
+
+	// SPIFFEBundlePath is the path component of the URL to Nomad's SPIFFE
+	// trust bundle endpoint.
+	SPIFFEBundlePath = "/.well-known/spiffe-bundle.json"
+
+	// rootKeyCAValidity is how long the CA certificate of a root key is valid
+	// for. Root keys are rotated long before their CA expires.
+	rootKeyCAValidity = 10 * 365 * 24 * time.Hour
 )
 
 // RootKey is used to encrypt and decrypt variables. It is never stored in raft.
@@ -44,6 +61,15 @@ type RootKey struct {
 	// RS256 algorithm. It is stored in its PKCS #1, ASN.1 DER form. See
 	// x509.MarshalPKCS1PrivateKey for details.
 	RSAKey []byte
+
+	// CAKey is the private key of the CA used to sign X.509 SVIDs for
+	// workload identities. It is stored in its PKCS #8, ASN.1 DER form.
+	CAKey []byte
+
+	// CACert is the self-signed, ASN.1 DER encoded certificate of the CA
+	// used to sign X.509 SVIDs. It is generated along with the CAKey so that
+	// all servers share the same certificate.
+	CACert []byte
 }
 
 // NewRootKey returns a new root key and its metadata.
@@ -72,9 +98,58 @@ func NewRootKey(algorithm EncryptionAlgorithm) (*RootKey, error) {
 
 	rootKey.RSAKey = x509.MarshalPKCS1PrivateKey(rsaPrivateKey)
 
+	// Generate the CA for signing workload identity X.509 SVIDs.
+	caKey, caCert, err := newRootKeyCA(meta)
+	if err != nil {
+		return nil, err
+	}
+	rootKey.CAKey = caKey
+	rootKey.CACert = caCert
+
 	return rootKey, nil
 }
 
+// newRootKeyCA generates an ECDSA P-256 private key and a self-signed CA
+// certificate for the root key, and returns them ASN.1 DER encoded.
+func newRootKeyCA(meta *RootKeyMeta) ([]byte, []byte, error) {
+	caPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate ca key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate ca serial number: %w", err)
+	}
+
+	createTime := time.Unix(0, meta.CreateTime).UTC()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Nomad"},
+			CommonName:   "Nomad Workload Identity CA " + meta.KeyID,
+		},
+		NotBefore:             createTime.Add(-time.Minute),
+		NotAfter:              createTime.Add(rootKeyCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	caCert, err := x509.CreateCertificate(rand.Reader, template, template,
+		&caPrivateKey.PublicKey, caPrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate ca certificate: %w", err)
+	}
+
+	caKey, err := x509.MarshalPKCS8PrivateKey(caPrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode ca key: %w", err)
+	}
+	return caKey, caCert, nil
+}
+
 // RootKeyMeta is the metadata used to refer to a RootKey. It is
 // stored in raft.
 type RootKeyMeta struct {
@@ -198,7 +273,18 @@ type KeyEncryptionKeyWrapper struct {
 	Meta                       *RootKeyMeta
 	EncryptedDataEncryptionKey []byte `json:"DEK"`
 	EncryptedRSAKey            []byte `json:"RSAKey"`
+	EncryptedCAKey             []byte `json:"CAKey,omitempty"`
+	CACert                     []byte `json:"CACert,omitempty"`
 	KeyEncryptionKey           []byte `json:"KEK"`
+
+	// ProviderID is the ID of the keyring provider that wrapped the KEK. It
+	// is empty for the default aead provider, which stores the KEK in the
+	// clear.
+	ProviderID string `json:",omitempty"`
+
+	// WrappedKeyEncryptionKey is the KEK as encrypted by an external
+	// provider, in which case KeyEncryptionKey is empty.
+	WrappedKeyEncryptionKey []byte `json:"WrappedKEK,omitempty"`
 }
 
 // EncryptionAlgorithm chooses which algorithm is used for
@@ -318,6 +404,11 @@ type KeyringPublicKey struct {
 	// CreateTime + root_key_rotation_threshold = when consumers should look for
 	// a new key. Therefore this field can be used for cache control.
 	CreateTime int64
+
+	// X509CACert is the ASN.1 DER encoded certificate of the CA which signs
+	// X.509 SVIDs with this key. Keys created before X.509 SVIDs were
+	// supported have no CA.
+	X509CACert []byte
 }
 
 // GetPublicKey returns the concrete PublicKey type. This *must* be used to
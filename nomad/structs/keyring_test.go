@@ -4,6 +4,7 @@
 package structs
 
 import (
+	"crypto/x509"
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
@@ -31,3 +32,19 @@ func TestKeyring_OIDCDiscoveryConfig(t *testing.T) {
 	must.SliceNotEmpty(t, c.ResponseTypes)
 	must.SliceNotEmpty(t, c.Subjects)
 }
+
+func TestNewRootKey_CA(t *testing.T) {
+	ci.Parallel(t)
+
+	key, err := NewRootKey(EncryptionAlgorithmAES256GCM)
+	must.NoError(t, err)
+	must.SliceNotEmpty(t, key.CAKey)
+
+	cert, err := x509.ParseCertificate(key.CACert)
+	must.NoError(t, err)
+	must.True(t, cert.IsCA)
+	must.StrContains(t, cert.Subject.CommonName, key.Meta.KeyID)
+
+	_, err = x509.ParsePKCS8PrivateKey(key.CAKey)
+	must.NoError(t, err)
+}
@@ -3,6 +3,12 @@
 
 package structs
 
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
 // NamespaceVaultConfiguration stores configuration about permissions to Vault
 // clusters for a namespace, for use with Nomad Enterprise.
 type NamespaceVaultConfiguration struct {
@@ -46,3 +52,44 @@ type NamespaceConsulConfiguration struct {
 	// This field cannot be used with Allowed.
 	Denied []string
 }
+
+// NamespaceVariablesConfiguration stores configuration about variables for a
+// namespace.
+type NamespaceVariablesConfiguration struct {
+	// MaxVersions is the number of previous versions of each variable kept
+	// in the variable's history. Zero disables the history. If unset, the
+	// DefaultVariableMaxVersions is used.
+	MaxVersions *int
+}
+
+// Validate returns an error if the variables configuration is invalid.
+func (v *NamespaceVariablesConfiguration) Validate() error {
+	if v == nil || v.MaxVersions == nil {
+		return nil
+	}
+	if *v.MaxVersions < 0 || *v.MaxVersions > maxVariableMaxVersions {
+		return fmt.Errorf("max_versions must be between 0 and %d", maxVariableMaxVersions)
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the variables configuration.
+func (v *NamespaceVariablesConfiguration) Copy() *NamespaceVariablesConfiguration {
+	if v == nil {
+		return nil
+	}
+	nv := new(NamespaceVariablesConfiguration)
+	if v.MaxVersions != nil {
+		nv.MaxVersions = pointer.Of(*v.MaxVersions)
+	}
+	return nv
+}
+
+// VariableMaxVersions returns the number of previous versions of each
+// variable kept in the namespace.
+func (n *Namespace) VariableMaxVersions() int {
+	if n == nil || n.VariablesConfiguration == nil || n.VariablesConfiguration.MaxVersions == nil {
+		return DefaultVariableMaxVersions
+	}
+	return *n.VariablesConfiguration.MaxVersions
+}
@@ -67,6 +67,17 @@ type NetworkIndex struct {
 	AvailBandwidth map[string]int // Bandwidth by device
 	UsedBandwidth  map[string]int // Bandwidth by device
 
+	// GroupBandwidth is the link speed in megabits per second of the node's
+	// default host network. It is the capacity available to group.network
+	// bandwidth asks in each direction. Zero means the speed is unknown and
+	// bandwidth is not accounted for.
+	GroupBandwidth int
+
+	// UsedIngressBandwidth and UsedEgressBandwidth track the group.network
+	// bandwidth used by allocations in megabits per second.
+	UsedIngressBandwidth int
+	UsedEgressBandwidth  int
+
 	MinDynamicPort int // The smallest dynamic port generated
 	MaxDynamicPort int // The largest dynamic port generated
 }
@@ -184,14 +195,18 @@ func (idx *NetworkIndex) Release() {
 
 // Overcommitted checks if the network is overcommitted
 func (idx *NetworkIndex) Overcommitted() bool {
-	// TODO remove since bandwidth is deprecated
+	// Bandwidth of task networks is deprecated and not checked.
 	/*for device, used := range idx.UsedBandwidth {
 		avail := idx.AvailBandwidth[device]
 		if used > avail {
 			return true
 		}
 	}*/
-	return false
+	if idx.GroupBandwidth <= 0 {
+		return false
+	}
+	return idx.UsedIngressBandwidth > idx.GroupBandwidth ||
+		idx.UsedEgressBandwidth > idx.GroupBandwidth
 }
 
 // SetNode is used to initialize a node's network index with available IPs,
@@ -302,6 +317,12 @@ func (idx *NetworkIndex) SetNode(node *Node) error {
 			// with group.network.port.host_network set.
 			idx.HostNetworks[a.Alias] = append(idx.HostNetworks[a.Alias], a)
 
+			// The link of the default host network carries the
+			// traffic of the group networks.
+			if a.Alias == "default" && n.Mode == "host" && idx.GroupBandwidth == 0 {
+				idx.GroupBandwidth = n.Speed
+			}
+
 			// Mark reserved ports as used without worrying about
 			// collisions. This effectively merges
 			// client.reserved.reserved_ports into each
@@ -355,6 +376,10 @@ func (idx *NetworkIndex) AddAllocs(allocs []*Allocation) (collide bool, reason s
 		}
 
 		if alloc.AllocatedResources != nil {
+			for _, network := range alloc.AllocatedResources.Shared.Networks {
+				idx.AddReservedBandwidth(network.Bandwidth)
+			}
+
 			// Only look at AllocatedPorts if populated, otherwise use pre 0.12 logic
 			// COMPAT(1.0): Remove when network resources struct is removed.
 			if len(alloc.AllocatedResources.Shared.Ports) > 0 {
@@ -428,6 +453,16 @@ func (idx *NetworkIndex) AddReserved(n *NetworkResource) (collide bool, reasons
 	return
 }
 
+// AddReservedBandwidth is used to add the bandwidth used by a group network,
+// returns true if the bandwidth of the node is exceeded
+func (idx *NetworkIndex) AddReservedBandwidth(b *NetworkBandwidth) (exceeded bool) {
+	if b != nil {
+		idx.UsedIngressBandwidth += b.IngressMBits
+		idx.UsedEgressBandwidth += b.EgressMBits
+	}
+	return idx.Overcommitted()
+}
+
 func (idx *NetworkIndex) AddReservedPorts(ports AllocatedPorts) (collide bool, reasons []string) {
 	for _, port := range ports {
 		used := idx.getUsedPortsFor(port.HostIP)
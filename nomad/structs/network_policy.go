@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// NetworkPolicyProtocolTCP and NetworkPolicyProtocolUDP are the transport
+	// protocols a network policy ingress rule can be restricted to. Rules
+	// without a protocol apply to both.
+	NetworkPolicyProtocolTCP = "tcp"
+	NetworkPolicyProtocolUDP = "udp"
+
+	// maxNetworkPolicyDescriptionLength is the maximum length allowed for a
+	// network policy description.
+	maxNetworkPolicyDescriptionLength = 256
+)
+
+var (
+	// validNetworkPolicyName is the rule used to validate a network policy
+	// name.
+	validNetworkPolicyName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// NetworkPolicy restricts which allocations may open connections to the
+// allocations it selects when they share a client's bridge network.
+//
+// Allocations that are not selected by any network policy accept traffic from
+// every other allocation on the bridge. Once an allocation is selected by at
+// least one policy it only accepts connections allowed by the ingress rules
+// of the policies that select it. Traffic that does not originate from the
+// bridge subnet, such as mapped host ports, is not affected.
+type NetworkPolicy struct {
+	// Name is the name of the network policy. It is unique within its
+	// namespace.
+	Name string
+
+	// Namespace is the namespace the network policy belongs to. Only
+	// allocations of jobs in the same namespace are selected by the policy.
+	Namespace string
+
+	// Description is the human-friendly description of the network policy.
+	Description string
+
+	// Selector selects the allocations protected by this policy. A nil
+	// selector selects every allocation in the namespace.
+	Selector *NetworkPolicySelector
+
+	// Ingress is the list of rules allowing connections to the selected
+	// allocations. A policy without ingress rules denies all connections.
+	Ingress []*NetworkPolicyIngressRule
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NetworkPolicySelector selects allocations by the job and task group they
+// belong to and by their metadata. Empty fields match any allocation.
+type NetworkPolicySelector struct {
+	// Namespace is the namespace of the allocations to select. It may only
+	// be set on the sources of an ingress rule and defaults to the namespace
+	// of the policy.
+	Namespace string
+
+	// Job is the ID of the job of the allocations to select.
+	Job string
+
+	// Group is the name of the task group of the allocations to select.
+	Group string
+
+	// Meta selects allocations whose merged job and group metadata contains
+	// all of the given key/value pairs.
+	Meta map[string]string
+}
+
+// NetworkPolicyIngressRule allows connections from the allocations matching
+// any of its sources.
+type NetworkPolicyIngressRule struct {
+	// From is the list of sources allowed by this rule. A rule without
+	// sources allows connections from every allocation on the bridge.
+	From []*NetworkPolicySelector
+
+	// Ports restricts the rule to the given destination ports inside the
+	// allocation network namespace. All ports are allowed when empty.
+	Ports []int
+
+	// Protocol restricts the rule to a single transport protocol. Both tcp
+	// and udp are allowed when empty.
+	Protocol string
+}
+
+// NetworkPolicyEndpoint describes an allocation attached to a client's
+// bridge network, as seen by network policy selectors.
+type NetworkPolicyEndpoint struct {
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	Meta      map[string]string
+}
+
+// GetID implements the IDGetter interface required for pagination.
+func (p *NetworkPolicy) GetID() string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+// GetNamespace implements the NamespaceGetter interface required for
+// pagination.
+func (p *NetworkPolicy) GetNamespace() string {
+	if p == nil {
+		return ""
+	}
+	return p.Namespace
+}
+
+// Canonicalize sets the default namespace of the policy.
+func (p *NetworkPolicy) Canonicalize() {
+	if p.Namespace == "" {
+		p.Namespace = DefaultNamespace
+	}
+}
+
+// Validate returns an error if the network policy is invalid.
+func (p *NetworkPolicy) Validate() error {
+	var mErr *multierror.Error
+
+	if !validNetworkPolicyName.MatchString(p.Name) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid name %q, must match regex %s", p.Name, validNetworkPolicyName))
+	}
+	if p.Namespace == "" {
+		mErr = multierror.Append(mErr, errors.New("missing namespace"))
+	}
+	if len(p.Description) > maxNetworkPolicyDescriptionLength {
+		mErr = multierror.Append(mErr, fmt.Errorf("description longer than %d", maxNetworkPolicyDescriptionLength))
+	}
+	if p.Selector != nil && p.Selector.Namespace != "" && p.Selector.Namespace != p.Namespace {
+		mErr = multierror.Append(mErr, fmt.Errorf("selector namespace %q must match the policy namespace %q",
+			p.Selector.Namespace, p.Namespace))
+	}
+
+	for i, rule := range p.Ingress {
+		if rule == nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("ingress rule %d is empty", i))
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			mErr = multierror.Append(mErr, multierror.Prefix(err, fmt.Sprintf("ingress rule %d:", i)))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the network policy.
+func (p *NetworkPolicy) Copy() *NetworkPolicy {
+	if p == nil {
+		return nil
+	}
+
+	np := new(NetworkPolicy)
+	*np = *p
+	np.Selector = p.Selector.Copy()
+
+	if p.Ingress != nil {
+		np.Ingress = make([]*NetworkPolicyIngressRule, len(p.Ingress))
+		for i, rule := range p.Ingress {
+			np.Ingress[i] = rule.Copy()
+		}
+	}
+
+	return np
+}
+
+// Selects returns true if the policy protects the given endpoint.
+func (p *NetworkPolicy) Selects(e *NetworkPolicyEndpoint) bool {
+	if e.Namespace != p.Namespace {
+		return false
+	}
+	return p.Selector.Matches(p.Namespace, e)
+}
+
+// Copy returns a deep copy of the selector.
+func (s *NetworkPolicySelector) Copy() *NetworkPolicySelector {
+	if s == nil {
+		return nil
+	}
+
+	ns := new(NetworkPolicySelector)
+	*ns = *s
+	ns.Meta = maps.Clone(s.Meta)
+	return ns
+}
+
+// Matches returns true if the endpoint matches the selector. The namespace
+// is used when the selector does not set one.
+func (s *NetworkPolicySelector) Matches(namespace string, e *NetworkPolicyEndpoint) bool {
+	if s == nil {
+		return e.Namespace == namespace
+	}
+
+	if s.Namespace != "" {
+		namespace = s.Namespace
+	}
+	if namespace != AllNamespacesSentinel && e.Namespace != namespace {
+		return false
+	}
+	if s.Job != "" && s.Job != e.JobID {
+		return false
+	}
+	if s.Group != "" && s.Group != e.TaskGroup {
+		return false
+	}
+	for k, v := range s.Meta {
+		if ev, ok := e.Meta[k]; !ok || ev != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate returns an error if the ingress rule is invalid.
+func (r *NetworkPolicyIngressRule) Validate() error {
+	var mErr *multierror.Error
+
+	switch r.Protocol {
+	case "", NetworkPolicyProtocolTCP, NetworkPolicyProtocolUDP:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid protocol %q, must be %q or %q",
+			r.Protocol, NetworkPolicyProtocolTCP, NetworkPolicyProtocolUDP))
+	}
+
+	for _, port := range r.Ports {
+		if port < 1 || port > MaxValidPort {
+			mErr = multierror.Append(mErr, fmt.Errorf("port %d out of range [1, %d]", port, MaxValidPort))
+		}
+	}
+
+	for i, from := range r.From {
+		if from == nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("source %d is empty", i))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the ingress rule.
+func (r *NetworkPolicyIngressRule) Copy() *NetworkPolicyIngressRule {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(NetworkPolicyIngressRule)
+	*nr = *r
+	nr.Ports = slices.Clone(r.Ports)
+
+	if r.From != nil {
+		nr.From = make([]*NetworkPolicySelector, len(r.From))
+		for i, from := range r.From {
+			nr.From[i] = from.Copy()
+		}
+	}
+
+	return nr
+}
+
+// Allows returns true if the rule allows connections from the given source
+// endpoint. The namespace is the namespace of the policy owning the rule.
+func (r *NetworkPolicyIngressRule) Allows(namespace string, src *NetworkPolicyEndpoint) bool {
+	if len(r.From) == 0 {
+		return true
+	}
+	for _, from := range r.From {
+		if from.Matches(namespace, src) {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkPolicyListRequest is used to list network policies.
+type NetworkPolicyListRequest struct {
+	QueryOptions
+}
+
+// NetworkPolicyListResponse is the response to a network policies list
+// request.
+type NetworkPolicyListResponse struct {
+	NetworkPolicies []*NetworkPolicy
+	QueryMeta
+}
+
+// NetworkPolicySpecificRequest is used to make a request for a specific
+// network policy.
+type NetworkPolicySpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNetworkPolicyResponse is the response to a specific network policy
+// request.
+type SingleNetworkPolicyResponse struct {
+	NetworkPolicy *NetworkPolicy
+	QueryMeta
+}
+
+// NetworkPolicyUpsertRequest is used to make a request to insert or update
+// network policies.
+type NetworkPolicyUpsertRequest struct {
+	NetworkPolicies []*NetworkPolicy
+	WriteRequest
+}
+
+// NetworkPolicyDeleteRequest is used to make a request to delete network
+// policies from the request namespace.
+type NetworkPolicyDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestNetworkPolicy_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	policy := &NetworkPolicy{
+		Name:      "db",
+		Namespace: DefaultNamespace,
+		Selector: &NetworkPolicySelector{
+			Job:  "db",
+			Meta: map[string]string{"tier": "data"},
+		},
+		Ingress: []*NetworkPolicyIngressRule{{
+			From:  []*NetworkPolicySelector{{Job: "api"}},
+			Ports: []int{5432},
+		}},
+	}
+
+	policyCopy := policy.Copy()
+	must.Eq(t, policy, policyCopy)
+
+	policyCopy.Selector.Meta["tier"] = "web"
+	policyCopy.Ingress[0].From[0].Job = "web"
+	policyCopy.Ingress[0].Ports[0] = 80
+	must.Eq(t, "data", policy.Selector.Meta["tier"])
+	must.Eq(t, "api", policy.Ingress[0].From[0].Job)
+	must.Eq(t, 5432, policy.Ingress[0].Ports[0])
+}
+
+func TestNetworkPolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name        string
+		policy      *NetworkPolicy
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			policy: &NetworkPolicy{
+				Name:      "db",
+				Namespace: DefaultNamespace,
+				Ingress: []*NetworkPolicyIngressRule{{
+					From:     []*NetworkPolicySelector{{Namespace: "web", Job: "api"}},
+					Ports:    []int{5432},
+					Protocol: NetworkPolicyProtocolTCP,
+				}},
+			},
+		},
+		{
+			name: "invalid name",
+			policy: &NetworkPolicy{
+				Name:      "db/primary",
+				Namespace: DefaultNamespace,
+			},
+			expectedErr: "invalid name",
+		},
+		{
+			name: "missing namespace",
+			policy: &NetworkPolicy{
+				Name: "db",
+			},
+			expectedErr: "missing namespace",
+		},
+		{
+			name: "selector in other namespace",
+			policy: &NetworkPolicy{
+				Name:      "db",
+				Namespace: DefaultNamespace,
+				Selector:  &NetworkPolicySelector{Namespace: "prod"},
+			},
+			expectedErr: "must match the policy namespace",
+		},
+		{
+			name: "invalid protocol",
+			policy: &NetworkPolicy{
+				Name:      "db",
+				Namespace: DefaultNamespace,
+				Ingress:   []*NetworkPolicyIngressRule{{Protocol: "icmp"}},
+			},
+			expectedErr: "invalid protocol",
+		},
+		{
+			name: "invalid port",
+			policy: &NetworkPolicy{
+				Name:      "db",
+				Namespace: DefaultNamespace,
+				Ingress:   []*NetworkPolicyIngressRule{{Ports: []int{0, 70000}}},
+			},
+			expectedErr: "port 70000 out of range",
+		},
+		{
+			name: "empty source",
+			policy: &NetworkPolicy{
+				Name:      "db",
+				Namespace: DefaultNamespace,
+				Ingress: []*NetworkPolicyIngressRule{{
+					From: []*NetworkPolicySelector{nil},
+				}},
+			},
+			expectedErr: "source 0 is empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.expectedErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestNetworkPolicy_Selects(t *testing.T) {
+	ci.Parallel(t)
+
+	api := &NetworkPolicyEndpoint{
+		Namespace: DefaultNamespace,
+		JobID:     "api",
+		TaskGroup: "web",
+		Meta:      map[string]string{"tier": "frontend"},
+	}
+	db := &NetworkPolicyEndpoint{
+		Namespace: DefaultNamespace,
+		JobID:     "db",
+		TaskGroup: "postgres",
+		Meta:      map[string]string{"tier": "data"},
+	}
+	batch := &NetworkPolicyEndpoint{
+		Namespace: "batch",
+		JobID:     "etl",
+		TaskGroup: "worker",
+	}
+
+	policy := &NetworkPolicy{
+		Name:      "db",
+		Namespace: DefaultNamespace,
+		Selector:  &NetworkPolicySelector{Meta: map[string]string{"tier": "data"}},
+		Ingress: []*NetworkPolicyIngressRule{
+			{From: []*NetworkPolicySelector{{Job: "api", Group: "web"}}},
+			{From: []*NetworkPolicySelector{{Namespace: "batch"}}},
+		},
+	}
+
+	must.True(t, policy.Selects(db))
+	must.False(t, policy.Selects(api))
+	must.False(t, policy.Selects(batch))
+
+	must.True(t, policy.Ingress[0].Allows(policy.Namespace, api))
+	must.False(t, policy.Ingress[0].Allows(policy.Namespace, batch))
+	must.False(t, policy.Ingress[1].Allows(policy.Namespace, api))
+	must.True(t, policy.Ingress[1].Allows(policy.Namespace, batch))
+
+	// A nil selector selects every allocation of the namespace.
+	policy.Selector = nil
+	must.True(t, policy.Selects(api))
+	must.True(t, policy.Selects(db))
+	must.False(t, policy.Selects(batch))
+
+	// A rule without sources allows every allocation.
+	must.True(t, (&NetworkPolicyIngressRule{}).Allows(policy.Namespace, batch))
+}
@@ -173,6 +173,62 @@ func TestNetworkIndex_Overcommitted(t *testing.T) {
 	}
 }
 
+func TestNetworkIndex_GroupBandwidth(t *testing.T) {
+	ci.Parallel(t)
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			NodeNetworks: []*NodeNetworkResource{
+				{
+					Mode: "bridge",
+				},
+				{
+					Addresses: []NodeNetworkAddress{{
+						Address: "192.168.0.100",
+						Alias:   "default",
+						Family:  "ipv4",
+					}},
+					Device: "eth0",
+					Mode:   "host",
+					Speed:  1000,
+				},
+			},
+		},
+	}
+
+	idx := NewNetworkIndex()
+	must.NoError(t, idx.SetNode(n))
+	must.Eq(t, 1000, idx.GroupBandwidth)
+
+	// Existing allocations consume the bandwidth of their group network
+	alloc := &Allocation{
+		AllocatedResources: &AllocatedResources{
+			Shared: AllocatedSharedResources{
+				Networks: []*NetworkResource{{
+					Mode:      "bridge",
+					Bandwidth: &NetworkBandwidth{IngressMBits: 600, EgressMBits: 100},
+				}},
+			},
+		},
+	}
+	collide, reason := idx.AddAllocs([]*Allocation{alloc})
+	must.False(t, collide, must.Sprint(reason))
+	must.Eq(t, 600, idx.UsedIngressBandwidth)
+	must.Eq(t, 100, idx.UsedEgressBandwidth)
+	must.False(t, idx.Overcommitted())
+
+	// Ingress and egress are accounted for separately
+	must.False(t, idx.AddReservedBandwidth(&NetworkBandwidth{EgressMBits: 900}))
+	must.True(t, idx.AddReservedBandwidth(&NetworkBandwidth{IngressMBits: 500}))
+
+	// Bandwidth is not accounted for on nodes with an unknown speed
+	n.NodeResources.NodeNetworks[1].Speed = 0
+	idx = NewNetworkIndex()
+	must.NoError(t, idx.SetNode(n))
+	idx.AddAllocs([]*Allocation{alloc, alloc})
+	must.False(t, idx.AddReservedBandwidth(&NetworkBandwidth{IngressMBits: 5000}))
+}
+
 func TestNetworkIndex_SetNode(t *testing.T) {
 	ci.Parallel(t)
 
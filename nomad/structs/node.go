@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/nomad/helper"
 )
 
 // CSITopology is a map of topological domains to topological segments.
@@ -478,3 +479,170 @@ type NodeMetaResponse struct {
 	// Static is the static Node metadata (set via agent configuration)
 	Static map[string]string
 }
+
+// ArtifactCacheEntry describes an artifact in the artifact cache of a Client
+// agent.
+type ArtifactCacheEntry struct {
+	// Key identifies the content of the artifact
+	Key string
+
+	// Source is the source the artifact was downloaded from
+	Source string
+
+	// Name is the file name of the artifact
+	Name string
+
+	// Size is the size of the artifact in bytes
+	Size int64
+
+	// References is the number of tasks currently using the artifact
+	References int
+
+	CreateTime  int64
+	LastUseTime int64
+}
+
+// ArtifactCacheResponse is used to read the artifact cache directly from
+// Client agents.
+type ArtifactCacheResponse struct {
+	// Entries are the cached artifacts, most recently used first
+	Entries []*ArtifactCacheEntry
+
+	// Size is the total size of the cached artifacts in bytes
+	Size int64
+
+	// MaxSize is the size in bytes beyond which artifacts are evicted
+	MaxSize int64
+}
+
+// ArtifactCachePurgeRequest is used to remove artifacts from the artifact
+// cache of Client agents.
+type ArtifactCachePurgeRequest struct {
+	QueryOptions // Client RPCs must use QueryOptions to set AllowStale=true
+
+	// NodeID is the node being targeted by this request (or the node
+	// receiving this request if NodeID is empty).
+	NodeID string
+
+	// Keys are the keys of the artifacts to remove. Every artifact is
+	// removed if empty.
+	Keys []string
+}
+
+// ArtifactCachePurgeResponse lists the artifacts affected by a purge.
+type ArtifactCachePurgeResponse struct {
+	// Purged are the keys of the removed artifacts
+	Purged []string
+
+	// InUse are the keys of the artifacts that were not removed because
+	// tasks are using them
+	InUse []string
+
+	// NotFound are the requested keys that are not in the cache
+	NotFound []string
+}
+
+const (
+	NodePrefetchStatusPending  = "pending"
+	NodePrefetchStatusRunning  = "running"
+	NodePrefetchStatusComplete = "complete"
+	NodePrefetchStatusFailed   = "failed"
+	NodePrefetchStatusSkipped  = "skipped"
+)
+
+// NodePrefetch is the progress of prefetching the container images and
+// artifacts of a job on a node.
+type NodePrefetch struct {
+	NodeID     string
+	Namespace  string
+	JobID      string
+	JobVersion uint64
+
+	// Status is the status of the prefetch, which is complete once every
+	// image and artifact was fetched or skipped, and failed if any failed
+	Status string
+
+	Images    []*NodePrefetchItem
+	Artifacts []*NodePrefetchItem
+
+	StartTime int64
+	EndTime   int64
+}
+
+// Copy returns a deep copy of the NodePrefetch.
+func (p *NodePrefetch) Copy() *NodePrefetch {
+	if p == nil {
+		return nil
+	}
+	np := *p
+	np.Images = helper.CopySlice(p.Images)
+	np.Artifacts = helper.CopySlice(p.Artifacts)
+	return &np
+}
+
+// Terminal returns whether prefetching has finished.
+func (p *NodePrefetch) Terminal() bool {
+	switch p.Status {
+	case NodePrefetchStatusComplete, NodePrefetchStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// NodePrefetchItem is the progress of prefetching a container image or an
+// artifact.
+type NodePrefetchItem struct {
+	// Source is the container image or the source of the artifact, without
+	// its query string
+	Source string
+	Status string
+	Error  string
+}
+
+// Copy returns a copy of the NodePrefetchItem.
+func (i *NodePrefetchItem) Copy() *NodePrefetchItem {
+	if i == nil {
+		return nil
+	}
+	ni := *i
+	return &ni
+}
+
+// NodePrefetchRequest is used to start prefetching the container images and
+// artifacts of a job on a node.
+type NodePrefetchRequest struct {
+	NodeID     string
+	JobID      string
+	JobVersion uint64
+
+	// Images, ImageAuths and Artifacts are set by the server from the stored
+	// job at JobVersion before the request is forwarded to the client.
+	Images     []string
+	ImageAuths map[string]*PrefetchImageAuth
+	Artifacts  []*TaskArtifact
+
+	QueryOptions
+}
+
+// PrefetchImageAuth is the registry authentication of the tasks using a
+// prefetched container image, from the auth block of their driver config.
+type PrefetchImageAuth struct {
+	Username      string
+	Password      string
+	Email         string
+	ServerAddress string
+}
+
+// NodePrefetchStatusRequest is used to read the progress of prefetching a job
+// on a node.
+type NodePrefetchStatusRequest struct {
+	NodeID string
+	JobID  string
+	QueryOptions
+}
+
+// NodePrefetchResponse is the response to prefetch requests for a node.
+type NodePrefetchResponse struct {
+	Prefetch *NodePrefetch
+}
@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// The nomad block of a task requests a short-lived Nomad ACL token linked to
+// ACL roles. The token is created by the servers for the allocation, renewed
+// by the client while the task runs, and revoked when the allocation stops.
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// NomadChangeModeNoop takes no action when the Nomad ACL token of a task
+	// is replaced
+	NomadChangeModeNoop = "noop"
+
+	// NomadChangeModeSignal signals the task when the Nomad ACL token of a
+	// task is replaced
+	NomadChangeModeSignal = "signal"
+
+	// NomadChangeModeRestart restarts the task when the Nomad ACL token of a
+	// task is replaced
+	NomadChangeModeRestart = "restart"
+
+	// DefaultNomadTokenTTL is the expiration TTL of the Nomad ACL token of a
+	// task when the nomad block doesn't set one.
+	DefaultNomadTokenTTL = time.Hour
+)
+
+// Nomad is the nomad block of a task.
+type Nomad struct {
+	// Roles are the names of the ACL roles the token is linked to.
+	Roles []string
+
+	// TTL is the expiration TTL of the token. The client renews the token
+	// before it expires, up to the token's max TTL.
+	TTL time.Duration
+
+	// Env exposes the token as the NOMAD_TOKEN environment variable.
+	Env bool
+
+	// DisableFile prevents the token from being written to the
+	// nomad_acl_token file in the task's secrets directory.
+	DisableFile bool
+
+	// ChangeMode is used to configure the task's behavior when the token is
+	// replaced because it could not be renewed.
+	ChangeMode string
+
+	// ChangeSignal is the signal sent to the task when the token is
+	// replaced. This is only valid when using the signal change mode.
+	ChangeSignal string
+}
+
+func (n *Nomad) Equal(o *Nomad) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	switch {
+	case !slices.Equal(n.Roles, o.Roles):
+		return false
+	case n.TTL != o.TTL:
+		return false
+	case n.Env != o.Env:
+		return false
+	case n.DisableFile != o.DisableFile:
+		return false
+	case n.ChangeMode != o.ChangeMode:
+		return false
+	case n.ChangeSignal != o.ChangeSignal:
+		return false
+	}
+	return true
+}
+
+// Copy returns a copy of this Nomad block.
+func (n *Nomad) Copy() *Nomad {
+	if n == nil {
+		return nil
+	}
+
+	nn := new(Nomad)
+	*nn = *n
+	nn.Roles = slices.Clone(n.Roles)
+	return nn
+}
+
+func (n *Nomad) Canonicalize() {
+	if n.TTL == 0 {
+		n.TTL = DefaultNomadTokenTTL
+	}
+
+	if n.ChangeSignal != "" {
+		n.ChangeSignal = strings.ToUpper(n.ChangeSignal)
+	}
+
+	if n.ChangeMode == "" {
+		n.ChangeMode = NomadChangeModeRestart
+	}
+}
+
+// Validate returns if the Nomad block is valid.
+func (n *Nomad) Validate() error {
+	if n == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if len(n.Roles) == 0 {
+		_ = multierror.Append(&mErr, errors.New("At least one role must be specified"))
+	}
+	for _, role := range n.Roles {
+		if role == "" {
+			_ = multierror.Append(&mErr, errors.New("Role names cannot be empty"))
+		}
+	}
+
+	if n.TTL < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("TTL %q cannot be negative", n.TTL))
+	}
+
+	switch n.ChangeMode {
+	case NomadChangeModeSignal:
+		if n.ChangeSignal == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Signal must be specified when using change mode %q", NomadChangeModeSignal))
+		}
+	case NomadChangeModeNoop, NomadChangeModeRestart:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown change mode %q", n.ChangeMode))
+	}
+
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestNomad_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var n *Nomad
+	must.Nil(t, n.Copy())
+
+	n = &Nomad{
+		Roles:      []string{"ops", "deploy"},
+		TTL:        time.Hour,
+		ChangeMode: NomadChangeModeRestart,
+	}
+
+	nCopy := n.Copy()
+	must.Equal(t, n, nCopy)
+
+	nCopy.Roles[0] = "admin"
+	must.Eq(t, "ops", n.Roles[0])
+	must.False(t, n.Equal(nCopy))
+}
+
+func TestNomad_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	n := &Nomad{Roles: []string{"ops"}, ChangeSignal: "sighup"}
+	n.Canonicalize()
+	must.Eq(t, DefaultNomadTokenTTL, n.TTL)
+	must.Eq(t, NomadChangeModeRestart, n.ChangeMode)
+	must.Eq(t, "SIGHUP", n.ChangeSignal)
+}
+
+func TestNomad_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		nomad    *Nomad
+		expError string
+	}{
+		{
+			name: "valid",
+			nomad: &Nomad{
+				Roles:        []string{"ops"},
+				TTL:          time.Hour,
+				ChangeMode:   NomadChangeModeSignal,
+				ChangeSignal: "SIGHUP",
+			},
+		},
+		{
+			name:     "no roles",
+			nomad:    &Nomad{ChangeMode: NomadChangeModeRestart},
+			expError: "At least one role must be specified",
+		},
+		{
+			name:     "empty role",
+			nomad:    &Nomad{Roles: []string{""}, ChangeMode: NomadChangeModeRestart},
+			expError: "Role names cannot be empty",
+		},
+		{
+			name:     "negative ttl",
+			nomad:    &Nomad{Roles: []string{"ops"}, TTL: -time.Second, ChangeMode: NomadChangeModeNoop},
+			expError: "cannot be negative",
+		},
+		{
+			name:     "signal without signal",
+			nomad:    &Nomad{Roles: []string{"ops"}, ChangeMode: NomadChangeModeSignal},
+			expError: "Signal must be specified",
+		},
+		{
+			name:     "unknown change mode",
+			nomad:    &Nomad{Roles: []string{"ops"}, ChangeMode: "reload"},
+			expError: `Unknown change mode "reload"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.nomad.Validate()
+			if tc.expError == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expError)
+			}
+		})
+	}
+}
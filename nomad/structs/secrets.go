@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Secrets deliver the items of a Nomad Variable or a Vault secret to a task as
+// files in its secrets directory or as environment variables, without the need
+// to write a template.
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// SecretProviderNomad reads the secret from a Nomad Variable
+	SecretProviderNomad = "nomad"
+
+	// SecretProviderVault reads the secret from Vault
+	SecretProviderVault = "vault"
+
+	// SecretChangeModeNoop marks that no action should be taken if the
+	// secret changes
+	SecretChangeModeNoop = "noop"
+
+	// SecretChangeModeSignal marks that the task should be signaled if the
+	// secret changes
+	SecretChangeModeSignal = "signal"
+
+	// SecretChangeModeRestart marks that the task should be restarted if the
+	// secret changes
+	SecretChangeModeRestart = "restart"
+)
+
+// validSecretName is used to validate a secret name. The name is used as a
+// directory name in the task's secrets directory.
+var validSecretName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+
+// Secret is a secret whose items are delivered to a task.
+type Secret struct {
+	// Name is the label of the secret block. Unless the items are exposed as
+	// environment variables, each item is written to a file in a directory
+	// with this name in the task's secrets directory.
+	Name string
+
+	// Provider is where the secret is read from: nomad or vault.
+	Provider string
+
+	// Path is the path of the Nomad Variable or Vault secret.
+	Path string
+
+	// Env exposes the items as environment variables instead of files.
+	Env bool
+
+	// ChangeMode indicates what should be done if the secret changes.
+	ChangeMode string
+
+	// ChangeSignal is the signal that should be sent if the change mode
+	// requires it.
+	ChangeSignal string
+
+	// Perms is the permission the files should be written out with.
+	Perms string
+
+	// User and group that should own the files.
+	Uid *int
+	Gid *int
+}
+
+func (s *Secret) Copy() *Secret {
+	if s == nil {
+		return nil
+	}
+	ns := new(Secret)
+	*ns = *s
+	ns.Uid = pointer.Copy(s.Uid)
+	ns.Gid = pointer.Copy(s.Gid)
+	return ns
+}
+
+func (s *Secret) Equal(o *Secret) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	switch {
+	case s.Name != o.Name:
+		return false
+	case s.Provider != o.Provider:
+		return false
+	case s.Path != o.Path:
+		return false
+	case s.Env != o.Env:
+		return false
+	case s.ChangeMode != o.ChangeMode:
+		return false
+	case s.ChangeSignal != o.ChangeSignal:
+		return false
+	case s.Perms != o.Perms:
+		return false
+	case !pointer.Eq(s.Uid, o.Uid):
+		return false
+	case !pointer.Eq(s.Gid, o.Gid):
+		return false
+	}
+	return true
+}
+
+func (s *Secret) Canonicalize() {
+	if s.ChangeSignal != "" {
+		s.ChangeSignal = strings.ToUpper(s.ChangeSignal)
+	}
+}
+
+func (s *Secret) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	var mErr *multierror.Error
+	if !validSecretName.MatchString(s.Name) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid name %q", s.Name))
+	}
+
+	switch s.Provider {
+	case SecretProviderNomad, SecretProviderVault:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid provider %q, must be one of: nomad, vault", s.Provider))
+	}
+
+	if s.Path == "" {
+		mErr = multierror.Append(mErr, errors.New("must specify a path"))
+	}
+
+	switch s.ChangeMode {
+	case SecretChangeModeNoop, SecretChangeModeRestart:
+	case SecretChangeModeSignal:
+		if s.ChangeSignal == "" {
+			mErr = multierror.Append(mErr, errors.New("must specify signal value when change mode is signal"))
+		}
+		if s.Env {
+			mErr = multierror.Append(mErr, errors.New("cannot use signals with env var secrets"))
+		}
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid change mode %q, must be one of: noop, signal, restart", s.ChangeMode))
+	}
+
+	if s.Perms != "" {
+		if _, err := strconv.ParseUint(s.Perms, 8, 12); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("failed to parse %q as octal: %v", s.Perms, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// DiffID fulfills the DiffableWithID interface.
+func (s *Secret) DiffID() string {
+	return s.Name
+}
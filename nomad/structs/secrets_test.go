@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestSecret_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var secret *Secret
+	must.Nil(t, secret.Copy())
+
+	secret = &Secret{
+		Name:       "db",
+		Provider:   SecretProviderNomad,
+		Path:       "nomad/jobs/example/db",
+		ChangeMode: SecretChangeModeRestart,
+		Uid:        pointer.Of(1000),
+	}
+
+	secretCopy := secret.Copy()
+	must.Equal(t, secret, secretCopy)
+
+	*secretCopy.Uid = 1001
+	must.Eq(t, 1000, *secret.Uid)
+	must.False(t, secret.Equal(secretCopy))
+}
+
+func TestSecret_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		secret   *Secret
+		expError string
+	}{
+		{
+			name: "valid",
+			secret: &Secret{
+				Name:         "db",
+				Provider:     SecretProviderVault,
+				Path:         "secret/data/db",
+				ChangeMode:   SecretChangeModeSignal,
+				ChangeSignal: "SIGHUP",
+				Perms:        "0400",
+			},
+		},
+		{
+			name: "invalid name",
+			secret: &Secret{
+				Name:       "../db",
+				Provider:   SecretProviderNomad,
+				Path:       "db",
+				ChangeMode: SecretChangeModeRestart,
+			},
+			expError: `invalid name "../db"`,
+		},
+		{
+			name: "invalid provider",
+			secret: &Secret{
+				Name:       "db",
+				Provider:   "consul",
+				Path:       "db",
+				ChangeMode: SecretChangeModeRestart,
+			},
+			expError: `invalid provider "consul"`,
+		},
+		{
+			name: "missing path",
+			secret: &Secret{
+				Name:       "db",
+				Provider:   SecretProviderNomad,
+				ChangeMode: SecretChangeModeRestart,
+			},
+			expError: "must specify a path",
+		},
+		{
+			name: "signal without signal value",
+			secret: &Secret{
+				Name:       "db",
+				Provider:   SecretProviderNomad,
+				Path:       "db",
+				ChangeMode: SecretChangeModeSignal,
+			},
+			expError: "must specify signal value",
+		},
+		{
+			name: "signal with env",
+			secret: &Secret{
+				Name:         "db",
+				Provider:     SecretProviderNomad,
+				Path:         "db",
+				Env:          true,
+				ChangeMode:   SecretChangeModeSignal,
+				ChangeSignal: "SIGHUP",
+			},
+			expError: "cannot use signals with env var secrets",
+		},
+		{
+			name: "invalid change mode",
+			secret: &Secret{
+				Name:       "db",
+				Provider:   SecretProviderNomad,
+				Path:       "db",
+				ChangeMode: "script",
+			},
+			expError: `invalid change mode "script"`,
+		},
+		{
+			name: "invalid perms",
+			secret: &Secret{
+				Name:       "db",
+				Provider:   SecretProviderNomad,
+				Path:       "db",
+				ChangeMode: SecretChangeModeRestart,
+				Perms:      "0999",
+			},
+			expError: "as octal",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.secret.Validate()
+			if tc.expError == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expError)
+			}
+		})
+	}
+}
+
+func TestTask_Validate_Secrets(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &Task{
+		Name:      "web",
+		Driver:    "docker",
+		Resources: DefaultResources(),
+		LogConfig: DefaultLogConfig(),
+		Secrets: []*Secret{
+			{
+				Name:       "db",
+				Provider:   SecretProviderNomad,
+				Path:       "db",
+				ChangeMode: SecretChangeModeRestart,
+			},
+			{
+				Name:       "db",
+				Provider:   SecretProviderVault,
+				Path:       "secret/data/db",
+				ChangeMode: SecretChangeModeRestart,
+			},
+		},
+	}
+	tg := &TaskGroup{
+		EphemeralDisk: DefaultEphemeralDisk(),
+	}
+
+	err := task.Validate(JobTypeService, tg)
+	must.ErrorContains(t, err, `Secret "db" defined multiple times`)
+	must.ErrorContains(t, err, `Secret "db" uses the vault provider but the task has no vault block`)
+
+	task.Secrets[1].Name = "vault-db"
+	task.Vault = &Vault{Role: "web", ChangeMode: VaultChangeModeRestart}
+	must.NoError(t, task.Validate(JobTypeService, tg))
+}
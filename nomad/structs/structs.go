@@ -128,10 +128,22 @@ const (
 	ACLBindingRulesDeleteRequestType             MessageType = 58
 	NodePoolUpsertRequestType                    MessageType = 59
 	NodePoolDeleteRequestType                    MessageType = 60
+	NetworkPolicyUpsertRequestType               MessageType = 61
+	NetworkPolicyDeleteRequestType               MessageType = 62
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	// Event sink types replace the ones removed during the 1.0-beta series
+	// and therefore can't reuse their values.
+	EventSinksUpsertRequestType   MessageType = 75
+	EventSinksDeleteRequestType   MessageType = 76
+	EventSinksProgressRequestType MessageType = 77
+
+	// Variable version types manage the version history kept for variables
+	// and follow the event sink types.
+	VariableVersionsDeleteRequestType MessageType = 78
 )
 
 const (
@@ -921,6 +933,20 @@ type JobDispatchRequest struct {
 	IdPrefixTemplate string
 }
 
+// JobPrefetchRequest is used to prefetch the container images and artifacts
+// of a job onto the nodes it may be placed on.
+type JobPrefetchRequest struct {
+	JobID string
+	WriteRequest
+}
+
+// JobPrefetchStatusRequest is used to get the progress of prefetching a job on
+// the nodes it may be placed on.
+type JobPrefetchStatusRequest struct {
+	JobID string
+	QueryOptions
+}
+
 // JobValidateRequest is used to validate a job
 type JobValidateRequest struct {
 	Job *Job
@@ -1633,6 +1659,30 @@ type JobDispatchResponse struct {
 	WriteMeta
 }
 
+// JobPrefetchResponse is the response to a JobPrefetchRequest.
+type JobPrefetchResponse struct {
+	// NodeIDs are the nodes prefetching the job
+	NodeIDs []string
+
+	// Errors maps the IDs of the nodes on which prefetching could not be
+	// started to the error
+	Errors map[string]string
+
+	WriteMeta
+}
+
+// JobPrefetchStatusResponse is the response to a JobPrefetchStatusRequest.
+type JobPrefetchStatusResponse struct {
+	// Nodes is the progress of prefetching the job on each node
+	Nodes []*NodePrefetch
+
+	// Errors maps the IDs of the nodes whose progress could not be read to
+	// the error
+	Errors map[string]string
+
+	QueryMeta
+}
+
 // JobListResponse is used for a list request
 type JobListResponse struct {
 	Jobs []*JobListStub
@@ -2189,6 +2239,11 @@ type Node struct {
 	// updatedd its allocations status.
 	LastAllocUpdateIndex uint64
 
+	// PrefetchedImages are the container images pulled onto the node by
+	// prefetching jobs ahead of their placement. The scheduler prefers nodes
+	// holding the images of a task group.
+	PrefetchedImages []string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -2291,6 +2346,7 @@ func (n *Node) Copy() *Node {
 	nn.HostVolumes = helper.DeepCopyMap(n.HostVolumes)
 	nn.HostNetworks = helper.DeepCopyMap(n.HostNetworks)
 	nn.LastDrain = nn.LastDrain.Copy()
+	nn.PrefetchedImages = slices.Clone(nn.PrefetchedImages)
 	return &nn
 }
 
@@ -2848,24 +2904,79 @@ func (d *DNSConfig) IsZero() bool {
 	return len(d.Options) == 0 && len(d.Searches) == 0 && len(d.Servers) == 0
 }
 
+// NetworkBandwidth is the bandwidth limit of a group network. Clients enforce
+// the limits on the allocation's network interface and the scheduler accounts
+// for them against the link speed of the node.
+type NetworkBandwidth struct {
+	// IngressMBits is the rate limit of the traffic received by the
+	// allocation in megabits per second.
+	IngressMBits int
+
+	// EgressMBits is the rate limit of the traffic sent by the allocation in
+	// megabits per second.
+	EgressMBits int
+}
+
+func (b *NetworkBandwidth) Equal(o *NetworkBandwidth) bool {
+	if b == nil || o == nil {
+		return b == o
+	}
+	return *b == *o
+}
+
+func (b *NetworkBandwidth) Copy() *NetworkBandwidth {
+	if b == nil {
+		return nil
+	}
+	nb := *b
+	return &nb
+}
+
+func (b *NetworkBandwidth) IsZero() bool {
+	if b == nil {
+		return true
+	}
+	return b.IngressMBits == 0 && b.EgressMBits == 0
+}
+
+func (b *NetworkBandwidth) Validate() error {
+	if b == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if b.IngressMBits < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("ingress_mbits must be greater than or equal to 0, got %d", b.IngressMBits))
+	}
+	if b.EgressMBits < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("egress_mbits must be greater than or equal to 0, got %d", b.EgressMBits))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // NetworkResource is used to represent available network
 // resources
 type NetworkResource struct {
-	Mode          string     // Mode of the network
-	Device        string     // Name of the device
-	CIDR          string     // CIDR block of addresses
-	IP            string     // Host IP address
-	Hostname      string     `json:",omitempty"` // Hostname of the network namespace
-	MBits         int        // Throughput
-	DNS           *DNSConfig // DNS Configuration
-	ReservedPorts []Port     // Host Reserved ports
-	DynamicPorts  []Port     // Host Dynamically assigned ports
+	Mode          string            // Mode of the network
+	Device        string            // Name of the device
+	CIDR          string            // CIDR block of addresses
+	IP            string            // Host IP address
+	Hostname      string            `json:",omitempty"` // Hostname of the network namespace
+	MBits         int               // Throughput
+	DNS           *DNSConfig        // DNS Configuration
+	Bandwidth     *NetworkBandwidth `json:",omitempty"` // Bandwidth limits of the network
+	ReservedPorts []Port            // Host Reserved ports
+	DynamicPorts  []Port            // Host Dynamically assigned ports
 }
 
 func (n *NetworkResource) Hash() uint32 {
 	var data []byte
 	data = append(data, []byte(fmt.Sprintf("%s%s%s%s%s%d", n.Mode, n.Device, n.CIDR, n.IP, n.Hostname, n.MBits))...)
 
+	if n.Bandwidth != nil {
+		data = append(data, []byte(fmt.Sprintf("b%d%d", n.Bandwidth.IngressMBits, n.Bandwidth.EgressMBits))...)
+	}
+
 	for i, port := range n.ReservedPorts {
 		data = append(data, []byte(fmt.Sprintf("r%d%s%d%d", i, port.Label, port.Value, port.To))...)
 	}
@@ -2911,6 +3022,7 @@ func (n *NetworkResource) Copy() *NetworkResource {
 	newR := new(NetworkResource)
 	*newR = *n
 	newR.DNS = n.DNS.Copy()
+	newR.Bandwidth = n.Bandwidth.Copy()
 	if n.ReservedPorts != nil {
 		newR.ReservedPorts = make([]Port, len(n.ReservedPorts))
 		copy(newR.ReservedPorts, n.ReservedPorts)
@@ -2949,6 +3061,13 @@ func (n *NetworkResource) PortLabels() map[string]int {
 	return labelValues
 }
 
+// AttachedHostNetwork returns the name of the host network of a network using
+// the host/<name> mode, which attaches an interface on the host network to the
+// allocation network namespace of groups with multiple networks.
+func (n *NetworkResource) AttachedHostNetwork() (string, bool) {
+	return strings.CutPrefix(n.Mode, "host/")
+}
+
 // Networks defined for a task on the Resources struct.
 type Networks []*NetworkResource
 
@@ -4545,6 +4664,15 @@ func (j *Job) GetNamespace() string {
 	return j.Namespace
 }
 
+// GetJobID implements the JobIDGetter interface, required for filtering the
+// jobs a token has access to.
+func (j *Job) GetJobID() string {
+	if j == nil {
+		return ""
+	}
+	return j.ID
+}
+
 // GetCreateIndex implements the CreateIndexGetter interface, required for
 // pagination.
 func (j *Job) GetCreateIndex() uint64 {
@@ -5428,6 +5556,9 @@ type Namespace struct {
 	VaultConfiguration  *NamespaceVaultConfiguration
 	ConsulConfiguration *NamespaceConsulConfiguration
 
+	// VariablesConfiguration is the namespace configuration for variables.
+	VariablesConfiguration *NamespaceVariablesConfiguration
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5512,6 +5643,10 @@ func (n *Namespace) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid consul configuration: %v", e))
 	}
 
+	if err := n.VariablesConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid variables configuration: %v", err))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5565,6 +5700,10 @@ func (n *Namespace) SetHash() []byte {
 		}
 	}
 
+	if n.VariablesConfiguration != nil && n.VariablesConfiguration.MaxVersions != nil {
+		_, _ = hash.Write([]byte(strconv.Itoa(*n.VariablesConfiguration.MaxVersions)))
+	}
+
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
 	for k := range n.Meta {
@@ -5614,6 +5753,7 @@ func (n *Namespace) Copy() *Namespace {
 		nc.Allowed = slices.Clone(n.ConsulConfiguration.Allowed)
 		nc.Denied = slices.Clone(n.ConsulConfiguration.Denied)
 	}
+	nc.VariablesConfiguration = n.VariablesConfiguration.Copy()
 
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
@@ -6688,6 +6828,10 @@ type TaskGroup struct {
 	// Migrate is used to control the migration strategy for this task group
 	Migrate *MigrateStrategy
 
+	// TemplateRestart is used to roll the restarts triggered by templates
+	// across the allocations of this task group
+	TemplateRestart *TemplateRestartPolicy
+
 	// Constraints can be specified at a task group level and apply to
 	// all the tasks contained.
 	Constraints []*Constraint
@@ -6765,6 +6909,7 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg := new(TaskGroup)
 	*ntg = *tg
 	ntg.Update = ntg.Update.Copy()
+	ntg.TemplateRestart = ntg.TemplateRestart.Copy()
 	ntg.Constraints = CopySliceConstraints(ntg.Constraints)
 	ntg.RestartPolicy = ntg.RestartPolicy.Copy()
 	ntg.Disconnect = ntg.Disconnect.Copy()
@@ -7060,6 +7205,18 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	// Validate the template restart policy
+	if tr := tg.TemplateRestart; tr != nil {
+		switch j.Type {
+		case JobTypeService, JobTypeSystem:
+		default:
+			mErr = multierror.Append(mErr, fmt.Errorf("Job type %q does not allow template_restart block", j.Type))
+		}
+		if err := tr.Validate(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("Template restart validation failed: %v", err))
+		}
+	}
+
 	// Check that there is only one leader task if any
 	tasks := make(map[string]int)
 	leaderTasks := 0
@@ -7182,6 +7339,66 @@ func (tg *TaskGroup) validateNetworks() error {
 				mErr.Errors = append(mErr.Errors, errors.New("Hostname is not a valid DNS name"))
 			}
 		}
+
+		// Bandwidth limits are enforced on the interface of the allocation
+		// network namespace, so they require a mode that creates one.
+		if !net.Bandwidth.IsZero() {
+			if net.Mode != "bridge" && !strings.HasPrefix(net.Mode, "cni/") {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network bandwidth is only supported in bridge and cni network modes, got %q", net.Mode))
+			}
+			if err := net.Bandwidth.Validate(); err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network bandwidth is invalid: %w", err))
+			}
+		}
+	}
+
+	// Groups with multiple networks attach each of them in order to the
+	// allocation network namespace, so only the modes creating an interface
+	// inside the namespace are supported and namespace-wide settings must be
+	// set on the first network.
+	if len(tg.Networks) > 1 {
+		attached := make(map[string]struct{})
+		for i, net := range tg.Networks {
+			mode := net.Mode
+			switch {
+			case mode == "bridge":
+				if i != 0 {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: only the first network of a group may use bridge mode", i+1))
+				}
+			case strings.HasPrefix(mode, "cni/"):
+				if _, ok := attached[mode]; ok {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: CNI network %q is used by another network", i+1, strings.TrimPrefix(mode, "cni/")))
+				}
+				attached[mode] = struct{}{}
+			case strings.HasPrefix(mode, "host/"):
+				hostNetwork, _ := net.AttachedHostNetwork()
+				if hostNetwork == "" {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: mode %q must name a host network", i+1, mode))
+				}
+				if _, ok := attached[mode]; ok {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: host network %q is used by another network", i+1, hostNetwork))
+				}
+				attached[mode] = struct{}{}
+			default:
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: mode %q not supported in groups with multiple networks, must be bridge, cni/<name> or host/<name>", i+1, mode))
+			}
+
+			if i > 0 && net.Hostname != "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: hostname may only be set on the first network", i+1))
+			}
+			if i > 0 && net.DNS != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: dns may only be set on the first network", i+1))
+			}
+			if i > 0 && net.Bandwidth != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network %d: bandwidth may only be set on the first network", i+1))
+			}
+		}
+	}
+
+	// Host networks are attached to the allocation network namespace created
+	// by the first network, so they can't be the first network themselves.
+	if len(tg.Networks) > 0 && strings.HasPrefix(tg.Networks[0].Mode, "host/") {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Network 1: mode %q is only supported for the networks following a bridge or cni/<name> network", tg.Networks[0].Mode))
 	}
 
 	// Check for duplicate tasks or port labels, and no duplicated static ports
@@ -7494,6 +7711,120 @@ func (tg *TaskGroup) UsesConnectGateway() bool {
 	return false
 }
 
+// PrefetchImages returns the container images of the docker tasks of the
+// group, which may be pulled onto nodes before the group is placed. Images
+// interpolated at runtime are not known ahead of placement and are omitted.
+func (tg *TaskGroup) PrefetchImages() []string {
+	var images []string
+	for _, task := range tg.Tasks {
+		if task.Driver != "docker" {
+			continue
+		}
+		image, ok := task.Config["image"].(string)
+		if !ok || image == "" || strings.Contains(image, "${") {
+			continue
+		}
+		if !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// PrefetchImageAuths returns the registry authentication of the images
+// returned by PrefetchImages, for the images whose tasks set it. The auth block
+// of the first task using an image is used, and interpolated ones are omitted.
+func (tg *TaskGroup) PrefetchImageAuths() map[string]*PrefetchImageAuth {
+	auths := make(map[string]*PrefetchImageAuth)
+	for _, task := range tg.Tasks {
+		if task.Driver != "docker" {
+			continue
+		}
+		image, ok := task.Config["image"].(string)
+		if !ok || image == "" || strings.Contains(image, "${") {
+			continue
+		}
+		if _, ok := auths[image]; ok {
+			continue
+		}
+		if auth := prefetchImageAuth(task.Config["auth"]); auth != nil {
+			auths[image] = auth
+		}
+	}
+	return auths
+}
+
+// prefetchImageAuth returns the registry authentication of the auth block of
+// a docker task config, which is a list of blocks when parsed from HCL and an
+// object when submitted as JSON.
+func prefetchImageAuth(raw interface{}) *PrefetchImageAuth {
+	var block map[string]interface{}
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		block = v
+	case []map[string]interface{}:
+		if len(v) > 0 {
+			block = v[0]
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			block, _ = v[0].(map[string]interface{})
+		}
+	}
+	if len(block) == 0 {
+		return nil
+	}
+
+	field := func(key string) string {
+		value, _ := block[key].(string)
+		return value
+	}
+	auth := &PrefetchImageAuth{
+		Username:      field("username"),
+		Password:      field("password"),
+		Email:         field("email"),
+		ServerAddress: field("server_address"),
+	}
+	for _, value := range []string{auth.Username, auth.Password, auth.Email, auth.ServerAddress} {
+		if strings.Contains(value, "${") {
+			return nil
+		}
+	}
+	if *auth == (PrefetchImageAuth{}) {
+		return nil
+	}
+	return auth
+}
+
+// PrefetchArtifacts returns the artifacts of the tasks of the group, which may
+// be downloaded onto nodes before the group is placed. Artifacts interpolated
+// at runtime are not known ahead of placement and are omitted.
+func (tg *TaskGroup) PrefetchArtifacts() []*TaskArtifact {
+	var artifacts []*TaskArtifact
+	for _, task := range tg.Tasks {
+	ARTIFACTS:
+		for _, artifact := range task.Artifacts {
+			if strings.Contains(artifact.GetterSource, "${") {
+				continue
+			}
+			for _, v := range artifact.GetterOptions {
+				if strings.Contains(v, "${") {
+					continue ARTIFACTS
+				}
+			}
+			for _, v := range artifact.GetterHeaders {
+				if strings.Contains(v, "${") {
+					continue ARTIFACTS
+				}
+			}
+			if !slices.ContainsFunc(artifacts, artifact.Equal) {
+				artifacts = append(artifacts, artifact)
+			}
+		}
+	}
+	return artifacts
+}
+
 func (tg *TaskGroup) GoString() string {
 	return fmt.Sprintf("*%#v", *tg)
 }
@@ -7710,9 +8041,16 @@ type Task struct {
 	// group's Consul field.
 	Consul *Consul
 
+	// Nomad requests a Nomad ACL token for the task, linked to ACL roles.
+	Nomad *Nomad
+
 	// Templates are the set of templates to be rendered for the task.
 	Templates []*Template
 
+	// Secrets are the set of Nomad Variables and Vault secrets whose items
+	// are delivered to the task.
+	Secrets []*Secret
+
 	// Constraints can be specified at a task level and apply only to
 	// the particular task.
 	Constraints []*Constraint
@@ -7873,6 +8211,7 @@ func (t *Task) Copy() *Task {
 
 	nt.Vault = nt.Vault.Copy()
 	nt.Consul = nt.Consul.Copy()
+	nt.Nomad = nt.Nomad.Copy()
 	nt.Resources = nt.Resources.Copy()
 	nt.LogConfig = nt.LogConfig.Copy()
 	nt.Meta = maps.Clone(nt.Meta)
@@ -7904,6 +8243,14 @@ func (t *Task) Copy() *Task {
 		nt.Templates = templates
 	}
 
+	if t.Secrets != nil {
+		secrets := make([]*Secret, len(t.Secrets))
+		for i, secret := range nt.Secrets {
+			secrets[i] = secret.Copy()
+		}
+		nt.Secrets = secrets
+	}
+
 	return nt
 }
 
@@ -7956,10 +8303,18 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 		t.Vault.Canonicalize()
 	}
 
+	if t.Nomad != nil {
+		t.Nomad.Canonicalize()
+	}
+
 	for _, template := range t.Templates {
 		template.Canonicalize()
 	}
 
+	for _, secret := range t.Secrets {
+		secret.Canonicalize()
+	}
+
 	// Initialize default Nomad workload identity
 	defaultIdx := -1
 	for i, wid := range t.Identities {
@@ -8087,6 +8442,17 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 		}
 	}
 
+	// Validate Nomad. The token and the default workload identity can't both
+	// be exposed as the NOMAD_TOKEN environment variable.
+	if t.Nomad != nil {
+		if err := t.Nomad.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Nomad validation failed: %v", err))
+		}
+		if t.Nomad.Env && t.Identity != nil && t.Identity.Env {
+			mErr.Errors = append(mErr.Errors, errors.New("Nomad token and default identity cannot both set env"))
+		}
+	}
+
 	// Validate templates.
 	destinations := make(map[string]int, len(t.Templates))
 	for idx, tmpl := range t.Templates {
@@ -8103,6 +8469,24 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 		}
 	}
 
+	// Validate secrets.
+	secrets := make(map[string]bool, len(t.Secrets))
+	for _, secret := range t.Secrets {
+		if err := secret.Validate(); err != nil {
+			outer := fmt.Errorf("Secret %q validation failed: %s", secret.Name, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if secrets[secret.Name] {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Secret %q defined multiple times", secret.Name))
+		}
+		secrets[secret.Name] = true
+
+		if secret.Provider == SecretProviderVault && t.Vault == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Secret %q uses the vault provider but the task has no vault block", secret.Name))
+		}
+	}
+
 	// Validate actions.
 	actions := make(map[string]bool)
 	for _, action := range t.Actions {
@@ -9090,6 +9474,10 @@ const (
 	// failed.
 	TaskArtifactDownloadFailed = "Failed Artifact Download"
 
+	// TaskArtifactVerificationFailed indicates that the signature of an
+	// artifact could not be verified.
+	TaskArtifactVerificationFailed = "Failed Artifact Verification"
+
 	// TaskBuildingTaskDir indicates that the task directory/chroot is being
 	// built.
 	TaskBuildingTaskDir = "Building Task Directory"
@@ -9288,6 +9676,12 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Failed to download artifacts"
 		}
+	case TaskArtifactVerificationFailed:
+		if v := e.Details["verification_error"]; v != "" {
+			desc = v
+		} else {
+			desc = "Failed to verify artifact signature"
+		}
 	case TaskKilling:
 		if e.KillReason != "" {
 			desc = e.KillReason
@@ -9524,6 +9918,13 @@ func (e *TaskEvent) SetDownloadError(err error) *TaskEvent {
 	return e
 }
 
+func (e *TaskEvent) SetVerificationError(err error) *TaskEvent {
+	if err != nil {
+		e.Details["verification_error"] = err.Error()
+	}
+	return e
+}
+
 func (e *TaskEvent) SetValidationError(err error) *TaskEvent {
 	if err != nil {
 		e.ValidationError = err.Error()
@@ -9594,6 +9995,10 @@ type TaskArtifact struct {
 	// RelativeDest is the download destination given relative to the task's
 	// directory.
 	RelativeDest string
+
+	// Signature configures the verification of a detached signature of the
+	// artifact before it is extracted into the task's directory.
+	Signature *TaskArtifactSignature
 }
 
 func (ta *TaskArtifact) Equal(o *TaskArtifact) bool {
@@ -9613,6 +10018,8 @@ func (ta *TaskArtifact) Equal(o *TaskArtifact) bool {
 		return false
 	case ta.RelativeDest != o.RelativeDest:
 		return false
+	case !ta.Signature.Equal(o.Signature):
+		return false
 	}
 	return true
 }
@@ -9628,6 +10035,7 @@ func (ta *TaskArtifact) Copy() *TaskArtifact {
 		GetterMode:     ta.GetterMode,
 		GetterInsecure: ta.GetterInsecure,
 		RelativeDest:   ta.RelativeDest,
+		Signature:      ta.Signature.Copy(),
 	}
 }
 
@@ -9669,6 +10077,14 @@ func (ta *TaskArtifact) Hash() string {
 	_, _ = h.Write([]byte(ta.GetterMode))
 	_, _ = h.Write([]byte(strconv.FormatBool(ta.GetterInsecure)))
 	_, _ = h.Write([]byte(ta.RelativeDest))
+
+	if sig := ta.Signature; sig != nil {
+		_, _ = h.Write([]byte(sig.Source))
+		_, _ = h.Write([]byte(sig.Format))
+		for _, key := range sig.Keys {
+			_, _ = h.Write([]byte(key))
+		}
+	}
 	return base64.RawStdEncoding.EncodeToString(h.Sum(nil))
 }
 
@@ -9701,6 +10117,15 @@ func (ta *TaskArtifact) Validate() error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	if ta.Signature != nil {
+		if ta.GetterMode == GetterModeDir {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("signature cannot be verified for artifacts in %s mode", GetterModeDir))
+		}
+		if err := ta.Signature.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -9755,6 +10180,74 @@ func (ta *TaskArtifact) validateChecksum() error {
 	return nil
 }
 
+const (
+	// ArtifactSignatureFormatMinisign is the format of signatures created by
+	// minisign with prehashing, verified with Ed25519 public keys.
+	ArtifactSignatureFormatMinisign = "minisign"
+
+	// ArtifactSignatureFormatCosign is the format of keyed signatures created
+	// by "cosign sign-blob", verified with ECDSA public keys.
+	ArtifactSignatureFormatCosign = "cosign"
+)
+
+// TaskArtifactSignature is the detached signature of an artifact, verified
+// against the public keys trusted by the client.
+type TaskArtifactSignature struct {
+	// Source is the go-getter source of the signature. If empty, the signature
+	// is downloaded from the source of the artifact with the extension of the
+	// format appended to its path.
+	Source string
+
+	// Format is the format of the signature.
+	Format string
+
+	// Keys restricts the verification to the trusted keys of the client with
+	// the given names. If empty, any trusted key can verify the signature.
+	Keys []string
+}
+
+func (s *TaskArtifactSignature) Equal(o *TaskArtifactSignature) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	switch {
+	case s.Source != o.Source:
+		return false
+	case s.Format != o.Format:
+		return false
+	case !slices.Equal(s.Keys, o.Keys):
+		return false
+	}
+	return true
+}
+
+func (s *TaskArtifactSignature) Copy() *TaskArtifactSignature {
+	if s == nil {
+		return nil
+	}
+	return &TaskArtifactSignature{
+		Source: s.Source,
+		Format: s.Format,
+		Keys:   slices.Clone(s.Keys),
+	}
+}
+
+func (s *TaskArtifactSignature) Validate() error {
+	var mErr multierror.Error
+	switch s.Format {
+	case ArtifactSignatureFormatMinisign, ArtifactSignatureFormatCosign:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid signature format %q; must be one of: %s, %s",
+			s.Format, ArtifactSignatureFormatMinisign, ArtifactSignatureFormatCosign))
+	}
+	for _, key := range s.Keys {
+		if key == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("signature key names cannot be empty"))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 const (
 	ConstraintDistinctProperty  = "distinct_property"
 	ConstraintDistinctHosts     = "distinct_hosts"
@@ -10534,6 +11027,15 @@ func (d *Deployment) GetNamespace() string {
 	return d.Namespace
 }
 
+// GetJobID implements the JobIDGetter interface, required for filtering
+// deployments by the jobs a token has access to.
+func (d *Deployment) GetJobID() string {
+	if d == nil {
+		return ""
+	}
+	return d.JobID
+}
+
 // DeploymentState tracks the state of a deployment for a given task group.
 type DeploymentState struct {
 	// AutoRevert marks whether the task group has indicated the job should be
@@ -10953,6 +11455,15 @@ func (a *Allocation) GetNamespace() string {
 	return a.Namespace
 }
 
+// GetJobID implements the JobIDGetter interface, required for filtering
+// allocations by the jobs a token has access to.
+func (a *Allocation) GetJobID() string {
+	if a == nil {
+		return ""
+	}
+	return a.JobID
+}
+
 // GetCreateIndex implements the CreateIndexGetter interface, required for
 // pagination.
 func (a *Allocation) GetCreateIndex() uint64 {
@@ -12094,6 +12605,11 @@ type AllocNetworkStatus struct {
 	InterfaceName string
 	Address       string
 	DNS           *DNSConfig
+
+	// Interfaces is the status of each network of a group with multiple
+	// network blocks, in the order of the blocks. The InterfaceName and
+	// Address fields above always refer to the first network.
+	Interfaces []*AllocNetworkInterfaceStatus
 }
 
 func (a *AllocNetworkStatus) Copy() *AllocNetworkStatus {
@@ -12104,7 +12620,20 @@ func (a *AllocNetworkStatus) Copy() *AllocNetworkStatus {
 		InterfaceName: a.InterfaceName,
 		Address:       a.Address,
 		DNS:           a.DNS.Copy(),
+		Interfaces:    helper.CopySlice(a.Interfaces),
+	}
+}
+
+// AddressForPort returns the interface name and address of the network that
+// defines the port label. Labels not defined by a specific network resolve to
+// the first network of the allocation.
+func (a *AllocNetworkStatus) AddressForPort(label string) (string, string) {
+	for _, iface := range a.Interfaces {
+		if iface != nil && slices.Contains(iface.PortLabels, label) {
+			return iface.InterfaceName, iface.Address
+		}
 	}
+	return a.InterfaceName, a.Address
 }
 
 func (a *AllocNetworkStatus) Equal(o *AllocNetworkStatus) bool {
@@ -12125,6 +12654,8 @@ func (a *AllocNetworkStatus) Equal(o *AllocNetworkStatus) bool {
 		return false
 	case !a.DNS.Equal(o.DNS):
 		return false
+	case !slices.EqualFunc(a.Interfaces, o.Interfaces, (*AllocNetworkInterfaceStatus).Equal):
+		return false
 	}
 	return true
 }
@@ -12139,6 +12670,52 @@ func (a *AllocNetworkStatus) IsZero() bool {
 	if !a.DNS.IsZero() {
 		return false
 	}
+	if len(a.Interfaces) > 0 {
+		return false
+	}
+	return true
+}
+
+// AllocNetworkInterfaceStatus captures the status of one of the networks of
+// an allocation with multiple network blocks.
+type AllocNetworkInterfaceStatus struct {
+	// Mode is the mode of the network block, such as bridge or cni/<name>.
+	Mode string
+
+	// InterfaceName is the name of the interface inside the allocation
+	// network namespace.
+	InterfaceName string
+
+	// Address is the address of the interface.
+	Address string
+
+	// PortLabels are the labels of the ports defined by the network block.
+	PortLabels []string
+}
+
+func (a *AllocNetworkInterfaceStatus) Copy() *AllocNetworkInterfaceStatus {
+	if a == nil {
+		return nil
+	}
+	na := *a
+	na.PortLabels = slices.Clone(a.PortLabels)
+	return &na
+}
+
+func (a *AllocNetworkInterfaceStatus) Equal(o *AllocNetworkInterfaceStatus) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	switch {
+	case a.Mode != o.Mode:
+		return false
+	case a.InterfaceName != o.InterfaceName:
+		return false
+	case a.Address != o.Address:
+		return false
+	case !slices.Equal(a.PortLabels, o.PortLabels):
+		return false
+	}
 	return true
 }
 
@@ -12320,6 +12897,12 @@ const (
 	// active key
 	CoreJobVariablesRekey = "variables-rekey"
 
+	// CoreJobVariableVersionGC is used for the garbage collection of the
+	// previous versions of variables. We periodically scan for versions of
+	// deleted variables and versions beyond the namespace's limit and delete
+	// them.
+	CoreJobVariableVersionGC = "variable-version-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -12488,6 +13071,15 @@ func (e *Evaluation) GetNamespace() string {
 	return e.Namespace
 }
 
+// GetJobID implements the JobIDGetter interface, required for filtering
+// evaluations by the jobs a token has access to.
+func (e *Evaluation) GetJobID() string {
+	if e == nil {
+		return ""
+	}
+	return e.JobID
+}
+
 // GetCreateIndex implements the CreateIndexGetter interface, required for
 // pagination.
 func (e *Evaluation) GetCreateIndex() uint64 {
@@ -13382,6 +13974,20 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration
 
+	// Renewable indicates the token can be renewed, which extends its
+	// ExpirationTime to the time of the renewal plus its ExpirationTTL.
+	Renewable bool
+
+	// MaxTTL is the maximum lifetime of a renewable token since its creation,
+	// after which renewals will no longer extend its ExpirationTime. If zero,
+	// the region's maximum token expiration TTL is used.
+	MaxTTL time.Duration
+
+	// AllocID is the ID of the allocation the token was derived for by the
+	// nomad block of one of its tasks. These tokens are revoked when the
+	// allocation stops.
+	AllocID string
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -13457,6 +14063,8 @@ type ACLTokenListStub struct {
 	Hash           []byte
 	CreateTime     time.Time
 	ExpirationTime *time.Time
+	Renewable      bool
+	AllocID        string
 	CreateIndex    uint64
 	ModifyIndex    uint64
 }
@@ -13509,6 +14117,8 @@ func (a *ACLToken) Stub() *ACLTokenListStub {
 		Hash:           a.Hash,
 		CreateTime:     a.CreateTime,
 		ExpirationTime: a.ExpirationTime,
+		Renewable:      a.Renewable,
+		AllocID:        a.AllocID,
 		CreateIndex:    a.CreateIndex,
 		ModifyIndex:    a.ModifyIndex,
 	}
@@ -63,6 +63,26 @@ func TestNamespace_Validate(t *testing.T) {
 			},
 			Expected: "description longer than",
 		},
+		{
+			Test: "negative variables max versions",
+			Namespace: &Namespace{
+				Name: "foo",
+				VariablesConfiguration: &NamespaceVariablesConfiguration{
+					MaxVersions: pointer.Of(-1),
+				},
+			},
+			Expected: "invalid variables configuration",
+		},
+		{
+			Test: "too many variables max versions",
+			Namespace: &Namespace{
+				Name: "foo",
+				VariablesConfiguration: &NamespaceVariablesConfiguration{
+					MaxVersions: pointer.Of(101),
+				},
+			},
+			Expected: "invalid variables configuration",
+		},
 		{
 			Test: "valid",
 			Namespace: &Namespace{
@@ -1360,6 +1380,72 @@ func TestTaskGroup_UsesConnect(t *testing.T) {
 	})
 }
 
+func TestTaskGroup_Prefetch(t *testing.T) {
+	ci.Parallel(t)
+
+	tg := &TaskGroup{
+		Tasks: []*Task{
+			{
+				Driver: "docker",
+				Config: map[string]interface{}{"image": "redis:7"},
+				Artifacts: []*TaskArtifact{
+					{GetterSource: "https://example.com/app.tar.gz"},
+					{GetterSource: "https://example.com/${NOMAD_JOB_ID}.tar.gz"},
+					{
+						GetterSource:  "https://example.com/config.json",
+						GetterOptions: map[string]string{"checksum": "${meta.sum}"},
+					},
+				},
+			},
+			{
+				Driver: "docker",
+				Config: map[string]interface{}{"image": "redis:7"},
+				Artifacts: []*TaskArtifact{
+					{GetterSource: "https://example.com/app.tar.gz"},
+				},
+			},
+			{
+				Driver: "docker",
+				Config: map[string]interface{}{"image": "app:${NOMAD_META_version}"},
+			},
+			{
+				Driver: "docker",
+				Config: map[string]interface{}{
+					"image": "registry.example.com/private:1",
+					"auth": []map[string]interface{}{{
+						"username": "user",
+						"password": "secret",
+					}},
+				},
+			},
+			{
+				Driver: "docker",
+				Config: map[string]interface{}{
+					"image": "registry.example.com/vault:1",
+					"auth": map[string]interface{}{
+						"username": "user",
+						"password": "${NOMAD_SECRETS_DIR}/password",
+					},
+				},
+			},
+			{
+				Driver: "exec",
+				Config: map[string]interface{}{"image": "not-a-container"},
+			},
+		},
+	}
+
+	must.Eq(t, []string{
+		"redis:7",
+		"registry.example.com/private:1",
+		"registry.example.com/vault:1",
+	}, tg.PrefetchImages())
+	must.Eq(t, map[string]*PrefetchImageAuth{
+		"registry.example.com/private:1": {Username: "user", Password: "secret"},
+	}, tg.PrefetchImageAuths())
+	must.Eq(t, []*TaskArtifact{{GetterSource: "https://example.com/app.tar.gz"}}, tg.PrefetchArtifacts())
+}
+
 func TestTaskGroup_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2015,6 +2101,163 @@ func TestTaskGroupNetwork_Validate(t *testing.T) {
 			},
 			ErrContains: "Hostname is not a valid DNS name",
 		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-ok",
+				Networks: []*NetworkResource{
+					{Mode: "bridge", Hostname: "foobar"},
+					{Mode: "cni/storage", DynamicPorts: []Port{{Label: "nfs"}}},
+					{Mode: "cni/telemetry"},
+				},
+			},
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-host-mode",
+				Networks: []*NetworkResource{
+					{Mode: "host"},
+					{Mode: "cni/storage"},
+				},
+			},
+			ErrContains: `mode "host" not supported in groups with multiple networks`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-default-mode",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{},
+				},
+			},
+			ErrContains: `Network 2: mode "" not supported in groups with multiple networks`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-host-network",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{Mode: "host/storage", DynamicPorts: []Port{{Label: "nfs"}}},
+					{Mode: "host/telemetry"},
+				},
+			},
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-host-network-first",
+				Networks: []*NetworkResource{
+					{Mode: "host/storage"},
+					{Mode: "cni/telemetry"},
+				},
+			},
+			ErrContains: `Network 1: mode "host/storage" is only supported for the networks following a bridge or cni/<name> network`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "single-host-network",
+				Networks: []*NetworkResource{
+					{Mode: "host/storage"},
+				},
+			},
+			ErrContains: `mode "host/storage" is only supported for the networks following`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-duplicate-host-network",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{Mode: "host/storage"},
+					{Mode: "host/storage"},
+				},
+			},
+			ErrContains: `host network "storage" is used by another network`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-unnamed-host-network",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{Mode: "host/"},
+				},
+			},
+			ErrContains: `mode "host/" must name a host network`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-bridge-not-first",
+				Networks: []*NetworkResource{
+					{Mode: "cni/storage"},
+					{Mode: "bridge"},
+				},
+			},
+			ErrContains: "only the first network of a group may use bridge mode",
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-duplicate-cni",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{Mode: "cni/storage"},
+					{Mode: "cni/storage"},
+				},
+			},
+			ErrContains: `CNI network "storage" is used by another network`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-hostname-not-first",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{Mode: "cni/storage", Hostname: "foobar"},
+				},
+			},
+			ErrContains: "hostname may only be set on the first network",
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-duplicate-port-label",
+				Networks: []*NetworkResource{
+					{Mode: "bridge", DynamicPorts: []Port{{Label: "http"}}},
+					{Mode: "cni/storage", DynamicPorts: []Port{{Label: "http"}}},
+				},
+			},
+			ErrContains: "Port label http already in use",
+		},
+		{
+			TG: &TaskGroup{
+				Name: "bandwidth-bridge",
+				Networks: []*NetworkResource{
+					{Mode: "bridge", Bandwidth: &NetworkBandwidth{IngressMBits: 100, EgressMBits: 10}},
+				},
+			},
+		},
+		{
+			TG: &TaskGroup{
+				Name: "bandwidth-host",
+				Networks: []*NetworkResource{
+					{Mode: "host", Bandwidth: &NetworkBandwidth{EgressMBits: 10}},
+				},
+			},
+			ErrContains: `Network bandwidth is only supported in bridge and cni network modes, got "host"`,
+		},
+		{
+			TG: &TaskGroup{
+				Name: "bandwidth-negative",
+				Networks: []*NetworkResource{
+					{Mode: "cni/mynet", Bandwidth: &NetworkBandwidth{IngressMBits: -1}},
+				},
+			},
+			ErrContains: "ingress_mbits must be greater than or equal to 0",
+		},
+		{
+			TG: &TaskGroup{
+				Name: "multiple-networks-bandwidth-not-first",
+				Networks: []*NetworkResource{
+					{Mode: "bridge"},
+					{Mode: "cni/storage", Bandwidth: &NetworkBandwidth{EgressMBits: 10}},
+				},
+			},
+			ErrContains: "bandwidth may only be set on the first network",
+		},
 	}
 
 	for i := range cases {
@@ -4926,6 +5169,33 @@ func TestTaskArtifact_Hash(t *testing.T) {
 			GetterInsecure: true,
 			RelativeDest:   "i",
 		},
+		{
+			GetterSource: "b",
+			GetterOptions: map[string]string{
+				"c": "c",
+				"d": "e",
+			},
+			GetterMode:     "g",
+			GetterInsecure: true,
+			RelativeDest:   "i",
+			Signature: &TaskArtifactSignature{
+				Format: ArtifactSignatureFormatMinisign,
+			},
+		},
+		{
+			GetterSource: "b",
+			GetterOptions: map[string]string{
+				"c": "c",
+				"d": "e",
+			},
+			GetterMode:     "g",
+			GetterInsecure: true,
+			RelativeDest:   "i",
+			Signature: &TaskArtifactSignature{
+				Format: ArtifactSignatureFormatMinisign,
+				Keys:   []string{"release"},
+			},
+		},
 	}
 
 	// Map of hash to source
@@ -5048,6 +5318,32 @@ func TestAllocation_ShouldMigrate(t *testing.T) {
 	}
 }
 
+func TestTaskArtifact_Validate_Signature(t *testing.T) {
+	ci.Parallel(t)
+
+	artifact := &TaskArtifact{
+		GetterSource: "https://example.com/app.tar.gz",
+		GetterMode:   GetterModeAny,
+		RelativeDest: "local/",
+		Signature: &TaskArtifactSignature{
+			Format: ArtifactSignatureFormatCosign,
+			Keys:   []string{"release"},
+		},
+	}
+	must.NoError(t, artifact.Validate())
+
+	artifact.Signature.Format = "pgp"
+	must.ErrorContains(t, artifact.Validate(), `invalid signature format "pgp"`)
+
+	artifact.Signature.Format = ArtifactSignatureFormatMinisign
+	artifact.Signature.Keys = []string{""}
+	must.ErrorContains(t, artifact.Validate(), "signature key names cannot be empty")
+
+	artifact.Signature.Keys = nil
+	artifact.GetterMode = GetterModeDir
+	must.ErrorContains(t, artifact.Validate(), "signature cannot be verified for artifacts in dir mode")
+}
+
 func TestTaskArtifact_Validate_Checksum(t *testing.T) {
 	ci.Parallel(t)
 
@@ -8436,3 +8732,34 @@ func TestNewIdentityClaims(t *testing.T) {
 		})
 	}
 }
+
+func TestAllocNetworkStatus_AddressForPort(t *testing.T) {
+	ci.Parallel(t)
+
+	status := &AllocNetworkStatus{
+		InterfaceName: "eth0",
+		Address:       "172.26.64.2",
+		Interfaces: []*AllocNetworkInterfaceStatus{
+			{Mode: "bridge", InterfaceName: "eth0", Address: "172.26.64.2", PortLabels: []string{"http"}},
+			{Mode: "cni/storage", InterfaceName: "eth1", Address: "10.0.5.2", PortLabels: []string{"nfs"}},
+		},
+	}
+
+	ifname, addr := status.AddressForPort("nfs")
+	must.Eq(t, "eth1", ifname)
+	must.Eq(t, "10.0.5.2", addr)
+
+	ifname, addr = status.AddressForPort("http")
+	must.Eq(t, "eth0", ifname)
+	must.Eq(t, "172.26.64.2", addr)
+
+	// Unknown labels resolve to the first network.
+	ifname, addr = status.AddressForPort("other")
+	must.Eq(t, "eth0", ifname)
+	must.Eq(t, "172.26.64.2", addr)
+
+	must.True(t, status.Equal(status.Copy()))
+	other := status.Copy()
+	other.Interfaces[1].Address = "10.0.5.3"
+	must.False(t, status.Equal(other))
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// The template_restart block of a task group rolls the restarts triggered by
+// templates with change_mode "restart" across the allocations of the group.
+// Clients ask the leader for a restart slot before restarting a task, and
+// release the slot once the allocation is healthy again, so that a change to
+// a Variable or secret shared by many allocations doesn't restart all of them
+// at once.
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// TemplateRestartAcquireRPCMethod is the RPC method used by clients to
+	// acquire a slot before restarting a task whose template re-rendered.
+	//
+	// Args: TemplateRestartAcquireRequest
+	// Reply: TemplateRestartAcquireResponse
+	TemplateRestartAcquireRPCMethod = "TemplateRestart.Acquire"
+
+	// TemplateRestartReleaseRPCMethod is the RPC method used by clients to
+	// release the slot acquired for a restart, once the health of the
+	// restarted allocation is known.
+	//
+	// Args: TemplateRestartReleaseRequest
+	// Reply: GenericResponse
+	TemplateRestartReleaseRPCMethod = "TemplateRestart.Release"
+)
+
+// TemplateRestartPolicy configures how the restarts triggered by templates
+// are rolled across the allocations of a task group.
+type TemplateRestartPolicy struct {
+	// MaxParallel is the number of allocations of the group which may be
+	// restarting at once.
+	MaxParallel int
+
+	// Stagger is the time to wait after an allocation is healthy again
+	// before its slot is given to the next allocation.
+	Stagger time.Duration
+
+	// HealthCheck is the type of health check used to determine whether a
+	// restarted allocation is healthy: "checks" or "task_states".
+	HealthCheck string
+
+	// MinHealthyTime is the minimum time a restarted allocation must be
+	// healthy before its slot is released.
+	MinHealthyTime time.Duration
+
+	// HealthyDeadline is the time a restarted allocation has to become
+	// healthy before it is marked unhealthy. Restarts of the other
+	// allocations of the group are paused while one is unhealthy.
+	HealthyDeadline time.Duration
+}
+
+// DefaultTemplateRestartPolicy returns the default template restart policy.
+//
+// This function should match its counterpart in api/tasks.go
+func DefaultTemplateRestartPolicy() *TemplateRestartPolicy {
+	return &TemplateRestartPolicy{
+		MaxParallel:     1,
+		Stagger:         30 * time.Second,
+		HealthCheck:     UpdateStrategyHealthCheck_Checks,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: 5 * time.Minute,
+	}
+}
+
+func (p *TemplateRestartPolicy) Copy() *TemplateRestartPolicy {
+	if p == nil {
+		return nil
+	}
+	np := new(TemplateRestartPolicy)
+	*np = *p
+	return np
+}
+
+// UseChecks returns whether the health of restarted allocations includes the
+// status of their service checks.
+func (p *TemplateRestartPolicy) UseChecks() bool {
+	return p.HealthCheck == UpdateStrategyHealthCheck_Checks
+}
+
+func (p *TemplateRestartPolicy) Validate() error {
+	var mErr multierror.Error
+
+	if p.MaxParallel < 1 {
+		_ = multierror.Append(&mErr, fmt.Errorf("MaxParallel must be >= 1 but found %d", p.MaxParallel))
+	}
+
+	if p.Stagger < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Stagger is %s and must be >= 0", p.Stagger))
+	}
+
+	switch p.HealthCheck {
+	case UpdateStrategyHealthCheck_Checks, UpdateStrategyHealthCheck_TaskStates:
+		// ok
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid HealthCheck: %q", p.HealthCheck))
+	}
+
+	if p.MinHealthyTime < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("MinHealthyTime is %s and must be >= 0", p.MinHealthyTime))
+	}
+
+	if p.HealthyDeadline <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("HealthyDeadline is %s and must be > 0", p.HealthyDeadline))
+	}
+
+	if p.MinHealthyTime >= p.HealthyDeadline {
+		_ = multierror.Append(&mErr, fmt.Errorf("MinHealthyTime must be less than HealthyDeadline"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// TemplateRestartAcquireRequest is used by clients to acquire a slot before
+// restarting a task of an allocation because one of its templates
+// re-rendered.
+type TemplateRestartAcquireRequest struct {
+	AllocID string
+	WriteRequest
+}
+
+// Validate the request.
+func (r *TemplateRestartAcquireRequest) Validate() error {
+	if r.AllocID == "" {
+		return errors.New("missing allocation ID")
+	}
+	return nil
+}
+
+// TemplateRestartAcquireResponse is the response to a
+// TemplateRestartAcquireRequest.
+type TemplateRestartAcquireResponse struct {
+	// Granted is set if the client may restart the task. The client must
+	// release the slot once the health of the allocation is known.
+	Granted bool
+
+	// RetryAfter is the time the client should wait before asking again when
+	// the slot was not granted.
+	RetryAfter time.Duration
+
+	// Reason describes why the slot was not granted.
+	Reason string
+
+	WriteMeta
+}
+
+// TemplateRestartReleaseRequest is used by clients to release the slot
+// acquired for a restart.
+type TemplateRestartReleaseRequest struct {
+	AllocID string
+
+	// Healthy is whether the allocation became healthy after the restart.
+	// Restarts of the other allocations of the group are paused while it is
+	// unhealthy.
+	Healthy bool
+
+	WriteRequest
+}
+
+// Validate the request.
+func (r *TemplateRestartReleaseRequest) Validate() error {
+	if r.AllocID == "" {
+		return errors.New("missing allocation ID")
+	}
+	return nil
+}
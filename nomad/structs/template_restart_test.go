@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestTemplateRestartPolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		modifyFn func(*TemplateRestartPolicy)
+		expError string
+	}{
+		{
+			name:     "valid",
+			modifyFn: func(*TemplateRestartPolicy) {},
+		},
+		{
+			name:     "task states",
+			modifyFn: func(p *TemplateRestartPolicy) { p.HealthCheck = UpdateStrategyHealthCheck_TaskStates },
+		},
+		{
+			name:     "max parallel",
+			modifyFn: func(p *TemplateRestartPolicy) { p.MaxParallel = 0 },
+			expError: "MaxParallel must be >= 1",
+		},
+		{
+			name:     "negative stagger",
+			modifyFn: func(p *TemplateRestartPolicy) { p.Stagger = -time.Second },
+			expError: "Stagger is -1s and must be >= 0",
+		},
+		{
+			name:     "manual health check",
+			modifyFn: func(p *TemplateRestartPolicy) { p.HealthCheck = UpdateStrategyHealthCheck_Manual },
+			expError: `Invalid HealthCheck: "manual"`,
+		},
+		{
+			name:     "missing healthy deadline",
+			modifyFn: func(p *TemplateRestartPolicy) { p.HealthyDeadline = 0 },
+			expError: "HealthyDeadline is 0s and must be > 0",
+		},
+		{
+			name:     "min healthy time",
+			modifyFn: func(p *TemplateRestartPolicy) { p.MinHealthyTime = p.HealthyDeadline },
+			expError: "MinHealthyTime must be less than HealthyDeadline",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := DefaultTemplateRestartPolicy()
+			tc.modifyFn(p)
+			err := p.Validate()
+			if tc.expError == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expError)
+			}
+		})
+	}
+}
+
+func TestTaskGroup_Validate_TemplateRestart(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	tg := job.TaskGroups[0]
+	tg.TemplateRestart = DefaultTemplateRestartPolicy()
+	must.NoError(t, tg.Validate(job))
+
+	tgCopy := tg.Copy()
+	tgCopy.TemplateRestart.MaxParallel = 5
+	must.Eq(t, 1, tg.TemplateRestart.MaxParallel)
+
+	job.Type = JobTypeBatch
+	tg.Update = nil
+	tg.Migrate = nil
+	must.ErrorContains(t, tg.Validate(job), `Job type "batch" does not allow template_restart block`)
+}
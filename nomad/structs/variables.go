@@ -46,6 +46,28 @@ const (
 	// Reply: VariablesRenewLockResponse
 	VariablesRenewLockRPCMethod = "Variables.RenewLock"
 
+	// VariablesListVersionsRPCMethod is the RPC method for listing the
+	// current and previous versions of a variable according to its namespace
+	// and path.
+	//
+	// Args: VariablesListVersionsRequest
+	// Reply: VariablesListVersionsResponse
+	VariablesListVersionsRPCMethod = "Variables.ListVersions"
+
+	// VariablesReadVersionRPCMethod is the RPC method for fetching a specific
+	// version of a variable according to its namespace, path and version.
+	//
+	// Args: VariablesReadVersionRequest
+	// Reply: VariablesReadResponse
+	VariablesReadVersionRPCMethod = "Variables.ReadVersion"
+
+	// VariablesDeleteVersionsRPCMethod is the RPC method used by the core
+	// scheduler to garbage collect previous versions of variables.
+	//
+	// Args: VariablesDeleteVersionsRequest
+	// Reply: GenericResponse
+	VariablesDeleteVersionsRPCMethod = "Variables.DeleteVersions"
+
 	// maxVariableSize is the maximum size of the unencrypted contents of a
 	// variable. This size is deliberately set low and is not configurable, to
 	// discourage DoS'ing the cluster
@@ -56,6 +78,10 @@ const (
 	minVariableLockTTL = 10 * time.Second
 	maxVariableLockTTL = 24 * time.Hour
 
+	// minVariableTTL is the shortest TTL a variable can be written with, so
+	// that a variable can't expire before it has a chance to be read.
+	minVariableTTL = 10 * time.Second
+
 	// defaultLockTTL is the default value used to maintain a lock before it needs to
 	// be renewed. The actual value comes from the experience with Consul.
 	defaultLockTTL = 15 * time.Second
@@ -64,6 +90,14 @@ const (
 	// went by without any renews. It is intended to prevent split brain situations.
 	// The actual value comes from the experience with Consul.
 	defaultLockDelay = 15 * time.Second
+
+	// DefaultVariableMaxVersions is the number of previous versions of each
+	// variable kept when the namespace doesn't configure its own limit.
+	DefaultVariableMaxVersions = 10
+
+	// maxVariableMaxVersions is the largest number of previous versions of
+	// each variable a namespace can be configured to keep.
+	maxVariableMaxVersions = 100
 )
 
 var (
@@ -74,6 +108,7 @@ var (
 	errQuotaExhausted     = errors.New("variables are limited to 64KiB in total size")
 	errNegativeDelayOrTTL = errors.New("Lock delay and TTL must be positive")
 	errInvalidTTL         = errors.New("TTL must be between 10 seconds and 24 hours")
+	errInvalidVariableTTL = fmt.Errorf("variable TTL must be zero or at least %v", minVariableTTL)
 )
 
 // VariableMetadata is the metadata envelope for a Variable, it is the list
@@ -86,6 +121,19 @@ type VariableMetadata struct {
 	// Lock represents a variable which is used for locking functionality.
 	Lock *VariableLock `json:",omitempty"`
 
+	// Version is incremented each time the contents of the variable change.
+	// Variables written before versioning was introduced have version 0.
+	Version uint64
+
+	// TTL is the optional duration after which the variable expires and is
+	// deleted, measured from the last time the variable was written.
+	TTL time.Duration
+
+	// ExpireTime is the Unix nanosecond time at which the variable expires.
+	// It's set by the server from the TTL each time the variable is written,
+	// and is zero if the variable doesn't expire.
+	ExpireTime int64
+
 	CreateIndex uint64
 	CreateTime  int64
 	ModifyIndex uint64
@@ -99,6 +147,29 @@ type VariableEncrypted struct {
 	VariableData
 }
 
+// VariableVersion is a previous version of a variable, kept in the
+// variable's history so that it can be read or restored after the variable
+// has been overwritten or deleted.
+type VariableVersion struct {
+	VariableEncrypted
+
+	// ArchiveIndex is the index at which this version was replaced by a
+	// newer version or the variable was deleted.
+	ArchiveIndex uint64
+}
+
+// Copy returns a copy of the version that can be modified without touching
+// the original.
+func (vv *VariableVersion) Copy() *VariableVersion {
+	if vv == nil {
+		return nil
+	}
+	return &VariableVersion{
+		VariableEncrypted: vv.VariableEncrypted.Copy(),
+		ArchiveIndex:      vv.ArchiveIndex,
+	}
+}
+
 // VariableData is the secret data for a Variable
 type VariableData struct {
 	Data  []byte // includes nonce
@@ -291,6 +362,15 @@ func (sv VariableMetadata) Equal(vm2 VariableMetadata) bool {
 	if sv.Path != vm2.Path {
 		return false
 	}
+	if sv.Version != vm2.Version {
+		return false
+	}
+	if sv.TTL != vm2.TTL {
+		return false
+	}
+	if sv.ExpireTime != vm2.ExpireTime {
+		return false
+	}
 	if sv.CreateIndex != vm2.CreateIndex {
 		return false
 	}
@@ -384,6 +464,10 @@ func (vd VariableDecrypted) Validate() error {
 		return err
 	}
 
+	if vd.TTL != 0 && vd.TTL < minVariableTTL {
+		return errInvalidVariableTTL
+	}
+
 	if vd.Lock != nil {
 		return vd.Lock.Validate()
 	}
@@ -408,6 +492,10 @@ func (vd VariableDecrypted) ValidateForLock() error {
 		return err
 	}
 
+	if vd.TTL != 0 && vd.TTL < minVariableTTL {
+		return errInvalidVariableTTL
+	}
+
 	return vd.Lock.Validate()
 }
 
@@ -501,6 +589,12 @@ func (sv *VariableMetadata) LockID() string {
 // locking.
 func (sv *VariableMetadata) IsLock() bool { return sv.Lock != nil }
 
+// IsExpired returns whether the variable has a TTL which expired at or
+// before the given time.
+func (sv *VariableMetadata) IsExpired(now time.Time) bool {
+	return sv.ExpireTime != 0 && sv.ExpireTime <= now.UnixNano()
+}
+
 // VariablesQuota is used to track the total size of variables entries per
 // namespace. The total length of Variable.EncryptedData in bytes will be added
 // to the VariablesQuota table in the same transaction as a write, update, or
@@ -541,6 +635,11 @@ const (
 	// VarOpLockRelease is the variable operation used when attempting to
 	// release a held variable lock.
 	VarOpLockRelease VarOp = "lock-release"
+
+	// VarOpRekey is the variable operation used by the core scheduler to
+	// re-encrypt a variable, or one of its previous versions, with the active
+	// key without creating a new version.
+	VarOpRekey VarOp = "rekey"
 )
 
 // VarOpResult constants give possible operations results from a transaction.
@@ -670,6 +769,36 @@ type VariablesReadResponse struct {
 	QueryMeta
 }
 
+// VariablesListVersionsRequest is used to list the current and previous
+// versions of a variable.
+type VariablesListVersionsRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesListVersionsResponse holds the metadata of the versions of a
+// variable, newest first.
+type VariablesListVersionsResponse struct {
+	Versions []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesReadVersionRequest is used to read a specific version of a
+// variable, which may be the current version or a previous one.
+type VariablesReadVersionRequest struct {
+	Path    string
+	Version uint64
+	QueryOptions
+}
+
+// VariablesDeleteVersionsRequest is used to delete previous versions of
+// variables. The versions are identified by their namespace, path and
+// version.
+type VariablesDeleteVersionsRequest struct {
+	Versions []*VariableMetadata
+	WriteRequest
+}
+
 // VariablesRenewLockRequest is used to renew the lease on a lock. This request
 // behaves like a write because the renewal needs to be forwarded to the leader
 // where the timers and lock work is kept.
@@ -259,6 +259,44 @@ func TestVariableMetadata_IsLock(t *testing.T) {
 	}
 }
 
+func TestVariableMetadata_IsExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+
+	testCases := []struct {
+		name                  string
+		inputVariableMetadata *VariableMetadata
+		expectedOutput        bool
+	}{
+		{
+			name:                  "no expiry",
+			inputVariableMetadata: &VariableMetadata{},
+			expectedOutput:        false,
+		},
+		{
+			name: "expires later",
+			inputVariableMetadata: &VariableMetadata{
+				ExpireTime: now.Add(time.Minute).UnixNano(),
+			},
+			expectedOutput: false,
+		},
+		{
+			name: "expired",
+			inputVariableMetadata: &VariableMetadata{
+				ExpireTime: now.Add(-time.Minute).UnixNano(),
+			},
+			expectedOutput: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.expectedOutput, tc.inputVariableMetadata.IsExpired(now))
+		})
+	}
+}
+
 func TestStructs_VariableDecrypted_Copy(t *testing.T) {
 	ci.Parallel(t)
 	n := time.Now()
@@ -320,6 +358,27 @@ func TestStructs_VariableDecrypted_Validate(t *testing.T) {
 	}
 }
 
+func TestStructs_VariableDecrypted_Validate_TTL(t *testing.T) {
+	ci.Parallel(t)
+
+	sv := VariableDecrypted{
+		VariableMetadata: VariableMetadata{Namespace: "a", Path: "a/b/c"},
+		Items:            VariableItems{"foo": "bar"},
+	}
+
+	sv.TTL = 0
+	must.NoError(t, sv.Validate())
+
+	sv.TTL = time.Hour
+	must.NoError(t, sv.Validate())
+
+	sv.TTL = time.Second
+	must.ErrorIs(t, sv.Validate(), errInvalidVariableTTL)
+
+	sv.TTL = -time.Hour
+	must.ErrorIs(t, sv.Validate(), errInvalidVariableTTL)
+}
+
 func TestStructs_VariablesRenewLockRequest_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5,6 +5,7 @@ package structs
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"slices"
 	"strings"
@@ -47,6 +48,10 @@ const (
 
 	// WIChangeModeRestart restarts the task when a new token is retrieved.
 	WIChangeModeRestart = "restart"
+
+	// SPIFFETrustDomainSuffix is appended to the region name to form the
+	// SPIFFE trust domain of the X.509 SVIDs signed in the region.
+	SPIFFETrustDomainSuffix = ".nomad"
 )
 
 var (
@@ -96,6 +101,10 @@ type WorkloadIdentity struct {
 	// TTL is used to determine the expiration of the credentials created for
 	// this identity (eg the JWT "exp" claim).
 	TTL time.Duration
+
+	// X509 requests an X.509 SVID for this identity in addition to the JWT.
+	// The SVID has the same expiration as the JWT, and is rotated with it.
+	X509 bool
 }
 
 // IsConsul returns true if the identity name starts with the standard prefix
@@ -124,6 +133,7 @@ func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
 		File:         wi.File,
 		ServiceName:  wi.ServiceName,
 		TTL:          wi.TTL,
+		X509:         wi.X509,
 	}
 }
 
@@ -164,6 +174,10 @@ func (wi *WorkloadIdentity) Equal(other *WorkloadIdentity) bool {
 		return false
 	}
 
+	if wi.X509 != other.X509 {
+		return false
+	}
+
 	return true
 }
 
@@ -228,6 +242,10 @@ func (wi *WorkloadIdentity) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("ttl must be >= 0"))
 	}
 
+	if wi.X509 && wi.TTL <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("x509 requires a ttl"))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -264,6 +282,11 @@ func (wi *WorkloadIdentity) Warnings() error {
 type WorkloadIdentityRequest struct {
 	AllocID string
 	WIHandle
+
+	// CSR is the ASN.1 DER encoded certificate signing request for the X.509
+	// SVID of identities with X509 set. The client generates the private key
+	// so that it never leaves the node.
+	CSR []byte
 }
 
 // SignedWorkloadIdentity is the response to a WorkloadIdentityRequest and
@@ -272,6 +295,16 @@ type SignedWorkloadIdentity struct {
 	WorkloadIdentityRequest
 	JWT        string
 	Expiration time.Time
+
+	// X509SVID is the PEM encoded X.509 SVID signed for the CSR of the
+	// request, and X509Bundle the PEM encoded CA certificates of the trust
+	// domain. Both are empty for identities without X509 set.
+	X509SVID   []byte
+	X509Bundle []byte
+
+	// X509Key is the PEM encoded private key of the X.509 SVID. It is only
+	// ever set by the client which generated it, and never sent by servers.
+	X509Key []byte
 }
 
 // WorkloadIdentityRejection is the response to a WorkloadIdentityRequest that
@@ -326,3 +359,16 @@ func (w *WIHandle) Equal(o WIHandle) bool {
 		w.WorkloadIdentifier == o.WorkloadIdentifier &&
 		w.WorkloadType == o.WorkloadType
 }
+
+// SPIFFEID returns the SPIFFE ID of the X.509 SVIDs signed for the identity
+// claims, in the trust domain of the region. Service identities are
+// identified by their service, and task identities by their task.
+func (claims *IdentityClaims) SPIFFEID(region string) string {
+	workload := "task/" + url.PathEscape(claims.TaskName)
+	if claims.ServiceName != "" {
+		workload = "svc/" + url.PathEscape(claims.ServiceName)
+	}
+	return fmt.Sprintf("spiffe://%s%s/ns/%s/job/%s/%s",
+		region, SPIFFETrustDomainSuffix,
+		url.PathEscape(claims.Namespace), url.PathEscape(claims.JobID), workload)
+}
@@ -64,6 +64,12 @@ func TestWorkloadIdentity_Equal(t *testing.T) {
 
 	newWI.TTL = 123 * time.Hour
 	must.NotEqual(t, orig, newWI)
+
+	newWI.TTL = orig.TTL
+	must.Equal(t, orig, newWI)
+
+	newWI.X509 = true
+	must.NotEqual(t, orig, newWI)
 }
 
 // TestWorkloadIdentity_Validate asserts that canonicalized workload identities
@@ -244,6 +250,15 @@ func TestWorkloadIdentity_Validate(t *testing.T) {
 			},
 			Warn: "identities without an expiration are insecure",
 		},
+		{
+			Desc: "X509 without TTL",
+			In: WorkloadIdentity{
+				Name:     "foo",
+				Audience: []string{"foo"},
+				X509:     true,
+			},
+			Err: "x509 requires a ttl",
+		},
 	}
 
 	for _, tc := range cases {
@@ -272,6 +287,22 @@ func TestWorkloadIdentity_Validate(t *testing.T) {
 	}
 }
 
+func TestIdentityClaims_SPIFFEID(t *testing.T) {
+	ci.Parallel(t)
+
+	claims := &IdentityClaims{
+		Namespace: "prod",
+		JobID:     "web app",
+		TaskName:  "server",
+	}
+	must.Eq(t, "spiffe://global.nomad/ns/prod/job/web%20app/task/server",
+		claims.SPIFFEID("global"))
+
+	claims.ServiceName = "api"
+	must.Eq(t, "spiffe://eu.nomad/ns/prod/job/web%20app/svc/api",
+		claims.SPIFFEID("eu"))
+}
+
 func TestWorkloadIdentity_Nil(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// templateRestartRetryInterval is how long clients are asked to wait
+	// before asking again for a slot while the group is paused or all its
+	// slots are held by restarting allocations.
+	templateRestartRetryInterval = 5 * time.Second
+
+	// templateRestartLeaseGrace is added to the healthy deadline of the policy
+	// to expire the slots of clients which never released them.
+	templateRestartLeaseGrace = time.Minute
+)
+
+// templateRestartKey identifies the task group whose template restarts are
+// coordinated.
+type templateRestartKey struct {
+	namespace string
+	jobID     string
+	group     string
+}
+
+// templateRestartGroup tracks the template restarts of the allocations of a
+// task group.
+type templateRestartGroup struct {
+	// leases maps the IDs of the restarting allocations to the time their
+	// slot expires if not released.
+	leases map[string]time.Time
+
+	// cooldowns are the times at which the slots released by healthy
+	// allocations can be given out again.
+	cooldowns []time.Time
+
+	// failed maps the IDs of the allocations which were unhealthy after
+	// their restart to the version of their job at that time. The restarts
+	// of the other allocations of the group are paused while any failed.
+	failed map[string]uint64
+}
+
+func (g *templateRestartGroup) empty() bool {
+	return len(g.leases) == 0 && len(g.cooldowns) == 0 && len(g.failed) == 0
+}
+
+// templateRestarts rolls the restarts triggered by templates across the
+// allocations of each task group with a template restart policy. It runs on
+// the leader and only keeps its state in memory: a new leader starts without
+// any restart in progress, so up to twice the number of allocations allowed
+// by a policy may restart at once after a leader election.
+type templateRestarts struct {
+	logger log.Logger
+
+	groups map[templateRestartKey]*templateRestartGroup
+	lock   sync.Mutex
+}
+
+func newTemplateRestarts(logger log.Logger) *templateRestarts {
+	return &templateRestarts{
+		logger: logger.Named("template_restart"),
+		groups: make(map[templateRestartKey]*templateRestartGroup),
+	}
+}
+
+// reset forgets the restarts in progress. It is called when leadership is
+// lost.
+func (t *templateRestarts) reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.groups = make(map[templateRestartKey]*templateRestartGroup)
+}
+
+// acquire returns whether the allocation may restart now, or how long to wait
+// before asking again. The lookup function returns the current allocations
+// of the group by ID, and nil for allocations which no longer exist.
+func (t *templateRestarts) acquire(alloc *structs.Allocation, policy *structs.TemplateRestartPolicy,
+	lookup func(string) *structs.Allocation, now time.Time) *structs.TemplateRestartAcquireResponse {
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := templateRestartKey{alloc.Namespace, alloc.JobID, alloc.TaskGroup}
+	g, ok := t.groups[key]
+	if !ok {
+		g = &templateRestartGroup{
+			leases: make(map[string]time.Time),
+			failed: make(map[string]uint64),
+		}
+		t.groups[key] = g
+	}
+	t.prune(g, lookup, now)
+
+	resp := &structs.TemplateRestartAcquireResponse{}
+	_, renew := g.leases[alloc.ID]
+	_, retry := g.failed[alloc.ID]
+
+	switch {
+	case renew:
+		// The client didn't see the response granting the slot
+
+	case len(g.failed) > 0 && !retry:
+		// Allocations which failed may restart again, as the new contents of
+		// their templates may fix them
+		for allocID := range g.failed {
+			resp.Reason = fmt.Sprintf("restarts paused: allocation %s is unhealthy after its restart", allocID)
+			break
+		}
+		resp.RetryAfter = templateRestartRetryInterval
+		return resp
+
+	case len(g.leases)+len(g.cooldowns) >= policy.MaxParallel:
+		resp.Reason = fmt.Sprintf("%d of %d allocations restarting", len(g.leases), policy.MaxParallel)
+		resp.RetryAfter = templateRestartRetryInterval
+		for _, cooldown := range g.cooldowns {
+			if wait := cooldown.Sub(now); wait < resp.RetryAfter {
+				resp.RetryAfter = wait
+			}
+		}
+		return resp
+	}
+
+	g.leases[alloc.ID] = now.Add(policy.HealthyDeadline + templateRestartLeaseGrace)
+	resp.Granted = true
+	return resp
+}
+
+// release releases the slot held by the allocation, and records whether the
+// allocation was healthy after its restart.
+func (t *templateRestarts) release(alloc *structs.Allocation, policy *structs.TemplateRestartPolicy,
+	healthy bool, now time.Time) {
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := templateRestartKey{alloc.Namespace, alloc.JobID, alloc.TaskGroup}
+	g, ok := t.groups[key]
+	if !ok {
+		return
+	}
+
+	_, leased := g.leases[alloc.ID]
+	delete(g.leases, alloc.ID)
+
+	if healthy {
+		delete(g.failed, alloc.ID)
+		if leased && policy.Stagger > 0 {
+			g.cooldowns = append(g.cooldowns, now.Add(policy.Stagger))
+		}
+	} else {
+		t.logger.Warn("pausing template restarts of task group: allocation unhealthy after restart",
+			"namespace", alloc.Namespace, "job_id", alloc.JobID, "task_group", alloc.TaskGroup,
+			"alloc_id", alloc.ID)
+		g.failed[alloc.ID] = alloc.Job.Version
+	}
+
+	if g.empty() {
+		delete(t.groups, key)
+	}
+}
+
+// prune removes the expired slots and cooldowns of the group, and the
+// allocations which stopped. Failed allocations whose job was updated no
+// longer pause the group.
+func (t *templateRestarts) prune(g *templateRestartGroup, lookup func(string) *structs.Allocation, now time.Time) {
+	for allocID, expiry := range g.leases {
+		if alloc := lookup(allocID); alloc == nil || alloc.TerminalStatus() || !now.Before(expiry) {
+			delete(g.leases, allocID)
+		}
+	}
+
+	for allocID, version := range g.failed {
+		if alloc := lookup(allocID); alloc == nil || alloc.TerminalStatus() || alloc.Job.Version != version {
+			delete(g.failed, allocID)
+		}
+	}
+
+	cooldowns := g.cooldowns[:0]
+	for _, cooldown := range g.cooldowns {
+		if now.Before(cooldown) {
+			cooldowns = append(cooldowns, cooldown)
+		}
+	}
+	g.cooldowns = cooldowns
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// TemplateRestart endpoint is used by clients to roll the restarts triggered
+// by templates across the allocations of a task group.
+type TemplateRestart struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewTemplateRestartEndpoint(srv *Server, ctx *RPCContext) *TemplateRestart {
+	return &TemplateRestart{srv: srv, ctx: ctx}
+}
+
+// Acquire is used by clients to acquire a slot before restarting a task whose
+// template re-rendered. Clients which are not granted a slot must ask again
+// after the returned delay.
+func (t *TemplateRestart) Acquire(args *structs.TemplateRestartAcquireRequest, reply *structs.TemplateRestartAcquireResponse) error {
+	aclObj, authErr := t.srv.AuthenticateClientOnly(t.ctx, args)
+	if done, err := t.srv.forward(structs.TemplateRestartAcquireRPCMethod, args, args, reply); done {
+		return err
+	}
+	t.srv.MeasureRPCRate("template_restart", structs.RateMetricWrite, args)
+	if authErr != nil || !aclObj.AllowClientOp() {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "template_restart", "acquire"}, time.Now())
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid request: %v", err)
+	}
+
+	snap, alloc, policy, err := t.lookup(args.AllocID, args.GetIdentity().ClientID)
+	if err != nil {
+		return err
+	}
+
+	// The group no longer has a policy, so the restart doesn't need to wait
+	if policy == nil {
+		reply.Granted = true
+		return nil
+	}
+
+	lookup := func(allocID string) *structs.Allocation {
+		alloc, _ := snap.AllocByID(nil, allocID)
+		return alloc
+	}
+	*reply = *t.srv.templateRestarts.acquire(alloc, policy, lookup, time.Now())
+	return nil
+}
+
+// Release is used by clients to release the slot acquired for a restart, once
+// the health of the restarted allocation is known.
+func (t *TemplateRestart) Release(args *structs.TemplateRestartReleaseRequest, reply *structs.GenericResponse) error {
+	aclObj, authErr := t.srv.AuthenticateClientOnly(t.ctx, args)
+	if done, err := t.srv.forward(structs.TemplateRestartReleaseRPCMethod, args, args, reply); done {
+		return err
+	}
+	t.srv.MeasureRPCRate("template_restart", structs.RateMetricWrite, args)
+	if authErr != nil || !aclObj.AllowClientOp() {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "template_restart", "release"}, time.Now())
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid request: %v", err)
+	}
+
+	_, alloc, policy, err := t.lookup(args.AllocID, args.GetIdentity().ClientID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+
+	t.srv.templateRestarts.release(alloc, policy, args.Healthy, time.Now())
+	return nil
+}
+
+// lookup returns the allocation, which must be running on the node making the
+// request, and the template restart policy of its task group. The policy of
+// the latest version of the job is used so that updates to the policy apply
+// to allocations which are updated in place.
+func (t *TemplateRestart) lookup(allocID, nodeID string) (*state.StateSnapshot, *structs.Allocation, *structs.TemplateRestartPolicy, error) {
+	snap, err := t.srv.State().Snapshot()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	alloc, err := snap.AllocByID(nil, allocID)
+	if err != nil {
+		return nil, nil, nil, structs.NewErrRPCCodedf(http.StatusInternalServerError, "alloc lookup failed: %v", err)
+	}
+	if alloc == nil {
+		return nil, nil, nil, structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find allocation %s", allocID)
+	}
+	if alloc.NodeID != nodeID {
+		return nil, nil, nil, structs.ErrPermissionDenied
+	}
+
+	job, err := snap.JobByID(nil, alloc.Namespace, alloc.JobID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if job == nil {
+		job = alloc.Job
+	}
+	tg := job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return snap, alloc, nil, nil
+	}
+	return snap, alloc, tg.TemplateRestart, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestTemplateRestartEndpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	otherNode := mock.Node()
+	must.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 10, node))
+	must.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 11, otherNode))
+
+	job := mock.Job()
+	job.TaskGroups[0].TemplateRestart = &structs.TemplateRestartPolicy{
+		MaxParallel:     1,
+		Stagger:         time.Minute,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_Checks,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: 5 * time.Minute,
+	}
+	must.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 20, nil, job))
+
+	alloc1 := mock.Alloc()
+	alloc1.Job = job
+	alloc1.JobID = job.ID
+	alloc1.NodeID = node.ID
+	alloc2 := alloc1.Copy()
+	alloc2.ID = uuid.Generate()
+	must.NoError(t, s1.fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 30,
+		[]*structs.Allocation{alloc1, alloc2}))
+
+	acquire := func(allocID, authToken string) (*structs.TemplateRestartAcquireResponse, error) {
+		req := &structs.TemplateRestartAcquireRequest{
+			AllocID: allocID,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: authToken,
+			},
+		}
+		var resp structs.TemplateRestartAcquireResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.TemplateRestartAcquireRPCMethod, req, &resp)
+		return &resp, err
+	}
+
+	// Only the node running the allocation can acquire a slot
+	_, err := acquire(alloc1.ID, root.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	_, err = acquire(alloc1.ID, otherNode.SecretID)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	resp, err := acquire(alloc1.ID, node.SecretID)
+	must.NoError(t, err)
+	must.True(t, resp.Granted)
+
+	resp, err = acquire(alloc2.ID, node.SecretID)
+	must.NoError(t, err)
+	must.False(t, resp.Granted)
+	must.Positive(t, resp.RetryAfter)
+
+	// Releasing the slot starts the stagger
+	release := &structs.TemplateRestartReleaseRequest{
+		AllocID: alloc1.ID,
+		Healthy: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var releaseResp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.TemplateRestartReleaseRPCMethod, release, &releaseResp))
+
+	resp, err = acquire(alloc2.ID, node.SecretID)
+	must.NoError(t, err)
+	must.False(t, resp.Granted)
+	must.Greater(t, templateRestartRetryInterval-time.Second, resp.RetryAfter)
+
+	// Groups without a policy don't wait
+	job2 := job.Copy()
+	job2.TaskGroups[0].TemplateRestart = nil
+	job2.Version++
+	must.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 40, nil, job2))
+
+	resp, err = acquire(alloc2.ID, node.SecretID)
+	must.NoError(t, err)
+	must.True(t, resp.Granted)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestTemplateRestarts(t *testing.T) {
+	ci.Parallel(t)
+
+	policy := &structs.TemplateRestartPolicy{
+		MaxParallel:     2,
+		Stagger:         30 * time.Second,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_Checks,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: time.Minute,
+	}
+
+	job := mock.Job()
+	allocs := make(map[string]*structs.Allocation)
+	var ids []string
+	for i := 0; i < 4; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		allocs[alloc.ID] = alloc
+		ids = append(ids, alloc.ID)
+	}
+	lookup := func(allocID string) *structs.Allocation { return allocs[allocID] }
+
+	restarts := newTemplateRestarts(hclog.NewNullLogger())
+	now := time.Now()
+
+	acquire := func(allocID string) *structs.TemplateRestartAcquireResponse {
+		return restarts.acquire(allocs[allocID], policy, lookup, now)
+	}
+
+	// Up to max_parallel allocations restart at once
+	must.True(t, acquire(ids[0]).Granted)
+	must.True(t, acquire(ids[1]).Granted)
+	resp := acquire(ids[2])
+	must.False(t, resp.Granted)
+	must.Eq(t, templateRestartRetryInterval, resp.RetryAfter)
+	must.Eq(t, "2 of 2 allocations restarting", resp.Reason)
+
+	// Asking again for a slot already held renews it
+	must.True(t, acquire(ids[0]).Granted)
+
+	// A released slot is given out again after the stagger
+	restarts.release(allocs[ids[0]], policy, true, now)
+	now = now.Add(time.Second)
+	resp = acquire(ids[2])
+	must.False(t, resp.Granted)
+	must.Eq(t, templateRestartRetryInterval, resp.RetryAfter)
+
+	now = now.Add(policy.Stagger)
+	must.True(t, acquire(ids[2]).Granted)
+
+	// An unhealthy allocation pauses the restarts of the group, but may
+	// restart again itself
+	restarts.release(allocs[ids[1]], policy, false, now)
+	restarts.release(allocs[ids[2]], policy, true, now)
+	now = now.Add(policy.Stagger)
+	resp = acquire(ids[3])
+	must.False(t, resp.Granted)
+	must.StrContains(t, resp.Reason, "restarts paused")
+	must.True(t, acquire(ids[1]).Granted)
+
+	// Once healthy, the restarts resume
+	restarts.release(allocs[ids[1]], policy, true, now)
+	now = now.Add(policy.Stagger)
+	must.True(t, acquire(ids[3]).Granted)
+	restarts.release(allocs[ids[3]], policy, true, now)
+
+	// A failed allocation no longer pauses the group once it stopped
+	restarts.release(allocs[ids[0]], policy, false, now)
+	now = now.Add(policy.Stagger)
+	must.False(t, acquire(ids[2]).Granted)
+	stopped := allocs[ids[0]].Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	allocs[ids[0]] = stopped
+	must.True(t, acquire(ids[2]).Granted)
+
+	// Slots which are never released expire
+	must.True(t, acquire(ids[3]).Granted)
+	must.False(t, acquire(ids[1]).Granted)
+	now = now.Add(policy.HealthyDeadline + templateRestartLeaseGrace)
+	must.True(t, acquire(ids[1]).Granted)
+
+	// Leadership changes forget the restarts in progress
+	restarts.reset()
+	must.MapEmpty(t, restarts.groups)
+}
@@ -40,6 +40,8 @@ type variableTimers interface {
 	CreateVariableLockTTLTimer(structs.VariableEncrypted)
 	RemoveVariableLockTTLTimer(structs.VariableEncrypted)
 	RenewTTLTimer(structs.VariableEncrypted) error
+	SetVariableExpiryTimer(structs.VariableMetadata)
+	RemoveVariableExpiryTimer(structs.VariableMetadata)
 }
 
 // Variables encapsulates the variables RPC endpoint which is
@@ -110,7 +112,7 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 
 	switch args.Op {
 	case structs.VarOpSet, structs.VarOpCAS, structs.VarOpLockAcquire,
-		structs.VarOpLockRelease:
+		structs.VarOpLockRelease, structs.VarOpRekey:
 		ev, err = sv.encrypt(args.Var)
 		if err != nil {
 			return fmt.Errorf("variable error: encrypt: %w", err)
@@ -119,6 +121,13 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 		ev.CreateTime = now // existing will override if it exists
 		ev.ModifyTime = now
 
+		// The expiry is always computed by the server, so that each write
+		// restarts the TTL.
+		ev.ExpireTime = 0
+		if ev.TTL > 0 {
+			ev.ExpireTime = now + int64(ev.TTL)
+		}
+
 	case structs.VarOpDelete, structs.VarOpDeleteCAS:
 		ev = &structs.VariableEncrypted{
 			VariableMetadata: structs.VariableMetadata{
@@ -160,6 +169,13 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 		case structs.VarOpLockRelease:
 			sv.timers.RemoveVariableLockTTLTimer(ev.Copy())
 		}
+
+		switch args.Op {
+		case structs.VarOpSet, structs.VarOpCAS, structs.VarOpLockAcquire:
+			sv.timers.SetVariableExpiryTimer(ev.VariableMetadata)
+		case structs.VarOpDelete, structs.VarOpDeleteCAS:
+			sv.timers.RemoveVariableExpiryTimer(ev.VariableMetadata)
+		}
 	}
 
 	return nil
@@ -188,6 +204,11 @@ func hasOperationPermissions(aclObj *acl.ACL, namespace, path string, op structs
 		if !hasPerm(acl.VariablesCapabilityDestroy) {
 			return structs.ErrPermissionDenied
 		}
+
+	case structs.VarOpRekey:
+		if !aclObj.IsManagement() {
+			return structs.ErrPermissionDenied
+		}
 	default:
 		return fmt.Errorf("svPreApply: unexpected VarOp received: %q", op)
 	}
@@ -245,6 +266,12 @@ func canonicalizeAndValidate(args *structs.VariablesApplyRequest) error {
 		}
 
 		return structs.ValidatePath(args.Var.Path)
+
+	case structs.VarOpRekey:
+		// Rekeying re-encrypts the variable as it is, so the contents are
+		// not validated again.
+		args.Var.Canonicalize()
+		return structs.ValidatePath(args.Var.Path)
 	}
 
 	return nil
@@ -574,6 +601,179 @@ func (sv *Variables) listAllVariables(
 	})
 }
 
+// ListVersions is used to list the metadata of the current and previous
+// versions of a variable, with the highest version first.
+func (sv *Variables) ListVersions(args *structs.VariablesListVersionsRequest,
+	reply *structs.VariablesListVersionsResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesListVersionsRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list_versions"}, time.Now())
+
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path, acl.PolicyList,
+		auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())) {
+		return structs.ErrPermissionDenied
+	}
+
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			current, err := s.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+			versions, err := s.GetVariableVersions(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Versions = make([]*structs.VariableMetadata, 0, len(versions)+1)
+			var maxIndex uint64
+			if current != nil {
+				meta := current.VariableMetadata.Copy()
+				if !aclObj.IsManagement() {
+					meta.Lock = nil
+				}
+				reply.Versions = append(reply.Versions, meta)
+				maxIndex = current.ModifyIndex
+			}
+			for _, version := range versions {
+				reply.Versions = append(reply.Versions, version.VariableMetadata.Copy())
+				maxIndex = max(maxIndex, version.ArchiveIndex)
+			}
+
+			if maxIndex == 0 {
+				return sv.srv.setReplyQueryMeta(s, state.TableVariableVersions, &reply.QueryMeta)
+			}
+			reply.Index = maxIndex
+			return nil
+		},
+	})
+}
+
+// ReadVersion is used to get a specific version of a variable, which may be
+// the current version or a previous one.
+func (sv *Variables) ReadVersion(args *structs.VariablesReadVersionRequest,
+	reply *structs.VariablesReadResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesReadVersionRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read_version"}, time.Now())
+
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path, acl.PolicyRead,
+		auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())) {
+		return structs.ErrPermissionDenied
+	}
+
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			reply.Data = nil
+
+			current, err := s.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			var out *structs.VariableEncrypted
+			var index uint64
+			if current != nil && current.Version == args.Version {
+				out = current
+				index = current.ModifyIndex
+			} else {
+				version, err := s.GetVariableVersion(ws, args.RequestNamespace(), args.Path, args.Version)
+				if err != nil {
+					return err
+				}
+				if version != nil {
+					out = &version.VariableEncrypted
+					index = version.ArchiveIndex
+				}
+			}
+
+			if out == nil {
+				return sv.srv.setReplyQueryMeta(s, state.TableVariableVersions, &reply.QueryMeta)
+			}
+
+			dv, err := sv.decrypt(out)
+			if err != nil {
+				return err
+			}
+			ov := dv.Copy()
+			if !aclObj.IsManagement() {
+				ov.Lock = nil
+			}
+			reply.Data = &ov
+			reply.Index = index
+			return nil
+		},
+	})
+}
+
+// DeleteVersions is used by the core scheduler to garbage collect previous
+// versions of variables.
+func (sv *Variables) DeleteVersions(args *structs.VariablesDeleteVersionsRequest,
+	reply *structs.GenericResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesDeleteVersionsRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "delete_versions"}, time.Now())
+
+	if !ServersMeetMinimumVersion(
+		sv.srv.serf.Members(), sv.srv.Region(), minVariableVersionsVersion, false) {
+		return fmt.Errorf("all servers must be running version %v or later to delete variable versions",
+			minVariableVersionsVersion)
+	}
+
+	if aclObj, err := sv.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Versions) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify at least one variable version")
+	}
+
+	_, index, err := sv.srv.raftApply(structs.VariableVersionsDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
 func (sv *Variables) encrypt(v *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
 	b, err := json.Marshal(v.Items)
 	if err != nil {
@@ -1658,3 +1658,125 @@ func TestVariablesEndpoint_RenewLock(t *testing.T) {
 		must.NoError(t, err)
 	})
 }
+
+func TestVariablesEndpoint_Versions(t *testing.T) {
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, nil)
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+
+	sv := mock.Variable()
+	for i := 0; i < 3; i++ {
+		sv.Items = structs.VariableItems{"value": fmt.Sprintf("v%d", i+1)}
+		applyReq := structs.VariablesApplyRequest{
+			Op:  structs.VarOpSet,
+			Var: sv,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		applyResp := new(structs.VariablesApplyResponse)
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesApplyRPCMethod, &applyReq, applyResp))
+		must.Eq(t, uint64(i+1), applyResp.Output.Version)
+	}
+
+	t.Run("list versions", func(t *testing.T) {
+		req := &structs.VariablesListVersionsRequest{
+			Path: sv.Path,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: sv.Namespace,
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		var resp structs.VariablesListVersionsResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesListVersionsRPCMethod, req, &resp))
+		must.Len(t, 3, resp.Versions)
+		for i, version := range resp.Versions {
+			must.Eq(t, uint64(3-i), version.Version)
+		}
+		must.Eq(t, resp.Versions[0].ModifyIndex, resp.Index)
+
+		req.Path = "does/not/exist"
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesListVersionsRPCMethod, req, &resp))
+		must.Len(t, 0, resp.Versions)
+	})
+
+	t.Run("read version", func(t *testing.T) {
+		req := &structs.VariablesReadVersionRequest{
+			Path:    sv.Path,
+			Version: 1,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: sv.Namespace,
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		var resp structs.VariablesReadResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesReadVersionRPCMethod, req, &resp))
+		must.NotNil(t, resp.Data)
+		must.Eq(t, "v1", resp.Data.Items["value"])
+		must.Eq(t, 1, resp.Data.Version)
+
+		req.Version = 3
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesReadVersionRPCMethod, req, &resp))
+		must.NotNil(t, resp.Data)
+		must.Eq(t, "v3", resp.Data.Items["value"])
+
+		req.Version = 10
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesReadVersionRPCMethod, req, &resp))
+		must.Nil(t, resp.Data)
+	})
+
+	t.Run("read version without permission", func(t *testing.T) {
+		policy := mock.NamespacePolicyWithVariables(sv.Namespace, "", []string{},
+			map[string][]string{"other/*": {"read"}})
+		token := mock.CreatePolicyAndToken(t, srv.fsm.State(), 1010, "other", policy)
+
+		req := &structs.VariablesReadVersionRequest{
+			Path:    sv.Path,
+			Version: 1,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: sv.Namespace,
+				AuthToken: token.SecretID,
+			},
+		}
+		var resp structs.VariablesReadResponse
+		err := msgpackrpc.CallWithCodec(
+			codec, structs.VariablesReadVersionRPCMethod, req, &resp)
+		must.EqError(t, err, structs.ErrPermissionDenied.Error())
+	})
+
+	t.Run("delete versions", func(t *testing.T) {
+		req := &structs.VariablesDeleteVersionsRequest{
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		err := msgpackrpc.CallWithCodec(
+			codec, structs.VariablesDeleteVersionsRPCMethod, req, &resp)
+		must.ErrorContains(t, err, "must specify at least one variable version")
+
+		req.Versions = []*structs.VariableMetadata{
+			{Namespace: sv.Namespace, Path: sv.Path, Version: 1},
+		}
+		must.NoError(t, msgpackrpc.CallWithCodec(
+			codec, structs.VariablesDeleteVersionsRPCMethod, req, &resp))
+
+		versions, err := srv.fsm.State().GetVariableVersions(nil, sv.Namespace, sv.Path)
+		must.NoError(t, err)
+		must.Len(t, 1, versions)
+		must.Eq(t, 2, versions[0].Version)
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// restoreVariableExpiryTimers iterates the stored variables and creates an
+// expiry timer for each variable with a TTL. This is used during leadership
+// establishment to populate the in-memory timer. Variables that expired while
+// there was no leader are deleted as soon as their timer is created.
+func (s *Server) restoreVariableExpiryTimers() error {
+	varIterator, err := s.fsm.State().Variables(nil)
+	if err != nil {
+		return fmt.Errorf("failed to list variables for expiry restore: %v", err)
+	}
+
+	for raw := varIterator.Next(); raw != nil; raw = varIterator.Next() {
+		variable := raw.(*structs.VariableEncrypted)
+		if variable.ExpireTime != 0 {
+			s.SetVariableExpiryTimer(variable.VariableMetadata)
+		}
+	}
+
+	return nil
+}
+
+// variableExpiryID returns the ID used to track the expiry timer of a
+// variable. Namespaces can't contain a slash, so the ID is unique.
+func variableExpiryID(namespace, path string) string {
+	return namespace + "/" + path
+}
+
+// SetVariableExpiryTimer creates or resets the expiry timer of a variable
+// that was just written. If the variable no longer has a TTL, any existing
+// timer is removed.
+func (s *Server) SetVariableExpiryTimer(variable structs.VariableMetadata) {
+	if variable.ExpireTime == 0 {
+		s.RemoveVariableExpiryTimer(variable)
+		return
+	}
+
+	namespace, path := variable.Namespace, variable.Path
+	ttl := time.Until(time.Unix(0, variable.ExpireTime))
+
+	// The create function resets the timer when it exists already, and the
+	// function it was created with looks up the latest expiry from state.
+	s.variableExpiryTimer.Create(variableExpiryID(namespace, path), ttl, func() {
+		s.expireVariable(namespace, path)
+	})
+}
+
+// RemoveVariableExpiryTimer stops and removes the expiry timer of a variable
+// that was deleted or no longer has a TTL.
+func (s *Server) RemoveVariableExpiryTimer(variable structs.VariableMetadata) {
+	s.variableExpiryTimer.StopAndRemove(variableExpiryID(variable.Namespace, variable.Path))
+}
+
+// expireVariable deletes the variable at the given namespace and path if it
+// has expired. The delete is conditional on the variable's modify index, so a
+// variable written after its timer fired isn't deleted. The deletion is
+// emitted on the event stream like any other variable deletion.
+func (s *Server) expireVariable(namespace, path string) {
+	id := variableExpiryID(namespace, path)
+	s.variableExpiryTimer.StopAndRemove(id)
+
+	variable, err := s.fsm.State().GetVariable(nil, namespace, path)
+	if err != nil {
+		s.logger.Error("failed to look up expired variable",
+			"namespace", namespace, "path", path, "error", err)
+		return
+	}
+	if variable == nil || variable.ExpireTime == 0 {
+		return
+	}
+
+	// The timer may fire early if the variable was rewritten with a longer TTL
+	// just before it fired.
+	if !variable.IsExpired(time.Now()) {
+		s.SetVariableExpiryTimer(variable.VariableMetadata)
+		return
+	}
+
+	s.logger.Debug("variable expired, removing it",
+		"namespace", namespace, "path", path, "ttl", variable.TTL)
+
+	// Passing the lock allows the delete of a locked variable, which also
+	// releases the lock.
+	args := structs.VarApplyStateRequest{
+		Op: structs.VarOpDeleteCAS,
+		Var: &structs.VariableEncrypted{
+			VariableMetadata: variable.VariableMetadata,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			Namespace: namespace,
+		},
+	}
+
+	// Retry with exponential backoff to remove the variable
+	for attempt := 0; attempt < maxAttemptsToRaftApply; attempt++ {
+		out, _, err := s.raftApply(structs.VarApplyStateRequestType, args)
+		if err == nil {
+			resp, _ := out.(*structs.VarApplyStateResponse)
+			if !resp.IsError() {
+				// A conflict means the variable was written since it was
+				// looked up, and that write set its own expiry.
+				if resp.IsOk() && variable.IsLock() {
+					s.lockTTLTimer.StopAndRemove(variable.LockID())
+				}
+				return
+			}
+			err = resp.Error
+		}
+
+		s.logger.Error("variable expiration failed",
+			"namespace", namespace, "path", path, "error", err)
+		time.Sleep((1 << attempt) * 10 * time.Second)
+	}
+}
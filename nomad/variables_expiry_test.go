@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc/v2"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func TestServer_restoreVariableExpiryTimers(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, testServerCleanup := TestServer(t, nil)
+	defer testServerCleanup()
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Generate three variables: one without a TTL, one that expires in the
+	// future and one that has already expired.
+	mockVar1 := mock.VariableEncrypted()
+
+	mockVar2 := mock.VariableEncrypted()
+	mockVar2.TTL = time.Hour
+	mockVar2.ExpireTime = time.Now().Add(time.Hour).UnixNano()
+
+	mockVar3 := mock.VariableEncrypted()
+	mockVar3.TTL = time.Hour
+	mockVar3.ExpireTime = time.Now().Add(-time.Minute).UnixNano()
+
+	for i, sv := range []*structs.VariableEncrypted{mockVar1, mockVar2, mockVar3} {
+		upsertResp := testServer.fsm.State().VarSet(uint64(10+i),
+			&structs.VarApplyStateRequest{Var: sv, Op: structs.VarOpSet})
+		must.NoError(t, upsertResp.Error)
+	}
+
+	// Call the server function that restores the expiry timers. This would
+	// usually happen on leadership transition.
+	must.NoError(t, testServer.restoreVariableExpiryTimers())
+
+	must.Nil(t, testServer.variableExpiryTimer.Get(
+		variableExpiryID(mockVar1.Namespace, mockVar1.Path)))
+	must.NotNil(t, testServer.variableExpiryTimer.Get(
+		variableExpiryID(mockVar2.Namespace, mockVar2.Path)))
+
+	// The expired variable is removed from state
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			out, err := testServer.fsm.State().GetVariable(nil, mockVar3.Namespace, mockVar3.Path)
+			must.NoError(t, err)
+			return out == nil
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+
+	out, err := testServer.fsm.State().GetVariable(nil, mockVar2.Namespace, mockVar2.Path)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+}
+
+func TestServer_expireVariable(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, testServerCleanup := TestServer(t, nil)
+	defer testServerCleanup()
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// A variable that hasn't expired yet isn't removed, and its timer is
+	// reset instead.
+	mockVar := mock.VariableEncrypted()
+	mockVar.TTL = time.Hour
+	mockVar.ExpireTime = time.Now().Add(time.Hour).UnixNano()
+	upsertResp := testServer.fsm.State().VarSet(10,
+		&structs.VarApplyStateRequest{Var: mockVar, Op: structs.VarOpSet})
+	must.NoError(t, upsertResp.Error)
+
+	testServer.expireVariable(mockVar.Namespace, mockVar.Path)
+	must.NotNil(t, testServer.variableExpiryTimer.Get(
+		variableExpiryID(mockVar.Namespace, mockVar.Path)))
+
+	out, err := testServer.fsm.State().GetVariable(nil, mockVar.Namespace, mockVar.Path)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	// Once expired, the variable is removed along with its timer
+	expired := out.Copy()
+	expired.ExpireTime = time.Now().Add(-time.Second).UnixNano()
+	upsertResp = testServer.fsm.State().VarSet(20,
+		&structs.VarApplyStateRequest{Var: &expired, Op: structs.VarOpSet})
+	must.NoError(t, upsertResp.Error)
+
+	testServer.expireVariable(mockVar.Namespace, mockVar.Path)
+	must.Nil(t, testServer.variableExpiryTimer.Get(
+		variableExpiryID(mockVar.Namespace, mockVar.Path)))
+
+	out, err = testServer.fsm.State().GetVariable(nil, mockVar.Namespace, mockVar.Path)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
+
+func TestVariablesEndpoint_Apply_TTL(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+
+	sv := mock.Variable()
+	sv.TTL = time.Hour
+	timerID := variableExpiryID(sv.Namespace, sv.Path)
+
+	// Writing a variable with a TTL sets its expiry and creates a timer
+	applyReq := structs.VariablesApplyRequest{
+		Op:           structs.VarOpSet,
+		Var:          sv,
+		WriteRequest: structs.WriteRequest{Region: srv.Region()},
+	}
+	applyResp := new(structs.VariablesApplyResponse)
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, applyResp))
+	must.Eq(t, time.Hour, applyResp.Output.TTL)
+	must.Greater(t, time.Now().UnixNano(), applyResp.Output.ExpireTime)
+	must.NotNil(t, srv.variableExpiryTimer.Get(timerID))
+
+	// Writing it again without a TTL clears the expiry and removes the timer
+	update := applyResp.Output.Copy()
+	sv = &update
+	sv.TTL = 0
+	applyReq.Var = sv
+	applyResp = new(structs.VariablesApplyResponse)
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, applyResp))
+	must.Zero(t, applyResp.Output.ExpireTime)
+	must.Nil(t, srv.variableExpiryTimer.Get(timerID))
+
+	// A TTL shorter than the minimum is rejected
+	update = applyResp.Output.Copy()
+	sv = &update
+	sv.TTL = time.Second
+	applyReq.Var = sv
+	applyResp = new(structs.VariablesApplyResponse)
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, applyResp)
+	must.ErrorContains(t, err, "variable TTL must be zero or at least")
+}
@@ -631,6 +631,22 @@ type InternalCapabilitiesDriver interface {
 	InternalCapabilities() InternalCapabilities
 }
 
+// ImagePrefetchDriver is implemented by drivers which can pull the images of
+// tasks before the tasks are placed on the client, so that they start
+// faster.
+//
+// Intended for internal drivers only, as it is not part of the plugin
+// protocol.
+type ImagePrefetchDriver interface {
+	// PrefetchImage pulls the image, authenticating with the registry
+	// authentication of the tasks using it if set.
+	PrefetchImage(image string, auth *structs.PrefetchImageAuth) error
+
+	// ImagePresent returns whether the image is present on the client, as
+	// prefetched images may be removed once no task uses them.
+	ImagePresent(image string) (bool, error)
+}
+
 // InternalCapabilities flags disabled functionality.
 // Zero value means all is supported.
 type InternalCapabilities struct {
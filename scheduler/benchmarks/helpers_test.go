@@ -49,7 +49,7 @@ func NewHarnessFromDataDir(t testing.TB, datadirPath string) (*scheduler.Harness
 	if datadirPath == "" {
 		return nil, errors.New("datadir path was not set")
 	}
-	fsm, err := raftutil.NewFSM(datadirPath)
+	fsm, err := raftutil.NewFSM(datadirPath, nil)
 	if err != nil {
 		return nil, err
 	}
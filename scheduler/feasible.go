@@ -371,39 +371,65 @@ func (c *CSIVolumeChecker) isFeasible(n *structs.Node) (bool, string) {
 // NetworkChecker is a FeasibilityChecker which returns whether a node has the
 // network resources necessary to schedule the task group
 type NetworkChecker struct {
-	ctx         Context
-	networkMode string
-	ports       []structs.Port
+	ctx          Context
+	networkModes []string
+	ports        []structs.Port
 }
 
 func NewNetworkChecker(ctx Context) *NetworkChecker {
-	return &NetworkChecker{ctx: ctx, networkMode: "host"}
+	return &NetworkChecker{ctx: ctx, networkModes: []string{"host"}}
 }
 
 func (c *NetworkChecker) SetNetwork(network *structs.NetworkResource) {
-	c.networkMode = network.Mode
-	if c.networkMode == "" {
-		c.networkMode = "host"
-	}
+	c.SetNetworks(structs.Networks{network})
+}
+
+// SetNetworks sets the networks of the task group. Every network mode must be
+// available on the node for the group to be feasible.
+func (c *NetworkChecker) SetNetworks(networks structs.Networks) {
+	c.networkModes = make([]string, 0, len(networks))
+	c.ports = nil
+	for _, network := range networks {
+		mode := network.Mode
+		if mode == "" {
+			mode = "host"
+		}
+		c.networkModes = append(c.networkModes, mode)
 
-	c.ports = make([]structs.Port, len(network.DynamicPorts)+len(network.ReservedPorts))
-	c.ports = append(c.ports, network.DynamicPorts...)
-	c.ports = append(c.ports, network.ReservedPorts...)
+		c.ports = append(c.ports, network.DynamicPorts...)
+		c.ports = append(c.ports, network.ReservedPorts...)
+	}
 }
 
 func (c *NetworkChecker) Feasible(option *structs.Node) bool {
+	for _, mode := range c.networkModes {
+		if !c.feasibleMode(option, mode) {
+			return false
+		}
+	}
+	return true
+}
+
+// feasibleMode returns whether the node supports the network mode.
+func (c *NetworkChecker) feasibleMode(option *structs.Node, networkMode string) bool {
 	// Allow jobs not requiring any network resources
-	if c.networkMode == "none" {
+	if networkMode == "none" {
 		return true
 	}
 
-	if !c.hasNetwork(option) {
+	// Host networks attached to the allocation only need the node to have
+	// the host network, the CNI plugins are checked by implicit constraints
+	if name, ok := strings.CutPrefix(networkMode, "host/"); ok {
+		return c.hasAttachedHostNetwork(option, name)
+	}
+
+	if !c.hasNetwork(option, networkMode) {
 
 		// special case - if the client is running a version older than 0.12 but
 		// the server is 0.12 or newer, we need to maintain an upgrade path for
 		// jobs looking for a bridge network that will not have been fingerprinted
 		// on the client (which was added in 0.12)
-		if c.networkMode == "bridge" {
+		if networkMode == "bridge" {
 			sv, err := version.NewSemver(option.Attributes["nomad.version"])
 			if err == nil && predatesBridgeFingerprint.Check(sv) {
 				return true
@@ -447,7 +473,21 @@ func (c *NetworkChecker) hasHostNetworks(option *structs.Node) bool {
 	return true
 }
 
-func (c *NetworkChecker) hasNetwork(option *structs.Node) bool {
+// hasAttachedHostNetwork returns whether the node has the host network with
+// an interface an allocation network can be attached to.
+func (c *NetworkChecker) hasAttachedHostNetwork(option *structs.Node, name string) bool {
+	if option.NodeResources != nil {
+		for _, net := range option.NodeResources.NodeNetworks {
+			if net.Device != "" && net.HasAlias(name) {
+				return true
+			}
+		}
+	}
+	c.ctx.Metrics().FilterNode(option, fmt.Sprintf("missing host network %q", name))
+	return false
+}
+
+func (c *NetworkChecker) hasNetwork(option *structs.Node, networkMode string) bool {
 	if option.NodeResources == nil {
 		return false
 	}
@@ -457,7 +497,7 @@ func (c *NetworkChecker) hasNetwork(option *structs.Node) bool {
 		if mode == "" {
 			mode = "host"
 		}
-		if mode == c.networkMode {
+		if mode == networkMode {
 			return true
 		}
 	}
@@ -665,6 +665,75 @@ func TestNetworkChecker(t *testing.T) {
 	}
 }
 
+func TestNetworkChecker_multipleNetworks(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+
+	node := func(modes ...string) *structs.Node {
+		n := mock.Node()
+		for _, mode := range modes {
+			n.NodeResources.Networks = append(n.NodeResources.Networks, &structs.NetworkResource{Mode: mode})
+		}
+		n.Attributes["nomad.version"] = "1.8.0" // mock version is 0.5.0
+		return n
+	}
+
+	nodes := []*structs.Node{
+		node("bridge", "cni/storage", "cni/telemetry"),
+		node("bridge", "cni/storage"),
+		node("cni/storage", "cni/telemetry"),
+	}
+
+	checker := NewNetworkChecker(ctx)
+	checker.SetNetworks(structs.Networks{
+		{Mode: "bridge"},
+		{Mode: "cni/storage"},
+		{Mode: "cni/telemetry"},
+	})
+
+	results := []bool{true, false, false}
+	for i, node := range nodes {
+		must.Eq(t, results[i], checker.Feasible(node), must.Sprintf("idx=%d", i))
+	}
+}
+
+func TestNetworkChecker_attachedHostNetwork(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+
+	node := func(device string, aliases ...string) *structs.Node {
+		n := mock.Node()
+		n.NodeResources.Networks = append(n.NodeResources.Networks, &structs.NetworkResource{Mode: "bridge"})
+		nodeNet := &structs.NodeNetworkResource{Device: device}
+		for _, alias := range aliases {
+			nodeNet.Addresses = append(nodeNet.Addresses, structs.NodeNetworkAddress{Alias: alias})
+		}
+		n.NodeResources.NodeNetworks = []*structs.NodeNetworkResource{nodeNet}
+		n.Attributes["nomad.version"] = "1.8.0" // mock version is 0.5.0
+		return n
+	}
+
+	nodes := []*structs.Node{
+		node("eth1", "storage"),
+		node("eth1", "public", "storage"),
+		node("eth1", "public"),
+		node("", "storage"),
+	}
+
+	checker := NewNetworkChecker(ctx)
+	checker.SetNetworks(structs.Networks{
+		{Mode: "bridge"},
+		{Mode: "host/storage"},
+	})
+
+	results := []bool{true, true, false, false}
+	for i, node := range nodes {
+		must.Eq(t, results[i], checker.Feasible(node), must.Sprintf("idx=%d", i))
+	}
+}
+
 func TestNetworkChecker_bridge_upgrade_path(t *testing.T) {
 	ci.Parallel(t)
 
@@ -456,6 +456,87 @@ OUTER:
 	return filteredBestAllocs
 }
 
+// PreemptForBandwidth tries to find allocations to preempt so that the group
+// network bandwidth asked for fits on the link of the node. It is called once
+// the ask was added to the network index and overcommitted the link.
+func (p *Preemptor) PreemptForBandwidth(ask *structs.NetworkBandwidth, netIdx *structs.NetworkIndex) []*structs.Allocation {
+	if ask.IsZero() || netIdx.GroupBandwidth <= 0 {
+		return nil
+	}
+
+	// The ask can't fit even if every allocation is preempted
+	if ask.IngressMBits > netIdx.GroupBandwidth || ask.EgressMBits > netIdx.GroupBandwidth {
+		return nil
+	}
+
+	ingressNeeded := netIdx.UsedIngressBandwidth - netIdx.GroupBandwidth
+	egressNeeded := netIdx.UsedEgressBandwidth - netIdx.GroupBandwidth
+	met := func(ingress, egress int) bool {
+		return ingress >= ingressNeeded && egress >= egressNeeded
+	}
+
+	var allocsToPreempt []*structs.Allocation
+	ingress, egress := 0, 0
+
+OUTER:
+	for _, allocGrp := range filterAndGroupPreemptibleAllocs(p.jobPriority, p.currentAllocs) {
+		allocs := allocGrp.allocs
+
+		// Preempt the allocations using the most bandwidth first
+		sort.Slice(allocs, func(i, j int) bool {
+			bi, bj := allocGroupBandwidth(allocs[i]), allocGroupBandwidth(allocs[j])
+			return bi.IngressMBits+bi.EgressMBits > bj.IngressMBits+bj.EgressMBits
+		})
+
+		for _, alloc := range allocs {
+			b := allocGroupBandwidth(alloc)
+			if b.IsZero() {
+				continue
+			}
+			ingress += b.IngressMBits
+			egress += b.EgressMBits
+			allocsToPreempt = append(allocsToPreempt, alloc)
+			if met(ingress, egress) {
+				break OUTER
+			}
+		}
+	}
+
+	if !met(ingress, egress) {
+		return nil
+	}
+
+	// Do a final pass to eliminate the allocations whose bandwidth is already
+	// covered by the other preempted allocations
+	filtered := allocsToPreempt[:0]
+	for _, alloc := range allocsToPreempt {
+		b := allocGroupBandwidth(alloc)
+		if met(ingress-b.IngressMBits, egress-b.EgressMBits) {
+			ingress -= b.IngressMBits
+			egress -= b.EgressMBits
+			continue
+		}
+		filtered = append(filtered, alloc)
+	}
+	return filtered
+}
+
+// allocGroupBandwidth returns the group network bandwidth used by the
+// allocation.
+func allocGroupBandwidth(alloc *structs.Allocation) *structs.NetworkBandwidth {
+	total := &structs.NetworkBandwidth{}
+	if alloc.AllocatedResources == nil {
+		return total
+	}
+	for _, network := range alloc.AllocatedResources.Shared.Networks {
+		if network.Bandwidth != nil {
+			total.IngressMBits += network.Bandwidth.IngressMBits
+			total.EgressMBits += network.Bandwidth.EgressMBits
+		}
+	}
+	return total
+}
+
 // deviceGroupAllocs represents a group of allocs that share a device
 type deviceGroupAllocs struct {
 	allocs []*structs.Allocation
@@ -6,6 +6,7 @@ package scheduler
 import (
 	"fmt"
 	"math"
+	"slices"
 
 	"github.com/hashicorp/nomad/client/lib/idset"
 	"github.com/hashicorp/nomad/client/lib/numalib/hw"
@@ -284,6 +285,14 @@ OUTER:
 		// Check if we need task group network resource
 		if len(iter.taskGroup.Networks) > 0 {
 			ask := iter.taskGroup.Networks[0].Copy()
+
+			// Ports of all the networks of the group are assigned together
+			// since they share the same host port space
+			for _, network := range iter.taskGroup.Networks[1:] {
+				ask.DynamicPorts = append(ask.DynamicPorts, network.DynamicPorts...)
+				ask.ReservedPorts = append(ask.ReservedPorts, network.ReservedPorts...)
+			}
+
 			for i, port := range ask.DynamicPorts {
 				if port.HostNetwork != "" {
 					if hostNetworkValue, hostNetworkOk := resolveTarget(port.HostNetwork, option.Node); hostNetworkOk {
@@ -351,6 +360,44 @@ OUTER:
 			// Reserve this to prevent another task from colliding
 			netIdx.AddReservedPorts(offer)
 
+			// Check the bandwidth of the group fits on the node's link
+			if netIdx.AddReservedBandwidth(ask.Bandwidth) {
+				// If eviction is not enabled, mark this node as exhausted and continue
+				if !iter.evict {
+					iter.ctx.Metrics().ExhaustedNode(option.Node, "network: bandwidth exceeded")
+					netIdx.Release()
+					continue OUTER
+				}
+
+				// Look for preemptible allocations to free the bandwidth
+				preemptor.SetCandidates(proposed)
+
+				bandwidthPreemptions := preemptor.PreemptForBandwidth(ask.Bandwidth, netIdx)
+				if bandwidthPreemptions == nil {
+					iter.ctx.Logger().Named("binpack").Debug("preemption not possible", "network_bandwidth", ask.Bandwidth)
+					iter.ctx.Metrics().ExhaustedNode(option.Node, "network: bandwidth exceeded")
+					netIdx.Release()
+					continue OUTER
+				}
+				allocsToPreempt = append(allocsToPreempt, bandwidthPreemptions...)
+
+				// First subtract out preempted allocations
+				proposed = structs.RemoveAllocs(proposed, bandwidthPreemptions)
+
+				// Reset the network index and reserve the offer and bandwidth again
+				netIdx.Release()
+				netIdx = structs.NewNetworkIndex()
+				netIdx.SetNode(option.Node)
+				netIdx.AddAllocs(proposed)
+				netIdx.AddReservedPorts(offer)
+				if netIdx.AddReservedBandwidth(ask.Bandwidth) {
+					iter.ctx.Logger().Named("binpack").Debug("unexpected error, bandwidth exceeded after considering preemption")
+					iter.ctx.Metrics().ExhaustedNode(option.Node, "network: bandwidth exceeded")
+					netIdx.Release()
+					continue OUTER
+				}
+			}
+
 			// Update the network ask to the offer
 			nwRes := structs.AllocatedPortsToNetworkResouce(ask, offer, option.Node.NodeResources)
 			total.Shared.Networks = []*structs.NetworkResource{nwRes}
@@ -794,6 +841,56 @@ func matchesAffinity(ctx Context, affinity *structs.Affinity, option *structs.No
 	return checkAffinity(ctx, affinity.Operand, lVal, rVal, lOk, rOk)
 }
 
+// ImageLocalityIterator is used to prefer nodes which already hold the
+// container images of the task group, which were pulled by prefetching the
+// job, so that its tasks start faster.
+type ImageLocalityIterator struct {
+	ctx    Context
+	source RankIterator
+	images []string
+}
+
+// NewImageLocalityIterator is used to create an ImageLocalityIterator that
+// scores nodes by the fraction of the task group's images they hold.
+func NewImageLocalityIterator(ctx Context, source RankIterator) *ImageLocalityIterator {
+	return &ImageLocalityIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *ImageLocalityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.images = tg.PrefetchImages()
+}
+
+func (iter *ImageLocalityIterator) Reset() {
+	iter.source.Reset()
+}
+
+func (iter *ImageLocalityIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil {
+		return nil
+	}
+	if len(iter.images) == 0 {
+		return option
+	}
+
+	// Nodes without any of the images are scored too, otherwise their
+	// remaining scores would be averaged without the image locality score
+	// and could rank them above nodes holding the images.
+	present := 0
+	for _, image := range iter.images {
+		if slices.Contains(option.Node.PrefetchedImages, image) {
+			present++
+		}
+	}
+	score := float64(present) / float64(len(iter.images))
+	option.Scores = append(option.Scores, score)
+	iter.ctx.Metrics().ScoreNode(option.Node, "image-locality", score)
+	return option
+}
+
 // ScoreNormalizationIterator is used to combine scores from various prior
 // iterators and combine them into one final score. The current implementation
 // averages the scores together.
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -595,6 +596,208 @@ func TestBinPackIterator_Network_NoCollision_Node(t *testing.T) {
 	require.Len(t, out, 1)
 }
 
+// TestBinPackIterator_Network_MultipleNetworks asserts that the ports of all
+// the networks of a group are assigned.
+func TestBinPackIterator_Network_MultipleNetworks(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{{Node: mock.Node()}}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+		Networks: []*structs.NetworkResource{
+			{
+				Mode:         "bridge",
+				DynamicPorts: []structs.Port{{Label: "http", To: 8080, HostNetwork: "default"}},
+			},
+			{
+				Mode:          "cni/storage",
+				ReservedPorts: []structs.Port{{Label: "nfs", Value: 2049, HostNetwork: "default"}},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+	binp.SetSchedulerConfiguration(testSchedulerConfig)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, binp)
+	out := collectRanked(scoreNorm)
+	require.Len(t, out, 1)
+
+	ports := out[0].AllocResources.Ports
+	http, ok := ports.Get("http")
+	require.True(t, ok)
+	require.Equal(t, 8080, http.To)
+	nfs, ok := ports.Get("nfs")
+	require.True(t, ok)
+	require.Equal(t, 2049, nfs.Value)
+}
+
+func TestBinPackIterator_Network_Bandwidth(t *testing.T) {
+	state, ctx := testContext(t)
+	nodes := []*RankedNode{{Node: mock.Node()}, {Node: mock.Node()}}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	// Consume most of the ingress bandwidth of the first node
+	j1 := mock.Job()
+	alloc1 := &structs.Allocation{
+		Namespace: structs.DefaultNamespace,
+		ID:        uuid.Generate(),
+		EvalID:    uuid.Generate(),
+		NodeID:    nodes[0].Node.ID,
+		JobID:     j1.ID,
+		Job:       j1,
+		AllocatedResources: &structs.AllocatedResources{
+			Tasks: map[string]*structs.AllocatedTaskResources{
+				"web": {
+					Cpu: structs.AllocatedCpuResources{
+						CpuShares: 256,
+					},
+					Memory: structs.AllocatedMemoryResources{
+						MemoryMB: 256,
+					},
+				},
+			},
+			Shared: structs.AllocatedSharedResources{
+				Networks: []*structs.NetworkResource{{
+					Mode:      "bridge",
+					Bandwidth: &structs.NetworkBandwidth{IngressMBits: 800},
+				}},
+			},
+		},
+		DesiredStatus: structs.AllocDesiredStatusRun,
+		ClientStatus:  structs.AllocClientStatusPending,
+		TaskGroup:     "web",
+	}
+	require.NoError(t, state.UpsertJobSummary(998, mock.JobSummary(alloc1.JobID)))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc1}))
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+		Networks: []*structs.NetworkResource{
+			{
+				Mode:      "bridge",
+				Bandwidth: &structs.NetworkBandwidth{IngressMBits: 500},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+	binp.SetSchedulerConfiguration(testSchedulerConfig)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, binp)
+	out := collectRanked(scoreNorm)
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[1], out[0])
+	require.Equal(t, 500, out[0].AllocResources.Networks[0].Bandwidth.IngressMBits)
+	require.Equal(t, 1, ctx.metrics.DimensionExhausted["network: bandwidth exceeded"])
+}
+
+func TestBinPackIterator_Network_Bandwidth_Preemption(t *testing.T) {
+	state, ctx := testContext(t)
+	nodes := []*RankedNode{{Node: mock.Node()}}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	// Consume most of the ingress bandwidth of the node with low priority
+	// allocations
+	newAlloc := func(priority, ingress int) *structs.Allocation {
+		job := mock.Job()
+		job.Priority = priority
+		return &structs.Allocation{
+			Namespace: structs.DefaultNamespace,
+			ID:        uuid.Generate(),
+			EvalID:    uuid.Generate(),
+			NodeID:    nodes[0].Node.ID,
+			JobID:     job.ID,
+			Job:       job,
+			AllocatedResources: &structs.AllocatedResources{
+				Tasks: map[string]*structs.AllocatedTaskResources{
+					"web": {
+						Cpu:    structs.AllocatedCpuResources{CpuShares: 256},
+						Memory: structs.AllocatedMemoryResources{MemoryMB: 256},
+					},
+				},
+				Shared: structs.AllocatedSharedResources{
+					Networks: []*structs.NetworkResource{{
+						Mode:      "bridge",
+						Bandwidth: &structs.NetworkBandwidth{IngressMBits: ingress},
+					}},
+				},
+			},
+			DesiredStatus: structs.AllocDesiredStatusRun,
+			ClientStatus:  structs.AllocClientStatusRunning,
+			TaskGroup:     "web",
+		}
+	}
+	highPriority := newAlloc(95, 300)
+	small := newAlloc(20, 100)
+	large := newAlloc(20, 500)
+	allocs := []*structs.Allocation{highPriority, small, large}
+	for i, alloc := range allocs {
+		must.NoError(t, state.UpsertJobSummary(uint64(998+i), mock.JobSummary(alloc.JobID)))
+	}
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+		Networks: []*structs.NetworkResource{
+			{
+				Mode:      "bridge",
+				Bandwidth: &structs.NetworkBandwidth{IngressMBits: 400},
+			},
+		},
+	}
+
+	// Only the allocation freeing enough bandwidth is preempted
+	binp := NewBinPackIterator(ctx, static, true, 100)
+	binp.SetTaskGroup(taskGroup)
+	binp.SetSchedulerConfiguration(testSchedulerConfig)
+
+	out := collectRanked(NewScoreNormalizationIterator(ctx, binp))
+	must.Len(t, 1, out)
+	must.Len(t, 1, out[0].PreemptedAllocs)
+	must.Eq(t, large.ID, out[0].PreemptedAllocs[0].ID)
+
+	// The bandwidth can't be freed by preempting allocations of a close
+	// priority
+	taskGroup.Networks[0].Bandwidth.IngressMBits = 800
+	static.Reset()
+	binp = NewBinPackIterator(ctx, static, true, 100)
+	binp.SetTaskGroup(taskGroup)
+	binp.SetSchedulerConfiguration(testSchedulerConfig)
+
+	out = collectRanked(NewScoreNormalizationIterator(ctx, binp))
+	must.Len(t, 0, out)
+	must.Eq(t, 1, ctx.metrics.DimensionExhausted["network: bandwidth exceeded"])
+}
+
 // TestBinPackIterator_Network_NodeError asserts that NetworkIndex.SetNode can
 // return an error and cause a node to be infeasible.
 //
@@ -2330,3 +2533,49 @@ func TestNodeAffinityIterator(t *testing.T) {
 	}
 
 }
+
+func TestImageLocalityIterator(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+
+	nodes[0].Node.PrefetchedImages = []string{"redis:7", "envoy:1"}
+	nodes[1].Node.PrefetchedImages = []string{"envoy:1"}
+
+	static := NewStaticRankIterator(ctx, nodes)
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Tasks[0].Driver = "docker"
+	tg.Tasks[0].Config = map[string]interface{}{"image": "redis:7"}
+	sidecar := tg.Tasks[0].Copy()
+	sidecar.Name = "sidecar"
+	sidecar.Config = map[string]interface{}{"image": "nginx:1"}
+	tg.Tasks = append(tg.Tasks, sidecar)
+
+	imageLocality := NewImageLocalityIterator(ctx, static)
+	imageLocality.SetTaskGroup(tg)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, imageLocality)
+
+	out := collectRanked(scoreNorm)
+	expectedScores := map[string]float64{
+		// Node 0 holds one of the two images
+		nodes[0].Node.ID: 0.5,
+		// Node 1 holds only an image not used by the group
+		nodes[1].Node.ID: 0,
+		// Node 2 holds no images
+		nodes[2].Node.ID: 0,
+	}
+
+	// Every node is scored, including those without any of the images, so
+	// that the score is averaged with the scores of other iterators.
+	must.Len(t, 3, out)
+	for _, n := range out {
+		must.Len(t, 1, n.Scores)
+		must.Eq(t, expectedScores[n.Node.ID], n.FinalScore)
+	}
+}
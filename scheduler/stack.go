@@ -68,6 +68,7 @@ type GenericStack struct {
 	maxScore                   *MaxScoreIterator
 	nodeAffinity               *NodeAffinityIterator
 	spread                     *SpreadIterator
+	imageLocality              *ImageLocalityIterator
 	scoreNorm                  *ScoreNormalizationIterator
 }
 
@@ -157,7 +158,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	s.taskGroupHostVolumes.SetVolumes(options.AllocName, tg.Volumes)
 	s.taskGroupCSIVolumes.SetVolumes(options.AllocName, tg.Volumes)
 	if len(tg.Networks) > 0 {
-		s.taskGroupNetwork.SetNetwork(tg.Networks[0])
+		s.taskGroupNetwork.SetNetworks(tg.Networks)
 	}
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
@@ -172,6 +173,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	}
 	s.nodeAffinity.SetTaskGroup(tg)
 	s.spread.SetTaskGroup(tg)
+	s.imageLocality.SetTaskGroup(tg)
 
 	if s.nodeAffinity.hasAffinities() || s.spread.hasSpreads() {
 		// scoring spread across all nodes has quadratic behavior, so
@@ -348,7 +350,7 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ran
 	s.taskGroupHostVolumes.SetVolumes(options.AllocName, tg.Volumes)
 	s.taskGroupCSIVolumes.SetVolumes(options.AllocName, tg.Volumes)
 	if len(tg.Networks) > 0 {
-		s.taskGroupNetwork.SetNetwork(tg.Networks[0])
+		s.taskGroupNetwork.SetNetworks(tg.Networks)
 	}
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
@@ -451,8 +453,11 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Apply scores based on spread block
 	s.spread = NewSpreadIterator(ctx, s.nodeAffinity)
 
+	// Prefer nodes holding the prefetched images of the task group
+	s.imageLocality = NewImageLocalityIterator(ctx, s.spread)
+
 	// Add the preemption options scoring iterator
-	preemptionScorer := NewPreemptionScoringIterator(ctx, s.spread)
+	preemptionScorer := NewPreemptionScoringIterator(ctx, s.imageLocality)
 
 	// Normalizes scores by averaging them across various scorers
 	s.scoreNorm = NewScoreNormalizationIterator(ctx, preemptionScorer)
@@ -290,12 +290,18 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 		if !at.Vault.Equal(bt.Vault) {
 			return difference("task vault", at.Vault, bt.Vault)
 		}
+		if !at.Nomad.Equal(bt.Nomad) {
+			return difference("task nomad", at.Nomad, bt.Nomad)
+		}
 		if c := consulUpdated(at.Consul, bt.Consul); c.modified {
 			return c
 		}
 		if !slices.EqualFunc(at.Templates, bt.Templates, func(a, b *structs.Template) bool { return a.Equal(b) }) {
 			return difference("task templates", at.Templates, bt.Templates)
 		}
+		if !slices.EqualFunc(at.Secrets, bt.Secrets, func(a, b *structs.Secret) bool { return a.Equal(b) }) {
+			return difference("task secrets", at.Secrets, bt.Secrets)
+		}
 		if !at.CSIPluginConfig.Equal(bt.CSIPluginConfig) {
 			return difference("task csi config", at.CSIPluginConfig, bt.CSIPluginConfig)
 		}
@@ -542,6 +548,10 @@ func networkUpdated(netA, netB []*structs.NetworkResource) comparison {
 			return difference("network dns", an.DNS, bn.DNS)
 		}
 
+		if !an.Bandwidth.Equal(bn.Bandwidth) {
+			return difference("network bandwidth", an.Bandwidth, bn.Bandwidth)
+		}
+
 		aPorts, bPorts := networkPortMap(an), networkPortMap(bn)
 		if !aPorts.Equal(bPorts) {
 			return difference("network port map", aPorts, bPorts)